@@ -28,21 +28,30 @@ package cli
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"net"
+	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/openthread/ot-ns/visualize"
+	visualizeStatslog "github.com/openthread/ot-ns/visualize/statslog"
 
 	"github.com/openthread/ot-ns/web"
 
 	"github.com/openthread/ot-ns/progctx"
 
 	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/geo"
 
 	"github.com/openthread/ot-ns/simulation"
 	. "github.com/openthread/ot-ns/types"
@@ -99,6 +108,10 @@ type CmdRunner struct {
 	sim           *simulation.Simulation
 	ctx           *progctx.ProgCtx
 	contextNodeId NodeId
+	sessions      *SessionManager
+	jsonOutput    bool
+	client        *remoteClient
+	async         *asyncOutput // console output of finished jobs, or nil for remote clients
 }
 
 func (rt *CmdRunner) RunCommand(cmdline string, output io.Writer) error {
@@ -117,6 +130,22 @@ func (rt *CmdRunner) RunCommand(cmdline string, output io.Writer) error {
 }
 
 func (rt *CmdRunner) HandleCommand(cmdline string, output io.Writer) error {
+	if rt.async != nil {
+		// hold asynchronous output back until the command completes
+		rt.async.lock.Lock()
+		defer rt.async.lock.Unlock()
+	}
+	return rt.handleCommand(cmdline, output)
+}
+
+func (rt *CmdRunner) handleCommand(cmdline string, output io.Writer) error {
+	if rt.sessions != nil && !isSessionCommand(cmdline) {
+		// run the command in the current session
+		if _, cur := rt.currentSession(); cur != rt {
+			return cur.handleCommand(cmdline, output)
+		}
+	}
+
 	if rt.contextNodeId != InvalidNodeId && !isContextlessCommand(cmdline) {
 		// run the command in node context
 		cmd := Command{
@@ -133,6 +162,12 @@ func (rt *CmdRunner) HandleCommand(cmdline string, output io.Writer) error {
 }
 
 func (rt *CmdRunner) GetPrompt() string {
+	if rt.sessions != nil {
+		if id, cur := rt.currentSession(); cur != rt {
+			return fmt.Sprintf("session %d: %s", id, cur.GetPrompt())
+		}
+	}
+
 	if rt.contextNodeId == InvalidNodeId {
 		return Prompt
 	} else {
@@ -169,14 +204,96 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 
 	if cmd.Move != nil {
 		rt.executeMoveNode(cc, cc.Move)
+	} else if cmd.Align != nil {
+		rt.executeAlign(cc, cc.Align)
+	} else if cmd.Grid != nil {
+		rt.executeGrid(cc, cc.Grid)
 	} else if cmd.Radio != nil {
 		rt.executeRadio(cc, cc.Radio)
+	} else if cmd.RadioModel != nil {
+		rt.executeRadioModel(cc, cc.RadioModel)
+	} else if cmd.RadioParam != nil {
+		rt.executeRadioParam(cc, cc.RadioParam)
+	} else if cmd.Election != nil {
+		rt.executeElection(cc, cc.Election)
+	} else if cmd.Energy != nil {
+		rt.executeEnergy(cc, cc.Energy)
+	} else if cmd.EnergyScan != nil {
+		rt.executeEnergyScan(cc, cc.EnergyScan)
+	} else if cmd.Script != nil {
+		rt.executeScript(cc, cc.Script)
+	} else if cmd.Watch != nil {
+		rt.executeWatch(cc, cc.Watch)
+	} else if cmd.Unwatch != nil {
+		rt.executeUnwatch(cc, cc.Unwatch)
+	} else if cmd.Timeline != nil {
+		rt.executeTimeline(cc, cc.Timeline)
+	} else if cmd.Roles != nil {
+		rt.executeRoles(cc, cc.Roles)
+	} else if cmd.NetDiag != nil {
+		rt.executeNetDiag(cc, cc.NetDiag)
+	} else if cmd.NetData != nil {
+		rt.executeNetData(cc, cc.NetData)
+	} else if cmd.RadioRange != nil {
+		rt.executeRadioRange(cc, cc.RadioRange)
+	} else if cmd.RfSim != nil {
+		rt.executeRfSim(cc, cc.RfSim)
+	} else if cmd.Range != nil {
+		rt.executeRange(cc, cc.Range)
+	} else if cmd.Radios != nil {
+		rt.executeRadios(cc, cc.Radios)
+	} else if cmd.AttachLog != nil {
+		rt.executeAttachLog(cc, cc.AttachLog)
+	} else if cmd.CmdBudget != nil {
+		rt.executeCmdBudget(cc, cc.CmdBudget)
+	} else if cmd.Alert != nil {
+		rt.executeAlert(cc, cc.Alert)
+	} else if cmd.Health != nil {
+		rt.executeHealth(cc, cc.Health)
+	} else if cmd.Zombies != nil {
+		rt.executeZombies(cc, cc.Zombies)
+	} else if cmd.History != nil {
+		rt.executeHistory(cc, cc.History)
+	} else if cmd.Stall != nil {
+		rt.executeStall(cc, cc.Stall)
+	} else if cmd.Site != nil {
+		rt.executeSite(cc, cc.Site)
+	} else if cmd.Jobs != nil {
+		rt.executeJobs(cc, cc.Jobs)
+	} else if cmd.LinkMetrics != nil {
+		rt.executeLinkMetrics(cc, cc.LinkMetrics)
+	} else if cmd.Cancel != nil {
+		rt.executeCancel(cc, cc.Cancel)
+	} else if cmd.Capture != nil {
+		rt.executeCapture(cc, cc.Capture)
+	} else if cmd.Dup != nil {
+		rt.executeDup(cc, cc.Dup)
+	} else if cmd.Reorder != nil {
+		rt.executeReorder(cc, cc.Reorder)
+	} else if cmd.MacStats != nil {
+		rt.executeMacStats(cc, cc.MacStats)
+	} else if cmd.Templates != nil {
+		rt.executeTemplates(cc, cc.Templates)
+	} else if cmd.Format != nil {
+		rt.executeFormat(cc, cc.Format)
+	} else if cmd.Summary != nil {
+		rt.executeSummary(cc, cc.Summary)
+	} else if cmd.Top != nil {
+		rt.executeTop(cc, cc.Top)
+	} else if cmd.Throttle != nil {
+		rt.executeThrottle(cc, cc.Throttle)
 	} else if cmd.Go != nil {
 		rt.executeGo(cc, cmd.Go)
 	} else if cmd.Nodes != nil {
 		rt.executeLsNodes(cc, cc.Nodes)
 	} else if cmd.Partitions != nil {
-		rt.executeLsPartitions(cc)
+		rt.executeLsPartitions(cc, cc.Partitions)
+	} else if cmd.Provision != nil {
+		rt.executeProvision(cc, cc.Provision)
+	} else if cmd.Power != nil {
+		rt.executePower(cc, cc.Power)
+	} else if cmd.Pcap != nil {
+		rt.executePcap(cc, cc.Pcap)
 	} else if cmd.Add != nil {
 		rt.executeAddNode(cc, cmd.Add)
 	} else if cmd.Del != nil {
@@ -191,12 +308,16 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 		rt.executeSpeed(cc, cmd.Speed)
 	} else if cmd.Plr != nil {
 		rt.executePlr(cc, cc.Plr)
+	} else if cmd.PingAll != nil {
+		rt.executePingAll(cc, cmd.PingAll)
 	} else if cmd.Pings != nil {
 		rt.executeCollectPings(cc, cc.Pings)
 	} else if cmd.Counters != nil {
 		rt.executeCounters(cc, cc.Counters)
 	} else if cmd.Joins != nil {
 		rt.executeCollectJoins(cc, cc.Joins)
+	} else if cmd.Coalesce != nil {
+		rt.executeCoalesce(cc, cc.Coalesce)
 	} else if cmd.Coaps != nil {
 		rt.executeCoaps(cc, cc.Coaps)
 	} else if cmd.Scan != nil {
@@ -215,6 +336,52 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 		rt.executeWeb(cc, cc.Web)
 	} else if cmd.NetInfo != nil {
 		rt.executeNetInfo(cc, cc.NetInfo)
+	} else if cmd.Geo != nil {
+		rt.executeGeo(cc, cc.Geo)
+	} else if cmd.Jam != nil {
+		rt.executeJam(cc, cc.Jam)
+	} else if cmd.Airtime != nil {
+		rt.executeAirtime(cc, cc.Airtime)
+	} else if cmd.Antenna != nil {
+		rt.executeAntenna(cc, cc.Antenna)
+	} else if cmd.Compare != nil {
+		rt.executeCompare(cc, cc.Compare)
+	} else if cmd.Kpi != nil {
+		rt.executeKpi(cc, cc.Kpi)
+	} else if cmd.Stats != nil {
+		rt.executeStatsWindow(cc, cc.Stats.Window)
+	} else if cmd.Pause != nil {
+		rt.executePause(cc, cc.Pause)
+	} else if cmd.Reset != nil {
+		rt.executeReset(cc, cc.Reset)
+	} else if cmd.Resume != nil {
+		rt.executeResume(cc, cc.Resume)
+	} else if cmd.Upgrade != nil {
+		rt.executeUpgrade(cc, cc.Upgrade)
+	} else if cmd.Upgrades != nil {
+		rt.executeUpgrades(cc, cc.Upgrades)
+	} else if cmd.Srp != nil {
+		rt.executeSrpStats(cc, cc.Srp)
+	} else if cmd.Send != nil {
+		rt.executeSend(cc, cc.Send)
+	} else if cmd.Frag != nil {
+		rt.executeFrag(cc, cc.Frag)
+	} else if cmd.Wait != nil {
+		rt.executeWait(cc, cc.Wait)
+	} else if cmd.LinkStats != nil {
+		rt.executeLinkStats(cc, cc.LinkStats)
+	} else if cmd.Load != nil {
+		rt.executeLoad(cc, cc.Load)
+	} else if cmd.Save != nil {
+		rt.executeSave(cc, cc.Save)
+	} else if cmd.Session != nil {
+		rt.executeSession(cc, cc.Session)
+	} else if cmd.Drift != nil {
+		rt.executeDrift(cc, cc.Drift)
+	} else if cmd.DutyCycle != nil {
+		rt.executeDutyCycle(cc, cc.DutyCycle)
+	} else if cmd.Coverage != nil {
+		rt.executeCoverage(cc, cc.Coverage)
 	} else {
 		simplelogger.Panicf("unimplemented command: %#v", cmd)
 	}
@@ -250,7 +417,9 @@ func (rt *CmdRunner) executeGo(cc *CommandContext, cmd *GoCmd) {
 
 func (rt *CmdRunner) executeSpeed(cc *CommandContext, cmd *SpeedCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		if cmd.Speed == nil && cmd.Max == nil {
+		if cmd.Auto != nil {
+			rt.executeAutoSpeed(cc, sim, cmd.Auto)
+		} else if cmd.Speed == nil && cmd.Max == nil {
 			cc.outputf("%v\n", sim.GetSpeed())
 		} else if cmd.Max != nil {
 			sim.SetSpeed(dispatcher.MaxSimulateSpeed)
@@ -260,6 +429,24 @@ func (rt *CmdRunner) executeSpeed(cc *CommandContext, cmd *SpeedCmd) {
 	})
 }
 
+func (rt *CmdRunner) executeAutoSpeed(cc *CommandContext, sim *simulation.Simulation, cmd *AutoSpeedFlag) {
+	if cmd.Off != nil {
+		_ = sim.SetAutoSpeed(0)
+	} else if cmd.Target != nil {
+		if *cmd.Target <= 0 || *cmd.Target > 100 {
+			cc.errorf("invalid target CPU usage: %v%%", *cmd.Target)
+			return
+		}
+		if err := sim.SetAutoSpeed(*cmd.Target); err != nil {
+			cc.error(err)
+		}
+	} else if target := sim.AutoSpeed(); target > 0 {
+		cc.outputf("%v%%\n", target)
+	} else {
+		cc.outputf("off\n")
+	}
+}
+
 func (rt *CmdRunner) postAsyncWait(f func(sim *simulation.Simulation)) {
 	done := make(chan struct{})
 	rt.sim.PostAsync(false, func() {
@@ -272,6 +459,7 @@ func (rt *CmdRunner) postAsyncWait(f func(sim *simulation.Simulation)) {
 func (rt *CmdRunner) executeAddNode(cc *CommandContext, cmd *AddCmd) {
 	simplelogger.Infof("Add: %#v", *cmd)
 	cfg := simulation.DefaultNodeConfig()
+	var template *simulation.NodeTemplate
 	if cmd.X != nil {
 		cfg.X = *cmd.X
 	}
@@ -296,24 +484,128 @@ func (rt *CmdRunner) executeAddNode(cc *CommandContext, cmd *AddCmd) {
 		cfg.IsMtd = true
 		cfg.RxOffWhenIdle = true
 	} else {
-		panic("wrong node type")
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			template = sim.NodeTemplate(cmd.Type.Val)
+		})
+		if template == nil {
+			cc.errorf("unknown node type or template: %s", cmd.Type.Val)
+			return
+		}
+		if err := template.Apply(cfg); err != nil {
+			cc.errorf("template %s: %v", cmd.Type.Val, err)
+			return
+		}
 	}
 
 	if cmd.Id != nil {
 		cfg.ID = cmd.Id.Val
 	}
 
-	if cmd.RadioRange != nil {
-		cfg.RadioRange = cmd.RadioRange.Val
-	}
-
 	if cmd.Executable != nil {
 		cfg.ExecutablePath = cmd.Executable.Path
 	}
 
 	cfg.Restore = cmd.Restore != nil
 
+	if cmd.Poll != nil {
+		if !cfg.RxOffWhenIdle {
+			cc.errorf("poll period only applies to sed")
+			return
+		}
+
+		ms := cmd.Poll.Period * 1000
+		if cmd.Poll.Unit == "ms" {
+			ms = cmd.Poll.Period
+		}
+		if ms < 1 || ms > math.MaxUint32 {
+			cc.errorf("invalid poll period: %v%s", cmd.Poll.Period, cmd.Poll.Unit)
+			return
+		}
+		cfg.PollPeriod = int(ms)
+	}
+
+	if cmd.Script != nil {
+		cfg.InitScript = cmd.Script.Path
+	}
+
+	if cmd.BootDelay != nil {
+		ms := cmd.BootDelay.Delay * 1000
+		if cmd.BootDelay.Unit == "ms" {
+			ms = cmd.BootDelay.Delay
+		}
+		if ms < 0 || ms > math.MaxInt32 {
+			cc.errorf("invalid boot delay: %v%s", cmd.BootDelay.Delay, cmd.BootDelay.Unit)
+			return
+		}
+		cfg.BootDelay = int(ms)
+	}
+
+	if cmd.BootCrash != nil {
+		if cmd.BootCrash.Prob < 0 || cmd.BootCrash.Prob > 1 {
+			cc.errorf("invalid boot crash probability: %v", cmd.BootCrash.Prob)
+			return
+		}
+		cfg.BootCrashProb = cmd.BootCrash.Prob
+	}
+
+	if cmd.CpuFactor != nil {
+		if cmd.CpuFactor.Factor <= 0 || cmd.CpuFactor.Factor > dispatcher.MaxCpuFactor {
+			cc.errorf("invalid cpu factor: %v", cmd.CpuFactor.Factor)
+			return
+		}
+		cfg.CpuFactor = cmd.CpuFactor.Factor
+	}
+
+	if len(cmd.Vars) > 0 {
+		if cfg.ScriptVars == nil {
+			cfg.ScriptVars = map[string]string{}
+		}
+		for _, v := range cmd.Vars {
+			if err := simulation.ValidateScriptVarName(v.Name); err != nil {
+				cc.error(err)
+				return
+			}
+			cfg.ScriptVars[v.Name] = v.Value
+		}
+	}
+
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if template == nil || template.RadioRange == 0 {
+			cfg.RadioRange = 0
+		}
+		if cmd.RadioRange != nil {
+			cfg.RadioRange = cmd.RadioRange.Val
+		}
+		if cmd.Like != nil {
+			if err := sim.CloneNodeConfig(cfg, cmd.Like.Node.Id); err != nil {
+				cc.error(err)
+				return
+			}
+		}
+		if cfg.RadioRange == 0 {
+			cfg.RadioRange = sim.DefaultRadioRange()
+		}
+
+		if cmd.Geo != nil {
+			origin := sim.GeoOrigin()
+			if origin == nil {
+				cc.errorf("geographic mode is disabled")
+				return
+			}
+			cfg.X, cfg.Y = origin.FromGeo(cmd.Geo.Lat, cmd.Geo.Lon)
+		}
+
+		if cmd.At != nil {
+			nodeid, err := sim.AddNodeAt(cfg, uint64(cmd.At.Seconds*1000000))
+			if err != nil {
+				cc.error(err)
+				return
+			}
+
+			cc.outputf("%d\n", nodeid)
+			return
+		}
+
 		node, err := sim.AddNode(cfg)
 		if err != nil {
 			cc.error(err)
@@ -324,6 +616,67 @@ func (rt *CmdRunner) executeAddNode(cc *CommandContext, cmd *AddCmd) {
 	})
 }
 
+func (rt *CmdRunner) executeTemplates(cc *CommandContext, cmd *TemplatesCmd) {
+	if cmd.Load != nil {
+		templates, err := simulation.ReadNodeTemplates(*cmd.Load)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			sim.SetNodeTemplates(templates)
+		})
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		templates := sim.NodeTemplates()
+		names := make([]string, 0, len(templates))
+		for name := range templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			t := templates[name]
+			cc.outputf("%-16s type=%-6s", name, t.Type)
+			if t.Extends != "" {
+				cc.outputf(" extends=%s", t.Extends)
+			}
+			if t.Executable != "" {
+				cc.outputf(" exe=%s", t.Executable)
+			}
+			if t.Version != 0 {
+				cc.outputf(" version=%d", t.Version)
+			}
+			if t.RadioRange != 0 {
+				cc.outputf(" rr=%d", t.RadioRange)
+			}
+			if t.ClockDrift != 0 {
+				cc.outputf(" drift=%gppm", t.ClockDrift)
+			}
+			if t.CpuFactor != 0 {
+				cc.outputf(" cpufactor=%g", t.CpuFactor)
+			}
+			if t.PollPeriod != 0 {
+				cc.outputf(" poll=%dms", t.PollPeriod)
+			}
+			if t.InitScript != "" {
+				cc.outputf(" script=%s", t.InitScript)
+			}
+			vars := make([]string, 0, len(t.Vars))
+			for name, val := range t.Vars {
+				vars = append(vars, fmt.Sprintf("%s=%s", name, val))
+			}
+			sort.Strings(vars)
+			if len(vars) > 0 {
+				cc.outputf(" vars=%s", strings.Join(vars, ","))
+			}
+			cc.outputf("\n")
+		}
+	})
+}
+
 func (rt *CmdRunner) executeDelNode(cc *CommandContext, cmd *DelCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		for _, sel := range cmd.Nodes {
@@ -343,10 +696,31 @@ func (rt *CmdRunner) executeExit(cc *CommandContext, cmd *ExitCmd) {
 		return
 	}
 
+	if rt.client != nil {
+		// remote clients only disconnect
+		rt.client.exited = true
+		return
+	}
+
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		sim.Stop()
 	})
-	rt.ctx.Cancel("exit")
+	rt.progCtx().Cancel("exit")
+}
+
+func (rt *CmdRunner) executeReset(cc *CommandContext, cmd *ResetCmd) {
+	rt.enterNodeContext(InvalidNodeId)
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		sim.Reset()
+	})
+}
+
+// progCtx returns the program context, which differs from the context of the runner in sessions other than the main.
+func (rt *CmdRunner) progCtx() *progctx.ProgCtx {
+	if rt.sessions != nil {
+		return rt.sessions.ctx
+	}
+	return rt.ctx
 }
 
 func (rt *CmdRunner) executePing(cc *CommandContext, cmd *PingCmd) {
@@ -397,384 +771,3736 @@ func (rt *CmdRunner) executePing(cc *CommandContext, cmd *PingCmd) {
 			hopLimit = cmd.HopLimit.Val
 		}
 
-		src.Ping(dstaddr, datasize, count, interval, hopLimit)
+		job, err := sim.StartPing(src.Id, dstaddr, datasize, count, interval, hopLimit)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("%d\n", job.Id)
 	})
 }
 
-func (rt *CmdRunner) getNode(sim *simulation.Simulation, sel NodeSelector) (*simulation.Node, *dispatcher.Node) {
-	if sel.Id > 0 {
-		return sim.Nodes()[sel.Id], sim.Dispatcher().Nodes()[sel.Id]
-	}
-
-	panic("node selector not implemented")
-}
-
-func (rt *CmdRunner) getAddrs(node *simulation.Node, addrType *AddrTypeFlag) []string {
-	if node == nil {
-		return nil
-	}
-
-	var addrs []string
-	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeMleid {
-		addrs = append(addrs, node.GetIpAddrMleid()...)
-	}
-
-	if len(addrs) > 0 {
-		return addrs
+func (rt *CmdRunner) executeSend(cc *CommandContext, cmd *SendCmd) {
+	if cmd.Report != nil {
+		rt.executeSendReport(cc, cmd.Report)
+		return
 	}
 
-	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeRloc {
-		addrs = append(addrs, node.GetIpAddrRloc()...)
-	}
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		src, _ := rt.getNode(sim, *cmd.Src)
+		if src == nil {
+			cc.errorf("src node not found")
+			return
+		}
 
-	if len(addrs) > 0 {
-		return addrs
-	}
+		var group string
+		if cmd.Scope != nil {
+			group = simulation.SendScopeGroups[*cmd.Scope]
+		} else {
+			group = cmd.Group.Addr
+		}
 
-	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeLinkLocal {
-		addrs = append(addrs, node.GetIpAddrLinkLocal()...)
-	}
+		datasize := 0
+		count := 1
+		interval := 1
 
-	return addrs
-}
+		if cmd.DataSize != nil {
+			datasize = cmd.DataSize.Val
+		}
 
-func (rt *CmdRunner) executeDebug(cc *CommandContext, cmd *DebugCmd) {
-	simplelogger.Infof("debug %#v", *cmd)
+		if cmd.Count != nil {
+			count = cmd.Count.Val
+		}
 
-	if cmd.Echo != nil {
-		cc.outputf("%s\n", *cmd.Echo)
-	}
+		if cmd.Interval != nil {
+			interval = cmd.Interval.Val
+		}
 
-	if cmd.Fail != nil {
-		cc.errorf("debug failed")
-	}
+		job, err := sim.SendMulticast(src.Id, group, count, uint64(interval)*1000000, datasize)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("%d\n", job.Id)
+	})
 }
 
-func (rt *CmdRunner) executeNode(cc *CommandContext, cmd *NodeCmd) {
-	contextNodeId := InvalidNodeId
+func (rt *CmdRunner) executeJobs(cc *CommandContext, cmd *JobsCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		node, _ := rt.getNode(sim, cmd.Node)
-		if node == nil {
-			cc.errorf("node not found")
+		jobs := sim.Jobs()
+		if cc.isJsonOutput(cmd.Json) {
+			cc.outputJson(jobs)
 			return
 		}
 
-		defer func() {
-			err := recover()
-			if err != nil {
-				cc.errorf("%+v", err)
-			}
-		}()
-
-		if cmd.Command != nil {
-			output := node.Command(*cmd.Command, simulation.DefaultCommandTimeout)
-			for _, line := range output {
-				cc.outputf("%s\n", line)
-			}
-		} else {
-			contextNodeId = node.Id
+		for _, job := range jobs {
+			cc.outputf("%-4d %-4s %-8s src=%d dst=%s sent=%d/%d received=%d/%d\n", job.Id, job.Kind, job.State, job.Src,
+				job.Dst, job.Sent, job.Count, job.Received, job.Expected)
 		}
 	})
-
-	if contextNodeId != InvalidNodeId {
-		// enter node context
-		rt.enterNodeContext(contextNodeId)
-	}
 }
 
-func (rt *CmdRunner) executeDemoLegend(cc *CommandContext, cmd *DemoLegendCmd) {
+func (rt *CmdRunner) executeCancel(cc *CommandContext, cmd *CancelCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		sim.ShowDemoLegend(cmd.X, cmd.Y, cmd.Title)
+		cc.error(sim.CancelJob(cmd.Job))
 	})
 }
 
-func (rt *CmdRunner) executeCountDown(cc *CommandContext, cmd *CountDownCmd) {
-	title := "%v"
-	if cmd.Text != nil {
-		title = *cmd.Text
-	}
+func (rt *CmdRunner) executeSendReport(cc *CommandContext, cmd *SendReport) {
+	var report []*simulation.SendDelivery
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		sim.CountDown(time.Duration(cmd.Seconds)*time.Second, title)
+		if cmd.Reset != nil {
+			sim.ResetSendReport()
+		} else {
+			report = sim.SendReport()
+		}
 	})
+
+	for _, sd := range report {
+		cc.outputf("group=%-16s src=%-4d dst=%-4d sent=%-4d recv=%-4d ratio=%.2f%%\n", sd.Group, sd.Src, sd.Dst, sd.Sent,
+			sd.Received, sd.Ratio()*100)
+	}
 }
 
-func (rt *CmdRunner) executeRadio(cc *CommandContext, radio *RadioCmd) {
-	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		for _, sel := range radio.Nodes {
-			node, dnode := rt.getNode(sim, sel)
-			if node == nil {
-				cc.errorf("node %d not found", sel.Id)
-				continue
-			}
+func (rt *CmdRunner) executeFrag(cc *CommandContext, cmd *FragCmd) {
+	if cmd.Stats != nil {
+		rt.executeFragStats(cc, cmd.Stats)
+		return
+	}
 
-			if radio.On != nil {
-				sim.SetNodeFailed(node.Id, false)
-			} else if radio.Off != nil {
-				sim.SetNodeFailed(node.Id, true)
-			} else if radio.FailTime != nil {
-				if radio.FailTime.FailInterval > 0 && radio.FailTime.FailDuration > 0 {
-					dnode.SetFailTime(dispatcher.FailTime{
-						FailDuration: uint64(radio.FailTime.FailDuration * 1000000),
-						FailInterval: uint64(radio.FailTime.FailInterval * 1000000),
-					})
-				} else {
-					dnode.SetFailTime(dispatcher.NonFailTime)
-				}
-			}
-		}
-	})
-}
+	send := cmd.Send
+	datasize := simulation.FragDefaultDataSize
+	count := 1
+	interval := 1
+
+	if send.DataSize != nil {
+		datasize = send.DataSize.Val
+	}
+
+	if send.Count != nil {
+		count = send.Count.Val
+	}
+
+	if send.Interval != nil {
+		interval = send.Interval.Val
+	}
 
-func (rt *CmdRunner) executeMoveNode(cc *CommandContext, cmd *Move) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		sim.MoveNodeTo(cmd.Target.Id, cmd.X, cmd.Y)
+		src, _ := rt.getNode(sim, send.Src)
+		dst, _ := rt.getNode(sim, send.Dst)
+		if src == nil || dst == nil {
+			cc.errorf("src or dst node not found")
+			return
+		}
+
+		job, err := sim.SendUnicast(src.Id, dst.Id, count, uint64(interval)*1000000, datasize)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("%d\n", job.Id)
 	})
 }
 
-func (rt *CmdRunner) executeLsNodes(cc *CommandContext, cmd *NodesCmd) {
+func (rt *CmdRunner) executeFragStats(cc *CommandContext, cmd *FragStatsFlag) {
+	var stats dispatcher.FragStats
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		for nodeid := range sim.Nodes() {
-			dnode := sim.Dispatcher().GetNode(nodeid)
-			var line strings.Builder
-			line.WriteString(fmt.Sprintf("id=%d\textaddr=%016x\trloc16=%04x\tx=%d\ty=%d\tstate=%s\tfailed=%v", nodeid, dnode.ExtAddr, dnode.Rloc16,
-				dnode.X, dnode.Y, dnode.Role, dnode.IsFailed()))
-			cc.outputf("%s\n", line.String())
+		d := sim.Dispatcher()
+		if cmd.Reset != nil {
+			d.ResetFragStats()
+		} else {
+			stats = d.GetFragStats()
 		}
 	})
-}
 
-func (rt *CmdRunner) executeLsPartitions(cc *CommandContext) {
+	if cmd.Reset != nil {
+		return
+	}
+
+	if cc.isJsonOutput(nil) {
+		cc.outputJson(stats)
+		return
+	}
+
+	cc.outputf("datagrams=%d fragments=%d receptions=%d reassembled=%d ratio=%.2f%% pending=%d undecrypted=%d\n",
+		stats.Datagrams, stats.Fragments, stats.Receptions, stats.Reassembled, stats.SuccessRatio()*100, stats.Pending,
+		stats.Undecrypted)
+	ls := stats.Latency
+	cc.outputf("latency  count=%-4d p50=%.3fs p90=%.3fs p99=%.3fs max=%.3fs\n", ls.Count, float64(ls.P50)/1000000,
+		float64(ls.P90)/1000000, float64(ls.P99)/1000000, float64(ls.Max)/1000000)
+	for _, ns := range stats.Nodes {
+		cc.outputf("node=%-4d datagrams=%-4d receptions=%-4d reassembled=%-4d\n", ns.Node, ns.Datagrams, ns.Receptions,
+			ns.Reassembled)
+	}
+}
+
+func (rt *CmdRunner) executePingAll(cc *CommandContext, cmd *PingAllCmd) {
+	datasize := 4
+	count := 1
+	interval := 1
+
+	if cmd.DataSize != nil {
+		datasize = cmd.DataSize.Val
+	}
+
+	if cmd.Count != nil {
+		count = cmd.Count.Val
+	}
+
+	if cmd.Interval != nil {
+		interval = cmd.Interval.Val
+	}
+
+	if datasize < 4 || count <= 0 || interval <= 0 {
+		// ping results are only tracked with datasize >= 4
+		cc.errorf("invalid datasize, count or interval")
+		return
+	}
+
+	var nodeids []NodeId
+	dstAddrs := map[NodeId]string{}
+	addrNodes := map[string]NodeId{}
+	var startTime uint64
+	var oldSpeed, oldAutoSpeed float64
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if len(cmd.Nodes) > 0 {
+			for _, sel := range cmd.Nodes {
+				if node, _ := rt.getNode(sim, sel); node != nil {
+					nodeids = append(nodeids, node.Id)
+				} else {
+					cc.errorf("node %d not found", sel.Id)
+				}
+			}
+		} else {
+			sim.VisitNodesInOrder(func(node *simulation.Node) {
+				nodeids = append(nodeids, node.Id)
+			})
+		}
+
+		if cc.Err() != nil {
+			return
+		}
+
+		for _, id := range nodeids {
+			addrs := rt.getAddrs(sim.Nodes()[id], nil)
+			if len(addrs) > 0 {
+				dstAddrs[id] = addrs[0]
+				addrNodes[normalizeIp6Addr(addrs[0])] = id
+			}
+		}
+
+		startTime = sim.Dispatcher().CurTime
+		oldSpeed, oldAutoSpeed = sim.GetSpeed(), sim.AutoSpeed()
+		sim.SetSpeed(dispatcher.MaxSimulateSpeed)
+	})
+
+	if cc.Err() != nil {
+		return
+	}
+
+	// a node runs one ping session at a time, so each source pings one destination per round until its session is over
+	roundDuration := time.Duration(count-1)*time.Duration(interval)*time.Second +
+		time.Duration(dispatcher.MaxPingDelayUs)*time.Microsecond
+	for round := 1; round < len(nodeids) && rt.ctx.Err() == nil; round++ {
+		var done <-chan struct{}
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			for i, src := range nodeids {
+				dst := nodeids[(i+round)%len(nodeids)]
+				if node := sim.Nodes()[src]; node != nil && dstAddrs[dst] != "" {
+					node.Ping(dstAddrs[dst], datasize, count, interval, 64)
+				}
+			}
+			done = sim.Go(roundDuration)
+		})
+		<-done
+	}
+
+	type pairStats struct {
+		received int
+		delay    uint64
+	}
+	stats := map[[2]NodeId]*pairStats{}
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		sim.SetSpeed(oldSpeed)
+		if oldAutoSpeed > 0 {
+			_ = sim.SetAutoSpeed(oldAutoSpeed)
+		}
+		for _, src := range nodeids {
+			node := sim.Dispatcher().GetNode(src)
+			if node == nil {
+				continue
+			}
+			for _, ping := range node.CollectPingsSince(startTime) {
+				dst, ok := addrNodes[normalizeIp6Addr(ping.Dst)]
+				if !ok || ping.Delay >= dispatcher.MaxPingDelayUs {
+					continue
+				}
+
+				ps := stats[[2]NodeId{src, dst}]
+				if ps == nil {
+					ps = &pairStats{}
+					stats[[2]NodeId{src, dst}] = ps
+				}
+				ps.received += 1
+				ps.delay += ping.Delay
+			}
+		}
+	})
+
+	var header strings.Builder
+	header.WriteString("src\\dst")
+	for _, dst := range nodeids {
+		header.WriteString(fmt.Sprintf(" %12d", dst))
+	}
+	cc.outputf("%s\n", header.String())
+
+	var unreachable []string
+	for _, src := range nodeids {
+		var line strings.Builder
+		line.WriteString(fmt.Sprintf("%-7d", src))
+		for _, dst := range nodeids {
+			if src == dst {
+				line.WriteString(fmt.Sprintf(" %12s", "-"))
+				continue
+			}
+
+			ps := stats[[2]NodeId{src, dst}]
+			if ps == nil {
+				line.WriteString(fmt.Sprintf(" %12s", "100%"))
+				unreachable = append(unreachable, fmt.Sprintf("%d->%d", src, dst))
+				continue
+			}
+
+			loss := float64(count-ps.received) * 100 / float64(count)
+			if loss < 0 {
+				loss = 0
+			}
+			line.WriteString(fmt.Sprintf(" %12s", fmt.Sprintf("%.0f%%/%.0fms", loss, float64(ps.delay)/float64(ps.received)/1000)))
+		}
+		cc.outputf("%s\n", line.String())
+	}
+
+	if len(unreachable) > 0 {
+		cc.outputf("unreachable: %s\n", strings.Join(unreachable, " "))
+	}
+}
+
+// joinNodeIds formats node IDs as a comma-separated list.
+func joinNodeIds(nodeids []NodeId) string {
+	ids := make([]string, len(nodeids))
+	for i, nodeid := range nodeids {
+		ids[i] = strconv.Itoa(nodeid)
+	}
+	return strings.Join(ids, ",")
+}
+
+// expandNodeRanges returns the node IDs of the node ranges in order. It fails if a range is invalid.
+func expandNodeRanges(ranges []NodeRange) ([]NodeId, error) {
+	var nodeids []NodeId
+	for _, r := range ranges {
+		to := r.From
+		if r.To != nil {
+			to = *r.To
+		}
+		if r.From <= 0 || to < r.From {
+			return nil, errors.Errorf("invalid node range: %d-%d", r.From, to)
+		}
+		for id := r.From; id <= to; id++ {
+			nodeids = append(nodeids, id)
+		}
+	}
+	return nodeids, nil
+}
+
+// selectNodes returns the IDs of the existing nodes in the node ranges, or of all nodes if there are no ranges. It
+// fails if a range is invalid or no node is selected.
+func selectNodes(sim *simulation.Simulation, ranges []NodeRange) ([]NodeId, error) {
+	var nodeids []NodeId
+	if len(ranges) == 0 {
+		sim.VisitNodesInOrder(func(node *simulation.Node) {
+			nodeids = append(nodeids, node.Id)
+		})
+	}
+
+	ids, err := expandNodeRanges(ranges)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if sim.Dispatcher().GetNode(id) != nil {
+			nodeids = append(nodeids, id)
+		}
+	}
+
+	if len(nodeids) == 0 {
+		return nil, errors.Errorf("node not found")
+	}
+	return nodeids, nil
+}
+
+func normalizeIp6Addr(addr string) string {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String()
+	}
+	return addr
+}
+
+func (rt *CmdRunner) getNode(sim *simulation.Simulation, sel NodeSelector) (*simulation.Node, *dispatcher.Node) {
+	if sel.Id > 0 {
+		return sim.Nodes()[sel.Id], sim.Dispatcher().Nodes()[sel.Id]
+	}
+
+	panic("node selector not implemented")
+}
+
+func (rt *CmdRunner) getAddrs(node *simulation.Node, addrType *AddrTypeFlag) []string {
+	if node == nil {
+		return nil
+	}
+
+	var addrs []string
+	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeMleid {
+		addrs = append(addrs, node.GetIpAddrMleid()...)
+	}
+
+	if len(addrs) > 0 {
+		return addrs
+	}
+
+	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeRloc {
+		addrs = append(addrs, node.GetIpAddrRloc()...)
+	}
+
+	if len(addrs) > 0 {
+		return addrs
+	}
+
+	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeLinkLocal {
+		addrs = append(addrs, node.GetIpAddrLinkLocal()...)
+	}
+
+	return addrs
+}
+
+func (rt *CmdRunner) executeDebug(cc *CommandContext, cmd *DebugCmd) {
+	simplelogger.Infof("debug %#v", *cmd)
+
+	if cmd.Echo != nil {
+		cc.outputf("%s\n", *cmd.Echo)
+	}
+
+	if cmd.Fail != nil {
+		cc.errorf("debug failed")
+	}
+}
+
+func (rt *CmdRunner) executeNode(cc *CommandContext, cmd *NodeCmd) {
+	if cmd.Command != nil && rt.executeBudgetedNodeCommand(cc, cmd) {
+		return
+	}
+
+	contextNodeId := InvalidNodeId
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		node, dnode := rt.getNode(sim, cmd.Node)
+		if node == nil {
+			cc.errorf("node not found")
+			return
+		}
+
+		if cmd.Command != nil && dnode.IsPaused() {
+			cc.errorf("node %d is paused", node.Id)
+			return
+		}
+
+		defer func() {
+			err := recover()
+			if err != nil {
+				cc.errorf("%+v", err)
+			}
+		}()
+
+		if cmd.Command != nil {
+			output := node.Command(*cmd.Command, simulation.DefaultCommandTimeout)
+			for _, line := range output {
+				cc.outputf("%s\n", line)
+			}
+		} else {
+			contextNodeId = node.Id
+		}
+	})
+
+	if contextNodeId != InvalidNodeId {
+		// enter node context
+		rt.enterNodeContext(contextNodeId)
+	}
+}
+
+// executeBudgetedNodeCommand runs the node command under the command budget, advancing the simulation until the
+// command completes or exhausts the budget. It returns false if there is no command budget.
+func (rt *CmdRunner) executeBudgetedNodeCommand(cc *CommandContext, cmd *NodeCmd) bool {
+	budgeted := false
+	var bc *simulation.BudgetedCommand
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if sim.CommandBudget() == 0 {
+			return
+		}
+
+		budgeted = true
+		node, _ := rt.getNode(sim, cmd.Node)
+		if node == nil {
+			cc.errorf("node not found")
+			return
+		}
+		var err error
+		if bc, err = sim.StartCommand(node.Id, *cmd.Command); err != nil {
+			cc.error(err)
+		}
+	})
+	if !budgeted || cc.Err() != nil {
+		return budgeted
+	}
+
+	for done := false; !done; {
+		var goDone <-chan struct{}
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			if done = bc.Poll(); !done {
+				goDone = sim.Go(simulation.CommandBudgetStep)
+			}
+		})
+		if goDone != nil {
+			<-goDone
+		}
+	}
+
+	result := bc.Result()
+	for _, line := range result.Output {
+		cc.outputf("%s\n", line)
+	}
+	if bc.Cost().Exceeded {
+		cc.errorf("command exceeded the budget of %.3fs", float64(bc.Cost().Cost)/1000000)
+	} else if result.Error != "" {
+		cc.errorf("%s", result.Error)
+	}
+	return true
+}
+
+func (rt *CmdRunner) executeCmdBudget(cc *CommandContext, cmd *CmdBudgetCmd) {
+	if cmd.Budget != nil && *cmd.Budget <= 0 {
+		cc.errorf("invalid command budget: %gs", *cmd.Budget)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Off != nil {
+			sim.SetCommandBudget(0)
+		} else if cmd.Budget != nil {
+			sim.SetCommandBudget(uint64(*cmd.Budget * 1000000))
+		} else if cmd.Reset != nil {
+			sim.ResetCommandCosts()
+		} else if cmd.Costs != nil {
+			costs := sim.CommandCosts()
+			if cc.isJsonOutput(cmd.Costs.Json) {
+				cc.outputJson(costs)
+				return
+			}
+			for _, c := range costs {
+				exceeded := ""
+				if c.Exceeded {
+					exceeded = " exceeded"
+				}
+				cc.outputf("node=%-4d start=%.6fs cost=%.6fs%s command=%q\n", c.Node, float64(c.Start)/1000000,
+					float64(c.Cost)/1000000, exceeded, c.Command)
+			}
+		} else if sim.CommandBudget() == 0 {
+			cc.outputf("off\n")
+		} else {
+			cc.outputf("%gs\n", float64(sim.CommandBudget())/1000000)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeDemoLegend(cc *CommandContext, cmd *DemoLegendCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		sim.ShowDemoLegend(cmd.X, cmd.Y, cmd.Title)
+	})
+}
+
+func (rt *CmdRunner) executeCountDown(cc *CommandContext, cmd *CountDownCmd) {
+	title := "%v"
+	if cmd.Text != nil {
+		title = *cmd.Text
+	}
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		sim.CountDown(time.Duration(cmd.Seconds)*time.Second, title)
+	})
+}
+
+func (rt *CmdRunner) executeRadio(cc *CommandContext, radio *RadioCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range radio.Nodes {
+			node, dnode := rt.getNode(sim, sel)
+			if node == nil {
+				cc.errorf("node %d not found", sel.Id)
+				continue
+			}
+
+			if radio.On != nil {
+				sim.SetNodeFailed(node.Id, false)
+			} else if radio.Off != nil {
+				sim.SetNodeFailed(node.Id, true)
+			} else if radio.FailTime != nil {
+				if radio.FailTime.FailInterval > 0 && radio.FailTime.FailDuration > 0 {
+					dnode.SetFailTime(dispatcher.FailTime{
+						FailDuration: uint64(radio.FailTime.FailDuration * 1000000),
+						FailInterval: uint64(radio.FailTime.FailInterval * 1000000),
+					})
+				} else {
+					dnode.SetFailTime(dispatcher.NonFailTime)
+				}
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executePause(cc *CommandContext, cmd *PauseCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			node, _ := rt.getNode(sim, sel)
+			if node == nil {
+				cc.errorf("node %d not found", sel.Id)
+				return
+			}
+
+			if err := sim.PauseNode(node.Id, cmd.SigStop != nil); err != nil {
+				cc.error(err)
+				return
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeResume(cc *CommandContext, cmd *ResumeCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			node, _ := rt.getNode(sim, sel)
+			if node == nil {
+				cc.errorf("node %d not found", sel.Id)
+				return
+			}
+
+			if err := sim.ResumeNode(node.Id); err != nil {
+				cc.error(err)
+				return
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeScript(cc *CommandContext, cmd *ScriptCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.File != nil {
+			if _, err := simulation.ReadNodeScript(*cmd.File); err != nil {
+				cc.error(err)
+				return
+			}
+			sim.SetInitScript(*cmd.File)
+		} else if cmd.Off != nil {
+			sim.SetInitScript("")
+		} else if cmd.Var != nil {
+			cc.error(sim.SetScriptVar(cmd.Var.Name, cmd.Var.Value))
+		} else {
+			file := sim.InitScript()
+			if file == "" {
+				file = "off"
+			}
+			cc.outputf("script=%s\n", file)
+
+			vars := sim.ScriptVars()
+			names := make([]string, 0, len(vars))
+			for name := range vars {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				cc.outputf("var %s=%s\n", name, vars[name])
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeWatch(cc *CommandContext, cmd *WatchCmd) {
+	radioLevel := dispatcher.RadioWatchOff
+	if cmd.Radio != nil {
+		var err error
+		if radioLevel, err = dispatcher.ParseRadioWatchLevel(cmd.Radio.Level); err != nil {
+			cc.error(err)
+			return
+		}
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if len(cmd.Nodes) == 0 {
+			if cmd.Radio != nil {
+				cc.errorf("node ID is required")
+				return
+			}
+
+			for _, id := range d.WatchingNodes() {
+				events := "off"
+				if d.IsWatching(id) {
+					events = "on"
+				}
+				cc.outputf("node=%-4d events=%-3s radio=%s\n", id, events, d.GetRadioWatchLevel(id))
+			}
+			return
+		}
+
+		for _, sel := range cmd.Nodes {
+			node, _ := rt.getNode(sim, sel)
+			if node == nil {
+				cc.errorf("node %d not found", sel.Id)
+				continue
+			}
+
+			if cmd.Radio != nil {
+				d.SetRadioWatchLevel(node.Id, radioLevel)
+			} else {
+				d.WatchNode(node.Id)
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeUnwatch(cc *CommandContext, cmd *UnwatchCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		for _, sel := range cmd.Nodes {
+			node, _ := rt.getNode(sim, sel)
+			if node == nil {
+				cc.errorf("node %d not found", sel.Id)
+				continue
+			}
+
+			d.UnwatchNode(node.Id)
+			d.SetRadioWatchLevel(node.Id, dispatcher.RadioWatchOff)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeDrift(cc *CommandContext, cmd *DriftCmd) {
+	if cmd.Analysis != nil {
+		rt.executeDriftAnalysis(cc, cmd.Analysis)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Ppm == nil {
+			sim.VisitNodesInOrder(func(node *simulation.Node) {
+				cc.outputf("node=%-4d drift=%gppm\n", node.Id, d.GetNode(node.Id).ClockDrift())
+			})
+			return
+		}
+
+		if *cmd.Ppm < -dispatcher.MaxClockDriftPpm || *cmd.Ppm > dispatcher.MaxClockDriftPpm {
+			cc.errorf("drift out of range: %gppm", *cmd.Ppm)
+			return
+		}
+
+		nodeids, err := selectNodes(sim, cmd.Nodes)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+
+		for _, id := range nodeids {
+			d.SetNodeClockDrift(id, *cmd.Ppm)
+		}
+	})
+}
+
+const (
+	// defaultDriftAccuracyPpm is the clock accuracy each node is assumed to claim for widening CSL receive windows.
+	defaultDriftAccuracyPpm = 20.0
+)
+
+func (rt *CmdRunner) executeDriftAnalysis(cc *CommandContext, cmd *DriftAnalysisFlag) {
+	accuracy := defaultDriftAccuracyPpm
+	if cmd.Accuracy != nil {
+		if *cmd.Accuracy <= 0 {
+			cc.errorf("invalid accuracy: %gppm", *cmd.Accuracy)
+			return
+		}
+		accuracy = *cmd.Accuracy
+	}
+
+	var analyses []dispatcher.DriftAnalysis
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		analyses = sim.Dispatcher().AnalyzeClockDrift()
+	})
+
+	for _, a := range analyses {
+		if a.Parent == InvalidNodeId {
+			cc.outputf("node=%-4d drift=%gppm parent=-    detach=%d reparent=%d\n", a.NodeId, a.Ppm, a.Detaches,
+				a.ParentChanges)
+			continue
+		}
+
+		csl := "ok"
+		if math.Abs(a.RelativePpm) > 2*accuracy {
+			csl = "risk"
+		}
+		cc.outputf("node=%-4d drift=%gppm parent=%-4d rel=%gppm detach=%d reparent=%d csl=%s\n", a.NodeId, a.Ppm,
+			a.Parent, a.RelativePpm, a.Detaches, a.ParentChanges, csl)
+	}
+}
+
+func (rt *CmdRunner) executeCoverage(cc *CommandContext, cmd *CoverageCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if sim.CoverageDir() == "" {
+			cc.errorf("coverage is disabled, start otns with -coverage <dir>")
+			return
+		}
+
+		if cmd.Merge == nil {
+			cc.outputf("dir=%s\n", sim.CoverageDir())
+			return
+		}
+
+		output := ""
+		if cmd.Merge.Output != nil {
+			output = *cmd.Merge.Output
+		}
+		result, err := sim.MergeCoverage(output)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+
+		if result.Output != "" {
+			cc.outputf("profiles=%d output=%s\n", result.Profiles, result.Output)
+		}
+		for _, dir := range result.GcovDirs {
+			cc.outputf("gcov=%s\n", dir)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeDutyCycle(cc *CommandContext, cmd *DutyCycleCmd) {
+	var limit *dispatcher.DutyCycleLimit
+	if cmd.Percent != nil {
+		if *cmd.Percent <= 0 || *cmd.Percent > 100 {
+			cc.errorf("duty cycle out of range: %g%%", *cmd.Percent)
+			return
+		}
+
+		limit = &dispatcher.DutyCycleLimit{Ratio: *cmd.Percent / 100, Window: dispatcher.DefaultDutyCycleWindow}
+		if cmd.Window != nil {
+			if *cmd.Window <= 0 {
+				cc.errorf("invalid duty-cycle window: %gs", *cmd.Window)
+				return
+			}
+			limit.Window = uint64(*cmd.Window * 1000000)
+		}
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Off == nil && cmd.Percent == nil {
+			var stats []*dispatcher.DutyCycleStat
+			sim.VisitNodesInOrder(func(node *simulation.Node) {
+				if stat := d.GetNodeDutyCycle(node.Id); stat != nil {
+					stats = append(stats, stat)
+				}
+			})
+
+			if cc.isJsonOutput(cmd.Json) {
+				cc.outputJson(stats)
+				return
+			}
+			for _, stat := range stats {
+				cc.outputf("node=%-4d limit=%g%%/%gs airtime=%gms util=%.1f%% frames=%d blocked=%d\n", stat.NodeId,
+					stat.Ratio*100, float64(stat.Window)/1000000, float64(stat.Airtime)/1000, stat.Utilization*100,
+					stat.TxFrames, stat.BlockedFrames)
+			}
+			return
+		}
+
+		nodeids, err := selectNodes(sim, cmd.Nodes)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+
+		for _, id := range nodeids {
+			d.SetNodeDutyCycle(id, limit)
+		}
+	})
+}
+
+func (rt *CmdRunner) executePower(cc *CommandContext, cmd *PowerCmd) {
+	var ps *dispatcher.PowerSource
+	if src := cmd.Source; src != nil {
+		ps = &dispatcher.PowerSource{Type: dispatcher.PowerMains}
+		if b := src.Battery; b != nil {
+			if b.CapacityMwh <= 0 {
+				cc.errorf("invalid battery capacity: %gmWh", b.CapacityMwh)
+				return
+			}
+			ps = &dispatcher.PowerSource{Type: dispatcher.PowerBattery, CapacityMwh: b.CapacityMwh}
+		} else if h := src.Harvest; h != nil {
+			if h.CapacityMwh <= 0 || h.PowerMw < 0 {
+				cc.errorf("invalid harvesting source: %gmWh %gmW", h.CapacityMwh, h.PowerMw)
+				return
+			}
+			ps = &dispatcher.PowerSource{Type: dispatcher.PowerHarvest, CapacityMwh: h.CapacityMwh, HarvestMw: h.PowerMw}
+			if h.Period != nil {
+				if *h.Period <= 0 || h.Duty < 0 || h.Duty > 100 {
+					cc.errorf("invalid harvesting period: %gs duty %g%%", *h.Period, h.Duty)
+					return
+				}
+				ps.HarvestPeriod = uint64(*h.Period * 1000000)
+				ps.HarvestDuty = h.Duty / 100
+			}
+		}
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if ps == nil {
+			type nodePowerSource struct {
+				NodeId NodeId `json:"node"`
+				dispatcher.PowerSource
+			}
+			sources := []nodePowerSource{}
+			sim.VisitNodesInOrder(func(node *simulation.Node) {
+				sources = append(sources, nodePowerSource{node.Id, d.GetNodePowerSource(node.Id)})
+			})
+
+			if cc.isJsonOutput(cmd.Json) {
+				cc.outputJson(sources)
+				return
+			}
+			for i := range sources {
+				cc.outputf("node=%-4d %s\n", sources[i].NodeId, sources[i].PowerSource.String())
+			}
+			return
+		}
+
+		nodeids, err := selectNodes(sim, cmd.Nodes)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+
+		for _, id := range nodeids {
+			d.SetNodePowerSource(id, *ps)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeEnergy(cc *CommandContext, cmd *EnergyCmd) {
+	if m := cmd.Model; m != nil {
+		for _, val := range []*float64{m.Tx, m.Rx, m.Sleep} {
+			if val != nil && *val < 0 {
+				cc.errorf("invalid power: %gmW", *val)
+				return
+			}
+		}
+	}
+
+	var report *dispatcher.EnergyReport
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Reset != nil {
+			d.ResetEnergy()
+			return
+		}
+
+		if m := cmd.Model; m != nil {
+			model := d.GetEnergyModel()
+			if m.Tx == nil && m.Rx == nil && m.Sleep == nil {
+				cc.outputf("tx=%gmW rx=%gmW sleep=%gmW\n", model.TxMw, model.RxMw, model.SleepMw)
+				return
+			}
+
+			if m.Tx != nil {
+				model.TxMw = *m.Tx
+			}
+			if m.Rx != nil {
+				model.RxMw = *m.Rx
+			}
+			if m.Sleep != nil {
+				model.SleepMw = *m.Sleep
+			}
+			d.SetEnergyModel(model)
+			return
+		}
+
+		report = d.GetEnergyReport()
+	})
+	if report == nil {
+		return
+	}
+
+	if cc.isJsonOutput(cmd.Json) {
+		cc.outputJson(report)
+		return
+	}
+
+	formatLifetime := func(lifetime *float64) string {
+		if lifetime == nil {
+			return "inf"
+		}
+		return fmt.Sprintf("%.1fd", *lifetime/86400)
+	}
+	for _, stat := range report.Nodes {
+		battery := ""
+		if stat.Battery != nil {
+			battery = fmt.Sprintf(" battery=%.1f%% lifetime=%s", *stat.Battery*100, formatLifetime(stat.Lifetime))
+		}
+		cc.outputf("node=%-4d source=%-7s tx=%.3fs rx=%.3fs sleep=%.3fs consumed=%.1fmJ avg=%.3fmW%s\n", stat.NodeId,
+			stat.Source, float64(stat.TxUs)/1000000, float64(stat.RxUs)/1000000, float64(stat.SleepUs)/1000000,
+			stat.ConsumedMj, stat.AvgPowerMw, battery)
+	}
+	for _, class := range report.Classes {
+		lifetime := ""
+		if class.Source != dispatcher.PowerMains {
+			lifetime = " min_lifetime=" + formatLifetime(class.MinLifetime)
+		}
+		cc.outputf("class=%-7s nodes=%-4d consumed=%.1fmJ avg=%.3fmW%s\n", class.Source, class.Nodes,
+			class.ConsumedMj, class.AvgPowerMw, lifetime)
+	}
+}
+
+// rfSimParam is a per-node radio parameter of the rfsim command.
+type rfSimParam struct {
+	name string
+	get  func(d *dispatcher.Dispatcher, node *dispatcher.Node) float64
+	set  func(d *dispatcher.Dispatcher, id NodeId, val float64) error
+}
+
+var rfSimParams = []rfSimParam{
+	{
+		name: "ParamClockDrift",
+		get: func(d *dispatcher.Dispatcher, node *dispatcher.Node) float64 {
+			return node.ClockDrift()
+		},
+		set: func(d *dispatcher.Dispatcher, id NodeId, val float64) error {
+			if val < -dispatcher.MaxClockDriftPpm || val > dispatcher.MaxClockDriftPpm {
+				return errors.Errorf("drift out of range: %gppm", val)
+			}
+			d.SetNodeClockDrift(id, val)
+			return nil
+		},
+	},
+	{
+		name: "ParamTxPower",
+		get: func(d *dispatcher.Dispatcher, node *dispatcher.Node) float64 {
+			return d.NodeTxPowerDbm(node)
+		},
+		set: rfSimDbmSetter(func(d *dispatcher.Dispatcher, id NodeId, val float64) {
+			d.SetNodeTxPower(id, val)
+		}),
+	},
+	{
+		name: "ParamRxSensitivity",
+		get: func(d *dispatcher.Dispatcher, node *dispatcher.Node) float64 {
+			return node.RxSensitivity()
+		},
+		set: rfSimDbmSetter(func(d *dispatcher.Dispatcher, id NodeId, val float64) {
+			d.SetNodeRxSensitivity(id, val)
+		}),
+	},
+	{
+		name: "ParamCcaThreshold",
+		get: func(d *dispatcher.Dispatcher, node *dispatcher.Node) float64 {
+			return node.CcaThreshold()
+		},
+		set: rfSimDbmSetter(func(d *dispatcher.Dispatcher, id NodeId, val float64) {
+			d.SetNodeCcaThreshold(id, val)
+		}),
+	},
+}
+
+// rfSimDbmSetter returns the setter of an rfsim parameter in dBm, which checks the range of the value.
+func rfSimDbmSetter(set func(d *dispatcher.Dispatcher, id NodeId, val float64)) func(*dispatcher.Dispatcher, NodeId,
+	float64) error {
+	return func(d *dispatcher.Dispatcher, id NodeId, val float64) error {
+		if val < dispatcher.MinNodeRadioDbm || val > dispatcher.MaxNodeRadioDbm {
+			return errors.Errorf("value out of range: %gdBm", val)
+		}
+		set(d, id, val)
+		return nil
+	}
+}
+
+// resolveNodeTargets returns the IDs of the nodes selected by the targets in order, and the IDs given explicitly which
+// were not found. Ranges only select the existing nodes within them.
+func resolveNodeTargets(sim *simulation.Simulation, targets []NodeTarget) (nodeids []NodeId, missing []NodeId) {
+	selected := map[NodeId]bool{}
+	for _, target := range targets {
+		if target.Range != nil && target.Range.To == nil {
+			if sim.Nodes()[target.Range.From] == nil {
+				missing = append(missing, target.Range.From)
+			}
+			selected[target.Range.From] = true
+			continue
+		}
+
+		sim.VisitNodesInOrder(func(node *simulation.Node) {
+			if target.Range != nil {
+				selected[node.Id] = selected[node.Id] || (node.Id >= target.Range.From && node.Id <= *target.Range.To)
+			} else {
+				selected[node.Id] = selected[node.Id] || *target.Group == "all" || *target.Group == node.NodeType()+"s"
+			}
+		})
+	}
+
+	sim.VisitNodesInOrder(func(node *simulation.Node) {
+		if selected[node.Id] {
+			nodeids = append(nodeids, node.Id)
+		}
+	})
+	return
+}
+
+func (rt *CmdRunner) executeRfSim(cc *CommandContext, cmd *RfSimCmd) {
+	if cmd.Raw != nil {
+		rt.executeRfSimRaw(cc, cmd.Raw)
+		return
+	}
+
+	params := rfSimParams
+	if cmd.Param != nil {
+		params = nil
+		for _, p := range rfSimParams {
+			if p.name == *cmd.Param {
+				params = append(params, p)
+			}
+		}
+		if len(params) == 0 {
+			cc.errorf("unknown rfsim parameter: %s", *cmd.Param)
+			return
+		}
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		nodeids, missing := resolveNodeTargets(sim, cmd.Targets)
+		if len(nodeids) == 0 {
+			cc.errorf("node not found")
+			return
+		}
+
+		if cmd.Val == nil {
+			for _, id := range nodeids {
+				cc.outputf("node=%-4d", id)
+				for _, p := range params {
+					cc.outputf(" %s=%g", p.name, p.get(d, d.GetNode(id)))
+				}
+				cc.outputf("\n")
+			}
+			return
+		}
+
+		failed := len(missing)
+		for _, id := range missing {
+			cc.outputf("node=%-4d error: node not found\n", id)
+		}
+		for _, id := range nodeids {
+			if err := params[0].set(d, id, *cmd.Val); err != nil {
+				cc.outputf("node=%-4d error: %v\n", id, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			cc.errorf("%s failed on %d of %d nodes", params[0].name, failed, len(nodeids)+len(missing))
+		}
+	})
+}
+
+func (rt *CmdRunner) executeRfSimRaw(cc *CommandContext, cmd *RfSimRawFlag) {
+	data, err := hex.DecodeString(strings.TrimPrefix(cmd.Data, "0x"))
+	if err != nil {
+		cc.errorf("invalid hex data: %v", err)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		responses, err := sim.Dispatcher().SendRfSimRaw(cmd.Node.Id, data)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		for _, resp := range responses {
+			cc.outputf("%s\n", hex.EncodeToString(resp))
+		}
+	})
+}
+
+func (rt *CmdRunner) executePcap(cc *CommandContext, cmd *PcapCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.All != nil {
+			d.SetPcapNodes(nil)
+		} else if len(cmd.Nodes) > 0 {
+			nodeids, err := expandNodeRanges(cmd.Nodes)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			d.SetPcapNodes(nodeids)
+			return
+		}
+
+		if nodeids := d.GetPcapNodes(); nodeids != nil {
+			cc.outputf("nodes=%s\n", joinNodeIds(nodeids))
+		} else {
+			cc.outputf("nodes=all\n")
+		}
+	})
+}
+
+func (rt *CmdRunner) executeMoveNode(cc *CommandContext, cmd *Move) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if len(cmd.Deltas) > 0 {
+			var dx, dy int
+			for _, delta := range cmd.Deltas {
+				if delta.Axis == "dx" {
+					dx += delta.Delta
+				} else {
+					dy += delta.Delta
+				}
+			}
+
+			if err := sim.MoveNodeBy(cmd.Target.Id, dx, dy); err != nil {
+				cc.error(err)
+			}
+			return
+		}
+
+		x, y := sim.SnapToGrid(cmd.X, cmd.Y)
+		if cmd.Geo != nil {
+			origin := sim.GeoOrigin()
+			if origin == nil {
+				cc.errorf("geographic mode is disabled")
+				return
+			}
+			x, y = origin.FromGeo(cmd.Geo.Lat, cmd.Geo.Lon)
+		}
+
+		sim.MoveNodeTo(cmd.Target.Id, x, y)
+	})
+}
+
+func (rt *CmdRunner) executeAlign(cc *CommandContext, cmd *AlignCmd) {
+	spacing := 0
+	if cmd.Spacing != nil {
+		if *cmd.Spacing <= 0 {
+			cc.errorf("invalid spacing: %d", *cmd.Spacing)
+			return
+		}
+		spacing = *cmd.Spacing
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var nodeids []NodeId
+		for _, sel := range cmd.Nodes {
+			nodeids = append(nodeids, sel.Id)
+		}
+
+		if err := sim.AlignNodes(nodeids, cmd.Direction == "horizontal", spacing); err != nil {
+			cc.error(err)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeGrid(cc *CommandContext, cmd *GridCmd) {
+	if cmd.Snap != nil && cmd.Snap.Size != nil && *cmd.Snap.Size <= 0 {
+		cc.errorf("invalid grid size: %d", *cmd.Snap.Size)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Snap == nil {
+			if sim.GridSnap() == 0 {
+				cc.outputf("snap=off\n")
+			} else {
+				cc.outputf("snap=%d\n", sim.GridSnap())
+			}
+			return
+		}
+
+		if cmd.Snap.Off != nil {
+			sim.SetGridSnap(0)
+		} else {
+			sim.SetGridSnap(*cmd.Snap.Size)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeLsNodes(cc *CommandContext, cmd *NodesCmd) {
+	if cmd.Exec != nil {
+		rt.executeNodesExec(cc, cmd.Exec)
+		return
+	}
+
+	var records []outputRecord
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		sim.VisitNodesInOrder(func(node *simulation.Node) {
+			dnode := sim.Dispatcher().GetNode(node.Id)
+			r := outputRecord{
+				{Key: "id", Text: strconv.Itoa(node.Id), Val: node.Id},
+				{Key: "extaddr", Text: fmt.Sprintf("%016x", dnode.ExtAddr), Val: fmt.Sprintf("%016x", dnode.ExtAddr)},
+				{Key: "rloc16", Text: fmt.Sprintf("%04x", dnode.Rloc16), Val: fmt.Sprintf("%04x", dnode.Rloc16)},
+				{Key: "x", Text: strconv.Itoa(dnode.X), Val: dnode.X},
+				{Key: "y", Text: strconv.Itoa(dnode.Y), Val: dnode.Y},
+				{Key: "state", Text: dnode.Role.String(), Val: dnode.Role.String()},
+				{Key: "failed", Text: strconv.FormatBool(dnode.IsFailed()), Val: dnode.IsFailed()},
+				{Key: "paused", Text: strconv.FormatBool(dnode.IsPaused()), Val: dnode.IsPaused()},
+			}
+			if pos, ok := sim.NodeGeoPosition(node.Id); ok {
+				r = append(r,
+					outputField{Key: "lat", Text: fmt.Sprintf("%.7f", pos.Lat), Val: pos.Lat},
+					outputField{Key: "lon", Text: fmt.Sprintf("%.7f", pos.Lon), Val: pos.Lon},
+					outputField{Key: "alt", Text: fmt.Sprintf("%g", pos.Alt), Val: pos.Alt},
+				)
+			}
+			records = append(records, r)
+		})
+	})
+
+	cc.outputRecords(records, &cmd.Output, "\t")
+}
+
+func (rt *CmdRunner) executeNodesExec(cc *CommandContext, cmd *NodesExecFlag) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var ids []NodeId
+		if len(cmd.Nodes) == 0 {
+			sim.VisitNodesInOrder(func(node *simulation.Node) {
+				ids = append(ids, node.Id)
+			})
+		}
+		for _, sel := range cmd.Nodes {
+			ids = append(ids, sel.Id)
+		}
+
+		defer func() {
+			err := recover()
+			if err != nil {
+				cc.errorf("%+v", err)
+			}
+		}()
+
+		for _, result := range sim.ExecCommand(ids, cmd.Command, simulation.DefaultCommandTimeout) {
+			if result.Error != "" {
+				cc.outputf("%-5d | ERROR: %s\n", result.Node, result.Error)
+				continue
+			}
+			if len(result.Output) == 0 {
+				cc.outputf("%-5d |\n", result.Node)
+			}
+			for _, line := range result.Output {
+				cc.outputf("%-5d | %s\n", result.Node, line)
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeGeo(cc *CommandContext, cmd *GeoCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Off != nil {
+			sim.SetGeoOrigin(nil)
+			return
+		}
+
+		if cmd.Origin != nil {
+			alt, scale := 0.0, geo.DefaultMetersPerUnit
+			if cmd.Origin.Alt != nil {
+				alt = *cmd.Origin.Alt
+			}
+			if cmd.Origin.Scale != nil {
+				scale = *cmd.Origin.Scale
+			}
+
+			origin, err := geo.NewOrigin(cmd.Origin.Lat, cmd.Origin.Lon, alt, scale)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			sim.SetGeoOrigin(origin)
+			return
+		}
+
+		origin := sim.GeoOrigin()
+		if origin == nil {
+			cc.outputf("off\n")
+			return
+		}
+		cc.outputf("lat=%.7f lon=%.7f alt=%g scale=%gm\n", origin.Lat, origin.Lon, origin.Alt, origin.MetersPerUnit)
+	})
+}
+
+func (rt *CmdRunner) executeLsPartitions(cc *CommandContext, cmd *PartitionsCmd) {
 	pars := map[uint32][]NodeId{}
 
-	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		for nodeid, dnode := range sim.Dispatcher().Nodes() {
-			parid := dnode.PartitionId
-			pars[parid] = append(pars[parid], nodeid)
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for nodeid, dnode := range sim.Dispatcher().Nodes() {
+			parid := dnode.PartitionId
+			pars[parid] = append(pars[parid], nodeid)
+		}
+	})
+
+	var parids []uint32
+	for parid := range pars {
+		parids = append(parids, parid)
+	}
+	sort.Slice(parids, func(i, j int) bool {
+		return parids[i] < parids[j]
+	})
+
+	var records []outputRecord
+	for _, parid := range parids {
+		nodeids := pars[parid]
+		sort.Ints(nodeids)
+		records = append(records, outputRecord{
+			{Key: "partition", Text: fmt.Sprintf("%08x", parid), Val: fmt.Sprintf("%08x", parid)},
+			{Key: "nodes", Text: joinNodeIds(nodeids), Val: nodeids},
+		})
+	}
+
+	cc.outputRecords(records, &cmd.Output, "\t")
+}
+
+func (rt *CmdRunner) executeCollectPings(cc *CommandContext, pings *PingsCmd) {
+	allPings := make(map[NodeId][]*dispatcher.PingResult)
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		for nodeid, node := range d.Nodes() {
+			pings := node.CollectPings()
+			if len(pings) > 0 {
+				allPings[nodeid] = pings
+			}
+		}
+	})
+
+	var nodeids []NodeId
+	for nodeid := range allPings {
+		nodeids = append(nodeids, nodeid)
+	}
+	sort.Ints(nodeids)
+
+	var records []outputRecord
+	for _, nodeid := range nodeids {
+		for _, ping := range allPings[nodeid] {
+			delay := float64(ping.Delay) / 1000
+			records = append(records, outputRecord{
+				{Key: "node", Text: strconv.Itoa(nodeid), Val: nodeid, Width: 4},
+				{Key: "dst", Text: ping.Dst, Val: ping.Dst, Width: 40},
+				{Key: "datasize", Text: strconv.Itoa(ping.DataSize), Val: ping.DataSize, Width: 3},
+				{Key: "delay", Text: fmt.Sprintf("%.3fms", delay), Val: delay},
+			})
+		}
+	}
+
+	cc.outputRecords(records, &pings.Output, " ")
+}
+
+func (rt *CmdRunner) executeCollectJoins(cc *CommandContext, joins *JoinsCmd) {
+	if joins.Stats != nil {
+		rt.executeJoinsStats(cc, joins.Stats)
+		return
+	}
+
+	allJoins := make(map[NodeId][]*dispatcher.JoinResult)
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		for nodeid, node := range d.Nodes() {
+			joins := node.CollectJoins()
+			if len(joins) > 0 {
+				allJoins[nodeid] = joins
+			}
+		}
+	})
+
+	var nodeids []NodeId
+	for nodeid := range allJoins {
+		nodeids = append(nodeids, nodeid)
+	}
+	sort.Ints(nodeids)
+
+	var records []outputRecord
+	for _, nodeid := range nodeids {
+		for _, join := range allJoins[nodeid] {
+			joinTime, sessionTime := float64(join.JoinDuration)/1000000, float64(join.SessionDuration)/1000000
+			records = append(records, outputRecord{
+				{Key: "node", Text: strconv.Itoa(nodeid), Val: nodeid, Width: 4},
+				{Key: "join", Text: fmt.Sprintf("%.3fs", joinTime), Val: joinTime},
+				{Key: "session", Text: fmt.Sprintf("%.3fs", sessionTime), Val: sessionTime},
+			})
+		}
+	}
+
+	cc.outputRecords(records, &OutputFlags{Json: joins.Json}, " ")
+}
+
+func (rt *CmdRunner) executeJoinsStats(cc *CommandContext, cmd *JoinsStatsFlag) {
+	var stats dispatcher.JoinStats
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Reset != nil {
+			d.ResetJoinStats()
+		} else {
+			stats = d.GetJoinStats()
+		}
+	})
+
+	if cmd.Reset != nil {
+		return
+	}
+
+	if cc.isJsonOutput(cmd.Json) {
+		cc.outputJson(stats)
+		return
+	}
+
+	cc.outputf("sessions=%d joined=%d\n", stats.Sessions, stats.Joined)
+	phases := []struct {
+		name  string
+		stats dispatcher.DurationStats
+	}{
+		{"discover", stats.Discover},
+		{"dtls", stats.Dtls},
+		{"netdata", stats.NetworkData},
+		{"attach", stats.Attach},
+		{"join", stats.Join},
+	}
+	for _, phase := range phases {
+		ps := phase.stats
+		cc.outputf("%-8s count=%-4d p50=%.3fs p90=%.3fs p99=%.3fs max=%.3fs\n", phase.name, ps.Count,
+			float64(ps.P50)/1000000, float64(ps.P90)/1000000, float64(ps.P99)/1000000, float64(ps.Max)/1000000)
+	}
+}
+
+func (rt *CmdRunner) executeUpgrade(cc *CommandContext, cmd *UpgradeCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		node, _ := rt.getNode(sim, cmd.Node)
+		if node == nil {
+			cc.errorf("node %d not found", cmd.Node.Id)
+			return
+		}
+
+		if _, err := sim.UpgradeNode(node.Id, cmd.Executable); err != nil {
+			cc.error(err)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeUpgrades(cc *CommandContext, cmd *UpgradesCmd) {
+	var results []*dispatcher.UpgradeResult
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		results = sim.Dispatcher().UpgradeResults()
+	})
+
+	for _, ur := range results {
+		rejoin := "pending"
+		if ur.RejoinTime != 0 {
+			rejoin = fmt.Sprintf("%.3fs", float64(ur.RejoinDuration())/1000000)
+		}
+		cc.outputf("node=%-4d time=%.3fs rejoin=%s exe=%s\n", ur.NodeId, float64(ur.StartTime)/1000000, rejoin, ur.Executable)
+	}
+}
+
+func (rt *CmdRunner) executeSrpStats(cc *CommandContext, cmd *SrpCmd) {
+	var stats *simulation.SrpStats
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		stats = sim.CollectSrpStats()
+	})
+
+	cc.outputf("servers=%d hosts=%d services=%d deleted=%d\n", stats.Servers, stats.ServerHosts, stats.ServerServices,
+		stats.DeletedServices)
+	cc.outputf("clients=%d registered=%d renewing=%d pending=%d conflicts=%d\n", stats.Clients, stats.Registered,
+		stats.Renewing, stats.Pending, stats.Conflicts)
+}
+
+const (
+	defaultElectionTimeout = time.Second * 300
+	defaultElectionSettle  = time.Second * 60
+)
+
+// goFor runs the simulation for the duration and waits for it.
+func (rt *CmdRunner) goFor(duration time.Duration) {
+	var done <-chan struct{}
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		done = sim.Go(duration)
+	})
+	<-done
+}
+
+const (
+	defaultWaitTimeout = time.Second * 60
+	waitStep           = time.Millisecond * 100
+)
+
+// executeWait runs the simulation until the condition holds, or the timeout.
+func (rt *CmdRunner) executeWait(cc *CommandContext, cmd *WaitCmd) {
+	timeout := defaultWaitTimeout
+	if cmd.Timeout != nil {
+		timeout = time.Duration(*cmd.Timeout * float64(time.Second))
+	}
+
+	var state string
+	check := func(sim *simulation.Simulation) bool {
+		d := sim.Dispatcher()
+		if cmd.Node != nil {
+			node := d.GetNode(cmd.Node.Node.Id)
+			if node == nil {
+				cc.errorf("node %d not found", cmd.Node.Node.Id)
+				return false
+			}
+			state = fmt.Sprintf("node %d is %s", node.Id, node.Role)
+			return node.Role.String() == cmd.Node.State
+		}
+
+		partitions, detached := countWaitPartitions(d)
+		state = fmt.Sprintf("%d partitions, %d nodes detached", partitions, detached)
+		return partitions == *cmd.Partitions && detached == 0
+	}
+
+	var start, now uint64
+	held := false
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		start = sim.Dispatcher().CurTime
+		now = start
+		held = check(sim)
+	})
+
+	deadline := start + uint64(timeout/time.Microsecond)
+	for !held && now < deadline && cc.Err() == nil && rt.ctx.Err() == nil {
+		step := waitStep
+		if remaining := time.Duration(deadline-now) * time.Microsecond; remaining < step {
+			step = remaining
+		}
+		rt.goFor(step)
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			now = sim.Dispatcher().CurTime
+			held = check(sim)
+		})
+	}
+
+	if cc.Err() != nil {
+		return
+	}
+	if !held {
+		cc.errorf("timeout after %.3fs: %s", float64(now-start)/1000000, state)
+		return
+	}
+	cc.outputf("waited=%.3fs\n", float64(now-start)/1000000)
+}
+
+func (rt *CmdRunner) executeLinkMetrics(cc *CommandContext, cmd *LinkMetricsCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Start != nil {
+			interval := uint64(simulation.DefaultLinkMetricsInterval)
+			if cmd.Start.Interval != nil {
+				if *cmd.Start.Interval <= 0 {
+					cc.errorf("invalid interval: %gs", *cmd.Start.Interval)
+					return
+				}
+				interval = uint64(*cmd.Start.Interval * 1000000)
+			}
+			cc.error(sim.StartLinkMetrics(cmd.Start.Src.Id, cmd.Start.Dst.Id, interval))
+			return
+		} else if cmd.Stop != nil {
+			if cmd.Stop.Src != nil {
+				cc.error(sim.StopLinkMetrics(cmd.Stop.Src.Id, cmd.Stop.Dst.Id))
+			} else {
+				sim.StopAllLinkMetrics()
+			}
+			return
+		} else if cmd.Reset != nil {
+			sim.CollectLinkMetrics()
+			d.ResetLinkMetrics()
+			return
+		}
+
+		sim.CollectLinkMetrics()
+		if cmd.Src != nil {
+			series := d.GetLinkMetricsSeries(cmd.Src.Id, cmd.Dst.Id)
+			if cc.isJsonOutput(cmd.Json) {
+				if series == nil {
+					series = []*dispatcher.LinkMetricsSample{}
+				}
+				cc.outputJson(series)
+				return
+			}
+			for _, sample := range series {
+				cc.outputf("time=%d.%06ds pdus=%d lqi=%d margin=%ddB rssi=%ddBm\n", sample.Time/1000000,
+					sample.Time%1000000, sample.Pdus, sample.Lqi, sample.Margin, sample.Rssi)
+			}
+			return
+		}
+
+		stats := d.GetLinkMetricsStats()
+		if cc.isJsonOutput(cmd.Json) {
+			cc.outputJson(stats)
+			return
+		}
+		probing := map[[2]NodeId]*simulation.LinkMetricsProbe{}
+		for _, probe := range sim.LinkMetricsProbes() {
+			probing[[2]NodeId{probe.Src, probe.Dst}] = probe
+			if len(d.GetLinkMetricsSeries(probe.Src, probe.Dst)) == 0 {
+				stats = append(stats, &dispatcher.LinkMetricsStats{Src: probe.Src, Dst: probe.Dst})
+			}
+		}
+		sort.Slice(stats, func(i, j int) bool {
+			if stats[i].Src != stats[j].Src {
+				return stats[i].Src < stats[j].Src
+			}
+			return stats[i].Dst < stats[j].Dst
+		})
+		for _, s := range stats {
+			interval := "off"
+			if probe := probing[[2]NodeId{s.Src, s.Dst}]; probe != nil {
+				interval = fmt.Sprintf("%gs", float64(probe.Interval)/1000000)
+			}
+			cc.outputf("%d->%d probe=%s samples=%d lqi=%.0f/%d/%d margin=%.1f/%d/%d rssi=%.1f/%d/%d\n", s.Src, s.Dst,
+				interval, s.Samples, s.Lqi.Avg, s.Lqi.Min, s.Lqi.Max, s.Margin.Avg, s.Margin.Min, s.Margin.Max,
+				s.Rssi.Avg, s.Rssi.Min, s.Rssi.Max)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeLinkStats(cc *CommandContext, cmd *LinkStatsCmd) {
+	var stats dispatcher.LinkStats
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Reset != nil {
+			d.ResetLinkStats()
+			return
+		}
+
+		src, _ := rt.getNode(sim, *cmd.Src)
+		dst, _ := rt.getNode(sim, *cmd.Dst)
+		if src == nil || dst == nil {
+			cc.errorf("src or dst node not found")
+			return
+		}
+		stats = d.GetLinkStats(src.Id, dst.Id)
+	})
+
+	if cmd.Reset != nil || cc.Err() != nil {
+		return
+	}
+
+	if cc.isJsonOutput(nil) {
+		cc.outputJson(stats)
+		return
+	}
+
+	cc.outputf("src=%d dst=%d frames=%d delivered=%d lost=%d retried=%d retries=%d\n", stats.Src, stats.Dst,
+		stats.Frames, stats.Delivered, stats.Lost, stats.Retried, stats.Retries)
+	ls := stats.Latency
+	cc.outputf("latency  count=%-4d p50=%.3fms p90=%.3fms p99=%.3fms max=%.3fms\n", ls.Count, float64(ls.P50)/1000,
+		float64(ls.P90)/1000, float64(ls.P99)/1000, float64(ls.Max)/1000)
+	lower := uint64(0)
+	for i, count := range stats.Histogram {
+		var bucket string
+		if i < len(dispatcher.LinkLatencyBuckets) {
+			bucket = fmt.Sprintf("%d-%dms", lower/1000, dispatcher.LinkLatencyBuckets[i]/1000)
+			lower = dispatcher.LinkLatencyBuckets[i]
+		} else {
+			bucket = fmt.Sprintf(">=%dms", lower/1000)
+		}
+		cc.outputf("%-10s %-6d %s\n", bucket, count, strings.Repeat("#", histogramBarLength(count, stats.Delivered)))
+	}
+}
+
+func (rt *CmdRunner) executeMacStats(cc *CommandContext, cmd *MacStatsCmd) {
+	var stats map[NodeId]*dispatcher.MacTxStats
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Reset != nil {
+			sim.Dispatcher().ResetMacTxStats()
+			return
+		}
+		stats = sim.Dispatcher().GetMacTxStats()
+	})
+
+	if cmd.Reset != nil {
+		return
+	}
+
+	if cc.isJsonOutput(cmd.Json) {
+		cc.outputJson(stats)
+		return
+	}
+
+	ids := make([]NodeId, 0, len(stats))
+	for id := range stats {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		s := stats[id]
+		var retries []string
+		for n, count := range s.Retries {
+			if count == 0 {
+				continue
+			}
+			if n == dispatcher.MaxMacTxRetries {
+				retries = append(retries, fmt.Sprintf("%d+:%d", n, count))
+			} else {
+				retries = append(retries, fmt.Sprintf("%d:%d", n, count))
+			}
+		}
+		bs := s.Backoff
+		cc.outputf("node=%-4d frames=%-6d retries=%s\n", id, s.Frames, strings.Join(retries, ","))
+		cc.outputf("          backoff count=%-4d p50=%.3fms p90=%.3fms p99=%.3fms max=%.3fms\n", bs.Count,
+			float64(bs.P50)/1000, float64(bs.P90)/1000, float64(bs.P99)/1000, float64(bs.Max)/1000)
+	}
+}
+
+// histogramBarLength returns the length of the bar of a histogram bucket with the count out of the total.
+func histogramBarLength(count int, total int) int {
+	const maxBarLength = 40
+	if total == 0 {
+		return 0
+	}
+	return (count*maxBarLength + total - 1) / total
+}
+
+// countWaitPartitions returns the number of partitions of the attached nodes, and the number of detached (or
+// disabled) nodes. Failed and paused nodes are not counted.
+func countWaitPartitions(d *dispatcher.Dispatcher) (int, int) {
+	partitions := map[uint32]struct{}{}
+	detached := 0
+	for _, node := range d.Nodes() {
+		if node.IsFailed() || node.IsPaused() {
+			continue
+		}
+		if node.Role < OtDeviceRoleChild || node.PartitionId == 0 {
+			detached++
+			continue
+		}
+		partitions[node.PartitionId] = struct{}{}
+	}
+	return len(partitions), detached
+}
+
+func (rt *CmdRunner) executeElection(cc *CommandContext, cmd *ElectionCmd) {
+	count, timeout, settle := 1, defaultElectionTimeout, defaultElectionSettle
+	if cmd.Count != nil {
+		count = cmd.Count.Val
+	}
+	if cmd.Timeout != nil {
+		timeout = time.Duration(cmd.Timeout.Val * float64(time.Second))
+	}
+	if cmd.Settle != nil {
+		settle = time.Duration(cmd.Settle.Val * float64(time.Second))
+	}
+
+	var runs []*dispatcher.ElectionRun
+	for i := 1; i <= count && rt.ctx.Err() == nil; i++ {
+		var err error
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			_, err = sim.Dispatcher().StartElectionRun()
+		})
+		if err != nil {
+			cc.error(err)
+			break
+		}
+
+		// run the simulation until a new leader is elected, or the timeout
+		elected := false
+		for waited := time.Duration(0); !elected && waited < timeout && rt.ctx.Err() == nil; waited += time.Second {
+			rt.goFor(time.Second)
+			rt.postAsyncWait(func(sim *simulation.Simulation) {
+				run := sim.Dispatcher().GetElectionRun()
+				elected = run == nil || run.Elected()
+			})
+		}
+
+		rt.goFor(settle)
+		var run *dispatcher.ElectionRun
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			run = sim.Dispatcher().FinishElectionRun()
+		})
+		if run == nil {
+			cc.errorf("election run %d was interrupted", i)
+			break
+		}
+		runs = append(runs, run)
+
+		if run.Elected() {
+			cc.outputf("run=%-3d leader=%-4d new_leader=%-4d downtime=%.3fs partitions=%d\n", i, run.FailedLeader,
+				run.NewLeader, float64(run.Downtime())/1000000, run.Partitions)
+		} else {
+			cc.outputf("run=%-3d leader=%-4d new_leader=none downtime=- partitions=%d\n", i, run.FailedLeader,
+				run.Partitions)
+		}
+
+		// let the recovered leader rejoin before the next run
+		if i < count {
+			rt.goFor(settle)
+		}
+	}
+
+	if len(runs) == 0 {
+		return
+	}
+
+	summary := dispatcher.SummarizeElections(runs)
+	cc.outputf("runs=%d elected=%d downtime_p50=%.3fs downtime_p90=%.3fs downtime_max=%.3fs\n", summary.Runs,
+		summary.Elected, float64(summary.Downtime.P50)/1000000, float64(summary.Downtime.P90)/1000000,
+		float64(summary.Downtime.Max)/1000000)
+	cc.outputf("partitions_min=%d partitions_max=%d partitions_avg=%.2f\n", summary.MinPartitions,
+		summary.MaxPartitions, summary.AvgPartitions)
+}
+
+func (rt *CmdRunner) executeRoles(cc *CommandContext, cmd *RolesCmd) {
+	var changes []dispatcher.RoleChange
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Reset != nil {
+			sim.Dispatcher().ResetRoleChanges()
+		} else {
+			changes = sim.Dispatcher().RoleChanges()
+		}
+	})
+
+	for _, change := range changes {
+		cc.outputf("time=%.3fs node=%-4d old=%-8s new=%s\n", float64(change.Time)/1000000, change.NodeId,
+			change.OldRole, change.NewRole)
+	}
+}
+
+func (rt *CmdRunner) executeTimeline(cc *CommandContext, cmd *TimelineCmd) {
+	var timeline dispatcher.Timeline
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Reset != nil {
+			sim.Dispatcher().ResetTimeline()
+		} else {
+			timeline = sim.Dispatcher().Timeline()
+		}
+	})
+
+	if cmd.Reset != nil {
+		return
+	}
+
+	if cmd.Save != nil {
+		if err := timeline.WriteFile(*cmd.Save); err != nil {
+			cc.error(err)
+		}
+		return
+	}
+
+	var sb strings.Builder
+	simplelogger.PanicIfError(timeline.Write(&sb))
+	cc.outputf("%s", sb.String())
+}
+
+func (rt *CmdRunner) executeNetDiag(cc *CommandContext, cmd *NetDiagCmd) {
+	timeout := time.Second * 5
+	if cmd.Timeout != nil {
+		if *cmd.Timeout <= 0 {
+			cc.errorf("invalid timeout: %gs", *cmd.Timeout)
+			return
+		}
+		timeout = time.Duration(*cmd.Timeout * float64(time.Second))
+	}
+
+	// the replies arrive while the simulation runs
+	var done <-chan struct{}
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if err := sim.StartNetDiagSweep(cmd.Node.Id, cmd.Tlvs); err != nil {
+			cc.error(err)
+			return
+		}
+		done = sim.Go(timeout)
+	})
+	if cc.Err() != nil {
+		return
+	}
+	<-done
+
+	var sweep *simulation.NetDiagSweep
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var err error
+		if sweep, err = sim.FinishNetDiagSweep(cmd.Node.Id); err != nil {
+			cc.error(err)
+		}
+	})
+	if cc.Err() != nil {
+		return
+	}
+
+	if cc.isJsonOutput(cmd.Json) {
+		cc.outputJson(sweep)
+		return
+	}
+	for _, reply := range sweep.Replies {
+		cc.outputf("node=%-4d address=%s\n", reply.Node, reply.Address)
+		for _, line := range reply.Lines {
+			cc.outputf("    %s\n", line)
+		}
+	}
+	if len(sweep.Missing) > 0 {
+		cc.outputf("missing=%s\n", joinNodeIds(sweep.Missing))
+	}
+}
+
+func (rt *CmdRunner) executeNetData(cc *CommandContext, cmd *NetDataCmd) {
+	var views []*simulation.NetworkDataView
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		views = sim.CollectNetworkData()
+	})
+
+	if cmd.Node != nil {
+		var nodeViews []*simulation.NetworkDataView
+		for _, view := range views {
+			if view.Node == cmd.Node.Id {
+				nodeViews = append(nodeViews, view)
+			}
+		}
+		if len(nodeViews) == 0 {
+			cc.errorf("node %d is not an active router", cmd.Node.Id)
+			return
+		}
+		views = nodeViews
+	}
+
+	if cmd.Json != nil {
+		if views == nil {
+			views = []*simulation.NetworkDataView{}
+		}
+		data, err := json.MarshalIndent(views, "", "  ")
+		simplelogger.PanicIfError(err)
+		cc.outputf("%s\n", data)
+		return
+	}
+
+	inconsistent := 0
+	for _, view := range views {
+		if !view.IsConsistent() {
+			inconsistent += 1
+		}
+
+		cc.outputf("node=%-4d role=%-6s partition=%08x version=%d/%d prefixes=%d routes=%d services=%d\n", view.Node,
+			view.Role, view.PartitionId, view.DataVersion, view.StableDataVersion, len(view.Prefixes), len(view.Routes),
+			len(view.Services))
+		if cmd.Node != nil {
+			for _, prefix := range view.Prefixes {
+				cc.outputf("  prefix %s\n", prefix)
+			}
+			for _, route := range view.Routes {
+				cc.outputf("  route %s\n", route)
+			}
+			for _, service := range view.Services {
+				cc.outputf("  service %s\n", service)
+			}
+		}
+		for _, entry := range view.Missing {
+			cc.outputf("  missing %s\n", entry)
+		}
+		for _, entry := range view.Stale {
+			cc.outputf("  stale %s\n", entry)
+		}
+	}
+
+	if cmd.Node == nil {
+		cc.outputf("routers=%d inconsistent=%d\n", len(views), inconsistent)
+	}
+}
+
+func (rt *CmdRunner) executeCounters(cc *CommandContext, cmd *CountersCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		countersTyp := reflect.TypeOf(d.Counters)
+
+		if cmd.Reset != nil {
+			d.ResetCounters()
+		} else if cmd.Snapshot != nil {
+			if cmd.Snapshot.Name == nil && cc.isJsonOutput(cmd.Snapshot.Json) {
+				snapshots := []map[string]interface{}{}
+				for _, s := range d.CounterSnapshots() {
+					snapshots = append(snapshots, map[string]interface{}{"name": s.Name, "time_us": s.Time})
+				}
+				cc.outputJson(snapshots)
+				return
+			}
+			if cmd.Snapshot.Name == nil {
+				for _, s := range d.CounterSnapshots() {
+					cc.outputf("%-20s time=%d.%06ds\n", s.Name, s.Time/1000000, s.Time%1000000)
+				}
+				return
+			}
+			d.TakeCounterSnapshot(*cmd.Snapshot.Name)
+		} else if cmd.Diff != nil {
+			from := d.GetCounterSnapshot(cmd.Diff.From)
+			if from == nil {
+				cc.errorf("snapshot %s not found", cmd.Diff.From)
+				return
+			}
+			to := d.CurrentCounterSnapshot()
+			if cmd.Diff.To != nil {
+				if to = d.GetCounterSnapshot(*cmd.Diff.To); to == nil {
+					cc.errorf("snapshot %s not found", *cmd.Diff.To)
+					return
+				}
+			}
+
+			diff := to.Diff(from)
+			duration := int64(to.Time) - int64(from.Time)
+			if cc.isJsonOutput(cmd.Diff.Json) || cmd.Diff.ToJson != nil {
+				cc.outputJson(map[string]interface{}{"duration_us": duration, "counters": diff})
+				return
+			}
+			cc.outputf("%-40s %.6fs\n", "Duration", float64(duration)/1000000)
+			for i := 0; i < countersTyp.NumField(); i++ {
+				fname := countersTyp.Field(i).Name
+				cc.outputf("%-40s %+d\n", fname, diff[fname])
+			}
+		} else {
+			counters := d.GetCounters()
+			if cc.isJsonOutput(cmd.Json) {
+				cc.outputJson(counters)
+				return
+			}
+			for i := 0; i < countersTyp.NumField(); i++ {
+				fname := countersTyp.Field(i).Name
+				cc.outputf("%-40s %v\n", fname, counters[fname])
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeWeb(cc *CommandContext, webcmd *WebCmd) {
+	if err := web.OpenWeb(rt.progCtx()); err != nil {
+		cc.error(err)
+	}
+}
+
+func (rt *CmdRunner) executePlr(cc *CommandContext, cmd *PlrCmd) {
+	if cmd.Val == nil {
+		// get PLR, after setting the PLR of data or ACK frames
+		var plr, ackPlr float64
+
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			d := sim.Dispatcher()
+			if cmd.Data != nil {
+				d.SetDataPacketLossRatio(*cmd.Data)
+			}
+			if cmd.Ack != nil {
+				d.SetAckPacketLossRatio(*cmd.Ack)
+			}
+			plr = d.GetGlobalMessageDropRatio()
+			ackPlr = d.GetAckPacketLossRatio()
+		})
+
+		if ackPlr < 0 {
+			cc.outputf("%v\n", plr)
+		} else {
+			cc.outputf("data=%v ack=%v\n", plr, ackPlr)
+		}
+	} else {
+		// set PLR
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			sim.Dispatcher().SetGlobalPacketLossRatio(*cmd.Val)
+			*cmd.Val = sim.Dispatcher().GetGlobalMessageDropRatio()
+		})
+		cc.outputf("%v\n", *cmd.Val)
+	}
+}
+
+func (rt *CmdRunner) executeRadioModel(cc *CommandContext, cmd *RadioModelCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		params := sim.Dispatcher().GetRadioModelParams()
+		if cmd.Model == nil {
+			cc.outputf("%s\n", params.Model)
+			return
+		}
+
+		model, err := dispatcher.ParseRadioModel(*cmd.Model)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+
+		params.Model = model
+		sim.Dispatcher().SetRadioModelParams(params)
+	})
+}
+
+func (rt *CmdRunner) executeCapture(cc *CommandContext, cmd *CaptureCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		params := sim.Dispatcher().GetRadioModelParams()
+		capture := params.GetCaptureParams()
+		if cmd.Preset == nil {
+			collisions := "off"
+			if capture.Collisions {
+				collisions = "on"
+			}
+			cc.outputf("model=%s collisions=%s sir=%gdB width=%gdB window=%gus\n", params.Model, collisions,
+				capture.SirDb, capture.SirWidthDb, capture.WindowUs)
+			return
+		}
+
+		preset, err := dispatcher.ParseCapturePreset(*cmd.Preset)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+
+		capture.ApplyPreset(preset)
+		params.SetCaptureParams(capture)
+		sim.Dispatcher().SetRadioModelParams(params)
+	})
+}
+
+// parseChannel parses a channel argument of format `ch<channel>`.
+func parseChannel(s string) (uint8, error) {
+	ch, err := strconv.Atoi(strings.TrimPrefix(s, "ch"))
+	if err != nil || !strings.HasPrefix(s, "ch") || ch < dispatcher.MinChannel || ch > dispatcher.MaxChannel {
+		return 0, errors.Errorf("invalid channel: %s", s)
+	}
+	return uint8(ch), nil
+}
+
+func (rt *CmdRunner) executeRadioParam(cc *CommandContext, cmd *RadioParamCmd) {
+	var channel uint8
+	if cmd.Channel != nil {
+		var err error
+		if channel, err = parseChannel(*cmd.Channel); err != nil {
+			cc.error(err)
+			return
+		}
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		params := sim.Dispatcher().GetRadioModelParams()
+		capture := params.GetCaptureParams()
+		scalarParams := []struct {
+			name        string
+			val         *float64
+			positive    bool
+			nonNegative bool
+		}{
+			{"PathLossExponent", &params.PathLossExponent, true, true},
+			{"TxPowerDbm", &params.TxPowerDbm, false, false},
+			{"MinSnrDb", &params.MinSnrDb, false, false},
+			{"MeterPerUnit", &params.MeterPerUnit, true, true},
+			{"CaptureSirDb", &capture.SirDb, false, false},
+			{"CaptureSirWidthDb", &capture.SirWidthDb, false, true},
+			{"CaptureWindowUs", &capture.WindowUs, false, true},
+		}
+
+		if cmd.Name == nil && cc.isJsonOutput(cmd.Json) {
+			values := map[string]interface{}{"NoiseFloorDbm": params.NoiseFloorDbm}
+			channelNoiseFloor := map[string]float64{}
+			for ch, nf := range params.ChannelNoiseFloorDbm {
+				channelNoiseFloor[fmt.Sprintf("ch%d", ch)] = nf
+			}
+			values["ChannelNoiseFloorDbm"] = channelNoiseFloor
+			for _, p := range scalarParams {
+				values[p.name] = *p.val
+			}
+			cc.outputJson(values)
+			return
+		}
+
+		if cmd.Name == nil {
+			cc.outputf("NoiseFloorDbm %v\n", params.NoiseFloorDbm)
+			for ch := uint8(dispatcher.MinChannel); ch <= dispatcher.MaxChannel; ch++ {
+				if nf, ok := params.ChannelNoiseFloorDbm[ch]; ok {
+					cc.outputf("NoiseFloorDbm ch%d %v\n", ch, nf)
+				}
+			}
+			for _, p := range scalarParams {
+				cc.outputf("%s %v\n", p.name, *p.val)
+			}
+			return
+		}
+
+		if *cmd.Name == "NoiseFloorDbm" {
+			if cmd.Val == nil {
+				if channel != 0 {
+					cc.outputf("%v\n", params.GetNoiseFloorDbm(channel))
+				} else {
+					cc.outputf("%v\n", params.NoiseFloorDbm)
+				}
+				return
+			}
+
+			if channel != 0 {
+				params.ChannelNoiseFloorDbm[channel] = *cmd.Val
+			} else {
+				params.NoiseFloorDbm = *cmd.Val
+			}
+			sim.Dispatcher().SetRadioModelParams(params)
+			return
+		}
+
+		for _, p := range scalarParams {
+			if p.name != *cmd.Name {
+				continue
+			}
+
+			if channel != 0 {
+				cc.errorf("%s is not a per-channel parameter", p.name)
+			} else if cmd.Val == nil {
+				cc.outputf("%v\n", *p.val)
+			} else if (p.positive && *cmd.Val <= 0) || (p.nonNegative && *cmd.Val < 0) {
+				cc.errorf("invalid %s: %v", p.name, *cmd.Val)
+			} else {
+				*p.val = *cmd.Val
+				params.SetCaptureParams(capture)
+				sim.Dispatcher().SetRadioModelParams(params)
+			}
+			return
+		}
+
+		cc.errorf("unknown radio parameter: %s", *cmd.Name)
+	})
+}
+
+func (rt *CmdRunner) executeRadioRange(cc *CommandContext, cmd *RadioRangeCmd) {
+	var channel uint8
+	if cmd.Channel != nil {
+		var err error
+		if channel, err = parseChannel(*cmd.Channel); err != nil {
+			cc.error(err)
+			return
+		}
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if cmd.Edge == nil {
+			cc.outputf("%d\n", sim.DefaultRadioRange())
+			return
+		}
+
+		if channel == 0 {
+			channel = uint8(sim.Channel())
+		}
+
+		params := sim.Dispatcher().GetRadioModelParams()
+		radioRange := int(math.Round(params.DistanceForRssi(*cmd.Edge, channel)))
+		if radioRange <= 0 {
+			cc.errorf("edge RSSI %v dBm is not reachable with TxPowerDbm %v", *cmd.Edge, params.TxPowerDbm)
+			return
+		}
+
+		sim.SetDefaultRadioRange(radioRange)
+		maxDistance := params.MaxDistance(radioRange, channel)
+		cc.outputf("rr=%d maxdist=%.0f maxdist_m=%.1f\n", radioRange, maxDistance, maxDistance*params.MeterPerUnit)
+	})
+}
+
+func (rt *CmdRunner) executeScan(cc *CommandContext, cmd *ScanCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		node, _ := rt.getNode(sim, cmd.Node)
+		if node == nil {
+			cc.errorf("node not found")
+			return
+		}
+
+		node.CommandExpectNone("scan", simulation.DefaultCommandTimeout)
+	})
+
+	timeout := time.Millisecond * 600 // FIXME: hardcoding
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			node, _ := rt.getNode(sim, cmd.Node)
+			if node == nil {
+				return
+			}
+			node.AssurePrompt()
+		})
+	}
+}
+
+// executeEnergyScan runs energy scans on all routers, and shows the channel quality matrix: the maximum RSSI measured
+// on each channel by each router, aggregated network-wide.
+func (rt *CmdRunner) executeEnergyScan(cc *CommandContext, cmd *EnergyScanCmd) {
+	duration := simulation.DefaultEnergyScanDuration
+	if cmd.Duration != nil {
+		if *cmd.Duration <= 0 {
+			cc.errorf("invalid duration: %dms", *cmd.Duration)
+			return
+		}
+		duration = *cmd.Duration
+	}
+	if cmd.Timeout != nil && *cmd.Timeout < 0 {
+		cc.errorf("invalid timeout: %gs", *cmd.Timeout)
+		return
+	}
+
+	var timeout time.Duration
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		scanTime, err := sim.StartEnergyScan(duration)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		timeout = scanTime + time.Second
+	})
+	if cc.Err() != nil {
+		return
+	}
+	if cmd.Timeout != nil {
+		timeout = time.Duration(*cmd.Timeout * float64(time.Second))
+	}
+
+	// the scans take the simulation time of scanning all channels
+	done := false
+	for elapsed := time.Duration(0); !done && elapsed < timeout && rt.ctx.Err() == nil; elapsed += waitStep {
+		rt.goFor(waitStep)
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			done = sim.EnergyScanDone()
+		})
+	}
+
+	var matrix *simulation.EnergyScanMatrix
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var err error
+		if matrix, err = sim.FinishEnergyScan(); err != nil {
+			cc.error(err)
+		}
+	})
+	if cc.Err() != nil {
+		return
+	}
+
+	if cc.isJsonOutput(cmd.Json) {
+		cc.outputJson(matrix)
+		return
+	}
+
+	var header strings.Builder
+	header.WriteString("node  ")
+	for _, ch := range matrix.Channels {
+		header.WriteString(fmt.Sprintf("%7d", ch.Channel))
+	}
+	cc.outputf("%s\n", header.String())
+	for _, node := range matrix.Nodes {
+		var row strings.Builder
+		row.WriteString(fmt.Sprintf("%-6d", node.Node))
+		for _, ch := range matrix.Channels {
+			if rssi, ok := node.Rssi[ch.Channel]; ok {
+				row.WriteString(fmt.Sprintf("%7d", rssi))
+			} else {
+				row.WriteString(fmt.Sprintf("%7s", "-"))
+			}
+		}
+		if node.Error != "" {
+			row.WriteString("  " + node.Error)
+		}
+		cc.outputf("%s\n", row.String())
+	}
+
+	var maxRow, avgRow strings.Builder
+	maxRow.WriteString("max   ")
+	avgRow.WriteString("avg   ")
+	for _, ch := range matrix.Channels {
+		maxRow.WriteString(fmt.Sprintf("%7d", ch.MaxRssi))
+		avgRow.WriteString(fmt.Sprintf("%7.1f", ch.AvgRssi))
+	}
+	cc.outputf("%s\n", maxRow.String())
+	cc.outputf("%s\n", avgRow.String())
+	if len(matrix.Missing) > 0 {
+		cc.outputf("missing=%s\n", joinNodeIds(matrix.Missing))
+	}
+	if matrix.Recommended == 0 {
+		cc.errorf("no energy scan results")
+		return
+	}
+	cc.outputf("recommended=%d\n", matrix.Recommended)
+}
+
+func (rt *CmdRunner) executeProvision(cc *CommandContext, cmd *ProvisionCmd) {
+	data, err := os.ReadFile(cmd.File)
+	if err != nil {
+		cc.error(err)
+		return
+	}
+	blob, err := simulation.ParseProvisionBlob(data)
+	if err != nil {
+		cc.error(err)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		result, err := sim.Provision(cmd.Node.Id, simulation.ProvisionKind(cmd.Kind), blob)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("node=%d kind=%s bytes=%d commands=%d verified\n", result.Node, result.Kind, result.Bytes,
+			len(result.Commands))
+	})
+}
+
+func (rt *CmdRunner) executeLoad(cc *CommandContext, cmd *LoadCmd) {
+	var topo simulation.Topology
+	data, err := os.ReadFile(cmd.File)
+	if err == nil {
+		err = yaml.Unmarshal(data, &topo)
+	}
+	if err != nil {
+		cc.error(err)
+		return
+	}
+
+	opts := simulation.TopologyLoadOptions{Add: cmd.Add != nil}
+	if cmd.Offset != nil {
+		opts.OffsetX, opts.OffsetY = cmd.Offset.X, cmd.Offset.Y
+	}
+	if cmd.Scale != nil {
+		if *cmd.Scale <= 0 {
+			cc.errorf("invalid scale: %v", *cmd.Scale)
+			return
+		}
+		opts.Scale = *cmd.Scale
+	}
+	if cmd.Rotate != nil {
+		opts.Rotate = *cmd.Rotate
+	}
+	if cmd.Ids != nil {
+		opts.Ids = simulation.TopologyIdStrategy(*cmd.Ids)
+	}
+	if cmd.Pan != nil {
+		opts.Pan = simulation.TopologyPanStrategy(*cmd.Pan)
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		added, err := sim.LoadTopology(&topo, opts)
+		for i, id := range added {
+			cc.outputf("id=%-4d file_id=%d\n", id, topo.Nodes[i].Id)
+		}
+		cc.error(err)
+	})
+}
+
+func (rt *CmdRunner) executeSave(cc *CommandContext, cmd *SaveCmd) {
+	var topo *simulation.Topology
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		topo = sim.Topology()
+	})
+
+	data, err := yaml.Marshal(topo)
+	if err == nil {
+		err = os.WriteFile(cmd.File, data, 0644)
+	}
+	cc.error(err)
+}
+
+func (rt *CmdRunner) executeConfigVisualization(cc *CommandContext, cmd *ConfigVisualizationCmd) {
+	var opts dispatcher.VisualizationOptions
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		opts = sim.Dispatcher().GetVisualizationOptions()
+
+		if cmd.BroadcastMessage != nil {
+			opts.BroadcastMessage = cmd.BroadcastMessage.OnOrOff.On != nil
+		}
+
+		if cmd.UnicastMessage != nil {
+			opts.UnicastMessage = cmd.UnicastMessage.OnOrOff.On != nil
+		}
+
+		if cmd.AckMessage != nil {
+			opts.AckMessage = cmd.AckMessage.OnOrOff.On != nil
+		}
+
+		if cmd.RouterTable != nil {
+			opts.RouterTable = cmd.RouterTable.OnOrOff.On != nil
+		}
+
+		if cmd.ChildTable != nil {
+			opts.ChildTable = cmd.ChildTable.OnOrOff.On != nil
+		}
+
+		if cmd.Nodes != nil && cmd.Nodes.All != nil {
+			opts.Nodes = nil
+		} else if cmd.Nodes != nil {
+			nodeids, err := expandNodeRanges(cmd.Nodes.Nodes)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			opts.Nodes = map[NodeId]struct{}{}
+			for _, id := range nodeids {
+				opts.Nodes[id] = struct{}{}
+			}
+		}
+
+		sim.Dispatcher().SetVisualizationOptions(opts)
+	})
+	if cc.Err() != nil {
+		return
+	}
+
+	bool_to_onoroff := func(on bool) string {
+		if on {
+			return "on"
+		} else {
+			return "off"
+		}
+	}
+	cc.outputf("bro=%s\n", bool_to_onoroff(opts.BroadcastMessage))
+	cc.outputf("uni=%s\n", bool_to_onoroff(opts.UnicastMessage))
+	cc.outputf("ack=%s\n", bool_to_onoroff(opts.AckMessage))
+	cc.outputf("rtb=%s\n", bool_to_onoroff(opts.RouterTable))
+	cc.outputf("ctb=%s\n", bool_to_onoroff(opts.ChildTable))
+	if opts.Nodes != nil {
+		nodeids := make([]NodeId, 0, len(opts.Nodes))
+		for id := range opts.Nodes {
+			nodeids = append(nodeids, id)
+		}
+		sort.Ints(nodeids)
+		cc.outputf("nodes=%s\n", joinNodeIds(nodeids))
+	} else {
+		cc.outputf("nodes=all\n")
+	}
+}
+
+func (rt *CmdRunner) enterNodeContext(nodeid NodeId) bool {
+	simplelogger.AssertTrue(nodeid == InvalidNodeId || nodeid > 0)
+	if rt.contextNodeId == nodeid {
+		return false
+	}
+
+	rt.contextNodeId = nodeid
+	return true
+}
+
+func (rt *CmdRunner) executeTitle(cc *CommandContext, cmd *TitleCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		titleInfo := visualize.DefaultTitleInfo()
+
+		titleInfo.Title = cmd.Title
+		if cmd.X != nil {
+			titleInfo.X = *cmd.X
+		}
+		if cmd.Y != nil {
+			titleInfo.Y = *cmd.Y
+		}
+		if cmd.FontSize != nil {
+			titleInfo.FontSize = *cmd.FontSize
+		}
+
+		sim.SetTitleInfo(titleInfo)
+	})
+}
+
+func (rt *CmdRunner) executeNetInfo(cc *CommandContext, cmd *NetInfoCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		netinfo := sim.GetNetworkInfo()
+		if cmd.Version != nil {
+			netinfo.Version = *cmd.Version
+		}
+		if cmd.Commit != nil {
+			netinfo.Commit = *cmd.Commit
+		}
+		if cmd.Real != nil {
+			netinfo.Real = cmd.Real.Yes != nil
+		}
+		sim.SetNetworkInfo(netinfo)
+	})
+}
+
+func (rt *CmdRunner) executeCoaps(cc *CommandContext, cmd *CoapsCmd) {
+	if cmd.Enable != nil {
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			sim.Dispatcher().EnableCoaps()
+		})
+	} else {
+		var coapMessages []*dispatcher.CoapMessage
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			coapMessages = sim.Dispatcher().CollectCoapMessages()
+		})
+
+		cc.outputItemsAsYaml(coapMessages)
+	}
+}
+
+func (rt *CmdRunner) executeJam(cc *CommandContext, cmd *JamCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Node == nil {
+			// list all jammers
+			var nodeids []NodeId
+			for nodeid := range d.Jammers() {
+				nodeids = append(nodeids, nodeid)
+			}
+			sort.Ints(nodeids)
+			for _, nodeid := range nodeids {
+				cc.outputf("node=%-4d filter=%s\n", nodeid, d.Jammers()[nodeid].JamFilter())
+			}
+			return
+		}
+
+		_, dnode := rt.getNode(sim, *cmd.Node)
+		if dnode == nil {
+			cc.errorf("node %v not found", cmd.Node)
+			return
+		}
+
+		if cmd.Off != nil {
+			d.SetNodeJammer(dnode.Id, nil)
+			return
+		}
+
+		filter := &dispatcher.JamFilter{}
+		if cmd.DstShort != nil {
+			filter.MatchDstShort = true
+			filter.DstShort = cmd.DstShort.Val
+		}
+		if cmd.FrameType != nil {
+			filter.MatchFrameType = true
+			filter.FrameType, _ = dispatcher.ParseJamFrameType(cmd.FrameType.Val)
+		}
+		d.SetNodeJammer(dnode.Id, filter)
+	})
+}
+
+func (rt *CmdRunner) executeRange(cc *CommandContext, cmd *RangeCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Model != nil {
+			em := d.GetRangingErrorModel()
+			if cmd.Model.Bias == nil && cmd.Model.StdDev == nil {
+				cc.outputf("bias=%g stddev=%g\n", em.Bias, em.StdDev)
+				return
+			}
+
+			if cmd.Model.Bias != nil {
+				em.Bias = *cmd.Model.Bias
+			}
+			if cmd.Model.StdDev != nil {
+				em.StdDev = *cmd.Model.StdDev
+			}
+			d.SetRangingErrorModel(em)
+			return
+		}
+
+		if cmd.Events != nil {
+			if cmd.Events.OnOrOff == nil {
+				sim.VisitNodesInOrder(func(node *simulation.Node) {
+					if d.GetNode(node.Id).RangingEvents() {
+						cc.outputf("%d\n", node.Id)
+					}
+				})
+				return
+			}
+
+			if len(cmd.Events.Nodes) == 0 {
+				cc.errorf("no node specified")
+				return
+			}
+			for _, sel := range cmd.Events.Nodes {
+				if _, dnode := rt.getNode(sim, sel); dnode == nil {
+					cc.errorf("node %v not found", sel)
+					return
+				}
+			}
+			for _, sel := range cmd.Events.Nodes {
+				d.SetRangingEvents(sel.Id, cmd.Events.OnOrOff.On != nil)
+			}
+			return
+		}
+
+		count := 1
+		if cmd.Count != nil {
+			count = cmd.Count.Val
+		}
+		if count < 1 {
+			cc.errorf("invalid count: %d", count)
+			return
+		}
+
+		var sum, sqsum float64
+		for i := 0; i < count; i++ {
+			m, err := d.Ranging(cmd.Src.Id, cmd.Dst.Id, uint8(sim.Channel()))
+			if err != nil {
+				cc.error(err)
+				return
+			}
+
+			cc.outputf("src=%-4d dst=%-4d dist=%.2f measured=%.2f err=%+.2f tof=%.2fns\n", m.Src, m.Dst, m.Distance,
+				m.Measured, m.Error(), m.Tof)
+			sum += m.Error()
+			sqsum += m.Error() * m.Error()
+		}
+
+		if count > 1 {
+			cc.outputf("count=%d mean_err=%+.2f rmse=%.2f\n", count, sum/float64(count), math.Sqrt(sqsum/float64(count)))
+		}
+	})
+}
+
+func (rt *CmdRunner) executeRadios(cc *CommandContext, cmd *RadiosCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		_, dnode := rt.getNode(sim, cmd.Node)
+		if dnode == nil {
+			cc.errorf("node %v not found", cmd.Node)
+			return
+		}
+
+		if cmd.Add != nil {
+			maxChannel := cmd.Add.MinChannel
+			if cmd.Add.MaxChannel != nil {
+				maxChannel = *cmd.Add.MaxChannel
+			}
+			if cmd.Add.MinChannel > 0xff || maxChannel > 0xff {
+				cc.errorf("invalid channels: %d-%d", cmd.Add.MinChannel, maxChannel)
+				return
+			}
+
+			cfg := dispatcher.RadioConfig{
+				MinChannel: uint8(cmd.Add.MinChannel),
+				MaxChannel: uint8(maxChannel),
+				RadioRange: dnode.RadioRange(),
+			}
+			if cmd.Add.Range != nil {
+				cfg.RadioRange = *cmd.Add.Range
+			}
+
+			radio, err := d.AddRadio(dnode.Id, cfg)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			cc.outputf("%d\n", radio.Index)
+			return
+		}
+
+		if cmd.Del != nil {
+			cc.error(d.DeleteRadio(dnode.Id, cmd.Del.Index))
+			return
+		}
+
+		cc.outputf("radio=%d channels=* range=%d\n", dispatcher.PrimaryRadio, dnode.RadioRange())
+		for _, radio := range dnode.Radios() {
+			cc.outputf("radio=%d channels=%d-%d range=%d\n", radio.Index, radio.MinChannel, radio.MaxChannel,
+				radio.RadioRange)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeStall(cc *CommandContext, cmd *StallCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		cfg := d.GetStallConfig()
+		if cmd.Timeout == nil && cmd.ForceFail == nil {
+			cc.outputf("timeout=%gs forcefail=%v\n", cfg.Timeout.Seconds(), cfg.ForceFail)
+			for _, report := range d.StallReports() {
+				cc.outputf("time=%d.%06ds duration=%.3fs alive=%s forcefailed=%s\n", report.Time/1000000,
+					report.Time%1000000, report.Duration.Seconds(), joinNodeIds(report.AliveNodes),
+					joinNodeIds(report.ForceFailed))
+			}
+			return
+		}
+
+		if cmd.Timeout != nil {
+			if *cmd.Timeout < 0 {
+				cc.errorf("invalid timeout: %gs", *cmd.Timeout)
+				return
+			}
+			cfg.Timeout = time.Duration(*cmd.Timeout * float64(time.Second))
+		}
+		if cmd.ForceFail != nil {
+			cfg.ForceFail = cmd.ForceFail.OnOrOff.On != nil
+		}
+		d.SetStallConfig(cfg)
+	})
+}
+
+func (rt *CmdRunner) executeSite(cc *CommandContext, cmd *SiteCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Backbone != nil {
+			rt.executeBackbone(cc, d, cmd.Backbone)
+			return
+		}
+		if cmd.Nodes == nil {
+			for _, site := range d.GetSites() {
+				cc.outputf("%s: %s\n", site.Name, joinNodeIds(site.Nodes))
+			}
+			return
+		}
+
+		site := ""
+		if cmd.Nodes.Name != nil {
+			site = *cmd.Nodes.Name
+		}
+		for _, sel := range cmd.Nodes.Nodes {
+			if err := d.SetNodeSite(sel.Id, site); err != nil {
+				cc.error(err)
+				return
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeBackbone(cc *CommandContext, d *dispatcher.Dispatcher, cmd *BackboneFlag) {
+	link := d.GetBackboneLink()
+	if cmd.Latency == nil && cmd.Jitter == nil && cmd.Loss == nil {
+		cc.outputf("latency=%gms jitter=%gms loss=%g%%\n", float64(link.Latency)/1000, float64(link.Jitter)/1000,
+			link.Loss*100)
+		return
+	}
+
+	if cmd.Latency != nil {
+		if *cmd.Latency < 0 {
+			cc.errorf("invalid latency: %gms", *cmd.Latency)
+			return
+		}
+		link.Latency = uint64(*cmd.Latency * 1000)
+	}
+	if cmd.Jitter != nil {
+		if *cmd.Jitter < 0 {
+			cc.errorf("invalid jitter: %gms", *cmd.Jitter)
+			return
+		}
+		link.Jitter = uint64(*cmd.Jitter * 1000)
+	}
+	if cmd.Loss != nil {
+		if *cmd.Loss < 0 || *cmd.Loss > 100 {
+			cc.errorf("invalid loss: %g%%", *cmd.Loss)
+			return
+		}
+		link.Loss = *cmd.Loss / 100
+	}
+	simplelogger.AssertNil(d.SetBackboneLink(link))
+}
+
+func (rt *CmdRunner) executeDup(cc *CommandContext, cmd *DupCmd) {
+	if cmd.Ratio != nil && (*cmd.Ratio < 0 || *cmd.Ratio > 100) {
+		cc.errorf("invalid ratio: %g%%", *cmd.Ratio)
+		return
+	}
+	if cmd.Delay != nil && *cmd.Delay < 0 {
+		cc.errorf("invalid delay: %gms", *cmd.Delay)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Link == nil && cmd.Off == nil && cmd.Ratio == nil && cmd.Delay == nil {
+			dup := d.GetFrameDup()
+			cc.outputf("ratio=%g%% delay=%gms duplicates=%d\n", dup.Ratio*100, float64(dup.Delay)/1000,
+				d.Counters.DuplicatedFrames)
+			for _, ld := range d.GetLinkFrameDups() {
+				cc.outputf("link=%d-%d ratio=%g%% delay=%gms duplicates=%d\n", ld.Src, ld.Dst, ld.Ratio*100,
+					float64(ld.Delay)/1000, ld.Duplicates)
+			}
+			return
+		}
+
+		dup := d.GetFrameDup()
+		if cmd.Link != nil {
+			src, dst := cmd.Link.Src.Id, cmd.Link.Dst.Id
+			if cmd.Off != nil {
+				d.ClearLinkFrameDup(src, dst)
+				return
+			}
+			for _, ld := range d.GetLinkFrameDups() {
+				if ld.Src == src && ld.Dst == dst {
+					dup = ld.FrameDup
+				}
+			}
+		} else if cmd.Off != nil {
+			dup.Ratio = 0
+		}
+
+		if cmd.Ratio != nil {
+			dup.Ratio = *cmd.Ratio / 100
+		}
+		if cmd.Delay != nil {
+			dup.Delay = uint64(*cmd.Delay * 1000)
+		}
+
+		if cmd.Link != nil {
+			cc.error(d.SetLinkFrameDup(cmd.Link.Src.Id, cmd.Link.Dst.Id, dup))
+		} else {
+			cc.error(d.SetFrameDup(dup))
+		}
+	})
+}
+
+func (rt *CmdRunner) executeReorder(cc *CommandContext, cmd *ReorderCmd) {
+	if cmd.Ratio != nil && (*cmd.Ratio < 0 || *cmd.Ratio > 100) {
+		cc.errorf("invalid ratio: %g%%", *cmd.Ratio)
+		return
+	}
+	if cmd.Hold != nil && *cmd.Hold <= 0 {
+		cc.errorf("invalid hold time: %gms", *cmd.Hold)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		reorder := d.GetFrameReorder()
+		if cmd.Off == nil && cmd.Ratio == nil && cmd.Hold == nil {
+			cc.outputf("ratio=%g%% hold=%gms reordered=%d timeouts=%d\n", reorder.Ratio*100,
+				float64(reorder.MaxHold)/1000, d.Counters.ReorderedFrames, d.Counters.ReorderHoldTimeouts)
+			return
+		}
+
+		if cmd.Off != nil {
+			reorder.Ratio = 0
+		} else if cmd.Ratio != nil {
+			reorder.Ratio = *cmd.Ratio / 100
+		}
+		if cmd.Hold != nil {
+			reorder.MaxHold = uint64(*cmd.Hold * 1000)
+		}
+		cc.error(d.SetFrameReorder(reorder))
+	})
+}
+
+func (rt *CmdRunner) executeFormat(cc *CommandContext, cmd *FormatCmd) {
+	if cmd.Format == nil {
+		if rt.jsonOutput {
+			cc.outputf("json\n")
+		} else {
+			cc.outputf("text\n")
+		}
+		return
+	}
+
+	rt.jsonOutput = *cmd.Format == "json"
+}
+
+func (rt *CmdRunner) executeSummary(cc *CommandContext, cmd *SummaryCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		sim.CollectMacCounters()
+		sim.CollectResourceUsage()
+		summary := sim.Summary()
+		if cmd.Json != nil {
+			data, err := json.MarshalIndent(summary, "", "  ")
+			simplelogger.PanicIfError(err)
+			cc.outputf("%s\n", data)
+			return
+		}
+
+		summary.WriteText(cc.output)
+	})
+}
+
+func (rt *CmdRunner) executeTop(cc *CommandContext, cmd *TopCmd) {
+	var resources []simulation.NodeResources
+	var footprint simulation.ResourceFootprint
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			if node, _ := rt.getNode(sim, sel); node == nil {
+				cc.errorf("node %v not found", sel)
+				return
+			}
+		}
+
+		sim.CollectResourceUsage()
+		if cmd.Total != nil {
+			footprint = sim.ResourceFootprint()
+			return
+		}
+
+		sim.VisitNodesInOrder(func(node *simulation.Node) {
+			if r, ok := node.Resources(); ok {
+				resources = append(resources, r)
+			}
+		})
+	})
+
+	if cc.Err() != nil {
+		return
+	}
+
+	if cmd.Total != nil {
+		if cc.isJsonOutput(cmd.Output.Json) {
+			cc.outputJson(footprint)
+			return
+		}
+		cc.outputf("nodes=%d rss=%s peak_rss=%s cpu_time=%.3fs otns_rss=%s otns_cpu_time=%.3fs\n", footprint.Nodes,
+			simulation.FormatBytes(footprint.RssBytes), simulation.FormatBytes(footprint.PeakRssBytes),
+			float64(footprint.CpuTime)/1000000, simulation.FormatBytes(footprint.OtnsRssBytes),
+			float64(footprint.OtnsCpuTime)/1000000)
+		return
+	}
+
+	if len(cmd.Nodes) > 0 {
+		selected := map[NodeId]bool{}
+		for _, sel := range cmd.Nodes {
+			selected[sel.Id] = true
+		}
+		var filtered []simulation.NodeResources
+		for _, r := range resources {
+			if selected[r.Node] {
+				filtered = append(filtered, r)
+			}
+		}
+		resources = filtered
+	}
+
+	// busiest nodes first
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].CpuPercent > resources[j].CpuPercent
+	})
+
+	var records []outputRecord
+	for _, r := range resources {
+		growth := "+" + simulation.FormatBytes(uint64(r.RssGrowth))
+		if r.RssGrowth < 0 {
+			growth = "-" + simulation.FormatBytes(uint64(-r.RssGrowth))
+		}
+		cpuTime := float64(r.CpuTime) / 1000000
+		records = append(records, outputRecord{
+			{Key: "node", Text: strconv.Itoa(r.Node), Val: r.Node, Width: 4},
+			{Key: "pid", Text: strconv.Itoa(r.Pid), Val: r.Pid, Width: 7},
+			{Key: "rss", Text: simulation.FormatBytes(r.RssBytes), Val: r.RssBytes, Width: 7},
+			{Key: "peak_rss", Text: simulation.FormatBytes(r.PeakRssBytes), Val: r.PeakRssBytes, Width: 7},
+			{Key: "rss_growth", Text: growth, Val: r.RssGrowth, Width: 7},
+			{Key: "cpu_time", Text: fmt.Sprintf("%.3fs", cpuTime), Val: cpuTime, Width: 9},
+			{Key: "cpu", Text: fmt.Sprintf("%.1f%%", r.CpuPercent), Val: r.CpuPercent},
+		})
+	}
+
+	cc.outputRecords(records, &cmd.Output, " ")
+}
+
+func (rt *CmdRunner) executeCoalesce(cc *CommandContext, cmd *CoalesceCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Off != nil {
+			d.SetAlarmCoalescing(0)
+		} else if cmd.Window != nil {
+			if *cmd.Window < 0 || *cmd.Window > dispatcher.MaxAlarmCoalescing {
+				cc.errorf("invalid window: %dus (max %dus)", *cmd.Window, dispatcher.MaxAlarmCoalescing)
+				return
+			}
+			d.SetAlarmCoalescing(uint64(*cmd.Window))
+		} else if window := d.GetAlarmCoalescing(); window > 0 {
+			cc.outputf("window=%dus coalesced=%d\n", window, d.Counters.CoalescedAlarms)
+		} else {
+			cc.outputf("off\n")
+		}
+	})
+}
+
+func (rt *CmdRunner) executeThrottle(cc *CommandContext, cmd *ThrottleCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		for _, sel := range cmd.Nodes {
+			if _, dnode := rt.getNode(sim, sel); dnode == nil {
+				cc.errorf("node %v not found", sel)
+				return
+			}
+		}
+
+		if cmd.Limit != nil {
+			if *cmd.Limit < 0 {
+				cc.errorf("invalid limit: %d", *cmd.Limit)
+				return
+			}
+			if len(cmd.Nodes) == 0 {
+				d.SetLogRateLimit(*cmd.Limit)
+			}
+			for _, sel := range cmd.Nodes {
+				d.SetNodeLogRateLimit(sel.Id, *cmd.Limit)
+			}
+			return
+		}
+
+		if len(cmd.Nodes) == 0 {
+			cc.outputf("limit=%d throttled=%d\n", d.GetLogRateLimit(), d.Counters.ThrottledEvents)
+			sim.VisitNodesInOrder(func(node *simulation.Node) {
+				dnode := d.GetNode(node.Id)
+				cc.outputf("node=%d limit=%d throttled=%d\n", node.Id, dnode.LogRateLimit(), dnode.ThrottledEvents())
+			})
+			return
+		}
+		for _, sel := range cmd.Nodes {
+			dnode := d.GetNode(sel.Id)
+			cc.outputf("node=%d limit=%d throttled=%d\n", sel.Id, dnode.LogRateLimit(), dnode.ThrottledEvents())
+		}
+	})
+}
+
+func (rt *CmdRunner) executeHistory(cc *CommandContext, cmd *HistoryCmd) {
+	var states []dispatcher.NodeState
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		start, end := uint64(0), dispatcher.Ever
+		if cmd.Start != nil {
+			if *cmd.Start < 0 {
+				cc.errorf("invalid time: %gs", *cmd.Start)
+				return
+			}
+			start = uint64(*cmd.Start * 1000000)
+			end = start
+		}
+		if cmd.End != nil {
+			if *cmd.End < *cmd.Start {
+				cc.errorf("invalid time range: %gs - %gs", *cmd.Start, *cmd.End)
+				return
+			}
+			end = uint64(*cmd.End * 1000000)
+		}
+
+		if cmd.Start != nil && cmd.End == nil {
+			// the state at the time
+			if state, ok := d.NodeStateAt(cmd.Node.Id, start); ok {
+				states = []dispatcher.NodeState{state}
+			} else {
+				cc.errorf("node %d has no state at %gs", cmd.Node.Id, *cmd.Start)
+			}
+			return
+		}
+		states = d.NodeHistory(cmd.Node.Id, start, end)
+	})
+
+	if cc.Err() != nil {
+		return
+	}
+	if cc.isJsonOutput(cmd.Json) {
+		if states == nil {
+			states = []dispatcher.NodeState{}
+		}
+		cc.outputJson(states)
+		return
+	}
+	for _, state := range states {
+		if state.Deleted {
+			cc.outputf("time=%d.%06ds deleted\n", state.Time/1000000, state.Time%1000000)
+			continue
+		}
+		cc.outputf("time=%d.%06ds role=%-8s rloc16=%04x parent=%016x partition=%08x\n", state.Time/1000000,
+			state.Time%1000000, state.RoleName, state.Rloc16, state.Parent, state.PartitionId)
+	}
+}
+
+func (rt *CmdRunner) executeZombies(cc *CommandContext, cmd *ZombiesCmd) {
+	if cmd.Policy != nil && cmd.Policy.Timeout != nil && *cmd.Policy.Timeout < 0 {
+		cc.errorf("invalid zombie timeout: %gs", *cmd.Policy.Timeout)
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		if cmd.Policy != nil {
+			if cmd.Policy.Timeout == nil && cmd.Policy.Restart == nil {
+				timeout := "off"
+				if d.GetZombieTimeout() > 0 {
+					timeout = fmt.Sprintf("%gs", float64(d.GetZombieTimeout())/1000000)
+				}
+				restart := "off"
+				if sim.ZombieRestart() {
+					restart = "on"
+				}
+				cc.outputf("timeout=%s restart=%s\n", timeout, restart)
+				return
+			}
+
+			if cmd.Policy.Timeout != nil {
+				d.SetZombieTimeout(uint64(*cmd.Policy.Timeout * 1000000))
+			}
+			if cmd.Policy.Restart != nil {
+				sim.SetZombieRestart(cmd.Policy.Restart.On != nil)
+			}
+			return
+		}
+
+		zombies := d.Zombies()
+		if cc.isJsonOutput(cmd.Json) {
+			cc.outputJson(zombies)
+			return
+		}
+
+		for _, z := range zombies {
+			cc.outputf("node=%-4d last_event=%.6fs detected=%.6fs\n", z.Node, float64(z.LastEvent)/1000000,
+				float64(z.Detected)/1000000)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeHealth(cc *CommandContext, cmd *HealthCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		cfg := sim.GetHealthConfig()
+		if cmd.Policy != nil {
+			if cmd.Policy.Policy == nil {
+				cc.outputf("policy=%s max=%d\n", cfg.Policy, cfg.MaxRestarts)
+				return
+			}
+
+			policy, err := simulation.ParseHealthPolicy(*cmd.Policy.Policy)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			cfg.Policy = policy
+			if cmd.Policy.MaxRestarts != nil {
+				if *cmd.Policy.MaxRestarts < 0 {
+					cc.errorf("invalid max restarts: %d", *cmd.Policy.MaxRestarts)
+					return
+				}
+				cfg.MaxRestarts = *cmd.Policy.MaxRestarts
+			}
+			sim.SetHealthConfig(cfg)
+			return
+		}
+
+		events := sim.HealthEvents()
+		if cmd.Json != nil {
+			if events == nil {
+				events = []*simulation.HealthEvent{}
+			}
+			data, err := json.MarshalIndent(events, "", "  ")
+			simplelogger.PanicIfError(err)
+			cc.outputf("%s\n", data)
+			return
 		}
-	})
 
-	for parid, nodeids := range pars {
-		cc.outputf("partition=%08x\tnodes=", parid)
-		for i, nodeid := range nodeids {
-			if i > 0 {
-				cc.outputf(",")
+		for _, evt := range events {
+			cc.outputf("time=%d.%06ds node=%-4d status=%q action=%s restarts=%d\n", evt.Time/1000000,
+				evt.Time%1000000, evt.Node, evt.Status, evt.Action, evt.Restarts)
+			if evt.Error != "" {
+				cc.outputf("    error: %s\n", evt.Error)
+			}
+			for _, line := range evt.Stderr {
+				cc.outputf("    stderr: %s\n", line)
 			}
-			cc.outputf("%d", nodeid)
 		}
-		cc.outputf("\n")
-	}
+	})
 }
 
-func (rt *CmdRunner) executeCollectPings(cc *CommandContext, pings *PingsCmd) {
-	allPings := make(map[NodeId][]*dispatcher.PingResult)
+func (rt *CmdRunner) executeAttachLog(cc *CommandContext, cmd *AttachLogCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		d := sim.Dispatcher()
-		for nodeid, node := range d.Nodes() {
-			pings := node.CollectPings()
-			if len(pings) > 0 {
-				allPings[nodeid] = pings
+		if cmd.Reset != nil {
+			d.ResetAttachLogs()
+			return
+		}
+
+		if d.GetNode(cmd.Node.Id) == nil {
+			cc.errorf("node %d not found", cmd.Node.Id)
+			return
+		}
+
+		attempts := d.AttachLog(cmd.Node.Id)
+		if cc.isJsonOutput(cmd.Json) {
+			if attempts == nil {
+				attempts = []*dispatcher.AttachAttempt{}
 			}
+			cc.outputJson(attempts)
+			return
 		}
-	})
 
-	for nodeid, pings := range allPings {
-		for _, ping := range pings {
-			cc.outputf("node=%-4d dst=%-40s datasize=%-3d delay=%.3fms\n", nodeid, ping.Dst, ping.DataSize, float64(ping.Delay)/1000)
+		for _, attempt := range attempts {
+			cc.outputf("time=%d.%06ds candidates=%d", attempt.Start/1000000, attempt.Start%1000000,
+				len(attempt.Candidates))
+			if attempt.End != 0 {
+				cc.outputf(" parent=%016x after=%v\n", attempt.Parent,
+					time.Duration(attempt.End-attempt.Start)*time.Microsecond)
+			} else {
+				cc.outputf(" parent=none\n")
+			}
+
+			for _, c := range attempt.Candidates {
+				mark, outcome := " ", "rejected: "+c.Reason
+				if c.Selected {
+					mark, outcome = "*", "selected"
+				} else if attempt.End == 0 {
+					outcome = "pending"
+				} else if c.Reason == "" {
+					outcome = "not selected"
+				}
+				cc.outputf("  %s %016x node=%-4d rloc16=0x%04x rssi=%-4d lq=%d priority=%-2d lq3=%-2d %s\n", mark,
+					c.ExtAddr, c.Node, c.Rloc16, c.Rssi, c.LinkQuality, c.Priority, c.LinkQuality3, outcome)
+			}
 		}
-	}
+	})
 }
 
-func (rt *CmdRunner) executeCollectJoins(cc *CommandContext, joins *JoinsCmd) {
-	allJoins := make(map[NodeId][]*dispatcher.JoinResult)
-
+func (rt *CmdRunner) executeAlert(cc *CommandContext, cmd *AlertCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		d := sim.Dispatcher()
-		for nodeid, node := range d.Nodes() {
-			joins := node.CollectJoins()
-			if len(joins) > 0 {
-				allJoins[nodeid] = joins
+		if cmd.Add != nil {
+			rule := dispatcher.AlertRule{Condition: dispatcher.AlertCondition(cmd.Add.Condition)}
+			if cmd.Add.Threshold != nil {
+				rule.Threshold = *cmd.Add.Threshold
+			} else if rule.Condition != dispatcher.AlertDetached {
+				cc.errorf("missing %s threshold", rule.Condition)
+				return
+			}
+			if cmd.Add.For != nil {
+				if *cmd.Add.For < 0 {
+					cc.errorf("invalid duration: %v", *cmd.Add.For)
+					return
+				}
+				rule.For = uint64(*cmd.Add.For * 1000000)
+			}
+			if cmd.Add.Webhook != nil {
+				rule.Webhook = *cmd.Add.Webhook
+			}
+
+			id, err := d.AddAlertRule(rule)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			cc.outputf("%d\n", id)
+		} else if cmd.Del != nil {
+			cc.error(d.DeleteAlertRule(*cmd.Del))
+		} else if cmd.Reset != nil {
+			d.ResetAlertEvents()
+		} else if cmd.Events != nil {
+			events := d.AlertEvents()
+			if cc.isJsonOutput(cmd.Json) {
+				if events == nil {
+					events = []*dispatcher.AlertEvent{}
+				}
+				cc.outputJson(events)
+				return
+			}
+
+			for _, evt := range events {
+				state := "fired"
+				if evt.Resolved {
+					state = "resolved"
+				}
+				cc.outputf("time=%d.%06ds rule=%-3d %-8s %s\n", evt.Time/1000000, evt.Time%1000000, evt.Rule, state,
+					evt.Message)
+			}
+		} else {
+			rules := d.AlertRules()
+			if cc.isJsonOutput(cmd.Json) {
+				cc.outputJson(rules)
+				return
 			}
-		}
-	})
 
-	for nodeid, joins := range allJoins {
-		for _, join := range joins {
-			cc.outputf("node=%-4d join=%.3fs session=%.3fs\n", nodeid, float64(join.JoinDuration)/1000000, float64(join.SessionDuration)/1000000)
+			for _, rule := range rules {
+				cc.outputf("id=%-3d condition=%-10s threshold=%-6v for=%v", rule.Id, rule.Condition, rule.Threshold,
+					time.Duration(rule.For)*time.Microsecond)
+				if rule.Webhook != "" {
+					cc.outputf(" webhook=%s", rule.Webhook)
+				}
+				cc.outputf("\n")
+			}
 		}
-	}
+	})
 }
 
-func (rt *CmdRunner) executeCounters(cc *CommandContext, counters *CountersCmd) {
+func (rt *CmdRunner) executeAirtime(cc *CommandContext, cmd *AirtimeCmd) {
+	var report *dispatcher.AirtimeReport
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		d := sim.Dispatcher()
-		countersVal := reflect.ValueOf(d.Counters)
-		countersTyp := reflect.TypeOf(d.Counters)
-		for i := 0; i < countersVal.NumField(); i++ {
-			fname := countersTyp.Field(i).Name
-			fval := countersVal.Field(i)
-			cc.outputf("%-40s %v\n", fname, fval.Uint())
+		if cmd.Reset != nil {
+			sim.Dispatcher().ResetAirtime()
+		} else {
+			report = sim.Dispatcher().GetAirtimeReport()
 		}
 	})
-}
 
-func (rt *CmdRunner) executeWeb(cc *CommandContext, webcmd *WebCmd) {
-	if err := web.OpenWeb(rt.ctx); err != nil {
-		cc.error(err)
+	if report == nil {
+		return
 	}
-}
-
-func (rt *CmdRunner) executePlr(cc *CommandContext, cmd *PlrCmd) {
-	if cmd.Val == nil {
-		// get PLR
-		var plr float64
 
-		rt.postAsyncWait(func(sim *simulation.Simulation) {
-			plr = sim.Dispatcher().GetGlobalMessageDropRatio()
-		})
+	if cmd.Yaml != nil {
+		cc.outputItemsAsYaml(report.Nodes)
+		return
+	}
 
-		cc.outputf("%v\n", plr)
-	} else {
-		// set PLR
-		rt.postAsyncWait(func(sim *simulation.Simulation) {
-			sim.Dispatcher().SetGlobalPacketLossRatio(*cmd.Val)
-			*cmd.Val = sim.Dispatcher().GetGlobalMessageDropRatio()
-		})
-		cc.outputf("%v\n", *cmd.Val)
+	for _, stat := range report.Nodes {
+		dominant := ""
+		if stat.Dominant {
+			dominant = "\tDOMINANT"
+		}
+		cc.outputf("node=%-4d frames=%-6d airtime=%.3fs util=%.2f%% share=%.2f%%%s\n", stat.NodeId, stat.TxFrames,
+			float64(stat.TxAirtime)/1000000, stat.Utilization*100, stat.Share*100, dominant)
 	}
+	cc.outputf("window=%.3fs airtime=%.3fs util=%.2f%% fairness=%.3f\n", float64(report.WindowEnd-report.WindowStart)/1000000,
+		float64(report.TxAirtime)/1000000, report.Utilization*100, report.Fairness)
 }
 
-func (rt *CmdRunner) executeScan(cc *CommandContext, cmd *ScanCmd) {
-	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		node, _ := rt.getNode(sim, cmd.Node)
-		if node == nil {
-			cc.errorf("node not found")
-			return
+func (rt *CmdRunner) executeAntenna(cc *CommandContext, cmd *AntennaCmd) {
+	var loaded []dispatcher.NodeAntenna
+	if cmd.Load != nil {
+		var items []yaml.Node
+		data, err := os.ReadFile(*cmd.Load)
+		if err == nil {
+			err = yaml.Unmarshal(data, &items)
 		}
-
-		node.CommandExpectNone("scan", simulation.DefaultCommandTimeout)
-	})
-
-	timeout := time.Millisecond * 600 // FIXME: hardcoding
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		rt.postAsyncWait(func(sim *simulation.Simulation) {
-			node, _ := rt.getNode(sim, cmd.Node)
-			if node == nil {
-				return
+		for i := 0; err == nil && i < len(items); i++ {
+			na := dispatcher.NodeAntenna{AntennaPattern: dispatcher.DefaultSectorAntenna(0, 0)}
+			if err = items[i].Decode(&na); err == nil {
+				err = na.Validate()
 			}
-			node.AssurePrompt()
-		})
+			loaded = append(loaded, na)
+		}
+		if err != nil {
+			cc.error(err)
+			return
+		}
 	}
-}
 
-func (rt *CmdRunner) executeConfigVisualization(cc *CommandContext, cmd *ConfigVisualizationCmd) {
-	var opts dispatcher.VisualizationOptions
+	var antennas []dispatcher.NodeAntenna
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		opts = sim.Dispatcher().GetVisualizationOptions()
+		d := sim.Dispatcher()
 
-		if cmd.BroadcastMessage != nil {
-			opts.BroadcastMessage = cmd.BroadcastMessage.OnOrOff.On != nil
+		if cmd.Load != nil {
+			for _, na := range loaded {
+				if d.GetNode(na.NodeId) == nil {
+					cc.errorf("node %d not found", na.NodeId)
+					return
+				}
+			}
+			for _, na := range loaded {
+				pattern := na.AntennaPattern
+				d.SetNodeAntenna(na.NodeId, &pattern)
+			}
+			return
 		}
 
-		if cmd.UnicastMessage != nil {
-			opts.UnicastMessage = cmd.UnicastMessage.OnOrOff.On != nil
-		}
+		if cmd.Node != nil {
+			_, node := rt.getNode(sim, *cmd.Node)
+			if node == nil {
+				cc.errorf("node %d not found", cmd.Node.Id)
+				return
+			}
 
-		if cmd.AckMessage != nil {
-			opts.AckMessage = cmd.AckMessage.OnOrOff.On != nil
+			if cmd.Off != nil {
+				d.SetNodeAntenna(node.Id, nil)
+				return
+			} else if cmd.Sector != nil {
+				pattern := dispatcher.DefaultSectorAntenna(cmd.Sector.Azimuth, cmd.Sector.BeamWidth)
+				if cmd.Sector.GainDbi != nil {
+					pattern.GainDbi = *cmd.Sector.GainDbi
+				}
+				if cmd.Sector.BackGainDbi != nil {
+					pattern.BackGainDbi = *cmd.Sector.BackGainDbi
+				}
+				if err := pattern.Validate(); err != nil {
+					cc.error(err)
+					return
+				}
+				d.SetNodeAntenna(node.Id, &pattern)
+				return
+			}
 		}
 
-		if cmd.RouterTable != nil {
-			opts.RouterTable = cmd.RouterTable.OnOrOff.On != nil
+		for _, na := range d.NodeAntennas() {
+			if cmd.Node == nil || na.NodeId == cmd.Node.Id {
+				antennas = append(antennas, na)
+			}
 		}
+	})
 
-		if cmd.ChildTable != nil {
-			opts.ChildTable = cmd.ChildTable.OnOrOff.On != nil
+	if cmd.Yaml != nil {
+		if len(antennas) > 0 {
+			cc.outputItemsAsYaml(antennas)
 		}
+		return
+	}
 
-		sim.Dispatcher().SetVisualizationOptions(opts)
-	})
+	for _, na := range antennas {
+		cc.outputf("node=%-4d azimuth=%v beamwidth=%v gain=%vdBi back=%vdBi\n", na.NodeId, na.Azimuth, na.BeamWidth,
+			na.GainDbi, na.BackGainDbi)
+	}
+}
 
-	bool_to_onoroff := func(on bool) string {
-		if on {
-			return "on"
+func (rt *CmdRunner) executeKpi(cc *CommandContext, cmd *KpiCmd) {
+	var kpi *dispatcher.Kpi
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		d := sim.Dispatcher()
+		// sample node MAC counters and resource usage so that the KPI covers exactly the increments between start and
+		// stop
+		sim.CollectMacCounters()
+		sim.CollectResourceUsage()
+		sim.CollectLinkMetrics()
+		if cmd.Start != nil {
+			d.StartKpi()
+		} else if cmd.Stop != nil {
+			if err := d.StopKpi(); err != nil {
+				cc.error(err)
+			}
 		} else {
-			return "off"
+			kpi = d.GetKpi()
+			if kpi == nil {
+				cc.errorf("KPI not started")
+			}
 		}
+	})
+
+	if kpi == nil {
+		return
 	}
-	cc.outputf("bro=%s\n", bool_to_onoroff(opts.BroadcastMessage))
-	cc.outputf("uni=%s\n", bool_to_onoroff(opts.UnicastMessage))
-	cc.outputf("ack=%s\n", bool_to_onoroff(opts.AckMessage))
-	cc.outputf("rtb=%s\n", bool_to_onoroff(opts.RouterTable))
-	cc.outputf("ctb=%s\n", bool_to_onoroff(opts.ChildTable))
-}
 
-func (rt *CmdRunner) enterNodeContext(nodeid NodeId) bool {
-	simplelogger.AssertTrue(nodeid == InvalidNodeId || nodeid > 0)
-	if rt.contextNodeId == nodeid {
-		return false
+	if cmd.Save != nil {
+		if err := kpi.WriteFile(*cmd.Save); err != nil {
+			cc.error(err)
+		}
+		return
 	}
 
-	rt.contextNodeId = nodeid
-	return true
+	data, err := json.MarshalIndent(kpi, "", "  ")
+	simplelogger.PanicIfError(err)
+	cc.outputf("%s\n", data)
 }
 
-func (rt *CmdRunner) executeTitle(cc *CommandContext, cmd *TitleCmd) {
-	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		titleInfo := visualize.DefaultTitleInfo()
+func (rt *CmdRunner) executeCompare(cc *CommandContext, cmd *CompareCmd) {
+	golden, err := visualizeStatslog.ReadFile(cmd.File)
+	if err != nil {
+		cc.error(err)
+		return
+	}
 
-		titleInfo.Title = cmd.Title
-		if cmd.X != nil {
-			titleInfo.X = *cmd.X
-		}
-		if cmd.Y != nil {
-			titleInfo.Y = *cmd.Y
-		}
-		if cmd.FontSize != nil {
-			titleInfo.FontSize = *cmd.FontSize
+	var tol visualizeStatslog.Tolerance
+	if cmd.Time != nil {
+		if *cmd.Time < 0 {
+			cc.errorf("invalid time tolerance: %v", *cmd.Time)
+			return
 		}
+		tol.Time = uint64(*cmd.Time * 1000000)
+	}
+	if cmd.Count != nil {
+		tol.Count = *cmd.Count
+	}
 
-		sim.SetTitleInfo(titleInfo)
+	var current visualizeStatslog.Timeline
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		current = sim.StatsLog()
 	})
+
+	mismatches := visualizeStatslog.Compare(golden, current, tol)
+	for _, m := range mismatches {
+		cc.outputf("%s\n", m)
+	}
+	if len(mismatches) > 0 {
+		cc.errorf("%d mismatches against %s", len(mismatches), cmd.File)
+	}
 }
 
-func (rt *CmdRunner) executeNetInfo(cc *CommandContext, cmd *NetInfoCmd) {
+func (rt *CmdRunner) executeStatsWindow(cc *CommandContext, cmd *StatsWindowCmd) {
+	var windows []*dispatcher.TimeWindowStats
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
-		netinfo := sim.GetNetworkInfo()
-		if cmd.Version != nil {
-			netinfo.Version = *cmd.Version
+		d := sim.Dispatcher()
+		cfg := d.GetWindowStatsConfig()
+
+		if cmd.Interval == nil && cmd.Retention == nil && cmd.Metrics == nil {
+			if cmd.Yaml == nil {
+				cc.outputf("interval=%.3fs keep=%d metrics=%s\n", float64(cfg.Interval)/1000000, cfg.Retention, cfg.Metrics)
+			}
+			sim.CollectMacCounters()
+			windows = d.GetWindowStats()
+			return
 		}
-		if cmd.Commit != nil {
-			netinfo.Commit = *cmd.Commit
+
+		if cmd.Interval != nil {
+			// the interval must be positive, and at least 1us
+			if cmd.Interval.Val <= 0 || cmd.Interval.Val*1000000 < 1 {
+				cc.errorf("invalid interval: %v", cmd.Interval.Val)
+				return
+			}
+			cfg.Interval = uint64(cmd.Interval.Val * 1000000)
 		}
-		if cmd.Real != nil {
-			netinfo.Real = cmd.Real.Yes != nil
+		if cmd.Retention != nil {
+			if cmd.Retention.Val <= 0 {
+				cc.errorf("invalid keep: %d", cmd.Retention.Val)
+				return
+			}
+			cfg.Retention = cmd.Retention.Val
 		}
-		sim.SetNetworkInfo(netinfo)
+		if cmd.Metrics != nil {
+			metrics, err := dispatcher.ParseWindowMetrics(cmd.Metrics.Val)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			cfg.Metrics = metrics
+		}
+		sim.SetWindowStatsConfig(cfg)
 	})
+
+	if cmd.Yaml != nil {
+		if windows != nil {
+			cc.outputItemsAsYaml(windows)
+		}
+		return
+	}
+
+	for _, w := range windows {
+		total := w.Total()
+		mac := w.MacTotal()
+		cc.outputf("start=%.3fs end=%.3fs nodes=%-4d frames=%-6d bytes=%-8d airtime=%.3fs retries=%-5d cca=%-5d drops=%d\n",
+			float64(w.Start)/1000000, float64(w.End)/1000000, len(w.PhyTx), total.Frames, total.Bytes,
+			float64(total.Airtime)/1000000, mac.TxRetries, mac.CcaFailures, mac.TotalDrops())
+	}
 }
 
-func (rt *CmdRunner) executeCoaps(cc *CommandContext, cmd *CoapsCmd) {
-	if cmd.Enable != nil {
-		rt.postAsyncWait(func(sim *simulation.Simulation) {
-			sim.Dispatcher().EnableCoaps()
-		})
-	} else {
-		var coapMessages []*dispatcher.CoapMessage
-		rt.postAsyncWait(func(sim *simulation.Simulation) {
-			coapMessages = sim.Dispatcher().CollectCoapMessages()
-		})
+func (rt *CmdRunner) executeSession(cc *CommandContext, cmd *SessionCmd) {
+	if rt.sessions == nil {
+		cc.errorf("sessions are not supported")
+		return
+	}
 
-		cc.outputItemsAsYaml(coapMessages)
+	if cmd.New != nil {
+		id := -1
+		if cmd.Id != nil {
+			id = *cmd.Id
+		}
+
+		id, err := rt.sessions.New(id)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("%d\n", id)
+	} else if cmd.Del != nil {
+		if cmd.Id == nil {
+			cc.errorf("session ID is required")
+			return
+		}
+		cc.error(rt.sessions.Delete(*cmd.Id))
+	} else if cmd.Id != nil {
+		if rt.client != nil {
+			cc.error(rt.client.switchSession(rt.sessions, *cmd.Id))
+		} else {
+			cc.error(rt.sessions.Switch(*cmd.Id))
+		}
+	} else {
+		currentId, _ := rt.currentSession()
+		for _, info := range rt.sessions.List() {
+			current := ""
+			if info.Id == currentId {
+				current = " *"
+			}
+			cc.outputf("session %d\tport=%d\tnodes=%d%s\n", info.Id, info.Port, info.Nodes, current)
+		}
 	}
 }
 
 func NewCmdRunner(ctx *progctx.ProgCtx, sim *simulation.Simulation) *CmdRunner {
+	return newCmdRunner(ctx, sim, newAsyncOutput(ctx))
+}
+
+func newCmdRunner(ctx *progctx.ProgCtx, sim *simulation.Simulation, async *asyncOutput) *CmdRunner {
 	cr := &CmdRunner{
 		ctx:           ctx,
 		sim:           sim,
 		contextNodeId: InvalidNodeId,
+		async:         async,
 	}
 	sim.SetCmdRunner(cr)
+	sim.SubscribeJobs(cr.onJobFinished)
 	return cr
 }
+
+// SetJsonOutput sets if commands output JSON instead of text by default.
+func (rt *CmdRunner) SetJsonOutput(jsonOutput bool) {
+	rt.jsonOutput = jsonOutput
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/progctx"
+)
+
+func TestStatsWindowInterval(t *testing.T) {
+	ctx := progctx.New(nil)
+	defer func() {
+		ctx.Cancel("test done")
+		ctx.Wait()
+	}()
+	sim, err := newTestSessionFactory(t)(ctx, testSessionBase)
+	assert.Nil(t, err)
+	rt := newCmdRunner(ctx, sim, nil)
+
+	run := func(cmd string) string {
+		buf := &bytes.Buffer{}
+		assert.Nil(t, rt.RunCommand(cmd, buf))
+		return buf.String()
+	}
+	for _, interval := range []string{"0", "-1", "0.0000001"} {
+		assert.Contains(t, run("stats window interval "+interval), "Error: invalid interval", interval)
+	}
+	assert.Equal(t, "Done\n", run("stats window interval 0.5"))
+	assert.Contains(t, run("stats window"), "interval=0.500s")
+}
+
+func TestEnergyScanTimeout(t *testing.T) {
+	ctx := progctx.New(nil)
+	defer func() {
+		ctx.Cancel("test done")
+		ctx.Wait()
+	}()
+	sim, err := newTestSessionFactory(t)(ctx, testSessionBase)
+	assert.Nil(t, err)
+	rt := newCmdRunner(ctx, sim, nil)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, rt.RunCommand("energyscan all timeout -1", buf))
+	assert.Contains(t, buf.String(), "Error: invalid timeout: -1s")
+}
@@ -37,29 +37,95 @@ import (
 // noinspection GoStructTag
 type Command struct {
 	Add                 *AddCmd                 `  @@` //nolint
+	Airtime             *AirtimeCmd             `| @@` //nolint
+	Alert               *AlertCmd               `| @@` //nolint
+	Align               *AlignCmd               `| @@` //nolint
+	Antenna             *AntennaCmd             `| @@` //nolint
+	AttachLog           *AttachLogCmd           `| @@` //nolint
+	Cancel              *CancelCmd              `| @@` //nolint
+	Capture             *CaptureCmd             `| @@` //nolint
+	CmdBudget           *CmdBudgetCmd           `| @@` //nolint
+	Coalesce            *CoalesceCmd            `| @@` //nolint
 	Coaps               *CoapsCmd               `| @@` //nolint
+	Compare             *CompareCmd             `| @@` //nolint
 	ConfigVisualization *ConfigVisualizationCmd `| @@` //nolint
 	CountDown           *CountDownCmd           `| @@` //nolint
 	Counters            *CountersCmd            `| @@` //nolint
+	Coverage            *CoverageCmd            `| @@` //nolint
 	Debug               *DebugCmd               `| @@` //nolint
 	Del                 *DelCmd                 `| @@` //nolint
 	DemoLegend          *DemoLegendCmd          `| @@` //nolint
+	Drift               *DriftCmd               `| @@` //nolint
+	Dup                 *DupCmd                 `| @@` //nolint
+	DutyCycle           *DutyCycleCmd           `| @@` //nolint
+	Election            *ElectionCmd            `| @@` //nolint
+	Energy              *EnergyCmd              `| @@` //nolint
+	EnergyScan          *EnergyScanCmd          `| @@` //nolint
 	Exit                *ExitCmd                `| @@` //nolint
+	Format              *FormatCmd              `| @@` //nolint
+	Frag                *FragCmd                `| @@` //nolint
+	Geo                 *GeoCmd                 `| @@` //nolint
 	Go                  *GoCmd                  `| @@` //nolint
+	Grid                *GridCmd                `| @@` //nolint
+	Health              *HealthCmd              `| @@` //nolint
+	History             *HistoryCmd             `| @@` //nolint
+	Jam                 *JamCmd                 `| @@` //nolint
+	Jobs                *JobsCmd                `| @@` //nolint
 	Joins               *JoinsCmd               `| @@` //nolint
+	Kpi                 *KpiCmd                 `| @@` //nolint
+	LinkMetrics         *LinkMetricsCmd         `| @@` //nolint
+	LinkStats           *LinkStatsCmd           `| @@` //nolint
+	Load                *LoadCmd                `| @@` //nolint
+	MacStats            *MacStatsCmd            `| @@` //nolint
 	Move                *Move                   `| @@` //nolint
+	NetData             *NetDataCmd             `| @@` //nolint
+	NetDiag             *NetDiagCmd             `| @@` //nolint
 	NetInfo             *NetInfoCmd             `| @@` //nolint
 	Node                *NodeCmd                `| @@` //nolint
 	Nodes               *NodesCmd               `| @@` //nolint
 	Partitions          *PartitionsCmd          `| @@` //nolint
+	Pause               *PauseCmd               `| @@` //nolint
+	Pcap                *PcapCmd                `| @@` //nolint
+	Power               *PowerCmd               `| @@` //nolint
 	Ping                *PingCmd                `| @@` //nolint
+	PingAll             *PingAllCmd             `| @@` //nolint
 	Pings               *PingsCmd               `| @@` //nolint
 	Plr                 *PlrCmd                 `| @@` //nolint
+	Provision           *ProvisionCmd           `| @@` //nolint
 	Radio               *RadioCmd               `| @@` //nolint
+	RadioModel          *RadioModelCmd          `| @@` //nolint
+	RadioParam          *RadioParamCmd          `| @@` //nolint
+	RadioRange          *RadioRangeCmd          `| @@` //nolint
+	Radios              *RadiosCmd              `| @@` //nolint
+	Range               *RangeCmd               `| @@` //nolint
+	Reorder             *ReorderCmd             `| @@` //nolint
+	Reset               *ResetCmd               `| @@` //nolint
+	Resume              *ResumeCmd              `| @@` //nolint
+	RfSim               *RfSimCmd               `| @@` //nolint
+	Roles               *RolesCmd               `| @@` //nolint
+	Save                *SaveCmd                `| @@` //nolint
 	Scan                *ScanCmd                `| @@` //nolint
+	Script              *ScriptCmd              `| @@` //nolint
+	Send                *SendCmd                `| @@` //nolint
+	Session             *SessionCmd             `| @@` //nolint
+	Site                *SiteCmd                `| @@` //nolint
 	Speed               *SpeedCmd               `| @@` //nolint
+	Srp                 *SrpCmd                 `| @@` //nolint
+	Stall               *StallCmd               `| @@` //nolint
+	Stats               *StatsCmd               `| @@` //nolint
+	Templates           *TemplatesCmd           `| @@` //nolint
+	Throttle            *ThrottleCmd            `| @@` //nolint
+	Summary             *SummaryCmd             `| @@` //nolint
+	Timeline            *TimelineCmd            `| @@` //nolint
 	Title               *TitleCmd               `| @@` //nolint
+	Top                 *TopCmd                 `| @@` //nolint
+	Unwatch             *UnwatchCmd             `| @@` //nolint
+	Upgrade             *UpgradeCmd             `| @@` //nolint
+	Upgrades            *UpgradesCmd            `| @@` //nolint
+	Wait                *WaitCmd                `| @@` //nolint
+	Watch               *WatchCmd               `| @@` //nolint
 	Web                 *WebCmd                 `| @@` //nolint
+	Zombies             *ZombiesCmd             `| @@` //nolint
 }
 
 // noinspection GoStructTag
@@ -143,6 +209,14 @@ type HopLimitFlag struct {
 	Val int `("hoplimit" | "hl") @Int` //nolint
 }
 
+// noinspection GoStructTag
+type ProvisionCmd struct {
+	Cmd  struct{}     `"provision"` //nolint
+	Node NodeSelector `@@`          //nolint
+	Kind string       `@"dataset"`  //nolint
+	File string       `@String`     //nolint
+}
+
 // noinspection GoStructTag
 type PingCmd struct {
 	Cmd      struct{}      `"ping"`   //nolint
@@ -156,6 +230,183 @@ type PingCmd struct {
 	HopLimit *HopLimitFlag `| @@ )*`  //nolint
 }
 
+// noinspection GoStructTag
+type SendCmd struct {
+	Cmd      struct{}      `"send"`                  //nolint
+	Report   *SendReport   `( @@`                    //nolint
+	Src      *NodeSelector `| @@`                    //nolint
+	Scope    *string       `( @( "link" | "realm" )` //nolint
+	Group    *Ipv6Address  `| @@ )`                  //nolint
+	DataSize *DataSizeFlag `( @@`                    //nolint
+	Count    *CountFlag    `| @@`                    //nolint
+	Interval *IntervalFlag `| @@ )* )`               //nolint
+}
+
+// noinspection GoStructTag
+type JobsCmd struct {
+	Cmd  struct{}  `"jobs"` //nolint
+	Json *JsonFlag `[ @@ ]` //nolint
+}
+
+// noinspection GoStructTag
+type CancelCmd struct {
+	Cmd struct{} `"cancel"` //nolint
+	Job int      `@Int`     //nolint
+}
+
+// noinspection GoStructTag
+type SendReport struct {
+	Dummy struct{}   `"report"` //nolint
+	Reset *ResetFlag `[ @@ ]`   //nolint
+}
+
+// noinspection GoStructTag
+type FragCmd struct {
+	Cmd   struct{}       `"frag"` //nolint
+	Send  *FragSendCmd   `( @@`   //nolint
+	Stats *FragStatsFlag `| @@ )` //nolint
+}
+
+// noinspection GoStructTag
+type FragSendCmd struct {
+	Dummy    struct{}      `"send"`  //nolint
+	Src      NodeSelector  `@@`      //nolint
+	Dst      NodeSelector  `@@`      //nolint
+	DataSize *DataSizeFlag `( @@`    //nolint
+	Count    *CountFlag    `| @@`    //nolint
+	Interval *IntervalFlag `| @@ )*` //nolint
+}
+
+// noinspection GoStructTag
+type FragStatsFlag struct {
+	Dummy struct{}   `"stats"` //nolint
+	Reset *ResetFlag `[ @@ ]`  //nolint
+}
+
+// noinspection GoStructTag
+type PingAllCmd struct {
+	Cmd      struct{}       `"pingall"` //nolint
+	Nodes    []NodeSelector `( @@ )*`   //nolint
+	DataSize *DataSizeFlag  `( @@`      //nolint
+	Count    *CountFlag     `| @@`      //nolint
+	Interval *IntervalFlag  `| @@ )*`   //nolint
+}
+
+// noinspection GoStructTag
+type DriftCmd struct {
+	Cmd      struct{}           `"drift"`                              //nolint
+	Analysis *DriftAnalysisFlag `( @@`                                 //nolint
+	Ppm      *float64           `| [ @( ["-"] (Int | Float) ) ["ppm"]` //nolint
+	Nodes    []NodeRange        `    [ "nodes" ( @@ )+ ] ] )`          //nolint
+}
+
+// noinspection GoStructTag
+type DriftAnalysisFlag struct {
+	Cmd      struct{} `"analysis"`                              //nolint
+	Accuracy *float64 `[ "accuracy" @( Int | Float ) ["ppm"] ]` //nolint
+}
+
+// noinspection GoStructTag
+type CoverageCmd struct {
+	Cmd   struct{}           `"coverage"` //nolint
+	Merge *CoverageMergeFlag `[ @@ ]`     //nolint
+}
+
+// noinspection GoStructTag
+type CoverageMergeFlag struct {
+	Dummy  struct{} `"merge"`     //nolint
+	Output *string  `[ @String ]` //nolint
+}
+
+// noinspection GoStructTag
+type DutyCycleCmd struct {
+	Cmd     struct{}    `"dutycycle"`                               //nolint
+	Off     *OffFlag    `[ ( @@`                                    //nolint
+	Percent *float64    `  | @( Int | Float ) "%"`                  //nolint
+	Window  *float64    `    [ "window" @( Int | Float ) ["s"] ] )` //nolint
+	Nodes   []NodeRange `  [ "nodes" ( @@ )+ ] ]`                   //nolint
+	Json    *JsonFlag   `[ @@ ]`                                    //nolint
+}
+
+// noinspection GoStructTag
+type PowerCmd struct {
+	Cmd    struct{}         `"power"`                 //nolint
+	Source *PowerSourceFlag `[ @@`                    //nolint
+	Nodes  []NodeRange      `  [ "nodes" ( @@ )+ ] ]` //nolint
+	Json   *JsonFlag        `[ @@ ]`                  //nolint
+}
+
+// noinspection GoStructTag
+type PowerSourceFlag struct {
+	Mains   *MainsFlag   `  @@` //nolint
+	Battery *BatteryFlag `| @@` //nolint
+	Harvest *HarvestFlag `| @@` //nolint
+}
+
+// noinspection GoStructTag
+type MainsFlag struct {
+	Dummy struct{} `"mains"` //nolint
+}
+
+// noinspection GoStructTag
+type BatteryFlag struct {
+	Cmd         struct{} `"battery"`        //nolint
+	CapacityMwh float64  `@( Int | Float )` //nolint
+}
+
+// noinspection GoStructTag
+type HarvestFlag struct {
+	Cmd         struct{} `"harvest"`                         //nolint
+	CapacityMwh float64  `@( Int | Float )`                  //nolint
+	PowerMw     float64  `@( Int | Float )`                  //nolint
+	Period      *float64 `[ "period" @( Int | Float ) ["s"]` //nolint
+	Duty        float64  `  "duty" @( Int | Float ) "%" ]`   //nolint
+}
+
+// noinspection GoStructTag
+type EnergyCmd struct {
+	Cmd   struct{}         `"energy"` //nolint
+	Reset *ResetFlag       `[ @@`     //nolint
+	Model *EnergyModelFlag `| @@`     //nolint
+	Json  *JsonFlag        `| @@ ]`   //nolint
+}
+
+// noinspection GoStructTag
+type EnergyModelFlag struct {
+	Cmd   struct{} `"model"`                      //nolint
+	Tx    *float64 `[ "tx" @( Int | Float ) ]`    //nolint
+	Rx    *float64 `[ "rx" @( Int | Float ) ]`    //nolint
+	Sleep *float64 `[ "sleep" @( Int | Float ) ]` //nolint
+}
+
+// noinspection GoStructTag
+type RfSimCmd struct {
+	Cmd     struct{}      `"rfsim" (`                            //nolint
+	Raw     *RfSimRawFlag `  @@`                                 //nolint
+	Targets []NodeTarget  `| ( @@ )+`                            //nolint
+	Param   *string       `  [ @Ident`                           //nolint
+	Val     *float64      `    [ @( ["-"] (Int | Float) ) ] ] )` //nolint
+}
+
+// noinspection GoStructTag
+type RfSimRawFlag struct {
+	Cmd  struct{}     `"raw"`                              //nolint
+	Node NodeSelector `@@`                                 //nolint
+	Data string       `@( String | Int | Float | Ident )+` //nolint
+}
+
+// noinspection GoStructTag
+type NodeTarget struct {
+	Group *string    `  @( "all" | "routers" | "feds" | "meds" | "seds" )` //nolint
+	Range *NodeRange `| @@`                                                //nolint
+}
+
+// noinspection GoStructTag
+type NodeRange struct {
+	From NodeId  `@Int`         //nolint
+	To   *NodeId `[ "-" @Int ]` //nolint
+}
+
 // noinspection GoStructTag
 type NetInfoCmd struct {
 	Cmd     struct{}     `"netinfo" (`         //nolint
@@ -186,7 +437,15 @@ type ConfigVisualizationCmd struct {
 	UnicastMessage   *CVUnicastMessage   `| @@`    //nolint
 	AckMessage       *CVAckMessage       `| @@`    //nolint
 	RouterTable      *CVRouterTable      `| @@`    //nolint
-	ChildTable       *CVChildTable       `| @@ )*` //nolint
+	ChildTable       *CVChildTable       `| @@`    //nolint
+	Nodes            *CVNodes            `| @@ )*` //nolint
+}
+
+// noinspection GoStructTag
+type CVNodes struct {
+	Flag  struct{}    `"nodes"`     //nolint
+	All   *AllFlag    `( @@`        //nolint
+	Nodes []NodeRange `| ( @@ )+ )` //nolint
 }
 
 // noinspection GoStructTag
@@ -226,6 +485,35 @@ type CountDownCmd struct {
 	Text    *string  `[ @String ]` //nolint
 }
 
+// noinspection GoStructTag
+type LoadCmd struct {
+	Cmd    struct{}        `"load"`                                     //nolint
+	File   string          `@String`                                    //nolint
+	Add    *AddFlag        `( @@`                                       //nolint
+	Offset *LoadOffsetFlag `| @@`                                       //nolint
+	Scale  *float64        `| "scale" (@Int|@Float)`                    //nolint
+	Rotate *float64        `| "rotate" @( ["-"] (Int|Float) )`          //nolint
+	Ids    *string         `| "ids" @( "keep" | "shift" | "renumber" )` //nolint
+	Pan    *string         `| "pan" @( "sim" | "file" | "strict" ) )*`  //nolint
+}
+
+// noinspection GoStructTag
+type AddFlag struct {
+	Dummy struct{} `"add"` //nolint
+}
+
+// noinspection GoStructTag
+type LoadOffsetFlag struct {
+	X int `"offset" @( ["-"] Int )` //nolint
+	Y int `@( ["-"] Int )`          //nolint
+}
+
+// noinspection GoStructTag
+type SaveCmd struct {
+	Cmd  struct{} `"save"`  //nolint
+	File string   `@String` //nolint
+}
+
 // noinspection GoStructTag
 type ScanCmd struct {
 	Cmd  struct{}     `"scan"` //nolint
@@ -234,9 +522,10 @@ type ScanCmd struct {
 
 // noinspection GoStructTag
 type SpeedCmd struct {
-	Cmd   struct{}      `"speed"`               //nolint
-	Max   *MaxSpeedFlag `( @@`                  //nolint
-	Speed *float64      `| [ (@Int|@Float) ] )` //nolint
+	Cmd   struct{}       `"speed"`               //nolint
+	Auto  *AutoSpeedFlag `( @@`                  //nolint
+	Max   *MaxSpeedFlag  `| @@`                  //nolint
+	Speed *float64       `| [ (@Int|@Float) ] )` //nolint
 }
 
 // noinspection GoStructTag
@@ -257,7 +546,69 @@ type AddCmd struct {
 	Id         *AddNodeId      `| @@`                 //nolint
 	RadioRange *RadioRangeFlag `| @@`                 //nolint
 	Restore    *RestoreFlag    `| @@`                 //nolint
-	Executable *ExecutableFlag `| @@ )*`              //nolint
+	Executable *ExecutableFlag `| @@`                 //nolint
+	At         *AddAtFlag      `| @@`                 //nolint
+	Poll       *AddPollFlag    `| @@`                 //nolint
+	Geo        *GeoPosFlag     `| @@`                 //nolint
+	Script     *ScriptFileFlag `| @@`                 //nolint
+	Vars       []ScriptVarFlag `| @@`                 //nolint
+	BootDelay  *BootDelayFlag  `| @@`                 //nolint
+	BootCrash  *BootCrashFlag  `| @@`                 //nolint
+	CpuFactor  *CpuFactorFlag  `| @@`                 //nolint
+	Like       *AddLikeFlag    `| @@ )*`              //nolint
+}
+
+// noinspection GoStructTag
+type TemplatesCmd struct {
+	Cmd  struct{} `"templates"`        //nolint
+	Load *string  `[ "load" @String ]` //nolint
+}
+
+// noinspection GoStructTag
+type AddLikeFlag struct {
+	Node NodeSelector `"like" @@` //nolint
+}
+
+// noinspection GoStructTag
+type BootDelayFlag struct {
+	Delay float64 `"bootdelay" (@Int|@Float)` //nolint
+	Unit  string  `[ @( "s" | "ms" ) ]`       //nolint
+}
+
+// noinspection GoStructTag
+type BootCrashFlag struct {
+	Prob float64 `"bootcrash" (@Int|@Float)` //nolint
+}
+
+// noinspection GoStructTag
+type CpuFactorFlag struct {
+	Factor float64 `"cpufactor" (@Int|@Float)` //nolint
+}
+
+// noinspection GoStructTag
+type ScriptFileFlag struct {
+	Path string `"script" @String` //nolint
+}
+
+// noinspection GoStructTag
+type ScriptVarFlag struct {
+	Name  string `"var" @Ident`                              //nolint
+	Value string `@( String | Ident | ["-"] (Int | Float) )` //nolint
+}
+
+// noinspection GoStructTag
+type CoalesceCmd struct {
+	Cmd    struct{} `"coalesce"`        //nolint
+	Window *int     `[ ( @Int [ "us" ]` //nolint
+	Off    *OffFlag `| @@ ) ]`          //nolint
+}
+
+// noinspection GoStructTag
+type ScriptCmd struct {
+	Cmd  struct{}       `"script"`  //nolint
+	File *string        `[ @String` //nolint
+	Off  *OffFlag       `| @@`      //nolint
+	Var  *ScriptVarFlag `| @@ ]`    //nolint
 }
 
 // noinspection GoStructTag
@@ -270,12 +621,30 @@ type RestoreFlag struct {
 	Dummy struct{} `"restore"` //nolint
 }
 
+// noinspection GoStructTag
+type AddPollFlag struct {
+	Period float64 `"poll" (@Int|@Float)` //nolint
+	Unit   string  `[ @( "s" | "ms" ) ]`  //nolint
+}
+
+// noinspection GoStructTag
+type AddAtFlag struct {
+	Seconds float64 `"at" (@Int|@Float) ["s"]` //nolint
+}
+
 // noinspection GoStructTag
 type ExecutableFlag struct {
 	Dummy struct{} `"exe"`   //nolint
 	Path  string   `@String` //nolint
 }
 
+// noinspection GoStructTag
+type AutoSpeedFlag struct {
+	Dummy  struct{} `"auto"`                  //nolint
+	Off    *OffFlag `[ @@`                    //nolint
+	Target *float64 `| (@Int|@Float) ["%"] ]` //nolint
+}
+
 // noinspection MaxSpeedFlag
 type MaxSpeedFlag struct {
 	Dummy struct{} `( "max" | "inf")` //nolint
@@ -283,7 +652,7 @@ type MaxSpeedFlag struct {
 
 // noinspection GoStructTag
 type NodeType struct {
-	Val string `@("router"|"fed"|"med"|"sed")` //nolint
+	Val string `@("router"|"fed"|"med"|"sed"|Ident)` //nolint
 }
 
 // noinspection GoStructTag
@@ -291,6 +660,137 @@ type AddNodeId struct {
 	Val int `"id" @Int` //nolint
 }
 
+// noinspection GoStructTag
+type AntennaCmd struct {
+	Cmd    struct{}       `"antenna"`        //nolint
+	Load   *string        `( "load" @String` //nolint
+	Node   *NodeSelector  `| [ @@`           //nolint
+	Sector *AntennaSector `    [ @@`         //nolint
+	Off    *OffFlag       `    | @@ ] ]`     //nolint
+	Yaml   *YamlFlag      `  [ @@ ] )?`      //nolint
+}
+
+// noinspection GoStructTag
+type AntennaSector struct {
+	Azimuth     float64  `"sector" @( ["-"] (Int | Float) )`   //nolint
+	BeamWidth   float64  `@(Int | Float)`                      //nolint
+	GainDbi     *float64 `[ "gain" @( ["-"] (Int | Float) ) ]` //nolint
+	BackGainDbi *float64 `[ "back" @( ["-"] (Int | Float) ) ]` //nolint
+}
+
+// noinspection GoStructTag
+type CompareCmd struct {
+	Cmd   struct{} `"compare"`                      //nolint
+	File  string   `@String`                        //nolint
+	Time  *float64 `[ "time" (@Int|@Float) ["s"] ]` //nolint
+	Count *int     `[ "count" @Int ]`               //nolint
+}
+
+// noinspection GoStructTag
+type AlertCmd struct {
+	Cmd    struct{}         `"alert"`      //nolint
+	Add    *AlertAddFlag    `[ @@`         //nolint
+	Del    *int             `| "del" @Int` //nolint
+	Events *AlertEventsFlag `| @@`         //nolint
+	Reset  *ResetFlag       `| @@ ]`       //nolint
+	Json   *JsonFlag        `[ @@ ]`       //nolint
+}
+
+// noinspection GoStructTag
+type AlertAddFlag struct {
+	Dummy     struct{} `"add"`                                       //nolint
+	Condition string   `@( "detached" | "partitions" | "pingloss" )` //nolint
+	Threshold *float64 `[ (@Int|@Float) ["%"] ]`                     //nolint
+	For       *float64 `[ "for" (@Int|@Float) ["s"] ]`               //nolint
+	Webhook   *string  `[ "webhook" @String ]`                       //nolint
+}
+
+// noinspection GoStructTag
+type AlertEventsFlag struct {
+	Dummy struct{} `"events"` //nolint
+}
+
+// noinspection GoStructTag
+type AttachLogCmd struct {
+	Cmd   struct{}      `"attachlog"` //nolint
+	Node  *NodeSelector `( @@`        //nolint
+	Reset *ResetFlag    `| @@ )`      //nolint
+	Json  *JsonFlag     `[ @@ ]`      //nolint
+}
+
+// noinspection GoStructTag
+type CmdBudgetCmd struct {
+	Cmd    struct{}   `"cmdbudget"`                //nolint
+	Off    *OffFlag   `[ ( @@`                     //nolint
+	Budget *float64   `  | @( Int | Float ) ["s"]` //nolint
+	Costs  *CostsFlag `  | @@`                     //nolint
+	Reset  *ResetFlag `  | @@ ) ]`                 //nolint
+}
+
+// noinspection GoStructTag
+type CostsFlag struct {
+	Dummy struct{}  `"costs"` //nolint
+	Json  *JsonFlag `[ @@ ]`  //nolint
+}
+
+// noinspection GoStructTag
+type AirtimeCmd struct {
+	Cmd   struct{}   `"airtime"` //nolint
+	Reset *ResetFlag `( @@`      //nolint
+	Yaml  *YamlFlag  `| @@ )?`   //nolint
+}
+
+// noinspection GoStructTag
+type ResetFlag struct {
+	Dummy struct{} `"reset"` //nolint
+}
+
+// noinspection GoStructTag
+type KpiCmd struct {
+	Cmd   struct{}   `"kpi"`               //nolint
+	Start *StartFlag `( @@`                //nolint
+	Stop  *StopFlag  `| @@`                //nolint
+	Save  *string    `| "save" @String )?` //nolint
+}
+
+// noinspection GoStructTag
+type SessionCmd struct {
+	Cmd struct{}        `"session"` //nolint
+	New *NewSessionFlag `[ @@`      //nolint
+	Del *DelSessionFlag `| @@ ]`    //nolint
+	Id  *int            `[ @Int ]`  //nolint
+}
+
+// noinspection GoStructTag
+type NewSessionFlag struct {
+	Dummy struct{} `"new"` //nolint
+}
+
+// noinspection GoStructTag
+type DelSessionFlag struct {
+	Dummy struct{} `"del"` //nolint
+}
+
+// noinspection GoStructTag
+type StartFlag struct {
+	Dummy struct{} `"start"` //nolint
+}
+
+// noinspection GoStructTag
+type StopFlag struct {
+	Dummy struct{} `"stop"` //nolint
+}
+
+// noinspection GoStructTag
+type YamlFlag struct {
+	Dummy struct{} `"yaml"` //nolint
+}
+
+// noinspection GoStructTag
+type JsonFlag struct {
+	Dummy struct{} `"json"` //nolint
+}
+
 // noinspection GoStructTag
 type CoapsCmd struct {
 	Cmd    struct{}    `"coaps"` //nolint
@@ -322,6 +822,11 @@ type ExitCmd struct {
 	Cmd struct{} `"exit"` //nolint
 }
 
+// noinspection GoStructTag
+type ResetCmd struct {
+	Cmd struct{} `"reset" "all"` //nolint
+}
+
 // noinspection GoStructTag
 type WebCmd struct {
 	Cmd struct{} `"web"` //nolint
@@ -336,6 +841,277 @@ type RadioCmd struct {
 	FailTime *FailTimeParams `| @@ )`  //nolint
 }
 
+// noinspection GoStructTag
+type RadioModelCmd struct {
+	Cmd   struct{} `"radiomodel"` //nolint
+	Model *string  `[ @Ident ]`   //nolint
+}
+
+// noinspection GoStructTag
+type CaptureCmd struct {
+	Cmd    struct{} `"capture"`                                           //nolint
+	Preset *string  `[ @( "ideal" | "at86rf233" | "cc2420" | "efr32" ) ]` //nolint
+}
+
+// noinspection GoStructTag
+type RadioParamCmd struct {
+	Cmd     struct{}  `"radioparam"`                     //nolint
+	Json    *JsonFlag `[ @@`                             //nolint
+	Name    *string   `| @Ident`                         //nolint
+	Channel *string   `  [ @Ident ]`                     //nolint
+	Val     *float64  `  [ @( ["-"] (Int | Float) ) ] ]` //nolint
+}
+
+// noinspection GoStructTag
+type ElectionCmd struct {
+	Cmd     struct{}             `"election"` //nolint
+	Count   *CountFlag           `( @@`       //nolint
+	Timeout *ElectionTimeoutFlag `| @@`       //nolint
+	Settle  *ElectionSettleFlag  `| @@ )*`    //nolint
+}
+
+// noinspection GoStructTag
+type EnergyScanCmd struct {
+	Cmd      struct{}  `"energyscan" "all"`                         //nolint
+	Duration *int      `[ "duration" @Int ["ms"] ]`                 //nolint
+	Timeout  *float64  `[ "timeout" @( ["-"] (Int|Float) ) ["s"] ]` //nolint
+	Json     *JsonFlag `[ @@ ]`                                     //nolint
+}
+
+// noinspection GoStructTag
+type ElectionTimeoutFlag struct {
+	Val float64 `"timeout" (@Int|@Float) ["s"]` //nolint
+}
+
+// noinspection GoStructTag
+type ElectionSettleFlag struct {
+	Val float64 `"settle" (@Int|@Float) ["s"]` //nolint
+}
+
+// noinspection GoStructTag
+type TimelineCmd struct {
+	Cmd   struct{}   `"timeline"`       //nolint
+	Save  *string    `[ "save" @String` //nolint
+	Reset *ResetFlag `| @@ ]`           //nolint
+}
+
+// noinspection GoStructTag
+type RolesCmd struct {
+	Cmd   struct{}   `"roles"` //nolint
+	Reset *ResetFlag `[ @@ ]`  //nolint
+}
+
+// noinspection GoStructTag
+type LinkStatsCmd struct {
+	Cmd   struct{}      `"linkstats"` //nolint
+	Reset *ResetFlag    `( @@`        //nolint
+	Src   *NodeSelector `| @@`        //nolint
+	Dst   *NodeSelector `  @@ )`      //nolint
+}
+
+// noinspection GoStructTag
+type MacStatsCmd struct {
+	Cmd   struct{}   `"macstats"` //nolint
+	Reset *ResetFlag `[ @@`       //nolint
+	Json  *JsonFlag  `| @@ ]`     //nolint
+}
+
+// noinspection GoStructTag
+type LinkMetricsCmd struct {
+	Cmd   struct{}              `"linkmetrics"` //nolint
+	Start *LinkMetricsStartFlag `[ ( @@`        //nolint
+	Stop  *LinkMetricsStopFlag  `| @@`          //nolint
+	Reset *ResetFlag            `| @@`          //nolint
+	Src   *NodeSelector         `| @@`          //nolint
+	Dst   *NodeSelector         `  @@ ) ]`      //nolint
+	Json  *JsonFlag             `[ @@ ]`        //nolint
+}
+
+// noinspection GoStructTag
+type LinkMetricsStartFlag struct {
+	Dummy    struct{}     `"start"`                            //nolint
+	Src      NodeSelector `@@`                                 //nolint
+	Dst      NodeSelector `@@`                                 //nolint
+	Interval *float64     `[ "interval" (@Int|@Float) ["s"] ]` //nolint
+}
+
+// noinspection GoStructTag
+type LinkMetricsStopFlag struct {
+	Dummy struct{}      `"stop"` //nolint
+	Src   *NodeSelector `[ @@`   //nolint
+	Dst   *NodeSelector `  @@ ]` //nolint
+}
+
+// noinspection GoStructTag
+type WaitCmd struct {
+	Cmd        struct{}      `"wait"`                            //nolint
+	Node       *WaitNodeCond `( @@`                              //nolint
+	Partitions *int          `| "partitions" @Int )`             //nolint
+	Timeout    *float64      `[ "timeout" (@Int|@Float) ["s"] ]` //nolint
+}
+
+// noinspection GoStructTag
+type WaitNodeCond struct {
+	Node  NodeSelector `"node" @@`                                                            //nolint
+	State string       `"state" @( "disabled" | "detached" | "child" | "router" | "leader" )` //nolint
+}
+
+// noinspection GoStructTag
+type StallCmd struct {
+	Cmd       struct{}        `"stall"`                         //nolint
+	Timeout   *float64        `( "timeout" (@Int|@Float) ["s"]` //nolint
+	ForceFail *StallForceFail `| @@ )*`                         //nolint
+}
+
+// noinspection GoStructTag
+type SiteCmd struct {
+	Cmd      struct{}       `"site"`   //nolint
+	Backbone *BackboneFlag  `[ ( @@`   //nolint
+	Nodes    *SiteNodesFlag `| @@ ) ]` //nolint
+}
+
+// noinspection GoStructTag
+type BackboneFlag struct {
+	Dummy   struct{} `"backbone"`                       //nolint
+	Latency *float64 `( "latency" (@Int|@Float) ["ms"]` //nolint
+	Jitter  *float64 `| "jitter" (@Int|@Float) ["ms"]`  //nolint
+	Loss    *float64 `| "loss" (@Int|@Float) ["%"] )*`  //nolint
+}
+
+// noinspection GoStructTag
+type SiteNodesFlag struct {
+	Off   *OffFlag       `( @@`       //nolint
+	Name  *string        `| @Ident )` //nolint
+	Nodes []NodeSelector `( @@ )+`    //nolint
+}
+
+// noinspection GoStructTag
+type DupCmd struct {
+	Cmd   struct{}     `"dup"`                            //nolint
+	Link  *DupLinkFlag `[ @@ ]`                           //nolint
+	Off   *OffFlag     `[ ( @@`                           //nolint
+	Ratio *float64     `  | (@Int|@Float) ["%"] ) ]`      //nolint
+	Delay *float64     `[ "delay" (@Int|@Float) ["ms"] ]` //nolint
+}
+
+// noinspection GoStructTag
+type DupLinkFlag struct {
+	Dummy struct{}     `"link"` //nolint
+	Src   NodeSelector `@@`     //nolint
+	Dst   NodeSelector `@@`     //nolint
+}
+
+// noinspection GoStructTag
+type ReorderCmd struct {
+	Cmd   struct{} `"reorder"`                       //nolint
+	Off   *OffFlag `[ ( @@`                          //nolint
+	Ratio *float64 `  | (@Int|@Float) ["%"] ) ]`     //nolint
+	Hold  *float64 `[ "hold" (@Int|@Float) ["ms"] ]` //nolint
+}
+
+// noinspection GoStructTag
+type StallForceFail struct {
+	Dummy   struct{}    `"forcefail"` //nolint
+	OnOrOff OnOrOffFlag `@@`          //nolint
+}
+
+// noinspection GoStructTag
+type HistoryCmd struct {
+	Cmd   struct{}     `"history"`           //nolint
+	Node  NodeSelector `@@`                  //nolint
+	Start *float64     `[ (@Int|@Float)`     //nolint
+	End   *float64     `[ (@Int|@Float) ] ]` //nolint
+	Json  *JsonFlag    `[ @@ ]`              //nolint
+}
+
+// noinspection GoStructTag
+type HealthCmd struct {
+	Cmd    struct{}          `"health"` //nolint
+	Policy *HealthPolicyFlag `[ @@`     //nolint
+	Json   *JsonFlag         `| @@ ]`   //nolint
+}
+
+// noinspection GoStructTag
+type ZombiesCmd struct {
+	Cmd    struct{}          `"zombies"` //nolint
+	Policy *ZombiePolicyFlag `[ @@`      //nolint
+	Json   *JsonFlag         `| @@ ]`    //nolint
+}
+
+// noinspection GoStructTag
+type ZombiePolicyFlag struct {
+	Dummy   struct{}     `"policy"`                           //nolint
+	Timeout *float64     `( "timeout" @( Int | Float ) ["s"]` //nolint
+	Restart *OnOrOffFlag `| "restart" @@ )*`                  //nolint
+}
+
+// noinspection GoStructTag
+type HealthPolicyFlag struct {
+	Dummy       struct{} `"policy"`                    //nolint
+	Policy      *string  `[ @( "restart" | "delete" )` //nolint
+	MaxRestarts *int     `  [ "max" @Int ] ]`          //nolint
+}
+
+// noinspection GoStructTag
+type NetDataCmd struct {
+	Cmd  struct{}      `"netdata"` //nolint
+	Node *NodeSelector `[ @@ ]`    //nolint
+	Json *JsonFlag     `[ @@ ]`    //nolint
+}
+
+// noinspection GoStructTag
+type NetDiagCmd struct {
+	Cmd     struct{}     `"netdiag" "sweep"`                 //nolint
+	Node    NodeSelector `@@`                                //nolint
+	Tlvs    []int        `[ "tlv" ( @Int )+ ]`               //nolint
+	Timeout *float64     `[ "timeout" (@Int|@Float) ["s"] ]` //nolint
+	Json    *JsonFlag    `[ @@ ]`                            //nolint
+}
+
+// noinspection GoStructTag
+type RadioRangeCmd struct {
+	Cmd     struct{} `"radiorange"`                              //nolint
+	Edge    *float64 `[ "edge" @( ["-"] (Int | Float) ) ["dBm"]` //nolint
+	Channel *string  `  [ @Ident ] ]`                            //nolint
+}
+
+// noinspection GoStructTag
+type PauseCmd struct {
+	Cmd     struct{}       `"pause"` //nolint
+	Nodes   []NodeSelector `( @@ )+` //nolint
+	SigStop *SigStopFlag   `[ @@ ]`  //nolint
+}
+
+// noinspection GoStructTag
+type SigStopFlag struct {
+	Dummy struct{} `"sigstop"` //nolint
+}
+
+// noinspection GoStructTag
+type WatchCmd struct {
+	Cmd   struct{}        `"watch"` //nolint
+	Nodes []NodeSelector  `( @@ )*` //nolint
+	Radio *WatchRadioFlag `[ @@ ]`  //nolint
+}
+
+// noinspection GoStructTag
+type WatchRadioFlag struct {
+	Dummy struct{} `"radio"`                       //nolint
+	Level string   `@( "off" | "info" | "trace" )` //nolint
+}
+
+// noinspection GoStructTag
+type UnwatchCmd struct {
+	Cmd   struct{}       `"unwatch"` //nolint
+	Nodes []NodeSelector `( @@ )+`   //nolint
+}
+
+// noinspection GoStructTag
+type ResumeCmd struct {
+	Cmd   struct{}       `"resume"` //nolint
+	Nodes []NodeSelector `( @@ )+`  //nolint
+}
+
 // noinspection GoStructTag
 type OnFlag struct {
 	Dummy struct{} `"on"` //nolint
@@ -370,41 +1146,222 @@ type YesOrNoFlag struct {
 
 // noinspection GoStructTag
 type Move struct {
-	Cmd    struct{}     `"move"` //nolint
-	Target NodeSelector `@@`     //nolint
-	X      int          `@Int`   //nolint
-	Y      int          `@Int`   //nolint
+	Cmd    struct{}        `"move"`      //nolint
+	Target NodeSelector    `@@`          //nolint
+	X      int             `( @Int`      //nolint
+	Y      int             `@Int`        //nolint
+	Geo    *GeoPosFlag     `| @@`        //nolint
+	Deltas []MoveDeltaFlag `| ( @@ )+ )` //nolint
+}
+
+// noinspection GoStructTag
+type MoveDeltaFlag struct {
+	Axis  string `@( "dx" | "dy" )`     //nolint
+	Delta int    `@( ["-" | "+"] Int )` //nolint
+}
+
+// noinspection GoStructTag
+type AlignCmd struct {
+	Cmd       struct{}       `"align"`                        //nolint
+	Nodes     []NodeSelector `( @@ )+`                        //nolint
+	Direction string         `@( "horizontal" | "vertical" )` //nolint
+	Spacing   *int           `[ "spacing" @Int ]`             //nolint
+}
+
+// noinspection GoStructTag
+type GridCmd struct {
+	Cmd  struct{}      `"grid"` //nolint
+	Snap *GridSnapFlag `[ @@ ]` //nolint
+}
+
+// noinspection GoStructTag
+type GridSnapFlag struct {
+	Dummy struct{} `"snap"`   //nolint
+	Off   *OffFlag `( @@`     //nolint
+	Size  *int     `| @Int )` //nolint
+}
+
+// noinspection GoStructTag
+type GeoPosFlag struct {
+	Dummy struct{} `"geo"`                    //nolint
+	Lat   float64  `@( ["-"] (Int | Float) )` //nolint
+	Lon   float64  `@( ["-"] (Int | Float) )` //nolint
+}
+
+// noinspection GoStructTag
+type GeoCmd struct {
+	Cmd    struct{}       `"geo"`   //nolint
+	Origin *GeoOriginFlag `( @@`    //nolint
+	Off    *OffFlag       `| @@ )?` //nolint
+}
+
+// noinspection GoStructTag
+type GeoOriginFlag struct {
+	Dummy struct{} `"origin"`                           //nolint
+	Lat   float64  `@( ["-"] (Int | Float) )`           //nolint
+	Lon   float64  `@( ["-"] (Int | Float) )`           //nolint
+	Alt   *float64 `[ "alt" @( ["-"] (Int | Float) ) ]` //nolint
+	Scale *float64 `[ "scale" (@Int|@Float) ]`          //nolint
 }
 
 // noinspection GoStructTag
 type NodesCmd struct {
-	Cmd struct{} `"nodes"` //nolint
+	Cmd    struct{}       `"nodes"` //nolint
+	Exec   *NodesExecFlag `( @@`    //nolint
+	Output OutputFlags    `| @@ )`  //nolint
+}
+
+// noinspection GoStructTag
+type NodesExecFlag struct {
+	Dummy   struct{}       `"exec"`  //nolint
+	Nodes   []NodeSelector `( @@ )*` //nolint
+	Command string         `@String` //nolint
+}
+
+// noinspection GoStructTag
+type PcapCmd struct {
+	Cmd   struct{}    `"pcap"`         //nolint
+	All   *AllFlag    `[ "nodes" ( @@` //nolint
+	Nodes []NodeRange `| ( @@ )+ ) ]`  //nolint
+}
+
+// noinspection GoStructTag
+type AllFlag struct {
+	Dummy struct{} `"all"` //nolint
 }
 
 // noinspection GoStructTag
 type PartitionsCmd struct {
-	Cmd struct{} `( "partitions" | "pts")` //nolint
+	Cmd    struct{}    `( "partitions" | "pts")` //nolint
+	Output OutputFlags `@@`                      //nolint
 }
 
 // noinspection GoStructTag
 type PingsCmd struct {
-	Cmd struct{} `"pings"` //nolint
+	Cmd    struct{}    `"pings"` //nolint
+	Output OutputFlags `@@`      //nolint
+}
+
+// noinspection GoStructTag
+type OutputFlags struct {
+	Filters []OutputFilter   `( @@`                               //nolint
+	Columns []string         `| "columns" @Ident ( "," @Ident )*` //nolint
+	Json    *JsonFlag        `| @@`                               //nolint
+	Count   *OutputCountFlag `| @@ )*`                            //nolint
+}
+
+// noinspection GoStructTag
+type OutputFilter struct {
+	Key   string `@Ident`                                    //nolint
+	Op    string `@( "=" | "~" | "!" "=" )`                  //nolint
+	Value string `@( String | Ident | ["-"] (Int | Float) )` //nolint
+}
+
+// noinspection GoStructTag
+type OutputCountFlag struct {
+	Dummy struct{} `"count"` //nolint
+}
+
+// noinspection GoStructTag
+type RangeCmd struct {
+	Cmd    struct{}         `"range"`    //nolint
+	Model  *RangeModelFlag  `( @@`       //nolint
+	Events *RangeEventsFlag `| @@`       //nolint
+	Src    *NodeSelector    `| @@`       //nolint
+	Dst    *NodeSelector    `  @@`       //nolint
+	Count  *CountFlag       `  [ @@ ] )` //nolint
+}
+
+// noinspection GoStructTag
+type RangeModelFlag struct {
+	Dummy  struct{} `"model"`                           //nolint
+	Bias   *float64 `( "bias" @( ["-"] (Int | Float) )` //nolint
+	StdDev *float64 `| "stddev" (@Int|@Float) )*`       //nolint
+}
+
+// noinspection GoStructTag
+type RangeEventsFlag struct {
+	Dummy   struct{}       `"events"` //nolint
+	Nodes   []NodeSelector `( @@ )*`  //nolint
+	OnOrOff *OnOrOffFlag   `[ @@ ]`   //nolint
+}
+
+// noinspection GoStructTag
+type JamCmd struct {
+	Cmd       struct{}          `"jam"`       //nolint
+	Node      *NodeSelector     `[ @@`        //nolint
+	Off       *OffFlag          `  ( @@`      //nolint
+	DstShort  *JamDstShortFlag  `  | @@`      //nolint
+	FrameType *JamFrameTypeFlag `  | @@ )* ]` //nolint
+}
+
+// noinspection GoStructTag
+type JamDstShortFlag struct {
+	Val uint16 `"dst" @Int` //nolint
+}
+
+// noinspection GoStructTag
+type JamFrameTypeFlag struct {
+	Val string `"type" @("beacon"|"data"|"ack"|"cmd")` //nolint
+}
+
+// noinspection GoStructTag
+type UpgradeCmd struct {
+	Cmd        struct{}     `"upgrade"` //nolint
+	Node       NodeSelector `@@`        //nolint
+	Executable string       `@String`   //nolint
+}
+
+// noinspection GoStructTag
+type UpgradesCmd struct {
+	Cmd struct{} `"upgrades"` //nolint
 }
 
 // noinspection GoStructTag
 type JoinsCmd struct {
-	Cmd struct{} `"joins"` //nolint
+	Cmd   struct{}        `"joins"` //nolint
+	Stats *JoinsStatsFlag `[ @@`    //nolint
+	Json  *JsonFlag       `| @@ ]`  //nolint
+}
+
+// noinspection GoStructTag
+type JoinsStatsFlag struct {
+	Dummy struct{}   `"stats"` //nolint
+	Reset *ResetFlag `[ @@`    //nolint
+	Json  *JsonFlag  `| @@ ]`  //nolint
 }
 
 // noinspection GoStructTag
 type CountersCmd struct {
-	Cmd struct{} `"counters"` //nolint
+	Cmd      struct{}              `"counters"` //nolint
+	Reset    *ResetFlag            `[ @@`       //nolint
+	Snapshot *CountersSnapshotFlag `| @@`       //nolint
+	Diff     *CountersDiffFlag     `| @@`       //nolint
+	Json     *JsonFlag             `| @@ ]`     //nolint
+}
+
+// noinspection GoStructTag
+type CountersSnapshotFlag struct {
+	Dummy struct{}  `"snapshot"`              //nolint
+	Json  *JsonFlag `[ @@`                    //nolint
+	Name  *string   `| @( String | Ident ) ]` //nolint
+}
+
+// noinspection GoStructTag
+type CountersDiffFlag struct {
+	Dummy  struct{}  `"diff"`                //nolint
+	From   string    `@( String | Ident )`   //nolint
+	Json   *JsonFlag `[ @@`                  //nolint
+	To     *string   `| @( String | Ident )` //nolint
+	ToJson *JsonFlag `  [ @@ ] ]`            //nolint
 }
 
 // noinspection GoStructTag
 type PlrCmd struct {
-	Cmd struct{} `"plr"`             //nolint
-	Val *float64 `[ (@Int|@Float) ]` //nolint
+	Cmd  struct{} `"plr"`                          //nolint
+	Val  *float64 `[ (@Int|@Float)`                //nolint
+	Data *float64 `  | ( "data" (@Int|@Float)`     //nolint
+	Ack  *float64 `    | "ack" (@Int|@Float) )+ ]` //nolint
 }
 
 // noinspection GoStructTag
@@ -432,3 +1389,98 @@ func ParseBytes(b []byte, cmd *Command) error {
 	err := commandParser.ParseBytes(b, cmd)
 	return err
 }
+
+// noinspection GoStructTag
+type SrpCmd struct {
+	Cmd   struct{}      `"srp"` //nolint
+	Stats *SrpStatsFlag `@@`    //nolint
+}
+
+// noinspection GoStructTag
+type SrpStatsFlag struct {
+	Dummy struct{} `"stats"` //nolint
+}
+
+// noinspection GoStructTag
+type StatsCmd struct {
+	Cmd    struct{}        `"stats"` //nolint
+	Window *StatsWindowCmd `@@`      //nolint
+}
+
+// noinspection GoStructTag
+type StatsWindowCmd struct {
+	Cmd       struct{}                 `"window"` //nolint
+	Interval  *StatsWindowIntervalFlag `( @@`     //nolint
+	Retention *StatsWindowKeepFlag     `| @@`     //nolint
+	Metrics   *StatsWindowMetricsFlag  `| @@`     //nolint
+	Yaml      *YamlFlag                `| @@ )*`  //nolint
+}
+
+// noinspection GoStructTag
+type StatsWindowIntervalFlag struct {
+	Val float64 `"interval" @( ["-"] (Int | Float) )` //nolint
+}
+
+// noinspection GoStructTag
+type StatsWindowKeepFlag struct {
+	Val int `"keep" @Int` //nolint
+}
+
+// noinspection GoStructTag
+type StatsWindowMetricsFlag struct {
+	Val []string `"metrics" @( "frames" | "bytes" | "airtime" | "retries" | "cca" | "drops" )+` //nolint
+}
+
+// noinspection GoStructTag
+type FormatCmd struct {
+	Cmd    struct{} `"format"`                 //nolint
+	Format *string  `[ @( "text" | "json" ) ]` //nolint
+}
+
+// noinspection GoStructTag
+type TopCmd struct {
+	Cmd    struct{}       `"top"`       //nolint
+	Total  *TopTotalFlag  `( @@`        //nolint
+	Nodes  []NodeSelector `| ( @@ )* )` //nolint
+	Output OutputFlags    `@@`          //nolint
+}
+
+// noinspection GoStructTag
+type TopTotalFlag struct {
+	Dummy struct{} `"total"` //nolint
+}
+
+// noinspection GoStructTag
+type SummaryCmd struct {
+	Cmd  struct{}  `"summary"` //nolint
+	Json *JsonFlag `[ @@ ]`    //nolint
+}
+
+// noinspection GoStructTag
+type ThrottleCmd struct {
+	Cmd   struct{}       `"throttle"`       //nolint
+	Nodes []NodeSelector `( @@ )*`          //nolint
+	Limit *int           `[ "limit" @Int ]` //nolint
+}
+
+// noinspection GoStructTag
+type RadiosCmd struct {
+	Cmd  struct{}       `"radios"` //nolint
+	Node NodeSelector   `@@`       //nolint
+	Add  *RadiosAddFlag `[ @@`     //nolint
+	Del  *RadiosDelFlag `| @@ ]`   //nolint
+}
+
+// noinspection GoStructTag
+type RadiosAddFlag struct {
+	Dummy      struct{} `"add" "channel"`  //nolint
+	MinChannel int      `@Int`             //nolint
+	MaxChannel *int     `[ @Int ]`         //nolint
+	Range      *int     `[ "range" @Int ]` //nolint
+}
+
+// noinspection GoStructTag
+type RadiosDelFlag struct {
+	Dummy struct{} `"del"` //nolint
+	Index int      `@Int`  //nolint
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/simulation"
+)
+
+const asyncOutputQueueLen = 100
+
+// asyncOutput prints the lines reported asynchronously by the simulations, e.g. finished jobs, to the console. The
+// lines are printed between commands, so that they never break the output of a command.
+type asyncOutput struct {
+	lock   sync.Mutex // held while a console command runs
+	writer io.Writer
+	lines  chan string
+}
+
+func newAsyncOutput(ctx *progctx.ProgCtx) *asyncOutput {
+	ao := &asyncOutput{
+		writer: os.Stdout,
+		lines:  make(chan string, asyncOutputQueueLen),
+	}
+	go ao.run(ctx)
+	return ao
+}
+
+func (ao *asyncOutput) run(ctx *progctx.ProgCtx) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line := <-ao.lines:
+			ao.lock.Lock()
+			_, _ = io.WriteString(ao.writer, line)
+			ao.lock.Unlock()
+		}
+	}
+}
+
+// post queues the line for printing. It does not block, since it is called in the simulation goroutine.
+func (ao *asyncOutput) post(line string) {
+	select {
+	case ao.lines <- line:
+	default:
+		simplelogger.Warnf("asynchronous output queue is full, dropped: %s", line)
+	}
+}
+
+// SetAsyncOutput sets the writer of the asynchronous output of the console, e.g. the terminal of the CLI.
+func (rt *CmdRunner) SetAsyncOutput(w io.Writer) {
+	if rt.async == nil {
+		return
+	}
+
+	rt.async.lock.Lock()
+	defer rt.async.lock.Unlock()
+	rt.async.writer = w
+}
+
+// onJobFinished reports a finished or canceled job to the console, e.g. "job 1 done: 1 -> fdde::1, sent=5/5
+// received=5/5".
+func (rt *CmdRunner) onJobFinished(job *simulation.Job) {
+	rt.async.post(job.String() + "\n")
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/simulation"
+	. "github.com/openthread/ot-ns/types"
+)
+
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestAsyncOutput(t *testing.T) {
+	ctx := progctx.New(context.Background())
+	defer ctx.Cancel(nil)
+
+	rt := &CmdRunner{contextNodeId: InvalidNodeId, async: newAsyncOutput(ctx)}
+	output := make(chanWriter, 10)
+	rt.SetAsyncOutput(output)
+
+	// finished jobs are held back while a command runs
+	rt.async.lock.Lock()
+	rt.onJobFinished(&simulation.Job{Id: 1, Src: 1, Dst: "fdde::1", Count: 5, Sent: 5, Received: 4, Expected: 5,
+		State: simulation.JobDone})
+	select {
+	case line := <-output:
+		t.Fatalf("unexpected output during a command: %s", line)
+	case <-time.After(time.Millisecond * 50):
+	}
+	rt.async.lock.Unlock()
+
+	select {
+	case line := <-output:
+		assert.Equal(t, "job 1 done: 1 -> fdde::1, sent=5/5 received=4/5\n", line)
+	case <-time.After(time.Second):
+		t.Fatal("finished job not reported")
+	}
+}
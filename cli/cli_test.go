@@ -27,9 +27,12 @@
 package cli
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
 )
 
 func TestParseBytes(t *testing.T) {
@@ -49,15 +52,104 @@ func TestParseBytes(t *testing.T) {
 	assert.True(t, *cmd.Add.X == 100 && *cmd.Add.Y == 200)
 	assert.Nil(t, ParseBytes([]byte("add router id 100"), &cmd))
 	assert.True(t, cmd.Add.Id.Val == 100)
+	assert.Nil(t, ParseBytes([]byte("add sed poll 5s"), &cmd))
+	assert.True(t, cmd.Add.Poll.Period == 5 && cmd.Add.Poll.Unit == "s")
+	assert.Nil(t, ParseBytes([]byte("add sed x 100 poll 500ms"), &cmd))
+	assert.True(t, cmd.Add.Poll.Period == 500 && cmd.Add.Poll.Unit == "ms" && *cmd.Add.X == 100)
+	assert.Nil(t, ParseBytes([]byte("add sed poll 2.5"), &cmd))
+	assert.True(t, cmd.Add.Poll.Period == 2.5 && cmd.Add.Poll.Unit == "")
+	assert.Nil(t, ParseBytes([]byte("add router script \"router.ot\" var txpower -10 var role leader"), &cmd))
+	assert.True(t, cmd.Add.Script.Path == "router.ot" && len(cmd.Add.Vars) == 2)
+	assert.True(t, cmd.Add.Vars[0].Name == "txpower" && cmd.Add.Vars[0].Value == "-10" && cmd.Add.Vars[1].Value == "leader")
 	assert.Nil(t, ParseBytes([]byte("add router rr 1234"), &cmd))
 	assert.True(t, cmd.Add.RadioRange.Val == 1234)
 	assert.Nil(t, ParseBytes([]byte("add router x 1 y 2 id 3 rr 1234"), &cmd))
 	assert.Nil(t, ParseBytes([]byte("add router rr 1234 id 3 y 2 x 1"), &cmd))
+	assert.Nil(t, ParseBytes([]byte("add router at 120s"), &cmd))
+	assert.True(t, cmd.Add.At.Seconds == 120)
+	assert.Nil(t, ParseBytes([]byte("add sed x 10 at 1.5 id 5"), &cmd))
+	assert.True(t, cmd.Add.At.Seconds == 1.5 && cmd.Add.Id.Val == 5)
+
+	assert.True(t, ParseBytes([]byte("airtime"), &cmd) == nil && cmd.Airtime != nil && cmd.Airtime.Reset == nil)
+	assert.True(t, ParseBytes([]byte("airtime reset"), &cmd) == nil && cmd.Airtime != nil && cmd.Airtime.Reset != nil)
+	assert.True(t, ParseBytes([]byte("airtime yaml"), &cmd) == nil && cmd.Airtime != nil && cmd.Airtime.Yaml != nil)
 
 	assert.True(t, ParseBytes([]byte("countdown 3"), &cmd) == nil && cmd.CountDown != nil)
 	assert.True(t, ParseBytes([]byte("countdown 3 \"abc\""), &cmd) == nil && cmd.CountDown != nil)
 
-	assert.True(t, ParseBytes([]byte("counters"), &cmd) == nil && cmd.Counters != nil)
+	assert.True(t, ParseBytes([]byte("counters"), &cmd) == nil && cmd.Counters != nil && cmd.Counters.Reset == nil &&
+		cmd.Counters.Snapshot == nil && cmd.Counters.Diff == nil)
+	assert.True(t, ParseBytes([]byte("counters reset"), &cmd) == nil && cmd.Counters.Reset != nil)
+	assert.True(t, ParseBytes([]byte("health"), &cmd) == nil && cmd.Health != nil && cmd.Health.Policy == nil && cmd.Health.Json == nil)
+	assert.True(t, ParseBytes([]byte("health json"), &cmd) == nil && cmd.Health.Json != nil)
+	assert.True(t, ParseBytes([]byte("history 5"), &cmd) == nil && cmd.History != nil && cmd.History.Node.Id == 5 && cmd.History.Start == nil && cmd.History.End == nil)
+	assert.True(t, ParseBytes([]byte("history 5 340"), &cmd) == nil && *cmd.History.Start == 340 && cmd.History.End == nil)
+	assert.True(t, ParseBytes([]byte("history 5 2.5 100 json"), &cmd) == nil && *cmd.History.Start == 2.5 && *cmd.History.End == 100 && cmd.History.Json != nil)
+	assert.True(t, ParseBytes([]byte("history"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("stall"), &cmd) == nil && cmd.Stall != nil && cmd.Stall.Timeout == nil && cmd.Stall.ForceFail == nil)
+	assert.True(t, ParseBytes([]byte("stall timeout 10"), &cmd) == nil && *cmd.Stall.Timeout == 10 && cmd.Stall.ForceFail == nil)
+	assert.True(t, ParseBytes([]byte("stall timeout 2.5s forcefail on"), &cmd) == nil && *cmd.Stall.Timeout == 2.5 &&
+		cmd.Stall.ForceFail.OnOrOff.On != nil)
+	assert.True(t, ParseBytes([]byte("stall forcefail off"), &cmd) == nil && cmd.Stall.ForceFail.OnOrOff.Off != nil)
+	assert.True(t, ParseBytes([]byte("format"), &cmd) == nil && cmd.Format != nil && cmd.Format.Format == nil)
+	assert.True(t, ParseBytes([]byte("format json"), &cmd) == nil && cmd.Format != nil && *cmd.Format.Format == "json")
+	assert.True(t, ParseBytes([]byte("format text"), &cmd) == nil && cmd.Format != nil && *cmd.Format.Format == "text")
+	assert.True(t, ParseBytes([]byte("format xml"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("top"), &cmd) == nil && cmd.Top != nil && cmd.Top.Total == nil && len(cmd.Top.Nodes) == 0)
+	assert.True(t, ParseBytes([]byte("top 1 2 cpu_time=1 json"), &cmd) == nil && cmd.Top != nil && len(cmd.Top.Nodes) == 2 && len(cmd.Top.Output.Filters) == 1 && cmd.Top.Output.Json != nil)
+	assert.True(t, ParseBytes([]byte("top total"), &cmd) == nil && cmd.Top != nil && cmd.Top.Total != nil)
+	assert.True(t, ParseBytes([]byte("top total json"), &cmd) == nil && cmd.Top != nil && cmd.Top.Total != nil && cmd.Top.Output.Json != nil)
+	assert.True(t, ParseBytes([]byte("summary"), &cmd) == nil && cmd.Summary != nil && cmd.Summary.Json == nil)
+	assert.True(t, ParseBytes([]byte("summary json"), &cmd) == nil && cmd.Summary != nil && cmd.Summary.Json != nil)
+	assert.True(t, ParseBytes([]byte("coalesce"), &cmd) == nil && cmd.Coalesce != nil && cmd.Coalesce.Window == nil && cmd.Coalesce.Off == nil)
+	assert.True(t, ParseBytes([]byte("coalesce 100"), &cmd) == nil && *cmd.Coalesce.Window == 100)
+	assert.True(t, ParseBytes([]byte("coalesce 250 us"), &cmd) == nil && *cmd.Coalesce.Window == 250)
+	assert.True(t, ParseBytes([]byte("coalesce off"), &cmd) == nil && cmd.Coalesce.Off != nil && cmd.Coalesce.Window == nil)
+	assert.True(t, ParseBytes([]byte("throttle"), &cmd) == nil && cmd.Throttle != nil && len(cmd.Throttle.Nodes) == 0 && cmd.Throttle.Limit == nil)
+	assert.True(t, ParseBytes([]byte("throttle limit 500"), &cmd) == nil && len(cmd.Throttle.Nodes) == 0 && *cmd.Throttle.Limit == 500)
+	assert.True(t, ParseBytes([]byte("throttle 1 3 limit 0"), &cmd) == nil && len(cmd.Throttle.Nodes) == 2 && *cmd.Throttle.Limit == 0)
+	assert.True(t, ParseBytes([]byte("throttle 2"), &cmd) == nil && len(cmd.Throttle.Nodes) == 1 && cmd.Throttle.Limit == nil)
+	assert.True(t, ParseBytes([]byte("radios 1"), &cmd) == nil && cmd.Radios != nil && cmd.Radios.Node.Id == 1 &&
+		cmd.Radios.Add == nil && cmd.Radios.Del == nil)
+	assert.True(t, ParseBytes([]byte("radios 1 add channel 0 10 range 500"), &cmd) == nil && cmd.Radios.Add.MinChannel == 0 &&
+		*cmd.Radios.Add.MaxChannel == 10 && *cmd.Radios.Add.Range == 500)
+	assert.True(t, ParseBytes([]byte("radios 2 add channel 15"), &cmd) == nil && cmd.Radios.Add.MinChannel == 15 &&
+		cmd.Radios.Add.MaxChannel == nil && cmd.Radios.Add.Range == nil)
+	assert.True(t, ParseBytes([]byte("radios 2 del 1"), &cmd) == nil && cmd.Radios.Del.Index == 1)
+	assert.True(t, ParseBytes([]byte("radio 1 off"), &cmd) == nil && cmd.Radio != nil && cmd.Radios == nil)
+	assert.True(t, ParseBytes([]byte("nodes"), &cmd) == nil && cmd.Nodes != nil && cmd.Nodes.Exec == nil)
+	assert.True(t, ParseBytes([]byte("nodes state=router"), &cmd) == nil && cmd.Nodes.Exec == nil &&
+		len(cmd.Nodes.Output.Filters) == 1 && cmd.Nodes.Output.Filters[0] == OutputFilter{Key: "state", Op: "=", Value: "router"})
+	assert.True(t, ParseBytes([]byte("nodes state!=leader x=100 rloc16~\"54\" columns id,rloc16 json"), &cmd) == nil &&
+		len(cmd.Nodes.Output.Filters) == 3 && cmd.Nodes.Output.Filters[0].Op == "!=" &&
+		cmd.Nodes.Output.Filters[1] == OutputFilter{Key: "x", Op: "=", Value: "100"} &&
+		cmd.Nodes.Output.Filters[2] == OutputFilter{Key: "rloc16", Op: "~", Value: "54"} && cmd.Nodes.Output.Json != nil)
+	assert.Equal(t, []string{"id", "rloc16"}, cmd.Nodes.Output.Columns)
+	assert.True(t, ParseBytes([]byte("nodes count"), &cmd) == nil && cmd.Nodes.Output.Count != nil)
+	assert.True(t, ParseBytes([]byte("pts count"), &cmd) == nil && cmd.Partitions != nil && cmd.Partitions.Output.Count != nil)
+	assert.True(t, ParseBytes([]byte("pings"), &cmd) == nil && cmd.Pings != nil && len(cmd.Pings.Output.Filters) == 0)
+	assert.True(t, ParseBytes([]byte("pings node=2 columns dst,delay"), &cmd) == nil && len(cmd.Pings.Output.Filters) == 1 &&
+		len(cmd.Pings.Output.Columns) == 2)
+	assert.True(t, ParseBytes([]byte("nodes exec \"state\""), &cmd) == nil && cmd.Nodes.Exec != nil &&
+		len(cmd.Nodes.Exec.Nodes) == 0 && cmd.Nodes.Exec.Command == "state")
+	assert.True(t, ParseBytes([]byte("nodes exec 1 3 \"rloc16\""), &cmd) == nil && len(cmd.Nodes.Exec.Nodes) == 2 &&
+		cmd.Nodes.Exec.Command == "rloc16")
+	assert.True(t, ParseBytes([]byte("health policy"), &cmd) == nil && cmd.Health.Policy != nil && cmd.Health.Policy.Policy == nil)
+	assert.True(t, ParseBytes([]byte("health policy delete"), &cmd) == nil && *cmd.Health.Policy.Policy == "delete" &&
+		cmd.Health.Policy.MaxRestarts == nil)
+	assert.True(t, ParseBytes([]byte("health policy restart max 5"), &cmd) == nil && *cmd.Health.Policy.Policy == "restart" &&
+		*cmd.Health.Policy.MaxRestarts == 5)
+	assert.True(t, ParseBytes([]byte("health policy ignore"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("counters snapshot"), &cmd) == nil && cmd.Counters.Snapshot.Name == nil)
+	assert.True(t, ParseBytes([]byte("counters snapshot attach"), &cmd) == nil && *cmd.Counters.Snapshot.Name == "attach")
+	assert.True(t, ParseBytes([]byte("counters snapshot \"phase 1\""), &cmd) == nil && *cmd.Counters.Snapshot.Name == "phase 1")
+	assert.True(t, ParseBytes([]byte("counters diff attach"), &cmd) == nil && cmd.Counters.Diff.From == "attach" &&
+		cmd.Counters.Diff.To == nil)
+	assert.True(t, ParseBytes([]byte("counters diff attach \"phase 1\""), &cmd) == nil && *cmd.Counters.Diff.To == "phase 1")
+	assert.True(t, ParseBytes([]byte("counters json"), &cmd) == nil && cmd.Counters.Json != nil && cmd.Counters.Snapshot == nil)
+	assert.True(t, ParseBytes([]byte("counters snapshot json"), &cmd) == nil && cmd.Counters.Snapshot.Json != nil && cmd.Counters.Snapshot.Name == nil)
+	assert.True(t, ParseBytes([]byte("counters diff attach json"), &cmd) == nil && cmd.Counters.Diff.Json != nil && cmd.Counters.Diff.To == nil)
+	assert.True(t, ParseBytes([]byte("counters diff attach steady json"), &cmd) == nil && *cmd.Counters.Diff.To == "steady" && cmd.Counters.Diff.ToJson != nil)
 
 	assert.True(t, ParseBytes([]byte("del 1"), &cmd) == nil && cmd.Del != nil)
 	assert.True(t, ParseBytes([]byte("del 1 2"), &cmd) == nil && cmd.Del != nil)
@@ -66,6 +158,281 @@ func TestParseBytes(t *testing.T) {
 
 	assert.True(t, ParseBytes([]byte("demo_legend \"title\" 100 200"), &cmd) == nil && cmd.DemoLegend != nil)
 
+	assert.True(t, ParseBytes([]byte("drift"), &cmd) == nil && cmd.Drift != nil && cmd.Drift.Ppm == nil)
+	assert.True(t, ParseBytes([]byte("drift 5 ppm"), &cmd) == nil && *cmd.Drift.Ppm == 5 && len(cmd.Drift.Nodes) == 0)
+	assert.True(t, ParseBytes([]byte("drift -2.5 nodes 1 3-10"), &cmd) == nil && *cmd.Drift.Ppm == -2.5 && len(cmd.Drift.Nodes) == 2 && *cmd.Drift.Nodes[1].To == 10)
+	assert.True(t, ParseBytes([]byte("drift 5 ppm nodes 3-10"), &cmd) == nil && *cmd.Drift.Ppm == 5 && len(cmd.Drift.Nodes) == 1)
+	assert.True(t, ParseBytes([]byte("drift analysis"), &cmd) == nil && cmd.Drift.Analysis != nil && cmd.Drift.Ppm == nil)
+	assert.True(t, ParseBytes([]byte("drift analysis accuracy 40 ppm"), &cmd) == nil && *cmd.Drift.Analysis.Accuracy == 40)
+
+	assert.True(t, ParseBytes([]byte("radiomodel"), &cmd) == nil && cmd.RadioModel != nil && cmd.RadioModel.Model == nil)
+	assert.True(t, ParseBytes([]byte("radiomodel friis"), &cmd) == nil && *cmd.RadioModel.Model == "friis")
+	assert.True(t, ParseBytes([]byte("radioparam"), &cmd) == nil && cmd.RadioParam != nil && cmd.RadioParam.Name == nil)
+	assert.True(t, ParseBytes([]byte("radioparam NoiseFloorDbm -90"), &cmd) == nil && *cmd.RadioParam.Name == "NoiseFloorDbm" && cmd.RadioParam.Channel == nil && *cmd.RadioParam.Val == -90)
+	assert.True(t, ParseBytes([]byte("radioparam NoiseFloorDbm ch15 -85.5"), &cmd) == nil && *cmd.RadioParam.Channel == "ch15" && *cmd.RadioParam.Val == -85.5)
+	assert.True(t, ParseBytes([]byte("radioparam NoiseFloorDbm ch15"), &cmd) == nil && *cmd.RadioParam.Channel == "ch15" && cmd.RadioParam.Val == nil)
+	assert.True(t, ParseBytes([]byte("radioparam json"), &cmd) == nil && cmd.RadioParam.Json != nil && cmd.RadioParam.Name == nil)
+
+	assert.True(t, ParseBytes([]byte("antenna"), &cmd) == nil && cmd.Antenna != nil && cmd.Antenna.Node == nil && cmd.Antenna.Load == nil)
+	assert.True(t, ParseBytes([]byte("antenna yaml"), &cmd) == nil && cmd.Antenna.Node == nil && cmd.Antenna.Yaml != nil)
+	assert.True(t, ParseBytes([]byte("antenna 1 off"), &cmd) == nil && cmd.Antenna.Node.Id == 1 && cmd.Antenna.Off != nil)
+	assert.True(t, ParseBytes([]byte("antenna 1 sector -90 120"), &cmd) == nil && cmd.Antenna.Sector.Azimuth == -90 && cmd.Antenna.Sector.BeamWidth == 120 && cmd.Antenna.Sector.GainDbi == nil)
+	assert.True(t, ParseBytes([]byte("antenna 2 sector 45 90 gain 8 back -20"), &cmd) == nil && *cmd.Antenna.Sector.GainDbi == 8 && *cmd.Antenna.Sector.BackGainDbi == -20)
+	assert.True(t, ParseBytes([]byte("antenna load \"antennas.yaml\""), &cmd) == nil && *cmd.Antenna.Load == "antennas.yaml")
+
+	assert.True(t, ParseBytes([]byte("compare \"golden.csv\""), &cmd) == nil && cmd.Compare != nil && cmd.Compare.File == "golden.csv" && cmd.Compare.Time == nil)
+	assert.True(t, ParseBytes([]byte("compare \"golden.csv\" time 1.5 count 1"), &cmd) == nil && *cmd.Compare.Time == 1.5 && *cmd.Compare.Count == 1)
+
+	assert.True(t, ParseBytes([]byte("kpi"), &cmd) == nil && cmd.Kpi != nil && cmd.Kpi.Start == nil && cmd.Kpi.Save == nil)
+	assert.True(t, ParseBytes([]byte("kpi start"), &cmd) == nil && cmd.Kpi.Start != nil)
+	assert.True(t, ParseBytes([]byte("kpi stop"), &cmd) == nil && cmd.Kpi.Stop != nil)
+	assert.True(t, ParseBytes([]byte("kpi save \"kpi.json\""), &cmd) == nil && *cmd.Kpi.Save == "kpi.json")
+
+	assert.True(t, ParseBytes([]byte("session"), &cmd) == nil && cmd.Session != nil && cmd.Session.Id == nil)
+	assert.True(t, ParseBytes([]byte("session new"), &cmd) == nil && cmd.Session.New != nil && cmd.Session.Id == nil)
+	assert.True(t, ParseBytes([]byte("session new 3"), &cmd) == nil && cmd.Session.New != nil && *cmd.Session.Id == 3)
+	assert.True(t, ParseBytes([]byte("session 3"), &cmd) == nil && cmd.Session.New == nil && *cmd.Session.Id == 3)
+	assert.True(t, ParseBytes([]byte("session del 3"), &cmd) == nil && cmd.Session.Del != nil && *cmd.Session.Id == 3)
+
+	assert.True(t, ParseBytes([]byte("reset all"), &cmd) == nil && cmd.Reset != nil)
+	assert.True(t, ParseBytes([]byte("reset"), &cmd) != nil)
+
+	assert.True(t, ParseBytes([]byte("geo"), &cmd) == nil && cmd.Geo != nil && cmd.Geo.Origin == nil && cmd.Geo.Off == nil)
+	assert.True(t, ParseBytes([]byte("geo off"), &cmd) == nil && cmd.Geo.Off != nil)
+	assert.True(t, ParseBytes([]byte("geo origin 37.422 -122.084"), &cmd) == nil && cmd.Geo.Origin != nil &&
+		cmd.Geo.Origin.Lat == 37.422 && cmd.Geo.Origin.Lon == -122.084 && cmd.Geo.Origin.Alt == nil && cmd.Geo.Origin.Scale == nil)
+	assert.True(t, ParseBytes([]byte("geo origin -33.8 151 alt -5 scale 0.5"), &cmd) == nil && cmd.Geo.Origin.Lat == -33.8 &&
+		*cmd.Geo.Origin.Alt == -5 && *cmd.Geo.Origin.Scale == 0.5)
+	assert.True(t, ParseBytes([]byte("add router geo 37.422 -122.084"), &cmd) == nil && cmd.Add.Geo != nil &&
+		cmd.Add.Geo.Lat == 37.422 && cmd.Add.Geo.Lon == -122.084)
+	assert.True(t, ParseBytes([]byte("move 1 geo 37.4 -122"), &cmd) == nil && cmd.Move.Geo != nil && cmd.Move.Geo.Lon == -122)
+	assert.True(t, ParseBytes([]byte("move 1 100 200"), &cmd) == nil && cmd.Move.Geo == nil && cmd.Move.X == 100 && cmd.Move.Y == 200)
+	assert.True(t, ParseBytes([]byte("move 5 dx +50 dy 0"), &cmd) == nil && len(cmd.Move.Deltas) == 2 &&
+		cmd.Move.Deltas[0].Axis == "dx" && cmd.Move.Deltas[0].Delta == 50 && cmd.Move.Deltas[1].Delta == 0)
+	assert.True(t, ParseBytes([]byte("move 5 dy -20"), &cmd) == nil && len(cmd.Move.Deltas) == 1 &&
+		cmd.Move.Deltas[0].Axis == "dy" && cmd.Move.Deltas[0].Delta == -20)
+	assert.True(t, ParseBytes([]byte("align 3 4 5 horizontal spacing 60"), &cmd) == nil && cmd.Align != nil &&
+		len(cmd.Align.Nodes) == 3 && cmd.Align.Direction == "horizontal" && *cmd.Align.Spacing == 60)
+	assert.True(t, ParseBytes([]byte("align 1 6 vertical"), &cmd) == nil && cmd.Align.Spacing == nil)
+	assert.True(t, ParseBytes([]byte("align 1 6"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("grid snap 20"), &cmd) == nil && cmd.Grid != nil && *cmd.Grid.Snap.Size == 20)
+	assert.True(t, ParseBytes([]byte("grid snap off"), &cmd) == nil && cmd.Grid.Snap.Off != nil)
+	assert.True(t, ParseBytes([]byte("grid"), &cmd) == nil && cmd.Grid.Snap == nil)
+	assert.True(t, ParseBytes([]byte("radiorange"), &cmd) == nil && cmd.RadioRange != nil && cmd.RadioRange.Edge == nil)
+	assert.True(t, ParseBytes([]byte("radiorange edge -85"), &cmd) == nil && *cmd.RadioRange.Edge == -85 && cmd.RadioRange.Channel == nil)
+	assert.True(t, ParseBytes([]byte("radiorange edge -85dBm"), &cmd) == nil && *cmd.RadioRange.Edge == -85)
+	assert.True(t, ParseBytes([]byte("radiorange edge -82.5 dBm ch15"), &cmd) == nil && *cmd.RadioRange.Edge == -82.5 &&
+		*cmd.RadioRange.Channel == "ch15")
+	assert.True(t, ParseBytes([]byte("range 3 7"), &cmd) == nil && cmd.Range != nil && cmd.Range.Src.Id == 3 && cmd.Range.Dst.Id == 7 &&
+		cmd.Range.Count == nil)
+	assert.True(t, ParseBytes([]byte("range 3 7 count 10"), &cmd) == nil && cmd.Range.Count.Val == 10)
+	assert.True(t, ParseBytes([]byte("range 3"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("range model"), &cmd) == nil && cmd.Range.Model != nil && cmd.Range.Model.Bias == nil &&
+		cmd.Range.Model.StdDev == nil)
+	assert.True(t, ParseBytes([]byte("range model bias -0.5 stddev 2"), &cmd) == nil && *cmd.Range.Model.Bias == -0.5 &&
+		*cmd.Range.Model.StdDev == 2)
+	assert.True(t, ParseBytes([]byte("range events"), &cmd) == nil && cmd.Range.Events != nil && cmd.Range.Events.OnOrOff == nil)
+	assert.True(t, ParseBytes([]byte("range events 1 2 on"), &cmd) == nil && len(cmd.Range.Events.Nodes) == 2 &&
+		cmd.Range.Events.OnOrOff.On != nil)
+	assert.True(t, ParseBytes([]byte("netdata"), &cmd) == nil && cmd.NetData != nil && cmd.NetData.Node == nil && cmd.NetData.Json == nil)
+	assert.True(t, ParseBytes([]byte("netdata 3"), &cmd) == nil && cmd.NetData.Node.Id == 3 && cmd.NetData.Json == nil)
+	assert.True(t, ParseBytes([]byte("netdata json"), &cmd) == nil && cmd.NetData.Node == nil && cmd.NetData.Json != nil)
+	assert.True(t, ParseBytes([]byte("frag send 1 2"), &cmd) == nil && cmd.Frag != nil && cmd.Frag.Send.Src.Id == 1 &&
+		cmd.Frag.Send.Dst.Id == 2 && cmd.Frag.Send.DataSize == nil)
+	assert.True(t, ParseBytes([]byte("frag send 1 2 datasize 300 count 5 interval 2"), &cmd) == nil &&
+		cmd.Frag.Send.DataSize.Val == 300 && cmd.Frag.Send.Count.Val == 5 && cmd.Frag.Send.Interval.Val == 2)
+	assert.True(t, ParseBytes([]byte("frag stats"), &cmd) == nil && cmd.Frag.Stats != nil && cmd.Frag.Stats.Reset == nil)
+	assert.True(t, ParseBytes([]byte("frag stats reset"), &cmd) == nil && cmd.Frag.Stats.Reset != nil)
+	assert.True(t, ParseBytes([]byte("frag send 1"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("add router bootdelay 500ms bootcrash 0.1"), &cmd) == nil && cmd.Add != nil &&
+		cmd.Add.BootDelay.Delay == 500 && cmd.Add.BootDelay.Unit == "ms" && cmd.Add.BootCrash.Prob == 0.1)
+	assert.True(t, ParseBytes([]byte("add sed bootdelay 2"), &cmd) == nil && cmd.Add.BootDelay.Delay == 2 &&
+		cmd.Add.BootCrash == nil && cmd.Add.CpuFactor == nil && cmd.Add.Like == nil)
+	assert.True(t, ParseBytes([]byte("add router like 3 x 10"), &cmd) == nil && cmd.Add.Like.Node.Id == 3 &&
+		*cmd.Add.X == 10)
+	assert.True(t, ParseBytes([]byte("add router like"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("add router cpufactor 2.0 x 10"), &cmd) == nil && cmd.Add.CpuFactor.Factor == 2 &&
+		*cmd.Add.X == 10)
+	assert.True(t, ParseBytes([]byte("cmdbudget"), &cmd) == nil && cmd.CmdBudget != nil && cmd.CmdBudget.Off == nil &&
+		cmd.CmdBudget.Budget == nil && cmd.CmdBudget.Costs == nil && cmd.CmdBudget.Reset == nil)
+	assert.True(t, ParseBytes([]byte("cmdbudget 2.5 s"), &cmd) == nil && *cmd.CmdBudget.Budget == 2.5)
+	assert.True(t, ParseBytes([]byte("cmdbudget off"), &cmd) == nil && cmd.CmdBudget.Off != nil)
+	assert.True(t, ParseBytes([]byte("cmdbudget costs json"), &cmd) == nil && cmd.CmdBudget.Costs != nil &&
+		cmd.CmdBudget.Costs.Json != nil)
+	assert.True(t, ParseBytes([]byte("cmdbudget reset"), &cmd) == nil && cmd.CmdBudget.Reset != nil)
+	assert.True(t, ParseBytes([]byte("cmdbudget 2 costs"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("site"), &cmd) == nil && cmd.Site != nil && cmd.Site.Backbone == nil &&
+		cmd.Site.Nodes == nil)
+	assert.True(t, ParseBytes([]byte("site home 1 2"), &cmd) == nil && cmd.Site != nil && cmd.Site.Nodes != nil &&
+		*cmd.Site.Nodes.Name == "home" && len(cmd.Site.Nodes.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("site off 3"), &cmd) == nil && cmd.Site != nil && cmd.Site.Nodes != nil &&
+		cmd.Site.Nodes.Off != nil && cmd.Site.Nodes.Nodes[0].Id == 3)
+	assert.True(t, ParseBytes([]byte("site home"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("site backbone"), &cmd) == nil && cmd.Site != nil && cmd.Site.Backbone != nil &&
+		cmd.Site.Backbone.Latency == nil)
+	assert.True(t, ParseBytes([]byte("site backbone latency 20ms jitter 5 loss 1%"), &cmd) == nil && cmd.Site != nil &&
+		*cmd.Site.Backbone.Latency == 20 && *cmd.Site.Backbone.Jitter == 5 && *cmd.Site.Backbone.Loss == 1)
+	assert.True(t, ParseBytes([]byte("jobs"), &cmd) == nil && cmd.Jobs != nil && cmd.Jobs.Json == nil)
+	assert.True(t, ParseBytes([]byte("jobs json"), &cmd) == nil && cmd.Jobs != nil && cmd.Jobs.Json != nil)
+	assert.True(t, ParseBytes([]byte("cancel 3"), &cmd) == nil && cmd.Cancel != nil && cmd.Cancel.Job == 3)
+	assert.True(t, ParseBytes([]byte("cancel"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("linkmetrics"), &cmd) == nil && cmd.LinkMetrics != nil &&
+		cmd.LinkMetrics.Src == nil && cmd.LinkMetrics.Json == nil)
+	assert.True(t, ParseBytes([]byte("linkmetrics json"), &cmd) == nil && cmd.LinkMetrics != nil &&
+		cmd.LinkMetrics.Json != nil)
+	assert.True(t, ParseBytes([]byte("linkmetrics 1 2 json"), &cmd) == nil && cmd.LinkMetrics != nil &&
+		cmd.LinkMetrics.Src.Id == 1 && cmd.LinkMetrics.Dst.Id == 2 && cmd.LinkMetrics.Json != nil)
+	assert.True(t, ParseBytes([]byte("linkmetrics start 1 2"), &cmd) == nil && cmd.LinkMetrics != nil &&
+		cmd.LinkMetrics.Start.Src.Id == 1 && cmd.LinkMetrics.Start.Dst.Id == 2 && cmd.LinkMetrics.Start.Interval == nil)
+	assert.True(t, ParseBytes([]byte("linkmetrics start 1 2 interval 0.5s"), &cmd) == nil &&
+		*cmd.LinkMetrics.Start.Interval == 0.5)
+	assert.True(t, ParseBytes([]byte("linkmetrics stop"), &cmd) == nil && cmd.LinkMetrics != nil &&
+		cmd.LinkMetrics.Stop != nil && cmd.LinkMetrics.Stop.Src == nil)
+	assert.True(t, ParseBytes([]byte("linkmetrics stop 1 2"), &cmd) == nil && cmd.LinkMetrics.Stop.Dst.Id == 2)
+	assert.True(t, ParseBytes([]byte("linkmetrics reset"), &cmd) == nil && cmd.LinkMetrics.Reset != nil)
+	assert.True(t, ParseBytes([]byte("dup"), &cmd) == nil && cmd.Dup != nil && cmd.Dup.Link == nil &&
+		cmd.Dup.Ratio == nil && cmd.Dup.Off == nil && cmd.Dup.Delay == nil)
+	assert.True(t, ParseBytes([]byte("dup 5 %"), &cmd) == nil && cmd.Dup != nil && *cmd.Dup.Ratio == 5)
+	assert.True(t, ParseBytes([]byte("dup off"), &cmd) == nil && cmd.Dup != nil && cmd.Dup.Off != nil)
+	assert.True(t, ParseBytes([]byte("dup delay 2ms"), &cmd) == nil && cmd.Dup != nil && cmd.Dup.Ratio == nil &&
+		*cmd.Dup.Delay == 2)
+	assert.True(t, ParseBytes([]byte("dup link 1 2 50 delay 0.5"), &cmd) == nil && cmd.Dup != nil &&
+		cmd.Dup.Link.Src.Id == 1 && cmd.Dup.Link.Dst.Id == 2 && *cmd.Dup.Ratio == 50 && *cmd.Dup.Delay == 0.5)
+	assert.True(t, ParseBytes([]byte("dup link 1 2 off"), &cmd) == nil && cmd.Dup != nil && cmd.Dup.Link != nil &&
+		cmd.Dup.Off != nil)
+	assert.True(t, ParseBytes([]byte("dup link 1"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("reorder"), &cmd) == nil && cmd.Reorder != nil && cmd.Reorder.Ratio == nil &&
+		cmd.Reorder.Off == nil && cmd.Reorder.Hold == nil)
+	assert.True(t, ParseBytes([]byte("reorder 2% hold 20ms"), &cmd) == nil && cmd.Reorder != nil &&
+		*cmd.Reorder.Ratio == 2 && *cmd.Reorder.Hold == 20)
+	assert.True(t, ParseBytes([]byte("reorder off"), &cmd) == nil && cmd.Reorder != nil && cmd.Reorder.Off != nil)
+	assert.True(t, ParseBytes([]byte("reorder hold"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("macstats"), &cmd) == nil && cmd.MacStats != nil && cmd.MacStats.Reset == nil &&
+		cmd.MacStats.Json == nil)
+	assert.True(t, ParseBytes([]byte("macstats reset"), &cmd) == nil && cmd.MacStats != nil && cmd.MacStats.Reset != nil)
+	assert.True(t, ParseBytes([]byte("macstats json"), &cmd) == nil && cmd.MacStats != nil && cmd.MacStats.Json != nil)
+	assert.True(t, ParseBytes([]byte("add slow_router x 100 y 200"), &cmd) == nil && cmd.Add != nil &&
+		cmd.Add.Type.Val == "slow_router" && *cmd.Add.X == 100)
+	assert.True(t, ParseBytes([]byte("add router"), &cmd) == nil && cmd.Add != nil && cmd.Add.Type.Val == "router")
+	assert.True(t, ParseBytes([]byte("templates"), &cmd) == nil && cmd.Templates != nil && cmd.Templates.Load == nil)
+	assert.True(t, ParseBytes([]byte("templates load \"t.yaml\""), &cmd) == nil && cmd.Templates != nil &&
+		*cmd.Templates.Load == "t.yaml")
+	assert.True(t, ParseBytes([]byte("templates load"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("cv nodes 1 5-9"), &cmd) == nil && cmd.ConfigVisualization != nil &&
+		len(cmd.ConfigVisualization.Nodes.Nodes) == 2 && *cmd.ConfigVisualization.Nodes.Nodes[1].To == 9)
+	assert.True(t, ParseBytes([]byte("cv bro off nodes all"), &cmd) == nil &&
+		cmd.ConfigVisualization.BroadcastMessage != nil && cmd.ConfigVisualization.Nodes.All != nil)
+	assert.True(t, ParseBytes([]byte("zombies"), &cmd) == nil && cmd.Zombies != nil && cmd.Zombies.Policy == nil)
+	assert.True(t, ParseBytes([]byte("zombies json"), &cmd) == nil && cmd.Zombies.Json != nil)
+	assert.True(t, ParseBytes([]byte("zombies policy timeout 600 restart on"), &cmd) == nil &&
+		*cmd.Zombies.Policy.Timeout == 600 && cmd.Zombies.Policy.Restart.On != nil)
+	assert.True(t, ParseBytes([]byte("zombies policy restart off"), &cmd) == nil &&
+		cmd.Zombies.Policy.Timeout == nil && cmd.Zombies.Policy.Restart.Off != nil)
+	assert.True(t, ParseBytes([]byte("zombies policy"), &cmd) == nil && cmd.Zombies.Policy.Timeout == nil &&
+		cmd.Zombies.Policy.Restart == nil)
+	assert.True(t, ParseBytes([]byte("coverage"), &cmd) == nil && cmd.Coverage != nil && cmd.Coverage.Merge == nil)
+	assert.True(t, ParseBytes([]byte("coverage merge"), &cmd) == nil && cmd.Coverage.Merge != nil &&
+		cmd.Coverage.Merge.Output == nil)
+	assert.True(t, ParseBytes([]byte(`coverage merge "all.profdata"`), &cmd) == nil &&
+		*cmd.Coverage.Merge.Output == "all.profdata")
+	assert.True(t, ParseBytes([]byte("dutycycle 1% window 3600 nodes 3-10"), &cmd) == nil && cmd.DutyCycle != nil &&
+		*cmd.DutyCycle.Percent == 1 && *cmd.DutyCycle.Window == 3600 && len(cmd.DutyCycle.Nodes) == 1 &&
+		*cmd.DutyCycle.Nodes[0].To == 10)
+	assert.True(t, ParseBytes([]byte("dutycycle 0.1 %"), &cmd) == nil && *cmd.DutyCycle.Percent == 0.1 &&
+		cmd.DutyCycle.Window == nil && cmd.DutyCycle.Nodes == nil)
+	assert.True(t, ParseBytes([]byte("dutycycle off nodes 2"), &cmd) == nil && cmd.DutyCycle.Off != nil &&
+		cmd.DutyCycle.Percent == nil && cmd.DutyCycle.Nodes[0].From == 2)
+	assert.True(t, ParseBytes([]byte("dutycycle json"), &cmd) == nil && cmd.DutyCycle.Off == nil &&
+		cmd.DutyCycle.Percent == nil && cmd.DutyCycle.Json != nil)
+	assert.True(t, ParseBytes([]byte("dutycycle 1"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("power"), &cmd) == nil && cmd.Power != nil && cmd.Power.Source == nil)
+	assert.True(t, ParseBytes([]byte("power battery 2500 nodes 2-5"), &cmd) == nil &&
+		cmd.Power.Source.Battery.CapacityMwh == 2500 && len(cmd.Power.Nodes) == 1)
+	assert.True(t, ParseBytes([]byte("power harvest 100 0.5 period 86400 s duty 50 %"), &cmd) == nil &&
+		cmd.Power.Source.Harvest.PowerMw == 0.5 && *cmd.Power.Source.Harvest.Period == 86400 &&
+		cmd.Power.Source.Harvest.Duty == 50)
+	assert.True(t, ParseBytes([]byte("power mains json"), &cmd) == nil && cmd.Power.Source.Mains != nil &&
+		cmd.Power.Json != nil)
+	assert.True(t, ParseBytes([]byte("energy"), &cmd) == nil && cmd.Energy != nil && cmd.Energy.Model == nil)
+	assert.True(t, ParseBytes([]byte("energy model tx 25 sleep 0.01"), &cmd) == nil && *cmd.Energy.Model.Tx == 25 &&
+		cmd.Energy.Model.Rx == nil && *cmd.Energy.Model.Sleep == 0.01)
+	assert.True(t, ParseBytes([]byte("energy reset"), &cmd) == nil && cmd.Energy.Reset != nil)
+	assert.True(t, ParseBytes([]byte("rfsim routers ParamClockDrift -20"), &cmd) == nil && cmd.RfSim != nil &&
+		len(cmd.RfSim.Targets) == 1 && *cmd.RfSim.Targets[0].Group == "routers" &&
+		*cmd.RfSim.Param == "ParamClockDrift" && *cmd.RfSim.Val == -20)
+	assert.True(t, ParseBytes([]byte("rfsim 3-20 7 ParamClockDrift 10.5"), &cmd) == nil && len(cmd.RfSim.Targets) == 2 &&
+		cmd.RfSim.Targets[0].Range.From == 3 && *cmd.RfSim.Targets[0].Range.To == 20 &&
+		cmd.RfSim.Targets[1].Range.From == 7 && cmd.RfSim.Targets[1].Range.To == nil && *cmd.RfSim.Val == 10.5)
+	assert.True(t, ParseBytes([]byte("rfsim all seds ParamClockDrift"), &cmd) == nil && len(cmd.RfSim.Targets) == 2 &&
+		cmd.RfSim.Val == nil)
+	assert.True(t, ParseBytes([]byte("rfsim routers ParamRxSensitivity -95"), &cmd) == nil &&
+		*cmd.RfSim.Param == "ParamRxSensitivity" && *cmd.RfSim.Val == -95)
+	assert.True(t, ParseBytes([]byte("rfsim meds"), &cmd) == nil && cmd.RfSim.Param == nil)
+	assert.True(t, ParseBytes([]byte("rfsim ParamClockDrift 10"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("rfsim raw 3 0a1bff"), &cmd) == nil && cmd.RfSim.Raw != nil &&
+		cmd.RfSim.Raw.Node.Id == 3 && cmd.RfSim.Raw.Data == "0a1bff" && cmd.RfSim.Targets == nil)
+	assert.True(t, ParseBytes([]byte("rfsim raw 3 \"09ff\""), &cmd) == nil && cmd.RfSim.Raw.Data == "09ff")
+	assert.True(t, ParseBytes([]byte("rfsim raw 3 ca fe 01"), &cmd) == nil && cmd.RfSim.Raw.Data == "cafe01")
+	assert.True(t, ParseBytes([]byte("rfsim raw 3"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("attachlog 5"), &cmd) == nil && cmd.AttachLog != nil && cmd.AttachLog.Node.Id == 5 &&
+		cmd.AttachLog.Reset == nil && cmd.AttachLog.Json == nil)
+	assert.True(t, ParseBytes([]byte("attachlog 5 json"), &cmd) == nil && cmd.AttachLog.Json != nil)
+	assert.True(t, ParseBytes([]byte("attachlog reset"), &cmd) == nil && cmd.AttachLog.Reset != nil && cmd.AttachLog.Node == nil)
+	assert.True(t, ParseBytes([]byte("attachlog"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte(`provision 3 dataset "ds.hex"`), &cmd) == nil && cmd.Provision != nil &&
+		cmd.Provision.Node.Id == 3 && cmd.Provision.Kind == "dataset" && cmd.Provision.File == "ds.hex")
+	assert.True(t, ParseBytes([]byte(`provision 3 cert "ds.hex"`), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("alert"), &cmd) == nil && cmd.Alert != nil && cmd.Alert.Add == nil && cmd.Alert.Json == nil)
+	assert.True(t, ParseBytes([]byte("alert add detached for 60s"), &cmd) == nil && cmd.Alert.Add.Condition == "detached" &&
+		cmd.Alert.Add.Threshold == nil && *cmd.Alert.Add.For == 60)
+	assert.True(t, ParseBytes([]byte(`alert add pingloss 10% for 30.5 webhook "http://localhost:9000/hook"`), &cmd) == nil &&
+		*cmd.Alert.Add.Threshold == 10 && *cmd.Alert.Add.For == 30.5 && *cmd.Alert.Add.Webhook == "http://localhost:9000/hook")
+	assert.True(t, ParseBytes([]byte("alert add partitions 1"), &cmd) == nil && *cmd.Alert.Add.Threshold == 1 && cmd.Alert.Add.For == nil)
+	assert.True(t, ParseBytes([]byte("alert del 2"), &cmd) == nil && *cmd.Alert.Del == 2)
+	assert.True(t, ParseBytes([]byte("alert events json"), &cmd) == nil && cmd.Alert.Events != nil && cmd.Alert.Json != nil)
+	assert.True(t, ParseBytes([]byte("alert reset"), &cmd) == nil && cmd.Alert.Reset != nil)
+	assert.True(t, ParseBytes([]byte("alert add offline"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("pcap"), &cmd) == nil && cmd.Pcap != nil && cmd.Pcap.All == nil && cmd.Pcap.Nodes == nil)
+	assert.True(t, ParseBytes([]byte("pcap nodes 1 5-9"), &cmd) == nil && len(cmd.Pcap.Nodes) == 2 &&
+		cmd.Pcap.Nodes[0].From == 1 && cmd.Pcap.Nodes[0].To == nil && cmd.Pcap.Nodes[1].From == 5 && *cmd.Pcap.Nodes[1].To == 9)
+	assert.True(t, ParseBytes([]byte("pcap nodes all"), &cmd) == nil && cmd.Pcap.All != nil)
+	assert.True(t, ParseBytes([]byte("pcap nodes"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte(`load "a.yaml"`), &cmd) == nil && cmd.Load != nil && cmd.Load.File == "a.yaml" &&
+		cmd.Load.Add == nil && cmd.Load.Offset == nil)
+	assert.True(t, ParseBytes([]byte(`load "a.yaml" add offset 500 -300 scale 1.5 rotate -90 ids shift pan file`), &cmd) == nil &&
+		cmd.Load.Add != nil && cmd.Load.Offset.X == 500 && cmd.Load.Offset.Y == -300 && *cmd.Load.Scale == 1.5 &&
+		*cmd.Load.Rotate == -90 && *cmd.Load.Ids == "shift" && *cmd.Load.Pan == "file")
+	assert.True(t, ParseBytes([]byte(`load "a.yaml" ids other`), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte(`save "a.yaml"`), &cmd) == nil && cmd.Save != nil && cmd.Save.File == "a.yaml")
+	assert.True(t, ParseBytes([]byte("energyscan all"), &cmd) == nil && cmd.EnergyScan != nil &&
+		cmd.EnergyScan.Duration == nil && cmd.EnergyScan.Json == nil)
+	assert.True(t, ParseBytes([]byte("energyscan all duration 50 timeout 2 json"), &cmd) == nil &&
+		*cmd.EnergyScan.Duration == 50 && *cmd.EnergyScan.Timeout == 2 && cmd.EnergyScan.Json != nil)
+	assert.True(t, ParseBytes([]byte("energyscan"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("linkstats 3 7"), &cmd) == nil && cmd.LinkStats != nil && cmd.LinkStats.Src.Id == 3 &&
+		cmd.LinkStats.Dst.Id == 7 && cmd.LinkStats.Reset == nil)
+	assert.True(t, ParseBytes([]byte("linkstats reset"), &cmd) == nil && cmd.LinkStats.Reset != nil && cmd.LinkStats.Src == nil)
+	assert.True(t, ParseBytes([]byte("linkstats 3"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("wait node 4 state router"), &cmd) == nil && cmd.Wait != nil &&
+		cmd.Wait.Node.Node.Id == 4 && cmd.Wait.Node.State == "router" && cmd.Wait.Timeout == nil)
+	assert.True(t, ParseBytes([]byte("wait node 4 state leader timeout 120s"), &cmd) == nil && *cmd.Wait.Timeout == 120)
+	assert.True(t, ParseBytes([]byte("wait partitions 1 timeout 30.5"), &cmd) == nil && cmd.Wait.Node == nil &&
+		*cmd.Wait.Partitions == 1 && *cmd.Wait.Timeout == 30.5)
+	assert.True(t, ParseBytes([]byte("wait node 4 state sleepy"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("netdiag sweep 1"), &cmd) == nil && cmd.NetDiag != nil && cmd.NetDiag.Node.Id == 1 && len(cmd.NetDiag.Tlvs) == 0 && cmd.NetDiag.Timeout == nil)
+	assert.True(t, ParseBytes([]byte("netdiag sweep 2 tlv 0 9 16 timeout 10 json"), &cmd) == nil && cmd.NetDiag.Node.Id == 2 &&
+		len(cmd.NetDiag.Tlvs) == 3 && cmd.NetDiag.Tlvs[2] == 16 && *cmd.NetDiag.Timeout == 10 && cmd.NetDiag.Json != nil)
+	assert.True(t, ParseBytes([]byte("netdiag 1"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("netdata 3 json"), &cmd) == nil && cmd.NetData.Node.Id == 3 && cmd.NetData.Json != nil)
+	assert.True(t, ParseBytes([]byte("election"), &cmd) == nil && cmd.Election != nil && cmd.Election.Count == nil &&
+		cmd.Election.Timeout == nil && cmd.Election.Settle == nil)
+	assert.True(t, ParseBytes([]byte("election count 10 timeout 200s settle 30"), &cmd) == nil && cmd.Election.Count.Val == 10 &&
+		cmd.Election.Timeout.Val == 200 && cmd.Election.Settle.Val == 30)
+	assert.True(t, ParseBytes([]byte("roles"), &cmd) == nil && cmd.Roles != nil && cmd.Roles.Reset == nil)
+	assert.True(t, ParseBytes([]byte("roles reset"), &cmd) == nil && cmd.Roles.Reset != nil)
+	assert.True(t, ParseBytes([]byte("timeline"), &cmd) == nil && cmd.Timeline != nil && cmd.Timeline.Save == nil && cmd.Timeline.Reset == nil)
+	assert.True(t, ParseBytes([]byte("timeline save \"timeline.jsonl\""), &cmd) == nil && *cmd.Timeline.Save == "timeline.jsonl")
+	assert.True(t, ParseBytes([]byte("timeline reset"), &cmd) == nil && cmd.Timeline.Reset != nil)
 	assert.True(t, ParseBytes([]byte("exit"), &cmd) == nil && cmd.Exit != nil)
 
 	assert.Nil(t, ParseBytes([]byte("go 1"), &cmd))
@@ -79,7 +446,17 @@ func TestParseBytes(t *testing.T) {
 	assert.Nil(t, ParseBytes([]byte("go 100 speed 2"), &cmd))
 	assert.NotNil(t, cmd.Go)
 
-	assert.True(t, ParseBytes([]byte("joins"), &cmd) == nil && cmd.Joins != nil)
+	assert.True(t, ParseBytes([]byte("jam"), &cmd) == nil && cmd.Jam != nil && cmd.Jam.Node == nil)
+	assert.True(t, ParseBytes([]byte("jam 1"), &cmd) == nil && cmd.Jam != nil && cmd.Jam.Node.Id == 1)
+	assert.True(t, ParseBytes([]byte("jam 1 off"), &cmd) == nil && cmd.Jam != nil && cmd.Jam.Off != nil)
+	assert.True(t, ParseBytes([]byte("jam 1 dst 0x5800"), &cmd) == nil && cmd.Jam != nil && cmd.Jam.DstShort.Val == 0x5800)
+	assert.True(t, ParseBytes([]byte("jam 1 type data dst 1024"), &cmd) == nil && cmd.Jam != nil && cmd.Jam.FrameType.Val == "data")
+
+	assert.True(t, ParseBytes([]byte("joins"), &cmd) == nil && cmd.Joins != nil && cmd.Joins.Stats == nil)
+	assert.True(t, ParseBytes([]byte("joins stats"), &cmd) == nil && cmd.Joins.Stats != nil && cmd.Joins.Stats.Reset == nil)
+	assert.True(t, ParseBytes([]byte("joins stats reset"), &cmd) == nil && cmd.Joins.Stats.Reset != nil)
+	assert.True(t, ParseBytes([]byte("joins json"), &cmd) == nil && cmd.Joins.Json != nil && cmd.Joins.Stats == nil)
+	assert.True(t, ParseBytes([]byte("joins stats json"), &cmd) == nil && cmd.Joins.Stats.Json != nil && cmd.Joins.Stats.Reset == nil)
 
 	assert.True(t, ParseBytes([]byte("move 1 200 300"), &cmd) == nil && cmd.Move != nil)
 
@@ -88,6 +465,10 @@ func TestParseBytes(t *testing.T) {
 
 	assert.True(t, ParseBytes([]byte("nodes"), &cmd) == nil && cmd.Nodes != nil)
 
+	assert.True(t, ParseBytes([]byte("pause 1"), &cmd) == nil && cmd.Pause != nil && cmd.Pause.SigStop == nil)
+	assert.True(t, ParseBytes([]byte("pause 1 2 sigstop"), &cmd) == nil && len(cmd.Pause.Nodes) == 2 && cmd.Pause.SigStop != nil)
+	assert.True(t, ParseBytes([]byte("pause"), &cmd) != nil)
+
 	assert.True(t, ParseBytes([]byte("partitions"), &cmd) == nil && cmd.Partitions != nil)
 	assert.True(t, ParseBytes([]byte("pts"), &cmd) == nil && cmd.Partitions != nil)
 
@@ -104,21 +485,121 @@ func TestParseBytes(t *testing.T) {
 	assert.True(t, ParseBytes([]byte("ping 1 2 datasize 20 interval 3 hoplimit 60"), &cmd) == nil && cmd.Ping != nil)
 	assert.True(t, ParseBytes([]byte("ping 1 2 datasize 20 hoplimit 60 interval 3"), &cmd) == nil && cmd.Ping != nil)
 	assert.True(t, ParseBytes([]byte("pings"), &cmd) == nil && cmd.Pings != nil)
+	assert.True(t, ParseBytes([]byte("pingall"), &cmd) == nil && cmd.PingAll != nil && len(cmd.PingAll.Nodes) == 0)
+	assert.True(t, ParseBytes([]byte("pingall 1 2 3 datasize 32 count 3"), &cmd) == nil && len(cmd.PingAll.Nodes) == 3 && cmd.PingAll.Count.Val == 3)
 
 	assert.True(t, ParseBytes([]byte("plr"), &cmd) == nil && cmd.Plr != nil && cmd.Plr.Val == nil)
 	assert.True(t, ParseBytes([]byte("plr 1"), &cmd) == nil && cmd.Plr != nil && *cmd.Plr.Val == 1)
+	assert.True(t, ParseBytes([]byte("plr data 0.05 ack 0.2"), &cmd) == nil && cmd.Plr != nil && cmd.Plr.Val == nil &&
+		*cmd.Plr.Data == 0.05 && *cmd.Plr.Ack == 0.2)
+	assert.True(t, ParseBytes([]byte("plr ack 1"), &cmd) == nil && cmd.Plr != nil && cmd.Plr.Data == nil &&
+		*cmd.Plr.Ack == 1)
+	assert.True(t, ParseBytes([]byte("plr 0.1 ack 0.2"), &cmd) != nil)
 	assert.True(t, ParseBytes([]byte("radio 1 on"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 1 off"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 1 2 3 on"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 4 5 6 off"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 4 5 6 ft 10 60"), &cmd) == nil && cmd.Radio != nil)
+	assert.True(t, ParseBytes([]byte("resume 1 2"), &cmd) == nil && cmd.Resume != nil && len(cmd.Resume.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("script"), &cmd) == nil && cmd.Script != nil && cmd.Script.File == nil && cmd.Script.Var == nil)
+	assert.True(t, ParseBytes([]byte("script \"node.ot\""), &cmd) == nil && *cmd.Script.File == "node.ot")
+	assert.True(t, ParseBytes([]byte("script off"), &cmd) == nil && cmd.Script.Off != nil)
+	assert.True(t, ParseBytes([]byte("script var prefix \"fd00:db8::/64\""), &cmd) == nil && cmd.Script.Var.Name == "prefix" && cmd.Script.Var.Value == "fd00:db8::/64")
+	assert.True(t, ParseBytes([]byte("watch"), &cmd) == nil && cmd.Watch != nil && len(cmd.Watch.Nodes) == 0 && cmd.Watch.Radio == nil)
+	assert.True(t, ParseBytes([]byte("watch 1 2"), &cmd) == nil && len(cmd.Watch.Nodes) == 2 && cmd.Watch.Radio == nil)
+	assert.True(t, ParseBytes([]byte("watch 3 radio trace"), &cmd) == nil && len(cmd.Watch.Nodes) == 1 && cmd.Watch.Radio.Level == "trace")
+	assert.True(t, ParseBytes([]byte("watch 3 radio verbose"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("unwatch 1 2"), &cmd) == nil && cmd.Unwatch != nil && len(cmd.Unwatch.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("unwatch"), &cmd) != nil)
 	assert.True(t, ParseBytes([]byte("scan 1"), &cmd) == nil && cmd.Scan != nil)
+	assert.True(t, ParseBytes([]byte("send 1 realm"), &cmd) == nil && cmd.Send != nil && *cmd.Send.Scope == "realm")
+	assert.True(t, ParseBytes([]byte("send 1 \"ff04::123\" count 10 interval 2 ds 32"), &cmd) == nil && cmd.Send.Group.Addr == "ff04::123" && cmd.Send.Count.Val == 10)
+	assert.True(t, ParseBytes([]byte("send report"), &cmd) == nil && cmd.Send.Report != nil && cmd.Send.Report.Reset == nil)
+	assert.True(t, ParseBytes([]byte("send report reset"), &cmd) == nil && cmd.Send.Report.Reset != nil)
+	assert.True(t, ParseBytes([]byte("send 1"), &cmd) != nil)
 	assert.True(t, ParseBytes([]byte("speed"), &cmd) == nil && cmd.Speed != nil && cmd.Speed.Speed == nil)
 	assert.True(t, ParseBytes([]byte("speed 1"), &cmd) == nil && cmd.Speed != nil && *cmd.Speed.Speed == 1)
+	assert.True(t, ParseBytes([]byte("speed auto"), &cmd) == nil && cmd.Speed != nil && cmd.Speed.Auto != nil &&
+		cmd.Speed.Auto.Target == nil && cmd.Speed.Auto.Off == nil)
+	assert.True(t, ParseBytes([]byte("speed auto 80%"), &cmd) == nil && cmd.Speed != nil && cmd.Speed.Auto != nil &&
+		*cmd.Speed.Auto.Target == 80)
+	assert.True(t, ParseBytes([]byte("speed auto 50.5"), &cmd) == nil && cmd.Speed != nil && cmd.Speed.Auto != nil &&
+		*cmd.Speed.Auto.Target == 50.5)
+	assert.True(t, ParseBytes([]byte("speed auto off"), &cmd) == nil && cmd.Speed != nil && cmd.Speed.Auto != nil &&
+		cmd.Speed.Auto.Off != nil)
+	assert.True(t, ParseBytes([]byte("srp stats"), &cmd) == nil && cmd.Srp != nil && cmd.Srp.Stats != nil)
+	assert.True(t, ParseBytes([]byte("srp"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("stats window"), &cmd) == nil && cmd.Stats != nil && cmd.Stats.Window.Interval == nil)
+	assert.True(t, ParseBytes([]byte("stats window yaml"), &cmd) == nil && cmd.Stats != nil && cmd.Stats.Window.Yaml != nil)
+	assert.True(t, ParseBytes([]byte("stats window interval 0.5 keep 100"), &cmd) == nil && cmd.Stats.Window.Interval.Val == 0.5 && cmd.Stats.Window.Retention.Val == 100)
+	assert.True(t, ParseBytes([]byte("stats window interval -1"), &cmd) == nil && cmd.Stats.Window.Interval.Val == -1)
+	assert.True(t, ParseBytes([]byte("stats window metrics frames airtime"), &cmd) == nil && len(cmd.Stats.Window.Metrics.Val) == 2)
+	assert.True(t, ParseBytes([]byte("stats window metrics retries cca drops"), &cmd) == nil && len(cmd.Stats.Window.Metrics.Val) == 3)
+	assert.True(t, ParseBytes([]byte("stats"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("upgrade 1 \"./ot-cli-ftd-v2\""), &cmd) == nil && cmd.Upgrade != nil && cmd.Upgrade.Executable == "./ot-cli-ftd-v2")
+	assert.True(t, ParseBytes([]byte("upgrade 1"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("upgrades"), &cmd) == nil && cmd.Upgrades != nil)
 	assert.True(t, ParseBytes([]byte("web"), &cmd) == nil && cmd.Web != nil)
 }
 
+func TestExpandNodeRanges(t *testing.T) {
+	for _, tc := range []struct {
+		cmd     string
+		nodeids []NodeId
+	}{
+		{cmd: "pcap nodes 3", nodeids: []NodeId{3}},
+		{cmd: "pcap nodes 1-3 7", nodeids: []NodeId{1, 2, 3, 7}},
+		{cmd: "pcap nodes 5-5 2-3", nodeids: []NodeId{5, 2, 3}},
+		{cmd: "pcap nodes 1 5-3", nodeids: nil},
+		{cmd: "pcap nodes 0-2", nodeids: nil},
+	} {
+		var cmd Command
+		assert.Nil(t, ParseBytes([]byte(tc.cmd), &cmd))
+		nodeids, err := expandNodeRanges(cmd.Pcap.Nodes)
+		assert.Equal(t, tc.nodeids, nodeids, tc.cmd)
+		assert.Equal(t, tc.nodeids == nil, err != nil, tc.cmd)
+	}
+}
+
 func TestContextlessCommandPat(t *testing.T) {
 	assert.True(t, contextLessCommandsPat.MatchString("exit"))
 	assert.True(t, contextLessCommandsPat.MatchString("node 1"))
 }
+
+func TestOutputRecords(t *testing.T) {
+	records := []outputRecord{
+		{{Key: "id", Text: "1", Val: 1}, {Key: "state", Text: "leader", Val: "leader"}},
+		{{Key: "id", Text: "2", Val: 2}, {Key: "state", Text: "router", Val: "router"}},
+		{{Key: "id", Text: "12", Val: 12}, {Key: "state", Text: "router", Val: "router"}},
+	}
+	output := func(cmd string) (string, error) {
+		var command Command
+		assert.Nil(t, ParseBytes([]byte(cmd), &command))
+		buf := &bytes.Buffer{}
+		cc := &CommandContext{output: buf}
+		cc.outputRecords(records, &command.Nodes.Output, "\t")
+		return buf.String(), cc.Err()
+	}
+
+	out, err := output("nodes")
+	assert.Nil(t, err)
+	assert.Equal(t, "id=1\tstate=leader\nid=2\tstate=router\nid=12\tstate=router\n", out)
+
+	out, _ = output("nodes state=router")
+	assert.Equal(t, "id=2\tstate=router\nid=12\tstate=router\n", out)
+	out, _ = output("nodes state!=router")
+	assert.Equal(t, "id=1\tstate=leader\n", out)
+	out, _ = output("nodes id~2 columns state")
+	assert.Equal(t, "state=router\nstate=router\n", out)
+	out, _ = output("nodes state=router count")
+	assert.Equal(t, "2\n", out)
+	out, _ = output("nodes id=12 json")
+	assert.Equal(t, "[\n  {\n    \"id\": 12,\n    \"state\": \"router\"\n  }\n]\n", out)
+	out, _ = output("nodes state=child json")
+	assert.Equal(t, "[]\n", out)
+
+	_, err = output("nodes role=router")
+	assert.NotNil(t, err)
+	_, err = output("nodes columns role")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// outputField is a key=value field of an output record.
+type outputField struct {
+	Key   string
+	Text  string      // text of the value, which filters match against
+	Val   interface{} // value in JSON output
+	Width int         // minimum width of the text in text output
+}
+
+// outputRecord is a line of output of commands with large results, e.g. a node of `nodes`.
+type outputRecord []outputField
+
+func (r outputRecord) get(key string) (outputField, bool) {
+	for _, f := range r {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return outputField{}, false
+}
+
+func (f *OutputFilter) match(r outputRecord) bool {
+	field, ok := r.get(f.Key)
+	switch f.Op {
+	case "=":
+		return ok && field.Text == f.Value
+	case "~":
+		return ok && strings.Contains(field.Text, f.Value)
+	case "!=":
+		return !ok || field.Text != f.Value
+	default:
+		simplelogger.Panicf("invalid filter operator: %s", f.Op)
+		return false
+	}
+}
+
+// isJsonOutput returns if the command outputs JSON, because of the json flag of the command or the JSON output format
+// of the runner.
+func (cc *CommandContext) isJsonOutput(flag *JsonFlag) bool {
+	return flag != nil || (cc.rt != nil && cc.rt.jsonOutput)
+}
+
+// outputJson outputs the value in indented JSON format.
+func (cc *CommandContext) outputJson(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	simplelogger.PanicIfError(err)
+	cc.outputf("%s\n", data)
+}
+
+// outputRecords outputs the records which match all filters of the output flags, as text lines with fields joined by
+// sep, as JSON, or as the number of matching records. Filters and columns must be keys of the records.
+func (cc *CommandContext) outputRecords(records []outputRecord, flags *OutputFlags, sep string) {
+	if len(records) > 0 {
+		keys := records[0]
+		for _, f := range flags.Filters {
+			if _, ok := keys.get(f.Key); !ok {
+				cc.err = errors.Errorf("unknown field: %s", f.Key)
+				return
+			}
+		}
+		for _, col := range flags.Columns {
+			if _, ok := keys.get(col); !ok {
+				cc.err = errors.Errorf("unknown field: %s", col)
+				return
+			}
+		}
+	}
+
+	var matched []outputRecord
+	for _, r := range records {
+		match := true
+		for i := range flags.Filters {
+			if !flags.Filters[i].match(r) {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+
+		if len(flags.Columns) > 0 {
+			var selected outputRecord
+			for _, col := range flags.Columns {
+				f, _ := r.get(col)
+				selected = append(selected, f)
+			}
+			r = selected
+		}
+		matched = append(matched, r)
+	}
+
+	if flags.Count != nil {
+		cc.outputf("%d\n", len(matched))
+		return
+	}
+
+	if cc.isJsonOutput(flags.Json) {
+		items := make([]map[string]interface{}, 0, len(matched))
+		for _, r := range matched {
+			item := map[string]interface{}{}
+			for _, f := range r {
+				item[f.Key] = f.Val
+			}
+			items = append(items, item)
+		}
+		cc.outputJson(items)
+		return
+	}
+
+	for _, r := range matched {
+		var line strings.Builder
+		for i, f := range r {
+			if i > 0 {
+				line.WriteString(sep)
+			}
+			if f.Width > 0 && i < len(r)-1 {
+				_, _ = fmt.Fprintf(&line, "%s=%-*s", f.Key, f.Width, f.Text)
+			} else {
+				_, _ = fmt.Fprintf(&line, "%s=%s", f.Key, f.Text)
+			}
+		}
+		cc.outputf("%s\n", line.String())
+	}
+}
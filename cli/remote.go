@@ -0,0 +1,199 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/simulation"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// remoteClient is the state of a remote CLI connection. Each connection has its own current session, and its own node
+// context and output format in each session.
+type remoteClient struct {
+	lock    sync.Mutex
+	current int
+	runners map[*simulation.Simulation]*CmdRunner
+	exited  bool
+}
+
+// runner returns the runner of the client for the session runner rt.
+func (c *remoteClient) runner(rt *CmdRunner) *CmdRunner {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cr := c.runners[rt.sim]; cr != nil {
+		return cr
+	}
+
+	cr := &CmdRunner{
+		ctx:           rt.ctx,
+		sim:           rt.sim,
+		contextNodeId: InvalidNodeId,
+		sessions:      rt.sessions,
+		jsonOutput:    rt.jsonOutput,
+		client:        c,
+	}
+	c.runners[rt.sim] = cr
+	return cr
+}
+
+// switchSession makes the session current for the client.
+func (c *remoteClient) switchSession(sm *SessionManager, id int) error {
+	if sm.runner(id) == nil {
+		return errors.Errorf("session %d not found", id)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.current = id
+	return nil
+}
+
+// ServeRemote serves the CLI to remote clients on the TCP listen address until ctx is done. If token is not empty,
+// clients must send the token as the first line before running commands. Since the CLI can run arbitrary
+// executables on the host, a non-loopback listen address is refused unless token is set.
+func ServeRemote(ctx context.Context, listenAddr string, rt *CmdRunner, token string) error {
+	if token == "" && !isLoopbackAddr(listenAddr) {
+		return errors.Errorf("remote CLI on non-loopback address %s requires a control token", listenAddr)
+	}
+
+	ln, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+
+	simplelogger.Infof("OTNS remote CLI serving on tcp://%s ...", listenAddr)
+	return ServeRemoteListener(ctx, ln, rt, token)
+}
+
+// isLoopbackAddr returns if the listen address only accepts connections from the local host. An empty host listens
+// on all interfaces, and host names other than localhost are not trusted to resolve to loopback addresses.
+func isLoopbackAddr(listenAddr string) bool {
+	host, _, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ServeRemoteListener serves the CLI to remote clients accepted by the listener until ctx is done. The listener and all
+// connections are closed when ServeRemoteListener returns.
+func ServeRemoteListener(ctx context.Context, ln net.Listener, rt *CmdRunner, token string) error {
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			_ = ln.Close()
+			return err
+		}
+
+		go serveRemoteConn(ctx, conn, rt, token)
+	}
+}
+
+func serveRemoteConn(ctx context.Context, conn net.Conn, rt *CmdRunner, token string) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+
+	addr := conn.RemoteAddr()
+	reader := bufio.NewReader(conn)
+	if token != "" {
+		if _, err := io.WriteString(conn, "Token: "); err != nil {
+			return
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(line)), []byte(token)) != 1 {
+			simplelogger.Warnf("remote CLI: rejected client %s with invalid token", addr)
+			_, _ = io.WriteString(conn, "Error: invalid token\n")
+			return
+		}
+	}
+
+	simplelogger.Infof("remote CLI: client %s connected", addr)
+	defer simplelogger.Infof("remote CLI: client %s disconnected", addr)
+
+	client := &remoteClient{
+		runners: map[*simulation.Simulation]*CmdRunner{},
+	}
+	if rt.sessions != nil {
+		client.current = rt.sessions.main
+	}
+	cr := client.runner(rt)
+
+	for !client.exited {
+		if _, err := io.WriteString(conn, cr.GetPrompt()); err != nil {
+			return
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return
+		}
+
+		cmd := strings.TrimSpace(line)
+		if len(cmd) == 0 {
+			continue
+		}
+
+		if err := cr.HandleCommand(cmd, conn); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func dialRemote(t *testing.T, addr string) (net.Conn, *bufio.Reader) {
+	conn, err := net.Dial("tcp", addr)
+	assert.Nil(t, err)
+	return conn, bufio.NewReader(conn)
+}
+
+func readUntilPrompt(t *testing.T, reader *bufio.Reader) string {
+	var out []byte
+	for {
+		b, err := reader.ReadByte()
+		assert.Nil(t, err)
+		out = append(out, b)
+		if len(out) >= len(Prompt) && string(out[len(out)-len(Prompt):]) == Prompt {
+			return string(out)
+		}
+	}
+}
+
+func TestServeRemote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	rt := &CmdRunner{contextNodeId: InvalidNodeId}
+	go func() {
+		_ = ServeRemoteListener(ctx, ln, rt, "secret")
+	}()
+	addr := ln.Addr().String()
+
+	// invalid token
+	conn, reader := dialRemote(t, addr)
+	_, err = io.WriteString(conn, "guess\n")
+	assert.Nil(t, err)
+	out, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "Token: Error: invalid token\n", string(out))
+	_ = conn.Close()
+
+	conn1, reader1 := dialRemote(t, addr)
+	defer conn1.Close()
+	conn2, reader2 := dialRemote(t, addr)
+	defer conn2.Close()
+	for _, conn := range []net.Conn{conn1, conn2} {
+		_, err = io.WriteString(conn, "secret\n")
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, "Token: "+Prompt, readUntilPrompt(t, reader1))
+	assert.Equal(t, "Token: "+Prompt, readUntilPrompt(t, reader2))
+
+	// each client has its own output format
+	_, err = io.WriteString(conn1, "format json\nformat\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "Done\n"+Prompt, readUntilPrompt(t, reader1))
+	assert.Equal(t, "json\nDone\n"+Prompt, readUntilPrompt(t, reader1))
+	_, err = io.WriteString(conn2, "format\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "text\nDone\n"+Prompt, readUntilPrompt(t, reader2))
+	assert.False(t, rt.jsonOutput)
+
+	// exit only disconnects the client
+	_, err = io.WriteString(conn1, "exit\n")
+	assert.Nil(t, err)
+	out, err = io.ReadAll(reader1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Done\n", string(out))
+	_, err = io.WriteString(conn2, "format\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "text\nDone\n"+Prompt, readUntilPrompt(t, reader2))
+}
+
+func TestServeRemoteRequiresToken(t *testing.T) {
+	assert.True(t, isLoopbackAddr("localhost:9000"))
+	assert.True(t, isLoopbackAddr("127.0.0.1:9000"))
+	assert.True(t, isLoopbackAddr("[::1]:9000"))
+	assert.False(t, isLoopbackAddr(":9000"))
+	assert.False(t, isLoopbackAddr("0.0.0.0:9000"))
+	assert.False(t, isLoopbackAddr("192.168.1.10:9000"))
+	assert.False(t, isLoopbackAddr("labserver:9000"))
+	assert.False(t, isLoopbackAddr("9000"))
+
+	err := ServeRemote(context.Background(), "0.0.0.0:0", nil, "")
+	assert.NotNil(t, err)
+}
@@ -13,6 +13,11 @@ type CliHandler interface {
 	GetPrompt() string
 }
 
+// AsyncOutputHandler is a CliHandler which also prints output between commands, e.g. to report background activity.
+type AsyncOutputHandler interface {
+	SetAsyncOutput(output io.Writer)
+}
+
 type CliOptions struct {
 	EchoInput bool
 	Stdin     *os.File
@@ -98,6 +103,10 @@ func RunCli(handler CliHandler, options *CliOptions) error {
 		_ = l.Close()
 	}()
 
+	if h, ok := handler.(AsyncOutputHandler); ok {
+		h.SetAsyncOutput(l.Stdout())
+	}
+
 	for {
 		// update the prompt
 		l.SetPrompt(handler.GetPrompt())
@@ -0,0 +1,250 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/simulation"
+	"github.com/openthread/ot-ns/threadconst"
+)
+
+const (
+	// MaxSessionId is the maximum session ID, for which all ports of the session are still valid.
+	MaxSessionId = (65535 - threadconst.InitialDispatcherPort) / threadconst.WellKnownNodeId
+)
+
+var (
+	sessionCommandPat = regexp.MustCompile(`^\s*session\b`)
+)
+
+// SessionFactory creates and starts the simulation of a new session with the session ID as the port offset.
+// The simulation must stop when ctx is cancelled.
+type SessionFactory func(ctx *progctx.ProgCtx, id int) (*simulation.Simulation, error)
+
+// SessionInfo describes a session.
+type SessionInfo struct {
+	Id      int  `yaml:"id"`
+	Port    int  `yaml:"port"`
+	Nodes   int  `yaml:"nodes"`
+	Current bool `yaml:"current"`
+}
+
+// SessionManager hosts several independent simulations (sessions) in one OTNS process. A session is identified by its
+// port offset, from which its dispatcher port, output files and web endpoints are derived. The console runs commands
+// in the current session.
+type SessionManager struct {
+	lock     sync.Mutex
+	ctx      *progctx.ProgCtx
+	main     int
+	current  int
+	factory  SessionFactory
+	sessions map[int]*session
+}
+
+type session struct {
+	ctx *progctx.ProgCtx
+	rt  *CmdRunner
+}
+
+// NewSessionManager creates a SessionManager with the simulation of rt as the main session.
+// The main session lives as long as the program and can not be deleted.
+func NewSessionManager(rt *CmdRunner, factory SessionFactory) *SessionManager {
+	id := rt.sim.PortOffset()
+	sm := &SessionManager{
+		ctx:      rt.ctx,
+		main:     id,
+		current:  id,
+		factory:  factory,
+		sessions: map[int]*session{id: {ctx: rt.ctx, rt: rt}},
+	}
+	rt.sessions = sm
+	return sm
+}
+
+// New creates a new session and returns its ID. If id is negative, the lowest free ID is used.
+func (sm *SessionManager) New(id int) (newId int, err error) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	if id < 0 {
+		for id = 0; sm.sessions[id] != nil; id++ {
+		}
+	}
+
+	if id > MaxSessionId {
+		return 0, errors.Errorf("session ID must be in range [0, %d]", MaxSessionId)
+	}
+	if sm.sessions[id] != nil {
+		return 0, errors.Errorf("session %d already exists", id)
+	}
+
+	ctx := progctx.New(sm.ctx)
+	defer func() {
+		// the dispatcher panics if it fails to listen
+		if r := recover(); r != nil {
+			err = fmt.Errorf("create session %d failed: %v", id, r)
+		}
+		if err != nil {
+			ctx.Cancel(err)
+		}
+	}()
+
+	sim, err := sm.factory(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	// sessions share the console output of the main session
+	main := sm.sessions[sm.main]
+	rt := newCmdRunner(ctx, sim, main.rt.async)
+	rt.sessions = sm
+	rt.jsonOutput = main.rt.jsonOutput
+	sm.sessions[id] = &session{ctx: ctx, rt: rt}
+
+	// the program exits after all sessions exit
+	sm.ctx.WaitAdd("session", 1)
+	go func() {
+		defer sm.ctx.WaitDone("session")
+		<-ctx.Done()
+		ctx.Wait()
+	}()
+
+	return id, nil
+}
+
+// Switch makes the session current.
+func (sm *SessionManager) Switch(id int) error {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	if sm.sessions[id] == nil {
+		return errors.Errorf("session %d not found", id)
+	}
+	sm.current = id
+	return nil
+}
+
+// Delete stops the simulation of the session and deletes the session. If the session is current, the main session
+// becomes current.
+func (sm *SessionManager) Delete(id int) error {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	s := sm.sessions[id]
+	if s == nil {
+		return errors.Errorf("session %d not found", id)
+	}
+	if id == sm.main {
+		return errors.Errorf("main session %d can not be deleted", id)
+	}
+
+	delete(sm.sessions, id)
+	if sm.current == id {
+		sm.current = sm.main
+	}
+	s.ctx.Cancel("session deleted")
+	return nil
+}
+
+// List returns all sessions ordered by ID.
+func (sm *SessionManager) List() []SessionInfo {
+	var infos []SessionInfo
+	var runners []*CmdRunner
+	sm.lock.Lock()
+	for id, s := range sm.sessions {
+		infos = append(infos, SessionInfo{
+			Id:      id,
+			Port:    threadconst.InitialDispatcherPort + id*threadconst.WellKnownNodeId,
+			Current: id == sm.current,
+		})
+		runners = append(runners, s.rt)
+	}
+	sm.lock.Unlock()
+
+	// the simulations are queried without the lock, so that a busy session does not block the other sessions
+	for i, rt := range runners {
+		info := &infos[i]
+		rt.postAsyncWait(func(sim *simulation.Simulation) {
+			info.Nodes = len(sim.Nodes())
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Id < infos[j].Id
+	})
+	return infos
+}
+
+func (sm *SessionManager) currentSession() (int, *CmdRunner) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	return sm.current, sm.sessions[sm.current].rt
+}
+
+// runner returns the runner of the session, or nil if the session is not found.
+func (sm *SessionManager) runner(id int) *CmdRunner {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	if s := sm.sessions[id]; s != nil {
+		return s.rt
+	}
+	return nil
+}
+
+// currentSession returns the ID and the runner of the current session of the runner. The console shares the current
+// session of the SessionManager, while each remote client has its own.
+func (rt *CmdRunner) currentSession() (int, *CmdRunner) {
+	if rt.client == nil {
+		return rt.sessions.currentSession()
+	}
+
+	rt.client.lock.Lock()
+	id := rt.client.current
+	rt.client.lock.Unlock()
+
+	cur := rt.sessions.runner(id)
+	if cur == nil {
+		// the current session was deleted
+		id = rt.sessions.main
+		cur = rt.sessions.runner(id)
+		_ = rt.client.switchSession(rt.sessions, id)
+	}
+	return id, rt.client.runner(cur)
+}
+
+func isSessionCommand(line string) bool {
+	return sessionCommandPat.MatchString(line)
+}
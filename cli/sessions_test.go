@@ -0,0 +1,136 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/simulation"
+	"github.com/openthread/ot-ns/threadconst"
+)
+
+// testSessionBase is the ID of the main session of the tests, high enough not to conflict with a running OTNS.
+const testSessionBase = 40
+
+func newTestSessionFactory(t *testing.T) SessionFactory {
+	dir := t.TempDir()
+	return func(ctx *progctx.ProgCtx, id int) (*simulation.Simulation, error) {
+		cfg := simulation.DefaultConfig()
+		cfg.DispatcherPort = threadconst.InitialDispatcherPort + id*threadconst.WellKnownNodeId
+		cfg.NodeDir = dir
+		dispatcherCfg := dispatcher.DefaultConfig()
+		dispatcherCfg.NoPcap = true
+		sim, err := simulation.NewSimulation(ctx, cfg, dispatcherCfg)
+		if err != nil {
+			return nil, err
+		}
+		go sim.Run()
+		return sim, nil
+	}
+}
+
+func newTestSessionManager(t *testing.T) (*SessionManager, *progctx.ProgCtx) {
+	ctx := progctx.New(nil)
+	t.Cleanup(func() {
+		ctx.Cancel("test done")
+		ctx.Wait()
+	})
+
+	factory := newTestSessionFactory(t)
+	sim, err := factory(ctx, testSessionBase)
+	assert.Nil(t, err)
+	rt := newCmdRunner(ctx, sim, newAsyncOutput(ctx))
+	return NewSessionManager(rt, factory), ctx
+}
+
+func TestSessionManager(t *testing.T) {
+	sm, _ := newTestSessionManager(t)
+
+	id, err := sm.New(testSessionBase + 2)
+	assert.Nil(t, err)
+	assert.Equal(t, testSessionBase+2, id)
+	_, err = sm.New(testSessionBase + 2)
+	assert.NotNil(t, err)
+	_, err = sm.New(MaxSessionId + 1)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, sm.Switch(testSessionBase+2))
+	assert.NotNil(t, sm.Switch(testSessionBase+1))
+	assert.Equal(t, []SessionInfo{
+		{Id: testSessionBase, Port: threadconst.InitialDispatcherPort + testSessionBase*threadconst.WellKnownNodeId},
+		{Id: testSessionBase + 2, Port: threadconst.InitialDispatcherPort + (testSessionBase+2)*threadconst.WellKnownNodeId,
+			Current: true},
+	}, sm.List())
+
+	// deleting the current session makes the main session current
+	assert.NotNil(t, sm.Delete(testSessionBase))
+	assert.Nil(t, sm.Delete(testSessionBase+2))
+	assert.NotNil(t, sm.Delete(testSessionBase+2))
+	infos := sm.List()
+	assert.Equal(t, 1, len(infos))
+	assert.True(t, infos[0].Current)
+}
+
+func TestSessionManagerListBusySession(t *testing.T) {
+	sm, _ := newTestSessionManager(t)
+	_, err := sm.New(testSessionBase + 1)
+	assert.Nil(t, err)
+
+	// block the simulation of the new session
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	sm.runner(testSessionBase+1).sim.PostAsync(false, func() {
+		close(blocked)
+		<-release
+	})
+	<-blocked
+
+	listed := make(chan []SessionInfo)
+	go func() {
+		listed <- sm.List()
+	}()
+
+	// other sessions are still usable while List waits for the busy session
+	switched := make(chan error)
+	go func() {
+		switched <- sm.Switch(testSessionBase)
+	}()
+	select {
+	case err = <-switched:
+		assert.Nil(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Switch blocked by List")
+	}
+
+	close(release)
+	assert.Equal(t, 2, len(<-listed))
+}
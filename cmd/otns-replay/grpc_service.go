@@ -29,17 +29,14 @@ package main
 import (
 	"bufio"
 	"context"
+	"io"
 	"os"
 	"time"
 
 	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
 	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
-	"google.golang.org/protobuf/encoding/prototext"
-)
-
-var (
-	unmarshalOptions = prototext.UnmarshalOptions{}
 )
 
 type grpcService struct {
@@ -89,23 +86,28 @@ func (gs *grpcService) visualizeStream(stream pb.VisualizeGrpcService_VisualizeS
 		}
 	}()
 
-	replay, err := os.Open(gs.replayFile)
+	replayFile, err := os.Open(gs.replayFile)
 	simplelogger.PanicIfError(err)
+	defer replayFile.Close()
 
-	scanner := bufio.NewScanner(bufio.NewReader(replay))
-	scanner.Split(bufio.ScanLines)
+	reader, err := replay.NewReader(bufio.NewReader(replayFile))
+	simplelogger.PanicIfError(err)
+	if reader.Version() < replay.FormatVersion {
+		simplelogger.Infof("replay format version %d is upgraded to version %d while playing", reader.Version(),
+			replay.FormatVersion)
+	}
 
 	startTime := time.Now()
 
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		simplelogger.Infof("visualize: %#v", line)
-
-		var entry pb.ReplayEntry
-		err = unmarshalOptions.Unmarshal([]byte(line), &entry)
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
 		simplelogger.PanicIfError(err)
 
+		simplelogger.Infof("visualize: %v", entry)
+
 		playTime := startTime.Add(time.Duration(entry.Timestamp) * time.Microsecond)
 		time.Sleep(time.Until(playTime))
 
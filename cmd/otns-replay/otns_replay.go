@@ -29,25 +29,39 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 
 	"github.com/openthread/ot-ns/progctx"
 	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
 	"github.com/openthread/ot-ns/web"
 	webSite "github.com/openthread/ot-ns/web/site"
+	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
 	"google.golang.org/grpc"
 )
 
 var args struct {
 	ReplayFile string
+	Convert    string // output file of the converted replay, or ""
 }
 
 func parseArgs() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s <replay>\n       %s convert <replay> <output>\n", os.Args[0],
+			os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	if len(flag.Args()) != 1 {
+	if flag.NArg() == 3 && flag.Arg(0) == "convert" {
+		args.ReplayFile, args.Convert = flag.Arg(1), flag.Arg(2)
+		return
+	}
+	if flag.NArg() != 1 {
 		flag.Usage()
 		os.Exit(1)
 	}
@@ -57,6 +71,13 @@ func parseArgs() {
 
 func main() {
 	parseArgs()
+	if args.Convert != "" {
+		if err := convertReplay(args.ReplayFile, args.Convert); err != nil {
+			fmt.Fprintf(os.Stderr, "convert %s failed: %v\n", args.ReplayFile, err)
+			os.Exit(1)
+		}
+		return
+	}
 	checkReplayFile(args.ReplayFile)
 	simplelogger.SetLevel(simplelogger.InfoLevel)
 
@@ -76,7 +97,7 @@ func main() {
 	}()
 
 	go func() {
-		web.ConfigWeb("", 8998, 8999, 8997)
+		web.ConfigWeb("", 8998, 8999, 8997, "")
 		_ = web.OpenWeb(ctx)
 	}()
 
@@ -84,6 +105,34 @@ func main() {
 	simplelogger.Errorf("server quit: %v", err)
 }
 
+// convertReplay converts the replay file to the current format version.
+func convertReplay(filename string, output string) error {
+	if filepath.Clean(filename) == filepath.Clean(output) {
+		return errors.Errorf("the output must be another file")
+	}
+
+	in, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	version, entries, err := replay.Convert(in, out)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("converted %d entries from format version %d to %d\n", entries, version, replay.FormatVersion)
+	return nil
+}
+
 func checkReplayFile(filename string) {
 	f, err := os.Open(filename)
 	simplelogger.PanicIfError(err)
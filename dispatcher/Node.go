@@ -27,7 +27,6 @@
 package dispatcher
 
 import (
-	"encoding/binary"
 	"fmt"
 	"math"
 	"net"
@@ -40,6 +39,9 @@ import (
 const (
 	maxPingResultCount = 1000
 	maxJoinResultCount = 1000
+
+	// MaxPingDelayUs is the delay after which a ping request without reply is considered timed out.
+	MaxPingDelayUs uint64 = 10 * 1000000
 )
 
 type pingRequest struct {
@@ -48,20 +50,33 @@ type pingRequest struct {
 	DataSize  int
 }
 type PingResult struct {
-	Dst      string
-	DataSize int
-	Delay    uint64
+	Timestamp uint64 // time of the ping request
+	Dst       string
+	DataSize  int
+	Delay     uint64
 }
 
 type joinerSession struct {
-	StartTime  uint64
-	JoinedTime uint64
-	StopTime   uint64
+	StartTime     uint64
+	ConnectTime   uint64
+	ConnectedTime uint64
+	JoinedTime    uint64
+	StopTime      uint64
 }
 
+// JoinResult is the result of a joiner session. Durations are in us, and the duration of a phase which was not reached
+// is 0.
 type JoinResult struct {
 	JoinDuration    uint64
 	SessionDuration uint64
+	// DiscoverDuration is the time to discover a joiner router.
+	DiscoverDuration uint64
+	// DtlsDuration is the time of the DTLS handshake with the commissioner.
+	DtlsDuration uint64
+	// NetworkDataDuration is the time to fetch the network credentials after the DTLS handshake.
+	NetworkDataDuration uint64
+	// AttachDuration is the time from joined to the first attach to a parent.
+	AttachDuration uint64
 }
 
 type Node struct {
@@ -78,32 +93,64 @@ type Node struct {
 	peerAddr      *net.UDPAddr
 	failureCtrl   *FailureCtrl
 	isFailed      bool
+	isPaused      bool
+	pausedAlarm   uint64
+	booting       bool // the node is paused until its boot delay elapses
+	bootCrashed   bool
 	radioRange    int
 	pendingPings  []*pingRequest
 	pingResults   []*PingResult
 	joinerState   OtJoinerState
 	joinerSession *joinerSession
 	joinResults   []*JoinResult
+	pendingAttach *JoinResult
+	joinedTime    uint64
+	jamFilter     *JamFilter
+	clockDrift    clockDrift
+	dutyCycle     *dutyCycle
+	cpuFactor     float64 // CPU speed factor, or 0 for 1
+	antenna       *AntennaPattern
+	radioParams   nodeRadioParams
+	macCounters   MacCounters
+	parent        uint64
+	rangingEvents bool
+	logThrottle   logThrottle
+	radios        []*Radio // additional radios
+	resourceUsage ResourceUsage
+	powerSource   PowerSource
+	energy        energyMeter
+	framing       int    // negotiated event framing version, or 0 for FramingV1
+	batch         []byte // pending batch of events with FramingV2
+	batchCount    int
+	shm           *shmTransport // shared memory transport announced by the node
+	tcpConn       net.Conn      // TCP connection of the node, or nil if it uses the UDP socket
+	recvEvents    uint64        // events received from the node
+	lastEventTime uint64        // time of the last event received from the node
+	site          string        // site of the node on the backbone, or "" if the node is not on the backbone
 }
 
 func newNode(d *Dispatcher, nodeid NodeId, x, y int, radioRange int) *Node {
 	simplelogger.AssertTrue(radioRange >= 0)
 
 	nc := &Node{
-		D:           d,
-		Id:          nodeid,
-		CurTime:     d.CurTime,
-		CreateTime:  d.CurTime,
-		X:           x,
-		Y:           y,
-		ExtAddr:     InvalidExtAddr,
-		Rloc16:      threadconst.InvalidRloc16,
-		Role:        OtDeviceRoleDisabled,
-		peerAddr:    nil, // peer address will be set when the first event is received
-		radioRange:  radioRange,
-		joinerState: OtJoinerStateIdle,
+		D:             d,
+		Id:            nodeid,
+		CurTime:       d.CurTime,
+		CreateTime:    d.CurTime,
+		lastEventTime: d.CurTime,
+		X:             x,
+		Y:             y,
+		ExtAddr:       InvalidExtAddr,
+		Rloc16:        threadconst.InvalidRloc16,
+		Role:          OtDeviceRoleDisabled,
+		peerAddr:      nil, // peer address will be set when the first event is received
+		radioRange:    radioRange,
+		joinerState:   OtJoinerStateIdle,
 	}
 
+	nc.logThrottle.limit = d.cfg.LogRateLimit
+	nc.powerSource.Type = PowerMains
+	nc.energy.reset(d.CurTime, &nc.powerSource)
 	nc.failureCtrl = newFailureCtrl(nc, NonFailTime)
 
 	return nc
@@ -114,18 +161,27 @@ func (node *Node) String() string {
 }
 
 func (node *Node) Send(elapsed uint64, data []byte) {
-	msg := make([]byte, len(data)+11)
-	binary.LittleEndian.PutUint64(msg[:8], elapsed)
-	msg[8] = eventTypeRadioReceived
-	binary.LittleEndian.PutUint16(msg[9:11], uint16(len(data)))
-	n := copy(msg[11:], data)
-	simplelogger.AssertTrue(n == len(data))
-
+	msg := (&event{Delay: elapsed, Type: eventTypeRadioReceived, Data: data}).Serialize()
 	node.SendMessage(msg)
 }
 
 func (node *Node) SendMessage(msg []byte) {
-	if node.peerAddr != nil {
+	node.D.perf.onMessageSent()
+	if node.D.offlineSink != nil {
+		node.D.sendOfflineMessage(node, msg)
+	} else if node.shm != nil {
+		node.sendSharedMemory(msg)
+	} else if node.framing >= FramingV2 {
+		node.queueBatchedMessage(msg)
+	} else {
+		node.writeMessage(msg)
+	}
+}
+
+func (node *Node) writeMessage(msg []byte) {
+	if node.tcpConn != nil {
+		node.writeTcpMessage(msg)
+	} else if node.peerAddr != nil {
 		_, _ = node.D.udpln.WriteToUDP(msg, node.peerAddr)
 	} else {
 		simplelogger.Errorf("%s does not have a peer address", node)
@@ -143,9 +199,14 @@ func (node *Node) IsFailed() bool {
 	return node.isFailed
 }
 
+func (node *Node) IsPaused() bool {
+	return node.isPaused
+}
+
 func (node *Node) Fail() {
 	if !node.isFailed {
 		node.isFailed = true
+		node.D.addTimelineEvent(TimelineNodeFail, node.Id, "", "")
 		node.D.cbHandler.OnNodeFail(node.Id)
 		node.D.vis.OnNodeFail(node.Id)
 	}
@@ -154,6 +215,7 @@ func (node *Node) Fail() {
 func (node *Node) Recover() {
 	if node.isFailed {
 		node.isFailed = false
+		node.D.addTimelineEvent(TimelineNodeRecover, node.Id, "", "")
 		node.D.cbHandler.OnNodeRecover(node.Id)
 		node.D.vis.OnNodeRecover(node.Id)
 	}
@@ -162,7 +224,13 @@ func (node *Node) Recover() {
 func (node *Node) DumpStat() string {
 	d := node.D
 	alarmTs := d.alarmMgr.GetTimestamp(node.Id)
-	return fmt.Sprintf("CurTime=%v, AlarmTs=%v, Failed=%-5v, RecoverTS=%v", node.CurTime, alarmTs, node.isFailed, node.failureCtrl.recoverTs)
+	return fmt.Sprintf("CurTime=%v, AlarmTs=%v, Failed=%-5v, Paused=%-5v, RecoverTS=%v", node.CurTime, alarmTs, node.isFailed,
+		node.isPaused, node.failureCtrl.recoverTs)
+}
+
+// JamFilter returns the reactive jamming filter of the node, or nil if the node is not a jammer.
+func (node *Node) JamFilter() *JamFilter {
+	return node.jamFilter
 }
 
 func (node *Node) SetFailTime(failTime FailTime) {
@@ -187,15 +255,14 @@ func (node *Node) onPingReply(timestamp uint64, dstaddr string, datasize int, ho
 		// if datasize < 4, timestamp is 0, these ping replies are ignored
 		return
 	}
-	const maxPingDelayUs uint64 = 10 * 1000000
 	var leftPingRequests []*pingRequest
 	for _, req := range node.pendingPings {
 		if req.Timestamp == timestamp && req.Dst == dstaddr {
 			// ping replied
-			node.addPingResult(req.Dst, req.DataSize, node.D.CurTime-req.Timestamp)
-		} else if req.Timestamp+maxPingDelayUs < node.D.CurTime {
+			node.addPingResult(req, node.D.CurTime-req.Timestamp)
+		} else if req.Timestamp+MaxPingDelayUs < node.D.CurTime {
 			// ping timeout
-			node.addPingResult(req.Dst, req.DataSize, maxPingDelayUs)
+			node.addPingResult(req, MaxPingDelayUs)
 		} else {
 			leftPingRequests = append(leftPingRequests, req)
 		}
@@ -204,24 +271,51 @@ func (node *Node) onPingReply(timestamp uint64, dstaddr string, datasize int, ho
 	node.pendingPings = leftPingRequests
 }
 
-func (node *Node) addPingResult(dst string, datasize int, delay uint64) {
-	node.pingResults = append(node.pingResults, &PingResult{
-		Dst:      dst,
-		DataSize: datasize,
-		Delay:    delay,
-	})
+func (node *Node) addPingResult(req *pingRequest, delay uint64) {
+	node.D.runStats.onPingResult(delay)
+	result := &PingResult{
+		Timestamp: req.Timestamp,
+		Dst:       req.Dst,
+		DataSize:  req.DataSize,
+		Delay:     delay,
+	}
+	node.pingResults = append(node.pingResults, result)
+	for _, cb := range node.D.pingHandlers {
+		cb(node.Id, result)
+	}
 
 	if len(node.pingResults) > maxPingResultCount {
 		node.pingResults = node.pingResults[1:]
 	}
 }
 
+// SubscribePingResults registers a callback for the ping results of all nodes, which are reported whether or not they
+// are collected by CollectPings. The callback is called in the dispatcher goroutine and must return quickly.
+func (d *Dispatcher) SubscribePingResults(cb func(NodeId, *PingResult)) {
+	d.pingHandlers = append(d.pingHandlers, cb)
+}
+
 func (node *Node) CollectPings() []*PingResult {
 	ret := node.pingResults
 	node.pingResults = nil
 	return ret
 }
 
+// CollectPingsSince collects the results of the pings requested at or after the timestamp. The results of earlier pings
+// are left for CollectPings.
+func (node *Node) CollectPingsSince(timestamp uint64) []*PingResult {
+	var ret, left []*PingResult
+	for _, result := range node.pingResults {
+		if result.Timestamp >= timestamp {
+			ret = append(ret, result)
+		} else {
+			left = append(left, result)
+		}
+	}
+	node.pingResults = left
+	return ret
+}
+
 func (node *Node) CollectJoins() []*JoinResult {
 	ret := node.joinResults
 	node.joinResults = nil
@@ -250,6 +344,13 @@ func (node *Node) onJoinerState(state OtJoinerState) {
 	if state == OtJoinerStateDiscover || state == OtJoinerStateConnect {
 		// new joiner session started
 		node.startNewJoinerSession()
+		if state == OtJoinerStateConnect {
+			node.joinerSession.ConnectTime = node.CurTime
+		}
+	} else if state == OtJoinerStateConnected {
+		if node.joinerSession != nil {
+			node.joinerSession.ConnectedTime = node.CurTime
+		}
 	} else if state == OtJoinerStateJoined {
 		if node.joinerSession != nil {
 			node.joinerSession.JoinedTime = node.CurTime
@@ -291,12 +392,40 @@ func (node *Node) addJoinResult(js *joinerSession) {
 
 	sessionDuration := js.StopTime - js.StartTime
 
-	node.joinResults = append(node.joinResults, &JoinResult{
+	result := &JoinResult{
 		JoinDuration:    joinDuration,
 		SessionDuration: sessionDuration,
-	})
+	}
+	if js.ConnectTime != 0 {
+		result.DiscoverDuration = js.ConnectTime - js.StartTime
+		if js.ConnectedTime != 0 {
+			result.DtlsDuration = js.ConnectedTime - js.ConnectTime
+			if js.JoinedTime != 0 {
+				result.NetworkDataDuration = js.JoinedTime - js.ConnectedTime
+			}
+		}
+	}
+
+	node.joinResults = append(node.joinResults, result)
+	node.D.joinHistory.add(result)
 
 	if len(node.joinResults) > maxJoinResultCount {
 		node.joinResults = node.joinResults[1:]
 	}
+
+	node.pendingAttach = nil
+	if js.JoinedTime != 0 {
+		node.pendingAttach = result
+		node.joinedTime = js.JoinedTime
+	}
+}
+
+// onAttached completes the join result of the last joined session with the attach time.
+func (node *Node) onAttached() {
+	if node.pendingAttach == nil {
+		return
+	}
+
+	node.pendingAttach.AttachDuration = node.CurTime - node.joinedTime
+	node.pendingAttach = nil
 }
@@ -0,0 +1,56 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestCollectPingsSince(t *testing.T) {
+	d := &Dispatcher{}
+	node := &Node{D: d, Id: 1}
+	d.nodes = map[NodeId]*Node{1: node}
+
+	d.CurTime = 1000
+	node.onPingRequest(1000, "fdde:ad00:beef:0::1", 4)
+	d.CurTime = 2000
+	node.onPingRequest(2000, "fdde:ad00:beef:0::2", 4)
+	d.CurTime = 3000
+	node.onPingReply(1000, "fdde:ad00:beef:0::1", 4, 64)
+	node.onPingReply(2000, "fdde:ad00:beef:0::2", 4, 64)
+
+	pings := node.CollectPingsSince(2000)
+	assert.Equal(t, 1, len(pings))
+	assert.Equal(t, PingResult{Timestamp: 2000, Dst: "fdde:ad00:beef:0::2", DataSize: 4, Delay: 1000}, *pings[0])
+
+	pings = node.CollectPings()
+	assert.Equal(t, 1, len(pings))
+	assert.Equal(t, "fdde:ad00:beef:0::1", pings[0].Dst)
+}
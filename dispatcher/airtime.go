@@ -0,0 +1,138 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	phyHeaderLen      = 6  // SHR (4 bytes preamble + 1 byte SFD) + PHR (1 byte)
+	phyByteDurationUs = 32 // O-QPSK at 250 kbps
+	dominantShareRate = 2  // a node using more than twice its fair share of airtime dominates the channel
+)
+
+// frameAirtime returns the on-air duration (in us) of a frame with the specified PSDU length.
+func frameAirtime(psduLen int) uint64 {
+	return uint64(phyHeaderLen+psduLen) * phyByteDurationUs
+}
+
+type AirtimeStat struct {
+	NodeId      NodeId  `yaml:"node" json:"node"`
+	TxFrames    uint64  `yaml:"frames" json:"frames"`
+	TxAirtime   uint64  `yaml:"airtime" json:"airtime_us"` // us
+	Utilization float64 `yaml:"util" json:"util"`          // ratio of the window duration used by the node
+	Share       float64 `yaml:"share" json:"share"`        // ratio of the total airtime of all nodes used by the node
+	Dominant    bool    `yaml:"dominant" json:"dominant"`
+}
+
+type AirtimeReport struct {
+	WindowStart uint64        `json:"window_start_us"`
+	WindowEnd   uint64        `json:"window_end_us"`
+	TxAirtime   uint64        `json:"airtime_us"` // total airtime of all nodes in us
+	Utilization float64       `json:"util"`       // ratio of the window duration used by all nodes
+	Fairness    float64       `json:"fairness"`   // Jain's fairness index of airtime among transmitting nodes
+	Nodes       []AirtimeStat `json:"nodes"`
+}
+
+type airtimeMeter struct {
+	windowStart uint64
+	frames      map[NodeId]uint64
+	airtime     map[NodeId]uint64
+}
+
+func newAirtimeMeter(now uint64) *airtimeMeter {
+	return &airtimeMeter{
+		windowStart: now,
+		frames:      map[NodeId]uint64{},
+		airtime:     map[NodeId]uint64{},
+	}
+}
+
+func (am *airtimeMeter) OnTransmit(id NodeId, psduLen int) {
+	am.frames[id] += 1
+	am.airtime[id] += frameAirtime(psduLen)
+}
+
+func (am *airtimeMeter) DeleteNode(id NodeId) {
+	delete(am.frames, id)
+	delete(am.airtime, id)
+}
+
+func (am *airtimeMeter) Reset(now uint64) {
+	am.windowStart = now
+	am.frames = map[NodeId]uint64{}
+	am.airtime = map[NodeId]uint64{}
+}
+
+func (am *airtimeMeter) Report(now uint64) *AirtimeReport {
+	report := &AirtimeReport{
+		WindowStart: am.windowStart,
+		WindowEnd:   now,
+	}
+
+	var sum, sumSquares float64
+	for id, airtime := range am.airtime {
+		report.TxAirtime += airtime
+		sum += float64(airtime)
+		sumSquares += float64(airtime) * float64(airtime)
+		report.Nodes = append(report.Nodes, AirtimeStat{
+			NodeId:    id,
+			TxFrames:  am.frames[id],
+			TxAirtime: airtime,
+		})
+	}
+
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].NodeId < report.Nodes[j].NodeId
+	})
+
+	n := len(report.Nodes)
+	if sumSquares > 0 {
+		report.Fairness = sum * sum / (float64(n) * sumSquares)
+	}
+
+	window := now - am.windowStart
+	if window > 0 {
+		report.Utilization = float64(report.TxAirtime) / float64(window)
+	}
+
+	for i := range report.Nodes {
+		stat := &report.Nodes[i]
+		if window > 0 {
+			stat.Utilization = float64(stat.TxAirtime) / float64(window)
+		}
+		if report.TxAirtime > 0 {
+			stat.Share = float64(stat.TxAirtime) / float64(report.TxAirtime)
+		}
+		stat.Dominant = n > 1 && stat.Share > dominantShareRate/float64(n)
+	}
+
+	return report
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAirtimeMeter(t *testing.T) {
+	am := newAirtimeMeter(0)
+	assert.Equal(t, uint64((6+127)*32), frameAirtime(127))
+
+	am.OnTransmit(1, 10)
+	am.OnTransmit(2, 10)
+	am.OnTransmit(3, 10)
+	report := am.Report(1000000)
+	assert.Equal(t, 3, len(report.Nodes))
+	assert.InDelta(t, 1.0, report.Fairness, 1e-9)
+	for _, stat := range report.Nodes {
+		assert.False(t, stat.Dominant)
+	}
+
+	for i := 0; i < 10; i++ {
+		am.OnTransmit(1, 100)
+	}
+	report = am.Report(1000000)
+	assert.True(t, report.Nodes[0].NodeId == 1 && report.Nodes[0].Dominant)
+	assert.True(t, report.Fairness < 0.5)
+
+	am.Reset(1000000)
+	report = am.Report(2000000)
+	assert.Equal(t, 0, len(report.Nodes))
+	assert.Equal(t, uint64(1000000), report.WindowStart)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// MaxAlarmCoalescing is the largest alarm coalescing window, in microseconds. Larger windows would delay alarms by
+	// more than the timing tolerance of the MAC layer.
+	MaxAlarmCoalescing = 1000
+)
+
+// AlarmsUntil returns the alarms which fire no later than the timestamp, ordered by timestamp and node ID.
+func (am *alarmMgr) AlarmsUntil(timestamp uint64) []alarmEvent {
+	var alarms []alarmEvent
+	for _, e := range am.q {
+		if e.Timestamp <= timestamp {
+			alarms = append(alarms, *e)
+		}
+	}
+	sort.Slice(alarms, func(i, j int) bool {
+		if alarms[i].Timestamp != alarms[j].Timestamp {
+			return alarms[i].Timestamp < alarms[j].Timestamp
+		}
+		return alarms[i].NodeId < alarms[j].NodeId
+	})
+	return alarms
+}
+
+// SetAlarmCoalescing sets the window in microseconds within which alarms are fired together, or 0 to disable
+// alarm coalescing.
+func (d *Dispatcher) SetAlarmCoalescing(window uint64) {
+	simplelogger.AssertTrue(window <= MaxAlarmCoalescing)
+	d.cfg.AlarmCoalescing = window
+}
+
+// GetAlarmCoalescing returns the alarm coalescing window in microseconds, or 0 if alarm coalescing is disabled.
+func (d *Dispatcher) GetAlarmCoalescing() uint64 {
+	return d.cfg.AlarmCoalescing
+}
+
+// processCoalescedAlarms fires the next alarm together with all alarms within the coalescing window, at the time of
+// the latest of them. Alarms are never fired early, and the window never extends past the next frame or timer, so
+// that no node observes the events out of order; an alarm is only delayed by less than the window.
+func (d *Dispatcher) processCoalescedAlarms(nextSendtime uint64, nextTimerTime uint64) {
+	until := d.alarmMgr.NextTimestamp() + d.cfg.AlarmCoalescing
+	if until >= nextSendtime {
+		until = nextSendtime
+	}
+	if until >= nextTimerTime {
+		until = nextTimerTime
+	}
+	if until > d.pauseTime {
+		until = d.pauseTime
+	}
+
+	alarms := d.alarmMgr.AlarmsUntil(until)
+	simplelogger.AssertTrue(len(alarms) > 0)
+	fireTime := alarms[len(alarms)-1].Timestamp
+	d.advanceTime(fireTime)
+	for _, alarm := range alarms {
+		if alarm.Timestamp < fireTime {
+			d.Counters.CoalescedAlarms += 1
+		}
+		d.perf.onAlarmProcessed()
+		d.advanceNodeTime(alarm.NodeId, fireTime, false)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlarmCoalescing(t *testing.T) {
+	udpln, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer udpln.Close()
+
+	d := newDispatcher(nil, DefaultConfig(), nil)
+	d.udpln = udpln
+	d.speed = MaxSimulateSpeed
+	d.pauseTime = Ever
+	for id := 1; id <= 4; id++ {
+		node := d.newNode(id, 0, 0, 100)
+		node.peerAddr = udpln.LocalAddr().(*net.UDPAddr)
+	}
+	d.alarmMgr.SetTimestamp(1, 1000)
+	d.alarmMgr.SetTimestamp(2, 1050)
+	d.alarmMgr.SetTimestamp(3, 1100)
+	d.alarmMgr.SetTimestamp(4, 1101)
+
+	// without coalescing, alarms are fired one at a time
+	assert.True(t, d.processNextEvent())
+	assert.Equal(t, uint64(1000), d.CurTime)
+	assert.Equal(t, uint64(1050), d.alarmMgr.NextTimestamp())
+
+	// alarms within the window are fired together at the time of the latest of them
+	d.SetAlarmCoalescing(50)
+	assert.True(t, d.processNextEvent())
+	assert.Equal(t, uint64(1100), d.CurTime)
+	assert.Equal(t, uint64(1100), d.nodes[2].CurTime)
+	assert.Equal(t, uint64(1100), d.nodes[3].CurTime)
+	assert.Equal(t, uint64(1101), d.alarmMgr.NextTimestamp())
+	assert.Equal(t, uint64(1), d.Counters.CoalescedAlarms)
+
+	// the window does not extend past the next frame
+	d.alarmMgr.SetTimestamp(1, 1120)
+	d.sendQueue.Add(1110, 2, []byte{eventTypeRadioReceived})
+	assert.True(t, d.processNextEvent())
+	assert.Equal(t, uint64(1101), d.CurTime)
+	assert.Equal(t, uint64(1120), d.alarmMgr.NextTimestamp())
+	assert.Equal(t, uint64(1110), d.sendQueue.NextTimestamp())
+	assert.Equal(t, uint64(1), d.Counters.CoalescedAlarms)
+
+	alarms := d.alarmMgr.AlarmsUntil(1200)
+	assert.Equal(t, 1, len(alarms))
+	assert.Equal(t, NodeId(1), alarms[0].NodeId)
+}
@@ -137,6 +137,17 @@ func (am *alarmMgr) NextTimestamp() uint64 {
 	return am.q[0].Timestamp
 }
 
+// ScheduledCount returns the number of nodes with a scheduled alarm.
+func (am *alarmMgr) ScheduledCount() int {
+	count := 0
+	for _, e := range am.q {
+		if e.Timestamp != Ever {
+			count++
+		}
+	}
+	return count
+}
+
 func (am *alarmMgr) DeleteNode(id NodeId) {
 	e := am.events[id]
 	simplelogger.AssertNotNil(e)
@@ -0,0 +1,68 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlarmMgr(t *testing.T) {
+	am := newAlarmMgr()
+	am.AddNode(1)
+	am.AddNode(2)
+	assert.Equal(t, Ever, am.NextTimestamp())
+
+	am.SetTimestamp(1, 200)
+	am.SetTimestamp(2, 100)
+	assert.Equal(t, 2, am.NextAlarm().NodeId)
+	am.SetNotified(2)
+	assert.Equal(t, uint64(200), am.NextTimestamp())
+	assert.Equal(t, 1, am.ScheduledCount())
+
+	am.DeleteNode(1)
+	assert.Equal(t, Ever, am.NextTimestamp())
+}
+
+// BenchmarkAlarmMgr measures the alarm scheduling of a simulation of 1000 nodes, where the node with the next alarm is
+// notified and schedules its next alarm.
+func BenchmarkAlarmMgr(b *testing.B) {
+	am := newAlarmMgr()
+	for id := 1; id <= 1000; id++ {
+		am.AddNode(id)
+		am.SetTimestamp(id, uint64(id*997%1000))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		alarm := am.NextAlarm()
+		id, timestamp := alarm.NodeId, alarm.Timestamp
+		am.SetNotified(id)
+		am.SetTimestamp(id, timestamp+uint64(id))
+	}
+}
@@ -0,0 +1,377 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultAlertPingLossWindow is the window of ping loss alert rules without a duration.
+	DefaultAlertPingLossWindow = 60000000
+
+	alertCheckInterval  = 100000 // virtual time between evaluations of the alert rules, in us
+	maxAlertEvents      = 1000
+	alertWebhookTimeout = time.Second * 5
+)
+
+// AlertCondition is the condition watched by an alert rule.
+type AlertCondition string
+
+const (
+	// AlertDetached fires for each node detached for the duration of the rule.
+	AlertDetached AlertCondition = "detached"
+	// AlertPartitions fires when there are more partitions than the threshold for the duration of the rule.
+	AlertPartitions AlertCondition = "partitions"
+	// AlertPingLoss fires when more than threshold percent of the pings completed within a window of the duration of
+	// the rule timed out.
+	AlertPingLoss AlertCondition = "pingloss"
+)
+
+func ParseAlertCondition(s string) (AlertCondition, error) {
+	switch cond := AlertCondition(s); cond {
+	case AlertDetached, AlertPartitions, AlertPingLoss:
+		return cond, nil
+	default:
+		return "", errors.Errorf("unknown alert condition: %s", s)
+	}
+}
+
+// AlertRule is a user-defined condition of the simulation to be notified of.
+type AlertRule struct {
+	Id        int            `json:"id"`
+	Condition AlertCondition `json:"condition"`
+	Threshold float64        `json:"threshold"` // partition count or ping loss percentage, unused for detached
+	For       uint64         `json:"for_us"`
+	Webhook   string         `json:"webhook,omitempty"` // URL the alert events are posted to in JSON
+}
+
+// AlertEvent reports an alert rule which fired, or resolved when its condition no longer holds.
+type AlertEvent struct {
+	Time      uint64         `json:"time_us"`
+	Rule      int            `json:"rule"`
+	Condition AlertCondition `json:"condition"`
+	Node      NodeId         `json:"node,omitempty"` // the detached node
+	Value     float64        `json:"value"`          // detached seconds, partition count or ping loss percentage
+	Resolved  bool           `json:"resolved"`
+	Message   string         `json:"message"`
+}
+
+type alertRuleState struct {
+	rule   AlertRule
+	since  map[NodeId]uint64 // start time of the condition per node, or InvalidNodeId for network conditions
+	firing map[NodeId]bool
+
+	windowStart    uint64
+	windowPings    int
+	windowTimeouts int
+}
+
+func newAlertRuleState(rule AlertRule) *alertRuleState {
+	return &alertRuleState{
+		rule:   rule,
+		since:  map[NodeId]uint64{},
+		firing: map[NodeId]bool{},
+	}
+}
+
+type alertManager struct {
+	nextRuleId    int
+	rules         []*alertRuleState
+	events        []*AlertEvent
+	nextHandlerId int
+	handlers      map[int]func(*AlertEvent)
+	nextCheck     uint64
+}
+
+// AddAlertRule adds an alert rule and returns its ID.
+func (d *Dispatcher) AddAlertRule(rule AlertRule) (int, error) {
+	if _, err := ParseAlertCondition(string(rule.Condition)); err != nil {
+		return 0, err
+	}
+	if rule.Threshold < 0 || (rule.Condition == AlertPingLoss && rule.Threshold >= 100) {
+		return 0, errors.Errorf("invalid %s threshold: %v", rule.Condition, rule.Threshold)
+	}
+	if rule.Webhook != "" {
+		u, err := url.Parse(rule.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return 0, errors.Errorf("invalid webhook URL: %s", rule.Webhook)
+		}
+	}
+	if rule.Condition == AlertPingLoss && rule.For == 0 {
+		rule.For = DefaultAlertPingLossWindow
+	}
+
+	am := &d.alerts
+	am.nextRuleId++
+	rule.Id = am.nextRuleId
+	rs := newAlertRuleState(rule)
+	d.startPingLossWindow(rs)
+	am.rules = append(am.rules, rs)
+	return rule.Id, nil
+}
+
+// DeleteAlertRule deletes an alert rule.
+func (d *Dispatcher) DeleteAlertRule(id int) error {
+	am := &d.alerts
+	for i, rs := range am.rules {
+		if rs.rule.Id == id {
+			am.rules = append(am.rules[:i], am.rules[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("alert rule %d not found", id)
+}
+
+// AlertRules returns the alert rules in order of their IDs.
+func (d *Dispatcher) AlertRules() []AlertRule {
+	rules := make([]AlertRule, 0, len(d.alerts.rules))
+	for _, rs := range d.alerts.rules {
+		rules = append(rules, rs.rule)
+	}
+	return rules
+}
+
+// AlertEvents returns the alert events so far, in order.
+func (d *Dispatcher) AlertEvents() []*AlertEvent {
+	return d.alerts.events
+}
+
+// ResetAlertEvents clears the alert events.
+func (d *Dispatcher) ResetAlertEvents() {
+	d.alerts.events = nil
+}
+
+// SubscribeAlerts registers a callback for alert events, and returns a function to unsubscribe.
+// The callback is called in the dispatcher goroutine and must return quickly.
+func (d *Dispatcher) SubscribeAlerts(cb func(*AlertEvent)) (unsubscribe func()) {
+	am := &d.alerts
+	if am.handlers == nil {
+		am.handlers = map[int]func(*AlertEvent){}
+	}
+	id := am.nextHandlerId
+	am.nextHandlerId++
+	am.handlers[id] = cb
+
+	return func() {
+		delete(am.handlers, id)
+	}
+}
+
+// resetAlerts restarts the evaluation of the alert rules, e.g. when the simulation is reset. The rules are kept.
+func (d *Dispatcher) resetAlerts() {
+	am := &d.alerts
+	for i, rs := range am.rules {
+		am.rules[i] = newAlertRuleState(rs.rule)
+		d.startPingLossWindow(am.rules[i])
+	}
+	am.events = nil
+	am.nextCheck = 0
+}
+
+// checkAlerts evaluates the alert rules periodically in virtual time.
+func (d *Dispatcher) checkAlerts() {
+	am := &d.alerts
+	if len(am.rules) == 0 || d.CurTime < am.nextCheck {
+		return
+	}
+	am.nextCheck = d.CurTime + alertCheckInterval
+
+	for _, rs := range am.rules {
+		switch rs.rule.Condition {
+		case AlertDetached:
+			d.checkDetachedAlert(rs)
+		case AlertPartitions:
+			d.checkPartitionsAlert(rs)
+		case AlertPingLoss:
+			d.checkPingLossAlert(rs)
+		}
+	}
+}
+
+func (d *Dispatcher) checkDetachedAlert(rs *alertRuleState) {
+	for id := range rs.since {
+		if _, ok := d.nodes[id]; !ok {
+			// the node was deleted
+			delete(rs.since, id)
+			delete(rs.firing, id)
+		}
+	}
+
+	for id, node := range d.nodes {
+		since, ok := rs.since[id]
+		if !ok {
+			since = d.CurTime
+		}
+		d.updateAlert(rs, id, node.Role == OtDeviceRoleDetached, float64(d.CurTime-since)/1e6)
+	}
+}
+
+func (d *Dispatcher) checkPartitionsAlert(rs *alertRuleState) {
+	partitions := map[uint32]struct{}{}
+	for _, node := range d.nodes {
+		if node.Role >= OtDeviceRoleChild && !node.isFailed {
+			partitions[node.PartitionId] = struct{}{}
+		}
+	}
+
+	count := float64(len(partitions))
+	d.updateAlert(rs, InvalidNodeId, count > rs.rule.Threshold, count)
+}
+
+func (d *Dispatcher) checkPingLossAlert(rs *alertRuleState) {
+	if d.CurTime-rs.windowStart < rs.rule.For {
+		return
+	}
+
+	count := d.runStats.pingCount - rs.windowPings
+	timeouts := d.runStats.pingTimeouts - rs.windowTimeouts
+	d.startPingLossWindow(rs)
+	if count == 0 {
+		return
+	}
+
+	loss := float64(timeouts) * 100 / float64(count)
+	d.setAlertFiring(rs, InvalidNodeId, loss > rs.rule.Threshold, loss)
+}
+
+func (d *Dispatcher) startPingLossWindow(rs *alertRuleState) {
+	rs.windowStart = d.CurTime
+	rs.windowPings = d.runStats.pingCount
+	rs.windowTimeouts = d.runStats.pingTimeouts
+}
+
+// updateAlert tracks whether the condition of the rule holds for the node, and fires the alert when it has held for the
+// duration of the rule.
+func (d *Dispatcher) updateAlert(rs *alertRuleState, id NodeId, active bool, value float64) {
+	if !active {
+		delete(rs.since, id)
+		d.setAlertFiring(rs, id, false, value)
+		return
+	}
+
+	since, ok := rs.since[id]
+	if !ok {
+		since = d.CurTime
+		rs.since[id] = since
+	}
+	if d.CurTime-since >= rs.rule.For {
+		d.setAlertFiring(rs, id, true, value)
+	}
+}
+
+func (d *Dispatcher) setAlertFiring(rs *alertRuleState, id NodeId, firing bool, value float64) {
+	if rs.firing[id] == firing {
+		return
+	}
+
+	if firing {
+		rs.firing[id] = true
+	} else {
+		delete(rs.firing, id)
+	}
+
+	evt := &AlertEvent{
+		Time:      d.CurTime,
+		Rule:      rs.rule.Id,
+		Condition: rs.rule.Condition,
+		Node:      id,
+		Value:     value,
+		Resolved:  !firing,
+	}
+	evt.Message = formatAlertMessage(rs.rule, evt)
+	d.notifyAlert(rs.rule, evt)
+}
+
+func formatAlertMessage(rule AlertRule, evt *AlertEvent) string {
+	var msg string
+	switch rule.Condition {
+	case AlertDetached:
+		msg = fmt.Sprintf("node %d detached for %.1fs", evt.Node, evt.Value)
+	case AlertPartitions:
+		msg = fmt.Sprintf("%d partitions, threshold %v", int(evt.Value), rule.Threshold)
+	case AlertPingLoss:
+		msg = fmt.Sprintf("ping loss %.1f%% in %v, threshold %v%%", evt.Value,
+			time.Duration(rule.For)*time.Microsecond, rule.Threshold)
+	}
+
+	if evt.Resolved {
+		return "resolved: " + msg
+	}
+	return msg
+}
+
+func (d *Dispatcher) notifyAlert(rule AlertRule, evt *AlertEvent) {
+	am := &d.alerts
+	am.events = append(am.events, evt)
+	if len(am.events) > maxAlertEvents {
+		am.events = am.events[1:]
+	}
+
+	if evt.Resolved {
+		simplelogger.Infof("alert %d %s", rule.Id, evt.Message)
+	} else {
+		simplelogger.Warnf("alert %d fired: %s", rule.Id, evt.Message)
+	}
+
+	for id := 0; id < am.nextHandlerId; id++ {
+		if cb, ok := am.handlers[id]; ok {
+			cb(evt)
+		}
+	}
+
+	if rule.Webhook != "" {
+		// post in the background, so that slow endpoints do not block the virtual time
+		go postAlertWebhook(rule.Webhook, *evt)
+	}
+}
+
+func postAlertWebhook(webhook string, evt AlertEvent) {
+	data, err := json.Marshal(evt)
+	simplelogger.PanicIfError(err)
+
+	client := http.Client{Timeout: alertWebhookTimeout}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(data))
+	if err != nil {
+		simplelogger.Errorf("post alert to %s failed: %v", webhook, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		simplelogger.Errorf("post alert to %s failed: %s", webhook, resp.Status)
+	}
+}
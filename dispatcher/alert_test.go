@@ -0,0 +1,185 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestAlertRules(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	_, err := d.AddAlertRule(AlertRule{Condition: "unknown"})
+	assert.Error(t, err)
+	_, err = d.AddAlertRule(AlertRule{Condition: AlertPingLoss, Threshold: 100})
+	assert.Error(t, err)
+	_, err = d.AddAlertRule(AlertRule{Condition: AlertDetached, Webhook: "localhost:8080"})
+	assert.Error(t, err)
+
+	id, err := d.AddAlertRule(AlertRule{Condition: AlertDetached, For: 60000000})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, id)
+	id, err = d.AddAlertRule(AlertRule{Condition: AlertPingLoss, Threshold: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, id)
+	rules := d.AlertRules()
+	assert.Equal(t, 2, len(rules))
+	assert.Equal(t, uint64(DefaultAlertPingLossWindow), rules[1].For)
+
+	assert.Nil(t, d.DeleteAlertRule(1))
+	assert.Error(t, d.DeleteAlertRule(1))
+	assert.Equal(t, []AlertRule{rules[1]}, d.AlertRules())
+}
+
+func TestAlertDetached(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	_, err := d.AddAlertRule(AlertRule{Condition: AlertDetached, For: 60000000})
+	assert.Nil(t, err)
+
+	var notified []*AlertEvent
+	unsubscribe := d.SubscribeAlerts(func(evt *AlertEvent) {
+		notified = append(notified, evt)
+	})
+
+	d.nodes[1].Role = OtDeviceRoleDetached
+	d.CurTime = 1000000
+	d.checkAlerts()
+	d.CurTime = 60000000
+	d.checkAlerts()
+	assert.Empty(t, d.AlertEvents())
+
+	d.CurTime = 61000000
+	d.checkAlerts()
+	d.CurTime = 62000000
+	d.checkAlerts()
+	events := d.AlertEvents()
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, NodeId(1), events[0].Node)
+	assert.Equal(t, 60.0, events[0].Value)
+	assert.False(t, events[0].Resolved)
+	assert.Equal(t, "node 1 detached for 60.0s", events[0].Message)
+
+	d.nodes[1].Role = OtDeviceRoleChild
+	d.CurTime = 63000000
+	d.checkAlerts()
+	events = d.AlertEvents()
+	assert.Equal(t, 2, len(events))
+	assert.True(t, events[1].Resolved)
+	assert.Equal(t, 62.0, events[1].Value)
+	assert.Equal(t, events, notified)
+
+	unsubscribe()
+	d.ResetAlertEvents()
+	assert.Empty(t, d.AlertEvents())
+}
+
+func TestAlertPartitions(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	_, err := d.AddAlertRule(AlertRule{Condition: AlertPartitions, Threshold: 1, For: 30000000})
+	assert.Nil(t, err)
+
+	d.nodes[1].Role, d.nodes[1].PartitionId = OtDeviceRoleLeader, 0x1111
+	d.nodes[2].Role, d.nodes[2].PartitionId = OtDeviceRoleLeader, 0x2222
+	d.CurTime = 1000000
+	d.checkAlerts()
+	d.CurTime = 20000000
+	d.checkAlerts()
+	assert.Empty(t, d.AlertEvents())
+	d.CurTime = 31000000
+	d.checkAlerts()
+	assert.Equal(t, 1, len(d.AlertEvents()))
+	assert.Equal(t, 2.0, d.AlertEvents()[0].Value)
+
+	// a failed node does not count
+	d.nodes[2].isFailed = true
+	d.CurTime = 32000000
+	d.checkAlerts()
+	assert.Equal(t, 2, len(d.AlertEvents()))
+	assert.True(t, d.AlertEvents()[1].Resolved)
+
+	// the rules are kept on reset
+	d.resetAlerts()
+	assert.Empty(t, d.AlertEvents())
+	assert.Equal(t, 1, len(d.AlertRules()))
+}
+
+func TestAlertPingLoss(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	_, err := d.AddAlertRule(AlertRule{Condition: AlertPingLoss, Threshold: 10, For: 10000000})
+	assert.Nil(t, err)
+
+	for i := 0; i < 9; i++ {
+		d.runStats.onPingResult(1000)
+	}
+	d.runStats.onPingResult(MaxPingDelayUs)
+	d.CurTime = 10000000
+	d.checkAlerts()
+	assert.Empty(t, d.AlertEvents())
+
+	d.runStats.onPingResult(1000)
+	d.runStats.onPingResult(MaxPingDelayUs)
+	d.CurTime = 20000000
+	d.checkAlerts()
+	assert.Equal(t, 1, len(d.AlertEvents()))
+	assert.Equal(t, 50.0, d.AlertEvents()[0].Value)
+
+	// windows without pings do not resolve the alert
+	d.CurTime = 30000000
+	d.checkAlerts()
+	assert.Equal(t, 1, len(d.AlertEvents()))
+}
+
+func TestAlertWebhook(t *testing.T) {
+	posted := make(chan AlertEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var evt AlertEvent
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&evt))
+		posted <- evt
+	}))
+	defer server.Close()
+
+	d := newStallTestDispatcher(StallConfig{})
+	_, err := d.AddAlertRule(AlertRule{Condition: AlertDetached, Webhook: server.URL})
+	assert.Nil(t, err)
+	d.nodes[2].Role = OtDeviceRoleDetached
+	d.checkAlerts()
+
+	select {
+	case evt := <-posted:
+		assert.Equal(t, NodeId(2), evt.Node)
+		assert.Equal(t, AlertDetached, evt.Condition)
+	case <-time.After(time.Second * 5):
+		t.Fatal("alert not posted")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	DefaultSectorGainDbi     = 6.0
+	DefaultSectorBackGainDbi = -10.0
+)
+
+// AntennaPattern is a simple sector antenna model: the gain is GainDbi within the main lobe of BeamWidth degrees
+// centered on Azimuth, and BackGainDbi elsewhere. Azimuth is in degrees, 0 pointing to +x and increasing clockwise as
+// displayed on the web UI.
+type AntennaPattern struct {
+	Azimuth     float64 `yaml:"azimuth"`
+	BeamWidth   float64 `yaml:"beamwidth"`
+	GainDbi     float64 `yaml:"gain"`
+	BackGainDbi float64 `yaml:"backgain"`
+}
+
+// NodeAntenna is the antenna pattern of a node.
+type NodeAntenna struct {
+	NodeId         NodeId `yaml:"node"`
+	AntennaPattern `yaml:",inline"`
+}
+
+// DefaultSectorAntenna returns a sector antenna pattern with the default gains.
+func DefaultSectorAntenna(azimuth float64, beamWidth float64) AntennaPattern {
+	return AntennaPattern{
+		Azimuth:     azimuth,
+		BeamWidth:   beamWidth,
+		GainDbi:     DefaultSectorGainDbi,
+		BackGainDbi: DefaultSectorBackGainDbi,
+	}
+}
+
+func (a *AntennaPattern) Validate() error {
+	if a.BeamWidth <= 0 || a.BeamWidth > 360 {
+		return fmt.Errorf("invalid beam width: %v", a.BeamWidth)
+	}
+	return nil
+}
+
+// GainDbiAt returns the antenna gain towards the specified azimuth.
+func (a *AntennaPattern) GainDbiAt(azimuth float64) float64 {
+	diff := math.Abs(math.Mod(azimuth-a.Azimuth, 360))
+	if diff > 180 {
+		diff = 360 - diff
+	}
+
+	if diff <= a.BeamWidth/2 {
+		return a.GainDbi
+	}
+	return a.BackGainDbi
+}
+
+// antennaGainTo returns the antenna gain of the node towards the other node, or 0 for isotropic antennas.
+func (node *Node) antennaGainTo(other *Node) float64 {
+	if node.antenna == nil || (node.X == other.X && node.Y == other.Y) {
+		return 0
+	}
+
+	azimuth := math.Atan2(float64(other.Y-node.Y), float64(other.X-node.X)) * 180 / math.Pi
+	return node.antenna.GainDbiAt(azimuth)
+}
+
+func (node *Node) Antenna() *AntennaPattern {
+	return node.antenna
+}
+
+// SetNodeAntenna sets the antenna pattern of the node, or restores an isotropic antenna if pattern is nil.
+func (d *Dispatcher) SetNodeAntenna(nodeid NodeId, pattern *AntennaPattern) {
+	node := d.nodes[nodeid]
+	simplelogger.AssertNotNil(node)
+
+	if pattern != nil {
+		p := *pattern
+		pattern = &p
+	}
+	node.antenna = pattern
+}
+
+// NodeAntennas returns the antenna patterns of all nodes with a directional antenna.
+func (d *Dispatcher) NodeAntennas() []NodeAntenna {
+	var antennas []NodeAntenna
+	for nodeid, node := range d.nodes {
+		if node.antenna != nil {
+			antennas = append(antennas, NodeAntenna{NodeId: nodeid, AntennaPattern: *node.antenna})
+		}
+	}
+
+	sort.Slice(antennas, func(i, j int) bool {
+		return antennas[i].NodeId < antennas[j].NodeId
+	})
+	return antennas
+}
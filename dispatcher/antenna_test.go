@@ -0,0 +1,55 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAntennaPattern(t *testing.T) {
+	a := &AntennaPattern{Azimuth: 90, BeamWidth: 120, GainDbi: 6, BackGainDbi: -10}
+	assert.Equal(t, 6.0, a.GainDbiAt(90))
+	assert.Equal(t, 6.0, a.GainDbiAt(30))
+	assert.Equal(t, 6.0, a.GainDbiAt(150))
+	assert.Equal(t, -10.0, a.GainDbiAt(-90))
+	assert.Equal(t, -10.0, a.GainDbiAt(0))
+
+	a.Azimuth = 0
+	assert.Equal(t, 6.0, a.GainDbiAt(-30))
+	assert.Equal(t, 6.0, a.GainDbiAt(330))
+	assert.Equal(t, -10.0, a.GainDbiAt(180))
+
+	src := &Node{X: 0, Y: 0, antenna: a}
+	front := &Node{X: 100, Y: 0}
+	back := &Node{X: -100, Y: 0}
+	assert.Equal(t, 6.0, src.antennaGainTo(front))
+	assert.Equal(t, -10.0, src.antennaGainTo(back))
+	assert.Equal(t, 0.0, front.antennaGainTo(src))
+	assert.Equal(t, 0.0, src.antennaGainTo(&Node{X: 0, Y: 0}))
+}
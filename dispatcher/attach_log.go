@@ -0,0 +1,179 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	maxAttachAttempts = 20 // attach attempts kept per node
+)
+
+// ParentCandidate is a parent response received by an attaching node.
+type ParentCandidate struct {
+	Time         uint64 `json:"time_us"`
+	ExtAddr      uint64 `json:"extaddr"`
+	Node         NodeId `json:"node"` // the simulated node of ExtAddr, or InvalidNodeId
+	Rloc16       uint16 `json:"rloc16"`
+	Rssi         int    `json:"rssi"`
+	LinkQuality  int    `json:"link_quality"`
+	Priority     int    `json:"priority"` // parent priority, from -1 (low) to 1 (high)
+	LinkQuality3 int    `json:"lq3"`      // number of neighbors of the candidate with link quality 3
+	Selected     bool   `json:"selected"`
+	Reason       string `json:"reason,omitempty"` // why the candidate was not selected
+}
+
+// AttachAttempt is an attach of a node: the parent responses it received and the parent it selected.
+type AttachAttempt struct {
+	Start      uint64             `json:"start_us"`
+	End        uint64             `json:"end_us"` // time the parent was selected, or 0 if none yet
+	Parent     uint64             `json:"parent"` // extended address of the selected parent, or 0 if none
+	Candidates []*ParentCandidate `json:"candidates"`
+}
+
+// parseParentCandidate parses the status push parent_response=<extaddr>,<rloc16>,<rssi>,<link quality>[,<priority>
+// [,<lq3>]], where extaddr and rloc16 are in hex.
+func parseParentCandidate(s string) (*ParentCandidate, error) {
+	args := strings.Split(s, ",")
+	if len(args) < 4 || len(args) > 6 {
+		return nil, errors.Errorf("invalid parent response: %s", s)
+	}
+
+	extaddr, err := strconv.ParseUint(args[0], 16, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid parent response: %s", s)
+	}
+	rloc16, err := strconv.ParseUint(args[1], 16, 16)
+	if err != nil {
+		return nil, errors.Errorf("invalid parent response: %s", s)
+	}
+
+	vals := make([]int, 4)
+	for i, arg := range args[2:] {
+		if vals[i], err = strconv.Atoi(arg); err != nil {
+			return nil, errors.Errorf("invalid parent response: %s", s)
+		}
+	}
+	return &ParentCandidate{
+		ExtAddr:      extaddr,
+		Rloc16:       uint16(rloc16),
+		Rssi:         vals[0],
+		LinkQuality:  vals[1],
+		Priority:     vals[2],
+		LinkQuality3: vals[3],
+	}, nil
+}
+
+// rejectReason explains why the candidate was not selected over the parent, following the order in which OpenThread
+// compares parent responses.
+func rejectReason(c, parent *ParentCandidate) string {
+	switch {
+	case c.LinkQuality != parent.LinkQuality:
+		return fmt.Sprintf("link quality %d vs %d", c.LinkQuality, parent.LinkQuality)
+	case c.Priority != parent.Priority:
+		return fmt.Sprintf("priority %d vs %d", c.Priority, parent.Priority)
+	case c.LinkQuality3 != parent.LinkQuality3:
+		return fmt.Sprintf("lq3 neighbors %d vs %d", c.LinkQuality3, parent.LinkQuality3)
+	case c.Rssi != parent.Rssi:
+		return fmt.Sprintf("rssi %d vs %d", c.Rssi, parent.Rssi)
+	default:
+		return "responded later"
+	}
+}
+
+func (d *Dispatcher) onParentResponse(node *Node, data string) {
+	c, err := parseParentCandidate(data)
+	if err != nil {
+		simplelogger.Warnf("node %d: %v", node.Id, err)
+		return
+	}
+	c.Time = d.CurTime
+	if cnode := d.extaddrMap[c.ExtAddr]; cnode != nil {
+		c.Node = cnode.Id
+	}
+
+	attempts := d.attachLogs[node.Id]
+	if len(attempts) == 0 || attempts[len(attempts)-1].End != 0 {
+		attempts = append(attempts, &AttachAttempt{Start: d.CurTime})
+		if len(attempts) > maxAttachAttempts {
+			attempts = attempts[1:]
+		}
+	}
+	attempt := attempts[len(attempts)-1]
+	attempt.Candidates = append(attempt.Candidates, c)
+
+	if d.attachLogs == nil {
+		d.attachLogs = map[NodeId][]*AttachAttempt{}
+	}
+	d.attachLogs[node.Id] = attempts
+}
+
+// onAttachParent completes the pending attach attempt of the node when it selects its parent.
+func (d *Dispatcher) onAttachParent(node *Node, parent uint64) {
+	attempts := d.attachLogs[node.Id]
+	if parent == 0 || len(attempts) == 0 || attempts[len(attempts)-1].End != 0 {
+		return
+	}
+
+	attempt := attempts[len(attempts)-1]
+	attempt.End = d.CurTime
+	attempt.Parent = parent
+
+	var selected *ParentCandidate
+	for _, c := range attempt.Candidates {
+		if c.ExtAddr == parent {
+			selected = c
+		}
+	}
+	for _, c := range attempt.Candidates {
+		if c == selected {
+			c.Selected = true
+		} else if c.ExtAddr == parent {
+			c.Reason = "repeated response"
+		} else if selected != nil {
+			c.Reason = rejectReason(c, selected)
+		}
+	}
+}
+
+// AttachLog returns the recent attach attempts of the node, in order.
+func (d *Dispatcher) AttachLog(nodeid NodeId) []*AttachAttempt {
+	return d.attachLogs[nodeid]
+}
+
+// ResetAttachLogs clears the attach attempts of all nodes.
+func (d *Dispatcher) ResetAttachLogs() {
+	d.attachLogs = nil
+}
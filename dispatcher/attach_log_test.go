@@ -0,0 +1,90 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestParseParentCandidate(t *testing.T) {
+	c, err := parseParentCandidate("1a2b3c4d5e6f7081,0400,-60,3,1,2")
+	assert.Nil(t, err)
+	assert.Equal(t, &ParentCandidate{ExtAddr: 0x1a2b3c4d5e6f7081, Rloc16: 0x0400, Rssi: -60, LinkQuality: 3, Priority: 1,
+		LinkQuality3: 2}, c)
+
+	c, err = parseParentCandidate("1a2b3c4d5e6f7081,0400,-60,3")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, c.Priority)
+
+	_, err = parseParentCandidate("1a2b3c4d5e6f7081,0400,-60")
+	assert.Error(t, err)
+	_, err = parseParentCandidate("1a2b3c4d5e6f7081,0400,strong,3")
+	assert.Error(t, err)
+}
+
+func TestAttachLog(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.extaddrMap = map[uint64]*Node{0x2222: d.nodes[2]}
+	child := d.nodes[1]
+
+	d.CurTime = 1000000
+	d.handleStatusPush(1, "parent_response=1111,0400,-62,3,0,1")
+	d.CurTime = 1100000
+	d.handleStatusPush(1, "parent_response=2222,0800,-55,3,0,2")
+	d.handleStatusPush(1, "parent_response=3333,0c00,-80,2,1,3")
+	d.CurTime = 1500000
+	d.handleStatusPush(1, "parent=2222")
+
+	attempts := d.AttachLog(1)
+	assert.Equal(t, 1, len(attempts))
+	attempt := attempts[0]
+	assert.Equal(t, uint64(1000000), attempt.Start)
+	assert.Equal(t, uint64(1500000), attempt.End)
+	assert.Equal(t, uint64(0x2222), attempt.Parent)
+	assert.Equal(t, 3, len(attempt.Candidates))
+	assert.Equal(t, "lq3 neighbors 1 vs 2", attempt.Candidates[0].Reason)
+	assert.True(t, attempt.Candidates[1].Selected)
+	assert.Equal(t, NodeId(2), attempt.Candidates[1].Node)
+	assert.Equal(t, "link quality 2 vs 3", attempt.Candidates[2].Reason)
+
+	// a parent change without parent responses does not start an attempt
+	d.handleStatusPush(1, "parent=1111")
+	assert.Equal(t, 1, len(d.AttachLog(1)))
+
+	// a new parent response starts the next attempt
+	d.CurTime = 9000000
+	d.onParentResponse(child, "1111,0400,-62,3")
+	assert.Equal(t, 2, len(d.AttachLog(1)))
+	assert.Equal(t, uint64(0), d.AttachLog(1)[1].End)
+
+	d.ResetAttachLogs()
+	assert.Empty(t, d.AttachLog(1))
+}
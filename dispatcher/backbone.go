@@ -0,0 +1,151 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// BackboneLink is the simulated backbone link between the sites, which connects the infrastructure links of the border
+// routers of different sites. Nodes of the same site share an infrastructure link without delay or loss.
+type BackboneLink struct {
+	Latency uint64  `yaml:"latency_us" json:"latency_us"` // one-way latency between sites in us
+	Jitter  uint64  `yaml:"jitter_us" json:"jitter_us"`   // maximum random delay added to the latency in us
+	Loss    float64 `yaml:"loss" json:"loss"`             // ratio of packets lost between sites, in [0, 1]
+}
+
+// Site is a group of nodes sharing an infrastructure link, e.g. the nodes of a building or home.
+type Site struct {
+	Name  string   `json:"name"`
+	Nodes []NodeId `json:"nodes"`
+}
+
+// SetNodeSite puts the node on the infrastructure link of the site, or takes it off the backbone if the site is "".
+// Infrastructure link packets sent by the node are delivered to the other nodes on the backbone.
+func (d *Dispatcher) SetNodeSite(id NodeId, site string) error {
+	node := d.nodes[id]
+	if node == nil {
+		return errors.Errorf("node %d not found", id)
+	}
+
+	node.site = site
+	return nil
+}
+
+// GetNodeSite returns the site of the node, or "" if the node is not on the backbone.
+func (d *Dispatcher) GetNodeSite(id NodeId) string {
+	if node := d.nodes[id]; node != nil {
+		return node.site
+	}
+	return ""
+}
+
+// GetSites returns the sites ordered by name, with the nodes of each site in order.
+func (d *Dispatcher) GetSites() []*Site {
+	sites := map[string]*Site{}
+	for _, node := range d.nodes {
+		if node.site == "" {
+			continue
+		}
+		if sites[node.site] == nil {
+			sites[node.site] = &Site{Name: node.site}
+		}
+		sites[node.site].Nodes = append(sites[node.site].Nodes, node.Id)
+	}
+
+	result := make([]*Site, 0, len(sites))
+	for _, site := range sites {
+		sort.Ints(site.Nodes)
+		result = append(result, site)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// SetBackboneLink sets the latency, jitter and loss of the backbone link between sites.
+func (d *Dispatcher) SetBackboneLink(link BackboneLink) error {
+	if link.Loss < 0 || link.Loss > 1 {
+		return errors.Errorf("invalid loss: %v", link.Loss)
+	}
+
+	d.backbone = link
+	return nil
+}
+
+func (d *Dispatcher) GetBackboneLink() BackboneLink {
+	return d.backbone
+}
+
+// handleInfraPacket delivers an infrastructure link packet sent by the node to the other nodes on the backbone: at
+// the next microsecond to nodes of the same site, and after the backbone latency and jitter to nodes of other sites
+// unless the packet is lost.
+func (d *Dispatcher) handleInfraPacket(src *Node, data []byte) {
+	d.Counters.InfraPackets += 1
+	if src.site == "" {
+		return
+	}
+
+	for _, dst := range d.nodes {
+		if dst == src || dst.site == "" {
+			continue
+		}
+
+		delay := uint64(1)
+		if dst.site != src.site {
+			if d.backbone.Loss > 0 && rand.Float64() < d.backbone.Loss {
+				d.Counters.InfraPacketsDropped += 1
+				continue
+			}
+
+			delay += d.backbone.Latency
+			if d.backbone.Jitter > 0 {
+				delay += uint64(rand.Int63n(int64(d.backbone.Jitter) + 1))
+			}
+		}
+
+		dstid := dst.Id
+		d.ScheduleAt(d.CurTime+delay, func() {
+			d.deliverInfraPacket(dstid, data)
+		})
+	}
+}
+
+func (d *Dispatcher) deliverInfraPacket(id NodeId, data []byte) {
+	node := d.nodes[id]
+	if node == nil || node.site == "" || node.isFailed || node.isPaused {
+		return
+	}
+
+	d.sendEvent(node, eventTypeInfraPacket, data)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestBackbone(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.CurTime = 1000000
+	d.nodes[3] = newNode(d, 3, 0, 0, 160)
+	d.nodes[4] = newNode(d, 4, 0, 0, 160)
+	assert.Nil(t, d.SetNodeSite(1, "home"))
+	assert.Nil(t, d.SetNodeSite(2, "home"))
+	assert.Nil(t, d.SetNodeSite(3, "office"))
+	assert.NotNil(t, d.SetNodeSite(5, "office"))
+	assert.Equal(t, []*Site{{Name: "home", Nodes: []NodeId{1, 2}}, {Name: "office", Nodes: []NodeId{3}}}, d.GetSites())
+
+	assert.NotNil(t, d.SetBackboneLink(BackboneLink{Loss: 2}))
+	assert.Nil(t, d.SetBackboneLink(BackboneLink{Latency: 5000}))
+
+	// the packet reaches the node of the same site at once, and the node of the other site after the latency
+	d.handleRecvEvent(&event{NodeId: 1, Type: eventTypeInfraPacket, Data: []byte{0x60}})
+	assert.Equal(t, uint64(1), d.Counters.InfraPackets)
+	assert.Equal(t, 2, d.timers.Len())
+	assert.Equal(t, uint64(1000001), d.timers.NextTimestamp())
+
+	d.CurTime = 1000001
+	d.handleTimers()
+	assert.Equal(t, uint64(1000001), d.nodes[2].CurTime)
+	assert.Equal(t, uint64(1000000), d.nodes[3].CurTime)
+	assert.Equal(t, uint64(1005001), d.timers.NextTimestamp())
+
+	d.CurTime = 1005001
+	d.handleTimers()
+	assert.Equal(t, uint64(1005001), d.nodes[3].CurTime)
+	assert.Equal(t, uint64(1000000), d.nodes[4].CurTime)
+
+	// all packets between sites are lost
+	assert.Nil(t, d.SetBackboneLink(BackboneLink{Loss: 1}))
+	d.handleRecvEvent(&event{NodeId: 3, Type: eventTypeInfraPacket, Data: []byte{0x60}})
+	assert.Equal(t, uint64(2), d.Counters.InfraPacketsDropped)
+	assert.Equal(t, 0, d.timers.Len())
+
+	// packets of nodes off the backbone are not delivered
+	assert.Nil(t, d.SetNodeSite(3, ""))
+	d.handleRecvEvent(&event{NodeId: 3, Type: eventTypeInfraPacket, Data: []byte{0x60}})
+	assert.Equal(t, 0, d.timers.Len())
+	assert.Equal(t, "", d.GetNodeSite(3))
+	assert.Equal(t, "home", d.GetNodeSite(1))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// DelayNodeBoot simulates a slow booting node: the node is paused, i.e. its alarm and radio events are held back,
+// until the boot delay (in us) elapses. If crash is true, the node crashes at boot instead: it stays paused and its
+// radio fails, so that it never responds on the air until it is resumed and recovered explicitly.
+func (d *Dispatcher) DelayNodeBoot(id NodeId, delay uint64, crash bool) error {
+	node := d.nodes[id]
+	if node == nil {
+		return errors.Errorf("node %d not found", id)
+	}
+	if d.cfg.Real {
+		return errors.Errorf("boot delay is not supported in real mode")
+	}
+
+	d.PauseNode(id)
+	node.booting = true
+	boot := func() {
+		if d.nodes[id] != node || !node.booting {
+			// the node was deleted
+			return
+		}
+		d.bootNode(node, crash)
+	}
+
+	if delay == 0 {
+		boot()
+	} else {
+		d.ScheduleAt(d.CurTime+delay, boot)
+	}
+	return nil
+}
+
+func (d *Dispatcher) bootNode(node *Node, crash bool) {
+	node.booting = false
+	if crash {
+		d.Counters.BootCrashes += 1
+		node.bootCrashed = true
+		simplelogger.Infof("node %d crashed at boot", node.Id)
+		node.Fail()
+		return
+	}
+
+	d.Counters.DelayedBoots += 1
+	simplelogger.Debugf("node %d booted after %dus", node.Id, d.CurTime-node.CreateTime)
+	d.ResumeNode(node.Id)
+}
+
+// IsBooting returns if the boot of the node is delayed, and the delay has not elapsed yet.
+func (node *Node) IsBooting() bool {
+	return node.booting
+}
+
+// IsBootCrashed returns if the node crashed at boot.
+func (node *Node) IsBootCrashed() bool {
+	return node.bootCrashed
+}
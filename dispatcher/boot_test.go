@@ -0,0 +1,71 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayNodeBoot(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	assert.Nil(t, d.DelayNodeBoot(1, 500000, false))
+	assert.True(t, d.nodes[1].IsPaused())
+	assert.True(t, d.nodes[1].IsBooting())
+	assert.Equal(t, Ever, d.alarmMgr.GetTimestamp(1))
+
+	d.CurTime = 499999
+	d.handleTimers()
+	assert.True(t, d.nodes[1].IsBooting())
+
+	// the alarm held back during the boot delay fires at boot
+	d.CurTime = 500000
+	d.handleTimers()
+	assert.False(t, d.nodes[1].IsPaused())
+	assert.False(t, d.nodes[1].IsBooting())
+	assert.False(t, d.nodes[1].IsFailed())
+	assert.Equal(t, uint64(2000000), d.alarmMgr.GetTimestamp(1))
+	assert.Equal(t, uint64(1), d.Counters.DelayedBoots)
+}
+
+func TestDelayNodeBootCrash(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	assert.Nil(t, d.DelayNodeBoot(2, 1000, true))
+	d.CurTime = 1000
+	d.handleTimers()
+	assert.True(t, d.nodes[2].IsPaused())
+	assert.True(t, d.nodes[2].IsFailed())
+	assert.True(t, d.nodes[2].IsBootCrashed())
+	assert.Equal(t, uint64(1), d.Counters.BootCrashes)
+
+	// crash immediately without a boot delay
+	assert.Nil(t, d.DelayNodeBoot(1, 0, true))
+	assert.True(t, d.nodes[1].IsFailed())
+
+	assert.NotNil(t, d.DelayNodeBoot(3, 1000, false))
+}
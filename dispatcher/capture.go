@@ -0,0 +1,237 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// CapturePreset is a preset of the collision and capture parameters, named after the PHY implementation whose receiver
+// behavior on overlapping frames it approximates.
+type CapturePreset string
+
+const (
+	// CaptureIdeal does not model collisions: overlapping frames are received as if they were alone on air.
+	CaptureIdeal CapturePreset = "ideal"
+	// CaptureAt86rf233 models a receiver which stays locked on the first frame it synchronized on.
+	CaptureAt86rf233 CapturePreset = "at86rf233"
+	// CaptureCc2420 models a receiver which synchronizes again on a stronger frame starting during the preamble of the
+	// first frame.
+	CaptureCc2420 CapturePreset = "cc2420"
+	// CaptureEfr32 models a receiver which synchronizes again on a stronger frame at any time (message in message).
+	CaptureEfr32 CapturePreset = "efr32"
+)
+
+const (
+	preambleDurationUs       = 5 * phyByteDurationUs // SHR: 4 bytes preamble + 1 byte SFD
+	maxFrameAirtimeUs        = (phyHeaderLen + 127) * phyByteDurationUs
+	defaultCaptureSirDb      = 3.0
+	defaultCaptureSirWidthDb = 1.0
+)
+
+// CaptureParams contains the collision and capture parameters of a radio model.
+type CaptureParams struct {
+	// Collisions enables the collision model: frames overlapping on air on the same channel at a receiver are decided
+	// against each other, and the receiver loses one or both of them.
+	Collisions bool
+	SirDb      float64 // signal-to-interference ratio at which a frame wins over another frame with 50% probability
+	SirWidthDb float64 // width of the capture probability curve around SirDb, or 0 for a hard threshold
+	WindowUs   float64 // time from the start of a frame during which a later frame can be captured
+}
+
+// DefaultCaptureParams returns the capture parameters of a radio model until they are set: collisions are not modeled.
+func DefaultCaptureParams() CaptureParams {
+	return CaptureParams{
+		SirDb:      defaultCaptureSirDb,
+		SirWidthDb: defaultCaptureSirWidthDb,
+	}
+}
+
+// CapturePresets returns the names of the capture presets.
+func CapturePresets() []CapturePreset {
+	return []CapturePreset{CaptureIdeal, CaptureAt86rf233, CaptureCc2420, CaptureEfr32}
+}
+
+// ParseCapturePreset parses the name of a capture preset.
+func ParseCapturePreset(s string) (CapturePreset, error) {
+	for _, preset := range CapturePresets() {
+		if string(preset) == s {
+			return preset, nil
+		}
+	}
+	return "", fmt.Errorf("unknown capture preset: %s", s)
+}
+
+// ApplyPreset sets the collision and capture parameters of the preset.
+func (c *CaptureParams) ApplyPreset(preset CapturePreset) {
+	c.Collisions = preset != CaptureIdeal
+	c.SirDb = defaultCaptureSirDb
+	c.SirWidthDb = defaultCaptureSirWidthDb
+	switch preset {
+	case CaptureAt86rf233:
+		c.WindowUs = 0
+	case CaptureCc2420:
+		c.WindowUs = preambleDurationUs
+	case CaptureEfr32:
+		c.WindowUs = maxFrameAirtimeUs
+	}
+}
+
+// GetCaptureParams returns the capture parameters of the selected radio model.
+func (p *RadioModelParams) GetCaptureParams() CaptureParams {
+	if c, ok := p.Capture[p.Model]; ok {
+		return c
+	}
+	return DefaultCaptureParams()
+}
+
+// SetCaptureParams sets the capture parameters of the selected radio model.
+func (p *RadioModelParams) SetCaptureParams(c CaptureParams) {
+	if p.Capture == nil {
+		p.Capture = map[RadioModel]CaptureParams{}
+	}
+	p.Capture[p.Model] = c
+}
+
+// captureProbability returns the probability of a frame to win over an overlapping frame at a receiver with the
+// signal-to-interference ratio, following a logistic curve centered on SirDb.
+func (c *CaptureParams) captureProbability(sirDb float64) float64 {
+	if c.SirWidthDb <= 0 {
+		if sirDb >= c.SirDb {
+			return 1
+		}
+		return 0
+	}
+	return 1 / (1 + math.Exp(-(sirDb-c.SirDb)/c.SirWidthDb))
+}
+
+// onAirFrame is a frame being transmitted with the collision model enabled. The frame is delivered to its receivers
+// when it ends, so that its fate at each receiver is decided against every frame overlapping it.
+type onAirFrame struct {
+	src        *Node
+	channel    uint8
+	start, end uint64
+	receivers  []*Node             // nodes the frame is delivered to when it ends
+	lost       map[NodeId]struct{} // nodes at which the frame is lost
+}
+
+// startOnAir adds the frame of srcnode starting at the timestamp to the frames on air, and decides it against the
+// frames of other nodes which are on air on the channel. It returns nil if collisions are not modeled.
+func (d *Dispatcher) startOnAir(srcnode *Node, channel uint8, timestamp uint64, psduLen int) *onAirFrame {
+	capture := d.radioModel.GetCaptureParams()
+	if !capture.Collisions {
+		d.onAir = nil
+		return nil
+	}
+
+	frame := &onAirFrame{
+		src:     srcnode,
+		channel: channel,
+		start:   timestamp,
+		end:     timestamp + frameAirtime(psduLen),
+		lost:    map[NodeId]struct{}{},
+	}
+	overlapped := false
+	onAir := d.onAir[:0]
+	for _, f := range d.onAir {
+		if f.end <= timestamp {
+			continue
+		}
+		onAir = append(onAir, f)
+		if f.channel == channel && f.src != srcnode {
+			overlapped = true
+			d.collide(&capture, f, frame)
+		}
+	}
+	d.onAir = append(onAir, frame)
+	if overlapped {
+		d.runStats.collisions.OverlappedFrames++
+	}
+	return frame
+}
+
+// collide decides the fate of two overlapping frames at each node. A node transmitting one frame loses the other one.
+// A node reached by both frames captures the later frame, and loses the earlier one, if the later frame starts within
+// WindowUs of the earlier one and wins the draw on its signal-to-interference ratio (SIR). Otherwise, the node stays
+// locked on the earlier frame and loses the later one, and it still loses the earlier frame unless the earlier frame
+// wins the draw on its own SIR.
+func (d *Dispatcher) collide(c *CaptureParams, earlier *onAirFrame, later *onAirFrame) {
+	earlier.lost[later.src.Id] = struct{}{}
+	later.lost[earlier.src.Id] = struct{}{}
+
+	channel := later.channel
+	inWindow := float64(later.start-earlier.start) < c.WindowUs
+	for _, rx := range d.nodes {
+		if rx == earlier.src || rx == later.src ||
+			!d.checkRadioReachable(earlier.src, rx, channel) || !d.checkRadioReachable(later.src, rx, channel) {
+			continue
+		}
+
+		sirDb := d.linkMarginDb(later.src, rx, channel) - d.linkMarginDb(earlier.src, rx, channel)
+		if math.IsNaN(sirDb) {
+			// both nodes are at the position of the receiver
+			sirDb = 0
+		}
+		if inWindow && rand.Float64() < c.captureProbability(sirDb) {
+			earlier.lost[rx.Id] = struct{}{}
+			continue
+		}
+		later.lost[rx.Id] = struct{}{}
+		if rand.Float64() >= c.captureProbability(-sirDb) {
+			earlier.lost[rx.Id] = struct{}{}
+		}
+	}
+}
+
+// endOnAir delivers the frame to its receivers when it ends, except at the receivers where it is lost.
+func (d *Dispatcher) endOnAir(sit *sendItem) {
+	frame := sit.onAir
+	srcnode := frame.src
+	if d.nodes[srcnode.Id] != srcnode {
+		return
+	}
+
+	rx := *sit
+	rx.Timestamp = d.CurTime
+	rx.onAir = nil
+	for _, dstnode := range frame.receivers {
+		if d.nodes[dstnode.Id] != dstnode {
+			continue
+		}
+		if _, lost := frame.lost[dstnode.Id]; lost {
+			d.Counters.CollidedFrames++
+			d.runStats.collisions.LostDeliveries++
+			d.onFrameDropped(srcnode.Id, DropReasonCollision)
+			continue
+		}
+		d.sendOneMessage(&rx, srcnode, dstnode, nil)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestCapturePresets(t *testing.T) {
+	capture := DefaultCaptureParams()
+	assert.False(t, capture.Collisions)
+
+	for _, tc := range []struct {
+		preset     CapturePreset
+		collisions bool
+		windowUs   float64
+	}{
+		{CaptureAt86rf233, true, 0},
+		{CaptureCc2420, true, 160},
+		{CaptureEfr32, true, 4256},
+		{CaptureIdeal, false, 4256},
+	} {
+		preset, err := ParseCapturePreset(string(tc.preset))
+		assert.Nil(t, err)
+		capture.ApplyPreset(preset)
+		assert.Equal(t, tc.collisions, capture.Collisions, "%v", tc.preset)
+		assert.Equal(t, tc.windowUs, capture.WindowUs, "%v", tc.preset)
+	}
+
+	_, err := ParseCapturePreset("anytime")
+	assert.NotNil(t, err)
+}
+
+func TestCaptureParamsPerRadioModel(t *testing.T) {
+	params := DefaultRadioModelParams()
+	assert.Equal(t, DefaultCaptureParams(), params.GetCaptureParams())
+
+	capture := params.GetCaptureParams()
+	capture.ApplyPreset(CaptureCc2420)
+	params.SetCaptureParams(capture)
+	clone := params.clone()
+
+	params.Model = RadioModelFriis
+	assert.False(t, params.GetCaptureParams().Collisions)
+	params.Model = RadioModelDisc
+	assert.Equal(t, capture, params.GetCaptureParams())
+
+	capture.SirDb = 6
+	params.SetCaptureParams(capture)
+	assert.Equal(t, defaultCaptureSirDb, clone.GetCaptureParams().SirDb)
+}
+
+func TestCaptureProbability(t *testing.T) {
+	capture := DefaultCaptureParams()
+	assert.InDelta(t, 0.5, capture.captureProbability(3), 1e-9)
+	assert.True(t, capture.captureProbability(10) > 0.99)
+	assert.True(t, capture.captureProbability(-4) < 0.01)
+
+	capture.SirWidthDb = 0
+	assert.Equal(t, 1.0, capture.captureProbability(3))
+	assert.Equal(t, 0.0, capture.captureProbability(2.9))
+}
+
+func setCapturePreset(d *Dispatcher, preset CapturePreset) {
+	capture := d.radioModel.GetCaptureParams()
+	capture.ApplyPreset(preset)
+	d.radioModel.SetCaptureParams(capture)
+}
+
+func TestStartOnAir(t *testing.T) {
+	n1, n2, n3 := &Node{Id: 1}, &Node{Id: 2, X: 1000}, &Node{Id: 3, X: 2000} // out of range of each other
+	d := &Dispatcher{radioModel: DefaultRadioModelParams(), nodes: map[NodeId]*Node{1: n1, 2: n2, 3: n3}}
+
+	// collisions are not modeled
+	assert.Nil(t, d.startOnAir(n1, 11, 1000, 10))
+	assert.Nil(t, d.onAir)
+
+	setCapturePreset(d, CaptureAt86rf233)
+	f1 := d.startOnAir(n1, 11, 1000, 10) // on air until 1512
+	assert.Equal(t, uint64(1512), f1.end)
+	d.startOnAir(n2, 12, 1100, 10)
+	f2 := d.startOnAir(n1, 11, 1200, 10) // own frames do not overlap
+	assert.Empty(t, f2.lost)
+	assert.Equal(t, uint64(0), d.GetRunStats().Collisions.OverlappedFrames)
+
+	// a node transmitting can not receive
+	f3 := d.startOnAir(n3, 11, 1300, 10)
+	assert.Equal(t, map[NodeId]struct{}{1: {}}, f3.lost)
+	assert.Equal(t, map[NodeId]struct{}{3: {}}, f1.lost)
+	assert.Equal(t, map[NodeId]struct{}{3: {}}, f2.lost)
+
+	// the frames which ended are discarded
+	d.startOnAir(n2, 11, 1712, 10)
+	assert.Equal(t, 2, len(d.onAir))
+	assert.Equal(t, uint64(2), d.GetRunStats().Collisions.OverlappedFrames)
+}
+
+func TestCollide(t *testing.T) {
+	rx := &Node{Id: 1, radioRange: 200}
+	far := &Node{Id: 2, X: 100, radioRange: 200}  // 9dB margin at the receiver
+	near := &Node{Id: 3, X: 20, radioRange: 200}  // 30dB margin at the receiver
+	out := &Node{Id: 4, X: 1000, radioRange: 200} // out of range of the receiver
+	d := &Dispatcher{radioModel: DefaultRadioModelParams(), nodes: map[NodeId]*Node{1: rx, 2: far, 3: near, 4: out}}
+
+	for _, tc := range []struct {
+		name           string
+		earlier, later *Node
+		delayUs        uint64
+		windowUs       float64
+		thresholdDb    float64
+		earlierLost    bool
+		laterLost      bool
+	}{
+		{"stronger frame captured in the preamble", far, near, 100, 160, 3, true, false},
+		{"stronger frame after the preamble", far, near, 200, 160, 3, true, true},
+		{"stronger frame below the threshold", far, near, 100, 160, 25, true, true},
+		{"weaker frame", near, far, 100, 160, 3, false, true},
+		{"weaker frame without capture", near, far, 0, 0, 3, false, true},
+		{"stronger frame without capture", far, near, 0, 0, 3, true, true},
+		{"interferer out of range", out, far, 100, 160, 3, false, false},
+	} {
+		capture := CaptureParams{Collisions: true, SirDb: tc.thresholdDb, WindowUs: tc.windowUs}
+		earlier := &onAirFrame{src: tc.earlier, channel: 11, start: 1000, end: 2000, lost: map[NodeId]struct{}{}}
+		later := &onAirFrame{src: tc.later, channel: 11, start: 1000 + tc.delayUs, end: 2000,
+			lost: map[NodeId]struct{}{}}
+		d.collide(&capture, earlier, later)
+
+		_, earlierLost := earlier.lost[rx.Id]
+		_, laterLost := later.lost[rx.Id]
+		assert.Equal(t, tc.earlierLost, earlierLost, tc.name)
+		assert.Equal(t, tc.laterLost, laterLost, tc.name)
+		assert.Contains(t, earlier.lost, tc.later.Id, tc.name)
+		assert.Contains(t, later.lost, tc.earlier.Id, tc.name)
+	}
+}
+
+func TestCollisionDrop(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.windowStats = newWindowStatsCollector(DefaultWindowStatsConfig(), 0)
+	d.radioModel = DefaultRadioModelParams()
+	setCapturePreset(d, CaptureCc2420)
+	d.nodes[3] = newNode(d, 3, 0, 0, 160)
+	src, dst := d.nodes[1], d.nodes[2]
+	data := []byte{11, 0x41, 0xd8}
+
+	// the frame is delivered when it ends
+	d.CurTime = 1000
+	sit := &sendItem{Timestamp: d.CurTime, NodeId: 1, Data: data, onAir: d.startOnAir(src, 11, d.CurTime, 2)}
+	d.sendOneMessage(sit, src, dst, nil)
+	assert.Equal(t, uint64(0), dst.CurTime)
+	d.CurTime = sit.onAir.end
+	d.endOnAir(sit)
+	assert.Equal(t, sit.onAir.end, dst.CurTime)
+
+	// the nodes are at the same position, so that both frames are lost
+	d.CurTime = 2000
+	d.startOnAir(d.nodes[3], 11, d.CurTime, 10)
+	d.CurTime = 2100
+	sit = &sendItem{Timestamp: d.CurTime, NodeId: 1, Data: data, onAir: d.startOnAir(src, 11, d.CurTime, 2)}
+	d.sendOneMessage(sit, src, dst, nil)
+	d.CurTime = sit.onAir.end
+	d.endOnAir(sit)
+	assert.NotEqual(t, d.CurTime, dst.CurTime)
+	assert.Equal(t, uint64(1), d.Counters.CollidedFrames)
+	stats := d.GetRunStats()
+	assert.Equal(t, uint64(1), stats.Collisions.LostDeliveries)
+	assert.Equal(t, uint64(1), stats.Mac.Drops[DropReasonCollision])
+}
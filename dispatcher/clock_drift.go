@@ -0,0 +1,164 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"math"
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	MaxClockDriftPpm = 100000
+)
+
+// clockDrift models the drift of a node's clock against the simulation clock.
+// The node clock runs at (1 + ppm/1e6) times the rate of the simulation clock. Node local time is measured in us
+// since the node was created.
+type clockDrift struct {
+	ppm       float64
+	baseTime  uint64 // simulation time since node creation when the drift was set
+	baseLocal uint64 // node local time at baseTime
+}
+
+func (cd *clockDrift) rate() float64 {
+	return 1 + cd.ppm/1000000
+}
+
+// localTime converts a simulation time (since node creation) to the node local time.
+func (cd *clockDrift) localTime(ts uint64) uint64 {
+	if ts < cd.baseTime {
+		simplelogger.Panicf("time %d is before drift base time %d", ts, cd.baseTime)
+	}
+
+	if cd.ppm == 0 {
+		return cd.baseLocal + (ts - cd.baseTime)
+	}
+	return cd.baseLocal + uint64(math.Round(float64(ts-cd.baseTime)*cd.rate()))
+}
+
+// simTime converts a node local time to the simulation time (since node creation).
+func (cd *clockDrift) simTime(local uint64) uint64 {
+	if cd.ppm == 0 {
+		return cd.baseTime + local - cd.baseLocal
+	}
+
+	if local >= cd.baseLocal {
+		return cd.baseTime + uint64(math.Round(float64(local-cd.baseLocal)/cd.rate()))
+	} else {
+		return cd.baseTime - uint64(math.Round(float64(cd.baseLocal-local)/cd.rate()))
+	}
+}
+
+// setPpm changes the drift from the specified simulation time (since node creation).
+func (cd *clockDrift) setPpm(ppm float64, ts uint64) {
+	cd.baseLocal = cd.localTime(ts)
+	cd.baseTime = ts
+	cd.ppm = ppm
+}
+
+// ClockDrift returns the clock drift of the node in ppm.
+func (node *Node) ClockDrift() float64 {
+	return node.clockDrift.ppm
+}
+
+// localElapsed returns the time elapsed on the node clock when the simulation time advances from oldTime to newTime.
+func (node *Node) localElapsed(oldTime, newTime uint64) uint64 {
+	return node.clockDrift.localTime(newTime-node.CreateTime) - node.clockDrift.localTime(oldTime-node.CreateTime)
+}
+
+// eventTime converts a delay on the node clock from the node's current time to a simulation time.
+func (node *Node) eventTime(delay uint64) uint64 {
+	local := node.clockDrift.localTime(node.CurTime-node.CreateTime) + delay
+	ts := node.CreateTime + node.clockDrift.simTime(local)
+	if ts < node.CurTime {
+		ts = node.CurTime
+	}
+	return ts
+}
+
+// SetNodeClockDrift sets the clock drift of the node in ppm.
+func (d *Dispatcher) SetNodeClockDrift(id NodeId, ppm float64) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertTrue(ppm >= -MaxClockDriftPpm && ppm <= MaxClockDriftPpm)
+
+	node.clockDrift.setPpm(ppm, node.CurTime-node.CreateTime)
+}
+
+// DriftAnalysis is the clock drift of a node relative to its parent, and the side effects observed on the node since
+// the role changes were reset.
+type DriftAnalysis struct {
+	NodeId        NodeId
+	Ppm           float64 // clock drift of the node
+	Parent        NodeId  // parent of the node, or InvalidNodeId if the node is not attached as a child
+	RelativePpm   float64 // clock drift of the node relative to its parent
+	Detaches      int     // number of times the node detached from the child role
+	ParentChanges int     // number of times the node switched from one parent to another
+}
+
+// AnalyzeClockDrift returns the drift analysis of all nodes in ID order. The detaches and parent changes are derived
+// from the timeline events since the role changes were reset.
+func (d *Dispatcher) AnalyzeClockDrift() []DriftAnalysis {
+	analyses := map[NodeId]*DriftAnalysis{}
+	for id, node := range d.nodes {
+		a := &DriftAnalysis{NodeId: id, Ppm: node.ClockDrift()}
+		if parent := d.extaddrMap[node.parent]; node.Role == OtDeviceRoleChild && parent != nil {
+			a.Parent = parent.Id
+			a.RelativePpm = node.ClockDrift() - parent.ClockDrift()
+		}
+		analyses[id] = a
+	}
+
+	for _, ev := range d.timelineSince(d.roleChangesFrom) {
+		a := analyses[ev.Node]
+		if a == nil {
+			continue
+		}
+		switch ev.Type {
+		case TimelineRole:
+			if parseTimelineRole(ev.Old) == OtDeviceRoleChild && parseTimelineRole(ev.New) == OtDeviceRoleDetached {
+				a.Detaches++
+			}
+		case TimelineParent:
+			if ev.Old != "" && ev.New != "" {
+				a.ParentChanges++
+			}
+		}
+	}
+
+	result := make([]DriftAnalysis, 0, len(analyses))
+	for _, a := range analyses {
+		result = append(result, *a)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NodeId < result[j].NodeId
+	})
+	return result
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestClockDrift(t *testing.T) {
+	cd := clockDrift{}
+	assert.Equal(t, uint64(1000000), cd.localTime(1000000))
+	assert.Equal(t, uint64(1000000), cd.simTime(1000000))
+
+	// 100ppm fast since 1s
+	cd.setPpm(100, 1000000)
+	assert.Equal(t, uint64(1000000), cd.localTime(1000000))
+	assert.Equal(t, uint64(2000100), cd.localTime(2000000))
+	assert.Equal(t, uint64(2000000), cd.simTime(2000100))
+
+	// back to no drift since 2s, the accumulated offset is kept
+	cd.setPpm(0, 2000000)
+	assert.Equal(t, uint64(3000100), cd.localTime(3000000))
+	assert.Equal(t, uint64(3000000), cd.simTime(3000100))
+
+	// 50ppm slow
+	cd.setPpm(-50, 3000000)
+	assert.Equal(t, uint64(4000050), cd.localTime(4000000))
+}
+
+func TestAnalyzeClockDrift(t *testing.T) {
+	parent := &Node{Id: 1, ExtAddr: 0x11, Role: OtDeviceRoleRouter}
+	child := &Node{Id: 2, ExtAddr: 0x22, Role: OtDeviceRoleChild, parent: 0x11}
+	d := &Dispatcher{
+		nodes:      map[NodeId]*Node{1: parent, 2: child},
+		extaddrMap: map[uint64]*Node{0x11: parent, 0x22: child},
+	}
+	parent.clockDrift.setPpm(-20, 0)
+	child.clockDrift.setPpm(30, 0)
+
+	d.addTimelineEvent(TimelineParent, 2, "", "0000000000000033")
+	d.addTimelineEvent(TimelineRole, 2, "child", "detached")
+	d.addTimelineEvent(TimelineParent, 2, "0000000000000033", "0000000000000011")
+	d.addTimelineEvent(TimelineRole, 2, "detached", "child")
+	d.addTimelineEvent(TimelineRole, 3, "child", "detached") // deleted node
+
+	assert.Equal(t, []DriftAnalysis{
+		{NodeId: 1, Ppm: -20},
+		{NodeId: 2, Ppm: 30, Parent: 1, RelativePpm: 50, Detaches: 1, ParentChanges: 1},
+	}, d.AnalyzeClockDrift())
+
+	d.ResetRoleChanges()
+	assert.Equal(t, DriftAnalysis{NodeId: 2, Ppm: 30, Parent: 1, RelativePpm: 50}, d.AnalyzeClockDrift()[1])
+
+	child.Role = OtDeviceRoleDetached
+	assert.Equal(t, DriftAnalysis{NodeId: 2, Ppm: 30}, d.AnalyzeClockDrift()[1])
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"reflect"
+)
+
+// CounterSnapshot is a named snapshot of the dispatcher counters taken at a simulation time (in us).
+// The counters of a snapshot are accumulated since the simulation start, including the values cleared by
+// ResetCounters, so snapshots taken across counter resets can still be compared.
+type CounterSnapshot struct {
+	Name     string
+	Time     uint64
+	Counters map[string]uint64
+}
+
+// totalCounters returns the dispatcher counters accumulated since the simulation start.
+func (d *Dispatcher) totalCounters() map[string]uint64 {
+	counters := d.GetCounters()
+	for name, val := range d.countersOffset {
+		counters[name] += val
+	}
+	return counters
+}
+
+// ResetCounters clears the dispatcher counters. KPI and counter snapshots are not affected.
+func (d *Dispatcher) ResetCounters() {
+	if d.countersOffset == nil {
+		d.countersOffset = map[string]uint64{}
+	}
+	for name, val := range d.GetCounters() {
+		d.countersOffset[name] += val
+	}
+	reflect.ValueOf(&d.Counters).Elem().Set(reflect.Zero(reflect.TypeOf(d.Counters)))
+	d.perf.reset()
+}
+
+// TakeCounterSnapshot takes a snapshot of the dispatcher counters, replacing the previous snapshot of the same name.
+func (d *Dispatcher) TakeCounterSnapshot(name string) *CounterSnapshot {
+	snapshot := &CounterSnapshot{
+		Name:     name,
+		Time:     d.CurTime,
+		Counters: d.totalCounters(),
+	}
+
+	for i, s := range d.counterSnapshots {
+		if s.Name == name {
+			d.counterSnapshots = append(d.counterSnapshots[:i], d.counterSnapshots[i+1:]...)
+			break
+		}
+	}
+	d.counterSnapshots = append(d.counterSnapshots, snapshot)
+	return snapshot
+}
+
+// CounterSnapshots returns the counter snapshots in the order they were taken.
+func (d *Dispatcher) CounterSnapshots() []*CounterSnapshot {
+	return d.counterSnapshots
+}
+
+// GetCounterSnapshot returns the counter snapshot of the name, or nil if not found.
+func (d *Dispatcher) GetCounterSnapshot(name string) *CounterSnapshot {
+	for _, s := range d.counterSnapshots {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// CurrentCounterSnapshot returns an unnamed snapshot of the current counters without storing it.
+func (d *Dispatcher) CurrentCounterSnapshot() *CounterSnapshot {
+	return &CounterSnapshot{
+		Time:     d.CurTime,
+		Counters: d.totalCounters(),
+	}
+}
+
+// Diff returns the increase of each counter from the earlier snapshot to s.
+func (s *CounterSnapshot) Diff(earlier *CounterSnapshot) map[string]int64 {
+	diff := map[string]int64{}
+	for name, val := range s.Counters {
+		diff[name] = int64(val - earlier.Counters[name])
+	}
+	return diff
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterSnapshots(t *testing.T) {
+	d := &Dispatcher{}
+	d.Counters.AlarmEvents = 10
+	d.CurTime = 1000000
+	start := d.TakeCounterSnapshot("start")
+	assert.Equal(t, uint64(1000000), start.Time)
+	assert.Equal(t, uint64(10), start.Counters["AlarmEvents"])
+
+	d.Counters.AlarmEvents = 25
+	d.Counters.RadioEvents = 3
+	d.CurTime = 2000000
+	d.ResetCounters()
+	assert.Equal(t, uint64(0), d.Counters.AlarmEvents)
+	assert.Equal(t, uint64(0), d.GetCounters()["RadioEvents"])
+
+	d.Counters.AlarmEvents = 5
+	d.CurTime = 3000000
+	end := d.TakeCounterSnapshot("end")
+	assert.Equal(t, uint64(30), end.Counters["AlarmEvents"])
+
+	diff := end.Diff(start)
+	assert.Equal(t, int64(20), diff["AlarmEvents"])
+	assert.Equal(t, int64(3), diff["RadioEvents"])
+	assert.Equal(t, int64(-20), start.Diff(end)["AlarmEvents"])
+
+	d.Counters.AlarmEvents = 6
+	assert.Equal(t, int64(1), d.CurrentCounterSnapshot().Diff(end)["AlarmEvents"])
+
+	// taking a snapshot of an existing name replaces it
+	d.TakeCounterSnapshot("start")
+	assert.Len(t, d.CounterSnapshots(), 2)
+	assert.Equal(t, "end", d.CounterSnapshots()[0].Name)
+	assert.Equal(t, uint64(31), d.GetCounterSnapshot("start").Counters["AlarmEvents"])
+	assert.Nil(t, d.GetCounterSnapshot("none"))
+}
+
+func TestKpiCountersReset(t *testing.T) {
+	d := &Dispatcher{linkStats: newLinkStatsCollector()}
+	d.Counters.AlarmEvents = 10
+	d.StartKpi()
+	d.Counters.AlarmEvents = 15
+	d.ResetCounters()
+	d.Counters.AlarmEvents = 2
+	assert.Equal(t, uint64(7), d.GetKpi().Counters["AlarmEvents"])
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"math"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	MaxCpuFactor = 100
+)
+
+// CpuFactor returns the CPU speed factor of the node, i.e. how many times slower than a reference MCU it is emulated.
+func (node *Node) CpuFactor() float64 {
+	if node.cpuFactor == 0 {
+		return 1
+	}
+	return node.cpuFactor
+}
+
+// cpuDelay stretches an alarm delay requested by the node by its CPU speed factor.
+func (node *Node) cpuDelay(delay uint64) uint64 {
+	if node.cpuFactor == 0 || node.cpuFactor == 1 {
+		return delay
+	}
+	return uint64(math.Round(float64(delay) * node.cpuFactor))
+}
+
+// SetNodeCpuFactor sets the CPU speed factor of the node, which scales the delays of all alarms scheduled by the node
+// to approximate a slower (factor > 1) or faster (factor < 1) MCU.
+func (d *Dispatcher) SetNodeCpuFactor(id NodeId, factor float64) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertTrue(factor > 0 && factor <= MaxCpuFactor)
+
+	node.cpuFactor = factor
+}
@@ -27,10 +27,11 @@
 package dispatcher
 
 import (
-	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -61,8 +62,10 @@ const (
 )
 
 type pcapFrameItem struct {
-	Ustime uint64
+	Info   pcap.FrameInfo
 	Data   []byte
+	Reset  bool // restart the pcap file instead of appending a frame
+	LogSeq uint64
 }
 
 type Config struct {
@@ -72,15 +75,37 @@ type Config struct {
 	Port        int
 	DumpPackets bool
 	NoPcap      bool
+	PcapNg      bool
+	PcapFile    string
+	StatsWindow WindowStatsConfig
+	// LogCorrelation tags captured frames with the log sequence number of the sending node.
+	LogCorrelation bool
+	Stall          StallConfig
+	// LogRateLimit is the maximum number of log events per second accepted from each node, or 0 for no limit.
+	LogRateLimit int
+	// TraceFile is the file to write a Chrome trace of the dispatcher activity to, or empty for no trace.
+	TraceFile string
+	// SharedMemory offers the shared memory transport to nodes (see PrepareSharedMemory).
+	SharedMemory bool
+	// AlarmCoalescing is the window in microseconds within which alarms are fired together, or 0 to disable.
+	AlarmCoalescing uint64
+	// NetworkKey is the network key (in hex) used to decrypt frames for the fragmentation statistics.
+	NetworkKey string
+	// TcpAddr is the listen address of the TCP transport for nodes (see TcpEnvVar), or empty to disable.
+	TcpAddr string
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		Speed:       1,
-		Real:        false,
-		Host:        "localhost",
-		Port:        threadconst.InitialDispatcherPort,
-		DumpPackets: false,
+		Speed:        1,
+		Real:         false,
+		Host:         "localhost",
+		Port:         threadconst.InitialDispatcherPort,
+		DumpPackets:  false,
+		PcapFile:     "current.pcap",
+		StatsWindow:  DefaultWindowStatsConfig(),
+		Stall:        DefaultStallConfig(),
+		LogRateLimit: DefaultLogRateLimit,
 	}
 }
 
@@ -90,6 +115,9 @@ type CallbackHandler interface {
 
 	// Notifies that the node's UART was written with data.
 	OnUartWrite(nodeid NodeId, data []byte)
+
+	// Returns the sequence number of the last log of the node.
+	GetNodeLogSeq(nodeid NodeId) uint64
 }
 
 type goDuration struct {
@@ -102,17 +130,21 @@ type Dispatcher struct {
 	cfg                   Config
 	cbHandler             CallbackHandler
 	udpln                 *net.UDPConn
+	tcpln                 net.Listener
 	eventChan             chan *event
 	waitGroup             sync.WaitGroup
 	CurTime               uint64
 	pauseTime             uint64
 	alarmMgr              *alarmMgr
 	sendQueue             *sendQueue
+	timers                *timerQueue
 	nodes                 map[NodeId]*Node
 	deletedNodes          map[NodeId]struct{}
 	aliveNodes            map[NodeId]struct{}
-	pcap                  *pcap.File
+	pcap                  pcap.Writer
+	logCorrelation        *logCorrelationFile
 	pcapFrameChan         chan pcapFrameItem
+	pcapNodes             map[NodeId]struct{} // nodes the packet capture is restricted to, or nil for all nodes
 	vis                   visualize.Visualizer
 	taskChan              chan func()
 	speed                 float64
@@ -122,8 +154,43 @@ type Dispatcher struct {
 	rloc16Map             rloc16Map
 	goDurationChan        chan goDuration
 	globalPacketLossRatio float64
+	ackPacketLossRatio    float64
 	visOptions            VisualizationOptions
 	coaps                 *coapsHandler
+	jammers               map[NodeId]*Node
+	airtime               *airtimeMeter
+	windowStats           *windowStatsCollector
+	pendingUpgrades       map[NodeId]*UpgradeResult
+	upgradeResults        []*UpgradeResult
+	radioModel            RadioModelParams
+	kpi                   kpiCollector
+	joinHistory           joinHistory
+	frags                 *fragTracker
+	linkStats             *linkStatsCollector
+	frameDecryptor        *frameDecryptor
+	electionRun           *ElectionRun
+	timeline              Timeline
+	timelineDropped       uint64 // number of timeline events dropped from the front
+	timelineFrom          uint64 // sequence number of the first event returned by Timeline
+	roleChangesFrom       uint64 // sequence number of the first event returned by RoleChanges
+	rangingErrorModel     RangingErrorModel
+	countersOffset        map[string]uint64
+	counterSnapshots      []*CounterSnapshot
+	stall                 stallDetector
+	alerts                alertManager
+	attachLogs            map[NodeId][]*AttachAttempt
+	zombie                zombieDetector
+	rfSimRaw              rfSimRawChannel
+	backbone              BackboneLink
+	pingHandlers          []func(NodeId, *PingResult)
+	linkMetrics           linkMetricsCollector
+	frameDup              FrameDup
+	linkFrameDups         map[linkKey]*LinkFrameDup
+	frameReorder          FrameReorder
+	heldFrames            map[linkKey]*heldFrame
+	runStats              runStatsCollector
+	onAir                 []*onAirFrame // frames being transmitted, with the collision model enabled
+	energyModel           EnergyModel
 
 	Counters struct {
 		// Event counters
@@ -131,15 +198,53 @@ type Dispatcher struct {
 		RadioEvents      uint64
 		StatusPushEvents uint64
 		UartWriteEvents  uint64
+		ThrottledEvents  uint64
+		BatchedEvents    uint64 // events received in batches from nodes with FramingV2
+		CoalescedAlarms  uint64 // alarms delayed by alarm coalescing
+		RfSimRawEvents   uint64 // raw OT-RFSIM platform events received from nodes
 		// Packet dispatching counters
 		DispatchByExtAddrSucc   uint64
 		DispatchByExtAddrFail   uint64
 		DispatchByShortAddrSucc uint64
 		DispatchByShortAddrFail uint64
 		DispatchAllInRange      uint64
-	}
-	watchingNodes map[NodeId]struct{}
-	stopped       bool
+		// Jamming counters
+		JamTriggers      uint64
+		JamDroppedFrames uint64
+		// Collision counters
+		CollidedFrames   uint64 // frame deliveries lost due to overlapping frames, with the collision model enabled
+		CcaBlockedFrames uint64 // frames not transmitted since the channel is busy at the sender
+		// Duty-cycle counters
+		DutyCycleBlockedFrames uint64 // frames not transmitted due to the duty-cycle limit of the sender
+		// Fragmentation counters
+		FragmentFrames       uint64 // transmitted frames carrying a 6LoWPAN fragment
+		ReassembledDatagrams uint64 // fragmented datagrams with all fragments received
+		// Capture counters
+		PcapFilteredFrames uint64 // frames not captured by the node filter of the packet capture
+		// Boot fault injection counters
+		DelayedBoots uint64 // nodes resumed after their boot delay
+		BootCrashes  uint64 // nodes crashed at boot
+		// Dead node counters
+		ZombieNodes uint64 // nodes detected to stop producing events while not asleep
+		// Backbone counters
+		InfraPackets        uint64 // packets sent by nodes on the backbone
+		InfraPacketsDropped uint64 // packets lost between sites of the backbone
+		// Frame duplication counters
+		DuplicatedFrames uint64 // frames delivered twice by the frame duplication
+		// Frame reordering counters
+		ReorderedFrames     uint64 // frames delivered after the next frame of the same link
+		ReorderHoldTimeouts uint64 // frames held back for reordering and delivered late without a next frame
+		// Shared memory counters
+		ShmStuckNodes uint64 // nodes failed because their shared memory ring stayed full
+	}
+	watchingNodes      map[NodeId]struct{}
+	radioWatchingNodes map[NodeId]RadioWatchLevel
+	stopped            bool
+	offlineSink        OfflineSink
+	trace              *tracer
+	perf               perfCounters
+	batchNodes         map[NodeId]*Node // nodes with pending batches of events
+	shm                map[NodeId]*shmTransport
 }
 
 func NewDispatcher(ctx *progctx.ProgCtx, cfg *Config, cbHandler CallbackHandler) *Dispatcher {
@@ -155,16 +260,48 @@ func NewDispatcher(ctx *progctx.ProgCtx, cfg *Config, cbHandler CallbackHandler)
 
 	simplelogger.AssertNil(err)
 
-	vis := visualize.NewNopVisualizer()
+	d := newDispatcher(ctx, cfg, cbHandler)
+	d.udpln = ln
+	if d.cfg.TcpAddr != "" {
+		err = d.listenTcp()
+		simplelogger.FatalIfError(err, err)
+	}
+	if !d.cfg.NoPcap {
+		if d.cfg.PcapNg {
+			d.pcap, err = pcap.NewNgFile(d.cfg.PcapFile)
+		} else {
+			d.pcap, err = pcap.NewFile(d.cfg.PcapFile)
+		}
+		simplelogger.PanicIfError(err)
+		if d.cfg.LogCorrelation && !d.cfg.PcapNg {
+			d.logCorrelation, err = newLogCorrelationFile(d.cfg.PcapFile)
+			simplelogger.PanicIfError(err)
+		}
+		go d.pcapFrameWriter()
+	}
+	if d.cfg.TraceFile != "" {
+		d.trace, err = newTracer(d.cfg.TraceFile)
+		simplelogger.PanicIfError(err)
+	}
+
+	go d.eventsReader()
 
+	d.vis.SetSpeed(d.speed)
+	simplelogger.Infof("dispatcher started: cfg=%+v", *cfg)
+
+	return d
+}
+
+// newDispatcher creates a dispatcher which is not connected to nodes yet.
+func newDispatcher(ctx *progctx.ProgCtx, cfg *Config, cbHandler CallbackHandler) *Dispatcher {
 	d := &Dispatcher{
 		ctx:                ctx,
 		cfg:                *cfg,
 		cbHandler:          cbHandler,
-		udpln:              ln,
 		eventChan:          make(chan *event, 10000),
 		alarmMgr:           newAlarmMgr(),
 		sendQueue:          newSendQueue(),
+		timers:             newTimerQueue(),
 		nodes:              make(map[NodeId]*Node),
 		deletedNodes:       map[NodeId]struct{}{},
 		aliveNodes:         make(map[NodeId]struct{}),
@@ -173,24 +310,30 @@ func NewDispatcher(ctx *progctx.ProgCtx, cfg *Config, cbHandler CallbackHandler)
 		pcapFrameChan:      make(chan pcapFrameItem, 100000),
 		speed:              cfg.Speed,
 		speedStartRealTime: time.Now(),
-		vis:                vis,
+		vis:                visualize.NewNopVisualizer(),
 		taskChan:           make(chan func(), 100),
 		watchingNodes:      map[NodeId]struct{}{},
+		radioWatchingNodes: map[NodeId]RadioWatchLevel{},
 		goDurationChan:     make(chan goDuration, 10),
 		visOptions:         defaultVisualizationOptions(),
+		jammers:            map[NodeId]*Node{},
+		airtime:            newAirtimeMeter(0),
+		windowStats:        newWindowStatsCollector(cfg.StatsWindow, 0),
+		pendingUpgrades:    map[NodeId]*UpgradeResult{},
+		radioModel:         DefaultRadioModelParams(),
+		energyModel:        DefaultEnergyModel(),
+		batchNodes:         map[NodeId]*Node{},
+		shm:                map[NodeId]*shmTransport{},
+		frags:              newFragTracker(),
+		linkStats:          newLinkStatsCollector(),
+		frameDup:           FrameDup{Delay: DefaultFrameDupDelay},
+		frameReorder:       FrameReorder{MaxHold: DefaultFrameReorderMaxHold},
+		ackPacketLossRatio: -1,
+	}
+	if key, err := hex.DecodeString(cfg.NetworkKey); err == nil {
+		d.frameDecryptor = newFrameDecryptor(key)
 	}
 	d.speed = d.normalizeSpeed(d.speed)
-	if !d.cfg.NoPcap {
-		d.pcap, err = pcap.NewFile("current.pcap")
-		simplelogger.PanicIfError(err)
-		go d.pcapFrameWriter()
-	}
-
-	go d.eventsReader()
-
-	d.vis.SetSpeed(d.speed)
-	simplelogger.Infof("dispatcher started: cfg=%+v", *cfg)
-
 	return d
 }
 
@@ -199,6 +342,21 @@ func (d *Dispatcher) Stop() {
 		return
 	}
 	d.stopped = true
+	if d.udpln != nil {
+		// frees the port, e.g. for a new session with the same ID
+		_ = d.udpln.Close()
+	}
+	if d.tcpln != nil {
+		_ = d.tcpln.Close()
+	}
+	for id := range d.shm {
+		d.closeSharedMemory(id)
+	}
+	if d.trace != nil {
+		if err := d.trace.close(); err != nil {
+			simplelogger.Errorf("failed to close trace: %v", err)
+		}
+	}
 	close(d.pcapFrameChan)
 	d.vis.Stop()
 	d.waitGroup.Wait()
@@ -230,10 +388,11 @@ loop:
 		select {
 		case f := <-d.taskChan:
 			f()
+			d.flushBatches()
 			break
 		case duration := <-d.goDurationChan:
 			// sync the speed start time with the current time
-			if len(d.nodes) == 0 {
+			if len(d.nodes) == 0 && d.timers.Len() == 0 {
 				// no nodes, sleep for a small duration to avoid high cpu
 				d.RecvEvents()
 				time.Sleep(time.Millisecond * 10)
@@ -252,7 +411,9 @@ loop:
 			}
 
 			simplelogger.AssertTrue(d.CurTime <= d.pauseTime)
+			d.resetStallDetector()
 			d.goUntilPauseTime()
+			d.runStats.runTime += time.Since(d.speedStartRealTime)
 
 			if d.ctx.Err() != nil {
 				close(duration.done)
@@ -260,7 +421,9 @@ loop:
 			}
 
 			simplelogger.AssertTrue(d.CurTime == d.pauseTime)
+			d.handleTimers()
 			d.syncAllNodes()
+			d.flushBatches()
 			if d.pcap != nil {
 				_ = d.pcap.Sync()
 			}
@@ -275,12 +438,16 @@ loop:
 func (d *Dispatcher) goUntilPauseTime() {
 	for d.CurTime < d.pauseTime {
 		d.handleTasks()
+		d.handleTimers()
 
 		if d.ctx.Err() != nil {
 			break
 		}
 
 		d.RecvEvents()
+		d.checkStall()
+		d.checkAlerts()
+		d.checkZombies()
 		d.syncAliveNodes()
 
 		// process the next event
@@ -294,6 +461,11 @@ func (d *Dispatcher) goUntilPauseTime() {
 }
 
 func (d *Dispatcher) handleRecvEvent(evt *event) {
+	d.perf.onEventReceived()
+	if evt.batched {
+		d.Counters.BatchedEvents += 1
+	}
+
 	// create new node if necessary
 	nodeid := evt.NodeId
 	if _, ok := d.nodes[nodeid]; !ok {
@@ -307,7 +479,14 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 
 	// assign source address from event to node
 	node := d.nodes[nodeid]
-	node.peerAddr = evt.SrcAddr
+	if evt.SrcAddr != nil {
+		node.peerAddr = evt.SrcAddr
+	}
+	if evt.conn != nil {
+		node.tcpConn = evt.conn
+	}
+	node.recvEvents++
+	node.lastEventTime = d.CurTime
 
 	if d.isWatching(evt.NodeId) {
 		simplelogger.Warnf("Node %d <<< %+v, cur time %d, node time %d, delay %d", evt.NodeId, *evt,
@@ -315,11 +494,17 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 	}
 
 	delay := evt.Delay
+	if evt.Type == eventTypeAlarmFired && delay < 2147483647 {
+		delay = node.cpuDelay(delay)
+	}
+
 	var evtTime uint64
 	if delay >= 2147483647 {
 		evtTime = Ever
+	} else if node.clockDrift.ppm != 0 {
+		evtTime = node.eventTime(delay)
 	} else {
-		evtTime = d.CurTime + evt.Delay
+		evtTime = d.CurTime + delay
 	}
 
 	if d.cfg.Real && (evt.Type == eventTypeAlarmFired || evt.Type == eventTypeRadioReceived) {
@@ -332,16 +517,36 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 	case eventTypeAlarmFired:
 		d.Counters.AlarmEvents += 1
 		d.setSleeping(nodeid)
-		d.alarmMgr.SetTimestamp(nodeid, evtTime)
+		if node.isPaused {
+			// keep the alarm until the node is resumed
+			node.pausedAlarm = evtTime
+		} else {
+			d.alarmMgr.SetTimestamp(nodeid, evtTime)
+		}
 	case eventTypeRadioReceived:
 		d.Counters.RadioEvents += 1
 		d.sendQueue.Add(d.CurTime+1, nodeid, evt.Data)
+	case eventTypeRadioReceivedMulti:
+		d.Counters.RadioEvents += 1
+		if len(evt.Data) < 2 {
+			simplelogger.Warnf("node %d sent an invalid radio frame event: %v", nodeid, evt.Data)
+			break
+		}
+		d.sendQueue.AddRadioFrame(d.CurTime+1, nodeid, int(evt.Data[0]), evt.Data[1:])
 	case eventTypeStatusPush:
 		d.Counters.StatusPushEvents += 1
 		d.handleStatusPush(evt.NodeId, string(evt.Data))
 	case eventTypeUartWrite:
 		d.Counters.UartWriteEvents += 1
 		d.handleUartWrite(evt.NodeId, evt.Data)
+	case eventTypeRadioLog:
+		if d.allowLogEvent(node) {
+			d.radioWatchf(nodeid, RadioWatchTrace, "%s", evt.Data)
+		}
+	case eventTypeRfSimRaw:
+		d.handleRfSimRaw(nodeid, evt.Data)
+	case eventTypeInfraPacket:
+		d.handleInfraPacket(node, evt.Data)
 	default:
 		simplelogger.Panicf("event type not implemented: %v", evt.Type)
 	}
@@ -349,15 +554,33 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 
 // RecvEvents receives events from nodes until there is no more alive node.
 func (d *Dispatcher) RecvEvents() int {
+	d.flushBatches()
+
 	blockTimeout := time.After(time.Second * 5)
 	count := 0
+	begin := time.Now()
+
+	var shmPoll <-chan time.Time
+	if len(d.shm) > 0 {
+		ticker := time.NewTicker(shmPollInterval)
+		defer ticker.Stop()
+		shmPoll = ticker.C
+	}
 
 loop:
 	for {
 		shouldBlock := len(d.aliveNodes) > 0
+		if shmPoll != nil {
+			if n := d.pollSharedMemory(); n > 0 {
+				count += n
+				continue
+			}
+		}
 
 		if shouldBlock {
 			select {
+			case <-shmPoll:
+				// poll the shared memory again
 			case evt := <-d.eventChan:
 				count += 1
 				d.handleRecvEvent(evt)
@@ -376,6 +599,10 @@ loop:
 		}
 	}
 
+	if d.trace != nil && count > 0 {
+		d.trace.span("dispatcher", "RecvEvents", traceDispatcherTid, begin, d.CurTime, 0,
+			map[string]interface{}{"events": count})
+	}
 	return count
 }
 
@@ -385,11 +612,15 @@ func (d *Dispatcher) processNextEvent() bool {
 	// we need to wait until all nodes are sleep
 	nextAlarmTime := d.alarmMgr.NextTimestamp()
 	nextSendtime := d.sendQueue.NextTimestamp()
+	nextTimerTime := d.timers.NextTimestamp()
 
 	nextEventTime := nextAlarmTime
 	if nextEventTime > nextSendtime {
 		nextEventTime = nextSendtime
 	}
+	if nextEventTime > nextTimerTime {
+		nextEventTime = nextTimerTime
+	}
 
 	// nextEventTime <= d.pauseTime
 	// convert nextEventTime to real time
@@ -433,7 +664,18 @@ func (d *Dispatcher) processNextEvent() bool {
 		return false
 	}
 
+	if nextTimerTime < nextAlarmTime && nextTimerTime < nextSendtime {
+		// timers are handled in the main loop once the time is reached
+		d.advanceTime(nextTimerTime)
+		return true
+	}
+
 	simplelogger.AssertTrue(nextAlarmTime >= d.CurTime && nextSendtime >= d.CurTime)
+	if d.cfg.AlarmCoalescing > 0 && !d.cfg.Real && nextAlarmTime <= nextSendtime {
+		d.processCoalescedAlarms(nextSendtime, nextTimerTime)
+		return len(d.nodes) > 0
+	}
+
 	var procUntilTime uint64
 	if nextAlarmTime <= nextSendtime {
 		procUntilTime = nextAlarmTime + ProcessEventTimeErrorUs
@@ -455,6 +697,7 @@ func (d *Dispatcher) processNextEvent() bool {
 			d.advanceTime(nextAlarmTime)
 			nextAlarm := d.alarmMgr.NextAlarm()
 			simplelogger.AssertNotNil(nextAlarm)
+			d.perf.onAlarmProcessed()
 			d.advanceNodeTime(nextAlarm.NodeId, nextAlarm.Timestamp, false)
 			// mark the node as alive in the alarm
 		} else {
@@ -463,8 +706,8 @@ func (d *Dispatcher) processNextEvent() bool {
 			simplelogger.AssertTrue(s.Timestamp == nextSendtime)
 			d.advanceTime(nextSendtime)
 			// construct the message
-			if !d.cfg.NoPcap {
-				d.pcapFrameChan <- pcapFrameItem{nextSendtime, s.Data[1:]}
+			if !d.cfg.NoPcap && d.pcapCaptures(s) {
+				d.pcapFrameChan <- pcapFrameItem{Info: d.pcapFrameInfo(s), Data: s.Data[1:], LogSeq: d.nodeLogSeq(s.NodeId)}
 			}
 			if d.cfg.DumpPackets {
 				d.dumpPacket(s)
@@ -481,7 +724,7 @@ func (d *Dispatcher) processNextEvent() bool {
 
 func (d *Dispatcher) eventsReader() {
 	udpln := d.udpln
-	readbuf := make([]byte, 4096)
+	readbuf := make([]byte, maxBatchLen)
 
 	for {
 		// wait until all nodes are sleepd
@@ -496,27 +739,21 @@ func (d *Dispatcher) eventsReader() {
 			break
 		}
 
-		if n < 11 {
-			simplelogger.Panicf("message length too sort: %d", n)
-		}
-
-		delay := binary.LittleEndian.Uint64(readbuf[:8])
-		typ := readbuf[8]
-		datalen := binary.LittleEndian.Uint16(readbuf[9:11])
-		nodeid := srcaddr.Port - d.cfg.Port
-
-		data := make([]byte, n-11)
-		copy(data, readbuf[11:n])
 		evt := &event{
-			NodeId:  nodeid,
-			Delay:   delay,
-			Type:    typ,
-			DataLen: datalen,
-			Data:    data,
+			NodeId:  srcaddr.Port - d.cfg.Port,
 			SrcAddr: srcaddr,
 		}
+		err = evt.Deserialize(readbuf[:n])
+		simplelogger.PanicIfError(err)
 
-		d.eventChan <- evt
+		events, err := unbatchEvent(evt)
+		if err != nil {
+			simplelogger.Warnf("node %d sent an invalid batch event: %v", evt.NodeId, err)
+			continue
+		}
+		for _, e := range events {
+			d.eventChan <- e
+		}
 	}
 }
 
@@ -527,8 +764,12 @@ func (d *Dispatcher) advanceNodeTime(id NodeId, timestamp uint64, force bool) {
 		return
 	}
 
+	if node.isPaused && !force {
+		return
+	}
+
 	oldTime := node.CurTime
-	elapsed := timestamp - oldTime
+	var elapsed uint64
 	if timestamp <= oldTime {
 		// node time was already newer than the timestamp
 		if !force {
@@ -536,12 +777,11 @@ func (d *Dispatcher) advanceNodeTime(id NodeId, timestamp uint64, force bool) {
 		} else {
 			elapsed = 0
 		}
+	} else {
+		elapsed = node.localElapsed(oldTime, timestamp)
 	}
 
-	msg := make([]byte, 11)
-	binary.LittleEndian.PutUint64(msg[:8], elapsed)
-	msg[8] = eventTypeAlarmFired
-	binary.LittleEndian.PutUint16(msg[9:11], 0)
+	msg := (&event{Delay: elapsed, Type: eventTypeAlarmFired}).Serialize()
 	node.SendMessage(msg)
 	node.CurTime = timestamp
 	if timestamp > oldTime {
@@ -550,6 +790,9 @@ func (d *Dispatcher) advanceNodeTime(id NodeId, timestamp uint64, force bool) {
 
 	d.alarmMgr.SetNotified(id)
 	d.setAlive(id)
+	if d.trace != nil {
+		d.trace.onNodeWake(id, "alarm", timestamp)
+	}
 	if d.isWatching(id) {
 		simplelogger.Warnf("Node %d >>> advance time %v -> %v", id, oldTime, timestamp)
 	}
@@ -557,19 +800,17 @@ func (d *Dispatcher) advanceNodeTime(id NodeId, timestamp uint64, force bool) {
 
 // SendToUART sends data to virtual time UART of the target node.
 func (d *Dispatcher) SendToUART(id NodeId, data []byte) {
-	node := d.nodes[id]
+	d.sendEvent(d.nodes[id], eventTypeUartWrite, data)
+}
 
+// sendEvent sends an event with data to the node at the current time.
+func (d *Dispatcher) sendEvent(node *Node, typ eventType, data []byte) {
 	oldTime := node.CurTime
 	timestamp := d.CurTime
 	simplelogger.AssertTrue(timestamp >= oldTime)
-	elapsed := timestamp - oldTime
-
-	msg := make([]byte, len(data)+11)
-	binary.LittleEndian.PutUint64(msg[:8], elapsed)
-	msg[8] = eventTypeUartWrite
-	binary.LittleEndian.PutUint16(msg[9:11], uint16(len(data)))
-	n := copy(msg[11:], data)
-	simplelogger.AssertTrue(n == len(data))
+	elapsed := node.localElapsed(oldTime, timestamp)
+
+	msg := (&event{Delay: elapsed, Type: typ, Data: data}).Serialize()
 	node.SendMessage(msg)
 
 	node.CurTime = timestamp
@@ -579,6 +820,9 @@ func (d *Dispatcher) SendToUART(id NodeId, data []byte) {
 
 	d.alarmMgr.SetNotified(node.Id)
 	d.setAlive(node.Id)
+	if d.trace != nil {
+		d.trace.onNodeWake(node.Id, traceEventName(typ), timestamp)
+	}
 }
 
 func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
@@ -591,16 +835,42 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 		}
 		return
 	}
+	if d.trace != nil {
+		defer d.trace.span("radio", "dispatch", traceDispatcherTid, time.Now(), d.CurTime,
+			frameAirtime(len(sit.Data)-1), map[string]interface{}{"src": srcnodeid, "bytes": len(sit.Data) - 1})
+	}
+
+	d.perf.onFrameDispatched()
 
 	// send to self as notify for tx done (should do even if the node is failed)
-	d.sendOneMessage(sit, srcnode, srcnode)
+	d.sendOneMessage(sit, srcnode, srcnode, nil)
 
-	if srcnode.isFailed {
+	if srcnode.isFailed || !d.checkCca(srcnode, sit.Data[0], sit.Timestamp) ||
+		!d.checkDutyCycle(srcnode, len(sit.Data)-1) {
 		return
 	}
 
+	d.airtime.OnTransmit(srcnodeid, len(sit.Data)-1)
+	d.onEnergyTransmit(srcnode, len(sit.Data)-1)
+	d.windowStats.OnTransmit(srcnodeid, len(sit.Data)-1)
+	d.kpi.OnTransmit(srcnodeid, len(sit.Data)-1)
+	d.runStats.onTransmit(len(sit.Data) - 1)
+
 	pktinfo := dissectpkt.Dissect(sit.Data)
 	pktframe := pktinfo.MacFrame
+	jammers := d.findJammers(srcnode, pktframe)
+	sit.onAir = d.startOnAir(srcnode, pktframe.Channel, sit.Timestamp, len(sit.Data)-1)
+	if sit.onAir != nil {
+		d.ScheduleAt(sit.onAir.end, func() {
+			d.endOnAir(sit)
+		})
+	}
+	d.radioWatchf(srcnodeid, RadioWatchInfo, "TX %s", pktframe)
+	if pktframe.FrameControl.FrameType() == wpan.FrameTypeData {
+		sit.frag = d.dissectFrag(srcnode, sit, pktframe)
+	}
+	sit.ack = pktframe.FrameControl.FrameType() == wpan.FrameTypeAck
+	d.trackLinkFrame(sit, srcnode, pktframe)
 
 	// try to dispatch the message by extaddr directly
 	dispatchedByDstAddr := false
@@ -610,16 +880,18 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 		// the message should only be dispatched to the target node with the extaddr
 		dstnode := d.extaddrMap[pktframe.DstAddrExtended]
 		if dstnode != srcnode && dstnode != nil {
-			if d.checkRadioReachable(srcnode, dstnode) {
-				d.sendOneMessage(sit, srcnode, dstnode)
+			if d.checkRadioReachable(srcnode, dstnode, pktframe.Channel) {
+				d.sendOneMessage(sit, srcnode, dstnode, jammers)
 				d.visSendFrame(srcnodeid, dstnode.Id, pktframe)
 			} else {
+				d.onFrameDropped(srcnodeid, DropReasonRange)
 				d.visSendFrame(srcnodeid, InvalidNodeId, pktframe)
 			}
 
 			d.Counters.DispatchByExtAddrSucc++
 		} else {
 			d.Counters.DispatchByExtAddrFail++
+			d.onFrameDropped(srcnodeid, DropReasonUnknown)
 			d.visSendFrame(srcnodeid, InvalidNodeId, pktframe)
 		}
 
@@ -632,8 +904,8 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 
 			if len(dstnodes) > 0 {
 				for _, dstnode := range dstnodes {
-					if d.checkRadioReachable(srcnode, dstnode) {
-						d.sendOneMessage(sit, srcnode, dstnode)
+					if d.checkRadioReachable(srcnode, dstnode, pktframe.Channel) {
+						d.sendOneMessage(sit, srcnode, dstnode, jammers)
 						d.visSendFrame(srcnodeid, dstnode.Id, pktframe)
 						dispatchCnt++
 					}
@@ -641,9 +913,13 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 				d.Counters.DispatchByShortAddrSucc++
 			} else {
 				d.Counters.DispatchByShortAddrFail++
+				d.onFrameDropped(srcnodeid, DropReasonUnknown)
 			}
 
 			if dispatchCnt == 0 {
+				if len(dstnodes) > 0 {
+					d.onFrameDropped(srcnodeid, DropReasonRange)
+				}
 				d.visSendFrame(srcnodeid, InvalidNodeId, pktframe)
 			}
 
@@ -654,8 +930,8 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 	if !dispatchedByDstAddr {
 		// TODO: optimize ACK message dispatching by sending it only to the correct node(s)
 		for _, dstnode := range d.nodes {
-			if d.checkRadioReachable(srcnode, dstnode) {
-				d.sendOneMessage(sit, srcnode, dstnode)
+			if d.checkRadioReachable(srcnode, dstnode, pktframe.Channel) {
+				d.sendOneMessage(sit, srcnode, dstnode, jammers)
 			}
 		}
 
@@ -663,23 +939,60 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 	}
 }
 
-func (d *Dispatcher) checkRadioReachable(src *Node, dst *Node) bool {
-	return dst != src && src.GetDistanceTo(dst) <= src.radioRange
+func (d *Dispatcher) checkRadioReachable(src *Node, dst *Node, channel uint8) bool {
+	if dst == src {
+		return false
+	}
+	d.perf.onLinkCheck()
+
+	if d.radioModel.Model == RadioModelDisc && src.antennaGainTo(dst)+dst.antennaGainTo(src) == 0 &&
+		d.radioModel.GetNoiseFloorDbm(channel) == d.radioModel.NoiseFloorDbm &&
+		src.radioParams.isDefault() && dst.radioParams.isDefault() {
+		return src.GetDistanceTo(dst) <= src.radioRangeOn(channel)
+	}
+
+	return d.linkMarginDb(src, dst, channel) >= 0
 }
 
-func (d *Dispatcher) sendOneMessage(sit *sendItem, srcnode *Node, dstnode *Node) {
+func (d *Dispatcher) sendOneMessage(sit *sendItem, srcnode *Node, dstnode *Node, jammers []*Node) {
 	simplelogger.AssertFalse(d.cfg.Real)
 
 	if srcnode != dstnode {
 		// we should always send the message when srcnode == dstnode, because it is the TX done notify
-		if dstnode.isFailed {
+		if dstnode.isFailed || dstnode.isPaused {
+			d.onFrameDropped(srcnode.Id, DropReasonDown)
 			return
 		}
 
-		if d.globalPacketLossRatio > 0 {
+		if len(jammers) > 0 && d.isJammed(jammers, dstnode, sit.Data[0]) {
+			d.Counters.JamDroppedFrames++
+			d.onFrameDropped(srcnode.Id, DropReasonJam)
+			return
+		}
+
+		if sit.onAir != nil && !sit.injected {
+			// delivered when the frame ends, unless it collides
+			sit.onAir.receivers = append(sit.onAir.receivers, dstnode)
+			return
+		}
+
+		if sit.ack && d.ackPacketLossRatio >= 0 {
+			if d.ackPacketLossRatio > 0 && !sit.injected && rand.Float64() < d.ackPacketLossRatio {
+				d.onFrameDropped(srcnode.Id, DropReasonLoss)
+				return
+			}
+		} else if d.globalPacketLossRatio > 0 && !sit.injected {
 			datalen := len(sit.Data)
 			succRate := math.Pow(1.0-d.globalPacketLossRatio, float64(datalen)/128.0)
 			if rand.Float64() >= succRate {
+				d.onFrameDropped(srcnode.Id, DropReasonLoss)
+				return
+			}
+		}
+
+		if !sit.injected {
+			d.duplicateFrame(sit, srcnode, dstnode)
+			if d.holdFrame(sit, srcnode, dstnode) {
 				return
 			}
 		}
@@ -690,12 +1003,16 @@ func (d *Dispatcher) sendOneMessage(sit *sendItem, srcnode *Node, dstnode *Node)
 
 	oldTime := dstnode.CurTime
 	if timestamp > oldTime {
-		elapsed = timestamp - oldTime
+		elapsed = dstnode.localElapsed(oldTime, timestamp)
 	} else {
 		elapsed = 0
 	}
 
-	dstnode.Send(elapsed, sit.Data)
+	if dstnode == srcnode {
+		dstnode.sendFrame(elapsed, sit.Radio, sit.Data)
+	} else {
+		dstnode.sendFrame(elapsed, dstnode.radioIndexOn(sit.Data[0]), sit.Data)
+	}
 	dstnode.CurTime = timestamp
 	if timestamp > oldTime {
 		dstnode.failureCtrl.OnTimeAdvanced(oldTime)
@@ -704,6 +1021,27 @@ func (d *Dispatcher) sendOneMessage(sit *sendItem, srcnode *Node, dstnode *Node)
 	dstnodeid := dstnode.Id
 	d.alarmMgr.SetNotified(dstnodeid)
 	d.setAlive(dstnodeid)
+	if d.trace != nil {
+		if dstnode == srcnode {
+			d.trace.onNodeWake(dstnodeid, "tx done", timestamp)
+		} else {
+			d.trace.onNodeWake(dstnodeid, "rx", timestamp)
+		}
+	}
+
+	if dstnode != srcnode {
+		d.radioWatchf(dstnodeid, RadioWatchInfo, "RX from node %d, %d bytes", srcnode.Id, len(sit.Data)-1)
+		if !sit.injected && d.heldFrames[linkKey{srcnode.Id, dstnodeid}] != nil {
+			d.Counters.ReorderedFrames += 1
+			defer d.releaseHeldFrame(linkKey{srcnode.Id, dstnodeid})
+		}
+		if sit.frag != nil {
+			d.onFragReceived(sit, dstnode)
+		}
+		if sit.linkTracked {
+			d.onLinkFrameDelivered(sit, srcnode, dstnode)
+		}
+	}
 
 	if d.isWatching(dstnodeid) {
 		if dstnode == srcnode {
@@ -719,6 +1057,7 @@ func (d *Dispatcher) newNode(nodeid NodeId, x, y int, radioRange int) (node *Nod
 	d.nodes[nodeid] = node
 	d.alarmMgr.AddNode(nodeid)
 	d.setAlive(nodeid)
+	d.addTimelineEvent(TimelineNodeAdd, nodeid, "", "")
 
 	d.vis.AddNode(nodeid, x, y, radioRange)
 	return
@@ -736,6 +1075,9 @@ func (d *Dispatcher) setAlive(nodeid NodeId) {
 func (d *Dispatcher) setSleeping(nodeid NodeId) {
 	simplelogger.AssertFalse(d.cfg.Real)
 	delete(d.aliveNodes, nodeid)
+	if d.trace != nil {
+		d.trace.onNodeSleep(nodeid)
+	}
 }
 
 func (d *Dispatcher) syncAliveNodes() {
@@ -764,12 +1106,31 @@ func (d *Dispatcher) pcapFrameWriter() {
 		if err != nil {
 			simplelogger.Errorf("failed to close pcap: %v", err)
 		}
+		if d.logCorrelation != nil {
+			_ = d.logCorrelation.Close()
+		}
 	}()
 	for item := range d.pcapFrameChan {
-		err := d.pcap.AppendFrame(item.Ustime, item.Data)
+		var err error
+		if item.Reset {
+			err = d.pcap.Reset()
+		} else {
+			err = d.pcap.WriteFrame(&item.Info, item.Data)
+		}
 		if err != nil {
 			simplelogger.Errorf("write pcap failed:%+v", err)
 		}
+
+		if d.logCorrelation != nil {
+			if item.Reset {
+				err = d.logCorrelation.Reset()
+			} else {
+				err = d.logCorrelation.Write(&item)
+			}
+			if err != nil {
+				simplelogger.Errorf("write log correlation failed: %+v", err)
+			}
+		}
 	}
 }
 
@@ -833,7 +1194,9 @@ func (d *Dispatcher) handleStatusPush(srcid NodeId, data string) {
 			// set partition id
 			parid, err := strconv.ParseUint(sp[1], 16, 32)
 			simplelogger.PanicIfError(err)
+			oldParid := srcnode.PartitionId
 			srcnode.PartitionId = uint32(parid)
+			d.onTimelinePartition(srcnode, oldParid)
 			d.vis.SetNodePartitionId(srcid, uint32(parid))
 		} else if sp[0] == "router_added" {
 			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
@@ -862,7 +1225,13 @@ func (d *Dispatcher) handleStatusPush(srcid NodeId, data string) {
 		} else if sp[0] == "parent" {
 			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
 			simplelogger.PanicIfError(err)
+			oldParent := srcnode.parent
+			srcnode.parent = extaddr
+			d.onTimelineParent(srcnode, oldParent)
+			d.onAttachParent(srcnode, extaddr)
 			d.vis.SetParent(srcid, extaddr)
+		} else if sp[0] == "parent_response" {
+			d.onParentResponse(srcnode, sp[1])
 		} else if sp[0] == "joiner_state" {
 			joinerState, err := strconv.Atoi(sp[1])
 			simplelogger.PanicIfError(err)
@@ -871,11 +1240,16 @@ func (d *Dispatcher) handleStatusPush(srcid NodeId, data string) {
 			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
 			simplelogger.PanicIfError(err)
 			srcnode.onStatusPushExtAddr(extaddr)
+		} else if sp[0] == "transport" {
+			d.onStatusPushTransport(srcnode, sp[1])
+		} else if sp[0] == "framing" {
+			d.onStatusPushFraming(srcnode, sp[1])
 		} else if sp[0] == "mode" {
 			mode := ParseNodeMode(sp[1])
+			d.onEnergyMode(srcnode, mode)
 			d.vis.SetNodeMode(srcid, mode)
 		} else if sp[0] == "radio_state" {
-			// TODO: calculate energy consumption based on radio state changes of each node
+			// the energy is estimated from the transmissions and the mode (see energy.go), not from the radio states
 		} else {
 			simplelogger.Warnf("unknown status push: %s=%s", sp[0], sp[1])
 		}
@@ -883,26 +1257,52 @@ func (d *Dispatcher) handleStatusPush(srcid NodeId, data string) {
 }
 
 func (d *Dispatcher) AddNode(nodeid NodeId, x, y int, radioRange int) {
+	if err := d.AddNodeChecked(nodeid, x, y, radioRange, nil); err != nil {
+		simplelogger.Panicf("%v", err)
+	}
+}
+
+// NodeNotRespondingError is returned by AddNodeChecked when a new node does not report its extended address in time.
+type NodeNotRespondingError struct {
+	Node   NodeId
+	Events uint64 // events received from the node
+	Exited bool   // the node process exited while the dispatcher was waiting
+}
+
+func (e *NodeNotRespondingError) Error() string {
+	if e.Exited {
+		return fmt.Sprintf("node %d exited before it responded (events=%d)", e.Node, e.Events)
+	}
+	return fmt.Sprintf("node %d did not respond (events=%d)", e.Node, e.Events)
+}
+
+// AddNodeChecked adds a node like AddNode, but returns a NodeNotRespondingError instead of panicking if the node does
+// not respond in time, or if exited returns true before. The node is added anyway, and must be deleted by the caller.
+func (d *Dispatcher) AddNodeChecked(nodeid NodeId, x, y int, radioRange int, exited func() bool) error {
 	simplelogger.AssertNil(d.nodes[nodeid])
 	simplelogger.Infof("dispatcher add node %d", nodeid)
 	node := d.newNode(nodeid, x, y, radioRange)
 
-	if !d.cfg.Real {
+	if !d.cfg.Real && d.offlineSink == nil {
 		// Wait until node's extended address is emitted (but not for real devices)
 		// This helps OTNS to make sure that the child process is ready to receive UDP events
 		t0 := time.Now()
 		deadline := t0.Add(time.Second * 10)
 		for node.ExtAddr == InvalidExtAddr && time.Now().Before(deadline) {
+			if exited != nil && exited() {
+				return &NodeNotRespondingError{Node: nodeid, Events: node.recvEvents, Exited: true}
+			}
 			d.RecvEvents()
 		}
 
 		if node.ExtAddr == InvalidExtAddr {
-			simplelogger.Panicf("expect node %d's extaddr to be valid, but failed", nodeid)
+			return &NodeNotRespondingError{Node: nodeid, Events: node.recvEvents}
 		} else {
 			takeTime := time.Since(t0)
 			simplelogger.Debugf("node %d's extaddr becomes valid in %v", nodeid, takeTime)
 		}
 	}
+	return nil
 }
 
 func (d *Dispatcher) setNodeRloc16(srcid NodeId, rloc16 uint16) {
@@ -921,6 +1321,7 @@ func (d *Dispatcher) setNodeRloc16(srcid NodeId, rloc16 uint16) {
 		// add node to the new rloc map
 		d.rloc16Map.Add(rloc16, node)
 	}
+	d.onTimelineRloc16(node, oldRloc16)
 
 	d.vis.SetNodeRloc16(srcid, rloc16)
 }
@@ -1021,6 +1422,10 @@ func (d *Dispatcher) visSend(srcid NodeId, dstid NodeId, visInfo *visualize.MsgV
 		}
 	}
 
+	if !d.visOptions.involves(srcid, dstid) {
+		return
+	}
+
 	d.vis.Send(srcid, dstid, visInfo)
 }
 
@@ -1029,6 +1434,7 @@ func (d *Dispatcher) advanceTime(ts uint64) {
 	if d.CurTime < ts {
 		oldTime := d.CurTime
 		d.CurTime = ts
+		d.windowStats.Advance(ts)
 		elapsedTime := int64(d.CurTime - d.speedStartTime)
 		elapsedRealTime := time.Since(d.speedStartRealTime) / time.Microsecond
 		if elapsedRealTime > 0 && ts/1000000 != oldTime/1000000 {
@@ -1050,7 +1456,11 @@ func (d *Dispatcher) PostAsync(trivial bool, task func()) {
 			break
 		}
 	} else {
-		d.taskChan <- task
+		// the task is dropped if the dispatcher exits before running it
+		select {
+		case d.taskChan <- task:
+		case <-d.ctx.Done():
+		}
 	}
 }
 
@@ -1074,6 +1484,25 @@ loop:
 	}
 }
 
+// ScheduleAt runs the task in the dispatcher routine when the simulation reaches the specified time.
+func (d *Dispatcher) ScheduleAt(timestamp uint64, task func()) {
+	simplelogger.AssertTrue(timestamp >= d.CurTime)
+	d.timers.Add(timestamp, task)
+}
+
+func (d *Dispatcher) handleTimers() {
+	defer func() {
+		err := recover()
+		if err != nil {
+			simplelogger.Errorf("dispatcher handle timer failed: %+v", err)
+		}
+	}()
+
+	for d.timers.NextTimestamp() <= d.CurTime {
+		d.timers.PopNext().Task()
+	}
+}
+
 func (d *Dispatcher) WatchNode(nodeid NodeId) {
 	d.watchingNodes[nodeid] = struct{}{}
 }
@@ -1120,6 +1549,13 @@ func (d *Dispatcher) DeleteNode(id NodeId) {
 	delete(d.nodes, id)
 	delete(d.aliveNodes, id)
 	delete(d.watchingNodes, id)
+	delete(d.radioWatchingNodes, id)
+	delete(d.jammers, id)
+	delete(d.pendingUpgrades, id)
+	delete(d.zombie.zombies, id)
+	d.deleteLinkFrameDups(id)
+	d.deleteHeldFrames(id)
+	d.airtime.DeleteNode(id)
 	if node.Rloc16 != threadconst.InvalidRloc16 {
 		d.rloc16Map.Remove(node.Rloc16, node)
 	}
@@ -1128,11 +1564,65 @@ func (d *Dispatcher) DeleteNode(id NodeId) {
 		delete(d.extaddrMap, node.ExtAddr)
 	}
 	d.alarmMgr.DeleteNode(id)
+	delete(d.batchNodes, id)
+	d.closeSharedMemory(id)
+	if node.tcpConn != nil {
+		_ = node.tcpConn.Close()
+	}
+	if d.trace != nil {
+		d.trace.onNodeDeleted(id)
+	}
 	d.deletedNodes[id] = struct{}{}
+	d.addTimelineEvent(TimelineNodeDelete, id, "", "")
 
 	d.vis.DeleteNode(id)
 }
 
+// Reset clears the state of the dispatcher, including the time, counters, scheduled tasks, CoAP messages, KPI and
+// statistics, and restarts the pcap file. All nodes must have been deleted. Configurations such as the speed, PLR and
+// radio model are kept.
+func (d *Dispatcher) Reset() {
+	simplelogger.AssertTrue(len(d.nodes) == 0)
+
+	d.CurTime = 0
+	d.pauseTime = 0
+	d.speedStartTime = 0
+	d.speedStartRealTime = time.Now()
+	d.alarmMgr = newAlarmMgr()
+	d.sendQueue = newSendQueue()
+	d.timers = newTimerQueue()
+	d.extaddrMap = map[uint64]*Node{}
+	d.rloc16Map = rloc16Map{}
+	reflect.ValueOf(&d.Counters).Elem().Set(reflect.Zero(reflect.TypeOf(d.Counters)))
+	d.countersOffset = nil
+	d.counterSnapshots = nil
+	d.stall = stallDetector{}
+	d.runStats = runStatsCollector{}
+	if d.coaps != nil {
+		d.coaps = newCoapsHandler()
+	}
+	d.airtime = newAirtimeMeter(0)
+	d.windowStats = newWindowStatsCollector(d.windowStats.Config(), 0)
+	d.upgradeResults = nil
+	d.kpi = kpiCollector{}
+	d.joinHistory = joinHistory{}
+	d.frags = newFragTracker()
+	d.linkStats = newLinkStatsCollector()
+	d.electionRun = nil
+	d.resetTimelines()
+	d.resetAlerts()
+	d.attachLogs = nil
+	d.zombie.zombies = nil
+	d.linkMetrics = linkMetricsCollector{}
+	d.heldFrames = nil
+	d.onAir = nil
+
+	if d.pcap != nil {
+		d.pcapFrameChan <- pcapFrameItem{Reset: true}
+	}
+	d.vis.AdvanceTime(0, d.speed)
+}
+
 func (d *Dispatcher) SetNodeFailed(id NodeId, fail bool) {
 	node := d.nodes[id]
 	simplelogger.AssertNotNil(node)
@@ -1147,6 +1637,45 @@ func (d *Dispatcher) SetNodeFailed(id NodeId, fail bool) {
 	}
 }
 
+// PauseNode stops delivering alarm and radio events to the node, so that the node misses virtual time until it is
+// resumed. The node is not considered failed while it is paused.
+func (d *Dispatcher) PauseNode(id NodeId) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertFalse(d.cfg.Real)
+
+	if node.isPaused {
+		return
+	}
+
+	if _, alive := d.aliveNodes[id]; alive {
+		// wait for the node to sleep, so that its next alarm is known
+		d.RecvEvents()
+	}
+
+	node.isPaused = true
+	node.pausedAlarm = d.alarmMgr.GetTimestamp(id)
+	d.alarmMgr.SetTimestamp(id, Ever)
+}
+
+// ResumeNode resumes delivering events to the paused node. Alarms that expired while the node was paused fire
+// immediately.
+func (d *Dispatcher) ResumeNode(id NodeId) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+
+	if !node.isPaused {
+		return
+	}
+
+	node.isPaused = false
+	alarmTs := node.pausedAlarm
+	if alarmTs < d.CurTime {
+		alarmTs = d.CurTime
+	}
+	d.alarmMgr.SetTimestamp(id, alarmTs)
+}
+
 func (d *Dispatcher) SetSpeed(f float64) {
 	ns := d.normalizeSpeed(f)
 	if ns == d.speed {
@@ -1173,30 +1702,56 @@ func (d *Dispatcher) GetSpeed() float64 {
 	return d.speed
 }
 
+// GetGlobalMessageDropRatio returns the packet loss ratio of 128 byte frames. Shorter frames are lost with a lower
+// ratio.
 func (d *Dispatcher) GetGlobalMessageDropRatio() float64 {
 	return d.globalPacketLossRatio
 }
 
+// SetGlobalPacketLossRatio sets the packet loss ratio of 128 byte frames, and clears the packet loss ratio of ACK
+// frames so that it applies to all frames.
 func (d *Dispatcher) SetGlobalPacketLossRatio(plr float64) {
+	d.SetDataPacketLossRatio(plr)
+	d.ackPacketLossRatio = -1
+}
+
+// SetDataPacketLossRatio sets the packet loss ratio of 128 byte frames, without changing the packet loss ratio of ACK
+// frames.
+func (d *Dispatcher) SetDataPacketLossRatio(plr float64) {
+	d.globalPacketLossRatio = clampPacketLossRatio(plr)
+}
+
+// GetAckPacketLossRatio returns the packet loss ratio of ACK frames, or -1 if ACK frames are lost with the ratio of
+// all frames.
+func (d *Dispatcher) GetAckPacketLossRatio() float64 {
+	return d.ackPacketLossRatio
+}
+
+// SetAckPacketLossRatio sets the packet loss ratio of ACK frames, which does not depend on the frame length.
+func (d *Dispatcher) SetAckPacketLossRatio(plr float64) {
+	d.ackPacketLossRatio = clampPacketLossRatio(plr)
+}
+
+func clampPacketLossRatio(plr float64) float64 {
 	if plr > 1 {
 		plr = 1
 	} else if plr < 0 {
 		plr = 0
 	}
-	d.globalPacketLossRatio = plr
+	return plr
 }
 
 func (d *Dispatcher) convertNodeMilliTime(node *Node, milliTime uint32) uint64 {
-	ts := node.CreateTime + uint64(milliTime)*1000 // convert to us
+	local := uint64(milliTime) * 1000 // convert to us
 
 	// because timestamp on node is uint32_t, so it can not exceed 1293 hours, after that the timestamp rewinds from zero
 	// so we should calculate the real timestamp.
 	// This assumes that the node is not far behind in time
-	for ts+(0xffffffff*1000) < d.CurTime {
-		ts += 0xffffffff * 1000
+	for node.CreateTime+node.clockDrift.simTime(local+0xffffffff*1000) < d.CurTime {
+		local += 0xffffffff * 1000
 	}
 
-	return ts
+	return node.CreateTime + node.clockDrift.simTime(local)
 }
 
 func (d *Dispatcher) onStatusPushExtAddr(node *Node, oldExtAddr uint64) {
@@ -1242,6 +1797,9 @@ func (d *Dispatcher) dumpPacket(item *sendItem) {
 	for _, b := range item.Data {
 		_, _ = fmt.Fprintf(&sb, "%02X", b)
 	}
+	if d.cfg.LogCorrelation {
+		_, _ = fmt.Fprintf(&sb, ":%d", d.nodeLogSeq(item.NodeId))
+	}
 
 	_, _ = fmt.Fprintf(os.Stdout, "%s\n", sb.String())
 }
@@ -1253,8 +1811,15 @@ func (d *Dispatcher) setNodeRole(id NodeId, role OtDeviceRole) {
 		return
 	}
 
+	oldRole := node.Role
 	node.Role = role
+	d.onTimelineRole(node, oldRole)
+	if role >= OtDeviceRoleChild {
+		node.onAttached()
+	}
 	d.vis.SetNodeRole(id, role)
+	d.onUpgradedNodeRole(id, role)
+	d.onElectionNodeRole(id, role)
 }
 
 func (d *Dispatcher) handleCoapEvent(node *Node, argsStr string) {
@@ -1319,3 +1884,34 @@ func (d *Dispatcher) CollectCoapMessages() []*CoapMessage {
 		return nil
 	}
 }
+
+// GetAirtimeReport returns the airtime usage of all nodes in the current accounting window.
+func (d *Dispatcher) GetAirtimeReport() *AirtimeReport {
+	return d.airtime.Report(d.CurTime)
+}
+
+// ResetAirtime starts a new airtime accounting window at the current time.
+func (d *Dispatcher) ResetAirtime() {
+	d.airtime.Reset(d.CurTime)
+}
+
+// GetWindowStats returns the completed time windows, oldest first.
+func (d *Dispatcher) GetWindowStats() []*TimeWindowStats {
+	return d.windowStats.Windows()
+}
+
+// GetWindowStatsConfig returns the current time window statistics configuration.
+func (d *Dispatcher) GetWindowStatsConfig() WindowStatsConfig {
+	return d.windowStats.Config()
+}
+
+// GetCurrentWindowEnd returns the end time of the current time window.
+func (d *Dispatcher) GetCurrentWindowEnd() uint64 {
+	return d.windowStats.current.End
+}
+
+// SetWindowStatsConfig reconfigures the time window statistics, discarding all collected windows.
+func (d *Dispatcher) SetWindowStatsConfig(cfg WindowStatsConfig) {
+	simplelogger.AssertTrue(cfg.Interval > 0 && cfg.Retention > 0)
+	d.windowStats.Configure(cfg, d.CurTime)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAckPacketLossRatio(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.windowStats = newWindowStatsCollector(DefaultWindowStatsConfig(), 0)
+	src, dst := d.nodes[1], d.nodes[2]
+	send := func(ack bool) bool {
+		d.CurTime += 1000
+		d.sendOneMessage(&sendItem{Timestamp: d.CurTime, NodeId: 1, Data: []byte{11, 0x41, 0xd8}, ack: ack}, src,
+			dst, nil)
+		return dst.CurTime == d.CurTime
+	}
+
+	// all data frames are lost, but no ACK
+	d.SetDataPacketLossRatio(1)
+	d.SetAckPacketLossRatio(0)
+	assert.False(t, send(false))
+	assert.True(t, send(true))
+
+	// all ACK frames are lost, but no data frame
+	d.SetDataPacketLossRatio(0)
+	d.SetAckPacketLossRatio(2)
+	assert.Equal(t, 1.0, d.GetAckPacketLossRatio())
+	assert.True(t, send(false))
+	assert.False(t, send(true))
+
+	// the global packet loss ratio applies to all frames
+	d.SetGlobalPacketLossRatio(1)
+	assert.Equal(t, -1.0, d.GetAckPacketLossRatio())
+	assert.False(t, send(false))
+	assert.False(t, send(true))
+	d.SetGlobalPacketLossRatio(0)
+	assert.True(t, send(true))
+}
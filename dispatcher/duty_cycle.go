@@ -0,0 +1,157 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	DefaultDutyCycleWindow = 3600000000 // 1 hour in us
+)
+
+// DutyCycleLimit limits the airtime of a node in a sliding window, as required by the regulatory rules of some
+// sub-GHz regions (e.g. 1% per hour).
+type DutyCycleLimit struct {
+	Ratio  float64 // max ratio of the window the node may transmit, in (0, 1]
+	Window uint64  // us
+}
+
+// Budget returns the airtime (in us) the node may use in the window.
+func (l *DutyCycleLimit) Budget() uint64 {
+	return uint64(l.Ratio * float64(l.Window))
+}
+
+// DutyCycleStat contains the duty-cycle usage of a node with a duty-cycle limit.
+type DutyCycleStat struct {
+	NodeId        NodeId  `json:"node"`
+	Ratio         float64 `json:"limit"`
+	Window        uint64  `json:"window_us"`
+	Airtime       uint64  `json:"airtime_us"` // airtime used in the current window
+	Utilization   float64 `json:"util"`       // ratio of the budget used in the current window
+	TxFrames      uint64  `json:"frames"`
+	BlockedFrames uint64  `json:"blocked"`
+}
+
+type dutyCycleTx struct {
+	time    uint64
+	airtime uint64
+}
+
+// dutyCycle tracks the transmissions of a node within the sliding window of its duty-cycle limit.
+type dutyCycle struct {
+	limit    DutyCycleLimit
+	txs      []dutyCycleTx // transmissions within the window, oldest first
+	airtime  uint64        // total airtime of txs
+	frames   uint64
+	blocked  uint64
+	blocking bool // if the last transmission was blocked
+}
+
+func (dc *dutyCycle) expire(now uint64) {
+	i := 0
+	for ; i < len(dc.txs) && dc.txs[i].time+dc.limit.Window <= now; i++ {
+		dc.airtime -= dc.txs[i].airtime
+	}
+	dc.txs = dc.txs[i:]
+}
+
+// onTransmit returns if the transmission of a frame with the specified airtime is allowed, and records it if so.
+func (dc *dutyCycle) onTransmit(now uint64, airtime uint64) bool {
+	dc.expire(now)
+	if dc.airtime+airtime > dc.limit.Budget() {
+		dc.blocked++
+		return false
+	}
+
+	dc.txs = append(dc.txs, dutyCycleTx{time: now, airtime: airtime})
+	dc.airtime += airtime
+	dc.frames++
+	return true
+}
+
+// checkDutyCycle returns if the node is allowed to transmit the frame under its duty-cycle limit.
+// A blocked frame is not put on air, while the node still gets the TX done notification.
+func (d *Dispatcher) checkDutyCycle(node *Node, psduLen int) bool {
+	dc := node.dutyCycle
+	if dc == nil {
+		return true
+	}
+
+	if dc.onTransmit(d.CurTime, frameAirtime(psduLen)) {
+		dc.blocking = false
+		return true
+	}
+
+	d.Counters.DutyCycleBlockedFrames++
+	d.onFrameDropped(node.Id, DropReasonDutyCycle)
+	if !dc.blocking {
+		simplelogger.Warnf("node %d exceeds duty-cycle limit of %g%% per %ds, blocking transmissions", node.Id,
+			dc.limit.Ratio*100, dc.limit.Window/1000000)
+	}
+	dc.blocking = true
+	return false
+}
+
+// SetNodeDutyCycle sets the duty-cycle limit of the node, or removes it if limit is nil. Setting a limit starts a new
+// window without previous transmissions.
+func (d *Dispatcher) SetNodeDutyCycle(id NodeId, limit *DutyCycleLimit) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+
+	if limit == nil {
+		node.dutyCycle = nil
+		return
+	}
+
+	simplelogger.AssertTrue(limit.Ratio > 0 && limit.Ratio <= 1 && limit.Window > 0)
+	node.dutyCycle = &dutyCycle{limit: *limit}
+}
+
+// GetNodeDutyCycle returns the duty-cycle usage of the node, or nil if the node has no duty-cycle limit.
+func (d *Dispatcher) GetNodeDutyCycle(id NodeId) *DutyCycleStat {
+	node := d.nodes[id]
+	if node == nil || node.dutyCycle == nil {
+		return nil
+	}
+
+	dc := node.dutyCycle
+	dc.expire(d.CurTime)
+	stat := &DutyCycleStat{
+		NodeId:        id,
+		Ratio:         dc.limit.Ratio,
+		Window:        dc.limit.Window,
+		Airtime:       dc.airtime,
+		TxFrames:      dc.frames,
+		BlockedFrames: dc.blocked,
+	}
+	if budget := dc.limit.Budget(); budget > 0 {
+		stat.Utilization = float64(dc.airtime) / float64(budget)
+	}
+	return stat
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDutyCycle(t *testing.T) {
+	// 1% of 1s allows 10ms of airtime
+	dc := &dutyCycle{limit: DutyCycleLimit{Ratio: 0.01, Window: 1000000}}
+	assert.Equal(t, uint64(10000), dc.limit.Budget())
+
+	assert.True(t, dc.onTransmit(0, 4000))
+	assert.True(t, dc.onTransmit(100000, 4000))
+	assert.False(t, dc.onTransmit(200000, 4000))
+	assert.True(t, dc.onTransmit(300000, 2000))
+	assert.Equal(t, uint64(10000), dc.airtime)
+
+	// the first transmission leaves the window
+	assert.True(t, dc.onTransmit(1000000, 4000))
+	assert.False(t, dc.onTransmit(1050000, 4000))
+	assert.Equal(t, uint64(4), dc.frames)
+	assert.Equal(t, uint64(2), dc.blocked)
+
+	dc.expire(1400000)
+	assert.Equal(t, uint64(4000), dc.airtime)
+	assert.Equal(t, 1, len(dc.txs))
+}
+
+func TestSetNodeDutyCycle(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	assert.Nil(t, d.GetNodeDutyCycle(1))
+
+	d.SetNodeDutyCycle(1, &DutyCycleLimit{Ratio: 0.01, Window: DefaultDutyCycleWindow})
+	assert.True(t, d.checkDutyCycle(d.nodes[1], 127))
+	assert.True(t, d.checkDutyCycle(d.nodes[2], 127))
+
+	stat := d.GetNodeDutyCycle(1)
+	assert.Equal(t, frameAirtime(127), stat.Airtime)
+	assert.Equal(t, uint64(1), stat.TxFrames)
+	assert.InDelta(t, float64(frameAirtime(127))/36000000, stat.Utilization, 1e-9)
+	assert.Nil(t, d.GetNodeDutyCycle(2))
+
+	d.SetNodeDutyCycle(1, nil)
+	assert.Nil(t, d.GetNodeDutyCycle(1))
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// ElectionRun is the result of a forced leader failure.
+type ElectionRun struct {
+	FailedLeader NodeId
+	FailTime     uint64 // time when the leader was failed
+	NewLeader    NodeId // the first node becoming leader after the failure, InvalidNodeId if none
+	ElectedTime  uint64 // time when the new leader was elected, 0 if not elected
+	Partitions   int    // partitions of the alive nodes when the run finished
+}
+
+// Elected returns if a new leader was elected.
+func (r *ElectionRun) Elected() bool {
+	return r.NewLeader != InvalidNodeId
+}
+
+// Downtime returns the duration from the leader failure to a new leader being elected, or 0 if not elected.
+func (r *ElectionRun) Downtime() uint64 {
+	if !r.Elected() {
+		return 0
+	}
+	return r.ElectedTime - r.FailTime
+}
+
+// StartElectionRun fails the radio of the leader with the lowest node ID, and tracks the election of a new leader from
+// the role changes of the other nodes until FinishElectionRun.
+func (d *Dispatcher) StartElectionRun() (*ElectionRun, error) {
+	if d.electionRun != nil {
+		return nil, errors.Errorf("leader failure of node %d is being tracked", d.electionRun.FailedLeader)
+	}
+
+	leader := InvalidNodeId
+	for _, nodeid := range d.sortedNodeIds() {
+		node := d.nodes[nodeid]
+		if node.Role == OtDeviceRoleLeader && !node.IsFailed() {
+			leader = nodeid
+			break
+		}
+	}
+	if leader == InvalidNodeId {
+		return nil, errors.Errorf("no leader")
+	}
+
+	d.electionRun = &ElectionRun{
+		FailedLeader: leader,
+		FailTime:     d.CurTime,
+		NewLeader:    InvalidNodeId,
+	}
+	d.SetNodeFailed(leader, true)
+	return d.electionRun, nil
+}
+
+// GetElectionRun returns the election being tracked, or nil if none.
+func (d *Dispatcher) GetElectionRun() *ElectionRun {
+	return d.electionRun
+}
+
+// FinishElectionRun stops tracking the election, counts the resulting partitions and recovers the failed leader.
+func (d *Dispatcher) FinishElectionRun() *ElectionRun {
+	run := d.electionRun
+	if run == nil {
+		return nil
+	}
+
+	d.electionRun = nil
+	run.Partitions = d.countPartitions()
+	if d.nodes[run.FailedLeader] != nil {
+		d.SetNodeFailed(run.FailedLeader, false)
+	}
+	return run
+}
+
+func (d *Dispatcher) onElectionNodeRole(id NodeId, role OtDeviceRole) {
+	run := d.electionRun
+	if run == nil || run.Elected() || role != OtDeviceRoleLeader || id == run.FailedLeader {
+		return
+	}
+
+	run.NewLeader = id
+	run.ElectedTime = d.CurTime
+}
+
+// countPartitions returns the number of partitions of the attached nodes which are not failed.
+func (d *Dispatcher) countPartitions() int {
+	partitions := map[uint32]struct{}{}
+	for _, node := range d.nodes {
+		if node.IsFailed() || node.Role < OtDeviceRoleChild || node.PartitionId == 0 {
+			continue
+		}
+		partitions[node.PartitionId] = struct{}{}
+	}
+	return len(partitions)
+}
+
+func (d *Dispatcher) sortedNodeIds() []NodeId {
+	nodeids := make([]NodeId, 0, len(d.nodes))
+	for nodeid := range d.nodes {
+		nodeids = append(nodeids, nodeid)
+	}
+	sort.Ints(nodeids)
+	return nodeids
+}
+
+// ElectionSummary is the summary of election runs.
+type ElectionSummary struct {
+	Runs          int
+	Elected       int
+	Downtime      DurationStats // downtime of the runs with a new leader elected
+	MinPartitions int
+	MaxPartitions int
+	AvgPartitions float64
+}
+
+// SummarizeElections summarizes the election runs.
+func SummarizeElections(runs []*ElectionRun) ElectionSummary {
+	summary := ElectionSummary{Runs: len(runs)}
+	var downtimes []uint64
+	totalPartitions := 0
+
+	for i, run := range runs {
+		if run.Elected() {
+			summary.Elected++
+			downtimes = append(downtimes, run.Downtime())
+		}
+
+		if i == 0 || run.Partitions < summary.MinPartitions {
+			summary.MinPartitions = run.Partitions
+		}
+		if run.Partitions > summary.MaxPartitions {
+			summary.MaxPartitions = run.Partitions
+		}
+		totalPartitions += run.Partitions
+	}
+
+	summary.Downtime = newDurationStats(downtimes)
+	if len(runs) > 0 {
+		summary.AvgPartitions = float64(totalPartitions) / float64(len(runs))
+	}
+	return summary
+}
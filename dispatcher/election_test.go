@@ -0,0 +1,106 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+type nopCallbackHandler struct{}
+
+func (h nopCallbackHandler) OnNodeFail(nodeid NodeId)               {}
+func (h nopCallbackHandler) OnNodeRecover(nodeid NodeId)            {}
+func (h nopCallbackHandler) OnUartWrite(nodeid NodeId, data []byte) {}
+func (h nopCallbackHandler) GetNodeLogSeq(nodeid NodeId) uint64     { return 0 }
+
+func TestElectionRun(t *testing.T) {
+	d := &Dispatcher{
+		nodes:           map[NodeId]*Node{},
+		vis:             visualize.NewNopVisualizer(),
+		cbHandler:       nopCallbackHandler{},
+		pendingUpgrades: map[NodeId]*UpgradeResult{},
+	}
+	for nodeid := 1; nodeid <= 3; nodeid++ {
+		node := newNode(d, nodeid, 0, 0, 160)
+		node.Role, node.PartitionId = OtDeviceRoleRouter, 1
+		d.nodes[nodeid] = node
+	}
+	d.nodes[2].Role = OtDeviceRoleLeader
+
+	d.CurTime = 1000000
+	run, err := d.StartElectionRun()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, run.FailedLeader)
+	assert.True(t, d.nodes[2].IsFailed())
+	_, err = d.StartElectionRun()
+	assert.NotNil(t, err)
+
+	// the failed leader keeps its role, and the first other leader is the new leader
+	d.CurTime = 5000000
+	d.setNodeRole(2, OtDeviceRoleLeader)
+	assert.False(t, run.Elected())
+	d.CurTime = 21000000
+	d.nodes[3].PartitionId = 2
+	d.setNodeRole(3, OtDeviceRoleLeader)
+	d.CurTime = 22000000
+	d.setNodeRole(1, OtDeviceRoleLeader)
+	assert.Equal(t, 3, run.NewLeader)
+	assert.Equal(t, uint64(20000000), run.Downtime())
+
+	d.nodes[1].PartitionId = 3
+	assert.Equal(t, run, d.FinishElectionRun())
+	assert.Equal(t, 2, run.Partitions)
+	assert.False(t, d.nodes[2].IsFailed())
+	assert.Nil(t, d.FinishElectionRun())
+
+	for _, node := range d.nodes {
+		node.Role = OtDeviceRoleRouter
+	}
+	_, err = d.StartElectionRun()
+	assert.NotNil(t, err)
+}
+
+func TestSummarizeElections(t *testing.T) {
+	runs := []*ElectionRun{
+		{FailTime: 0, NewLeader: 2, ElectedTime: 10000000, Partitions: 1},
+		{FailTime: 100000000, NewLeader: InvalidNodeId, Partitions: 0},
+		{FailTime: 200000000, NewLeader: 3, ElectedTime: 230000000, Partitions: 2},
+	}
+
+	summary := SummarizeElections(runs)
+	assert.Equal(t, 3, summary.Runs)
+	assert.Equal(t, 2, summary.Elected)
+	assert.Equal(t, DurationStats{Count: 2, P50: 10000000, P90: 30000000, P99: 30000000, Max: 30000000}, summary.Downtime)
+	assert.Equal(t, 0, summary.MinPartitions)
+	assert.Equal(t, 2, summary.MaxPartitions)
+	assert.Equal(t, 1.0, summary.AvgPartitions)
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// ackWaitDurationUs is the time a node keeps its radio on after a transmission to receive the ACK
+	// (macAckWaitDuration: 54 symbols of 16us).
+	ackWaitDurationUs = 54 * 16
+
+	defaultEnergyTxMw    = 30
+	defaultEnergyRxMw    = 20
+	defaultEnergySleepMw = 0.003
+)
+
+// PowerSourceType is the class of the power source of a node.
+type PowerSourceType string
+
+const (
+	PowerMains   PowerSourceType = "mains"   // unlimited energy
+	PowerBattery PowerSourceType = "battery" // a battery which is not recharged
+	PowerHarvest PowerSourceType = "harvest" // a battery recharged by energy harvesting
+)
+
+// PowerSources returns the power source classes in report order.
+func PowerSources() []PowerSourceType {
+	return []PowerSourceType{PowerMains, PowerBattery, PowerHarvest}
+}
+
+// PowerSource is the power source of a node. The battery of battery and harvesting nodes starts full. Harvesting nodes
+// recharge it with HarvestMw during the first HarvestDuty ratio of each HarvestPeriod, e.g. in daylight, or all the
+// time if HarvestPeriod is 0.
+type PowerSource struct {
+	Type          PowerSourceType `json:"source"`
+	CapacityMwh   float64         `json:"capacity_mwh,omitempty"`
+	HarvestMw     float64         `json:"harvest_mw,omitempty"`
+	HarvestPeriod uint64          `json:"harvest_period_us,omitempty"`
+	HarvestDuty   float64         `json:"harvest_duty,omitempty"`
+}
+
+func (ps *PowerSource) String() string {
+	switch ps.Type {
+	case PowerBattery:
+		return fmt.Sprintf("battery %gmWh", ps.CapacityMwh)
+	case PowerHarvest:
+		s := fmt.Sprintf("harvest %gmWh %gmW", ps.CapacityMwh, ps.HarvestMw)
+		if ps.HarvestPeriod > 0 {
+			s += fmt.Sprintf(" period %gs duty %g%%", float64(ps.HarvestPeriod)/1000000, ps.HarvestDuty*100)
+		}
+		return s
+	default:
+		return string(PowerMains)
+	}
+}
+
+// capacityMj returns the battery capacity in mJ, or 0 for mains power.
+func (ps *PowerSource) capacityMj() float64 {
+	if ps.Type == PowerMains {
+		return 0
+	}
+	return ps.CapacityMwh * 3600
+}
+
+// harvestingUs returns the total harvesting time (in us) from time 0 to the time.
+func (ps *PowerSource) harvestingUs(ts uint64) float64 {
+	if ps.HarvestPeriod == 0 {
+		return float64(ts)
+	}
+	on := ps.HarvestDuty * float64(ps.HarvestPeriod)
+	return float64(ts/ps.HarvestPeriod)*on + math.Min(float64(ts%ps.HarvestPeriod), on)
+}
+
+// harvestedMj returns the energy (in mJ) harvested from the time from to the time to.
+func (ps *PowerSource) harvestedMj(from, to uint64) float64 {
+	if ps.Type != PowerHarvest {
+		return 0
+	}
+	return ps.HarvestMw * (ps.harvestingUs(to) - ps.harvestingUs(from)) / 1000000
+}
+
+// averageHarvestMw returns the average harvesting power over a harvesting period.
+func (ps *PowerSource) averageHarvestMw() float64 {
+	if ps.Type != PowerHarvest {
+		return 0
+	}
+	if ps.HarvestPeriod == 0 {
+		return ps.HarvestMw
+	}
+	return ps.HarvestMw * ps.HarvestDuty
+}
+
+// EnergyModel is the power consumed by the radio of a node in each state.
+type EnergyModel struct {
+	TxMw    float64 `json:"tx_mw"`
+	RxMw    float64 `json:"rx_mw"`
+	SleepMw float64 `json:"sleep_mw"`
+}
+
+func DefaultEnergyModel() EnergyModel {
+	return EnergyModel{TxMw: defaultEnergyTxMw, RxMw: defaultEnergyRxMw, SleepMw: defaultEnergySleepMw}
+}
+
+// energyMeter estimates the energy used by the radio of a node from its transmissions and its mode. The radio of a
+// node which is rx-on-when-idle listens whenever it does not transmit. Otherwise, it sleeps except while transmitting
+// and waiting for the ACK of a transmission.
+type energyMeter struct {
+	start       uint64
+	last        uint64 // time of the last update
+	rxOff       bool   // the node is rx-off-when-idle
+	busy        uint64 // time of transmissions and ACK waits beyond the last update
+	txUs        uint64
+	rxUs        uint64
+	sleepUs     uint64
+	consumedMj  float64
+	harvestedMj float64
+	levelMj     float64 // battery level
+}
+
+func (em *energyMeter) reset(now uint64, ps *PowerSource) {
+	*em = energyMeter{start: now, last: now, rxOff: em.rxOff, levelMj: ps.capacityMj()}
+}
+
+// update accounts the idle time of the radio and the harvested energy until now.
+func (em *energyMeter) update(now uint64, model *EnergyModel, ps *PowerSource) {
+	if now <= em.last {
+		return
+	}
+
+	idle := now - em.last
+	if em.busy >= idle {
+		em.busy -= idle
+		idle = 0
+	} else {
+		idle -= em.busy
+		em.busy = 0
+	}
+	if em.rxOff {
+		em.sleepUs += idle
+		em.consume(model.SleepMw*float64(idle)/1000000, ps)
+	} else {
+		em.rxUs += idle
+		em.consume(model.RxMw*float64(idle)/1000000, ps)
+	}
+
+	harvested := ps.harvestedMj(em.last, now)
+	em.harvestedMj += harvested
+	em.levelMj = math.Min(em.levelMj+harvested, ps.capacityMj())
+	em.last = now
+}
+
+func (em *energyMeter) consume(mj float64, ps *PowerSource) {
+	em.consumedMj += mj
+	if ps.Type != PowerMains {
+		em.levelMj = math.Max(em.levelMj-mj, 0)
+	}
+}
+
+// onTransmit accounts a transmission with the airtime, and the following ACK wait of an rx-off-when-idle node.
+func (em *energyMeter) onTransmit(now uint64, airtime uint64, model *EnergyModel, ps *PowerSource) {
+	em.update(now, model, ps)
+	em.txUs += airtime
+	em.busy += airtime
+	em.consume(model.TxMw*float64(airtime)/1000000, ps)
+	if em.rxOff {
+		em.rxUs += ackWaitDurationUs
+		em.busy += ackWaitDurationUs
+		em.consume(model.RxMw*ackWaitDurationUs/1000000, ps)
+	}
+}
+
+// EnergyStat is the estimated radio energy of a node since the energy accounting started.
+type EnergyStat struct {
+	NodeId      NodeId          `json:"node"`
+	Source      PowerSourceType `json:"source"`
+	TxUs        uint64          `json:"tx_us"`
+	RxUs        uint64          `json:"rx_us"`
+	SleepUs     uint64          `json:"sleep_us"`
+	ConsumedMj  float64         `json:"consumed_mj"`
+	HarvestedMj float64         `json:"harvested_mj"`
+	AvgPowerMw  float64         `json:"avg_mw"`
+	// Battery is the remaining ratio of the battery capacity, and Lifetime the expected remaining lifetime (in s) of
+	// the battery at the average power consumption net of the average harvesting power. Lifetime is nil if the battery
+	// is not expected to run out, and both are nil for mains-powered nodes.
+	Battery  *float64 `json:"battery,omitempty"`
+	Lifetime *float64 `json:"lifetime_s,omitempty"`
+}
+
+// EnergyClassStat aggregates the energy statistics of the nodes of a power source class.
+type EnergyClassStat struct {
+	Source      PowerSourceType `json:"source"`
+	Nodes       int             `json:"nodes"`
+	ConsumedMj  float64         `json:"consumed_mj"`
+	AvgPowerMw  float64         `json:"avg_mw"`                   // average power per node
+	MinLifetime *float64        `json:"min_lifetime_s,omitempty"` // shortest expected lifetime of the nodes
+}
+
+// EnergyReport is the energy report of all nodes.
+type EnergyReport struct {
+	Model   EnergyModel       `json:"model"`
+	Nodes   []EnergyStat      `json:"nodes"`
+	Classes []EnergyClassStat `json:"classes"`
+}
+
+func (d *Dispatcher) onEnergyTransmit(node *Node, psduLen int) {
+	node.energy.onTransmit(d.CurTime, frameAirtime(psduLen), &d.energyModel, &node.powerSource)
+}
+
+func (d *Dispatcher) onEnergyMode(node *Node, mode NodeMode) {
+	node.energy.update(d.CurTime, &d.energyModel, &node.powerSource)
+	node.energy.rxOff = !mode.RxOnWhenIdle
+}
+
+// SetNodePowerSource sets the power source of the node, which starts with a full battery.
+func (d *Dispatcher) SetNodePowerSource(id NodeId, ps PowerSource) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertTrue(ps.Type == PowerMains || ps.CapacityMwh > 0)
+
+	node.energy.update(d.CurTime, &d.energyModel, &node.powerSource)
+	node.powerSource = ps
+	node.energy.levelMj = ps.capacityMj()
+}
+
+// GetNodePowerSource returns the power source of the node.
+func (d *Dispatcher) GetNodePowerSource(id NodeId) PowerSource {
+	return d.nodes[id].powerSource
+}
+
+// SetEnergyModel sets the power consumed by the radios. The energy used so far is kept.
+func (d *Dispatcher) SetEnergyModel(model EnergyModel) {
+	for _, node := range d.nodes {
+		node.energy.update(d.CurTime, &d.energyModel, &node.powerSource)
+	}
+	d.energyModel = model
+}
+
+// GetEnergyModel returns the power consumed by the radios.
+func (d *Dispatcher) GetEnergyModel() EnergyModel {
+	return d.energyModel
+}
+
+// ResetEnergy restarts the energy accounting of all nodes, with full batteries.
+func (d *Dispatcher) ResetEnergy() {
+	for _, node := range d.nodes {
+		node.energy.reset(d.CurTime, &node.powerSource)
+	}
+}
+
+// GetEnergyReport returns the energy statistics of all nodes in ID order, and their aggregates by power source class.
+func (d *Dispatcher) GetEnergyReport() *EnergyReport {
+	report := &EnergyReport{Model: d.energyModel, Nodes: []EnergyStat{}, Classes: []EnergyClassStat{}}
+	for id, node := range d.nodes {
+		em := &node.energy
+		em.update(d.CurTime, &d.energyModel, &node.powerSource)
+		stat := EnergyStat{
+			NodeId:      id,
+			Source:      node.powerSource.Type,
+			TxUs:        em.txUs,
+			RxUs:        em.rxUs,
+			SleepUs:     em.sleepUs,
+			ConsumedMj:  em.consumedMj,
+			HarvestedMj: em.harvestedMj,
+		}
+		if elapsed := d.CurTime - em.start; elapsed > 0 {
+			stat.AvgPowerMw = em.consumedMj * 1000000 / float64(elapsed)
+		}
+		if capacity := node.powerSource.capacityMj(); capacity > 0 {
+			battery := em.levelMj / capacity
+			stat.Battery = &battery
+			if net := stat.AvgPowerMw - node.powerSource.averageHarvestMw(); net > 0 {
+				lifetime := em.levelMj / net
+				stat.Lifetime = &lifetime
+			}
+		}
+		report.Nodes = append(report.Nodes, stat)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].NodeId < report.Nodes[j].NodeId
+	})
+
+	for _, source := range PowerSources() {
+		class := EnergyClassStat{Source: source}
+		for i := range report.Nodes {
+			stat := &report.Nodes[i]
+			if stat.Source != source {
+				continue
+			}
+			class.Nodes++
+			class.ConsumedMj += stat.ConsumedMj
+			class.AvgPowerMw += stat.AvgPowerMw
+			if stat.Lifetime != nil && (class.MinLifetime == nil || *stat.Lifetime < *class.MinLifetime) {
+				class.MinLifetime = stat.Lifetime
+			}
+		}
+		if class.Nodes > 0 {
+			class.AvgPowerMw /= float64(class.Nodes)
+			report.Classes = append(report.Classes, class)
+		}
+	}
+	return report
+}
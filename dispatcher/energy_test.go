@@ -0,0 +1,119 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestHarvestedEnergy(t *testing.T) {
+	ps := PowerSource{Type: PowerHarvest, CapacityMwh: 1, HarvestMw: 2}
+	assert.Equal(t, 20.0, ps.harvestedMj(0, 10000000))
+	assert.Equal(t, 2.0, ps.averageHarvestMw())
+
+	// harvesting in the first 25% of each 4s period
+	ps.HarvestPeriod, ps.HarvestDuty = 4000000, 0.25
+	assert.Equal(t, 2.0, ps.harvestedMj(0, 1000000))
+	assert.Equal(t, 0.0, ps.harvestedMj(1000000, 4000000))
+	assert.Equal(t, 4.0, ps.harvestedMj(500000, 8500000))
+	assert.Equal(t, 0.5, ps.averageHarvestMw())
+
+	ps.Type = PowerBattery
+	assert.Equal(t, 0.0, ps.harvestedMj(0, 1000000))
+}
+
+func TestEnergyMeter(t *testing.T) {
+	model := EnergyModel{TxMw: 30, RxMw: 20, SleepMw: 1}
+	ps := PowerSource{Type: PowerBattery, CapacityMwh: 1}
+	em := energyMeter{}
+	em.reset(0, &ps)
+	assert.Equal(t, 3600.0, em.levelMj)
+
+	// rx-on-when-idle: listening except while transmitting
+	em.onTransmit(1000000, 1000, &model, &ps)
+	em.update(2000000, &model, &ps)
+	assert.Equal(t, uint64(1000), em.txUs)
+	assert.Equal(t, uint64(1999000), em.rxUs)
+	assert.InDelta(t, 0.03+39.98, em.consumedMj, 1e-9)
+
+	// rx-off-when-idle: sleeping except while transmitting and waiting for the ACK
+	em.reset(2000000, &ps)
+	em.rxOff = true
+	em.onTransmit(2500000, 1000, &model, &ps)
+	em.update(3000000, &model, &ps)
+	assert.Equal(t, uint64(1000+ackWaitDurationUs), em.txUs+em.rxUs)
+	assert.Equal(t, uint64(1000000-1000-ackWaitDurationUs), em.sleepUs)
+	assert.InDelta(t, 0.03+0.01728+0.998136, em.consumedMj, 1e-9)
+	assert.InDelta(t, 3600-em.consumedMj, em.levelMj, 1e-9)
+
+	// the battery runs out
+	em.reset(0, &ps)
+	em.rxOff = false
+	em.update(1000000000, &model, &ps)
+	assert.Equal(t, 0.0, em.levelMj)
+}
+
+func TestEnergyReport(t *testing.T) {
+	d := &Dispatcher{nodes: map[NodeId]*Node{}, energyModel: EnergyModel{TxMw: 30, RxMw: 20, SleepMw: 1}}
+	for id := 1; id <= 3; id++ {
+		d.nodes[id] = newNode(d, id, 0, 0, 100)
+	}
+	d.SetNodePowerSource(2, PowerSource{Type: PowerBattery, CapacityMwh: 1})
+	d.SetNodePowerSource(3, PowerSource{Type: PowerHarvest, CapacityMwh: 1, HarvestMw: 30})
+	d.onEnergyMode(d.nodes[2], NodeMode{})
+	d.onEnergyMode(d.nodes[3], NodeMode{})
+
+	d.CurTime = 10000000
+	report := d.GetEnergyReport()
+	assert.Equal(t, 3, len(report.Nodes))
+	assert.Equal(t, EnergyStat{NodeId: 1, Source: PowerMains, RxUs: 10000000, ConsumedMj: 200, AvgPowerMw: 20},
+		report.Nodes[0])
+
+	battery := report.Nodes[1]
+	assert.Equal(t, uint64(10000000), battery.SleepUs)
+	assert.InDelta(t, 1, battery.AvgPowerMw, 1e-9)
+	assert.InDelta(t, 3590.0/3600, *battery.Battery, 1e-9)
+	assert.InDelta(t, 3590, *battery.Lifetime, 1e-6)
+
+	// the harvesting node stays full and does not run out
+	harvest := report.Nodes[2]
+	assert.Equal(t, 1.0, *harvest.Battery)
+	assert.Nil(t, harvest.Lifetime)
+
+	assert.Equal(t, []PowerSourceType{PowerMains, PowerBattery, PowerHarvest},
+		[]PowerSourceType{report.Classes[0].Source, report.Classes[1].Source, report.Classes[2].Source})
+	assert.Equal(t, 1, report.Classes[1].Nodes)
+	assert.InDelta(t, 3590, *report.Classes[1].MinLifetime, 1e-6)
+	assert.Nil(t, report.Classes[2].MinLifetime)
+
+	d.ResetEnergy()
+	assert.Equal(t, 0.0, d.GetEnergyReport().Nodes[0].ConsumedMj)
+}
@@ -27,8 +27,11 @@
 package dispatcher
 
 import (
+	"encoding/binary"
 	"net"
 
+	"github.com/pkg/errors"
+
 	. "github.com/openthread/ot-ns/types"
 )
 
@@ -37,10 +40,19 @@ const (
 	eventTypeRadioReceived = 1
 	eventTypeUartWrite     = 2
 	eventTypeStatusPush    = 5
+	eventTypeRadioLog      = 14 // radio trace of the OT-RFSIM platform
+	eventTypeRangingResult = 15 // only sent to nodes supporting ranging events
+	// radio frame of a node with additional radios, the first byte of the data is the radio index
+	eventTypeRadioReceivedMulti = 16
+	eventTypeRfSimRaw           = 18 // vendor or experimental OT-RFSIM platform event, in both directions
+	eventTypeInfraPacket        = 19 // packet on the infrastructure link of a border router, in both directions
 )
 
 type eventType = uint8
 
+// eventHeaderLen is the length of the header of event messages: the delay (8 bytes), the type and the data length.
+const eventHeaderLen = 11
+
 type event struct {
 	Delay   uint64
 	Type    eventType
@@ -48,4 +60,31 @@ type event struct {
 	DataLen uint16
 	Data    []byte
 	SrcAddr *net.UDPAddr
+	conn    net.Conn // TCP connection the event was received on, or nil
+	batched bool     // received in a batch event
+}
+
+// Serialize returns the message of the event, using Delay as the time elapsed on the node for messages to nodes.
+func (e *event) Serialize() []byte {
+	msg := make([]byte, eventHeaderLen+len(e.Data))
+	binary.LittleEndian.PutUint64(msg[:8], e.Delay)
+	msg[8] = e.Type
+	binary.LittleEndian.PutUint16(msg[9:11], uint16(len(e.Data)))
+	copy(msg[eventHeaderLen:], e.Data)
+	return msg
+}
+
+// Deserialize parses the event from a message. The data is copied, so that buf can be reused. NodeId and SrcAddr are
+// not part of the message and are left unchanged.
+func (e *event) Deserialize(buf []byte) error {
+	if len(buf) < eventHeaderLen {
+		return errors.Errorf("message length too short: %d", len(buf))
+	}
+
+	e.Delay = binary.LittleEndian.Uint64(buf[:8])
+	e.Type = buf[8]
+	e.DataLen = binary.LittleEndian.Uint16(buf[9:11])
+	e.Data = make([]byte, len(buf)-eventHeaderLen)
+	copy(e.Data, buf[eventHeaderLen:])
+	return nil
 }
@@ -0,0 +1,174 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// Event framing versions. With FramingV1, each message carries one event with an 11-byte header. With FramingV2, a
+// message may also be a batch event carrying several events with varint lengths, so that a node or the dispatcher can
+// send all its pending events in one socket write.
+//
+// A node supporting batches announces its highest framing version with the status push "framing=<version>". The
+// dispatcher answers with an empty batch event, and both sides may send batches from then on. The delay field of batch
+// events carries the framing version, and each event in the batch is encoded as its type (1 byte), its delay (uvarint),
+// the length of its data (uvarint) and the data.
+const (
+	FramingV1 = 1
+	FramingV2 = 2
+
+	eventTypeBatch = 17
+
+	// maxBatchLen is the maximum length of batch messages, which fits in the receive buffers of both sides.
+	maxBatchLen = 4096
+)
+
+// appendBatchedEvent appends the event encoded in a message of FramingV1 to the batch data.
+func appendBatchedEvent(batch []byte, msg []byte) []byte {
+	simplelogger.AssertTrue(len(msg) >= eventHeaderLen)
+
+	var buf [binary.MaxVarintLen64]byte
+	batch = append(batch, msg[8])
+	batch = append(batch, buf[:binary.PutUvarint(buf[:], binary.LittleEndian.Uint64(msg[:8]))]...)
+	batch = append(batch, buf[:binary.PutUvarint(buf[:], uint64(len(msg)-eventHeaderLen))]...)
+	return append(batch, msg[eventHeaderLen:]...)
+}
+
+// parseEventBatch parses the events of the data of a batch event.
+func parseEventBatch(data []byte) ([]*event, error) {
+	var events []*event
+	for len(data) > 0 {
+		typ := data[0]
+		delay, n := binary.Uvarint(data[1:])
+		if n <= 0 {
+			return nil, errors.Errorf("invalid delay of batched event %d", len(events))
+		}
+		data = data[1+n:]
+
+		datalen, n := binary.Uvarint(data)
+		if n <= 0 || datalen > uint64(len(data)-n) {
+			return nil, errors.Errorf("invalid data length of batched event %d", len(events))
+		}
+		data = data[n:]
+
+		events = append(events, &event{
+			Delay:   delay,
+			Type:    typ,
+			DataLen: uint16(datalen),
+			Data:    data[:datalen:datalen],
+		})
+		data = data[datalen:]
+	}
+	return events, nil
+}
+
+// unbatchEvent returns the events of the received event, which is a batch event or a single event.
+func unbatchEvent(evt *event) ([]*event, error) {
+	if evt.Type != eventTypeBatch {
+		return []*event{evt}, nil
+	}
+
+	events, err := parseEventBatch(evt.Data)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		e.NodeId = evt.NodeId
+		e.SrcAddr = evt.SrcAddr
+		e.conn = evt.conn
+		e.batched = true
+	}
+	return events, nil
+}
+
+// onStatusPushFraming negotiates the event framing with the node.
+func (d *Dispatcher) onStatusPushFraming(node *Node, version string) {
+	v, err := strconv.Atoi(version)
+	if err != nil || v < FramingV1 {
+		simplelogger.Warnf("%s announced an invalid framing version: %s", node, version)
+		return
+	}
+	if v < FramingV2 || node.framing >= FramingV2 {
+		return
+	}
+
+	// the empty batch acknowledges the framing version
+	node.SendMessage((&event{Delay: FramingV2, Type: eventTypeBatch}).Serialize())
+	node.framing = FramingV2
+}
+
+// GetFraming returns the event framing version negotiated with the node.
+func (node *Node) GetFraming() int {
+	if node.framing == 0 {
+		return FramingV1
+	}
+	return node.framing
+}
+
+// queueBatchedMessage adds the message of FramingV1 to the pending batch of the node.
+func (node *Node) queueBatchedMessage(msg []byte) {
+	if len(node.batch) > 0 && eventHeaderLen+len(node.batch)+len(msg)+2*binary.MaxVarintLen64 > maxBatchLen {
+		node.flushBatch()
+	}
+	if len(node.batch) == 0 {
+		node.D.batchNodes[node.Id] = node
+	}
+	node.batch = appendBatchedEvent(node.batch, msg)
+	node.batchCount++
+}
+
+// flushBatch sends the pending batch of the node. A batch of a single event is sent as the event itself.
+func (node *Node) flushBatch() {
+	if len(node.batch) == 0 {
+		return
+	}
+
+	var msg []byte
+	if node.batchCount == 1 {
+		events, err := parseEventBatch(node.batch)
+		simplelogger.PanicIfError(err)
+		msg = events[0].Serialize()
+	} else {
+		msg = (&event{Delay: FramingV2, Type: eventTypeBatch, Data: node.batch}).Serialize()
+	}
+	node.batch = node.batch[:0]
+	node.batchCount = 0
+	delete(node.D.batchNodes, node.Id)
+	node.writeMessage(msg)
+}
+
+// flushBatches sends the pending batches of all nodes, before the dispatcher waits for the nodes.
+func (d *Dispatcher) flushBatches() {
+	for _, node := range d.batchNodes {
+		node.flushBatch()
+	}
+}
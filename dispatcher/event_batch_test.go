@@ -0,0 +1,108 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBatch(t *testing.T) {
+	alarm := &event{Delay: 300, Type: eventTypeAlarmFired}
+	uart := &event{Delay: 0, Type: eventTypeUartWrite, Data: []byte("state\n")}
+	batch := appendBatchedEvent(nil, alarm.Serialize())
+	batch = appendBatchedEvent(batch, uart.Serialize())
+	// 2 bytes of delay for 300, 1 byte for 0
+	assert.Equal(t, 1+2+1+1+1+1+6, len(batch))
+
+	events, err := unbatchEvent(&event{Type: eventTypeBatch, NodeId: 3, Delay: FramingV2, Data: batch})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, &event{Delay: 300, Type: eventTypeAlarmFired, NodeId: 3, Data: []byte{}, batched: true}, events[0])
+	assert.Equal(t, uint16(6), events[1].DataLen)
+	assert.Equal(t, "state\n", string(events[1].Data))
+
+	events, err = unbatchEvent(uart)
+	assert.Nil(t, err)
+	assert.Equal(t, []*event{uart}, events)
+
+	_, err = parseEventBatch(batch[:len(batch)-1])
+	assert.NotNil(t, err)
+	_, err = parseEventBatch([]byte{eventTypeAlarmFired, 0x80})
+	assert.NotNil(t, err)
+}
+
+func TestFramingNegotiation(t *testing.T) {
+	udpln, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer udpln.Close()
+	peer, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer peer.Close()
+
+	d := newDispatcher(nil, DefaultConfig(), nil)
+	d.udpln = udpln
+	node := d.newNode(1, 0, 0, 100)
+	node.peerAddr = peer.LocalAddr().(*net.UDPAddr)
+	assert.Equal(t, FramingV1, node.GetFraming())
+
+	recv := func() *event {
+		buf := make([]byte, maxBatchLen)
+		_ = peer.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := peer.ReadFromUDP(buf)
+		assert.Nil(t, err)
+		evt := &event{}
+		assert.Nil(t, evt.Deserialize(buf[:n]))
+		return evt
+	}
+
+	d.handleStatusPush(1, "framing=3")
+	assert.Equal(t, &event{Delay: FramingV2, Type: eventTypeBatch, Data: []byte{}}, recv())
+	assert.Equal(t, FramingV2, node.GetFraming())
+
+	// a single event is not wrapped in a batch
+	d.CurTime = 100
+	d.advanceNodeTime(1, 100, false)
+	d.flushBatches()
+	assert.Equal(t, &event{Delay: 100, Type: eventTypeAlarmFired, Data: []byte{}}, recv())
+
+	node.Send(0, []byte{11, 1, 2})
+	d.SendToUART(1, []byte("state\n"))
+	assert.Equal(t, 1, len(d.batchNodes))
+	d.flushBatches()
+	evt := recv()
+	assert.Equal(t, uint8(eventTypeBatch), evt.Type)
+	events, err := parseEventBatch(evt.Data)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, []byte{11, 1, 2}, events[0].Data)
+	assert.Equal(t, uint8(eventTypeUartWrite), events[1].Type)
+	assert.Empty(t, d.batchNodes)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSerialize(t *testing.T) {
+	evt := &event{Delay: 0x0102030405060708, Type: eventTypeRadioReceived, Data: []byte{11, 1, 2, 3}}
+	msg := evt.Serialize()
+	assert.Equal(t, []byte{8, 7, 6, 5, 4, 3, 2, 1, 1, 4, 0, 11, 1, 2, 3}, msg)
+
+	var parsed event
+	assert.Nil(t, parsed.Deserialize(msg))
+	assert.Equal(t, event{Delay: evt.Delay, Type: evt.Type, DataLen: 4, Data: evt.Data}, parsed)
+
+	// the data is copied
+	msg[eventHeaderLen] = 12
+	assert.Equal(t, uint8(11), parsed.Data[0])
+
+	assert.NotNil(t, parsed.Deserialize(msg[:eventHeaderLen-1]))
+}
+
+func BenchmarkEventSerialize(b *testing.B) {
+	evt := &event{Delay: 1000, Type: eventTypeRadioReceived, Data: make([]byte, 128)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = evt.Serialize()
+	}
+}
+
+func BenchmarkEventDeserialize(b *testing.B) {
+	msg := (&event{Delay: 1000, Type: eventTypeRadioReceived, Data: make([]byte, 128)}).Serialize()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var evt event
+		_ = evt.Deserialize(msg)
+	}
+}
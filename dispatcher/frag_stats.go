@@ -0,0 +1,371 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	"github.com/openthread/ot-ns/dissectpkt/lowpan"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// fragReassemblyTimeout is the time (in us) after the last fragment of a datagram after which receptions that
+	// are not reassembled are counted as failed, like the reassembly timeout of OT nodes.
+	fragReassemblyTimeout = 2000000
+	maxFragHistoryCount   = 10000
+)
+
+// FragStats is the summary of the 6LoWPAN fragmented datagrams dissected from transmitted frames. Datagrams are
+// counted per hop: a datagram forwarded by a router is transmitted again with new fragments.
+type FragStats struct {
+	Datagrams   int           // fragmented datagrams transmitted
+	Fragments   uint64        // fragments transmitted, including retransmissions
+	Receptions  int           // finished datagram receptions by the MAC destination or, if broadcast, any node
+	Reassembled int           // receptions with all fragments received
+	Pending     int           // receptions within the reassembly timeout
+	Undecrypted uint64        // secured data frames which could not be decrypted to look for fragments
+	Latency     DurationStats // from the first fragment transmitted to the last fragment received
+	Nodes       []*NodeFragStats
+}
+
+// NodeFragStats is the summary of the fragmented datagrams transmitted by a node.
+type NodeFragStats struct {
+	Node        NodeId
+	Datagrams   int
+	Receptions  int
+	Reassembled int
+}
+
+// SuccessRatio returns the ratio of the finished receptions which were reassembled.
+func (s *FragStats) SuccessRatio() float64 {
+	if s.Receptions == 0 {
+		return 0
+	}
+	return float64(s.Reassembled) / float64(s.Receptions)
+}
+
+type fragKey struct {
+	src  NodeId
+	tag  uint16
+	size uint16
+}
+
+type fragRange struct {
+	start, end uint16
+}
+
+type fragReception struct {
+	first       bool        // the first fragment is received
+	ranges      []fragRange // sorted and merged ranges of the subsequent fragments received
+	reassembled uint64      // time of the reassembly, or 0 if not reassembled
+}
+
+func (rx *fragReception) addRange(r fragRange) {
+	ranges := append(rx.ranges, r)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.start <= last.end {
+			if r.end > last.end {
+				last.end = r.end
+			}
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	rx.ranges = merged
+}
+
+// complete returns if the datagram is reassembled. The first fragment covers the datagram up to the offset of the
+// second fragment, because the compressed headers in the first fragment do not reveal their uncompressed size.
+func (rx *fragReception) complete(frag1End uint16, size uint16) bool {
+	if !rx.first || frag1End == 0 {
+		return false
+	}
+	for _, r := range rx.ranges {
+		if r.start <= frag1End && r.end >= size {
+			return true
+		}
+	}
+	return false
+}
+
+// fragTrain is the sequence of fragments of a datagram transmitted by a node.
+type fragTrain struct {
+	key      fragKey
+	start    uint64
+	last     uint64
+	frag1End uint16 // offset of the second fragment, or 0 if not transmitted yet
+	unicast  bool
+	rx       map[NodeId]*fragReception
+}
+
+// fragFrame is a transmitted frame carrying a fragment.
+type fragFrame struct {
+	train *fragTrain
+	hdr   *lowpan.FragHeader
+}
+
+type fragTracker struct {
+	trains      map[fragKey]*fragTrain
+	nodes       map[NodeId]*NodeFragStats
+	latencies   []uint64
+	fragments   uint64
+	undecrypted uint64
+}
+
+func newFragTracker() *fragTracker {
+	return &fragTracker{
+		trains: map[fragKey]*fragTrain{},
+		nodes:  map[NodeId]*NodeFragStats{},
+	}
+}
+
+func (ft *fragTracker) node(id NodeId) *NodeFragStats {
+	stats := ft.nodes[id]
+	if stats == nil {
+		stats = &NodeFragStats{Node: id}
+		ft.nodes[id] = stats
+	}
+	return stats
+}
+
+// onTransmit tracks a fragment transmitted by the node to the receivers (nil if broadcast). It returns the fragment
+// train, or nil if the first fragment of the datagram was not seen.
+func (ft *fragTracker) onTransmit(now uint64, src NodeId, hdr *lowpan.FragHeader, receivers []NodeId,
+	unicast bool) *fragTrain {
+	ft.expire(now)
+	ft.fragments++
+
+	key := fragKey{src, hdr.DatagramTag, hdr.DatagramSize}
+	train := ft.trains[key]
+	if train != nil && hdr.First && train.frag1End > 0 {
+		// the datagram tag is reused by a new datagram
+		ft.finish(train)
+		train = nil
+	}
+
+	if train == nil {
+		if !hdr.First {
+			return nil
+		}
+
+		train = &fragTrain{
+			key:     key,
+			start:   now,
+			unicast: unicast,
+			rx:      map[NodeId]*fragReception{},
+		}
+		for _, id := range receivers {
+			train.rx[id] = &fragReception{}
+		}
+		ft.trains[key] = train
+		ft.node(src).Datagrams++
+	}
+
+	train.last = now
+	if !hdr.First && train.frag1End == 0 {
+		train.frag1End = hdr.Offset
+	}
+	return train
+}
+
+// onReceive tracks a fragment received by a node. It returns if the datagram is reassembled by the fragment.
+func (ft *fragTracker) onReceive(now uint64, ff *fragFrame, dst NodeId) bool {
+	train := ff.train
+	if ft.trains[train.key] != train {
+		return false
+	}
+
+	rx := train.rx[dst]
+	if rx == nil {
+		rx = &fragReception{}
+		train.rx[dst] = rx
+	}
+	if rx.reassembled > 0 {
+		return false
+	}
+
+	if ff.hdr.First {
+		rx.first = true
+	} else {
+		rx.addRange(fragRange{ff.hdr.Offset, ff.hdr.Offset + uint16(ff.hdr.Length)})
+	}
+
+	if !rx.complete(train.frag1End, train.key.size) {
+		return false
+	}
+
+	rx.reassembled = now
+	if train.unicast && ft.allReassembled(train) {
+		ft.finish(train)
+	}
+	return true
+}
+
+func (ft *fragTracker) allReassembled(train *fragTrain) bool {
+	for _, rx := range train.rx {
+		if rx.reassembled == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ft *fragTracker) expire(now uint64) {
+	for _, train := range ft.trains {
+		if now > train.last+fragReassemblyTimeout {
+			ft.finish(train)
+		}
+	}
+}
+
+func (ft *fragTracker) finish(train *fragTrain) {
+	delete(ft.trains, train.key)
+
+	stats := ft.node(train.key.src)
+	for _, rx := range train.rx {
+		stats.Receptions++
+		if rx.reassembled > 0 {
+			stats.Reassembled++
+			ft.latencies = append(ft.latencies, rx.reassembled-train.start)
+			if len(ft.latencies) > maxFragHistoryCount {
+				ft.latencies = ft.latencies[1:]
+			}
+		}
+	}
+}
+
+func (ft *fragTracker) stats(now uint64) FragStats {
+	ft.expire(now)
+
+	stats := FragStats{
+		Fragments:   ft.fragments,
+		Undecrypted: ft.undecrypted,
+		Latency:     newDurationStats(append([]uint64(nil), ft.latencies...)),
+		Nodes:       make([]*NodeFragStats, 0, len(ft.nodes)),
+	}
+	for _, train := range ft.trains {
+		stats.Pending += len(train.rx)
+	}
+	for _, ns := range ft.nodes {
+		nodeStats := *ns
+		stats.Nodes = append(stats.Nodes, &nodeStats)
+		stats.Datagrams += ns.Datagrams
+		stats.Receptions += ns.Receptions
+		stats.Reassembled += ns.Reassembled
+	}
+	sort.Slice(stats.Nodes, func(i, j int) bool {
+		return stats.Nodes[i].Node < stats.Nodes[j].Node
+	})
+	return stats
+}
+
+// dissectFrag returns the fragment carried by a data frame transmitted by the node, or nil if the frame does not
+// carry a fragment.
+func (d *Dispatcher) dissectFrag(srcnode *Node, sit *sendItem, frame *wpan.MacFrame) *fragFrame {
+	hdr, err := wpan.DissectHeader(sit.Data)
+	if err != nil {
+		return nil
+	}
+
+	extAddr := srcnode.ExtAddr
+	if hdr.SrcAddrExtended != 0 {
+		extAddr = hdr.SrcAddrExtended
+	}
+	payload := hdr.Payload(sit.Data[1:])
+	if extAddr == InvalidExtAddr {
+		d.frags.undecrypted++
+		return nil
+	}
+	payload, ok := d.frameDecryptor.decrypt(hdr, extAddr, payload)
+	if !ok {
+		d.frags.undecrypted++
+		return nil
+	}
+
+	fh := lowpan.DissectFragHeader(payload)
+	if fh == nil {
+		return nil
+	}
+
+	d.Counters.FragmentFrames++
+	receivers, unicast := d.unicastReceivers(srcnode, frame)
+	train := d.frags.onTransmit(sit.Timestamp, srcnode.Id, fh, receivers, unicast)
+	if train == nil {
+		return nil
+	}
+	return &fragFrame{train: train, hdr: fh}
+}
+
+// unicastReceivers returns the nodes with the destination address of a unicast frame. It returns false if the frame
+// is broadcast or has no destination address.
+func (d *Dispatcher) unicastReceivers(srcnode *Node, frame *wpan.MacFrame) ([]NodeId, bool) {
+	var receivers []NodeId
+	switch frame.FrameControl.DstAddrMode() {
+	case wpan.DstAddrModeExtended:
+		if dstnode := d.extaddrMap[frame.DstAddrExtended]; dstnode != nil && dstnode != srcnode {
+			receivers = append(receivers, dstnode.Id)
+		}
+		return receivers, true
+	case wpan.DstAddrModeShort:
+		if frame.DstAddrShort == threadconst.BroadcastRloc16 {
+			return nil, false
+		}
+		for _, dstnode := range d.rloc16Map[frame.DstAddrShort] {
+			if dstnode != srcnode {
+				receivers = append(receivers, dstnode.Id)
+			}
+		}
+		return receivers, true
+	default:
+		return nil, false
+	}
+}
+
+func (d *Dispatcher) onFragReceived(sit *sendItem, dstnode *Node) {
+	if d.frags.onReceive(sit.Timestamp, sit.frag, dstnode.Id) {
+		d.Counters.ReassembledDatagrams++
+	}
+}
+
+// GetFragStats returns the summary of the fragmented datagrams transmitted by all nodes.
+func (d *Dispatcher) GetFragStats() FragStats {
+	return d.frags.stats(d.CurTime)
+}
+
+// ResetFragStats discards the fragmented datagrams summarized by GetFragStats.
+func (d *Dispatcher) ResetFragStats() {
+	d.frags = newFragTracker()
+}
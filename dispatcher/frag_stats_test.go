@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/openthread/ot-ns/dissectpkt"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNetworkKey, _ = hex.DecodeString("00112233445566778899aabbccddeeff")
+
+func TestThreadMacKey(t *testing.T) {
+	// the MAC key of key sequence 0 in the test vectors of the Thread specification
+	assert.Equal(t, "de89c53af382b421e0fde5a9bae3bef0", hex.EncodeToString(threadMacKey(testNetworkKey, 0)))
+}
+
+// secureFrame returns a data frame from the extended address to the short address with the payload encrypted by
+// the MAC key of key sequence 0.
+func secureFrame(t *testing.T, fd *frameDecryptor, extAddr uint64, dstShort uint16, counter uint32,
+	payload []byte) []byte {
+	data := []byte{11, 0x69, 0xc8, byte(counter), 0xce, 0xfa, byte(dstShort), byte(dstShort >> 8)}
+	data = append(data, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(data[8:], extAddr)
+	data = append(data, 0x0d, byte(counter), byte(counter>>8), byte(counter>>16), byte(counter>>24), 0x01)
+	data = append(data, payload...)
+	data = append(data, make([]byte, 4+wpan.FcsLength)...)
+
+	hdr, err := wpan.DissectHeader(data)
+	assert.Nil(t, err)
+	assert.Equal(t, 21, hdr.Length)
+	assert.Equal(t, 4, hdr.MicLength)
+	assert.Equal(t, extAddr, hdr.SrcAddrExtended)
+	encrypted, ok := fd.decrypt(hdr, extAddr, payload)
+	assert.True(t, ok)
+	copy(hdr.Payload(data[1:]), encrypted)
+	return data
+}
+
+func frag1(size uint16, tag uint16, length int) []byte {
+	return append([]byte{0xc0 | byte(size>>8), byte(size), byte(tag >> 8), byte(tag)}, make([]byte, length)...)
+}
+
+func fragN(size uint16, tag uint16, offset uint16, length int) []byte {
+	return append([]byte{0xe0 | byte(size>>8), byte(size), byte(tag >> 8), byte(tag), byte(offset / 8)},
+		make([]byte, length)...)
+}
+
+func TestFragStats(t *testing.T) {
+	d := &Dispatcher{
+		extaddrMap:     map[uint64]*Node{},
+		rloc16Map:      rloc16Map{},
+		frags:          newFragTracker(),
+		frameDecryptor: newFrameDecryptor(testNetworkKey),
+	}
+	src := &Node{D: d, Id: 1, ExtAddr: 0x1122334455667788}
+	dst := &Node{D: d, Id: 2}
+	d.rloc16Map.Add(0x0400, dst)
+
+	counter := uint32(0)
+	transmit := func(timestamp uint64, payload []byte, delivered bool) {
+		counter++
+		sit := &sendItem{Timestamp: timestamp, NodeId: src.Id, Data: secureFrame(t, d.frameDecryptor, src.ExtAddr,
+			0x0400, counter, payload)}
+		sit.frag = d.dissectFrag(src, sit, dissectpkt.Dissect(sit.Data).MacFrame)
+		assert.NotNil(t, sit.frag)
+		if delivered {
+			d.onFragReceived(sit, dst)
+		}
+	}
+
+	// all fragments are received, the second one after a retransmission
+	transmit(1000, frag1(200, 1, 70), true)
+	transmit(6000, fragN(200, 1, 96, 64), false)
+	transmit(9000, fragN(200, 1, 96, 64), true)
+	transmit(14000, fragN(200, 1, 160, 40), true)
+
+	// the last fragment is lost
+	transmit(100000, frag1(200, 2, 70), true)
+	transmit(105000, fragN(200, 2, 96, 64), true)
+	transmit(110000, fragN(200, 2, 160, 40), false)
+
+	d.CurTime = 200000
+	stats := d.GetFragStats()
+	assert.Equal(t, 2, stats.Datagrams)
+	assert.Equal(t, uint64(7), stats.Fragments)
+	assert.Equal(t, 1, stats.Receptions)
+	assert.Equal(t, 1, stats.Reassembled)
+	assert.Equal(t, 1, stats.Pending)
+	assert.Equal(t, uint64(13000), stats.Latency.Max)
+	assert.Equal(t, uint64(1), d.Counters.ReassembledDatagrams)
+
+	d.CurTime = 110000 + fragReassemblyTimeout + 1
+	stats = d.GetFragStats()
+	assert.Equal(t, 2, stats.Receptions)
+	assert.Equal(t, 1, stats.Reassembled)
+	assert.Equal(t, 0, stats.Pending)
+	assert.Equal(t, 0.5, stats.SuccessRatio())
+	assert.Equal(t, []*NodeFragStats{{Node: 1, Datagrams: 2, Receptions: 2, Reassembled: 1}}, stats.Nodes)
+
+	// frames of an unknown network key are not decryptable
+	d.frameDecryptor = newFrameDecryptor(make([]byte, 16))
+	sit := &sendItem{Timestamp: d.CurTime, NodeId: src.Id, Data: secureFrame(t, newFrameDecryptor(testNetworkKey),
+		src.ExtAddr, 0x0400, 100, frag1(200, 3, 70))}
+	assert.Nil(t, d.dissectFrag(src, sit, dissectpkt.Dissect(sit.Data).MacFrame))
+
+	d.ResetFragStats()
+	assert.Equal(t, 0, d.GetFragStats().Datagrams)
+}
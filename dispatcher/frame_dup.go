@@ -0,0 +1,169 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultFrameDupDelay is the default delay (in us) of duplicated frames after the original frames.
+	DefaultFrameDupDelay = 1000
+)
+
+// FrameDup configures the duplication of frames: a fraction Ratio of the frames delivered is delivered a second time
+// Delay us later.
+type FrameDup struct {
+	Ratio float64
+	Delay uint64
+}
+
+// LinkFrameDup is the frame duplication of the frames sent from a node to another, with the number of duplicates
+// injected on the link.
+type LinkFrameDup struct {
+	FrameDup
+	Src        NodeId
+	Dst        NodeId
+	Duplicates uint64
+}
+
+func (dup FrameDup) validate() error {
+	if dup.Ratio < 0 || dup.Ratio > 1 {
+		return errors.Errorf("invalid duplication ratio: %v", dup.Ratio)
+	}
+	return nil
+}
+
+// SetFrameDup sets the duplication of the frames of all links without a link frame duplication.
+func (d *Dispatcher) SetFrameDup(dup FrameDup) error {
+	if err := dup.validate(); err != nil {
+		return err
+	}
+	d.frameDup = dup
+	return nil
+}
+
+// GetFrameDup returns the duplication of the frames of all links without a link frame duplication.
+func (d *Dispatcher) GetFrameDup() FrameDup {
+	return d.frameDup
+}
+
+// SetLinkFrameDup sets the duplication of the frames sent from the source node to the destination node, which
+// overrides the global frame duplication.
+func (d *Dispatcher) SetLinkFrameDup(src, dst NodeId, dup FrameDup) error {
+	if src == dst {
+		return errors.Errorf("invalid link %d-%d", src, dst)
+	}
+	if d.nodes[src] == nil {
+		return errors.Errorf("node %d not found", src)
+	}
+	if d.nodes[dst] == nil {
+		return errors.Errorf("node %d not found", dst)
+	}
+	if err := dup.validate(); err != nil {
+		return err
+	}
+
+	if d.linkFrameDups == nil {
+		d.linkFrameDups = map[linkKey]*LinkFrameDup{}
+	}
+	key := linkKey{src, dst}
+	if ld := d.linkFrameDups[key]; ld != nil {
+		ld.FrameDup = dup
+	} else {
+		d.linkFrameDups[key] = &LinkFrameDup{FrameDup: dup, Src: src, Dst: dst}
+	}
+	return nil
+}
+
+// ClearLinkFrameDup removes the frame duplication of the link, which then uses the global frame duplication.
+func (d *Dispatcher) ClearLinkFrameDup(src, dst NodeId) {
+	delete(d.linkFrameDups, linkKey{src, dst})
+}
+
+// GetLinkFrameDups returns the frame duplications of all links, sorted by source and destination node.
+func (d *Dispatcher) GetLinkFrameDups() []LinkFrameDup {
+	dups := make([]LinkFrameDup, 0, len(d.linkFrameDups))
+	for _, ld := range d.linkFrameDups {
+		dups = append(dups, *ld)
+	}
+	sort.Slice(dups, func(i, j int) bool {
+		if dups[i].Src != dups[j].Src {
+			return dups[i].Src < dups[j].Src
+		}
+		return dups[i].Dst < dups[j].Dst
+	})
+	return dups
+}
+
+func (d *Dispatcher) deleteLinkFrameDups(id NodeId) {
+	for key := range d.linkFrameDups {
+		if key.src == id || key.dst == id {
+			delete(d.linkFrameDups, key)
+		}
+	}
+}
+
+// duplicateFrame delivers the frame delivered to the destination node a second time after the delay of the link
+// frame duplication, or of the global frame duplication.
+func (d *Dispatcher) duplicateFrame(sit *sendItem, srcnode *Node, dstnode *Node) {
+	dup := d.frameDup
+	ld := d.linkFrameDups[linkKey{srcnode.Id, dstnode.Id}]
+	if ld != nil {
+		dup = ld.FrameDup
+	}
+	if dup.Ratio <= 0 || rand.Float64() >= dup.Ratio {
+		return
+	}
+
+	d.Counters.DuplicatedFrames += 1
+	if ld != nil {
+		ld.Duplicates += 1
+	}
+
+	delay := dup.Delay
+	if delay == 0 {
+		delay = 1
+	}
+	dupsit := &sendItem{NodeId: sit.NodeId, Radio: sit.Radio, Data: sit.Data, injected: true}
+	srcid, dstid := srcnode.Id, dstnode.Id
+	d.ScheduleAt(d.CurTime+delay, func() {
+		srcnode, dstnode := d.nodes[srcid], d.nodes[dstid]
+		if srcnode == nil || dstnode == nil {
+			// the node was deleted
+			return
+		}
+		dupsit.Timestamp = d.CurTime
+		d.radioWatchf(dstid, RadioWatchInfo, "RX duplicate from node %d", srcid)
+		d.sendOneMessage(dupsit, srcnode, dstnode, nil)
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameDup(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.CurTime = 1000000
+	src, dst := d.nodes[1], d.nodes[2]
+	send := func() {
+		d.sendOneMessage(&sendItem{Timestamp: d.CurTime, NodeId: 1, Data: []byte{11, 0x41, 0xd8}}, src, dst, nil)
+	}
+
+	assert.NotNil(t, d.SetFrameDup(FrameDup{Ratio: 1.5}))
+	assert.NotNil(t, d.SetLinkFrameDup(1, 1, FrameDup{Ratio: 1}))
+	assert.NotNil(t, d.SetLinkFrameDup(1, 3, FrameDup{Ratio: 1}))
+
+	// no duplicates by default
+	send()
+	assert.Equal(t, 0, d.timers.Len())
+
+	// all frames are duplicated after the delay, and duplicates are not duplicated again
+	assert.Nil(t, d.SetFrameDup(FrameDup{Ratio: 1, Delay: 500}))
+	send()
+	assert.Equal(t, uint64(1), d.Counters.DuplicatedFrames)
+	assert.Equal(t, uint64(1000500), d.timers.NextTimestamp())
+	d.CurTime = 1000500
+	d.handleTimers()
+	assert.Equal(t, uint64(1000500), dst.CurTime)
+	assert.Equal(t, 0, d.timers.Len())
+	assert.Equal(t, uint64(1), d.Counters.DuplicatedFrames)
+
+	// the link frame duplication overrides the global frame duplication
+	assert.Nil(t, d.SetLinkFrameDup(1, 2, FrameDup{Ratio: 0}))
+	send()
+	assert.Equal(t, 0, d.timers.Len())
+	assert.Nil(t, d.SetLinkFrameDup(1, 2, FrameDup{Ratio: 1, Delay: 100}))
+	send()
+	assert.Equal(t, uint64(1000600), d.timers.NextTimestamp())
+	assert.Equal(t, []LinkFrameDup{{FrameDup: FrameDup{Ratio: 1, Delay: 100}, Src: 1, Dst: 2, Duplicates: 1}},
+		d.GetLinkFrameDups())
+	assert.Equal(t, uint64(2), d.Counters.DuplicatedFrames)
+
+	// the duplicate is not delivered to a deleted node, and the link frame duplication is deleted
+	delete(d.nodes, 2)
+	d.deleteLinkFrameDups(2)
+	d.CurTime = 1000600
+	d.handleTimers()
+	assert.Equal(t, uint64(1000500), dst.CurTime)
+	assert.Empty(t, d.GetLinkFrameDups())
+
+	d.ClearLinkFrameDup(1, 2)
+	assert.Equal(t, FrameDup{Ratio: 1, Delay: 500}, d.GetFrameDup())
+}
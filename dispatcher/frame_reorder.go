@@ -0,0 +1,122 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"math/rand"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultFrameReorderMaxHold is the default maximum time (in us) a frame is held back for reordering.
+	DefaultFrameReorderMaxHold = 50000
+)
+
+// FrameReorder configures the reordering of frames: a fraction Ratio of the frames delivered on a link is held back
+// until the next frame of the link is delivered, and delivered right after it. A held frame is delivered late,
+// without reordering, if no other frame is delivered on the link within MaxHold us.
+type FrameReorder struct {
+	Ratio   float64
+	MaxHold uint64
+}
+
+type heldFrame struct {
+	sit *sendItem
+}
+
+// SetFrameReorder sets the reordering of the frames of all links.
+func (d *Dispatcher) SetFrameReorder(reorder FrameReorder) error {
+	if reorder.Ratio < 0 || reorder.Ratio > 1 {
+		return errors.Errorf("invalid reordering ratio: %v", reorder.Ratio)
+	}
+	if reorder.MaxHold == 0 {
+		return errors.Errorf("invalid max hold time: %v", reorder.MaxHold)
+	}
+	d.frameReorder = reorder
+	return nil
+}
+
+// GetFrameReorder returns the reordering of the frames of all links.
+func (d *Dispatcher) GetFrameReorder() FrameReorder {
+	return d.frameReorder
+}
+
+func (d *Dispatcher) deleteHeldFrames(id NodeId) {
+	for key := range d.heldFrames {
+		if key.src == id || key.dst == id {
+			delete(d.heldFrames, key)
+		}
+	}
+}
+
+// holdFrame holds back the frame delivered to the destination node for reordering, and returns if it was held.
+// At most one frame is held per link.
+func (d *Dispatcher) holdFrame(sit *sendItem, srcnode *Node, dstnode *Node) bool {
+	if d.frameReorder.Ratio <= 0 {
+		return false
+	}
+	key := linkKey{srcnode.Id, dstnode.Id}
+	if d.heldFrames[key] != nil || rand.Float64() >= d.frameReorder.Ratio {
+		return false
+	}
+
+	if d.heldFrames == nil {
+		d.heldFrames = map[linkKey]*heldFrame{}
+	}
+	held := &heldFrame{sit: &sendItem{NodeId: sit.NodeId, Radio: sit.Radio, Data: sit.Data, frag: sit.frag,
+		linkTracked: sit.linkTracked, injected: true}}
+	d.heldFrames[key] = held
+	d.ScheduleAt(d.CurTime+d.frameReorder.MaxHold, func() {
+		if d.heldFrames[key] != held {
+			// the frame was released, or a node was deleted
+			return
+		}
+		d.Counters.ReorderHoldTimeouts += 1
+		d.releaseHeldFrame(key)
+	})
+	return true
+}
+
+// releaseHeldFrame delivers the frame held back on the link, if any.
+func (d *Dispatcher) releaseHeldFrame(key linkKey) {
+	held := d.heldFrames[key]
+	if held == nil {
+		return
+	}
+	delete(d.heldFrames, key)
+
+	srcnode, dstnode := d.nodes[key.src], d.nodes[key.dst]
+	if srcnode == nil || dstnode == nil {
+		return
+	}
+	held.sit.Timestamp = d.CurTime
+	d.radioWatchf(key.dst, RadioWatchInfo, "RX held frame from node %d", key.src)
+	d.sendOneMessage(held.sit, srcnode, dstnode, nil)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameReorder(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.CurTime = 1000000
+	src, dst := d.nodes[1], d.nodes[2]
+	send := func() {
+		d.sendOneMessage(&sendItem{Timestamp: d.CurTime, NodeId: 1, Data: []byte{11, 0x41, 0xd8}}, src, dst, nil)
+	}
+
+	assert.NotNil(t, d.SetFrameReorder(FrameReorder{Ratio: 2, MaxHold: 1000}))
+	assert.NotNil(t, d.SetFrameReorder(FrameReorder{Ratio: 1}))
+
+	// the first frame is held, and delivered right after the next frame
+	assert.Nil(t, d.SetFrameReorder(FrameReorder{Ratio: 1, MaxHold: 1000}))
+	send()
+	assert.Equal(t, uint64(0), dst.CurTime)
+	assert.Equal(t, 1, len(d.heldFrames))
+
+	d.CurTime = 1000200
+	send()
+	assert.Equal(t, uint64(1000200), dst.CurTime)
+	assert.Equal(t, uint64(1), d.Counters.ReorderedFrames)
+	assert.Empty(t, d.heldFrames)
+
+	// the hold timer of the released frame does nothing
+	d.CurTime = 1001000
+	d.handleTimers()
+	assert.Equal(t, uint64(0), d.Counters.ReorderHoldTimeouts)
+
+	// a frame without a next frame is delivered late
+	send()
+	assert.Equal(t, 1, len(d.heldFrames))
+	d.CurTime = 1002000
+	d.handleTimers()
+	assert.Equal(t, uint64(1002000), dst.CurTime)
+	assert.Equal(t, uint64(1), d.Counters.ReorderHoldTimeouts)
+	assert.Empty(t, d.heldFrames)
+
+	// held frames of deleted nodes are discarded
+	send()
+	d.deleteHeldFrames(2)
+	assert.Empty(t, d.heldFrames)
+	assert.Equal(t, FrameReorder{Ratio: 1, MaxHold: 1000}, d.GetFrameReorder())
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+)
+
+const (
+	securityLevelEncrypted = 0x04
+	macKeyHashString       = "Thread"
+)
+
+// frameDecryptor decrypts the payload of MAC frames secured with the MAC key of the Thread network key (Key ID
+// mode 1). The key sequence is derived from the key index, so that the first 128 key sequences are supported.
+type frameDecryptor struct {
+	networkKey []byte
+	ciphers    map[uint8]cipher.Block
+}
+
+func newFrameDecryptor(networkKey []byte) *frameDecryptor {
+	if len(networkKey) != 16 {
+		return nil
+	}
+
+	return &frameDecryptor{
+		networkKey: networkKey,
+		ciphers:    map[uint8]cipher.Block{},
+	}
+}
+
+// threadMacKey returns the MAC key of the key sequence: the second half of HMAC-SHA256 of the key sequence and
+// "Thread" keyed with the network key.
+func threadMacKey(networkKey []byte, keySequence uint32) []byte {
+	mac := hmac.New(sha256.New, networkKey)
+	var seq [4]byte
+	binary.BigEndian.PutUint32(seq[:], keySequence)
+	mac.Write(seq[:])
+	mac.Write([]byte(macKeyHashString))
+	return mac.Sum(nil)[16:]
+}
+
+func (fd *frameDecryptor) cipher(keyIndex uint8) cipher.Block {
+	block := fd.ciphers[keyIndex]
+	if block == nil {
+		block, _ = aes.NewCipher(threadMacKey(fd.networkKey, uint32(keyIndex-1)))
+		fd.ciphers[keyIndex] = block
+	}
+	return block
+}
+
+// decrypt returns the plain payload of the frame sent by the node with the extended address. The MIC is not
+// verified. It returns false if the frame is not decryptable.
+func (fd *frameDecryptor) decrypt(hdr *wpan.FrameHeader, extAddr uint64, payload []byte) ([]byte, bool) {
+	if hdr.SecurityLevel&securityLevelEncrypted == 0 {
+		return payload, true
+	}
+	if fd == nil || hdr.KeyIdMode != wpan.KeyIdMode1 || hdr.KeyIndex == 0 {
+		return nil, false
+	}
+
+	// CCM* counter blocks: flags (L=2), nonce (extended address, frame counter, security level), block counter
+	iv := make([]byte, aes.BlockSize)
+	iv[0] = 0x01
+	binary.BigEndian.PutUint64(iv[1:], extAddr)
+	binary.BigEndian.PutUint32(iv[9:], hdr.FrameCounter)
+	iv[13] = hdr.SecurityLevel
+	iv[15] = 1
+
+	plain := make([]byte, len(payload))
+	cipher.NewCTR(fd.cipher(hdr.KeyIndex), iv).XORKeyStream(plain, payload)
+	return plain, true
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// JamFilter selects the on-air frames that trigger a reactive jammer.
+type JamFilter struct {
+	MatchDstShort  bool
+	DstShort       uint16
+	MatchFrameType bool
+	FrameType      wpan.FrameType
+}
+
+func (f *JamFilter) Match(frame *wpan.MacFrame) bool {
+	if f.MatchFrameType && frame.FrameControl.FrameType() != f.FrameType {
+		return false
+	}
+
+	if f.MatchDstShort {
+		if frame.FrameControl.DstAddrMode() != wpan.DstAddrModeShort || frame.DstAddrShort != f.DstShort {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *JamFilter) String() string {
+	var conds []string
+	if f.MatchDstShort {
+		conds = append(conds, fmt.Sprintf("dst=%04x", f.DstShort))
+	}
+	if f.MatchFrameType {
+		conds = append(conds, fmt.Sprintf("type=%s", JamFrameTypeName(f.FrameType)))
+	}
+	if len(conds) == 0 {
+		return "any"
+	}
+	return strings.Join(conds, ",")
+}
+
+var jamFrameTypeNames = map[wpan.FrameType]string{
+	wpan.FrameTypeBeacon:  "beacon",
+	wpan.FrameTypeData:    "data",
+	wpan.FrameTypeAck:     "ack",
+	wpan.FrameTypeCommand: "cmd",
+}
+
+func JamFrameTypeName(ft wpan.FrameType) string {
+	if name, ok := jamFrameTypeNames[ft]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", ft)
+}
+
+func ParseJamFrameType(name string) (wpan.FrameType, bool) {
+	for ft, n := range jamFrameTypeNames {
+		if n == name {
+			return ft, true
+		}
+	}
+	return 0, false
+}
+
+// findJammers returns the reactive jammers that detect the frame sent by srcnode.
+// A jammer detects a frame when it is reachable by the sender on the channel of the frame.
+func (d *Dispatcher) findJammers(srcnode *Node, frame *wpan.MacFrame) []*Node {
+	var jammers []*Node
+	for _, jammer := range d.jammers {
+		if jammer == srcnode || jammer.isFailed || jammer.isPaused {
+			continue
+		}
+
+		if !d.checkRadioReachable(srcnode, jammer, frame.Channel) || !jammer.jamFilter.Match(frame) {
+			continue
+		}
+
+		jammers = append(jammers, jammer)
+	}
+
+	if len(jammers) > 0 {
+		d.Counters.JamTriggers++
+	}
+	return jammers
+}
+
+// isJammed returns if the frame reception of dstnode on the channel is corrupted by any of the triggered jammers.
+func (d *Dispatcher) isJammed(jammers []*Node, dstnode *Node, channel uint8) bool {
+	for _, jammer := range jammers {
+		if d.checkRadioReachable(jammer, dstnode, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNodeJammer makes the node a reactive jammer using the filter, or stops jamming if filter is nil.
+func (d *Dispatcher) SetNodeJammer(id NodeId, filter *JamFilter) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+
+	node.jamFilter = filter
+	if filter != nil {
+		d.jammers[id] = node
+	} else {
+		delete(d.jammers, id)
+	}
+}
+
+// Jammers returns all nodes that are currently configured as reactive jammers.
+func (d *Dispatcher) Jammers() map[NodeId]*Node {
+	return d.jammers
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJamFilter_Match(t *testing.T) {
+	dataToRouter := &wpan.MacFrame{
+		FrameControl: wpan.FrameControl(wpan.FrameTypeData | wpan.DstAddrModeShort<<10),
+		DstAddrShort: 0x5800,
+	}
+	ack := &wpan.MacFrame{
+		FrameControl: wpan.FrameControl(wpan.FrameTypeAck),
+	}
+
+	anyFrame := &JamFilter{}
+	assert.True(t, anyFrame.Match(dataToRouter))
+	assert.True(t, anyFrame.Match(ack))
+
+	byDst := &JamFilter{MatchDstShort: true, DstShort: 0x5800}
+	assert.True(t, byDst.Match(dataToRouter))
+	assert.False(t, byDst.Match(ack))
+
+	byType := &JamFilter{MatchFrameType: true, FrameType: wpan.FrameTypeAck}
+	assert.False(t, byType.Match(dataToRouter))
+	assert.True(t, byType.Match(ack))
+
+	byBoth := &JamFilter{MatchDstShort: true, DstShort: 0x5801, MatchFrameType: true, FrameType: wpan.FrameTypeData}
+	assert.False(t, byBoth.Match(dataToRouter))
+}
+
+func TestJammerReach(t *testing.T) {
+	d := &Dispatcher{
+		vis:        visualize.NewNopVisualizer(),
+		cbHandler:  nopCallbackHandler{},
+		radioModel: DefaultRadioModelParams(),
+		jammers:    map[NodeId]*Node{},
+	}
+	d.nodes = map[NodeId]*Node{
+		1: newNode(d, 1, 0, 0, 100),
+		2: newNode(d, 2, 50, 0, 100),
+		3: newNode(d, 3, 120, 0, 100),
+	}
+	src, jammer, dst := d.nodes[1], d.nodes[2], d.nodes[3]
+	d.SetNodeJammer(2, &JamFilter{})
+
+	jammers := d.findJammers(src, &wpan.MacFrame{Channel: 11})
+	assert.Equal(t, []*Node{jammer}, jammers)
+	assert.True(t, d.isJammed(jammers, dst, 11))
+	assert.False(t, d.isJammed(jammers, jammer, 11))
+
+	// the noise floor of the channel shrinks the reach of the jammer
+	d.radioModel.ChannelNoiseFloorDbm[15] = -75
+	assert.Empty(t, d.findJammers(src, &wpan.MacFrame{Channel: 15}))
+	assert.False(t, d.isJammed(jammers, dst, 15))
+
+	// a paused jammer does not jam
+	jammer.isPaused = true
+	assert.Empty(t, d.findJammers(src, &wpan.MacFrame{Channel: 11}))
+	assert.Equal(t, uint64(1), d.Counters.JamTriggers)
+}
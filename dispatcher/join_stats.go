@@ -0,0 +1,95 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+const (
+	maxJoinHistoryCount = 10000
+)
+
+// JoinStats is the summary of joiner sessions. Sessions which did not reach a phase are not counted for the phase.
+type JoinStats struct {
+	Sessions    int
+	Joined      int
+	Discover    DurationStats
+	Dtls        DurationStats
+	NetworkData DurationStats
+	Attach      DurationStats
+	Join        DurationStats
+}
+
+// joinHistory keeps the results of recent joiner sessions of all nodes, including those already collected.
+type joinHistory struct {
+	results []*JoinResult
+}
+
+func (jh *joinHistory) add(result *JoinResult) {
+	jh.results = append(jh.results, result)
+	if len(jh.results) > maxJoinHistoryCount {
+		jh.results = jh.results[1:]
+	}
+}
+
+func (jh *joinHistory) stats() JoinStats {
+	stats := JoinStats{Sessions: len(jh.results)}
+	var discover, dtls, networkData, attach, join []uint64
+
+	for _, result := range jh.results {
+		if result.JoinDuration > 0 {
+			stats.Joined++
+			join = append(join, result.JoinDuration)
+		}
+		if result.DiscoverDuration > 0 {
+			discover = append(discover, result.DiscoverDuration)
+		}
+		if result.DtlsDuration > 0 {
+			dtls = append(dtls, result.DtlsDuration)
+		}
+		if result.NetworkDataDuration > 0 {
+			networkData = append(networkData, result.NetworkDataDuration)
+		}
+		if result.AttachDuration > 0 {
+			attach = append(attach, result.AttachDuration)
+		}
+	}
+
+	stats.Discover = newDurationStats(discover)
+	stats.Dtls = newDurationStats(dtls)
+	stats.NetworkData = newDurationStats(networkData)
+	stats.Attach = newDurationStats(attach)
+	stats.Join = newDurationStats(join)
+	return stats
+}
+
+// GetJoinStats returns the summary of the recent joiner sessions of all nodes.
+func (d *Dispatcher) GetJoinStats() JoinStats {
+	return d.joinHistory.stats()
+}
+
+// ResetJoinStats discards the joiner sessions summarized by GetJoinStats.
+func (d *Dispatcher) ResetJoinStats() {
+	d.joinHistory = joinHistory{}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinStatsBreakdown(t *testing.T) {
+	d := &Dispatcher{}
+	node := &Node{D: d, joinerState: OtJoinerStateIdle}
+
+	node.CurTime = 1000000
+	node.onJoinerState(OtJoinerStateDiscover)
+	node.CurTime = 1300000
+	node.onJoinerState(OtJoinerStateConnect)
+	node.CurTime = 2500000
+	node.onJoinerState(OtJoinerStateConnected)
+	node.CurTime = 2550000
+	node.onJoinerState(OtJoinerStateEntrust)
+	node.CurTime = 2600000
+	node.onJoinerState(OtJoinerStateJoined)
+	node.CurTime = 2700000
+	node.onJoinerState(OtJoinerStateIdle)
+	node.CurTime = 4600000
+	node.onAttached()
+
+	// a failed session is only counted for discover
+	node.CurTime = 10000000
+	node.onJoinerState(OtJoinerStateDiscover)
+	node.CurTime = 10500000
+	node.onJoinerState(OtJoinerStateConnect)
+	node.CurTime = 12000000
+	node.onJoinerState(OtJoinerStateIdle)
+
+	stats := d.GetJoinStats()
+	assert.Equal(t, 2, stats.Sessions)
+	assert.Equal(t, 1, stats.Joined)
+	assert.Equal(t, DurationStats{Count: 2, P50: 300000, P90: 500000, P99: 500000, Max: 500000}, stats.Discover)
+	assert.Equal(t, uint64(1200000), stats.Dtls.Max)
+	assert.Equal(t, uint64(100000), stats.NetworkData.Max)
+	assert.Equal(t, uint64(2000000), stats.Attach.Max)
+	assert.Equal(t, uint64(1600000), stats.Join.Max)
+	assert.Equal(t, 1, stats.Join.Count)
+
+	d.ResetJoinStats()
+	assert.Equal(t, JoinStats{}, d.GetJoinStats())
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// Kpi contains the key performance indicators of the simulation between KPI start and stop.
+type Kpi struct {
+	StartTime uint64               `json:"start_us"`
+	StopTime  uint64               `json:"stop_us"`
+	Running   bool                 `json:"running"`
+	Counters  map[string]uint64    `json:"counters"` // increments of the dispatcher counters
+	Airtime   *AirtimeReport       `json:"airtime"`
+	Mac       map[NodeId]*MacStats `json:"mac"` // MAC retries, CCA failures and frame drops per node
+
+	// RSS and CPU time of node processes, if sampled
+	Resources map[NodeId]*ResourceUsage `json:"resources,omitempty"`
+	// Link Metrics aggregates of each probed node pair, if probed
+	LinkMetrics []*LinkMetricsStats `json:"link_metrics,omitempty"`
+	// MAC retransmission distribution and backoff statistics of the unicast frames of each node
+	MacTx map[NodeId]*MacTxStats `json:"mac_tx,omitempty"`
+}
+
+// WriteFile writes the KPI to the file in JSON format.
+func (kpi *Kpi) WriteFile(filename string) error {
+	data, err := json.MarshalIndent(kpi, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+type kpiCollector struct {
+	running       bool
+	startTime     uint64
+	stopTime      uint64
+	startCounters map[string]uint64
+	stopCounters  map[string]uint64
+	airtime       *airtimeMeter
+	mac           map[NodeId]*MacStats
+	resources     map[NodeId]*ResourceUsage
+	linkMetrics   map[linkMetricsKey]*LinkMetricsStats
+	macTx         macTxTable
+}
+
+func (kc *kpiCollector) OnTransmit(id NodeId, psduLen int) {
+	if kc.running {
+		kc.airtime.OnTransmit(id, psduLen)
+	}
+}
+
+func (kc *kpiCollector) OnMacCounters(id NodeId, delta MacCounters) {
+	if kc.running {
+		stats := getMacStats(kc.mac, id)
+		stats.TxRetries += delta.TxRetry
+		stats.CcaFailures += delta.TxErrCca
+	}
+}
+
+func (kc *kpiCollector) OnLinkMetrics(src NodeId, dst NodeId, sample *LinkMetricsSample) {
+	if kc.running {
+		getLinkMetricsStats(kc.linkMetrics, src, dst).add(sample)
+	}
+}
+
+func (kc *kpiCollector) OnFrameDropped(id NodeId, reason string) {
+	if kc.running {
+		getMacStats(kc.mac, id).addDrop(reason, 1)
+	}
+}
+
+// GetCounters returns the dispatcher counters by name.
+func (d *Dispatcher) GetCounters() map[string]uint64 {
+	counters := map[string]uint64{}
+	countersVal := reflect.ValueOf(d.Counters)
+	countersTyp := reflect.TypeOf(d.Counters)
+	for i := 0; i < countersVal.NumField(); i++ {
+		counters[countersTyp.Field(i).Name] = countersVal.Field(i).Uint()
+	}
+	for name, val := range d.perf.counters() {
+		counters[name] = val
+	}
+	return counters
+}
+
+// StartKpi starts collecting KPI, discarding the previous KPI.
+func (d *Dispatcher) StartKpi() {
+	d.kpi = kpiCollector{
+		running:       true,
+		startTime:     d.CurTime,
+		startCounters: d.totalCounters(),
+		airtime:       newAirtimeMeter(d.CurTime),
+		mac:           map[NodeId]*MacStats{},
+		resources:     map[NodeId]*ResourceUsage{},
+		linkMetrics:   map[linkMetricsKey]*LinkMetricsStats{},
+		macTx:         macTxTable{},
+	}
+	d.linkStats.kpiMacTx = d.kpi.macTx
+}
+
+// StopKpi stops collecting KPI.
+func (d *Dispatcher) StopKpi() error {
+	if !d.kpi.running {
+		return errors.Errorf("KPI not started")
+	}
+
+	d.kpi.running = false
+	d.linkStats.kpiMacTx = nil
+	d.kpi.stopTime = d.CurTime
+	d.kpi.stopCounters = d.totalCounters()
+	return nil
+}
+
+// GetKpi returns the KPI collected so far, or nil if KPI was never started.
+func (d *Dispatcher) GetKpi() *Kpi {
+	kc := &d.kpi
+	if kc.airtime == nil {
+		return nil
+	}
+
+	kpi := &Kpi{
+		StartTime: kc.startTime,
+		StopTime:  kc.stopTime,
+		Running:   kc.running,
+		Counters:  map[string]uint64{},
+		Mac:       map[NodeId]*MacStats{},
+	}
+
+	stopCounters := kc.stopCounters
+	if kc.running {
+		kpi.StopTime = d.CurTime
+		stopCounters = d.totalCounters()
+		d.linkStats.sweep(d.CurTime)
+	}
+
+	for name, val := range stopCounters {
+		kpi.Counters[name] = val - kc.startCounters[name]
+	}
+	kpi.Airtime = kc.airtime.Report(kpi.StopTime)
+	for id, stats := range kc.mac {
+		kpiStats := &MacStats{}
+		kpiStats.add(stats)
+		kpi.Mac[id] = kpiStats
+	}
+	if len(kc.resources) > 0 {
+		kpi.Resources = map[NodeId]*ResourceUsage{}
+		for id, r := range kc.resources {
+			usage := *r
+			kpi.Resources[id] = &usage
+		}
+	}
+	if len(kc.linkMetrics) > 0 {
+		kpi.LinkMetrics = sortedLinkMetricsStats(kc.linkMetrics)
+	}
+	if macTx := kc.macTx.stats(); len(macTx) > 0 {
+		kpi.MacTx = macTx
+	}
+	return kpi
+}
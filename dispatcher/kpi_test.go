@@ -0,0 +1,107 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestKpi(t *testing.T) {
+	d := &Dispatcher{linkStats: newLinkStatsCollector()}
+	assert.Nil(t, d.GetKpi())
+	assert.NotNil(t, d.StopKpi())
+
+	d.Counters.AlarmEvents = 10
+	d.CurTime = 1000000
+	d.StartKpi()
+	d.Counters.AlarmEvents = 15
+	d.kpi.OnTransmit(1, 10)
+	d.CurTime = 2000000
+
+	kpi := d.GetKpi()
+	assert.True(t, kpi.Running)
+	assert.Equal(t, uint64(1000000), kpi.StartTime)
+	assert.Equal(t, uint64(2000000), kpi.StopTime)
+	assert.Equal(t, uint64(5), kpi.Counters["AlarmEvents"])
+	assert.Equal(t, 1, len(kpi.Airtime.Nodes))
+
+	assert.Nil(t, d.StopKpi())
+	d.Counters.AlarmEvents = 20
+	d.kpi.OnTransmit(1, 10)
+	d.CurTime = 3000000
+
+	kpi = d.GetKpi()
+	assert.False(t, kpi.Running)
+	assert.Equal(t, uint64(2000000), kpi.StopTime)
+	assert.Equal(t, uint64(5), kpi.Counters["AlarmEvents"])
+	assert.Equal(t, uint64(1), kpi.Airtime.Nodes[0].TxFrames)
+}
+
+func TestKpiResources(t *testing.T) {
+	d := &Dispatcher{linkStats: newLinkStatsCollector()}
+	d.nodes = map[NodeId]*Node{1: {D: d, Id: 1}}
+
+	// samples before KPI start are the baseline
+	d.SetResourceUsage(1, ResourceUsage{RssBytes: 1000, PeakRssBytes: 1000, CpuTime: 500})
+	d.StartKpi()
+	assert.Nil(t, d.GetKpi().Resources)
+
+	d.SetResourceUsage(1, ResourceUsage{RssBytes: 3000, PeakRssBytes: 3000, CpuTime: 800})
+	d.SetResourceUsage(1, ResourceUsage{RssBytes: 2000, PeakRssBytes: 3000, CpuTime: 1000})
+	// the node process was restarted
+	d.SetResourceUsage(1, ResourceUsage{RssBytes: 1500, PeakRssBytes: 1500, CpuTime: 100})
+	d.SetResourceUsage(2, ResourceUsage{RssBytes: 1000, CpuTime: 100})
+
+	kpi := d.GetKpi()
+	assert.Equal(t, 1, len(kpi.Resources))
+	assert.Equal(t, ResourceUsage{RssBytes: 1500, PeakRssBytes: 3000, CpuTime: 600}, *kpi.Resources[1])
+
+	assert.Nil(t, d.StopKpi())
+	d.SetResourceUsage(1, ResourceUsage{RssBytes: 5000, PeakRssBytes: 5000, CpuTime: 2000})
+	assert.Equal(t, uint64(600), d.GetKpi().Resources[1].CpuTime)
+}
+
+func TestPerfCounters(t *testing.T) {
+	d := &Dispatcher{radioModel: DefaultRadioModelParams()}
+	src := newNode(d, 1, 0, 0, 100)
+	dst := newNode(d, 2, 10, 0, 100)
+	assert.True(t, d.checkRadioReachable(src, dst, 11))
+
+	counters := d.GetCounters()
+	if PerfCountersEnabled {
+		assert.Equal(t, uint64(1), counters["PerfLinkChecks"])
+		d.ResetCounters()
+		assert.Equal(t, uint64(0), d.GetCounters()["PerfLinkChecks"])
+	} else {
+		_, ok := counters["PerfLinkChecks"]
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	maxLinkMetricsSamples = 1000 // maximum number of samples kept per node pair
+)
+
+// LinkMetricsSample is a Thread 1.2 Link Metrics report of a neighbor, queried by a node with a single probe.
+type LinkMetricsSample struct {
+	Time   uint64 `json:"time_us"`
+	Pdus   uint32 `json:"pdus"`   // PDU counter
+	Lqi    int    `json:"lqi"`    // link quality indicator, 0-255
+	Margin int    `json:"margin"` // link margin in dB
+	Rssi   int    `json:"rssi"`   // RSSI in dBm
+}
+
+// MetricStats aggregates the values of a link metric.
+type MetricStats struct {
+	Min  int     `json:"min"`
+	Max  int     `json:"max"`
+	Avg  float64 `json:"avg"`
+	Last int     `json:"last"`
+}
+
+func (ms *MetricStats) add(val int, count int) {
+	if count == 1 || val < ms.Min {
+		ms.Min = val
+	}
+	if count == 1 || val > ms.Max {
+		ms.Max = val
+	}
+	ms.Avg += (float64(val) - ms.Avg) / float64(count)
+	ms.Last = val
+}
+
+// LinkMetricsStats aggregates the Link Metrics samples of the link from a node to a neighbor.
+type LinkMetricsStats struct {
+	Src     NodeId      `json:"src"`
+	Dst     NodeId      `json:"dst"`
+	Samples int         `json:"samples"`
+	Lqi     MetricStats `json:"lqi"`
+	Margin  MetricStats `json:"margin"`
+	Rssi    MetricStats `json:"rssi"`
+}
+
+func (lms *LinkMetricsStats) add(sample *LinkMetricsSample) {
+	lms.Samples++
+	lms.Lqi.add(sample.Lqi, lms.Samples)
+	lms.Margin.add(sample.Margin, lms.Samples)
+	lms.Rssi.add(sample.Rssi, lms.Samples)
+}
+
+type linkMetricsKey struct {
+	Src, Dst NodeId
+}
+
+// linkMetricsCollector keeps the series of Link Metrics samples and their aggregates of each node pair.
+type linkMetricsCollector struct {
+	series map[linkMetricsKey][]*LinkMetricsSample
+	stats  map[linkMetricsKey]*LinkMetricsStats
+}
+
+func getLinkMetricsStats(m map[linkMetricsKey]*LinkMetricsStats, src NodeId, dst NodeId) *LinkMetricsStats {
+	key := linkMetricsKey{src, dst}
+	stats := m[key]
+	if stats == nil {
+		stats = &LinkMetricsStats{Src: src, Dst: dst}
+		m[key] = stats
+	}
+	return stats
+}
+
+// sortedLinkMetricsStats returns copies of the aggregates ordered by node pair.
+func sortedLinkMetricsStats(m map[linkMetricsKey]*LinkMetricsStats) []*LinkMetricsStats {
+	result := make([]*LinkMetricsStats, 0, len(m))
+	for _, stats := range m {
+		s := *stats
+		result = append(result, &s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Src != result[j].Src {
+			return result[i].Src < result[j].Src
+		}
+		return result[i].Dst < result[j].Dst
+	})
+	return result
+}
+
+// AddLinkMetrics records a Link Metrics sample of the link from the source node to the neighbor, which is added to the
+// series of the node pair, its aggregates and the KPI.
+func (d *Dispatcher) AddLinkMetrics(src NodeId, dst NodeId, sample LinkMetricsSample) {
+	lc := &d.linkMetrics
+	if lc.series == nil {
+		lc.series = map[linkMetricsKey][]*LinkMetricsSample{}
+		lc.stats = map[linkMetricsKey]*LinkMetricsStats{}
+	}
+
+	key := linkMetricsKey{src, dst}
+	series := append(lc.series[key], &sample)
+	if len(series) > maxLinkMetricsSamples {
+		series = series[1:]
+	}
+	lc.series[key] = series
+	getLinkMetricsStats(lc.stats, src, dst).add(&sample)
+	d.kpi.OnLinkMetrics(src, dst, &sample)
+}
+
+// GetLinkMetricsSeries returns the latest Link Metrics samples of the link from the source node to the neighbor.
+func (d *Dispatcher) GetLinkMetricsSeries(src NodeId, dst NodeId) []*LinkMetricsSample {
+	return d.linkMetrics.series[linkMetricsKey{src, dst}]
+}
+
+// GetLinkMetricsStats returns the aggregates of the Link Metrics samples of all node pairs.
+func (d *Dispatcher) GetLinkMetricsStats() []*LinkMetricsStats {
+	return sortedLinkMetricsStats(d.linkMetrics.stats)
+}
+
+// ResetLinkMetrics discards all Link Metrics samples.
+func (d *Dispatcher) ResetLinkMetrics() {
+	d.linkMetrics = linkMetricsCollector{}
+}
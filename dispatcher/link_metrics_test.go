@@ -0,0 +1,69 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkMetrics(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.AddLinkMetrics(1, 2, LinkMetricsSample{Time: 1000000, Pdus: 1, Lqi: 200, Margin: 60, Rssi: -40})
+
+	d.StartKpi()
+	d.AddLinkMetrics(1, 2, LinkMetricsSample{Time: 2000000, Pdus: 2, Lqi: 100, Margin: 40, Rssi: -60})
+	d.AddLinkMetrics(2, 1, LinkMetricsSample{Time: 2000000, Pdus: 1, Lqi: 255, Margin: 70, Rssi: -30})
+
+	assert.Len(t, d.GetLinkMetricsSeries(1, 2), 2)
+	assert.Equal(t, uint64(2000000), d.GetLinkMetricsSeries(1, 2)[1].Time)
+	assert.Empty(t, d.GetLinkMetricsSeries(2, 3))
+
+	stats := d.GetLinkMetricsStats()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, &LinkMetricsStats{Src: 1, Dst: 2, Samples: 2,
+		Lqi:    MetricStats{Min: 100, Max: 200, Avg: 150, Last: 100},
+		Margin: MetricStats{Min: 40, Max: 60, Avg: 50, Last: 40},
+		Rssi:   MetricStats{Min: -60, Max: -40, Avg: -50, Last: -60},
+	}, stats[0])
+	assert.Equal(t, 2, stats[1].Src)
+
+	// the KPI only covers the samples after KPI start
+	kpi := d.GetKpi()
+	assert.Len(t, kpi.LinkMetrics, 2)
+	assert.Equal(t, 1, kpi.LinkMetrics[0].Samples)
+	assert.Equal(t, -60, kpi.LinkMetrics[0].Rssi.Last)
+
+	for i := 0; i < maxLinkMetricsSamples; i++ {
+		d.AddLinkMetrics(1, 2, LinkMetricsSample{})
+	}
+	assert.Len(t, d.GetLinkMetricsSeries(1, 2), maxLinkMetricsSamples)
+
+	d.ResetLinkMetrics()
+	assert.Empty(t, d.GetLinkMetricsStats())
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// linkFrameTimeout is the time (in us) after the first transmission of a frame after which it is counted as lost
+	// if not delivered, and after which a transmission with the same sequence number is a new frame.
+	linkFrameTimeout         = 1000000
+	maxLinkLatencySampleSize = 1000
+)
+
+// LinkLatencyBuckets are the upper bounds (in us, exclusive) of the buckets of the link latency histogram. The last
+// bucket of the histogram counts the latencies above all bounds.
+var LinkLatencyBuckets = []uint64{1000, 2000, 5000, 10000, 20000, 50000, 100000}
+
+// LinkStats contains the statistics of the unicast MAC frames (requesting an ACK) sent from a node to another.
+// The latency of a frame is the time from the start of its first transmission to the end of the transmission that
+// is delivered, including retries.
+type LinkStats struct {
+	Src       NodeId
+	Dst       NodeId
+	Frames    int // frames sent, counted once for all their transmissions
+	Delivered int
+	Lost      int // frames not delivered by any transmission within the frame timeout
+	Retried   int // frames delivered by a retransmission
+	Retries   int // retransmissions of all frames
+	Latency   DurationStats
+	Histogram []int // latency histogram with buckets of LinkLatencyBuckets
+}
+
+type linkKey struct {
+	src, dst NodeId
+}
+
+type linkFrameKey struct {
+	src, dst NodeId
+	seq      uint8
+}
+
+type linkFrame struct {
+	first      uint64
+	lastEnd    uint64 // end of the latest transmission
+	attempts   int
+	delivered  bool
+	macTx      bool // the frame is counted for the MAC TX statistics of the source node
+	linkReset  bool // the link statistics were reset after the first transmission
+	macTxReset bool // the MAC TX statistics were reset after the first transmission
+}
+
+type linkStatsEntry struct {
+	stats     LinkStats
+	latencies []uint64
+}
+
+// linkStatsCollector tracks the transmitted unicast frames for the link statistics, and the MAC TX statistics of the
+// source nodes.
+type linkStatsCollector struct {
+	links     map[linkKey]*linkStatsEntry
+	frames    map[linkFrameKey]*linkFrame
+	macTx     macTxTable
+	kpiMacTx  macTxTable // MAC TX statistics of the running KPI, or nil
+	lastSweep uint64
+}
+
+func newLinkStatsCollector() *linkStatsCollector {
+	return &linkStatsCollector{
+		links:  map[linkKey]*linkStatsEntry{},
+		frames: map[linkFrameKey]*linkFrame{},
+		macTx:  macTxTable{},
+	}
+}
+
+func (lc *linkStatsCollector) link(src, dst NodeId) *linkStatsEntry {
+	key := linkKey{src, dst}
+	entry := lc.links[key]
+	if entry == nil {
+		entry = &linkStatsEntry{stats: LinkStats{Src: src, Dst: dst, Histogram: make([]int, len(LinkLatencyBuckets)+1)}}
+		lc.links[key] = entry
+	}
+	return entry
+}
+
+// onTransmit tracks a transmission of the frame. macTx is set if the frame is counted for the MAC TX statistics of the
+// source node, which counts a frame once even if it has several receivers.
+func (lc *linkStatsCollector) onTransmit(now uint64, airtime uint64, src, dst NodeId, seq uint8, macTx bool) {
+	lc.sweep(now)
+
+	key := linkFrameKey{src, dst, seq}
+	frame := lc.frames[key]
+	if frame != nil && now > frame.first+linkFrameTimeout {
+		lc.finish(key, frame)
+		frame = nil
+	}
+
+	entry := lc.link(src, dst)
+	if frame == nil {
+		frame = &linkFrame{first: now, macTx: macTx}
+		lc.frames[key] = frame
+		entry.stats.Frames++
+	} else {
+		if !frame.delivered && !frame.linkReset {
+			entry.stats.Retries++
+		}
+		if frame.macTx && !frame.macTxReset && now >= frame.lastEnd {
+			lc.macTx.addBackoff(src, now-frame.lastEnd)
+			lc.kpiMacTx.addBackoff(src, now-frame.lastEnd)
+		}
+	}
+	frame.lastEnd = now + airtime
+	frame.attempts++
+}
+
+// onDelivered counts the delivery of the frame transmitted at the time. Deliveries of retransmissions after the frame
+// is delivered are not counted, e.g. if the ACK was lost.
+func (lc *linkStatsCollector) onDelivered(timestamp uint64, airtime uint64, src, dst NodeId, seq uint8) {
+	frame := lc.frames[linkFrameKey{src, dst, seq}]
+	if frame == nil || frame.delivered || frame.linkReset {
+		return
+	}
+
+	frame.delivered = true
+	entry := lc.link(src, dst)
+	entry.stats.Delivered++
+	if frame.attempts > 1 {
+		entry.stats.Retried++
+	}
+
+	latency := timestamp + airtime - frame.first
+	bucket := 0
+	for bucket < len(LinkLatencyBuckets) && latency >= LinkLatencyBuckets[bucket] {
+		bucket++
+	}
+	entry.stats.Histogram[bucket]++
+	entry.latencies = append(entry.latencies, latency)
+	if len(entry.latencies) > maxLinkLatencySampleSize {
+		entry.latencies = entry.latencies[1:]
+	}
+}
+
+func (lc *linkStatsCollector) finish(key linkFrameKey, frame *linkFrame) {
+	delete(lc.frames, key)
+	if !frame.delivered && !frame.linkReset {
+		lc.link(key.src, key.dst).stats.Lost++
+	}
+	if frame.macTx && !frame.macTxReset {
+		lc.macTx.addFrame(key.src, frame.attempts-1)
+		lc.kpiMacTx.addFrame(key.src, frame.attempts-1)
+	}
+}
+
+// sweep finishes the frames after the frame timeout, at most once per frame timeout.
+func (lc *linkStatsCollector) sweep(now uint64) {
+	if now < lc.lastSweep+linkFrameTimeout {
+		return
+	}
+	lc.lastSweep = now
+
+	for key, frame := range lc.frames {
+		if now > frame.first+linkFrameTimeout {
+			lc.finish(key, frame)
+		}
+	}
+}
+
+func (lc *linkStatsCollector) stats(now uint64, src, dst NodeId) LinkStats {
+	lc.sweep(now)
+
+	entry := lc.links[linkKey{src, dst}]
+	if entry == nil {
+		return LinkStats{Src: src, Dst: dst, Histogram: make([]int, len(LinkLatencyBuckets)+1)}
+	}
+
+	stats := entry.stats
+	stats.Histogram = append([]int(nil), entry.stats.Histogram...)
+	stats.Latency = newDurationStats(append([]uint64(nil), entry.latencies...))
+	return stats
+}
+
+// trackLinkFrame starts tracking a transmitted unicast frame which requests an ACK for the link statistics.
+func (d *Dispatcher) trackLinkFrame(sit *sendItem, srcnode *Node, frame *wpan.MacFrame) {
+	if frame.FrameControl.FrameType() != wpan.FrameTypeData && frame.FrameControl.FrameType() != wpan.FrameTypeCommand ||
+		!frame.FrameControl.AckRequest() {
+		return
+	}
+
+	receivers, unicast := d.unicastReceivers(srcnode, frame)
+	if !unicast {
+		return
+	}
+
+	airtime := frameAirtime(len(sit.Data) - 1)
+	for i, dst := range receivers {
+		d.linkStats.onTransmit(sit.Timestamp, airtime, srcnode.Id, dst, frame.Seq, i == 0)
+	}
+	sit.linkTracked = true
+}
+
+func (d *Dispatcher) onLinkFrameDelivered(sit *sendItem, srcnode *Node, dstnode *Node) {
+	d.linkStats.onDelivered(sit.Timestamp, frameAirtime(len(sit.Data)-1), srcnode.Id, dstnode.Id, sit.Data[3])
+}
+
+// GetLinkStats returns the statistics of the unicast frames sent from the source node to the destination node.
+func (d *Dispatcher) GetLinkStats(src, dst NodeId) LinkStats {
+	return d.linkStats.stats(d.CurTime, src, dst)
+}
+
+// ResetLinkStats discards the statistics of all links. The frames in flight are no longer counted for the links.
+func (d *Dispatcher) ResetLinkStats() {
+	lc := d.linkStats
+	lc.links = map[linkKey]*linkStatsEntry{}
+	for _, frame := range lc.frames {
+		frame.linkReset = true
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/openthread/ot-ns/dissectpkt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkStats(t *testing.T) {
+	d := &Dispatcher{
+		extaddrMap: map[uint64]*Node{},
+		rloc16Map:  rloc16Map{},
+		linkStats:  newLinkStatsCollector(),
+	}
+	src := &Node{D: d, Id: 3}
+	dst := &Node{D: d, Id: 7}
+	d.rloc16Map.Add(0x0400, dst)
+
+	transmit := func(timestamp uint64, seq uint8, delivered bool) {
+		// data frame with ACK request to short address 0x0400
+		data := []byte{11, 0x61, 0x88, seq, 0xce, 0xfa, 0x00, 0x04, 0x00, 0x08, 0, 0}
+		sit := &sendItem{Timestamp: timestamp, NodeId: src.Id, Data: data}
+		d.trackLinkFrame(sit, src, dissectpkt.Dissect(data).MacFrame)
+		assert.True(t, sit.linkTracked)
+		if delivered {
+			d.onLinkFrameDelivered(sit, src, dst)
+		}
+	}
+	airtime := frameAirtime(11)
+
+	// delivered at the first transmission
+	transmit(1000, 1, true)
+	// delivered by the second retransmission, then retransmitted again since the ACK was lost
+	transmit(10000, 2, false)
+	transmit(12000, 2, false)
+	transmit(15000, 2, true)
+	transmit(17000, 2, true)
+	// lost
+	transmit(20000, 3, false)
+	transmit(22000, 3, false)
+
+	d.CurTime = 30000
+	stats := d.GetLinkStats(3, 7)
+	assert.Equal(t, 3, stats.Frames)
+	assert.Equal(t, 2, stats.Delivered)
+	assert.Equal(t, 0, stats.Lost)
+	assert.Equal(t, 1, stats.Retried)
+	assert.Equal(t, 3, stats.Retries)
+	assert.Equal(t, 2, stats.Latency.Count)
+	assert.Equal(t, 5000+airtime, stats.Latency.Max)
+	assert.Equal(t, []int{1, 0, 0, 1, 0, 0, 0, 0}, stats.Histogram)
+
+	// the undelivered frame is lost after the frame timeout, and the sequence number starts a new frame
+	transmit(20000+linkFrameTimeout+1, 3, true)
+	stats = d.GetLinkStats(3, 7)
+	assert.Equal(t, 4, stats.Frames)
+	assert.Equal(t, 3, stats.Delivered)
+	assert.Equal(t, 1, stats.Lost)
+
+	assert.Equal(t, 0, d.GetLinkStats(7, 3).Frames)
+	assert.Equal(t, len(LinkLatencyBuckets)+1, len(d.GetLinkStats(7, 3).Histogram))
+
+	d.ResetLinkStats()
+	assert.Equal(t, 0, d.GetLinkStats(3, 7).Frames)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// logCorrelationFile is the CSV file which maps the frames of a classic pcap file to the log sequence numbers of the
+// sending nodes, since the classic pcap format can not carry comments.
+type logCorrelationFile struct {
+	fd     *os.File
+	frames int
+}
+
+// logCorrelationFilename returns the name of the log correlation file of the pcap file, e.g. `current_logseq.csv`.
+func logCorrelationFilename(pcapFile string) string {
+	return strings.TrimSuffix(pcapFile, filepath.Ext(pcapFile)) + "_logseq.csv"
+}
+
+func newLogCorrelationFile(pcapFile string) (*logCorrelationFile, error) {
+	fd, err := os.OpenFile(logCorrelationFilename(pcapFile), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	lc := &logCorrelationFile{fd: fd}
+	if err = lc.writeHeader(); err != nil {
+		_ = fd.Close()
+		return nil, err
+	}
+	return lc, nil
+}
+
+// Write writes the row of the frame. Frames are numbered from 1 like in Wireshark.
+func (lc *logCorrelationFile) Write(item *pcapFrameItem) error {
+	lc.frames++
+	_, err := fmt.Fprintf(lc.fd, "%d,%d,%d,%d\n", lc.frames, item.Info.Ustime, item.Info.NodeId, item.LogSeq)
+	return err
+}
+
+func (lc *logCorrelationFile) Reset() error {
+	if err := lc.fd.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := lc.fd.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	lc.frames = 0
+	return lc.writeHeader()
+}
+
+func (lc *logCorrelationFile) Close() error {
+	return lc.fd.Close()
+}
+
+func (lc *logCorrelationFile) writeHeader() error {
+	_, err := fmt.Fprintln(lc.fd, "frame,time_us,node,log_seq")
+	return err
+}
+
+func (d *Dispatcher) nodeLogSeq(nodeid NodeId) uint64 {
+	if !d.cfg.LogCorrelation {
+		return 0
+	}
+	return d.cbHandler.GetNodeLogSeq(nodeid)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openthread/ot-ns/pcap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogCorrelationFile(t *testing.T) {
+	assert.Equal(t, "current_logseq.csv", logCorrelationFilename("current.pcap"))
+	assert.Equal(t, "out/session_logseq.csv", logCorrelationFilename("out/session.pcap"))
+
+	dir, err := ioutil.TempDir("", "otns")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	pcapFile := filepath.Join(dir, "current.pcap")
+	lc, err := newLogCorrelationFile(pcapFile)
+	assert.Nil(t, err)
+
+	assert.Nil(t, lc.Write(&pcapFrameItem{Info: pcap.FrameInfo{Ustime: 1000, NodeId: 1}, LogSeq: 12}))
+	assert.Nil(t, lc.Write(&pcapFrameItem{Info: pcap.FrameInfo{Ustime: 2000, NodeId: 2}, LogSeq: 7}))
+	data, err := ioutil.ReadFile(logCorrelationFilename(pcapFile))
+	assert.Nil(t, err)
+	assert.Equal(t, "frame,time_us,node,log_seq\n1,1000,1,12\n2,2000,2,7\n", string(data))
+
+	assert.Nil(t, lc.Reset())
+	assert.Nil(t, lc.Write(&pcapFrameItem{Info: pcap.FrameInfo{Ustime: 10, NodeId: 3}, LogSeq: 1}))
+	assert.Nil(t, lc.Close())
+	data, err = ioutil.ReadFile(logCorrelationFilename(pcapFile))
+	assert.Nil(t, err)
+	assert.Equal(t, "frame,time_us,node,log_seq\n1,10,3,1\n", string(data))
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// Frame drop reasons decided by the dispatcher when delivering a frame.
+const (
+	DropReasonRange     = "range"     // unicast destination is out of radio range
+	DropReasonUnknown   = "unknown"   // unicast destination address is not known
+	DropReasonDown      = "down"      // destination node is failed or paused
+	DropReasonJam       = "jam"       // frame is jammed at the destination
+	DropReasonCollision = "collision" // frame collides with another frame at the destination
+	DropReasonLoss      = "loss"      // frame is lost due to the global packet loss ratio
+	DropReasonDutyCycle = "dutycycle" // frame is blocked by the duty-cycle limit of the sender
+	DropReasonCca       = "cca"       // frame is not transmitted since the channel is busy at the sender
+)
+
+// MacCounters contains the MAC counters reported by a node (`counters mac`).
+type MacCounters struct {
+	TxRetry  uint64
+	TxErrCca uint64
+}
+
+// sub returns the increments since the previous sample. A counter lower than before means the node counters were
+// reset, in which case the whole value counts as increment.
+func (c MacCounters) sub(prev MacCounters) MacCounters {
+	delta := c
+	if c.TxRetry >= prev.TxRetry {
+		delta.TxRetry -= prev.TxRetry
+	}
+	if c.TxErrCca >= prev.TxErrCca {
+		delta.TxErrCca -= prev.TxErrCca
+	}
+	return delta
+}
+
+// MacStats contains the MAC retries, CCA failures and frame drops of a node.
+// Drops are counted per undelivered frame copy for the transmitting node, by reason.
+type MacStats struct {
+	TxRetries   uint64            `yaml:"retries,omitempty" json:"retries"`
+	CcaFailures uint64            `yaml:"cca,omitempty" json:"cca"`
+	Drops       map[string]uint64 `yaml:"drops,omitempty" json:"drops,omitempty"`
+}
+
+// TotalDrops returns the number of dropped frames of all reasons.
+func (s *MacStats) TotalDrops() uint64 {
+	var total uint64
+	for _, n := range s.Drops {
+		total += n
+	}
+	return total
+}
+
+// DropReasons returns the sorted drop reasons.
+func (s *MacStats) DropReasons() []string {
+	reasons := make([]string, 0, len(s.Drops))
+	for reason := range s.Drops {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+func (s *MacStats) add(o *MacStats) {
+	s.TxRetries += o.TxRetries
+	s.CcaFailures += o.CcaFailures
+	for reason, n := range o.Drops {
+		s.addDrop(reason, n)
+	}
+}
+
+func (s *MacStats) addDrop(reason string, n uint64) {
+	if s.Drops == nil {
+		s.Drops = map[string]uint64{}
+	}
+	s.Drops[reason] += n
+}
+
+func getMacStats(m map[NodeId]*MacStats, id NodeId) *MacStats {
+	stats := m[id]
+	if stats == nil {
+		stats = &MacStats{}
+		m[id] = stats
+	}
+	return stats
+}
+
+// SetMacCounters records the MAC counters sampled from a node. The increments since the previous sample are added
+// to the current time window and the KPI.
+func (d *Dispatcher) SetMacCounters(id NodeId, counters MacCounters) {
+	node := d.nodes[id]
+	if node == nil {
+		return
+	}
+
+	delta := counters.sub(node.macCounters)
+	node.macCounters = counters
+	if delta.TxRetry == 0 && delta.TxErrCca == 0 {
+		return
+	}
+
+	d.windowStats.OnMacCounters(id, delta)
+	d.kpi.OnMacCounters(id, delta)
+	d.runStats.onMacCounters(delta)
+}
+
+func (d *Dispatcher) onFrameDropped(id NodeId, reason string) {
+	d.radioWatchf(id, RadioWatchInfo, "TX dropped: %s", reason)
+	d.windowStats.OnFrameDropped(id, reason)
+	d.kpi.OnFrameDropped(id, reason)
+	d.runStats.mac.addDrop(reason, 1)
+}
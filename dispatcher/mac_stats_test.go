@@ -0,0 +1,82 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestMacCountersSub(t *testing.T) {
+	delta := MacCounters{TxRetry: 10, TxErrCca: 3}.sub(MacCounters{TxRetry: 4, TxErrCca: 1})
+	assert.Equal(t, MacCounters{TxRetry: 6, TxErrCca: 2}, delta)
+
+	// node counters were reset
+	delta = MacCounters{TxRetry: 2, TxErrCca: 3}.sub(MacCounters{TxRetry: 4, TxErrCca: 1})
+	assert.Equal(t, MacCounters{TxRetry: 2, TxErrCca: 2}, delta)
+}
+
+func TestMacStats(t *testing.T) {
+	d := &Dispatcher{
+		nodes:       map[NodeId]*Node{1: {Id: 1}},
+		windowStats: newWindowStatsCollector(WindowStatsConfig{Interval: 10, Retention: 3, Metrics: WindowMetricAll}, 0),
+		linkStats:   newLinkStatsCollector(),
+	}
+
+	// counters before the KPI start are not included in the KPI
+	d.SetMacCounters(1, MacCounters{TxRetry: 5, TxErrCca: 1})
+	d.StartKpi()
+	d.SetMacCounters(1, MacCounters{TxRetry: 8, TxErrCca: 1})
+	d.SetMacCounters(2, MacCounters{TxRetry: 8, TxErrCca: 1})
+	d.onFrameDropped(1, DropReasonRange)
+	d.onFrameDropped(1, DropReasonRange)
+	d.onFrameDropped(1, DropReasonJam)
+
+	kpi := d.GetKpi()
+	assert.Equal(t, 1, len(kpi.Mac))
+	assert.Equal(t, uint64(3), kpi.Mac[1].TxRetries)
+	assert.Equal(t, uint64(0), kpi.Mac[1].CcaFailures)
+	assert.Equal(t, uint64(3), kpi.Mac[1].TotalDrops())
+	assert.Equal(t, []string{DropReasonJam, DropReasonRange}, kpi.Mac[1].DropReasons())
+
+	d.windowStats.Advance(10)
+	total := d.windowStats.Windows()[0].MacTotal()
+	assert.Equal(t, uint64(8), total.TxRetries)
+	assert.Equal(t, uint64(1), total.CcaFailures)
+	assert.Equal(t, uint64(2), total.Drops[DropReasonRange])
+
+	// disabled metrics are not counted
+	d.windowStats.Configure(WindowStatsConfig{Interval: 10, Retention: 3, Metrics: WindowMetricDrops}, 10)
+	d.SetMacCounters(1, MacCounters{TxRetry: 9, TxErrCca: 2})
+	d.onFrameDropped(1, DropReasonLoss)
+	d.windowStats.Advance(20)
+	total = d.windowStats.Windows()[0].MacTotal()
+	assert.True(t, total.TxRetries == 0 && total.CcaFailures == 0 && total.TotalDrops() == 1)
+}
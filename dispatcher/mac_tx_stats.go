@@ -0,0 +1,121 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// MaxMacTxRetries is the last bucket of the retry distribution, which counts the frames with at least as many
+	// retransmissions.
+	MaxMacTxRetries         = 15
+	maxMacBackoffSampleSize = 1000
+)
+
+// MacTxStats contains the distribution of the MAC retransmissions of the unicast frames (requesting an ACK) sent by a
+// node to other nodes, and the statistics of the retransmission backoff. The backoff of a retransmission is the time
+// from the end of the previous transmission of the frame to its start, i.e. the ACK timeout, the CSMA backoff and the
+// CCA. The frames are those tracked by the link statistics, and are counted when the frame timeout elapses after their
+// first transmission, as retransmissions may still follow until then.
+type MacTxStats struct {
+	Frames  uint64        `json:"frames"`
+	Retries []uint64      `json:"retries"` // frames by number of retransmissions, up to MaxMacTxRetries or more
+	Backoff DurationStats `json:"backoff"` // of the latest retransmissions
+}
+
+type macTxEntry struct {
+	frames   uint64
+	retries  []uint64
+	backoffs []uint64
+}
+
+// macTxTable accumulates the MAC TX statistics of nodes from the frames finished by the link statistics. A nil table
+// ignores the frames.
+type macTxTable map[NodeId]*macTxEntry
+
+func (t macTxTable) node(id NodeId) *macTxEntry {
+	entry := t[id]
+	if entry == nil {
+		entry = &macTxEntry{retries: make([]uint64, MaxMacTxRetries+1)}
+		t[id] = entry
+	}
+	return entry
+}
+
+func (t macTxTable) addBackoff(src NodeId, backoff uint64) {
+	if t == nil {
+		return
+	}
+
+	entry := t.node(src)
+	entry.backoffs = append(entry.backoffs, backoff)
+	if len(entry.backoffs) > maxMacBackoffSampleSize {
+		entry.backoffs = entry.backoffs[1:]
+	}
+}
+
+func (t macTxTable) addFrame(src NodeId, retries int) {
+	if t == nil {
+		return
+	}
+
+	if retries > MaxMacTxRetries {
+		retries = MaxMacTxRetries
+	}
+	entry := t.node(src)
+	entry.frames++
+	entry.retries[retries]++
+}
+
+func (t macTxTable) stats() map[NodeId]*MacTxStats {
+	stats := make(map[NodeId]*MacTxStats, len(t))
+	for id, entry := range t {
+		stats[id] = &MacTxStats{
+			Frames:  entry.frames,
+			Retries: append([]uint64(nil), entry.retries...),
+			Backoff: newDurationStats(append([]uint64(nil), entry.backoffs...)),
+		}
+	}
+	return stats
+}
+
+// GetMacTxStats returns the MAC retransmission distribution and backoff statistics of the nodes which sent unicast
+// frames.
+func (d *Dispatcher) GetMacTxStats() map[NodeId]*MacTxStats {
+	d.linkStats.sweep(d.CurTime)
+	return d.linkStats.macTx.stats()
+}
+
+// ResetMacTxStats discards the MAC TX statistics of all nodes. The frames in flight are no longer counted.
+func (d *Dispatcher) ResetMacTxStats() {
+	lc := d.linkStats
+	lc.macTx = macTxTable{}
+	for _, frame := range lc.frames {
+		frame.macTxReset = true
+	}
+}
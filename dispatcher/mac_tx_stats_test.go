@@ -0,0 +1,106 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dissectpkt"
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestMacTxStats(t *testing.T) {
+	d := &Dispatcher{
+		extaddrMap: map[uint64]*Node{},
+		rloc16Map:  rloc16Map{},
+		linkStats:  newLinkStatsCollector(),
+	}
+	dst := &Node{D: d, Id: 7}
+	d.rloc16Map.Add(0x0400, dst)
+	// a duplicate RLOC16 receives the same frames, which are counted once for the source node
+	d.rloc16Map.Add(0x0400, &Node{D: d, Id: 8})
+
+	transmit := func(timestamp uint64, src NodeId, seq uint8) {
+		// data frame with ACK request to short address 0x0400
+		data := []byte{11, 0x61, 0x88, seq, 0xce, 0xfa, 0x00, 0x04, 0x00, 0x08, 0, 0}
+		sit := &sendItem{Timestamp: timestamp, NodeId: src, Data: data}
+		d.trackLinkFrame(sit, &Node{D: d, Id: src}, dissectpkt.Dissect(data).MacFrame)
+	}
+	airtime := frameAirtime(11)
+
+	d.StartKpi()
+	// sent at the first transmission
+	transmit(1000, 3, 1)
+	// two retransmissions
+	transmit(10000, 3, 2)
+	transmit(12000, 3, 2)
+	transmit(15000, 3, 2)
+	// frames of other nodes are counted separately
+	transmit(16000, 4, 2)
+
+	// the frames are counted after the frame timeout
+	d.CurTime = 30000
+	assert.Equal(t, uint64(0), d.GetMacTxStats()[3].Frames)
+	d.CurTime = 16000 + linkFrameTimeout + 1
+	stats := d.GetMacTxStats()
+	assert.Equal(t, uint64(2), stats[3].Frames)
+	assert.Equal(t, MaxMacTxRetries+1, len(stats[3].Retries))
+	assert.Equal(t, []uint64{1, 0, 1}, stats[3].Retries[:3])
+	assert.Equal(t, 2, stats[3].Backoff.Count)
+	assert.Equal(t, 3000-airtime, stats[3].Backoff.Max)
+	assert.Equal(t, []uint64{1, 0}, stats[4].Retries[:2])
+	assert.Equal(t, 0, stats[4].Backoff.Count)
+	// the link statistics count the same frames for each receiver
+	assert.Equal(t, 2, d.GetLinkStats(3, 7).Frames)
+	assert.Equal(t, 2, d.GetLinkStats(3, 8).Frames)
+
+	// the KPI counts the frames until stopped
+	assert.Nil(t, d.StopKpi())
+	assert.Equal(t, stats, d.GetKpi().MacTx)
+
+	// the sequence number starts a new frame after the frame timeout, and the retries are capped
+	now := uint64(2 * linkFrameTimeout)
+	for i := 0; i <= MaxMacTxRetries+1; i++ {
+		transmit(now+uint64(i)*5000, 3, 1)
+	}
+	d.CurTime = now + 3*linkFrameTimeout
+	stats = d.GetMacTxStats()
+	assert.Equal(t, uint64(3), stats[3].Frames)
+	assert.Equal(t, uint64(1), stats[3].Retries[MaxMacTxRetries])
+	assert.Nil(t, stats[NodeId(5)])
+	assert.Equal(t, uint64(2), d.GetKpi().MacTx[3].Frames)
+
+	// frames in flight are not counted after a reset
+	transmit(d.CurTime, 3, 5)
+	d.ResetMacTxStats()
+	transmit(d.CurTime+5000, 3, 5)
+	d.CurTime += 2 * linkFrameTimeout
+	assert.Empty(t, d.GetMacTxStats())
+	assert.Equal(t, 4, d.GetLinkStats(3, 7).Frames)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// NodeState is the state of a node from Time until the next state in its history.
+type NodeState struct {
+	Time        uint64       `json:"time_us"`
+	Role        OtDeviceRole `json:"-"`
+	RoleName    string       `json:"role"` // Role as a string
+	Rloc16      uint16       `json:"rloc16"`
+	Parent      uint64       `json:"parent"` // extended address of the parent, or 0
+	PartitionId uint32       `json:"partition_id"`
+	Deleted     bool         `json:"deleted,omitempty"` // the node was deleted at Time
+}
+
+func (s *NodeState) sameAs(other *NodeState) bool {
+	return s.Role == other.Role && s.Rloc16 == other.Rloc16 && s.Parent == other.Parent &&
+		s.PartitionId == other.PartitionId && s.Deleted == other.Deleted
+}
+
+// nodeStates replays the timeline events of the node into its state history. Several changes at the same virtual
+// time are compacted into one state.
+func (d *Dispatcher) nodeStates(id NodeId) []NodeState {
+	var history []NodeState
+	state := NodeState{Role: OtDeviceRoleDisabled, Rloc16: threadconst.InvalidRloc16}
+	for i := range d.timeline {
+		ev := &d.timeline[i]
+		if ev.Node != id {
+			continue
+		}
+
+		switch ev.Type {
+		case TimelineNodeAdd:
+			state = NodeState{Role: OtDeviceRoleDisabled, Rloc16: threadconst.InvalidRloc16}
+		case TimelineNodeDelete:
+			state = NodeState{Role: OtDeviceRoleDisabled, Rloc16: threadconst.InvalidRloc16, Deleted: true}
+		case TimelineRole:
+			state.Role = parseTimelineRole(ev.New)
+		case TimelineParent:
+			state.Parent = parseTimelineHex(ev.New)
+		case TimelinePartition:
+			state.PartitionId = uint32(parseTimelineHex(ev.New))
+		case TimelineRloc16:
+			state.Rloc16 = threadconst.InvalidRloc16
+			if ev.New != "" {
+				state.Rloc16 = uint16(parseTimelineHex(ev.New))
+			}
+		default:
+			continue
+		}
+		state.Time = ev.Time
+		state.RoleName = state.Role.String()
+
+		if n := len(history); n > 0 {
+			if history[n-1].sameAs(&state) {
+				continue
+			}
+			if history[n-1].Time == state.Time {
+				history[n-1] = state
+				continue
+			}
+		}
+		history = append(history, state)
+	}
+	return history
+}
+
+// NodeStateAt returns the state of the node at the virtual time, or false if the node did not exist at the time or
+// the time is older than its recorded history.
+func (d *Dispatcher) NodeStateAt(id NodeId, ts uint64) (NodeState, bool) {
+	history := d.nodeStates(id)
+	// the index of the first state after ts
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].Time > ts
+	})
+	if i == 0 || history[i-1].Deleted {
+		return NodeState{}, false
+	}
+	return history[i-1], true
+}
+
+// NodeHistory returns the states of the node between the virtual times: the state at start, if any, followed by the
+// state transitions until end.
+func (d *Dispatcher) NodeHistory(id NodeId, start, end uint64) []NodeState {
+	history := d.nodeStates(id)
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].Time > start
+	})
+	if i > 0 {
+		// include the state at start
+		i--
+	}
+
+	var states []NodeState
+	for ; i < len(history) && history[i].Time <= end; i++ {
+		states = append(states, history[i])
+	}
+	return states
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func TestNodeHistory(t *testing.T) {
+	d := &Dispatcher{
+		nodes:           map[NodeId]*Node{},
+		vis:             visualize.NewNopVisualizer(),
+		pendingUpgrades: map[NodeId]*UpgradeResult{},
+		rloc16Map:       rloc16Map{},
+	}
+	node := newNode(d, 5, 0, 0, 160)
+	d.nodes[5] = node
+	d.CurTime = 1000000
+	d.addTimelineEvent(TimelineNodeAdd, node.Id, "", "")
+
+	d.CurTime = 2000000
+	d.setNodeRole(5, OtDeviceRoleDetached)
+	d.CurTime = 3000000
+	d.setNodeRole(5, OtDeviceRoleChild)
+	d.setNodeRloc16(5, 0x0401)
+	d.CurTime = 340000000
+	d.setNodeRole(5, OtDeviceRoleRouter)
+	d.setNodeRloc16(5, 0x0800)
+	d.setNodeRloc16(5, 0x0800)
+
+	_, ok := d.NodeStateAt(5, 999999)
+	assert.False(t, ok)
+	state, ok := d.NodeStateAt(5, 3500000)
+	assert.True(t, ok)
+	assert.Equal(t, OtDeviceRoleChild, state.Role)
+	assert.Equal(t, uint16(0x0401), state.Rloc16)
+	state, _ = d.NodeStateAt(5, 340000000)
+	assert.Equal(t, OtDeviceRoleRouter, state.Role)
+	assert.Equal(t, "router", state.RoleName)
+	assert.Equal(t, uint16(0x0800), state.Rloc16)
+
+	// changes at the same time are compacted
+	assert.Equal(t, 4, len(d.NodeHistory(5, 0, Ever)))
+	states := d.NodeHistory(5, 2500000, 3000000)
+	assert.Equal(t, 2, len(states))
+	assert.Equal(t, uint64(2000000), states[0].Time)
+	assert.Equal(t, uint64(3000000), states[1].Time)
+
+	d.CurTime = 400000000
+	d.addTimelineEvent(TimelineNodeDelete, node.Id, "", "")
+	_, ok = d.NodeStateAt(5, 400000000)
+	assert.False(t, ok)
+	state, _ = d.NodeStateAt(5, 399999999)
+	assert.Equal(t, OtDeviceRoleRouter, state.Role)
+	states = d.NodeHistory(5, 400000000, Ever)
+	assert.Equal(t, 1, len(states))
+	assert.True(t, states[0].Deleted)
+	assert.Equal(t, threadconst.InvalidRloc16, states[0].Rloc16)
+
+	// the node history is kept when the timeline and role changes are reset
+	d.ResetTimeline()
+	d.ResetRoleChanges()
+	assert.Empty(t, d.Timeline())
+	assert.Empty(t, d.RoleChanges())
+	assert.Equal(t, 5, len(d.NodeHistory(5, 0, Ever)))
+
+	d.resetTimelines()
+	assert.Empty(t, d.NodeHistory(5, 0, Ever))
+}
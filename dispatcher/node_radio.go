@@ -0,0 +1,139 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+
+package dispatcher
+
+import (
+	"math"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// MinNodeRadioDbm and MaxNodeRadioDbm bound the per-node TX power, RX sensitivity and CCA threshold.
+	MinNodeRadioDbm = -127
+	MaxNodeRadioDbm = 127
+)
+
+// nodeRadioParams are the radio parameters of a node which override the link budget of the radio model. The
+// parameters which are not set use the radio model.
+type nodeRadioParams struct {
+	txPowerDbm       *float64 // TX power, or nil for the TxPowerDbm of the radio model
+	rxSensitivityDbm *float64 // minimum RSSI of received frames, or nil for none
+	ccaThresholdDbm  *float64 // RSSI at which the channel is busy, or nil for a channel which is always clear
+}
+
+// isDefault returns whether the node uses the link budget of the radio model.
+func (p *nodeRadioParams) isDefault() bool {
+	return p.txPowerDbm == nil && p.rxSensitivityDbm == nil
+}
+
+// rxSensitivity returns the RX sensitivity, or -Inf if not set.
+func (p *nodeRadioParams) rxSensitivity() float64 {
+	if p.rxSensitivityDbm != nil {
+		return *p.rxSensitivityDbm
+	}
+	return math.Inf(-1)
+}
+
+// ccaThreshold returns the CCA threshold, or +Inf if not set.
+func (p *nodeRadioParams) ccaThreshold() float64 {
+	if p.ccaThresholdDbm != nil {
+		return *p.ccaThresholdDbm
+	}
+	return math.Inf(1)
+}
+
+// NodeTxPowerDbm returns the TX power of the node, which is the TxPowerDbm of the radio model unless set for the node.
+func (d *Dispatcher) NodeTxPowerDbm(node *Node) float64 {
+	if node.radioParams.txPowerDbm != nil {
+		return *node.radioParams.txPowerDbm
+	}
+	return d.radioModel.TxPowerDbm
+}
+
+// SetNodeTxPower sets the TX power of the node in dBm.
+func (d *Dispatcher) SetNodeTxPower(id NodeId, dbm float64) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertTrue(dbm >= MinNodeRadioDbm && dbm <= MaxNodeRadioDbm)
+
+	node.radioParams.txPowerDbm = &dbm
+}
+
+// RxSensitivity returns the RX sensitivity of the node in dBm, or -Inf if the node receives every frame above the
+// noise floor by MinSnrDb of the radio model.
+func (node *Node) RxSensitivity() float64 {
+	return node.radioParams.rxSensitivity()
+}
+
+// SetNodeRxSensitivity sets the RX sensitivity of the node in dBm: the node only receives frames with an RSSI of at
+// least the sensitivity.
+func (d *Dispatcher) SetNodeRxSensitivity(id NodeId, dbm float64) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertTrue(dbm >= MinNodeRadioDbm && dbm <= MaxNodeRadioDbm)
+
+	node.radioParams.rxSensitivityDbm = &dbm
+}
+
+// CcaThreshold returns the CCA threshold of the node in dBm, or +Inf if the node does not check the channel.
+func (node *Node) CcaThreshold() float64 {
+	return node.radioParams.ccaThreshold()
+}
+
+// SetNodeCcaThreshold sets the CCA threshold of the node in dBm: the node does not transmit while a frame of another
+// node is on air on the channel with an RSSI at the node of at least the threshold.
+func (d *Dispatcher) SetNodeCcaThreshold(id NodeId, dbm float64) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	simplelogger.AssertTrue(dbm >= MinNodeRadioDbm && dbm <= MaxNodeRadioDbm)
+
+	node.radioParams.ccaThresholdDbm = &dbm
+}
+
+// checkCca returns whether the channel is clear at srcnode for its frame starting at the timestamp. Frames on air are
+// only tracked with the collision model (see CaptureParams), so the channel is always clear without it. A frame failing
+// CCA is not transmitted.
+func (d *Dispatcher) checkCca(srcnode *Node, channel uint8, timestamp uint64) bool {
+	if srcnode.radioParams.ccaThresholdDbm == nil {
+		return true
+	}
+	threshold := *srcnode.radioParams.ccaThresholdDbm
+
+	for _, f := range d.onAir {
+		if f.end <= timestamp || f.channel != channel || f.src == srcnode || d.nodes[f.src.Id] != f.src {
+			continue
+		}
+		if d.rssiDbm(f.src, srcnode, channel) >= threshold {
+			d.Counters.CcaBlockedFrames++
+			d.onFrameDropped(srcnode.Id, DropReasonCca)
+			return false
+		}
+	}
+	return true
+}
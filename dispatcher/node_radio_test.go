@@ -0,0 +1,81 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+
+package dispatcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeRadioParams(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.radioModel = DefaultRadioModelParams()
+	src, dst := d.nodes[1], d.nodes[2]
+	dst.X = 100 // 9dB margin with a radio range of 160
+
+	assert.Equal(t, 0.0, d.NodeTxPowerDbm(src))
+	assert.True(t, math.IsInf(dst.RxSensitivity(), -1))
+	assert.True(t, math.IsInf(src.CcaThreshold(), 1))
+	margin := d.linkMarginDb(src, dst, 11)
+	assert.InDelta(t, 6.1, margin, 0.1)
+	assert.InDelta(t, -95+margin, d.rssiDbm(src, dst, 11), 1e-9)
+
+	// the RX sensitivity only matters above the noise floor plus MinSnrDb
+	d.SetNodeRxSensitivity(dst.Id, -100)
+	assert.InDelta(t, margin, d.linkMarginDb(src, dst, 11), 1e-9)
+	d.SetNodeRxSensitivity(dst.Id, -85)
+	assert.False(t, d.checkRadioReachable(src, dst, 11))
+	assert.True(t, d.checkRadioReachable(dst, src, 11))
+
+	d.SetNodeTxPower(src.Id, 5)
+	assert.Equal(t, 5.0, d.NodeTxPowerDbm(src))
+	assert.InDelta(t, margin-5, d.linkMarginDb(src, dst, 11), 1e-9)
+	assert.True(t, d.checkRadioReachable(src, dst, 11))
+}
+
+func TestCheckCca(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.windowStats = newWindowStatsCollector(DefaultWindowStatsConfig(), 0)
+	d.radioModel = DefaultRadioModelParams()
+	setCapturePreset(d, CaptureCc2420)
+	src, other := d.nodes[1], d.nodes[2]
+	other.X = 100
+
+	d.startOnAir(other, 11, 1000, 10)
+	assert.True(t, d.checkCca(src, 11, 1100), "CCA disabled")
+
+	d.SetNodeCcaThreshold(src.Id, -80)
+	assert.True(t, d.checkCca(src, 11, 1100), "RSSI below the threshold")
+	assert.True(t, d.checkCca(src, 12, 1100), "other channel")
+
+	d.SetNodeCcaThreshold(src.Id, -90)
+	assert.False(t, d.checkCca(src, 11, 1100), "busy channel")
+	assert.True(t, d.checkCca(src, 11, 2000), "frame ended")
+	assert.Equal(t, uint64(1), d.Counters.CcaBlockedFrames)
+	assert.Equal(t, uint64(1), d.GetRunStats().Mac.Drops[DropReasonCca])
+}
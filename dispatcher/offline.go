@@ -0,0 +1,138 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/progctx"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// OfflineMessage is a message sent to a node by an offline dispatcher.
+type OfflineMessage struct {
+	Node       NodeId
+	Time       uint64 // simulation time of the message
+	RadioFrame bool   // if the message is a received radio frame, or the TX done notification of the sender
+	Radio      int    // radio index of the node which receives the radio frame
+	Type       uint8  // event type of other messages
+	Data       []byte // radio frame, starting with the channel, or the event data
+}
+
+// OfflineSink receives the messages sent to nodes by an offline dispatcher.
+type OfflineSink func(msg OfflineMessage)
+
+// offlineCallbackHandler ignores the callbacks of an offline dispatcher.
+type offlineCallbackHandler struct{}
+
+func (h offlineCallbackHandler) OnNodeFail(nodeid NodeId)               {}
+func (h offlineCallbackHandler) OnNodeRecover(nodeid NodeId)            {}
+func (h offlineCallbackHandler) OnUartWrite(nodeid NodeId, data []byte) {}
+func (h offlineCallbackHandler) GetNodeLogSeq(nodeid NodeId) uint64     { return 0 }
+
+// NewOfflineDispatcher creates a dispatcher which runs without a socket, node processes and capture files, so that
+// scripted events can be fed into the radio model in tests. Messages to nodes are passed to sink instead of being sent.
+// Nodes added to an offline dispatcher do not respond, so their addresses must be set with InjectStatusPush.
+func NewOfflineDispatcher(cfg *Config, sink OfflineSink) *Dispatcher {
+	simplelogger.AssertNotNil(sink)
+
+	offlineCfg := *cfg
+	offlineCfg.Real = false
+	offlineCfg.NoPcap = true
+	d := newDispatcher(progctx.New(nil), &offlineCfg, offlineCallbackHandler{})
+	d.offlineSink = sink
+	return d
+}
+
+// InjectRadioFrame injects a frame as if the node sent it on the radio at the current time. The frame starts with the
+// channel, followed by the PSDU.
+func (d *Dispatcher) InjectRadioFrame(id NodeId, radio int, frame []byte) {
+	simplelogger.AssertNotNil(d.offlineSink)
+	simplelogger.AssertTrue(len(frame) >= 4)
+
+	d.Counters.RadioEvents += 1
+	d.sendQueue.AddRadioFrame(d.CurTime+1, id, radio, frame)
+}
+
+// InjectStatusPush injects a status push of the node, e.g. "extaddr=0123456789abcdef;rloc16=1024".
+func (d *Dispatcher) InjectStatusPush(id NodeId, status string) {
+	simplelogger.AssertNotNil(d.offlineSink)
+
+	d.Counters.StatusPushEvents += 1
+	d.handleStatusPush(id, status)
+}
+
+// RunOffline dispatches the queued frames and runs the timers until the timestamp, and advances the time to it.
+func (d *Dispatcher) RunOffline(until uint64) {
+	simplelogger.AssertNotNil(d.offlineSink)
+
+	for {
+		nextSendTime := d.sendQueue.NextTimestamp()
+		nextTimerTime := d.timers.NextTimestamp()
+		if nextSendTime > until && nextTimerTime > until {
+			break
+		}
+
+		if nextTimerTime < nextSendTime {
+			d.advanceTime(nextTimerTime)
+			d.handleTimers()
+			continue
+		}
+
+		s := d.sendQueue.PopNext()
+		d.advanceTime(s.Timestamp)
+		d.sendNodeMessage(s)
+	}
+
+	if until > d.CurTime {
+		d.advanceTime(until)
+	}
+}
+
+func (d *Dispatcher) sendOfflineMessage(node *Node, msg []byte) {
+	var evt event
+	err := evt.Deserialize(msg)
+	simplelogger.PanicIfError(err)
+	simplelogger.AssertTrue(int(evt.DataLen) == len(evt.Data))
+
+	m := OfflineMessage{
+		Node: node.Id,
+		Time: d.CurTime,
+		Type: evt.Type,
+		Data: evt.Data,
+	}
+
+	switch evt.Type {
+	case eventTypeRadioReceived:
+		m.RadioFrame = true
+	case eventTypeRadioReceivedMulti:
+		m.RadioFrame = true
+		m.Radio = int(evt.Data[0])
+		m.Data = evt.Data[1:]
+	}
+	d.offlineSink(m)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	"github.com/openthread/ot-ns/dissectpkt"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// SetPcapNodes restricts the packet capture to the frames transmitted by or destined to the nodes, which need not
+// exist yet. Broadcast frames, and frames without destination address like ACKs, are destined to the nodes in radio
+// range of the sender. No nodes capture all frames.
+func (d *Dispatcher) SetPcapNodes(nodeids []NodeId) {
+	if len(nodeids) == 0 {
+		d.pcapNodes = nil
+		return
+	}
+
+	d.pcapNodes = map[NodeId]struct{}{}
+	for _, id := range nodeids {
+		d.pcapNodes[id] = struct{}{}
+	}
+}
+
+// GetPcapNodes returns the nodes the packet capture is restricted to in ascending order, or nil if all frames are
+// captured.
+func (d *Dispatcher) GetPcapNodes() []NodeId {
+	if d.pcapNodes == nil {
+		return nil
+	}
+
+	nodeids := make([]NodeId, 0, len(d.pcapNodes))
+	for id := range d.pcapNodes {
+		nodeids = append(nodeids, id)
+	}
+	sort.Ints(nodeids)
+	return nodeids
+}
+
+// pcapCaptures returns if the frame is captured by the node filter of the packet capture.
+func (d *Dispatcher) pcapCaptures(s *sendItem) bool {
+	if d.pcapNodes == nil {
+		return true
+	}
+	if _, ok := d.pcapNodes[s.NodeId]; ok {
+		return true
+	}
+
+	srcnode := d.nodes[s.NodeId]
+	if srcnode == nil {
+		return false
+	}
+
+	frame := dissectpkt.Dissect(s.Data).MacFrame
+	captured := false
+	if dstnode := d.findUnicastDstNode(frame); dstnode != nil {
+		_, captured = d.pcapNodes[dstnode.Id]
+	} else if dstMode := frame.FrameControl.DstAddrMode(); dstMode == wpan.DstAddrModeNone ||
+		(dstMode == wpan.DstAddrModeShort && frame.DstAddrShort == threadconst.BroadcastRloc16) {
+		// frames without destination address, e.g. ACKs, are handled like broadcast frames
+		captured = d.pcapCapturesBroadcast(srcnode, s.Data[0])
+	}
+
+	if !captured {
+		d.Counters.PcapFilteredFrames += 1
+	}
+	return captured
+}
+
+func (d *Dispatcher) pcapCapturesBroadcast(srcnode *Node, channel uint8) bool {
+	for id := range d.pcapNodes {
+		if dstnode := d.nodes[id]; dstnode != nil && dstnode != srcnode && d.checkRadioReachable(srcnode, dstnode, channel) {
+			return true
+		}
+	}
+	return false
+}
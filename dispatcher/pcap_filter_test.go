@@ -0,0 +1,80 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestPcapNodeFilter(t *testing.T) {
+	d := &Dispatcher{
+		nodes:      map[NodeId]*Node{},
+		extaddrMap: map[uint64]*Node{},
+		rloc16Map:  rloc16Map{},
+		radioModel: DefaultRadioModelParams(),
+	}
+	for id, x := range map[NodeId]int{1: 0, 2: 100, 3: 1000} {
+		d.nodes[id] = newNode(d, id, x, 0, 160)
+	}
+	d.rloc16Map.Add(0x0400, d.nodes[2])
+	d.rloc16Map.Add(0x0800, d.nodes[3])
+
+	unicast := func(src NodeId, dst uint16) *sendItem {
+		return &sendItem{NodeId: src, Data: []byte{11, 0x61, 0x88, 1, 0xce, 0xfa, byte(dst), byte(dst >> 8), 0x00, 0x08, 0, 0}}
+	}
+	broadcast := func(src NodeId) *sendItem {
+		return &sendItem{NodeId: src, Data: []byte{11, 0x41, 0x88, 1, 0xce, 0xfa, 0xff, 0xff, 0x00, 0x08, 0, 0}}
+	}
+	ack := func(src NodeId) *sendItem {
+		return &sendItem{NodeId: src, Data: []byte{11, 0x02, 0x00, 1, 0, 0}}
+	}
+
+	// all frames are captured without filter
+	assert.Nil(t, d.GetPcapNodes())
+	assert.True(t, d.pcapCaptures(unicast(1, 0x0800)))
+
+	d.SetPcapNodes([]NodeId{2, 9})
+	assert.Equal(t, []NodeId{2, 9}, d.GetPcapNodes())
+	assert.True(t, d.pcapCaptures(unicast(2, 0x0800)))
+	assert.True(t, d.pcapCaptures(unicast(1, 0x0400)))
+	assert.False(t, d.pcapCaptures(unicast(1, 0x0800)))
+	assert.False(t, d.pcapCaptures(unicast(1, 0x0c00)))
+	// broadcast frames and ACKs are captured if node 2 is in range
+	assert.True(t, d.pcapCaptures(broadcast(1)))
+	assert.False(t, d.pcapCaptures(broadcast(3)))
+	assert.True(t, d.pcapCaptures(ack(1)))
+	assert.False(t, d.pcapCaptures(ack(3)))
+	assert.Equal(t, uint64(4), d.Counters.PcapFilteredFrames)
+
+	d.SetPcapNodes(nil)
+	assert.Nil(t, d.GetPcapNodes())
+	assert.True(t, d.pcapCaptures(unicast(1, 0x0800)))
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/openthread/ot-ns/dissectpkt"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/openthread/ot-ns/pcap"
+	"github.com/openthread/ot-ns/threadconst"
+)
+
+const (
+	// lqiMaxMarginDb is the link margin mapped to the maximum LQI (255).
+	lqiMaxMarginDb = 40
+)
+
+// pcapFrameInfo returns the metadata of the frame to capture. The RSSI and LQI are given for unicast frames as received
+// by the destination node, and omitted for broadcast frames.
+func (d *Dispatcher) pcapFrameInfo(s *sendItem) pcap.FrameInfo {
+	info := pcap.FrameInfo{
+		Ustime:  s.Timestamp,
+		NodeId:  s.NodeId,
+		Channel: s.Data[0],
+	}
+
+	srcnode := d.nodes[s.NodeId]
+	if !d.cfg.PcapNg || srcnode == nil {
+		return info
+	}
+
+	info.ExtAddr = srcnode.ExtAddr
+	info.Comment = fmt.Sprintf("node %d at %d.%06ds", s.NodeId, s.Timestamp/1000000, s.Timestamp%1000000)
+	if d.cfg.LogCorrelation {
+		info.Comment += fmt.Sprintf(", log #%d", d.nodeLogSeq(s.NodeId))
+	}
+
+	dstnode := d.findUnicastDstNode(dissectpkt.Dissect(s.Data).MacFrame)
+	if dstnode == nil || dstnode == srcnode {
+		return info
+	}
+
+	margin := d.linkMarginDb(srcnode, dstnode, info.Channel)
+	rssi := d.rssiDbm(srcnode, dstnode, info.Channel)
+	if !math.IsInf(rssi, 0) {
+		info.HasRssi = true
+		info.Rssi = float32(rssi)
+		info.Lqi = uint8(math.Round(math.Max(0, math.Min(margin, lqiMaxMarginDb)) * 255 / lqiMaxMarginDb))
+	}
+	return info
+}
+
+// findUnicastDstNode returns the destination node of a unicast frame, or nil if the frame is broadcast or the node is
+// not found.
+func (d *Dispatcher) findUnicastDstNode(frame *wpan.MacFrame) *Node {
+	switch frame.FrameControl.DstAddrMode() {
+	case wpan.DstAddrModeExtended:
+		return d.extaddrMap[frame.DstAddrExtended]
+	case wpan.DstAddrModeShort:
+		if frame.DstAddrShort != threadconst.BroadcastRloc16 {
+			if dstnodes := d.rloc16Map[frame.DstAddrShort]; len(dstnodes) > 0 {
+				return dstnodes[0]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+//go:build otns_perf
+// +build otns_perf
+
+package dispatcher
+
+// PerfCountersEnabled tells if OTNS is built with the otns_perf tag, which enables the performance counters of the event
+// pipeline.
+const PerfCountersEnabled = true
+
+// perfCounters counts the operations of the event pipeline, to guide optimizations of large simulations.
+type perfCounters struct {
+	EventsReceived   uint64 // events received from nodes
+	MessagesSent     uint64 // messages sent to nodes
+	AlarmsProcessed  uint64
+	FramesDispatched uint64
+	LinkChecks       uint64 // radio model evaluations of links
+}
+
+func (pc *perfCounters) onEventReceived() {
+	pc.EventsReceived++
+}
+
+func (pc *perfCounters) onMessageSent() {
+	pc.MessagesSent++
+}
+
+func (pc *perfCounters) onAlarmProcessed() {
+	pc.AlarmsProcessed++
+}
+
+func (pc *perfCounters) onFrameDispatched() {
+	pc.FramesDispatched++
+}
+
+func (pc *perfCounters) onLinkCheck() {
+	pc.LinkChecks++
+}
+
+// counters returns the counters by name, prefixed with "Perf".
+func (pc *perfCounters) counters() map[string]uint64 {
+	return map[string]uint64{
+		"PerfEventsReceived":   pc.EventsReceived,
+		"PerfMessagesSent":     pc.MessagesSent,
+		"PerfAlarmsProcessed":  pc.AlarmsProcessed,
+		"PerfFramesDispatched": pc.FramesDispatched,
+		"PerfLinkChecks":       pc.LinkChecks,
+	}
+}
+
+func (pc *perfCounters) reset() {
+	*pc = perfCounters{}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	MinChannel = 11
+	MaxChannel = 26
+
+	defaultNoiseFloorDbm    = -95.0
+	defaultPathLossExponent = 3.0
+	defaultTxPowerDbm       = 0.0
+	defaultMinSnrDb         = 0.0
+	defaultMeterPerUnit     = 0.1
+
+	speedOfLight = 299792458.0 // m/s
+)
+
+type RadioModel string
+
+const (
+	// RadioModelDisc delivers frames within the radio range of the sender.
+	RadioModelDisc RadioModel = "disc"
+	// RadioModelLogDistance uses a log-distance path loss with exponent PathLossExponent.
+	RadioModelLogDistance RadioModel = "logdistance"
+	// RadioModelFriis uses the free-space Friis path loss.
+	RadioModelFriis RadioModel = "friis"
+)
+
+// ParseRadioModel parses the name of a built-in or registered radio model.
+func ParseRadioModel(s string) (RadioModel, error) {
+	model := RadioModel(s)
+	if isBuiltinRadioModel(model) || getRadioModelFactory(model) != nil {
+		return model, nil
+	}
+	return "", fmt.Errorf("unknown radio model: %s", s)
+}
+
+// RadioModelParams contains the parameters of the radio model.
+//
+// With the disc model, node radio ranges are calibrated against NoiseFloorDbm: a frame reaches every node within the
+// radio range of the sender. A channel with a higher noise floor shrinks the effective radio range on that channel by
+// the distance that would compensate the extra noise under a log-distance path loss with exponent PathLossExponent.
+//
+// The log-distance and Friis models ignore the radio ranges: a frame is received if its RSSI exceeds the noise floor by
+// at least MinSnrDb, where the RSSI is TxPowerDbm minus the path loss over the distance scaled by MeterPerUnit.
+// Registered models (see RegisterRadioModel) work the same with their own path loss.
+type RadioModelParams struct {
+	Model                RadioModel
+	NoiseFloorDbm        float64
+	ChannelNoiseFloorDbm map[uint8]float64
+	PathLossExponent     float64
+	TxPowerDbm           float64
+	MinSnrDb             float64
+	MeterPerUnit         float64
+
+	// Capture contains the collision and capture parameters of each radio model (see CaptureParams).
+	Capture map[RadioModel]CaptureParams
+
+	pathLoss PathLossModel // path loss of the selected registered model
+}
+
+func DefaultRadioModelParams() RadioModelParams {
+	return RadioModelParams{
+		Model:                RadioModelDisc,
+		NoiseFloorDbm:        defaultNoiseFloorDbm,
+		ChannelNoiseFloorDbm: map[uint8]float64{},
+		PathLossExponent:     defaultPathLossExponent,
+		TxPowerDbm:           defaultTxPowerDbm,
+		MinSnrDb:             defaultMinSnrDb,
+		MeterPerUnit:         defaultMeterPerUnit,
+		Capture:              map[RadioModel]CaptureParams{},
+	}
+}
+
+// GetNoiseFloorDbm returns the noise floor on the specified channel.
+func (p *RadioModelParams) GetNoiseFloorDbm(channel uint8) float64 {
+	if nf, ok := p.ChannelNoiseFloorDbm[channel]; ok {
+		return nf
+	}
+	return p.NoiseFloorDbm
+}
+
+func (p *RadioModelParams) clone() RadioModelParams {
+	c := *p
+	c.ChannelNoiseFloorDbm = make(map[uint8]float64, len(p.ChannelNoiseFloorDbm))
+	for ch, nf := range p.ChannelNoiseFloorDbm {
+		c.ChannelNoiseFloorDbm[ch] = nf
+	}
+	c.Capture = make(map[RadioModel]CaptureParams, len(p.Capture))
+	for model, capture := range p.Capture {
+		c.Capture[model] = capture
+	}
+	return c
+}
+
+// channelFrequency returns the center frequency (in Hz) of the 2.4 GHz O-QPSK channel.
+func channelFrequency(channel uint8) float64 {
+	return (2405 + 5*(float64(channel)-MinChannel)) * 1e6
+}
+
+// PathLossDb returns the path loss over the distance (in meters) on the specified channel.
+// The disc model has no path loss. Registered models are evaluated along the X axis.
+func (p *RadioModelParams) PathLossDb(meters float64, channel uint8) float64 {
+	if p.Model == RadioModelDisc {
+		return 0
+	}
+	if p.pathLoss != nil {
+		return p.pathLoss.PathLossDb(LinkEnd{}, LinkEnd{X: int(math.Round(meters / p.MeterPerUnit))}, meters, channel)
+	}
+
+	refLoss := refPathLossDb(channel)
+	if meters <= 1 {
+		return refLoss
+	}
+
+	return refLoss + 10*p.pathLossExponent()*math.Log10(meters)
+}
+
+// refPathLossDb returns the free-space path loss at the reference distance of 1 meter on the specified channel.
+func refPathLossDb(channel uint8) float64 {
+	wavelength := speedOfLight / channelFrequency(channel)
+	return 20 * math.Log10(4*math.Pi/wavelength)
+}
+
+// pathLossExponent returns the path loss exponent of the model. The disc model is calibrated against a log-distance
+// path loss.
+func (p *RadioModelParams) pathLossExponent() float64 {
+	if p.Model == RadioModelFriis {
+		return 2
+	}
+	return p.PathLossExponent
+}
+
+// DistanceForRssi returns the distance (in units) at which the RSSI of a frame sent with TxPowerDbm drops to rssiDbm on
+// the specified channel. The disc model uses the log-distance path loss with exponent PathLossExponent.
+func (p *RadioModelParams) DistanceForRssi(rssiDbm float64, channel uint8) float64 {
+	pathLoss := p.TxPowerDbm - rssiDbm
+	meters := math.Pow(10, (pathLoss-refPathLossDb(channel))/(10*p.pathLossExponent()))
+	return meters / p.MeterPerUnit
+}
+
+// MaxDistance returns the maximum distance (in units) of a usable link on the specified channel from a sender with the
+// radio range. The log-distance and Friis models ignore the radio range.
+func (p *RadioModelParams) MaxDistance(radioRange int, channel uint8) float64 {
+	if p.pathLoss != nil {
+		return p.customMaxDistance(channel)
+	}
+	if p.Model != RadioModelDisc {
+		return p.DistanceForRssi(p.GetNoiseFloorDbm(channel)+p.MinSnrDb, channel)
+	}
+
+	// the path loss margin over the shorter distance compensates the extra noise on the channel
+	noiseRise := p.GetNoiseFloorDbm(channel) - p.NoiseFloorDbm
+	return float64(radioRange) * math.Pow(10, -noiseRise/(10*p.PathLossExponent))
+}
+
+// linkMarginDb returns the margin (in dB) of a link of the given distance on the specified channel.
+// The link is usable if the margin is not negative.
+func (p *RadioModelParams) linkMarginDb(dist int, radioRange int, channel uint8) float64 {
+	if p.Model != RadioModelDisc {
+		rssi := p.TxPowerDbm - p.PathLossDb(float64(dist)*p.MeterPerUnit, channel)
+		return rssi - p.GetNoiseFloorDbm(channel) - p.MinSnrDb
+	}
+
+	if dist <= 0 {
+		return math.Inf(1)
+	} else if radioRange <= 0 {
+		return math.Inf(-1)
+	}
+
+	pathLossMargin := 10 * p.PathLossExponent * math.Log10(float64(radioRange)/float64(dist))
+	return pathLossMargin - (p.GetNoiseFloorDbm(channel) - p.NoiseFloorDbm)
+}
+
+// rssiDbm returns the RSSI of a frame of src at dst, including the antenna gains and the TX power of src. With the
+// disc model, the RSSI at the radio range of src is NoiseFloorDbm plus MinSnrDb.
+func (d *Dispatcher) rssiDbm(src *Node, dst *Node, channel uint8) float64 {
+	p := &d.radioModel
+	gain := src.antennaGainTo(dst) + dst.antennaGainTo(src) + d.NodeTxPowerDbm(src) - p.TxPowerDbm
+	if p.pathLoss != nil {
+		return p.TxPowerDbm - p.customPathLossDb(LinkEnd{src.Id, src.X, src.Y}, LinkEnd{dst.Id, dst.X, dst.Y}, channel) + gain
+	}
+	margin := p.linkMarginDb(src.GetDistanceTo(dst), src.radioRangeOn(channel), channel)
+	return margin + p.GetNoiseFloorDbm(channel) + p.MinSnrDb + gain
+}
+
+// linkMarginDb returns the margin (in dB) of the link from src to dst: the RSSI above the noise floor plus MinSnrDb,
+// or above the RX sensitivity of dst if higher.
+func (d *Dispatcher) linkMarginDb(src *Node, dst *Node, channel uint8) float64 {
+	p := &d.radioModel
+	threshold := math.Max(p.GetNoiseFloorDbm(channel)+p.MinSnrDb, dst.radioParams.rxSensitivity())
+	return d.rssiDbm(src, dst, channel) - threshold
+}
+
+func (d *Dispatcher) GetRadioModelParams() RadioModelParams {
+	return d.radioModel.clone()
+}
+
+func (d *Dispatcher) SetRadioModelParams(params RadioModelParams) {
+	params = params.clone()
+	params.selectPathLossModel(&d.radioModel)
+	d.radioModel = params
+}
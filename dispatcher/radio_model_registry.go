@@ -0,0 +1,170 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"sync"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// LinkEnd is a node at one end of a radio link.
+type LinkEnd struct {
+	Id   NodeId
+	X, Y int
+}
+
+// PathLossModel is the path loss of a custom radio model. As with the log-distance and Friis models, a frame is
+// received if its RSSI, i.e. TxPowerDbm plus antenna gains minus the path loss, is at least MinSnrDb above the noise
+// floor of the channel.
+type PathLossModel interface {
+	// PathLossDb returns the path loss (in dB) of the link from src to dst on the channel, where meters is the
+	// distance of the nodes scaled by MeterPerUnit.
+	PathLossDb(src, dst LinkEnd, meters float64, channel uint8) float64
+}
+
+// PathLossModelFactory creates the path loss model of a custom radio model when the model is selected.
+type PathLossModelFactory func() PathLossModel
+
+var (
+	radioModelsLock sync.Mutex
+	radioModels     = map[RadioModel]PathLossModelFactory{}
+
+	radioModelNamePat = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// RegisterRadioModel registers a custom radio model, so that it can be selected by its name like the built-in models,
+// e.g. with the `radiomodel <name>` command. The name must be an identifier which is not used by another model.
+func RegisterRadioModel(name string, factory PathLossModelFactory) error {
+	if factory == nil {
+		return fmt.Errorf("radio model %s: factory is nil", name)
+	}
+	if !radioModelNamePat.MatchString(name) {
+		return fmt.Errorf("invalid radio model name: %q", name)
+	}
+
+	radioModelsLock.Lock()
+	defer radioModelsLock.Unlock()
+
+	model := RadioModel(name)
+	if isBuiltinRadioModel(model) || radioModels[model] != nil {
+		return fmt.Errorf("radio model %s already exists", name)
+	}
+	radioModels[model] = factory
+	return nil
+}
+
+// RadioModels returns the names of the built-in radio models, followed by the registered models in alphabetical order.
+func RadioModels() []RadioModel {
+	radioModelsLock.Lock()
+	defer radioModelsLock.Unlock()
+
+	var custom []RadioModel
+	for model := range radioModels {
+		custom = append(custom, model)
+	}
+	sort.Slice(custom, func(i, j int) bool {
+		return custom[i] < custom[j]
+	})
+	return append([]RadioModel{RadioModelDisc, RadioModelLogDistance, RadioModelFriis}, custom...)
+}
+
+func isBuiltinRadioModel(model RadioModel) bool {
+	switch model {
+	case RadioModelDisc, RadioModelLogDistance, RadioModelFriis:
+		return true
+	default:
+		return false
+	}
+}
+
+func getRadioModelFactory(model RadioModel) PathLossModelFactory {
+	radioModelsLock.Lock()
+	defer radioModelsLock.Unlock()
+
+	return radioModels[model]
+}
+
+// customPathLossDb returns the path loss of the link with the custom radio model.
+func (p *RadioModelParams) customPathLossDb(src, dst LinkEnd, channel uint8) float64 {
+	dx, dy := float64(dst.X-src.X), float64(dst.Y-src.Y)
+	meters := math.Sqrt(dx*dx+dy*dy) * p.MeterPerUnit
+	return p.pathLoss.PathLossDb(src, dst, meters, channel)
+}
+
+// customMaxDistance returns the maximum distance (in units) of a usable link along the X axis with the custom radio
+// model, assuming the path loss does not decrease with the distance.
+func (p *RadioModelParams) customMaxDistance(channel uint8) float64 {
+	usable := func(dist int) bool {
+		rssi := p.TxPowerDbm - p.customPathLossDb(LinkEnd{}, LinkEnd{X: dist}, channel)
+		return rssi >= p.GetNoiseFloorDbm(channel)+p.MinSnrDb
+	}
+
+	if !usable(0) {
+		return 0
+	}
+
+	lo, hi := 0, 1
+	for usable(hi) {
+		lo, hi = hi, hi*2
+		if hi > 1<<30 {
+			return math.Inf(1)
+		}
+	}
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if usable(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return float64(lo)
+}
+
+// selectPathLossModel creates the path loss model of the custom radio model of params, unless it is already selected.
+func (p *RadioModelParams) selectPathLossModel(current *RadioModelParams) {
+	if isBuiltinRadioModel(p.Model) {
+		p.pathLoss = nil
+		return
+	}
+
+	if current.Model == p.Model && current.pathLoss != nil {
+		p.pathLoss = current.pathLoss
+		return
+	}
+
+	factory := getRadioModelFactory(p.Model)
+	if factory == nil {
+		panic(fmt.Errorf("unknown radio model: %s", p.Model))
+	}
+	p.pathLoss = factory()
+}
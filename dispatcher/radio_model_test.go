@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRadioModelParams(t *testing.T) {
+	params := DefaultRadioModelParams()
+	assert.Equal(t, defaultNoiseFloorDbm, params.GetNoiseFloorDbm(15))
+	assert.True(t, params.linkMarginDb(100, 100, 15) == 0)
+	assert.True(t, params.linkMarginDb(101, 100, 15) < 0)
+	assert.True(t, params.linkMarginDb(0, 0, 15) > 0)
+
+	// 10dB more noise with exponent 2 shrinks the radio range by a factor of sqrt(10)
+	params.PathLossExponent = 2
+	params.ChannelNoiseFloorDbm[15] = defaultNoiseFloorDbm + 10
+	assert.Equal(t, defaultNoiseFloorDbm+10, params.GetNoiseFloorDbm(15))
+	assert.True(t, params.linkMarginDb(100, 317, 15) > 0)
+	assert.True(t, params.linkMarginDb(100, 315, 15) < 0)
+	assert.True(t, params.linkMarginDb(100, 100, 11) == 0)
+	assert.InDelta(t, 316.2, params.MaxDistance(1000, 15), 0.1)
+	assert.Equal(t, 1000.0, params.MaxDistance(1000, 11))
+	// the disc model is calibrated with the log-distance path loss: 0dBm - 60.07dB at 10m
+	assert.InDelta(t, 100, params.DistanceForRssi(-60.07, 11), 0.1)
+
+	model, err := ParseRadioModel("friis")
+	assert.Nil(t, err)
+	params.Model = model
+	assert.InDelta(t, 40.07, params.PathLossDb(1, 11), 0.01)
+	assert.InDelta(t, 60.07, params.PathLossDb(10, 11), 0.01)
+	assert.True(t, params.PathLossDb(10, 26) > params.PathLossDb(10, 11))
+	// 0dBm - 80.07dB at 100m on channel 11 is 4.93dB above a noise floor of -85dBm
+	params.ChannelNoiseFloorDbm[11] = -85
+	assert.InDelta(t, 4.93, params.linkMarginDb(1000, 0, 11), 0.01)
+
+	params.Model = RadioModelLogDistance
+	params.PathLossExponent = 3.5
+	assert.InDelta(t, 75.07, params.PathLossDb(10, 11), 0.01)
+
+	// 0dBm - 75.07dB at 10m is -75.07dBm, and each unit is 0.1m
+	assert.InDelta(t, 100, params.DistanceForRssi(-75.07, 11), 0.1)
+	params.ChannelNoiseFloorDbm[11] = -75.07
+	params.MinSnrDb = 0
+	assert.InDelta(t, 100, params.MaxDistance(0, 11), 0.1)
+
+	_, err = ParseRadioModel("3gpp")
+	assert.NotNil(t, err)
+
+	c := params.clone()
+	c.ChannelNoiseFloorDbm[15] = 0
+	assert.Equal(t, defaultNoiseFloorDbm+10, params.GetNoiseFloorDbm(15))
+}
+
+type fixedPathLoss float64
+
+func (l fixedPathLoss) PathLossDb(src, dst LinkEnd, meters float64, channel uint8) float64 {
+	return float64(l) + meters
+}
+
+func TestRegisterRadioModel(t *testing.T) {
+	factory := func() PathLossModel {
+		return fixedPathLoss(80)
+	}
+	assert.Nil(t, RegisterRadioModel("fixed", factory))
+	assert.NotNil(t, RegisterRadioModel("fixed", factory))
+	assert.NotNil(t, RegisterRadioModel("friis", factory))
+	assert.NotNil(t, RegisterRadioModel("my model", factory))
+	assert.NotNil(t, RegisterRadioModel("other", nil))
+	assert.Equal(t, []RadioModel{RadioModelDisc, RadioModelLogDistance, RadioModelFriis, "fixed"}, RadioModels())
+
+	model, err := ParseRadioModel("fixed")
+	assert.Nil(t, err)
+
+	d := &Dispatcher{radioModel: DefaultRadioModelParams()}
+	params := d.GetRadioModelParams()
+	params.Model = model
+	d.SetRadioModelParams(params)
+	params = d.GetRadioModelParams()
+	assert.Equal(t, fixedPathLoss(80), params.pathLoss)
+	assert.InDelta(t, 81, params.PathLossDb(1, 11), 0.01)
+
+	// 0dBm - 80dB - 5m is 5dB above the noise floor of -95dBm plus a minimum SNR of 10dB
+	params.NoiseFloorDbm = -95
+	params.MinSnrDb = 10
+	params.MeterPerUnit = 0.1
+	assert.Equal(t, 50.0, params.MaxDistance(0, 11))
+
+	params.Model = RadioModelFriis
+	d.SetRadioModelParams(params)
+	assert.Nil(t, d.GetRadioModelParams().pathLoss)
+}
+
+// BenchmarkCheckRadioReachable measures the radio model evaluation of a link, which is done for each node in range of
+// each broadcast frame.
+func BenchmarkCheckRadioReachable(b *testing.B) {
+	for _, model := range []RadioModel{RadioModelDisc, RadioModelLogDistance, RadioModelFriis} {
+		b.Run(string(model), func(b *testing.B) {
+			d := &Dispatcher{radioModel: DefaultRadioModelParams()}
+			d.radioModel.Model = model
+			src := newNode(d, 1, 0, 0, 100)
+			dst := newNode(d, 2, 60, 80, 100)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = d.checkRadioReachable(src, dst, 11)
+			}
+		})
+	}
+}
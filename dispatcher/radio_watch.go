@@ -0,0 +1,111 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// RadioWatchLevel is the level of radio traces logged for a watched node. Radio traces are watched independently from
+// the events watched by WatchNode.
+type RadioWatchLevel int
+
+const (
+	RadioWatchOff   RadioWatchLevel = iota
+	RadioWatchInfo                  // frames transmitted, received and dropped by the dispatcher
+	RadioWatchTrace                 // radio traces of the node's platform (OT-RFSIM) in addition
+)
+
+var radioWatchLevelNames = []string{"off", "info", "trace"}
+
+func (l RadioWatchLevel) String() string {
+	if l < 0 || int(l) >= len(radioWatchLevelNames) {
+		return fmt.Sprintf("RadioWatchLevel(%d)", int(l))
+	}
+	return radioWatchLevelNames[l]
+}
+
+// ParseRadioWatchLevel parses a radio watch level name.
+func ParseRadioWatchLevel(s string) (RadioWatchLevel, error) {
+	for i, name := range radioWatchLevelNames {
+		if name == s {
+			return RadioWatchLevel(i), nil
+		}
+	}
+	return RadioWatchOff, fmt.Errorf("unknown radio watch level: %s", s)
+}
+
+// SetRadioWatchLevel sets the level of radio traces logged for the node.
+func (d *Dispatcher) SetRadioWatchLevel(nodeid NodeId, level RadioWatchLevel) {
+	if level == RadioWatchOff {
+		delete(d.radioWatchingNodes, nodeid)
+	} else {
+		d.radioWatchingNodes[nodeid] = level
+	}
+}
+
+// GetRadioWatchLevel returns the level of radio traces logged for the node.
+func (d *Dispatcher) GetRadioWatchLevel(nodeid NodeId) RadioWatchLevel {
+	return d.radioWatchingNodes[nodeid]
+}
+
+// WatchingNodes returns the sorted IDs of nodes whose events or radio traces are watched.
+func (d *Dispatcher) WatchingNodes() []NodeId {
+	var ids []NodeId
+	for id := range d.watchingNodes {
+		ids = append(ids, id)
+	}
+	for id := range d.radioWatchingNodes {
+		if !d.isWatching(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// IsWatching returns if the events of the node are watched.
+func (d *Dispatcher) IsWatching(nodeid NodeId) bool {
+	return d.isWatching(nodeid)
+}
+
+func (d *Dispatcher) isRadioWatching(nodeid NodeId, level RadioWatchLevel) bool {
+	return d.radioWatchingNodes[nodeid] >= level
+}
+
+func (d *Dispatcher) radioWatchf(nodeid NodeId, level RadioWatchLevel, format string, args ...interface{}) {
+	if d.isRadioWatching(nodeid, level) {
+		simplelogger.Warnf("Node %d radio: %s", nodeid, fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestParseRadioWatchLevel(t *testing.T) {
+	for _, level := range []RadioWatchLevel{RadioWatchOff, RadioWatchInfo, RadioWatchTrace} {
+		parsed, err := ParseRadioWatchLevel(level.String())
+		assert.Nil(t, err)
+		assert.Equal(t, level, parsed)
+	}
+
+	_, err := ParseRadioWatchLevel("debug")
+	assert.NotNil(t, err)
+}
+
+func TestRadioWatch(t *testing.T) {
+	d := &Dispatcher{
+		watchingNodes:      map[NodeId]struct{}{},
+		radioWatchingNodes: map[NodeId]RadioWatchLevel{},
+	}
+
+	d.WatchNode(3)
+	d.SetRadioWatchLevel(3, RadioWatchInfo)
+	d.SetRadioWatchLevel(1, RadioWatchTrace)
+	assert.Equal(t, []NodeId{1, 3}, d.WatchingNodes())
+	assert.False(t, d.IsWatching(1))
+	assert.True(t, d.isRadioWatching(1, RadioWatchTrace))
+	assert.True(t, d.isRadioWatching(3, RadioWatchInfo))
+	assert.False(t, d.isRadioWatching(3, RadioWatchTrace))
+	assert.False(t, d.isRadioWatching(2, RadioWatchInfo))
+
+	// radio traces are watched independently of events
+	d.SetRadioWatchLevel(3, RadioWatchOff)
+	assert.True(t, d.IsWatching(3))
+	assert.Equal(t, RadioWatchOff, d.GetRadioWatchLevel(3))
+	d.UnwatchNode(3)
+	assert.Equal(t, []NodeId{1}, d.WatchingNodes())
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// PrimaryRadio is the index of the primary radio of each node, which is used on all channels not covered by the
+	// additional radios of the node.
+	PrimaryRadio = 0
+)
+
+// RadioConfig contains the parameters of an additional radio of a node.
+type RadioConfig struct {
+	MinChannel uint8 // lowest channel of the radio
+	MaxChannel uint8 // highest channel of the radio
+	RadioRange int   // radio range of the radio, as the radio range of the primary radio
+}
+
+// Radio is an additional radio of a node, e.g. a radio on an alternative band or a second 802.15.4 radio on another
+// channel. Frames on the channels of an additional radio are transmitted and received by that radio, with its radio
+// range. Nodes with additional radios exchange frames with the dispatcher in eventTypeRadioReceivedMulti events, which
+// carry the radio index, so their platform must support such events.
+type Radio struct {
+	Index int
+	RadioConfig
+}
+
+func (r *Radio) hasChannel(channel uint8) bool {
+	return channel >= r.MinChannel && channel <= r.MaxChannel
+}
+
+// radioOn returns the additional radio of the node used on the channel, or nil if it is the primary radio.
+func (node *Node) radioOn(channel uint8) *Radio {
+	for _, r := range node.radios {
+		if r.hasChannel(channel) {
+			return r
+		}
+	}
+	return nil
+}
+
+// radioRangeOn returns the radio range of the node on the channel.
+func (node *Node) radioRangeOn(channel uint8) int {
+	if r := node.radioOn(channel); r != nil {
+		return r.RadioRange
+	}
+	return node.radioRange
+}
+
+// radioIndexOn returns the index of the radio of the node used on the channel.
+func (node *Node) radioIndexOn(channel uint8) int {
+	if r := node.radioOn(channel); r != nil {
+		return r.Index
+	}
+	return PrimaryRadio
+}
+
+// RadioRange returns the radio range of the primary radio of the node.
+func (node *Node) RadioRange() int {
+	return node.radioRange
+}
+
+// Radios returns the additional radios of the node in index order.
+func (node *Node) Radios() []*Radio {
+	return node.radios
+}
+
+// sendFrame sends a received frame, or the TX done notification of a transmitted frame, to the node through the radio.
+func (node *Node) sendFrame(elapsed uint64, radio int, data []byte) {
+	if len(node.radios) == 0 {
+		node.Send(elapsed, data)
+		return
+	}
+
+	evt := &event{Delay: elapsed, Type: eventTypeRadioReceivedMulti, Data: append([]byte{uint8(radio)}, data...)}
+	node.SendMessage(evt.Serialize())
+}
+
+// AddRadio adds an additional radio to the node and returns the radio. The channels of the radio must not overlap with
+// those of the other additional radios of the node.
+func (d *Dispatcher) AddRadio(id NodeId, cfg RadioConfig) (*Radio, error) {
+	node := d.nodes[id]
+	if node == nil {
+		return nil, errors.Errorf("node %d not found", id)
+	}
+
+	if cfg.MinChannel > cfg.MaxChannel {
+		return nil, errors.Errorf("invalid channels: %d-%d", cfg.MinChannel, cfg.MaxChannel)
+	}
+	if cfg.RadioRange < 0 {
+		return nil, errors.Errorf("invalid radio range: %d", cfg.RadioRange)
+	}
+
+	index := PrimaryRadio + 1
+	for _, r := range node.radios {
+		if cfg.MinChannel <= r.MaxChannel && cfg.MaxChannel >= r.MinChannel {
+			return nil, errors.Errorf("channels %d-%d overlap with radio %d", cfg.MinChannel, cfg.MaxChannel, r.Index)
+		}
+		if r.Index >= index {
+			index = r.Index + 1
+		}
+	}
+	if index > 0xff {
+		return nil, errors.Errorf("too many radios")
+	}
+
+	radio := &Radio{Index: index, RadioConfig: cfg}
+	node.radios = append(node.radios, radio)
+	sort.Slice(node.radios, func(i, j int) bool {
+		return node.radios[i].Index < node.radios[j].Index
+	})
+	return radio, nil
+}
+
+// DeleteRadio deletes the additional radio of the node. The indexes of the other radios are not changed.
+func (d *Dispatcher) DeleteRadio(id NodeId, index int) error {
+	node := d.nodes[id]
+	if node == nil {
+		return errors.Errorf("node %d not found", id)
+	}
+
+	for i, r := range node.radios {
+		if r.Index == index {
+			node.radios = append(node.radios[:i], node.radios[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("node %d has no radio %d", id, index)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func TestAddRadio(t *testing.T) {
+	d := &Dispatcher{
+		vis:       visualize.NewNopVisualizer(),
+		cbHandler: nopCallbackHandler{},
+	}
+	d.nodes = map[NodeId]*Node{1: newNode(d, 1, 0, 0, 100)}
+
+	r1, err := d.AddRadio(1, RadioConfig{MinChannel: 0, MaxChannel: 10, RadioRange: 500})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, r1.Index)
+	r2, err := d.AddRadio(1, RadioConfig{MinChannel: 15, MaxChannel: 15, RadioRange: 50})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, r2.Index)
+
+	_, err = d.AddRadio(1, RadioConfig{MinChannel: 10, MaxChannel: 11})
+	assert.NotNil(t, err)
+	_, err = d.AddRadio(1, RadioConfig{MinChannel: 20, MaxChannel: 19})
+	assert.NotNil(t, err)
+	_, err = d.AddRadio(2, RadioConfig{MinChannel: 20, MaxChannel: 20})
+	assert.NotNil(t, err)
+
+	node := d.nodes[1]
+	assert.Equal(t, 500, node.radioRangeOn(5))
+	assert.Equal(t, 1, node.radioIndexOn(5))
+	assert.Equal(t, 50, node.radioRangeOn(15))
+	assert.Equal(t, 2, node.radioIndexOn(15))
+	assert.Equal(t, 100, node.radioRangeOn(11))
+	assert.Equal(t, PrimaryRadio, node.radioIndexOn(11))
+
+	// indexes of the remaining radios are kept
+	assert.Nil(t, d.DeleteRadio(1, 1))
+	assert.NotNil(t, d.DeleteRadio(1, 1))
+	assert.Equal(t, []*Radio{r2}, node.Radios())
+	assert.Equal(t, 100, node.radioRangeOn(5))
+	r3, err := d.AddRadio(1, RadioConfig{MinChannel: 0, MaxChannel: 10, RadioRange: 500})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, r3.Index)
+}
+
+func TestMultiRadioReachable(t *testing.T) {
+	d := &Dispatcher{
+		vis:        visualize.NewNopVisualizer(),
+		cbHandler:  nopCallbackHandler{},
+		radioModel: DefaultRadioModelParams(),
+		sendQueue:  newSendQueue(),
+	}
+	d.nodes = map[NodeId]*Node{1: newNode(d, 1, 0, 0, 100), 2: newNode(d, 2, 300, 0, 100)}
+	_, err := d.AddRadio(1, RadioConfig{MinChannel: 0, MaxChannel: 10, RadioRange: 500})
+	assert.Nil(t, err)
+
+	src, dst := d.nodes[1], d.nodes[2]
+	assert.True(t, d.checkRadioReachable(src, dst, 5))
+	assert.False(t, d.checkRadioReachable(src, dst, 11))
+	assert.False(t, d.checkRadioReachable(dst, src, 5))
+
+	// frames of additional radios carry the radio index
+	d.handleRecvEvent(&event{NodeId: 1, Type: eventTypeRadioReceivedMulti, Data: []byte{1, 5, 0x01, 0x02}})
+	d.handleRecvEvent(&event{NodeId: 1, Type: eventTypeRadioReceivedMulti, Data: []byte{1}})
+	d.handleRecvEvent(&event{NodeId: 1, Type: eventTypeRadioReceived, Data: []byte{11, 0x01, 0x02}})
+	assert.Equal(t, 2, d.sendQueue.Len())
+	assert.Equal(t, uint64(3), d.Counters.RadioEvents)
+	radios := map[int][]byte{}
+	for d.sendQueue.Len() > 0 {
+		sit := d.sendQueue.PopNext()
+		radios[sit.Radio] = sit.Data
+	}
+	assert.Equal(t, map[int][]byte{1: {5, 0x01, 0x02}, PrimaryRadio: {11, 0x01, 0x02}}, radios)
+}
+
+func TestMultiRadioJammer(t *testing.T) {
+	d := &Dispatcher{
+		vis:        visualize.NewNopVisualizer(),
+		cbHandler:  nopCallbackHandler{},
+		radioModel: DefaultRadioModelParams(),
+		jammers:    map[NodeId]*Node{},
+	}
+	d.nodes = map[NodeId]*Node{
+		1: newNode(d, 1, 0, 0, 100),
+		2: newNode(d, 2, 300, 0, 100),
+		3: newNode(d, 3, 600, 0, 100),
+	}
+	src, jammer, dst := d.nodes[1], d.nodes[2], d.nodes[3]
+	_, err := d.AddRadio(2, RadioConfig{MinChannel: 15, MaxChannel: 15, RadioRange: 500})
+	assert.Nil(t, err)
+	d.SetNodeJammer(2, &JamFilter{})
+
+	// the primary radio of the jammer is out of range
+	assert.Empty(t, d.findJammers(src, &wpan.MacFrame{Channel: 11}))
+	assert.False(t, d.isJammed([]*Node{jammer}, dst, 11))
+
+	// the additional radio of the jammer reaches the receiver, and is reached by senders with the same range
+	_, err = d.AddRadio(1, RadioConfig{MinChannel: 15, MaxChannel: 15, RadioRange: 500})
+	assert.Nil(t, err)
+	jammers := d.findJammers(src, &wpan.MacFrame{Channel: 15})
+	assert.Equal(t, []*Node{jammer}, jammers)
+	assert.True(t, d.isJammed(jammers, dst, 15))
+	assert.False(t, d.isJammed(jammers, dst, 11))
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+// Package radiotest feeds scripted event sequences into the radio model of an offline dispatcher, without node
+// processes, and records the frames delivered to the nodes, so that radio models can be developed and
+// regression-tested in unit tests.
+//
+// A script is a list of steps, each applying an action at a simulation time:
+//
+//	h := radiotest.New(dispatcher.DefaultRadioModelParams())
+//	h.AddNode(1, 0, 0, 100)
+//	h.AddNode(2, 50, 0, 100)
+//	deliveries := h.Run([]radiotest.Step{
+//		{At: 1000, Action: radiotest.Tx{Node: 1, Channel: 11, Dst: radiotest.Broadcast}},
+//		{At: 2000, Action: radiotest.Move{Node: 2, X: 500, Y: 0}},
+//		{At: 3000, Action: radiotest.Tx{Node: 1, Channel: 11, Dst: radiotest.Broadcast}},
+//	}, 10000)
+//
+// As with node processes, a frame sent at time T is delivered at T+1.
+package radiotest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// Broadcast is the destination of broadcast frames.
+	Broadcast = -1
+
+	panId = 0xface
+)
+
+// Step applies the action at the simulation time.
+type Step struct {
+	At     uint64
+	Action Action
+}
+
+// Action is an action of a script.
+type Action interface {
+	apply(h *Harness, step int)
+}
+
+// Tx sends a data frame from the node on the channel to the destination node, or to all nodes if Dst is Broadcast.
+// If Short is set, the destination is addressed by its RLOC16 instead of its extended address.
+type Tx struct {
+	Node    NodeId
+	Radio   int // radio index of the sender
+	Channel uint8
+	Dst     NodeId
+	Short   bool
+	Payload int // payload length
+}
+
+func (tx Tx) apply(h *Harness, step int) {
+	h.d.InjectRadioFrame(tx.Node, tx.Radio, buildFrame(tx, step))
+	h.txs[step] = tx
+}
+
+// Move moves the node to the position.
+type Move struct {
+	Node NodeId
+	X, Y int
+}
+
+func (m Move) apply(h *Harness, step int) {
+	h.d.SetNodePos(m.Node, m.X, m.Y)
+}
+
+// SetFailed fails or recovers the node radio.
+type SetFailed struct {
+	Node   NodeId
+	Failed bool
+}
+
+func (f SetFailed) apply(h *Harness, step int) {
+	h.d.SetNodeFailed(f.Node, f.Failed)
+}
+
+// SetParams sets the radio model parameters.
+type SetParams struct {
+	Params dispatcher.RadioModelParams
+}
+
+func (p SetParams) apply(h *Harness, step int) {
+	h.d.SetRadioModelParams(p.Params)
+}
+
+// Do runs the function with the dispatcher, for actions not covered by the other action types.
+type Do func(d *dispatcher.Dispatcher)
+
+func (f Do) apply(h *Harness, step int) {
+	f(h.d)
+}
+
+// Delivery is a frame delivered to a node.
+type Delivery struct {
+	Time    uint64
+	Step    int // index of the Tx step of the frame in the script
+	Src     NodeId
+	Dst     NodeId
+	Radio   int // radio index of the receiver
+	Channel uint8
+	TxDone  bool // if the delivery is the TX done notification of the sender
+}
+
+func (dv Delivery) String() string {
+	if dv.TxDone {
+		return fmt.Sprintf("%d: step %d: node %d TX done", dv.Time, dv.Step, dv.Src)
+	}
+	return fmt.Sprintf("%d: step %d: node %d -> node %d on ch%d radio %d", dv.Time, dv.Step, dv.Src, dv.Dst,
+		dv.Channel, dv.Radio)
+}
+
+// Harness runs scripts against the radio model of an offline dispatcher.
+type Harness struct {
+	d          *dispatcher.Dispatcher
+	txs        map[int]Tx
+	deliveries []Delivery
+	others     []dispatcher.OfflineMessage
+}
+
+// New creates a harness with the radio model parameters.
+func New(params dispatcher.RadioModelParams) *Harness {
+	h := &Harness{
+		txs: map[int]Tx{},
+	}
+	cfg := dispatcher.DefaultConfig()
+	cfg.Speed = dispatcher.MaxSimulateSpeed
+	h.d = dispatcher.NewOfflineDispatcher(cfg, h.onMessage)
+	h.d.SetRadioModelParams(params)
+	return h
+}
+
+// Dispatcher returns the offline dispatcher, e.g. to add radios, antennas or jammers to nodes.
+func (h *Harness) Dispatcher() *dispatcher.Dispatcher {
+	return h.d
+}
+
+// ExtAddr returns the extended address of the node.
+func ExtAddr(id NodeId) uint64 {
+	return 0x1000000000000000 | uint64(id)
+}
+
+// Rloc16 returns the RLOC16 of the node.
+func Rloc16(id NodeId) uint16 {
+	return uint16(id) << 10
+}
+
+// AddNode adds a node at the position with the radio range.
+func (h *Harness) AddNode(id NodeId, x, y int, radioRange int) {
+	h.d.AddNode(id, x, y, radioRange)
+	h.d.InjectStatusPush(id, fmt.Sprintf("extaddr=%016x;rloc16=%d", ExtAddr(id), Rloc16(id)))
+}
+
+// Run runs the steps of the script ordered by time, and then runs until the timestamp. It returns the frames
+// delivered by the script in the order of delivery.
+func (h *Harness) Run(script []Step, until uint64) []Delivery {
+	order := make([]int, len(script))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return script[order[i]].At < script[order[j]].At
+	})
+
+	start := len(h.deliveries)
+	for _, i := range order {
+		step := script[i]
+		if step.At < h.d.CurTime {
+			panic(fmt.Sprintf("step %d at %d is in the past", i, step.At))
+		}
+		h.d.RunOffline(step.At)
+		step.Action.apply(h, i)
+	}
+	h.d.RunOffline(until)
+
+	return append([]Delivery(nil), h.deliveries[start:]...)
+}
+
+// Deliveries returns all frames delivered so far.
+func (h *Harness) Deliveries() []Delivery {
+	return h.deliveries
+}
+
+// OtherMessages returns the messages other than radio frames sent to nodes so far, e.g. ranging results.
+func (h *Harness) OtherMessages() []dispatcher.OfflineMessage {
+	return h.others
+}
+
+// Receivers returns the nodes which received the frame of the Tx step, ordered by node ID.
+func Receivers(deliveries []Delivery, step int) []NodeId {
+	var ids []NodeId
+	for _, dv := range deliveries {
+		if dv.Step == step && !dv.TxDone {
+			ids = append(ids, dv.Dst)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (h *Harness) onMessage(msg dispatcher.OfflineMessage) {
+	if !msg.RadioFrame {
+		h.others = append(h.others, msg)
+		return
+	}
+
+	step := parseFrameStep(msg.Data)
+	tx := h.txs[step]
+	h.deliveries = append(h.deliveries, Delivery{
+		Time:    msg.Time,
+		Step:    step,
+		Src:     tx.Node,
+		Dst:     msg.Node,
+		Radio:   msg.Radio,
+		Channel: msg.Data[0],
+		TxDone:  msg.Node == tx.Node,
+	})
+}
+
+// buildFrame builds a data frame of the Tx step, starting with the channel. The step is carried at the end of the
+// payload.
+func buildFrame(tx Tx, step int) []byte {
+	fc := uint16(0x0001 | 0x0040 | 0x1000 | 0xc000) // data frame, PAN ID compression, 2006, extended source
+	var dst []byte
+	if tx.Dst == Broadcast {
+		fc |= 0x0800 // short destination
+		dst = make([]byte, 2)
+		binary.LittleEndian.PutUint16(dst, threadconst.BroadcastRloc16)
+	} else if tx.Short {
+		fc |= 0x0800 | 0x0020 // short destination, ACK request
+		dst = make([]byte, 2)
+		binary.LittleEndian.PutUint16(dst, Rloc16(tx.Dst))
+	} else {
+		fc |= 0x0c00 | 0x0020 // extended destination, ACK request
+		dst = make([]byte, 8)
+		binary.LittleEndian.PutUint64(dst, ExtAddr(tx.Dst))
+	}
+
+	frame := []byte{tx.Channel, 0, 0, uint8(step)}
+	binary.LittleEndian.PutUint16(frame[1:3], fc)
+	frame = append(frame, 0, 0)
+	binary.LittleEndian.PutUint16(frame[4:6], panId)
+	frame = append(frame, dst...)
+	src := make([]byte, 8)
+	binary.LittleEndian.PutUint64(src, ExtAddr(tx.Node))
+	frame = append(frame, src...)
+	frame = append(frame, make([]byte, tx.Payload)...)
+	stepBytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(stepBytes, uint32(step))
+	frame = append(frame, stepBytes...)
+	return append(frame, 0, 0) // FCS
+}
+
+// parseFrameStep returns the Tx step carried by the frame.
+func parseFrameStep(frame []byte) int {
+	return int(binary.LittleEndian.Uint32(frame[len(frame)-6 : len(frame)-2]))
+}
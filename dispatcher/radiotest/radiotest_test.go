@@ -0,0 +1,145 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package radiotest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestDiscModel(t *testing.T) {
+	h := New(dispatcher.DefaultRadioModelParams())
+	h.AddNode(1, 0, 0, 100)
+	h.AddNode(2, 50, 0, 100)
+	h.AddNode(3, 150, 0, 100)
+
+	deliveries := h.Run([]Step{
+		{At: 1000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+		{At: 2000, Action: Move{Node: 3, X: 80, Y: 0}},
+		{At: 3000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+		{At: 4000, Action: Tx{Node: 1, Channel: 11, Dst: 3}},
+		{At: 5000, Action: Tx{Node: 1, Channel: 11, Dst: 2, Short: true}},
+		{At: 6000, Action: SetFailed{Node: 2, Failed: true}},
+		{At: 7000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+	}, 10000)
+
+	assert.Equal(t, []NodeId{2}, Receivers(deliveries, 0))
+	assert.Equal(t, []NodeId{2, 3}, Receivers(deliveries, 2))
+	assert.Equal(t, []NodeId{3}, Receivers(deliveries, 3))
+	assert.Equal(t, []NodeId{2}, Receivers(deliveries, 4))
+	assert.Equal(t, []NodeId{3}, Receivers(deliveries, 6))
+
+	// frames are delivered one microsecond after they are sent, together with the TX done notification
+	assert.Equal(t, Delivery{Time: 1001, Step: 0, Src: 1, Dst: 1, Channel: 11, TxDone: true}, deliveries[0])
+	assert.Equal(t, Delivery{Time: 1001, Step: 0, Src: 1, Dst: 2, Channel: 11}, deliveries[1])
+	assert.Equal(t, uint64(10000), h.Dispatcher().CurTime)
+	assert.Equal(t, uint64(1), h.Dispatcher().GetRunStats().Mac.Drops[dispatcher.DropReasonDown])
+	assert.Equal(t, deliveries, h.Deliveries())
+}
+
+func TestLogDistanceModel(t *testing.T) {
+	params := dispatcher.DefaultRadioModelParams()
+	h := New(params)
+	params.Model = dispatcher.RadioModelLogDistance
+	maxDist := int(params.MaxDistance(0, 11))
+
+	// the radio range is ignored by the log-distance model
+	h.AddNode(1, 0, 0, 1)
+	h.AddNode(2, maxDist-1, 0, 1)
+	h.AddNode(3, maxDist+1, 0, 1)
+
+	deliveries := h.Run([]Step{
+		{At: 1000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+		{At: 2000, Action: SetParams{Params: params}},
+		{At: 3000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+		{At: 4000, Action: Do(func(d *dispatcher.Dispatcher) {
+			params.NoiseFloorDbm += 10
+			d.SetRadioModelParams(params)
+		})},
+		{At: 5000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+	}, 10000)
+
+	assert.Empty(t, Receivers(deliveries, 0))
+	assert.Equal(t, []NodeId{2}, Receivers(deliveries, 2))
+	assert.Empty(t, Receivers(deliveries, 4))
+}
+
+func TestRadios(t *testing.T) {
+	h := New(dispatcher.DefaultRadioModelParams())
+	h.AddNode(1, 0, 0, 100)
+	h.AddNode(2, 50, 0, 100)
+	_, err := h.Dispatcher().AddRadio(2, dispatcher.RadioConfig{MinChannel: 20, MaxChannel: 20, RadioRange: 100})
+	assert.Nil(t, err)
+
+	deliveries := h.Run([]Step{
+		{At: 1000, Action: Tx{Node: 1, Channel: 20, Dst: 2}},
+		{At: 2000, Action: Tx{Node: 2, Radio: 1, Channel: 20, Dst: 1}},
+	}, 3000)
+
+	assert.Equal(t, []Delivery{
+		{Time: 1001, Step: 0, Src: 1, Dst: 1, Channel: 20, TxDone: true},
+		{Time: 1001, Step: 0, Src: 1, Dst: 2, Radio: 1, Channel: 20},
+		{Time: 2001, Step: 1, Src: 2, Dst: 2, Radio: 1, Channel: 20, TxDone: true},
+		{Time: 2001, Step: 1, Src: 2, Dst: 1, Channel: 20},
+	}, deliveries)
+}
+
+// wallModel blocks the links crossing the line x = 100.
+type wallModel struct{}
+
+func (wallModel) PathLossDb(src, dst dispatcher.LinkEnd, meters float64, channel uint8) float64 {
+	if (src.X < 100) != (dst.X < 100) {
+		return 1000
+	}
+	return 0
+}
+
+func TestCustomModel(t *testing.T) {
+	assert.Nil(t, dispatcher.RegisterRadioModel("wall", func() dispatcher.PathLossModel {
+		return wallModel{}
+	}))
+
+	params := dispatcher.DefaultRadioModelParams()
+	params.Model = "wall"
+	h := New(params)
+	h.AddNode(1, 0, 0, 1)
+	h.AddNode(2, 90, 0, 1)
+	h.AddNode(3, 110, 0, 1)
+
+	deliveries := h.Run([]Step{
+		{At: 1000, Action: Tx{Node: 1, Channel: 11, Dst: Broadcast}},
+		{At: 2000, Action: Move{Node: 2, X: 200, Y: 0}},
+		{At: 3000, Action: Tx{Node: 2, Channel: 11, Dst: Broadcast}},
+	}, 10000)
+
+	assert.Equal(t, []NodeId{2}, Receivers(deliveries, 0))
+	assert.Equal(t, []NodeId{3}, Receivers(deliveries, 2))
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// rangingEventDataLen is the length of the ranging event data: the extended address of the peer (8 bytes) and the
+	// measured distance as a float64 in simulation distance units (8 bytes), both little endian.
+	rangingEventDataLen = 16
+)
+
+// RangingErrorModel is the error model of simulated time-of-flight ranging.
+// A measured distance is the true distance plus Bias plus a Gaussian error with standard deviation StdDev, clamped to
+// be non-negative. Both are in simulation distance units.
+type RangingErrorModel struct {
+	Bias   float64
+	StdDev float64
+}
+
+// RangingMeasurement is the result of a simulated ranging between two nodes. Distances are in simulation distance
+// units and Tof is the time of flight of the measured distance in ns, scaled by the MeterPerUnit of the radio model.
+type RangingMeasurement struct {
+	Time     uint64  `json:"time_us"`
+	Src      NodeId  `json:"src"`
+	Dst      NodeId  `json:"dst"`
+	Distance float64 `json:"distance"`
+	Measured float64 `json:"measured"`
+	Tof      float64 `json:"tof_ns"`
+}
+
+// Error returns the error of the measured distance.
+func (m *RangingMeasurement) Error() float64 {
+	return m.Measured - m.Distance
+}
+
+// measure returns a measured distance of the true distance.
+func (em RangingErrorModel) measure(dist float64) float64 {
+	measured := dist + em.Bias
+	if em.StdDev > 0 {
+		measured += rand.NormFloat64() * em.StdDev
+	}
+	if measured < 0 {
+		measured = 0
+	}
+	return measured
+}
+
+// GetRangingErrorModel returns the error model of ranging measurements.
+func (d *Dispatcher) GetRangingErrorModel() RangingErrorModel {
+	return d.rangingErrorModel
+}
+
+// SetRangingErrorModel sets the error model of ranging measurements.
+func (d *Dispatcher) SetRangingErrorModel(em RangingErrorModel) {
+	simplelogger.AssertTrue(em.StdDev >= 0)
+	d.rangingErrorModel = em
+}
+
+// SetRangingEvents sets if the node supports ranging events. Ranging results are only sent to nodes supporting them,
+// because other nodes do not understand the event type.
+func (d *Dispatcher) SetRangingEvents(id NodeId, enabled bool) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	node.rangingEvents = enabled
+}
+
+// RangingEvents returns if the node supports ranging events.
+func (node *Node) RangingEvents() bool {
+	return node.rangingEvents
+}
+
+// Ranging measures the distance from node src to node dst on the channel. Both nodes must be up and dst must be
+// reachable by the radio of src. The result is sent to src if it supports ranging events.
+func (d *Dispatcher) Ranging(src, dst NodeId, channel uint8) (*RangingMeasurement, error) {
+	if src == dst {
+		return nil, errors.Errorf("can not range node %d to itself", src)
+	}
+
+	srcnode, dstnode := d.nodes[src], d.nodes[dst]
+	if srcnode == nil {
+		return nil, errors.Errorf("node %d not found", src)
+	}
+	if dstnode == nil {
+		return nil, errors.Errorf("node %d not found", dst)
+	}
+	for _, node := range []*Node{srcnode, dstnode} {
+		if node.isFailed || node.isPaused {
+			return nil, errors.Errorf("node %d is down", node.Id)
+		}
+	}
+	if !d.checkRadioReachable(srcnode, dstnode, channel) {
+		return nil, errors.Errorf("node %d is out of range of node %d", dst, src)
+	}
+
+	dx, dy := float64(dstnode.X-srcnode.X), float64(dstnode.Y-srcnode.Y)
+	dist := math.Sqrt(dx*dx + dy*dy)
+	m := &RangingMeasurement{
+		Time:     d.CurTime,
+		Src:      src,
+		Dst:      dst,
+		Distance: dist,
+		Measured: d.rangingErrorModel.measure(dist),
+	}
+	m.Tof = m.Measured * d.radioModel.MeterPerUnit / speedOfLight * 1e9
+
+	if srcnode.rangingEvents {
+		data := make([]byte, rangingEventDataLen)
+		binary.LittleEndian.PutUint64(data[:8], dstnode.ExtAddr)
+		binary.LittleEndian.PutUint64(data[8:], math.Float64bits(m.Measured))
+		d.sendEvent(srcnode, eventTypeRangingResult, data)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestRangingErrorModel(t *testing.T) {
+	assert.Equal(t, 100.0, RangingErrorModel{}.measure(100))
+	assert.Equal(t, 105.0, RangingErrorModel{Bias: 5}.measure(100))
+	assert.Equal(t, 0.0, RangingErrorModel{Bias: -20}.measure(10))
+
+	em := RangingErrorModel{StdDev: 2}
+	sum := 0.0
+	for i := 0; i < 1000; i++ {
+		measured := em.measure(100)
+		assert.True(t, measured >= 0)
+		sum += measured
+	}
+	assert.InDelta(t, 100.0, sum/1000, 0.5)
+}
+
+func TestRanging(t *testing.T) {
+	d := &Dispatcher{
+		nodes:      map[NodeId]*Node{},
+		radioModel: DefaultRadioModelParams(),
+	}
+	d.nodes[1] = newNode(d, 1, 0, 0, 160)
+	d.nodes[2] = newNode(d, 2, 30, 40, 160)
+	d.nodes[3] = newNode(d, 3, 300, 0, 160)
+	d.CurTime = 1000000
+
+	m, err := d.Ranging(1, 2, MinChannel)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1000000), m.Time)
+	assert.Equal(t, 50.0, m.Distance)
+	assert.Equal(t, 50.0, m.Measured)
+	assert.Equal(t, 0.0, m.Error())
+	assert.InDelta(t, 16.68, m.Tof, 0.01)
+
+	d.SetRangingErrorModel(RangingErrorModel{Bias: 1.5})
+	m, err = d.Ranging(2, 1, MinChannel)
+	assert.Nil(t, err)
+	assert.Equal(t, 51.5, m.Measured)
+	assert.Equal(t, 1.5, m.Error())
+
+	_, err = d.Ranging(1, 1, MinChannel)
+	assert.NotNil(t, err)
+	_, err = d.Ranging(1, 4, MinChannel)
+	assert.NotNil(t, err)
+	_, err = d.Ranging(1, 3, MinChannel)
+	assert.NotNil(t, err)
+
+	d.nodes[2].isFailed = true
+	_, err = d.Ranging(1, 2, MinChannel)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+)
+
+// ResourceUsage is the resource usage of a node process.
+type ResourceUsage struct {
+	RssBytes     uint64 `json:"rss_bytes"`      // resident set size
+	PeakRssBytes uint64 `json:"peak_rss_bytes"` // peak resident set size
+	CpuTime      uint64 `json:"cpu_us"`         // user and system CPU time
+}
+
+// onResourceUsage accounts a new resource usage sample of a node to the KPI: the RSS is the latest sample, the peak RSS
+// is the maximum sample and the CPU time is the CPU time used since KPI started.
+func (kc *kpiCollector) onResourceUsage(id NodeId, prev, usage ResourceUsage) {
+	if !kc.running {
+		return
+	}
+
+	r := kc.resources[id]
+	if r == nil {
+		r = &ResourceUsage{}
+		kc.resources[id] = r
+	}
+
+	r.RssBytes = usage.RssBytes
+	if usage.RssBytes > r.PeakRssBytes {
+		r.PeakRssBytes = usage.RssBytes
+	}
+	if usage.CpuTime >= prev.CpuTime {
+		r.CpuTime += usage.CpuTime - prev.CpuTime
+	} else {
+		// the node process was restarted
+		r.CpuTime += usage.CpuTime
+	}
+}
+
+// SetResourceUsage records a sample of the resource usage of the node process.
+func (d *Dispatcher) SetResourceUsage(id NodeId, usage ResourceUsage) {
+	node := d.nodes[id]
+	if node == nil {
+		return
+	}
+
+	prev := node.resourceUsage
+	node.resourceUsage = usage
+	d.kpi.onResourceUsage(id, prev, usage)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// MaxRfSimRawLen is the maximum data length of raw OT-RFSIM platform events.
+	MaxRfSimRawLen = maxBatchLen - eventHeaderLen
+)
+
+// RfSimRawEvent is a raw OT-RFSIM platform event received from a node without being requested by SendRfSimRaw.
+type RfSimRawEvent struct {
+	Time uint64
+	Node NodeId
+	Data []byte
+}
+
+// rfSimRawChannel passes raw events between OTNS and the OT-RFSIM platform, which allows to prototype new platform
+// features without adding dedicated event types.
+type rfSimRawChannel struct {
+	waiting   NodeId   // node of the pending SendRfSimRaw, or InvalidNodeId
+	responses [][]byte // responses received from the waiting node
+	handlers  []func(*RfSimRawEvent)
+}
+
+// SendRfSimRaw sends a raw OT-RFSIM platform event to the node, and returns the raw events sent back by the node
+// until it goes asleep again. The node must run a platform handling raw events.
+func (d *Dispatcher) SendRfSimRaw(id NodeId, data []byte) ([][]byte, error) {
+	node := d.nodes[id]
+	if node == nil {
+		return nil, errors.Errorf("node %d not found", id)
+	}
+	if node.isFailed || node.isPaused {
+		return nil, errors.Errorf("node %d is down", id)
+	}
+	if len(data) > MaxRfSimRawLen {
+		return nil, errors.Errorf("data too long: %d > %d bytes", len(data), MaxRfSimRawLen)
+	}
+
+	d.rfSimRaw.waiting = id
+	d.rfSimRaw.responses = nil
+	defer func() {
+		d.rfSimRaw.waiting = InvalidNodeId
+		d.rfSimRaw.responses = nil
+	}()
+
+	d.sendEvent(node, eventTypeRfSimRaw, data)
+	d.RecvEvents()
+	return d.rfSimRaw.responses, nil
+}
+
+// SubscribeRfSimRaw registers a callback for raw OT-RFSIM platform events sent by nodes on their own.
+// The callback is called in the dispatcher goroutine and must return quickly.
+func (d *Dispatcher) SubscribeRfSimRaw(cb func(*RfSimRawEvent)) {
+	d.rfSimRaw.handlers = append(d.rfSimRaw.handlers, cb)
+}
+
+func (d *Dispatcher) handleRfSimRaw(nodeid NodeId, data []byte) {
+	d.Counters.RfSimRawEvents += 1
+	if d.rfSimRaw.waiting == nodeid {
+		d.rfSimRaw.responses = append(d.rfSimRaw.responses, data)
+		return
+	}
+
+	evt := &RfSimRawEvent{Time: d.CurTime, Node: nodeid, Data: data}
+	for _, cb := range d.rfSimRaw.handlers {
+		cb(evt)
+	}
+}
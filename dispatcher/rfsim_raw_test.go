@@ -0,0 +1,67 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRfSimRaw(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.eventChan = make(chan *event, 10)
+	d.CurTime = 1000000
+
+	var unsolicited []*RfSimRawEvent
+	d.SubscribeRfSimRaw(func(evt *RfSimRawEvent) {
+		unsolicited = append(unsolicited, evt)
+	})
+
+	// the node responds with two raw events and goes asleep
+	d.eventChan <- &event{NodeId: 2, Type: eventTypeRfSimRaw, Data: []byte{0x01}}
+	d.eventChan <- &event{NodeId: 2, Type: eventTypeRfSimRaw, Data: []byte{0x02, 0x03}}
+	d.eventChan <- &event{NodeId: 2, Type: eventTypeAlarmFired, Delay: 1000}
+	responses, err := d.SendRfSimRaw(2, []byte{0xca, 0xfe})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{{0x01}, {0x02, 0x03}}, responses)
+	assert.Empty(t, unsolicited)
+	assert.Equal(t, uint64(2), d.Counters.RfSimRawEvents)
+
+	// events sent by the node on its own go to the subscribers
+	d.handleRecvEvent(&event{NodeId: 1, Type: eventTypeRfSimRaw, Data: []byte{0x04}})
+	assert.Len(t, unsolicited, 1)
+	assert.Equal(t, &RfSimRawEvent{Time: 1000000, Node: 1, Data: []byte{0x04}}, unsolicited[0])
+
+	_, err = d.SendRfSimRaw(3, []byte{0x01})
+	assert.NotNil(t, err)
+	_, err = d.SendRfSimRaw(1, make([]byte, MaxRfSimRawLen+1))
+	assert.NotNil(t, err)
+	d.nodes[1].isPaused = true
+	_, err = d.SendRfSimRaw(1, []byte{0x01})
+	assert.NotNil(t, err)
+}
@@ -77,3 +77,16 @@ func TestRloc16Map_Remove(t *testing.T) {
 	rm.Remove(node3.Rloc16, node3)
 	assert.False(t, rm.Contains(node3.Rloc16, node3))
 }
+
+func BenchmarkRloc16MapLookup(b *testing.B) {
+	rm := make(rloc16Map)
+	for id := 1; id <= 1000; id++ {
+		rloc16 := uint16(id%100) << 10 // 10 nodes with the same RLOC16 in each of the 100 partitions
+		rm.Add(rloc16, &Node{Id: id, Rloc16: rloc16})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rm[uint16(i%100)<<10]
+	}
+}
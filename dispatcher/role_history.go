@@ -0,0 +1,71 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+)
+
+// RoleChange is a role change of a node pushed by the node.
+type RoleChange struct {
+	Time    uint64
+	NodeId  NodeId
+	OldRole OtDeviceRole
+	NewRole OtDeviceRole
+}
+
+// IsPromotion returns if the node became router or leader from a child or detached role.
+func (rc *RoleChange) IsPromotion() bool {
+	return rc.OldRole < OtDeviceRoleRouter && rc.NewRole >= OtDeviceRoleRouter
+}
+
+// IsDemotion returns if the node lost the router or leader role.
+func (rc *RoleChange) IsDemotion() bool {
+	return rc.OldRole >= OtDeviceRoleRouter && rc.NewRole < OtDeviceRoleRouter
+}
+
+// RoleChanges returns the recent role changes of all nodes in time order. They are derived from the role events of the
+// timeline.
+func (d *Dispatcher) RoleChanges() []RoleChange {
+	var changes []RoleChange
+	for _, ev := range d.timelineSince(d.roleChangesFrom) {
+		if ev.Type == TimelineRole {
+			changes = append(changes, RoleChange{
+				Time:    ev.Time,
+				NodeId:  ev.Node,
+				OldRole: parseTimelineRole(ev.Old),
+				NewRole: parseTimelineRole(ev.New),
+			})
+		}
+	}
+	return changes
+}
+
+// ResetRoleChanges discards the role changes returned by RoleChanges.
+func (d *Dispatcher) ResetRoleChanges() {
+	d.roleChangesFrom = d.timelineSeq()
+}
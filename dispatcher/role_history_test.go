@@ -0,0 +1,74 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func TestRoleChanges(t *testing.T) {
+	d := &Dispatcher{
+		nodes:           map[NodeId]*Node{},
+		vis:             visualize.NewNopVisualizer(),
+		pendingUpgrades: map[NodeId]*UpgradeResult{},
+	}
+	d.nodes[1] = newNode(d, 1, 0, 0, 160)
+
+	d.CurTime = 1000000
+	d.setNodeRole(1, OtDeviceRoleDetached)
+	d.CurTime = 2000000
+	d.setNodeRole(1, OtDeviceRoleChild)
+	d.setNodeRole(1, OtDeviceRoleChild)
+	d.CurTime = 3000000
+	d.setNodeRole(1, OtDeviceRoleRouter)
+	d.CurTime = 4000000
+	d.setNodeRole(1, OtDeviceRoleChild)
+
+	changes := d.RoleChanges()
+	assert.Equal(t, 4, len(changes))
+	assert.Equal(t, RoleChange{Time: 1000000, NodeId: 1, OldRole: OtDeviceRoleDisabled, NewRole: OtDeviceRoleDetached},
+		changes[0])
+	assert.False(t, changes[1].IsPromotion())
+	assert.True(t, changes[2].IsPromotion())
+	assert.True(t, changes[3].IsDemotion())
+	assert.False(t, changes[2].IsDemotion())
+
+	d.ResetRoleChanges()
+	assert.Equal(t, 0, len(d.RoleChanges()))
+	assert.Equal(t, 4, len(d.Timeline()))
+
+	d.CurTime = 5000000
+	d.setNodeRole(1, OtDeviceRoleLeader)
+	changes = d.RoleChanges()
+	assert.Equal(t, 1, len(changes))
+	assert.True(t, changes[0].IsPromotion())
+}
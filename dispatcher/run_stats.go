@@ -0,0 +1,113 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"time"
+)
+
+const (
+	maxPingHistoryCount = 10000
+)
+
+// PingStats is the summary of ping results. Timed out pings are counted by Timeouts but not by Delay.
+type PingStats struct {
+	Count    int           `json:"count"`
+	Timeouts int           `json:"timeouts"`
+	Delay    DurationStats `json:"delay"`
+}
+
+// RunStats contains the statistics of the simulation run since the dispatcher started or was reset.
+// RunTime is the wall-clock time spent simulating, i.e. in go periods.
+type RunStats struct {
+	RunTime    time.Duration  `json:"-"`
+	TxFrames   uint64         `json:"tx_frames"`
+	TxBytes    uint64         `json:"tx_bytes"`
+	Collisions CollisionStats `json:"collisions"`
+	Mac        MacStats       `json:"mac"` // MAC retries, CCA failures and frame drops of all nodes
+	Pings      PingStats      `json:"pings"`
+	Joins      JoinStats      `json:"joins"`
+}
+
+// CollisionStats counts the frames overlapping on air, while the collision model is enabled.
+type CollisionStats struct {
+	OverlappedFrames uint64 `json:"overlapped_frames"` // frames sent while another frame was on air on the channel
+	LostDeliveries   uint64 `json:"lost_deliveries"`   // deliveries of frames lost due to collisions
+}
+
+type runStatsCollector struct {
+	runTime      time.Duration
+	txFrames     uint64
+	txBytes      uint64
+	collisions   CollisionStats
+	mac          MacStats
+	pingCount    int
+	pingTimeouts int
+	pingDelays   []uint64 // delays of the recent replied pings
+}
+
+func (rc *runStatsCollector) onTransmit(psduLen int) {
+	rc.txFrames++
+	rc.txBytes += uint64(psduLen)
+}
+
+func (rc *runStatsCollector) onMacCounters(delta MacCounters) {
+	rc.mac.TxRetries += delta.TxRetry
+	rc.mac.CcaFailures += delta.TxErrCca
+}
+
+func (rc *runStatsCollector) onPingResult(delay uint64) {
+	rc.pingCount++
+	if delay >= MaxPingDelayUs {
+		rc.pingTimeouts++
+		return
+	}
+
+	rc.pingDelays = append(rc.pingDelays, delay)
+	if len(rc.pingDelays) > maxPingHistoryCount {
+		rc.pingDelays = rc.pingDelays[1:]
+	}
+}
+
+// GetRunStats returns the statistics of the simulation run.
+func (d *Dispatcher) GetRunStats() RunStats {
+	rc := &d.runStats
+	stats := RunStats{
+		RunTime:    rc.runTime,
+		TxFrames:   rc.txFrames,
+		TxBytes:    rc.txBytes,
+		Collisions: rc.collisions,
+		Pings: PingStats{
+			Count:    rc.pingCount,
+			Timeouts: rc.pingTimeouts,
+			Delay:    newDurationStats(append([]uint64(nil), rc.pingDelays...)),
+		},
+		Joins: d.joinHistory.stats(),
+	}
+	stats.Mac.add(&rc.mac)
+	return stats
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestRunStats(t *testing.T) {
+	d := &Dispatcher{
+		windowStats: newWindowStatsCollector(WindowStatsConfig{Interval: 10, Retention: 3, Metrics: WindowMetricAll}, 0),
+	}
+	d.nodes = map[NodeId]*Node{1: {D: d, Id: 1}}
+
+	d.runStats.onTransmit(10)
+	d.runStats.onTransmit(20)
+	d.SetMacCounters(1, MacCounters{TxRetry: 5, TxErrCca: 1})
+	d.SetMacCounters(1, MacCounters{TxRetry: 8, TxErrCca: 1})
+	d.onFrameDropped(1, DropReasonRange)
+	d.nodes[1].addPingResult(&pingRequest{Dst: "fdde:ad00:beef:0::1", DataSize: 4}, 10000)
+	d.nodes[1].addPingResult(&pingRequest{Dst: "fdde:ad00:beef:0::1", DataSize: 4}, 20000)
+	d.nodes[1].addPingResult(&pingRequest{Dst: "fdde:ad00:beef:0::1", DataSize: 4}, MaxPingDelayUs)
+
+	// collecting the ping results of the node does not affect the run statistics
+	d.nodes[1].CollectPings()
+
+	stats := d.GetRunStats()
+	assert.Equal(t, uint64(2), stats.TxFrames)
+	assert.Equal(t, uint64(30), stats.TxBytes)
+	assert.Equal(t, uint64(8), stats.Mac.TxRetries)
+	assert.Equal(t, uint64(1), stats.Mac.CcaFailures)
+	assert.Equal(t, uint64(1), stats.Mac.Drops[DropReasonRange])
+	assert.Equal(t, 3, stats.Pings.Count)
+	assert.Equal(t, 1, stats.Pings.Timeouts)
+	assert.Equal(t, 2, stats.Pings.Delay.Count)
+	assert.Equal(t, uint64(20000), stats.Pings.Delay.Max)
+
+	// the returned statistics are not changed by later events
+	d.onFrameDropped(1, DropReasonRange)
+	assert.Equal(t, uint64(1), stats.Mac.Drops[DropReasonRange])
+}
@@ -33,9 +33,15 @@ import (
 )
 
 type sendItem struct {
-	Timestamp uint64
-	NodeId    NodeId
-	Data      []byte
+	Timestamp   uint64
+	NodeId      NodeId
+	Radio       int // index of the radio transmitting the frame
+	Data        []byte
+	frag        *fragFrame  // fragment carried by the frame, set when dispatched
+	linkTracked bool        // the frame is tracked by the link statistics, set when dispatched
+	injected    bool        // the frame is delivered again by a fault injection, which does not apply to it again
+	ack         bool        // the frame is an ACK, set when dispatched
+	onAir       *onAirFrame // the frame on air with the collision model, set when dispatched
 }
 
 type sendQueue struct {
@@ -74,9 +80,14 @@ func (sq sendQueue) NextTimestamp() uint64 {
 }
 
 func (sq *sendQueue) Add(timestamp uint64, id NodeId, data []byte) {
+	sq.AddRadioFrame(timestamp, id, PrimaryRadio, data)
+}
+
+func (sq *sendQueue) AddRadioFrame(timestamp uint64, id NodeId, radio int, data []byte) {
 	heap.Push(sq, &sendItem{
 		Timestamp: timestamp,
 		NodeId:    id,
+		Radio:     radio,
 		Data:      data,
 	})
 }
@@ -74,3 +74,17 @@ func TestSendQueue_PopNext(t *testing.T) {
 	it = q.PopNext()
 	assert.True(t, it.NodeId == 3 && it.Timestamp == 3)
 }
+
+func BenchmarkSendQueue(b *testing.B) {
+	sq := newSendQueue()
+	data := make([]byte, 128)
+	for i := 0; i < 1000; i++ {
+		sq.Add(uint64(i*997%1000), i, data)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := sq.PopNext()
+		sq.Add(s.Timestamp+1000, s.NodeId, s.Data)
+	}
+}
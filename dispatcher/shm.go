@@ -0,0 +1,306 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// The shared memory transport replaces the UDP socket between the dispatcher and a node with two single-producer
+// single-consumer rings in a memory-mapped file, which the dispatcher polls while it waits for the nodes.
+//
+// The file starts with a 64-byte header: the magic "OTNSSHM1", the version (uint32) and the capacity of each ring
+// (uint32, a power of 2). It is followed by the ring of the events from the node to the dispatcher, and then by the
+// ring of the messages from the dispatcher to the node. Each ring has the total number of bytes written (uint64) at
+// offset 0, the total number of bytes read (uint64) at offset 64, and the data at offset 128. Each record in a ring is
+// the length of a message (uint32) followed by the message, in the same format as the UDP messages, and may wrap around
+// the end of the data. All integers are little-endian.
+//
+// The dispatcher creates the file before the node starts, and passes its path to the node in the environment variable
+// OTNS_SHM. A node supporting the transport maps the file and announces it with the status push "transport=shm" on
+// the socket. Other nodes keep using the socket.
+const (
+	ShmEnvVar          = "OTNS_SHM"
+	DefaultShmCapacity = 1 << 20
+
+	shmMagic         = "OTNSSHM1"
+	shmVersion       = 1
+	shmHeaderLen     = 64
+	shmRingHeaderLen = 128
+	shmPollInterval  = 20 * time.Microsecond
+)
+
+// shmWriteTimeout is how long sendSharedMemory waits for the node to free space in a full ring.
+var shmWriteTimeout = time.Second
+
+// shmRing is a single-producer single-consumer ring of messages in shared memory.
+type shmRing struct {
+	mem  []byte // ring header and data
+	data []byte
+	mask uint64
+}
+
+func newShmRing(mem []byte, capacity int) *shmRing {
+	return &shmRing{
+		mem:  mem,
+		data: mem[shmRingHeaderLen : shmRingHeaderLen+capacity],
+		mask: uint64(capacity - 1),
+	}
+}
+
+func (r *shmRing) written() *uint64 {
+	return (*uint64)(unsafe.Pointer(&r.mem[0]))
+}
+
+func (r *shmRing) read() *uint64 {
+	return (*uint64)(unsafe.Pointer(&r.mem[64]))
+}
+
+func (r *shmRing) copyIn(pos uint64, b []byte) {
+	n := copy(r.data[pos&r.mask:], b)
+	copy(r.data, b[n:])
+}
+
+func (r *shmRing) copyOut(pos uint64, b []byte) {
+	n := copy(b, r.data[pos&r.mask:])
+	copy(b[n:], r.data)
+}
+
+// Write appends the message to the ring, and returns false if the ring is full.
+func (r *shmRing) Write(msg []byte) bool {
+	head := atomic.LoadUint64(r.written())
+	tail := atomic.LoadUint64(r.read())
+	need := uint64(4 + len(msg))
+	if head-tail+need > uint64(len(r.data)) {
+		return false
+	}
+
+	var lenbuf [4]byte
+	binary.LittleEndian.PutUint32(lenbuf[:], uint32(len(msg)))
+	r.copyIn(head, lenbuf[:])
+	r.copyIn(head+4, msg)
+	atomic.StoreUint64(r.written(), head+need)
+	return true
+}
+
+// Read removes the next message from the ring, and returns nil if the ring is empty.
+func (r *shmRing) Read() ([]byte, error) {
+	tail := atomic.LoadUint64(r.read())
+	head := atomic.LoadUint64(r.written())
+	if head == tail {
+		return nil, nil
+	}
+
+	var lenbuf [4]byte
+	r.copyOut(tail, lenbuf[:])
+	n := uint64(binary.LittleEndian.Uint32(lenbuf[:]))
+	if head-tail < 4+n {
+		return nil, errors.Errorf("invalid record length %d in shared memory ring", n)
+	}
+
+	msg := make([]byte, n)
+	r.copyOut(tail+4, msg)
+	atomic.StoreUint64(r.read(), tail+4+n)
+	return msg, nil
+}
+
+// shmTransport is the shared memory file of a node.
+type shmTransport struct {
+	path   string
+	mem    []byte
+	active bool     // the node announced the transport
+	stuck  bool     // the node did not read from the ring of messages within shmWriteTimeout
+	events *shmRing // events from the node
+	msgs   *shmRing // messages to the node
+}
+
+func shmRingsLen(capacity int) int {
+	return shmHeaderLen + 2*(shmRingHeaderLen+capacity)
+}
+
+func newShmTransport(path string, capacity int) (*shmTransport, error) {
+	if capacity <= 0 || capacity&(capacity-1) != 0 {
+		return nil, errors.Errorf("shared memory capacity must be a power of 2: %d", capacity)
+	}
+
+	mem, err := mapShmFile(path, shmRingsLen(capacity))
+	if err != nil {
+		return nil, err
+	}
+
+	copy(mem, shmMagic)
+	binary.LittleEndian.PutUint32(mem[8:12], shmVersion)
+	binary.LittleEndian.PutUint32(mem[12:16], uint32(capacity))
+	ringLen := shmRingHeaderLen + capacity
+	return &shmTransport{
+		path:   path,
+		mem:    mem,
+		events: newShmRing(mem[shmHeaderLen:shmHeaderLen+ringLen], capacity),
+		msgs:   newShmRing(mem[shmHeaderLen+ringLen:], capacity),
+	}, nil
+}
+
+func (t *shmTransport) Close() error {
+	err := unmapShmFile(t.mem)
+	if rmErr := os.Remove(t.path); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// shmDir returns the directory of the shared memory files, which is in memory on Linux.
+func shmDir() string {
+	if st, err := os.Stat("/dev/shm"); err == nil && st.IsDir() {
+		return "/dev/shm"
+	}
+	return os.TempDir()
+}
+
+// PrepareSharedMemory creates the shared memory file of a node which is about to start, and returns its path, which
+// is passed to the node in the OTNS_SHM environment variable.
+func (d *Dispatcher) PrepareSharedMemory(id NodeId) (string, error) {
+	d.closeSharedMemory(id)
+
+	path := filepath.Join(shmDir(), fmt.Sprintf("otns_%d_%d.shm", d.cfg.Port, id))
+	t, err := newShmTransport(path, DefaultShmCapacity)
+	if err != nil {
+		return "", err
+	}
+	d.shm[id] = t
+	return path, nil
+}
+
+func (d *Dispatcher) closeSharedMemory(id NodeId) {
+	t := d.shm[id]
+	if t == nil {
+		return
+	}
+
+	delete(d.shm, id)
+	if node := d.nodes[id]; node != nil && node.shm == t {
+		node.shm = nil
+	}
+	if err := t.Close(); err != nil {
+		simplelogger.Warnf("close shared memory of node %d failed: %v", id, err)
+	}
+}
+
+func (d *Dispatcher) onStatusPushTransport(node *Node, transport string) {
+	if transport != "shm" {
+		simplelogger.Warnf("%s announced an unknown transport: %s", node, transport)
+		return
+	}
+
+	t := d.shm[node.Id]
+	if t == nil {
+		simplelogger.Warnf("%s announced the shared memory transport, which is not enabled", node)
+		return
+	}
+	t.active = true
+	node.shm = t
+}
+
+// pollSharedMemory handles the events in the rings of the nodes using shared memory, and returns the number of events.
+func (d *Dispatcher) pollSharedMemory() int {
+	count := 0
+	for id, t := range d.shm {
+		if !t.active {
+			continue
+		}
+
+		if _, alive := d.aliveNodes[id]; alive && t.stuck {
+			// the node does not read its messages, so it would never respond
+			d.forceFailNode(id)
+		}
+
+		for {
+			msg, err := t.events.Read()
+			if err != nil {
+				simplelogger.Errorf("node %d: %v", id, err)
+				d.closeSharedMemory(id)
+				break
+			}
+			if msg == nil {
+				break
+			}
+
+			evt := &event{NodeId: id}
+			if err = evt.Deserialize(msg); err != nil {
+				simplelogger.Warnf("node %d sent an invalid event: %v", id, err)
+				continue
+			}
+			events, err := unbatchEvent(evt)
+			if err != nil {
+				simplelogger.Warnf("node %d sent an invalid batch event: %v", id, err)
+				continue
+			}
+			for _, e := range events {
+				count++
+				d.handleRecvEvent(e)
+			}
+		}
+	}
+	return count
+}
+
+// sendSharedMemory writes the message to the ring of the node. If the ring is full, it waits for the node to read
+// from the ring. A node which does not read within shmWriteTimeout is stuck: it is failed and no longer waited for by
+// pollSharedMemory, and the messages to it are dropped until it reads from the ring again. The message is never sent to
+// the socket instead, since the node could then receive it before the earlier messages in the ring.
+func (node *Node) sendSharedMemory(msg []byte) {
+	t := node.shm
+	if t.msgs.Write(msg) {
+		t.stuck = false
+		return
+	}
+	if t.stuck {
+		return
+	}
+
+	deadline := time.Now().Add(shmWriteTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(shmPollInterval)
+		if t.msgs.Write(msg) {
+			return
+		}
+	}
+
+	simplelogger.Errorf("%s: shared memory ring stays full, failing the node", node)
+	t.stuck = true
+	node.D.Counters.ShmStuckNodes += 1
+}
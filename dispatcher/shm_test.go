@@ -0,0 +1,161 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShmRing(t *testing.T) {
+	r := newShmRing(make([]byte, shmRingHeaderLen+32), 32)
+	msg, err := r.Read()
+	assert.Nil(t, err)
+	assert.Nil(t, msg)
+
+	assert.True(t, r.Write([]byte("0123456789")))
+	assert.True(t, r.Write([]byte("abcdefghij")))
+	assert.False(t, r.Write([]byte("ABCDEFGHIJ")))
+
+	msg, _ = r.Read()
+	assert.Equal(t, "0123456789", string(msg))
+	// the record wraps around the end of the data
+	assert.True(t, r.Write([]byte("ABCDEFGHIJ")))
+	msg, _ = r.Read()
+	assert.Equal(t, "abcdefghij", string(msg))
+	msg, _ = r.Read()
+	assert.Equal(t, "ABCDEFGHIJ", string(msg))
+	msg, _ = r.Read()
+	assert.Nil(t, msg)
+}
+
+func TestSharedMemoryTransport(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SharedMemory = true
+	d := newDispatcher(nil, cfg, nil)
+	node := d.newNode(1, 0, 0, 100)
+
+	path, err := d.PrepareSharedMemory(1)
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, shmRingsLen(DefaultShmCapacity), len(data))
+	assert.Equal(t, shmMagic, string(data[:8]))
+	assert.Equal(t, uint32(DefaultShmCapacity), binary.LittleEndian.Uint32(data[12:16]))
+
+	// events are ignored until the node announces the transport
+	shm := d.shm[1]
+	assert.True(t, shm.events.Write((&event{Delay: 100, Type: eventTypeAlarmFired}).Serialize()))
+	assert.Equal(t, 0, d.pollSharedMemory())
+
+	d.handleStatusPush(1, "transport=shm")
+	assert.Equal(t, shm, node.shm)
+	assert.Equal(t, 1, d.RecvEvents())
+	assert.Equal(t, uint64(100), d.alarmMgr.GetTimestamp(1))
+	assert.Empty(t, d.aliveNodes)
+
+	d.CurTime = 100
+	d.advanceNodeTime(1, 100, false)
+	msg, err := shm.msgs.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, (&event{Delay: 100, Type: eventTypeAlarmFired}).Serialize(), msg)
+
+	d.DeleteNode(1)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, d.shm)
+}
+
+func TestSharedMemoryRingFull(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SharedMemory = true
+	d := newDispatcher(nil, cfg, nil)
+	d.cbHandler = nopCallbackHandler{}
+	node := d.newNode(1, 0, 0, 100)
+
+	dir, err := ioutil.TempDir("", "otns_shm")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	shm, err := newShmTransport(filepath.Join(dir, "ring.shm"), 64)
+	assert.Nil(t, err)
+	defer shm.Close()
+	node.shm = shm
+
+	msg := make([]byte, 28)
+	node.sendSharedMemory(msg)
+	node.sendSharedMemory(msg)
+	assert.False(t, shm.msgs.Write(msg))
+
+	// the message waits for the node to read from the ring
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		_, _ = shm.msgs.Read()
+	}()
+	node.sendSharedMemory(msg)
+	assert.False(t, shm.stuck)
+
+	// the node is stuck if it does not
+	timeout := shmWriteTimeout
+	shmWriteTimeout = time.Millisecond * 10
+	defer func() { shmWriteTimeout = timeout }()
+	node.sendSharedMemory(msg)
+	assert.True(t, shm.stuck)
+	assert.Equal(t, uint64(1), d.Counters.ShmStuckNodes)
+
+	// a stuck node is failed and not waited for
+	shm.active = true
+	d.shm[1] = shm
+	d.setAlive(1)
+	assert.Equal(t, 0, d.pollSharedMemory())
+	assert.True(t, node.IsFailed())
+	assert.Empty(t, d.aliveNodes)
+	assert.Equal(t, Ever, d.alarmMgr.GetTimestamp(1))
+
+	// messages to a stuck node are dropped without waiting
+	begin := time.Now()
+	node.sendSharedMemory(msg)
+	assert.Less(t, time.Since(begin), shmWriteTimeout)
+	assert.Equal(t, uint64(1), d.Counters.ShmStuckNodes)
+
+	// until the node reads from the ring again
+	for i := 0; i < 2; i++ {
+		read, err := shm.msgs.Read()
+		assert.Nil(t, err)
+		assert.Equal(t, msg, read)
+	}
+	node.sendSharedMemory(msg)
+	assert.False(t, shm.stuck)
+	read, err := shm.msgs.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, msg, read)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	DefaultStallDumpFile = "stall.txt"
+)
+
+// StallConfig configures the detection of stalls of the virtual time loop, which happen when alive nodes do not
+// respond to the dispatcher, e.g. a node stuck in a busy loop.
+type StallConfig struct {
+	Timeout   time.Duration // 0 to disable the detection
+	ForceFail bool          // fail the nodes which do not respond, so that the simulation goes on
+	DumpFile  string        // file to write the diagnostic state to, or empty for no dump
+}
+
+// DefaultStallConfig returns the stall configuration with the detection disabled.
+func DefaultStallConfig() StallConfig {
+	return StallConfig{
+		DumpFile: DefaultStallDumpFile,
+	}
+}
+
+// StallReport is the diagnostic state of a stall of the virtual time loop.
+type StallReport struct {
+	Time          uint64        // simulation time of the stall
+	Duration      time.Duration // wall-clock duration without virtual time progress when the stall was detected
+	AliveNodes    []NodeId      // nodes the dispatcher is waiting for
+	PendingAlarms int           // nodes with a scheduled alarm
+	PendingSends  int
+	PendingTimers int
+	PendingTasks  int
+	PendingEvents int
+	ForceFailed   []NodeId
+}
+
+type stallDetector struct {
+	progressTime    time.Time // wall-clock time of the latest progress
+	progressCurTime uint64
+	stalled         bool // the current stall was already reported
+	dumped          bool // the dump file was already created
+	reports         []*StallReport
+}
+
+// GetStallConfig returns the configuration of the stall detection.
+func (d *Dispatcher) GetStallConfig() StallConfig {
+	return d.cfg.Stall
+}
+
+// SetStallConfig sets the configuration of the stall detection.
+func (d *Dispatcher) SetStallConfig(cfg StallConfig) {
+	simplelogger.AssertTrue(cfg.Timeout >= 0)
+	d.cfg.Stall = cfg
+}
+
+// StallReports returns the stalls detected so far.
+func (d *Dispatcher) StallReports() []*StallReport {
+	return d.stall.reports
+}
+
+// resetStallDetector restarts the detection of stalls, e.g. when a go period starts.
+func (d *Dispatcher) resetStallDetector() {
+	d.stall.progressTime = time.Now()
+	d.stall.progressCurTime = d.CurTime
+	d.stall.stalled = false
+}
+
+// checkStall checks if the virtual time loop is stalled: the dispatcher has been waiting for alive nodes without
+// virtual time progress for longer than the stall timeout. Each stall is reported once.
+func (d *Dispatcher) checkStall() {
+	if d.CurTime != d.stall.progressCurTime || len(d.aliveNodes) == 0 {
+		d.resetStallDetector()
+		return
+	}
+
+	timeout := d.cfg.Stall.Timeout
+	duration := time.Since(d.stall.progressTime)
+	if timeout <= 0 || d.stall.stalled || duration < timeout {
+		return
+	}
+
+	d.stall.stalled = true
+	report := &StallReport{
+		Time:          d.CurTime,
+		Duration:      duration,
+		PendingAlarms: d.alarmMgr.ScheduledCount(),
+		PendingSends:  d.sendQueue.Len(),
+		PendingTimers: d.timers.Len(),
+		PendingTasks:  len(d.taskChan),
+		PendingEvents: len(d.eventChan),
+	}
+	for nodeid := range d.aliveNodes {
+		report.AliveNodes = append(report.AliveNodes, nodeid)
+	}
+	sort.Ints(report.AliveNodes)
+
+	simplelogger.Errorf("virtual time stalled at %d for %v, waiting for nodes %v", d.CurTime, duration.Round(time.Second),
+		report.AliveNodes)
+	d.dumpStall(report)
+
+	if d.cfg.Stall.ForceFail {
+		for _, nodeid := range report.AliveNodes {
+			d.forceFailNode(nodeid)
+		}
+		report.ForceFailed = report.AliveNodes
+	}
+
+	d.stall.reports = append(d.stall.reports, report)
+}
+
+// forceFailNode fails the node which does not respond, and stops waiting for it.
+func (d *Dispatcher) forceFailNode(nodeid NodeId) {
+	node := d.nodes[nodeid]
+	simplelogger.AssertNotNil(node)
+
+	simplelogger.Errorf("force failing stalled node %d", nodeid)
+	node.SetFailTime(NonFailTime)
+	node.Fail()
+	d.setSleeping(nodeid)
+	d.alarmMgr.SetTimestamp(nodeid, Ever)
+}
+
+// dumpStall writes the diagnostic state of the stall, including the stacks of all goroutines, to the dump file.
+func (d *Dispatcher) dumpStall(report *StallReport) {
+	if d.cfg.Stall.DumpFile == "" {
+		return
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !d.stall.dumped {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(d.cfg.Stall.DumpFile, flags, 0644)
+	if err != nil {
+		simplelogger.Errorf("create stall dump %s failed: %v", d.cfg.Stall.DumpFile, err)
+		return
+	}
+	defer f.Close()
+	d.stall.dumped = true
+
+	d.writeStallReport(f, report)
+	simplelogger.Errorf("stall diagnostics dumped to %s", d.cfg.Stall.DumpFile)
+}
+
+func (d *Dispatcher) writeStallReport(w io.Writer, report *StallReport) {
+	_, _ = fmt.Fprintf(w, "=== stall at %d us, no progress for %v\n", report.Time, report.Duration)
+	_, _ = fmt.Fprintf(w, "pending: alarms=%d sends=%d timers=%d tasks=%d events=%d\n", report.PendingAlarms,
+		report.PendingSends, report.PendingTimers, report.PendingTasks, report.PendingEvents)
+	for _, nodeid := range report.AliveNodes {
+		_, _ = fmt.Fprintf(w, "alive node %d: %s\n", nodeid, d.nodes[nodeid].DumpStat())
+	}
+
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	_, _ = fmt.Fprintf(w, "goroutines:\n%s\n", buf)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func newStallTestDispatcher(cfg StallConfig) *Dispatcher {
+	d := &Dispatcher{
+		cfg:        Config{Stall: cfg},
+		nodes:      map[NodeId]*Node{},
+		aliveNodes: map[NodeId]struct{}{},
+		alarmMgr:   newAlarmMgr(),
+		sendQueue:  newSendQueue(),
+		timers:     newTimerQueue(),
+		vis:        visualize.NewNopVisualizer(),
+		cbHandler:  nopCallbackHandler{},
+		linkStats:  newLinkStatsCollector(),
+	}
+	for nodeid := 1; nodeid <= 2; nodeid++ {
+		d.nodes[nodeid] = newNode(d, nodeid, 0, 0, 160)
+		d.alarmMgr.AddNode(nodeid)
+	}
+	d.alarmMgr.SetTimestamp(1, 2000000)
+	return d
+}
+
+func TestStallDetector(t *testing.T) {
+	dumpFile := filepath.Join(t.TempDir(), "stall.txt")
+	d := newStallTestDispatcher(StallConfig{Timeout: time.Millisecond, DumpFile: dumpFile})
+	d.CurTime = 1000000
+	d.resetStallDetector()
+
+	// no stall while no node is alive
+	time.Sleep(time.Millisecond * 2)
+	d.checkStall()
+	assert.Empty(t, d.StallReports())
+
+	d.setAlive(2)
+	d.checkStall()
+	time.Sleep(time.Millisecond * 2)
+	d.checkStall()
+	assert.Len(t, d.StallReports(), 1)
+	report := d.StallReports()[0]
+	assert.Equal(t, uint64(1000000), report.Time)
+	assert.Equal(t, []NodeId{2}, report.AliveNodes)
+	assert.Equal(t, 1, report.PendingAlarms)
+	assert.Nil(t, report.ForceFailed)
+	assert.False(t, d.nodes[2].IsFailed())
+
+	// the stall is reported once
+	d.checkStall()
+	assert.Len(t, d.StallReports(), 1)
+
+	// virtual time progress ends the stall
+	d.CurTime = 1000001
+	d.checkStall()
+	time.Sleep(time.Millisecond * 2)
+	d.checkStall()
+	assert.Len(t, d.StallReports(), 2)
+
+	dump, err := ioutil.ReadFile(dumpFile)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(string(dump), "=== stall at"))
+	assert.Contains(t, string(dump), "alive node 2:")
+	assert.Contains(t, string(dump), "goroutine")
+}
+
+func TestStallDetectorForceFail(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{Timeout: time.Millisecond, ForceFail: true})
+	d.setAlive(2)
+	d.resetStallDetector()
+	time.Sleep(time.Millisecond * 2)
+	d.checkStall()
+
+	assert.Len(t, d.StallReports(), 1)
+	assert.Equal(t, []NodeId{2}, d.StallReports()[0].ForceFailed)
+	assert.True(t, d.nodes[2].IsFailed())
+	assert.Empty(t, d.aliveNodes)
+	assert.Equal(t, Ever, d.alarmMgr.GetTimestamp(2))
+	assert.False(t, d.nodes[1].IsFailed())
+}
+
+func TestStallDetectorDisabled(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	d.setAlive(2)
+	d.resetStallDetector()
+	time.Sleep(time.Millisecond * 2)
+	d.checkStall()
+	assert.Empty(t, d.StallReports())
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// The TCP transport replaces the UDP socket between the dispatcher and a node with a TCP connection, so that node
+// executables can run inside containers or on remote hosts (with port forwarding) while OTNS runs natively.
+//
+// The dispatcher listens on the TCP address of Config.TcpAddr, and passes the address to nodes in the environment
+// variable OTNS_TCP. A node supporting the transport connects to the address and sends its node ID (uint32). Then
+// both sides send messages in the same format as the UDP messages, each preceded by its length (uint32). All integers
+// are little-endian. Other nodes keep using the UDP socket.
+const (
+	TcpEnvVar = "OTNS_TCP"
+)
+
+// TcpAddr returns the address of the TCP transport, or an empty string if the transport is disabled.
+func (d *Dispatcher) TcpAddr() string {
+	if d.tcpln == nil {
+		return ""
+	}
+	return d.tcpln.Addr().String()
+}
+
+func (d *Dispatcher) listenTcp() error {
+	ln, err := net.Listen("tcp", d.cfg.TcpAddr)
+	if err != nil {
+		return err
+	}
+
+	d.tcpln = ln
+	simplelogger.Infof("dispatcher listening on tcp %s ...", ln.Addr())
+	go d.tcpAcceptor(ln)
+	return nil
+}
+
+func (d *Dispatcher) tcpAcceptor(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			simplelogger.Infof("TCP acceptor quit.")
+			break
+		}
+
+		go d.tcpEventsReader(conn)
+	}
+}
+
+// tcpEventsReader reads the events of the node connected over TCP until the connection is closed.
+func (d *Dispatcher) tcpEventsReader(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		simplelogger.Warnf("TCP connection from %s closed before the node ID", conn.RemoteAddr())
+		return
+	}
+	nodeid := NodeId(binary.LittleEndian.Uint32(buf[:]))
+
+	readbuf := make([]byte, maxBatchLen)
+	for {
+		msg, err := readTcpMessage(r, readbuf)
+		if err != nil {
+			if err != io.EOF {
+				simplelogger.Warnf("node %d TCP connection failed: %v", nodeid, err)
+			}
+			break
+		}
+
+		evt := &event{
+			NodeId: nodeid,
+			conn:   conn,
+		}
+		err = evt.Deserialize(msg)
+		if err != nil {
+			simplelogger.Warnf("node %d sent an invalid event: %v", nodeid, err)
+			break
+		}
+
+		events, err := unbatchEvent(evt)
+		if err != nil {
+			simplelogger.Warnf("node %d sent an invalid batch event: %v", nodeid, err)
+			continue
+		}
+		for _, e := range events {
+			d.eventChan <- e
+		}
+	}
+}
+
+// readTcpMessage reads the next message of a TCP connection into buf.
+func readTcpMessage(r io.Reader, buf []byte) ([]byte, error) {
+	var lenbuf [4]byte
+	if _, err := io.ReadFull(r, lenbuf[:]); err != nil {
+		return nil, err
+	}
+
+	n := binary.LittleEndian.Uint32(lenbuf[:])
+	if n > uint32(len(buf)) {
+		return nil, errors.Errorf("message too long: %d", n)
+	}
+	if _, err := io.ReadFull(r, buf[:n]); err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// writeTcpMessage writes the message to the TCP connection of the node.
+func (node *Node) writeTcpMessage(msg []byte) {
+	rec := make([]byte, 4+len(msg))
+	binary.LittleEndian.PutUint32(rec, uint32(len(msg)))
+	copy(rec[4:], msg)
+	if _, err := node.tcpConn.Write(rec); err != nil {
+		simplelogger.Warnf("%s: TCP write failed: %v", node, err)
+	}
+}
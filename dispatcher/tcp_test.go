@@ -0,0 +1,89 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTcpTransport(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TcpAddr = "127.0.0.1:0"
+	d := newDispatcher(nil, cfg, nil)
+	assert.Equal(t, "", d.TcpAddr())
+	assert.Nil(t, d.listenTcp())
+	defer d.tcpln.Close()
+	node := d.newNode(1, 0, 0, 100)
+
+	conn, err := net.Dial("tcp", d.TcpAddr())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	write := func(msg []byte) {
+		rec := make([]byte, 4+len(msg))
+		binary.LittleEndian.PutUint32(rec, uint32(len(msg)))
+		copy(rec[4:], msg)
+		_, err := conn.Write(rec)
+		assert.Nil(t, err)
+	}
+	var hello [4]byte
+	binary.LittleEndian.PutUint32(hello[:], 1)
+	_, err = conn.Write(hello[:])
+	assert.Nil(t, err)
+	write((&event{Delay: 500, Type: eventTypeAlarmFired}).Serialize())
+
+	select {
+	case evt := <-d.eventChan:
+		assert.Equal(t, 1, evt.NodeId)
+		assert.Equal(t, uint64(500), evt.Delay)
+		d.handleRecvEvent(evt)
+	case <-time.After(time.Second):
+		t.Fatal("no event received over TCP")
+	}
+	assert.NotNil(t, node.tcpConn)
+
+	// messages to the node go to its TCP connection
+	d.SendToUART(1, []byte("state\n"))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, maxBatchLen)
+	msg, err := readTcpMessage(conn, buf)
+	assert.Nil(t, err)
+	evt := &event{}
+	assert.Nil(t, evt.Deserialize(msg))
+	assert.Equal(t, uint8(eventTypeUartWrite), evt.Type)
+	assert.Equal(t, "state\n", string(evt.Data))
+
+	// messages longer than the buffer are rejected
+	_, err = readTcpMessage(bytes.NewReader([]byte{0xff, 0xff, 0, 0}), buf)
+	assert.NotNil(t, err)
+}
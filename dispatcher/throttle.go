@@ -0,0 +1,126 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// DefaultLogRateLimit is the default maximum number of log events per second (wall-clock time) accepted from each
+	// node, or 0 for no limit.
+	DefaultLogRateLimit = 0
+
+	logThrottleWindow = time.Second
+)
+
+// logThrottle limits the log events accepted from a node in fixed windows of wall-clock time, so that a node flooding
+// the dispatcher with logs does not make the simulation unresponsive. UART writes are never throttled, since they
+// carry the CLI output that commands wait for.
+type logThrottle struct {
+	limit       int // maximum number of events per window, or 0 for no limit
+	windowStart time.Time
+	count       int    // events received in the current window
+	throttled   uint64 // events dropped since the node was added
+	flooding    bool   // events were dropped in the current or the previous window
+}
+
+// allow returns if an event received at the time is accepted. It returns false if the limit of the current window
+// is exceeded, and warns once when a node starts flooding.
+func (ut *logThrottle) allow(node *Node, now time.Time) bool {
+	if ut.limit <= 0 {
+		return true
+	}
+
+	if now.Sub(ut.windowStart) >= logThrottleWindow {
+		if ut.flooding && ut.count <= ut.limit {
+			simplelogger.Infof("node %d stopped flooding logs, %d events throttled in total", node.Id, ut.throttled)
+			ut.flooding = false
+		}
+		ut.windowStart = now
+		ut.count = 0
+	}
+
+	ut.count++
+	if ut.count <= ut.limit {
+		return true
+	}
+
+	if !ut.flooding {
+		simplelogger.Warnf("node %d is flooding logs with more than %d events/s, throttling", node.Id, ut.limit)
+		ut.flooding = true
+	}
+	ut.throttled++
+	return false
+}
+
+// allowLogEvent returns if the log event received from the node is accepted by the rate limit of the node.
+func (d *Dispatcher) allowLogEvent(node *Node) bool {
+	if node.logThrottle.allow(node, time.Now()) {
+		return true
+	}
+
+	d.Counters.ThrottledEvents += 1
+	return false
+}
+
+// GetLogRateLimit returns the default rate limit of log events of new nodes, in events per second.
+func (d *Dispatcher) GetLogRateLimit() int {
+	return d.cfg.LogRateLimit
+}
+
+// SetLogRateLimit sets the rate limit of log events of all nodes, including new nodes, in events per
+// second. A limit of 0 disables the rate limit.
+func (d *Dispatcher) SetLogRateLimit(limit int) {
+	simplelogger.AssertTrue(limit >= 0)
+	d.cfg.LogRateLimit = limit
+	for _, node := range d.nodes {
+		node.logThrottle.limit = limit
+	}
+}
+
+// SetNodeLogRateLimit sets the rate limit of log events of the node, in events per second. A limit of 0
+// disables the rate limit.
+func (d *Dispatcher) SetNodeLogRateLimit(id NodeId, limit int) {
+	simplelogger.AssertTrue(limit >= 0)
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+	node.logThrottle.limit = limit
+}
+
+// LogRateLimit returns the rate limit of log events of the node, in events per second, or 0 if disabled.
+func (node *Node) LogRateLimit() int {
+	return node.logThrottle.limit
+}
+
+// ThrottledEvents returns the number of log events of the node dropped by the rate limit.
+func (node *Node) ThrottledEvents() uint64 {
+	return node.logThrottle.throttled
+}
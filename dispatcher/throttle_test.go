@@ -0,0 +1,95 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func TestLogThrottle(t *testing.T) {
+	node := &Node{Id: 1}
+	ut := &logThrottle{limit: 3}
+	t0 := time.Now()
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, ut.allow(node, t0))
+	}
+	assert.False(t, ut.allow(node, t0.Add(time.Millisecond)))
+	assert.False(t, ut.allow(node, t0.Add(time.Millisecond*999)))
+	assert.True(t, ut.flooding)
+	assert.Equal(t, uint64(2), ut.throttled)
+
+	// a new window accepts events again
+	assert.True(t, ut.allow(node, t0.Add(time.Second)))
+	assert.True(t, ut.flooding)
+	assert.True(t, ut.allow(node, t0.Add(time.Second*2)))
+	assert.False(t, ut.flooding)
+	assert.Equal(t, uint64(2), ut.throttled)
+
+	// no limit
+	ut = &logThrottle{}
+	for i := 0; i < 100; i++ {
+		assert.True(t, ut.allow(node, t0))
+	}
+	assert.Equal(t, uint64(0), ut.throttled)
+}
+
+func TestLogRateLimit(t *testing.T) {
+	d := &Dispatcher{
+		cfg:       Config{LogRateLimit: 2},
+		vis:       visualize.NewNopVisualizer(),
+		cbHandler: nopCallbackHandler{},
+	}
+	d.nodes = map[NodeId]*Node{1: newNode(d, 1, 0, 0, 100), 2: newNode(d, 2, 0, 0, 100)}
+	assert.Equal(t, 2, d.nodes[1].LogRateLimit())
+
+	for i := 0; i < 5; i++ {
+		d.handleRecvEvent(&event{NodeId: 1, Type: eventTypeUartWrite, Data: []byte("flood\n")})
+		d.handleRecvEvent(&event{NodeId: 2, Type: eventTypeRadioLog, Data: []byte("flood")})
+	}
+	// UART writes carry the CLI output and are never throttled
+	assert.Equal(t, uint64(0), d.nodes[1].ThrottledEvents())
+	assert.Equal(t, uint64(3), d.nodes[2].ThrottledEvents())
+	assert.Equal(t, uint64(3), d.Counters.ThrottledEvents)
+	assert.Equal(t, uint64(5), d.Counters.UartWriteEvents)
+
+	d.SetNodeLogRateLimit(2, 0)
+	assert.Equal(t, 0, d.nodes[2].LogRateLimit())
+	assert.Equal(t, 2, d.nodes[1].LogRateLimit())
+	d.handleRecvEvent(&event{NodeId: 2, Type: eventTypeRadioLog, Data: []byte("flood")})
+	assert.Equal(t, uint64(3), d.nodes[2].ThrottledEvents())
+
+	d.SetLogRateLimit(10)
+	assert.Equal(t, 10, d.GetLogRateLimit())
+	assert.True(t, d.nodes[1].LogRateLimit() == 10 && d.nodes[2].LogRateLimit() == 10)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	maxTimelineEventCount = 1000000
+)
+
+// Types of timeline events.
+const (
+	TimelineNodeAdd     = "node_add"
+	TimelineNodeDelete  = "node_del"
+	TimelineNodeFail    = "node_fail"
+	TimelineNodeRecover = "node_recover"
+	TimelineRole        = "role"
+	TimelineParent      = "parent"
+	TimelinePartition   = "partition"
+	TimelineRloc16      = "rloc16"
+)
+
+// TimelineEvent is a topology-affecting event of a node. For changes, Old and New are the values before and after the
+// change: role names, parent extended addresses, partition IDs or RLOC16s in hex. Unknown values are omitted.
+//
+// The timeline is the only record of node state changes: the role changes and node histories are derived from it.
+type TimelineEvent struct {
+	Time uint64 `json:"time_us"`
+	Type string `json:"type"`
+	Node NodeId `json:"node"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// Timeline is a list of timeline events in time order.
+type Timeline []TimelineEvent
+
+// Write writes the timeline in JSONL format: one JSON object per event and line.
+func (tl Timeline) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for i := range tl {
+		if err := enc.Encode(&tl[i]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes the timeline to the file in JSONL format.
+func (tl Timeline) WriteFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = tl.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (d *Dispatcher) addTimelineEvent(typ string, id NodeId, oldVal, newVal string) {
+	d.timeline = append(d.timeline, TimelineEvent{
+		Time: d.CurTime,
+		Type: typ,
+		Node: id,
+		Old:  oldVal,
+		New:  newVal,
+	})
+	if len(d.timeline) > maxTimelineEventCount {
+		d.timeline = d.timeline[1:]
+		d.timelineDropped++
+	}
+}
+
+// timelineSince returns the retained timeline events starting from the sequence number, i.e. the number of events
+// recorded before.
+func (d *Dispatcher) timelineSince(seq uint64) Timeline {
+	if seq <= d.timelineDropped {
+		return d.timeline
+	}
+	if seq-d.timelineDropped >= uint64(len(d.timeline)) {
+		return nil
+	}
+	return d.timeline[seq-d.timelineDropped:]
+}
+
+// timelineSeq returns the sequence number of the next timeline event.
+func (d *Dispatcher) timelineSeq() uint64 {
+	return d.timelineDropped + uint64(len(d.timeline))
+}
+
+func (d *Dispatcher) resetTimelines() {
+	d.timeline = nil
+	d.timelineDropped = 0
+	d.timelineFrom = 0
+	d.roleChangesFrom = 0
+}
+
+func (d *Dispatcher) onTimelineRole(node *Node, oldRole OtDeviceRole) {
+	if oldRole != node.Role {
+		d.addTimelineEvent(TimelineRole, node.Id, oldRole.String(), node.Role.String())
+	}
+}
+
+func (d *Dispatcher) onTimelineParent(node *Node, oldParent uint64) {
+	if oldParent != node.parent {
+		d.addTimelineEvent(TimelineParent, node.Id, formatTimelineExtAddr(oldParent), formatTimelineExtAddr(node.parent))
+	}
+}
+
+func (d *Dispatcher) onTimelinePartition(node *Node, oldPartitionId uint32) {
+	if oldPartitionId != node.PartitionId {
+		d.addTimelineEvent(TimelinePartition, node.Id, formatTimelinePartitionId(oldPartitionId),
+			formatTimelinePartitionId(node.PartitionId))
+	}
+}
+
+func (d *Dispatcher) onTimelineRloc16(node *Node, oldRloc16 uint16) {
+	if oldRloc16 != node.Rloc16 {
+		d.addTimelineEvent(TimelineRloc16, node.Id, formatTimelineRloc16(oldRloc16), formatTimelineRloc16(node.Rloc16))
+	}
+}
+
+func formatTimelineExtAddr(extaddr uint64) string {
+	if extaddr == 0 || extaddr == InvalidExtAddr {
+		return ""
+	}
+	return fmt.Sprintf("%016x", extaddr)
+}
+
+func formatTimelinePartitionId(parid uint32) string {
+	if parid == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%08x", parid)
+}
+
+func formatTimelineRloc16(rloc16 uint16) string {
+	if rloc16 == threadconst.InvalidRloc16 {
+		return ""
+	}
+	return fmt.Sprintf("%04x", rloc16)
+}
+
+// parseTimelineHex parses a hex value of a timeline event, returning 0 for an unknown value.
+func parseTimelineHex(s string) uint64 {
+	val, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
+// parseTimelineRole parses a role name of a timeline event.
+func parseTimelineRole(s string) OtDeviceRole {
+	for role := OtDeviceRoleDisabled; role <= OtDeviceRoleLeader; role++ {
+		if role.String() == s {
+			return role
+		}
+	}
+	return OtDeviceRoleDisabled
+}
+
+// Timeline returns the recorded timeline events of all nodes in time order.
+func (d *Dispatcher) Timeline() Timeline {
+	events := d.timelineSince(d.timelineFrom)
+	timeline := make(Timeline, len(events))
+	copy(timeline, events)
+	return timeline
+}
+
+// ResetTimeline discards the timeline events returned by Timeline. The role changes and node histories, which are
+// derived from the same events, are kept.
+func (d *Dispatcher) ResetTimeline() {
+	d.timelineFrom = d.timelineSeq()
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func TestTimeline(t *testing.T) {
+	d := &Dispatcher{cbHandler: nopCallbackHandler{}, vis: visualize.NewNopVisualizer()}
+	node := &Node{D: d, Id: 1, Role: OtDeviceRoleLeader}
+
+	d.CurTime = 10
+	d.onTimelineRole(node, OtDeviceRoleDetached)
+	d.onTimelineRole(node, OtDeviceRoleLeader)
+	node.PartitionId = 0x1234
+	d.onTimelinePartition(node, 0)
+	node.parent = 0x1122334455667788
+	d.onTimelineParent(node, InvalidExtAddr)
+	node.Rloc16 = 0x0400
+	d.onTimelineRloc16(node, threadconst.InvalidRloc16)
+	d.CurTime = 20
+	node.Fail()
+	node.Fail()
+
+	timeline := d.Timeline()
+	assert.Equal(t, 5, len(timeline))
+	assert.Equal(t, TimelineEvent{Time: 10, Type: TimelineRole, Node: 1, Old: "detached", New: "leader"}, timeline[0])
+	assert.Equal(t, TimelineEvent{Time: 10, Type: TimelinePartition, Node: 1, New: "00001234"}, timeline[1])
+	assert.Equal(t, TimelineEvent{Time: 10, Type: TimelineParent, Node: 1, New: "1122334455667788"}, timeline[2])
+	assert.Equal(t, TimelineEvent{Time: 10, Type: TimelineRloc16, Node: 1, New: "0400"}, timeline[3])
+	assert.Equal(t, TimelineEvent{Time: 20, Type: TimelineNodeFail, Node: 1}, timeline[4])
+
+	var sb strings.Builder
+	assert.Nil(t, timeline[:2].Write(&sb))
+	assert.Equal(t, `{"time_us":10,"type":"role","node":1,"old":"detached","new":"leader"}
+{"time_us":10,"type":"partition","node":1,"new":"00001234"}
+`, sb.String())
+
+	d.ResetTimeline()
+	assert.Equal(t, 0, len(d.Timeline()))
+	assert.Equal(t, 1, len(d.RoleChanges()))
+	d.CurTime = 30
+	node.Recover()
+	assert.Equal(t, []TimelineEvent{{Time: 30, Type: TimelineNodeRecover, Node: 1}}, []TimelineEvent(d.Timeline()))
+}
+
+func TestTimelineDropped(t *testing.T) {
+	d := &Dispatcher{}
+	d.timeline = Timeline{{Time: 1, Type: TimelineNodeAdd, Node: 1}, {Time: 2, Type: TimelineNodeAdd, Node: 2}}
+	d.timelineDropped = 10
+
+	assert.Equal(t, 2, len(d.timelineSince(0)))
+	assert.Equal(t, 1, len(d.timelineSince(11)))
+	assert.Empty(t, d.timelineSince(12))
+	assert.Equal(t, uint64(12), d.timelineSeq())
+}
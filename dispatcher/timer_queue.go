@@ -0,0 +1,99 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"container/heap"
+)
+
+type timerItem struct {
+	Timestamp uint64
+	Task      func()
+	seq       uint64
+}
+
+// timerQueue holds tasks to run at specified virtual times.
+// Tasks with the same timestamp run in the order they were added.
+type timerQueue struct {
+	q       []*timerItem
+	nextSeq uint64
+}
+
+func (tq timerQueue) Len() int {
+	return len(tq.q)
+}
+
+func (tq timerQueue) Less(i, j int) bool {
+	if tq.q[i].Timestamp != tq.q[j].Timestamp {
+		return tq.q[i].Timestamp < tq.q[j].Timestamp
+	}
+	return tq.q[i].seq < tq.q[j].seq
+}
+
+func (tq timerQueue) Swap(i, j int) {
+	tq.q[i], tq.q[j] = tq.q[j], tq.q[i]
+}
+
+func (tq *timerQueue) Push(x interface{}) {
+	tq.q = append(tq.q, x.(*timerItem))
+}
+
+func (tq *timerQueue) Pop() (elem interface{}) {
+	tqlen := len(tq.q)
+	elem = tq.q[tqlen-1]
+	tq.q = tq.q[:tqlen-1]
+	return
+}
+
+func (tq timerQueue) NextTimestamp() uint64 {
+	if len(tq.q) > 0 {
+		return tq.q[0].Timestamp
+	} else {
+		return Ever
+	}
+}
+
+func (tq *timerQueue) Add(timestamp uint64, task func()) {
+	heap.Push(tq, &timerItem{
+		Timestamp: timestamp,
+		Task:      task,
+		seq:       tq.nextSeq,
+	})
+	tq.nextSeq += 1
+}
+
+func (tq *timerQueue) PopNext() *timerItem {
+	return heap.Pop(tq).(*timerItem)
+}
+
+func newTimerQueue() *timerQueue {
+	tq := &timerQueue{
+		q: []*timerItem{},
+	}
+	heap.Init(tq)
+	return tq
+}
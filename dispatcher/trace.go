@@ -0,0 +1,216 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// Process IDs of the trace: the same activity is traced on a wall-clock and a virtual time timeline.
+const (
+	traceWallClockPid   = 1
+	traceVirtualTimePid = 2
+	traceDispatcherTid  = 0 // thread ID of the dispatcher, nodes use their node IDs
+)
+
+// traceEvent is an event of the Chrome trace event format, see
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat,omitempty"`
+	Phase string                 `json:"ph"`
+	Ts    float64                `json:"ts"` // microseconds
+	Dur   *float64               `json:"dur,omitempty"`
+	Pid   int                    `json:"pid"`
+	Tid   NodeId                 `json:"tid"`
+	Scope string                 `json:"s,omitempty"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// nodeWake is the wake-up of a node which has not gone back to sleep yet.
+type nodeWake struct {
+	name string
+	real time.Time
+	time uint64
+}
+
+// tracer writes the activity of the dispatcher to a file in the JSON array format of the Chrome trace viewer
+// (chrome://tracing or https://ui.perfetto.dev). Spans of wall-clock time are traced on the "wall clock" process, and
+// at the virtual time they happen on the "virtual time" process.
+type tracer struct {
+	file    *os.File
+	w       *bufio.Writer
+	start   time.Time
+	count   int
+	threads map[NodeId]struct{}
+	wakes   map[NodeId]nodeWake
+}
+
+func newTracer(filename string) (*tracer, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tracer{
+		file:    f,
+		w:       bufio.NewWriter(f),
+		start:   time.Now(),
+		threads: map[NodeId]struct{}{},
+		wakes:   map[NodeId]nodeWake{},
+	}
+	_, _ = t.w.WriteString("[\n")
+	t.writeMetadata("process_name", traceWallClockPid, traceDispatcherTid, "wall clock")
+	t.writeMetadata("process_name", traceVirtualTimePid, traceDispatcherTid, "virtual time")
+	return t, nil
+}
+
+func (t *tracer) write(evt *traceEvent) {
+	if t.file == nil {
+		return
+	}
+
+	data, err := json.Marshal(evt)
+	simplelogger.PanicIfError(err)
+	if t.count > 0 {
+		_, _ = t.w.WriteString(",\n")
+	}
+	_, err = t.w.Write(data)
+	t.count++
+	if err != nil {
+		simplelogger.Errorf("write trace file %s failed: %v", t.file.Name(), err)
+		_ = t.file.Close()
+		t.file = nil
+	}
+}
+
+func (t *tracer) writeMetadata(name string, pid int, tid NodeId, value string) {
+	t.write(&traceEvent{Name: name, Phase: "M", Pid: pid, Tid: tid, Args: map[string]interface{}{"name": value}})
+}
+
+// thread names the thread of the node on both timelines when it is first traced.
+func (t *tracer) thread(tid NodeId) {
+	if _, ok := t.threads[tid]; ok {
+		return
+	}
+
+	t.threads[tid] = struct{}{}
+	name := "dispatcher"
+	if tid != traceDispatcherTid {
+		name = fmt.Sprintf("node %d", tid)
+	}
+	t.writeMetadata("thread_name", traceWallClockPid, tid, name)
+	t.writeMetadata("thread_name", traceVirtualTimePid, tid, name)
+}
+
+func (t *tracer) wallTs(real time.Time) float64 {
+	return float64(real.Sub(t.start).Nanoseconds()) / 1000
+}
+
+// span traces the activity which started at the wall-clock time and lasted until now, and happened at the virtual time
+// curTime for virtualDur microseconds.
+func (t *tracer) span(cat string, name string, tid NodeId, begin time.Time, curTime uint64, virtualDur uint64,
+	args map[string]interface{}) {
+	t.thread(tid)
+
+	wallDur := t.wallTs(time.Now()) - t.wallTs(begin)
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	args["time_us"] = curTime
+	t.write(&traceEvent{Name: name, Cat: cat, Phase: "X", Ts: t.wallTs(begin), Dur: &wallDur, Pid: traceWallClockPid,
+		Tid: tid, Args: args})
+
+	if virtualDur == 0 {
+		t.write(&traceEvent{Name: name, Cat: cat, Phase: "i", Ts: float64(curTime), Pid: traceVirtualTimePid, Tid: tid,
+			Scope: "t", Args: args})
+	} else {
+		dur := float64(virtualDur)
+		t.write(&traceEvent{Name: name, Cat: cat, Phase: "X", Ts: float64(curTime), Dur: &dur, Pid: traceVirtualTimePid,
+			Tid: tid, Args: args})
+	}
+}
+
+// onNodeWake records that the dispatcher woke up the node for an alarm or event, unless it is already awake.
+func (t *tracer) onNodeWake(id NodeId, name string, curTime uint64) {
+	if _, ok := t.wakes[id]; ok {
+		return
+	}
+	t.wakes[id] = nodeWake{name: name, real: time.Now(), time: curTime}
+}
+
+// onNodeSleep traces the handling of the wake-up of the node, which lasts until the node goes back to sleep.
+func (t *tracer) onNodeSleep(id NodeId) {
+	wake, ok := t.wakes[id]
+	if !ok {
+		return
+	}
+	delete(t.wakes, id)
+	t.span("node", wake.name, id, wake.real, wake.time, 0, nil)
+}
+
+func (t *tracer) onNodeDeleted(id NodeId) {
+	delete(t.wakes, id)
+}
+
+func (t *tracer) close() error {
+	if t.file == nil {
+		return nil
+	}
+
+	_, _ = t.w.WriteString("\n]\n")
+	err := t.w.Flush()
+	if closeErr := t.file.Close(); err == nil {
+		err = closeErr
+	}
+	t.file = nil
+	return err
+}
+
+// traceEventName returns the name of the wake-up of a node by an event sent to it.
+func traceEventName(typ eventType) string {
+	switch typ {
+	case eventTypeUartWrite:
+		return "uart"
+	case eventTypeRangingResult:
+		return "ranging"
+	case eventTypeRfSimRaw:
+		return "rfsim raw"
+	case eventTypeInfraPacket:
+		return "infra"
+	default:
+		return fmt.Sprintf("event %d", typ)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "otns")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	d := NewOfflineDispatcher(DefaultConfig(), func(msg OfflineMessage) {})
+	d.trace, err = newTracer(filepath.Join(dir, "trace.json"))
+	assert.Nil(t, err)
+
+	d.AddNode(1, 0, 0, 100)
+	d.AddNode(2, 50, 0, 100)
+	d.InjectStatusPush(1, "extaddr=0000000000000001;rloc16=1024")
+	d.InjectStatusPush(2, "extaddr=0000000000000002;rloc16=2048")
+	d.setSleeping(1)
+	d.setSleeping(2)
+
+	// broadcast data frame with an 11-byte PSDU
+	d.InjectRadioFrame(1, 0, []byte{11, 0x41, 0x98, 1, 0xce, 0xfa, 0xff, 0xff, 0, 0, 0, 0})
+	d.RunOffline(1000)
+	d.eventChan <- &event{NodeId: 1, Type: eventTypeAlarmFired, Delay: 100}
+	d.eventChan <- &event{NodeId: 2, Type: eventTypeAlarmFired, Delay: 100}
+	assert.Equal(t, 2, d.RecvEvents())
+	d.Stop()
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "trace.json"))
+	assert.Nil(t, err)
+	var events []traceEvent
+	assert.Nil(t, json.Unmarshal(data, &events))
+
+	names := map[string]string{}
+	var spans []traceEvent
+	for _, evt := range events {
+		if evt.Phase == "M" {
+			names[fmt.Sprintf("%s/%d/%d", evt.Name, evt.Pid, evt.Tid)] = evt.Args["name"].(string)
+		} else {
+			spans = append(spans, evt)
+		}
+	}
+	assert.Equal(t, "wall clock", names["process_name/1/0"])
+	assert.Equal(t, "virtual time", names["process_name/2/0"])
+	assert.Equal(t, "dispatcher", names["thread_name/2/0"])
+	assert.Equal(t, "node 2", names["thread_name/1/2"])
+
+	// the dispatch of the frame, the handling by both nodes and the received events, on both timelines
+	assert.Equal(t, 8, len(spans))
+	assert.Equal(t, "dispatch", spans[0].Name)
+	assert.Equal(t, traceWallClockPid, spans[0].Pid)
+	assert.Equal(t, 1.0, spans[1].Ts)
+	assert.Equal(t, float64(frameAirtime(11)), *spans[1].Dur)
+	assert.Equal(t, traceVirtualTimePid, spans[1].Pid)
+	assert.Equal(t, "tx done", spans[2].Name)
+	assert.Equal(t, 1, spans[2].Tid)
+	assert.Equal(t, "i", spans[3].Phase)
+	assert.Equal(t, "rx", spans[4].Name)
+	assert.Equal(t, 2, spans[4].Tid)
+	assert.Equal(t, "RecvEvents", spans[6].Name)
+	assert.Equal(t, 2.0, spans[6].Args["events"])
+	assert.Equal(t, 1000.0, spans[7].Ts)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+)
+
+type UpgradeResult struct {
+	NodeId     NodeId
+	Executable string
+	StartTime  uint64 // time when the node was stopped for upgrade
+	RejoinTime uint64 // time when the node was attached again, 0 if not rejoined yet
+}
+
+// RejoinDuration returns the duration from the node being stopped to being attached again.
+func (ur *UpgradeResult) RejoinDuration() uint64 {
+	if ur.RejoinTime == 0 {
+		return 0
+	}
+	return ur.RejoinTime - ur.StartTime
+}
+
+// NotifyUpgrade notifies that the node is being restarted with a new executable.
+// The rejoin time is recorded when the upgraded node attaches again.
+func (d *Dispatcher) NotifyUpgrade(nodeid NodeId, executable string) {
+	ur := &UpgradeResult{
+		NodeId:     nodeid,
+		Executable: executable,
+		StartTime:  d.CurTime,
+	}
+	d.pendingUpgrades[nodeid] = ur
+	d.upgradeResults = append(d.upgradeResults, ur)
+}
+
+// UpgradeResults returns the results of all upgrades, including the ones not rejoined yet.
+func (d *Dispatcher) UpgradeResults() []*UpgradeResult {
+	results := make([]*UpgradeResult, len(d.upgradeResults))
+	copy(results, d.upgradeResults)
+	return results
+}
+
+func (d *Dispatcher) onUpgradedNodeRole(id NodeId, role OtDeviceRole) {
+	ur := d.pendingUpgrades[id]
+	if ur == nil || role < OtDeviceRoleChild {
+		return
+	}
+
+	ur.RejoinTime = d.CurTime
+	delete(d.pendingUpgrades, id)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestUpgradeRejoin(t *testing.T) {
+	d := &Dispatcher{
+		pendingUpgrades: map[NodeId]*UpgradeResult{},
+	}
+
+	d.CurTime = 1000000
+	d.NotifyUpgrade(1, "./ot-cli-ftd-v2")
+	d.CurTime = 1500000
+	d.onUpgradedNodeRole(1, OtDeviceRoleDetached)
+	assert.Equal(t, uint64(0), d.UpgradeResults()[0].RejoinDuration())
+
+	d.CurTime = 2500000
+	d.onUpgradedNodeRole(1, OtDeviceRoleChild)
+	d.CurTime = 3000000
+	d.onUpgradedNodeRole(1, OtDeviceRoleRouter)
+
+	results := d.UpgradeResults()
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, uint64(1500000), results[0].RejoinDuration())
+	assert.Equal(t, 0, len(d.pendingUpgrades))
+}
@@ -26,12 +26,30 @@
 
 package dispatcher
 
+import (
+	. "github.com/openthread/ot-ns/types"
+)
+
 type VisualizationOptions struct {
 	BroadcastMessage bool
 	UnicastMessage   bool
 	AckMessage       bool
 	RouterTable      bool
 	ChildTable       bool
+	Nodes            map[NodeId]struct{} // nodes the visualized messages are restricted to, or nil for all nodes
+}
+
+// involves returns if a message from the source to the destination node involves the nodes which messages are
+// visualized for. Broadcast messages only involve the source node.
+func (opts *VisualizationOptions) involves(srcid NodeId, dstid NodeId) bool {
+	if opts.Nodes == nil {
+		return true
+	}
+	if _, ok := opts.Nodes[srcid]; ok {
+		return true
+	}
+	_, ok := opts.Nodes[dstid]
+	return ok
 }
 
 func defaultVisualizationOptions() VisualizationOptions {
@@ -0,0 +1,268 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	DefaultStatsWindow    uint64 = 60 * 1000000 // us
+	DefaultStatsRetention int    = 60
+)
+
+type WindowMetric int
+
+const (
+	WindowMetricFrames WindowMetric = 1 << iota
+	WindowMetricBytes
+	WindowMetricAirtime
+	WindowMetricRetries
+	WindowMetricCca
+	WindowMetricDrops
+
+	WindowMetricAll = WindowMetricFrames | WindowMetricBytes | WindowMetricAirtime | WindowMetricRetries |
+		WindowMetricCca | WindowMetricDrops
+)
+
+var windowMetricNames = map[WindowMetric]string{
+	WindowMetricFrames:  "frames",
+	WindowMetricBytes:   "bytes",
+	WindowMetricAirtime: "airtime",
+	WindowMetricRetries: "retries",
+	WindowMetricCca:     "cca",
+	WindowMetricDrops:   "drops",
+}
+
+// ParseWindowMetrics parses a list of metric names into a WindowMetric set.
+func ParseWindowMetrics(names []string) (WindowMetric, error) {
+	var metrics WindowMetric
+	for _, name := range names {
+		found := false
+		for m, s := range windowMetricNames {
+			if s == name {
+				metrics |= m
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown metric: %s", name)
+		}
+	}
+	return metrics, nil
+}
+
+func (m WindowMetric) Has(metric WindowMetric) bool {
+	return m&metric != 0
+}
+
+func (m WindowMetric) String() string {
+	var names []string
+	for _, metric := range []WindowMetric{WindowMetricFrames, WindowMetricBytes, WindowMetricAirtime,
+		WindowMetricRetries, WindowMetricCca, WindowMetricDrops} {
+		if m.Has(metric) {
+			names = append(names, windowMetricNames[metric])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+type WindowStatsConfig struct {
+	Interval  uint64 // window length in us
+	Retention int    // number of completed windows to keep
+	Metrics   WindowMetric
+}
+
+func DefaultWindowStatsConfig() WindowStatsConfig {
+	return WindowStatsConfig{
+		Interval:  DefaultStatsWindow,
+		Retention: DefaultStatsRetention,
+		Metrics:   WindowMetricAll,
+	}
+}
+
+// PhyTxStats contains the PHY transmit statistics of a node in a time window.
+// Only the metrics enabled for the window are counted.
+type PhyTxStats struct {
+	Frames  uint64 `yaml:"frames,omitempty"`
+	Bytes   uint64 `yaml:"bytes,omitempty"`
+	Airtime uint64 `yaml:"airtime,omitempty"` // us
+}
+
+func (s *PhyTxStats) add(o *PhyTxStats) {
+	s.Frames += o.Frames
+	s.Bytes += o.Bytes
+	s.Airtime += o.Airtime
+}
+
+type TimeWindowStats struct {
+	Start uint64                 `yaml:"start"`
+	End   uint64                 `yaml:"end"`
+	PhyTx map[NodeId]*PhyTxStats `yaml:"phytx"`
+	Mac   map[NodeId]*MacStats   `yaml:"mac,omitempty"`
+}
+
+// Total returns the PHY transmit statistics summed over all nodes.
+func (w *TimeWindowStats) Total() PhyTxStats {
+	var total PhyTxStats
+	for _, s := range w.PhyTx {
+		total.add(s)
+	}
+	return total
+}
+
+// MacTotal returns the MAC statistics summed over all nodes.
+func (w *TimeWindowStats) MacTotal() MacStats {
+	var total MacStats
+	for _, s := range w.Mac {
+		total.add(s)
+	}
+	return total
+}
+
+// NodeIds returns the sorted IDs of nodes that transmitted in the window.
+func (w *TimeWindowStats) NodeIds() []NodeId {
+	ids := make([]NodeId, 0, len(w.PhyTx))
+	for id := range w.PhyTx {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+type windowStatsCollector struct {
+	cfg     WindowStatsConfig
+	current *TimeWindowStats
+	history []*TimeWindowStats
+}
+
+func newWindowStatsCollector(cfg WindowStatsConfig, now uint64) *windowStatsCollector {
+	wc := &windowStatsCollector{}
+	wc.Configure(cfg, now)
+	return wc
+}
+
+// Configure applies a new configuration and discards all collected windows.
+func (wc *windowStatsCollector) Configure(cfg WindowStatsConfig, now uint64) {
+	wc.cfg = cfg
+	wc.current = newTimeWindowStats(now, now+cfg.Interval)
+	wc.history = nil
+}
+
+func (wc *windowStatsCollector) Config() WindowStatsConfig {
+	return wc.cfg
+}
+
+func newTimeWindowStats(start, end uint64) *TimeWindowStats {
+	return &TimeWindowStats{
+		Start: start,
+		End:   end,
+		PhyTx: map[NodeId]*PhyTxStats{},
+		Mac:   map[NodeId]*MacStats{},
+	}
+}
+
+// Advance closes all windows ending at or before the specified time.
+func (wc *windowStatsCollector) Advance(now uint64) {
+	if now < wc.current.End {
+		return
+	}
+
+	interval := wc.cfg.Interval
+	wc.push(wc.current)
+
+	// windows passed without any activity, only the ones within retention are kept
+	idle := (now - wc.current.End) / interval
+	start := wc.current.End + idle*interval
+	if idle > uint64(wc.cfg.Retention) {
+		idle = uint64(wc.cfg.Retention)
+	}
+	for i := idle; i > 0; i-- {
+		wc.push(newTimeWindowStats(start-i*interval, start-(i-1)*interval))
+	}
+
+	wc.current = newTimeWindowStats(start, start+interval)
+}
+
+func (wc *windowStatsCollector) push(w *TimeWindowStats) {
+	wc.history = append(wc.history, w)
+	if len(wc.history) > wc.cfg.Retention {
+		wc.history = wc.history[len(wc.history)-wc.cfg.Retention:]
+	}
+}
+
+func (wc *windowStatsCollector) OnTransmit(id NodeId, psduLen int) {
+	stats := wc.current.PhyTx[id]
+	if stats == nil {
+		stats = &PhyTxStats{}
+		wc.current.PhyTx[id] = stats
+	}
+
+	if wc.cfg.Metrics.Has(WindowMetricFrames) {
+		stats.Frames += 1
+	}
+	if wc.cfg.Metrics.Has(WindowMetricBytes) {
+		stats.Bytes += uint64(psduLen)
+	}
+	if wc.cfg.Metrics.Has(WindowMetricAirtime) {
+		stats.Airtime += frameAirtime(psduLen)
+	}
+}
+
+func (wc *windowStatsCollector) OnMacCounters(id NodeId, delta MacCounters) {
+	if !wc.cfg.Metrics.Has(WindowMetricRetries | WindowMetricCca) {
+		return
+	}
+
+	stats := getMacStats(wc.current.Mac, id)
+	if wc.cfg.Metrics.Has(WindowMetricRetries) {
+		stats.TxRetries += delta.TxRetry
+	}
+	if wc.cfg.Metrics.Has(WindowMetricCca) {
+		stats.CcaFailures += delta.TxErrCca
+	}
+}
+
+func (wc *windowStatsCollector) OnFrameDropped(id NodeId, reason string) {
+	if wc.cfg.Metrics.Has(WindowMetricDrops) {
+		getMacStats(wc.current.Mac, id).addDrop(reason, 1)
+	}
+}
+
+// Windows returns the completed windows, oldest first.
+func (wc *windowStatsCollector) Windows() []*TimeWindowStats {
+	windows := make([]*TimeWindowStats, len(wc.history))
+	copy(windows, wc.history)
+	return windows
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestWindowStatsCollector(t *testing.T) {
+	wc := newWindowStatsCollector(WindowStatsConfig{Interval: 10, Retention: 3, Metrics: WindowMetricAll}, 0)
+
+	wc.OnTransmit(1, 10)
+	wc.OnTransmit(1, 20)
+	wc.OnTransmit(2, 10)
+	wc.Advance(9)
+	assert.Equal(t, 0, len(wc.Windows()))
+
+	wc.Advance(10)
+	windows := wc.Windows()
+	assert.Equal(t, 1, len(windows))
+	assert.True(t, windows[0].Start == 0 && windows[0].End == 10)
+	total := windows[0].Total()
+	assert.Equal(t, uint64(3), total.Frames)
+	assert.Equal(t, uint64(40), total.Bytes)
+	assert.Equal(t, frameAirtime(10)*2+frameAirtime(20), total.Airtime)
+	assert.Equal(t, []NodeId{1, 2}, windows[0].NodeIds())
+
+	// idle windows are recorded, but no more than retention allows
+	wc.Advance(1000)
+	windows = wc.Windows()
+	assert.Equal(t, 3, len(windows))
+	assert.True(t, windows[0].Start == 970 && windows[2].End == 1000)
+	assert.Equal(t, 0, len(windows[2].PhyTx))
+
+	wc.Configure(WindowStatsConfig{Interval: 100, Retention: 3, Metrics: WindowMetricFrames}, 1000)
+	assert.Equal(t, 0, len(wc.Windows()))
+	wc.OnTransmit(1, 10)
+	wc.Advance(1100)
+	total = wc.Windows()[0].Total()
+	assert.True(t, total.Frames == 1 && total.Bytes == 0 && total.Airtime == 0)
+}
+
+func TestParseWindowMetrics(t *testing.T) {
+	metrics, err := ParseWindowMetrics([]string{"frames", "airtime"})
+	assert.Nil(t, err)
+	assert.Equal(t, WindowMetricFrames|WindowMetricAirtime, metrics)
+	assert.Equal(t, "frames,airtime", metrics.String())
+
+	metrics, err = ParseWindowMetrics([]string{"drops", "retries", "cca"})
+	assert.Nil(t, err)
+	assert.Equal(t, "retries,cca,drops", metrics.String())
+
+	_, err = ParseWindowMetrics([]string{"rssi"})
+	assert.NotNil(t, err)
+}
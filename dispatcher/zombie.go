@@ -0,0 +1,138 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	zombieCheckInterval = 1000000 // us
+)
+
+// Zombie is a node which stopped producing events while not asleep: it has no alarm scheduled, is not paused and the
+// dispatcher is not waiting for it, e.g. a node process which hangs or was force failed after a stall.
+type Zombie struct {
+	Node      NodeId `json:"node"`
+	LastEvent uint64 `json:"last_event_us"` // time of the last event received from the node
+	Detected  uint64 `json:"detected_us"`
+}
+
+type zombieDetector struct {
+	timeout   uint64 // us, or 0 if the detection is disabled
+	nextCheck uint64
+	zombies   map[NodeId]*Zombie
+	handlers  []func(*Zombie)
+}
+
+// GetZombieTimeout returns the time (in us) without events after which a node is considered a zombie, or 0 if the
+// detection is disabled.
+func (d *Dispatcher) GetZombieTimeout() uint64 {
+	return d.zombie.timeout
+}
+
+// SetZombieTimeout sets the time (in us) without events after which a node is considered a zombie, or disables the
+// detection if timeout is 0.
+func (d *Dispatcher) SetZombieTimeout(timeout uint64) {
+	d.zombie.timeout = timeout
+	d.zombie.nextCheck = d.CurTime
+	if timeout == 0 {
+		d.zombie.zombies = nil
+	}
+}
+
+// Zombies returns the current zombie nodes in order of node IDs.
+func (d *Dispatcher) Zombies() []*Zombie {
+	zombies := make([]*Zombie, 0, len(d.zombie.zombies))
+	for _, z := range d.zombie.zombies {
+		zombies = append(zombies, z)
+	}
+	sort.Slice(zombies, func(i, j int) bool {
+		return zombies[i].Node < zombies[j].Node
+	})
+	return zombies
+}
+
+// SubscribeZombies registers a callback for newly detected zombie nodes.
+// The callback is called in the dispatcher goroutine and must return quickly.
+func (d *Dispatcher) SubscribeZombies(cb func(*Zombie)) {
+	d.zombie.handlers = append(d.zombie.handlers, cb)
+}
+
+// isZombie returns if the node has been silent for the zombie timeout while not asleep.
+func (d *Dispatcher) isZombie(node *Node) bool {
+	if node.isPaused || d.CurTime < node.lastEventTime+d.zombie.timeout {
+		return false
+	}
+
+	if _, alive := d.aliveNodes[node.Id]; alive {
+		// the dispatcher is waiting for the node, which is reported by the stall detection
+		return false
+	}
+	return d.alarmMgr.GetTimestamp(node.Id) == Ever
+}
+
+func (d *Dispatcher) checkZombies() {
+	zd := &d.zombie
+	if zd.timeout == 0 || d.cfg.Real || d.CurTime < zd.nextCheck {
+		return
+	}
+	zd.nextCheck = d.CurTime + zombieCheckInterval
+
+	var detected []*Zombie
+	for id, node := range d.nodes {
+		if !d.isZombie(node) {
+			delete(zd.zombies, id)
+			continue
+		}
+
+		if zd.zombies[id] != nil {
+			continue
+		}
+
+		z := &Zombie{Node: id, LastEvent: node.lastEventTime, Detected: d.CurTime}
+		if zd.zombies == nil {
+			zd.zombies = map[NodeId]*Zombie{}
+		}
+		zd.zombies[id] = z
+		detected = append(detected, z)
+	}
+
+	sort.Slice(detected, func(i, j int) bool {
+		return detected[i].Node < detected[j].Node
+	})
+	for _, z := range detected {
+		d.Counters.ZombieNodes++
+		simplelogger.Warnf("node %d is a zombie: no events since %d", z.Node, z.LastEvent)
+		for _, handler := range zd.handlers {
+			handler(z)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZombieDetector(t *testing.T) {
+	d := newStallTestDispatcher(StallConfig{})
+	var detected []*Zombie
+	d.SubscribeZombies(func(z *Zombie) {
+		detected = append(detected, z)
+	})
+
+	// node 1 sleeps until its alarm at 2s, node 2 has no alarm
+	d.CurTime = 1000000
+	d.checkZombies()
+	assert.Empty(t, d.Zombies())
+
+	d.SetZombieTimeout(5000000)
+	d.CurTime = 4000000
+	d.checkZombies()
+	assert.Empty(t, d.Zombies())
+
+	d.CurTime = 5000000
+	d.checkZombies()
+	assert.Equal(t, 1, len(d.Zombies()))
+	assert.Equal(t, &Zombie{Node: 2, LastEvent: 0, Detected: 5000000}, d.Zombies()[0])
+	assert.Equal(t, 1, len(detected))
+	assert.Equal(t, uint64(1), d.Counters.ZombieNodes)
+
+	// each zombie is reported once
+	d.CurTime = 6000000
+	d.checkZombies()
+	assert.Equal(t, 1, len(detected))
+
+	// paused nodes are not zombies
+	d.nodes[2].isPaused = true
+	d.CurTime = 7000000
+	d.checkZombies()
+	assert.Empty(t, d.Zombies())
+
+	// a node waited for by the dispatcher is not a zombie
+	d.nodes[2].isPaused = false
+	d.aliveNodes[2] = struct{}{}
+	d.CurTime = 8000000
+	d.checkZombies()
+	assert.Empty(t, d.Zombies())
+
+	d.SetZombieTimeout(0)
+	delete(d.aliveNodes, 2)
+	d.CurTime = 9000000
+	d.checkZombies()
+	assert.Empty(t, d.Zombies())
+	assert.Equal(t, 1, len(detected))
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package lowpan
+
+import (
+	"encoding/binary"
+)
+
+const (
+	dispatchMeshMask  = 0xc0
+	dispatchMesh      = 0x80
+	dispatchFragMask  = 0xf8
+	dispatchFrag1     = 0xc0
+	dispatchFragN     = 0xe0
+	meshHopsLeftMask  = 0x0f
+	meshHopsLeftExtra = 0x0f
+
+	frag1HeaderLength = 4
+	fragNHeaderLength = 5
+)
+
+// FragHeader is the 6LoWPAN fragment header (RFC 4944) of a frame.
+type FragHeader struct {
+	DatagramSize uint16
+	DatagramTag  uint16
+	Offset       uint16 // offset of the fragment in the datagram in bytes, always 0 for the first fragment
+	First        bool
+	Length       int // length of the fragment payload following the header
+}
+
+// DissectFragHeader dissects the fragment header of a MAC payload, which may follow a mesh header.
+// It returns nil if the payload is not a fragment.
+func DissectFragHeader(payload []byte) *FragHeader {
+	offset := 0
+	if len(payload) > 0 && payload[0]&dispatchMeshMask == dispatchMesh {
+		offset = meshHeaderLength(payload[0])
+	}
+
+	if len(payload) < offset+frag1HeaderLength {
+		return nil
+	}
+
+	hdr := &FragHeader{
+		DatagramSize: binary.BigEndian.Uint16(payload[offset:]) & 0x07ff,
+		DatagramTag:  binary.BigEndian.Uint16(payload[offset+2:]),
+	}
+
+	switch payload[offset] & dispatchFragMask {
+	case dispatchFrag1:
+		hdr.First = true
+		hdr.Length = len(payload) - offset - frag1HeaderLength
+	case dispatchFragN:
+		if len(payload) < offset+fragNHeaderLength {
+			return nil
+		}
+		hdr.Offset = uint16(payload[offset+4]) * 8
+		hdr.Length = len(payload) - offset - fragNHeaderLength
+	default:
+		return nil
+	}
+
+	return hdr
+}
+
+func meshHeaderLength(dispatch byte) int {
+	length := 1
+	if dispatch&meshHopsLeftMask == meshHopsLeftExtra {
+		length += 1
+	}
+	// V and F flags select short (2 bytes) or extended (8 bytes) originator and final addresses
+	for _, flag := range []byte{0x20, 0x10} {
+		if dispatch&flag != 0 {
+			length += 2
+		} else {
+			length += 8
+		}
+	}
+	return length
+}
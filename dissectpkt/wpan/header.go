@@ -0,0 +1,192 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package wpan
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	FrameVersion2015 = 2
+
+	SrcAddrModeNone     = 0
+	SrcAddrModeShort    = 2
+	SrcAddrModeExtended = 3
+
+	KeyIdMode0 = 0
+	KeyIdMode1 = 1
+	KeyIdMode2 = 2
+	KeyIdMode3 = 3
+
+	FcsLength = 2
+
+	ieHeaderTermination1 = 0x7e
+	ieHeaderTermination2 = 0x7f
+)
+
+var errFrameTooShort = errors.New("frame too short")
+
+// FrameHeader contains the MAC header fields needed to access the payload of a frame.
+type FrameHeader struct {
+	SrcAddrShort    uint16
+	SrcAddrExtended uint64
+	SecurityLevel   uint8
+	KeyIdMode       uint8
+	KeyIndex        uint8
+	FrameCounter    uint32
+	Length          int // length of the header, including the auxiliary security header and header IEs
+	MicLength       int
+}
+
+// Payload returns the (possibly encrypted) MAC payload of the PSDU, without MIC and FCS.
+func (h *FrameHeader) Payload(psdu []byte) []byte {
+	end := len(psdu) - h.MicLength - FcsLength
+	if end < h.Length {
+		return nil
+	}
+	return psdu[h.Length:end]
+}
+
+// DissectHeader dissects the MAC header of a data or command frame. Like Dissect, data starts with the channel.
+// Payload IEs are not supported, since they are encrypted together with the payload.
+func DissectHeader(data []byte) (*FrameHeader, error) {
+	if len(data) < 4 {
+		return nil, errFrameTooShort
+	}
+
+	psdu := data[1:]
+	var fc FrameControl
+	fc.Dissect(psdu)
+	dstAddrMode, srcAddrMode := fc.DstAddrMode(), fc.SourceAddrMode()
+	dstPanId, srcPanId := dstAddrMode != DstAddrModeNone, srcAddrMode != SrcAddrModeNone && !fc.PanidCompression()
+
+	if fc.FrameVersion() == FrameVersion2015 {
+		dstPanId, srcPanId = panIdPresent2015(dstAddrMode, srcAddrMode, fc.PanidCompression())
+	}
+
+	hdr := &FrameHeader{}
+	offset := 3
+	if dstPanId {
+		offset += 2
+	}
+	offset += addrLength(dstAddrMode)
+	if srcPanId {
+		offset += 2
+	}
+
+	if len(psdu) < offset+addrLength(srcAddrMode) {
+		return nil, errFrameTooShort
+	}
+	if srcAddrMode == SrcAddrModeShort {
+		hdr.SrcAddrShort = binary.LittleEndian.Uint16(psdu[offset:])
+	} else if srcAddrMode == SrcAddrModeExtended {
+		hdr.SrcAddrExtended = binary.LittleEndian.Uint64(psdu[offset:])
+	}
+	offset += addrLength(srcAddrMode)
+
+	if fc.SecurityEnabled() {
+		if len(psdu) < offset+1 {
+			return nil, errFrameTooShort
+		}
+		secCtrl := psdu[offset]
+		offset += 1
+		hdr.SecurityLevel = secCtrl & 0x07
+		hdr.KeyIdMode = (secCtrl >> 3) & 0x03
+		hdr.MicLength = [4]int{0, 4, 8, 16}[hdr.SecurityLevel&0x03]
+
+		if secCtrl&0x20 == 0 {
+			if len(psdu) < offset+4 {
+				return nil, errFrameTooShort
+			}
+			hdr.FrameCounter = binary.LittleEndian.Uint32(psdu[offset:])
+			offset += 4
+		}
+
+		keyIdLength := [4]int{0, 1, 5, 9}[hdr.KeyIdMode]
+		if len(psdu) < offset+keyIdLength {
+			return nil, errFrameTooShort
+		}
+		if keyIdLength > 0 {
+			hdr.KeyIndex = psdu[offset+keyIdLength-1]
+		}
+		offset += keyIdLength
+	}
+
+	if fc.IEPresent() {
+		for {
+			if len(psdu) < offset+2 {
+				return nil, errFrameTooShort
+			}
+			desc := binary.LittleEndian.Uint16(psdu[offset:])
+			offset += 2 + int(desc&0x7f)
+			id := (desc >> 7) & 0xff
+			if id == ieHeaderTermination1 {
+				return nil, errors.New("payload IEs not supported")
+			} else if id == ieHeaderTermination2 {
+				break
+			}
+			if len(psdu) <= offset+hdr.MicLength+FcsLength {
+				// header IEs without termination are followed by no payload
+				break
+			}
+		}
+	}
+
+	if len(psdu) < offset+hdr.MicLength+FcsLength {
+		return nil, errFrameTooShort
+	}
+	hdr.Length = offset
+	return hdr, nil
+}
+
+func addrLength(mode uint16) int {
+	switch mode {
+	case DstAddrModeShort:
+		return 2
+	case DstAddrModeExtended:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// panIdPresent2015 returns the presence of the destination and source PAN IDs in an IEEE 802.15.4-2015 frame.
+func panIdPresent2015(dstAddrMode, srcAddrMode uint16, panIdCompression bool) (bool, bool) {
+	switch {
+	case dstAddrMode == DstAddrModeNone && srcAddrMode == SrcAddrModeNone:
+		return panIdCompression, false
+	case srcAddrMode == SrcAddrModeNone:
+		return !panIdCompression, false
+	case dstAddrMode == DstAddrModeNone:
+		return false, !panIdCompression
+	case dstAddrMode == DstAddrModeExtended && srcAddrMode == SrcAddrModeExtended:
+		return !panIdCompression, false
+	default:
+		return true, !panIdCompression
+	}
+}
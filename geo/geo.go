@@ -0,0 +1,138 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+// Package geo maps simulation coordinates to geographic coordinates.
+//
+// The mapping uses an equirectangular projection around the origin, which is accurate for the extent of a site
+// survey (a few kilometers). Simulation X grows to the east and simulation Y grows to the south, matching the web
+// visualizer. Nodes are placed on a plane, so all nodes are at the altitude of the origin.
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// EarthRadius is the mean radius of the earth in meters.
+	EarthRadius = 6371008.8
+	// DefaultMetersPerUnit is the default length in meters of one simulation distance unit.
+	DefaultMetersPerUnit = 1.0
+)
+
+// Origin is the geographic position of the simulation origin (0, 0) and the scale of the simulation coordinates.
+type Origin struct {
+	Lat           float64 // latitude in degrees
+	Lon           float64 // longitude in degrees
+	Alt           float64 // altitude in meters
+	MetersPerUnit float64 // length in meters of one simulation distance unit
+}
+
+// Position is a geographic position.
+type Position struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+	Alt float64 `json:"alt"`
+}
+
+// NewOrigin creates a new Origin, validating the parameters.
+func NewOrigin(lat, lon, alt, metersPerUnit float64) (*Origin, error) {
+	if lat < -90 || lat > 90 {
+		return nil, errors.Errorf("latitude out of range: %v", lat)
+	}
+	// the projection degenerates at the poles
+	if math.Abs(lat) > 89 {
+		return nil, errors.Errorf("latitude too close to the pole: %v", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return nil, errors.Errorf("longitude out of range: %v", lon)
+	}
+	if metersPerUnit <= 0 {
+		return nil, errors.Errorf("meters per unit must be positive: %v", metersPerUnit)
+	}
+
+	return &Origin{Lat: lat, Lon: lon, Alt: alt, MetersPerUnit: metersPerUnit}, nil
+}
+
+// ParseOrigin parses an origin of format `<lat>,<lon>[,<alt>[,<meters-per-unit>]]`.
+func ParseOrigin(s string) (*Origin, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 || len(parts) > 4 {
+		return nil, errors.Errorf("invalid geo origin %q, expecting <lat>,<lon>[,<alt>[,<meters-per-unit>]]", s)
+	}
+
+	vals := []float64{0, 0, 0, DefaultMetersPerUnit}
+	for i, part := range parts {
+		val, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid geo origin %q", s)
+		}
+		vals[i] = val
+	}
+
+	return NewOrigin(vals[0], vals[1], vals[2], vals[3])
+}
+
+func (o *Origin) String() string {
+	return fmt.Sprintf("%.7f,%.7f,%g,%g", o.Lat, o.Lon, o.Alt, o.MetersPerUnit)
+}
+
+// ToGeo returns the geographic position of the simulation coordinates.
+func (o *Origin) ToGeo(x, y int) Position {
+	east := float64(x) * o.MetersPerUnit
+	north := -float64(y) * o.MetersPerUnit
+
+	return Position{
+		Lat: o.Lat + radToDeg(north/EarthRadius),
+		Lon: o.Lon + radToDeg(east/(EarthRadius*math.Cos(degToRad(o.Lat)))),
+		Alt: o.Alt,
+	}
+}
+
+// FromGeo returns the simulation coordinates nearest to the geographic position.
+func (o *Origin) FromGeo(lat, lon float64) (x, y int) {
+	north := degToRad(lat-o.Lat) * EarthRadius
+	east := degToRad(lon-o.Lon) * EarthRadius * math.Cos(degToRad(o.Lat))
+
+	return int(math.Round(east / o.MetersPerUnit)), int(math.Round(-north / o.MetersPerUnit))
+}
+
+// Meters returns the distance in meters of a distance in simulation units.
+func (o *Origin) Meters(units float64) float64 {
+	return units * o.MetersPerUnit
+}
+
+func degToRad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func radToDeg(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
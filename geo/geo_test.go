@@ -0,0 +1,78 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrigin(t *testing.T) {
+	o, err := ParseOrigin("37.422,-122.084")
+	assert.Nil(t, err)
+	assert.Equal(t, Origin{Lat: 37.422, Lon: -122.084, Alt: 0, MetersPerUnit: DefaultMetersPerUnit}, *o)
+
+	o, err = ParseOrigin("37.422, -122.084, 30, 0.5")
+	assert.Nil(t, err)
+	assert.Equal(t, Origin{Lat: 37.422, Lon: -122.084, Alt: 30, MetersPerUnit: 0.5}, *o)
+
+	for _, s := range []string{"", "37.422", "a,b", "91,0", "0,181", "0,0,0,0", "1,2,3,4,5"} {
+		_, err = ParseOrigin(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestToGeo(t *testing.T) {
+	o, _ := NewOrigin(0, 0, 10, 1)
+
+	pos := o.ToGeo(0, 0)
+	assert.Equal(t, Position{Lat: 0, Lon: 0, Alt: 10}, pos)
+
+	// one degree at the equator is about 111.2 km, Y grows to the south
+	pos = o.ToGeo(111195, 111195)
+	assert.InDelta(t, -1.0, pos.Lat, 1e-5)
+	assert.InDelta(t, 1.0, pos.Lon, 1e-5)
+	assert.Equal(t, 10.0, pos.Alt)
+
+	// a degree of longitude shrinks with the latitude
+	o, _ = NewOrigin(60, 0, 0, 2)
+	pos = o.ToGeo(27799, 0)
+	assert.InDelta(t, 1.0, pos.Lon, 1e-4)
+	assert.Equal(t, 111.0, o.Meters(55.5))
+}
+
+func TestFromGeo(t *testing.T) {
+	o, _ := NewOrigin(52.3731, 4.8922, 0, 0.25)
+
+	for _, xy := range [][2]int{{0, 0}, {100, 200}, {-350, 40}, {1000, -1000}} {
+		pos := o.ToGeo(xy[0], xy[1])
+		x, y := o.FromGeo(pos.Lat, pos.Lon)
+		assert.Equal(t, xy[0], x)
+		assert.Equal(t, xy[1], y)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+// Package mobility receives live node position updates from an external mobility simulator over UDP.
+//
+// Each datagram contains one or more position updates as JSON objects separated by newlines, e.g.
+//
+//	{"node":1,"x":120,"y":80,"time_us":15000000}
+//	{"node":2,"lat":37.4225,"lon":-122.0843}
+//
+// An update moves the node to x and y, or to the geographic position lat and lon in geographic mode, at the virtual
+// time time_us. Updates without a time, or with a time already passed, are applied immediately.
+package mobility
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	maxDatagramSize = 65507
+)
+
+// Update is a position update of a node.
+type Update struct {
+	Node NodeId   `json:"node"`
+	Time *uint64  `json:"time_us,omitempty"` // virtual time (in us) to apply the update at, or nil to apply it now
+	X    *float64 `json:"x,omitempty"`
+	Y    *float64 `json:"y,omitempty"`
+	Lat  *float64 `json:"lat,omitempty"`
+	Lon  *float64 `json:"lon,omitempty"`
+}
+
+// IsGeo returns if the update moves the node to a geographic position.
+func (u *Update) IsGeo() bool {
+	return u.Lat != nil
+}
+
+func (u *Update) validate() error {
+	if u.Node <= 0 {
+		return errors.Errorf("invalid node: %d", u.Node)
+	}
+
+	switch {
+	case u.X != nil && u.Y != nil && u.Lat == nil && u.Lon == nil:
+		return nil
+	case u.Lat != nil && u.Lon != nil && u.X == nil && u.Y == nil:
+		return nil
+	default:
+		return errors.Errorf("node %d: expecting either x and y, or lat and lon", u.Node)
+	}
+}
+
+// ParseUpdates parses the position updates of a datagram.
+func ParseUpdates(data []byte) ([]Update, error) {
+	var updates []Update
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, maxDatagramSize), maxDatagramSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var update Update
+		if err := json.Unmarshal(line, &update); err != nil {
+			return nil, errors.Wrapf(err, "invalid update %q", line)
+		}
+		if err := update.validate(); err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, scanner.Err()
+}
+
+// Sink applies the position updates of a datagram.
+type Sink func(updates []Update)
+
+// Serve receives position updates on the UDP listen address until ctx is done.
+func Serve(ctx context.Context, listenAddr string, sink Sink) error {
+	conn, err := net.ListenPacket("udp", listenAddr)
+	if err != nil {
+		return err
+	}
+
+	simplelogger.Infof("OTNS mobility input serving on udp://%s ...", listenAddr)
+	return ServeConn(ctx, conn, sink)
+}
+
+// ServeConn receives position updates on the connection until ctx is done. The connection is closed when ServeConn
+// returns.
+func ServeConn(ctx context.Context, conn net.PacketConn, sink Sink) error {
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			_ = conn.Close()
+			return err
+		}
+
+		updates, err := ParseUpdates(buf[:n])
+		if err != nil {
+			simplelogger.Warnf("mobility: dropped datagram from %s: %v", addr, err)
+			continue
+		}
+
+		if len(updates) > 0 {
+			sink(updates)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package mobility
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUpdates(t *testing.T) {
+	updates, err := ParseUpdates([]byte("{\"node\":1,\"x\":120,\"y\":80.5,\"time_us\":15000000}\n\n" +
+		"{\"node\":2,\"lat\":37.4225,\"lon\":-122.0843}\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(updates))
+	assert.Equal(t, 1, updates[0].Node)
+	assert.Equal(t, uint64(15000000), *updates[0].Time)
+	assert.Equal(t, 80.5, *updates[0].Y)
+	assert.False(t, updates[0].IsGeo())
+	assert.Nil(t, updates[1].Time)
+	assert.True(t, updates[1].IsGeo())
+	assert.Equal(t, -122.0843, *updates[1].Lon)
+
+	for _, data := range []string{
+		`{"node":1,"x":1}`,
+		`{"node":1,"x":1,"y":2,"lat":3,"lon":4}`,
+		`{"node":1,"lat":3}`,
+		`{"node":0,"x":1,"y":2}`,
+		`{"node":1,"x":1,"y":2`,
+	} {
+		_, err = ParseUpdates([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
+
+func TestServeConn(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan []Update, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeConn(ctx, conn, func(updates []Update) {
+			received <- updates
+		})
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	assert.Nil(t, err)
+	defer client.Close()
+
+	// invalid datagrams are dropped
+	_, _ = client.Write([]byte(`{"node":1}`))
+	_, _ = client.Write([]byte(`{"node":3,"x":10,"y":20}`))
+
+	select {
+	case updates := <-received:
+		assert.Equal(t, 1, len(updates))
+		assert.Equal(t, 3, updates[0].Node)
+	case <-time.After(time.Second * 5):
+		t.Fatal("no updates received")
+	}
+
+	cancel()
+	assert.Nil(t, <-done)
+}
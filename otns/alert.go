@@ -0,0 +1,97 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package otns
+
+import (
+	"time"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/simulation"
+)
+
+// AlertCondition is the condition watched by an alert rule.
+type AlertCondition string
+
+const (
+	// AlertDetached fires for each node detached for the duration of the rule.
+	AlertDetached AlertCondition = "detached"
+	// AlertPartitions fires when there are more partitions than the threshold for the duration of the rule.
+	AlertPartitions AlertCondition = "partitions"
+	// AlertPingLoss fires when more than threshold percent of the pings completed within a window of the duration of
+	// the rule timed out. The window defaults to 60s.
+	AlertPingLoss AlertCondition = "pingloss"
+)
+
+// AlertRule is a condition of the simulation to be notified of by EventAlert events.
+type AlertRule struct {
+	Condition AlertCondition
+	Threshold float64 // partition count or ping loss percentage, unused for AlertDetached
+	For       time.Duration
+	Webhook   string // URL the alerts are posted to in JSON, or empty for none
+}
+
+// Alert is an alert rule which fired, or resolved when its condition no longer holds.
+type Alert struct {
+	Rule      int
+	Condition AlertCondition
+	NodeId    int     // the detached node for AlertDetached
+	Value     float64 // detached seconds, partition count or ping loss percentage
+	Resolved  bool
+	Message   string
+}
+
+// AddAlertRule adds an alert rule and returns its ID.
+func (s *Simulation) AddAlertRule(rule AlertRule) (id int, err error) {
+	err = s.do(func(sim *simulation.Simulation) error {
+		id, err = sim.Dispatcher().AddAlertRule(dispatcher.AlertRule{
+			Condition: dispatcher.AlertCondition(rule.Condition),
+			Threshold: rule.Threshold,
+			For:       uint64(rule.For / time.Microsecond),
+			Webhook:   rule.Webhook,
+		})
+		return err
+	})
+	return
+}
+
+// DeleteAlertRule deletes an alert rule.
+func (s *Simulation) DeleteAlertRule(id int) error {
+	return s.do(func(sim *simulation.Simulation) error {
+		return sim.Dispatcher().DeleteAlertRule(id)
+	})
+}
+
+func (h *eventHub) onAlert(evt *dispatcher.AlertEvent) {
+	h.publish(Event{Type: EventAlert, NodeId: evt.Node, Alert: &Alert{
+		Rule:      evt.Rule,
+		Condition: AlertCondition(evt.Condition),
+		NodeId:    evt.Node,
+		Value:     evt.Value,
+		Resolved:  evt.Resolved,
+		Message:   evt.Message,
+	}})
+}
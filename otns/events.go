@@ -0,0 +1,139 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package otns
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+// EventType is the type of a simulation event.
+type EventType string
+
+const (
+	EventNodeAdded        EventType = "node_added"
+	EventNodeDeleted      EventType = "node_deleted"
+	EventRoleChanged      EventType = "role_changed"
+	EventPartitionChanged EventType = "partition_changed"
+	EventNodeFailed       EventType = "node_failed"
+	EventNodeRecovered    EventType = "node_recovered"
+	EventAlert            EventType = "alert"
+)
+
+// Event is a change of the simulation state.
+type Event struct {
+	Type   EventType
+	Time   time.Duration // virtual time of the event
+	NodeId int
+	// Role is the new role for EventRoleChanged.
+	Role Role
+	// PartitionId is the new partition ID for EventPartitionChanged.
+	PartitionId uint32
+	// Alert is the alert for EventAlert.
+	Alert *Alert
+}
+
+// eventHub forwards the visualization events of the simulation to subscribers.
+type eventHub struct {
+	visualize.Visualizer
+
+	lock        sync.Mutex
+	curTime     uint64
+	nextSubId   int
+	subscribers map[int]func(Event)
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		Visualizer:  visualize.NewNopVisualizer(),
+		subscribers: map[int]func(Event){},
+	}
+}
+
+func (h *eventHub) subscribe(cb func(Event)) func() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	id := h.nextSubId
+	h.nextSubId++
+	h.subscribers[id] = cb
+
+	return func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		delete(h.subscribers, id)
+	}
+}
+
+func (h *eventHub) publish(evt Event) {
+	h.lock.Lock()
+	evt.Time = time.Duration(h.curTime) * time.Microsecond
+	subs := make([]func(Event), 0, len(h.subscribers))
+	for id := 0; id < h.nextSubId; id++ {
+		if cb, ok := h.subscribers[id]; ok {
+			subs = append(subs, cb)
+		}
+	}
+	h.lock.Unlock()
+
+	for _, cb := range subs {
+		cb(evt)
+	}
+}
+
+func (h *eventHub) AdvanceTime(ts uint64, speed float64) {
+	h.lock.Lock()
+	h.curTime = ts
+	h.lock.Unlock()
+}
+
+func (h *eventHub) AddNode(nodeid NodeId, x int, y int, radioRange int) {
+	h.publish(Event{Type: EventNodeAdded, NodeId: nodeid})
+}
+
+func (h *eventHub) DeleteNode(nodeid NodeId) {
+	h.publish(Event{Type: EventNodeDeleted, NodeId: nodeid})
+}
+
+func (h *eventHub) SetNodeRole(nodeid NodeId, role OtDeviceRole) {
+	h.publish(Event{Type: EventRoleChanged, NodeId: nodeid, Role: Role(role.String())})
+}
+
+func (h *eventHub) SetNodePartitionId(nodeid NodeId, parid uint32) {
+	h.publish(Event{Type: EventPartitionChanged, NodeId: nodeid, PartitionId: parid})
+}
+
+func (h *eventHub) OnNodeFail(nodeid NodeId) {
+	h.publish(Event{Type: EventNodeFailed, NodeId: nodeid})
+}
+
+func (h *eventHub) OnNodeRecover(nodeid NodeId) {
+	h.publish(Event{Type: EventNodeRecovered, NodeId: nodeid})
+}
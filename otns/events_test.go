@@ -0,0 +1,75 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package otns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestEventHub(t *testing.T) {
+	h := newEventHub()
+
+	var events []Event
+	unsubscribe := h.subscribe(func(evt Event) {
+		events = append(events, evt)
+	})
+
+	h.AddNode(1, 0, 0, 100)
+	h.AdvanceTime(2000000, 1)
+	h.SetNodeRole(1, OtDeviceRoleLeader)
+	h.SetNodePartitionId(1, 0x1234)
+	unsubscribe()
+	h.OnNodeFail(1)
+
+	assert.Equal(t, []Event{
+		{Type: EventNodeAdded, NodeId: 1},
+		{Type: EventRoleChanged, Time: 2 * time.Second, NodeId: 1, Role: RoleLeader},
+		{Type: EventPartitionChanged, Time: 2 * time.Second, NodeId: 1, PartitionId: 0x1234},
+	}, events)
+}
+
+func TestEventHubAlert(t *testing.T) {
+	h := newEventHub()
+
+	var events []Event
+	h.subscribe(func(evt Event) {
+		events = append(events, evt)
+	})
+
+	h.AdvanceTime(75000000, 1)
+	h.onAlert(&dispatcher.AlertEvent{Time: 75000000, Rule: 1, Condition: dispatcher.AlertDetached, Node: 4, Value: 60,
+		Message: "node 4 detached for 60.0s"})
+
+	assert.Equal(t, []Event{{Type: EventAlert, Time: 75 * time.Second, NodeId: 4, Alert: &Alert{
+		Rule: 1, Condition: AlertDetached, NodeId: 4, Value: 60, Message: "node 4 detached for 60.0s"}}}, events)
+}
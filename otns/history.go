@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package otns
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/simulation"
+)
+
+// NodeState is the state of a node from Time until the next state in its history.
+type NodeState struct {
+	Time        time.Duration
+	Role        Role
+	Rloc16      uint16
+	Parent      uint64 // extended address of the parent, or 0
+	PartitionId uint32
+	Deleted     bool // the node was deleted at Time
+}
+
+// NodeAt returns the state of a node at the virtual time, e.g. the role of node 5 at 340s.
+func (s *Simulation) NodeAt(id int, t time.Duration) (state NodeState, err error) {
+	err = s.do(func(sim *simulation.Simulation) error {
+		dstate, ok := sim.Dispatcher().NodeStateAt(id, uint64(t/time.Microsecond))
+		if !ok {
+			return errors.Errorf("node %d has no state at %v", id, t)
+		}
+		state = nodeState(dstate)
+		return nil
+	})
+	return
+}
+
+// NodeHistory returns the states of a node between the virtual times: the state at start, if any, followed by the
+// state transitions until end.
+func (s *Simulation) NodeHistory(id int, start, end time.Duration) (states []NodeState, err error) {
+	err = s.do(func(sim *simulation.Simulation) error {
+		for _, dstate := range sim.Dispatcher().NodeHistory(id, uint64(start/time.Microsecond),
+			uint64(end/time.Microsecond)) {
+			states = append(states, nodeState(dstate))
+		}
+		return nil
+	})
+	return
+}
+
+func nodeState(state dispatcher.NodeState) NodeState {
+	return NodeState{
+		Time:        time.Duration(state.Time) * time.Microsecond,
+		Role:        Role(state.RoleName),
+		Rloc16:      state.Rloc16,
+		Parent:      state.Parent,
+		PartitionId: state.PartitionId,
+		Deleted:     state.Deleted,
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+// Package otns embeds OpenThread network simulations in Go programs without the CLI.
+//
+// The API of this package follows semantic versioning as given by APIVersion: exported identifiers are not removed or
+// changed incompatibly within the same major version. The other packages of OTNS are internal and may change at any
+// time.
+package otns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/simulation"
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// APIVersion is the semantic version of the public API of this package.
+//
+// Minor versions added: 1.1.0 custom radio models, 1.2.0 node state history, 1.3.0 Config.StatsLogFormat and alert
+// rules.
+const APIVersion = "1.3.0"
+
+// Config is the configuration of a Simulation.
+type Config struct {
+	// OtCliPath is the path of the OT CLI executable used for nodes.
+	OtCliPath string
+	// Speed is the simulating speed relative to the real time, or 0 for the maximum speed.
+	Speed float64
+	// DispatcherHost and DispatcherPort are the UDP address the simulation listens on. Simulations running in
+	// parallel must use different ports.
+	DispatcherHost string
+	DispatcherPort int
+	// NoPcap disables writing frames to current.pcap.
+	NoPcap bool
+	// StatsLogFile is the file to write the node stats timeline to, or empty for none. With the influx format, it is
+	// the InfluxDB write URL.
+	StatsLogFile string
+	// StatsLogFormat is the format of the node stats timeline: csv (default), sqlite or influx.
+	StatsLogFormat string
+}
+
+// DefaultConfig returns the default configuration of a Simulation.
+func DefaultConfig() Config {
+	return Config{
+		OtCliPath:      "./ot-cli-ftd",
+		Speed:          0,
+		DispatcherHost: "localhost",
+		DispatcherPort: threadconst.InitialDispatcherPort,
+		NoPcap:         true,
+	}
+}
+
+// NodeType is the type of a Thread device.
+type NodeType string
+
+const (
+	Router NodeType = "router"
+	FED    NodeType = "fed"
+	MED    NodeType = "med"
+	SED    NodeType = "sed"
+)
+
+// NodeOptions are the options of a new node.
+type NodeOptions struct {
+	Type NodeType
+	// Id is the node ID, or 0 for the next available ID.
+	Id         int
+	X, Y       int
+	RadioRange int    // 0 for the default radio range
+	Executable string // empty for Config.OtCliPath
+}
+
+// Role is the Thread role of a node.
+type Role string
+
+const (
+	RoleDisabled Role = "disabled"
+	RoleDetached Role = "detached"
+	RoleChild    Role = "child"
+	RoleRouter   Role = "router"
+	RoleLeader   Role = "leader"
+)
+
+// NodeInfo is the state of a node.
+type NodeInfo struct {
+	Id          int
+	X, Y        int
+	Role        Role
+	Rloc16      uint16
+	ExtAddr     uint64
+	PartitionId uint32
+	Failed      bool
+}
+
+// Simulation is an OpenThread network simulation.
+// The methods of Simulation are safe for concurrent use.
+type Simulation struct {
+	ctx    *progctx.ProgCtx
+	sim    *simulation.Simulation
+	events *eventHub
+}
+
+// New creates and starts a new Simulation.
+func New(cfg Config) (s *Simulation, err error) {
+	ctx := progctx.New(context.Background())
+
+	simcfg := simulation.DefaultConfig()
+	simcfg.OtCliPath = cfg.OtCliPath
+	simcfg.Speed = cfg.Speed
+	if simcfg.Speed <= 0 {
+		simcfg.Speed = dispatcher.MaxSimulateSpeed
+	}
+	simcfg.DispatcherHost = cfg.DispatcherHost
+	simcfg.DispatcherPort = cfg.DispatcherPort
+	simcfg.StatsLogFile = cfg.StatsLogFile
+	simcfg.StatsLogFormat = cfg.StatsLogFormat
+
+	dispatcherCfg := dispatcher.DefaultConfig()
+	dispatcherCfg.NoPcap = cfg.NoPcap
+
+	defer func() {
+		if r := recover(); r != nil {
+			ctx.Cancel(nil)
+			err = fmt.Errorf("create simulation failed: %v", r)
+		}
+	}()
+
+	sim, err := simulation.NewSimulation(ctx, simcfg, dispatcherCfg)
+	if err != nil {
+		ctx.Cancel(nil)
+		return nil, err
+	}
+
+	s = &Simulation{
+		ctx:    ctx,
+		sim:    sim,
+		events: newEventHub(),
+	}
+	sim.SetVisualizer(s.events)
+	sim.Dispatcher().SubscribeAlerts(s.events.onAlert)
+	go sim.Run()
+	return s, nil
+}
+
+// Close stops the simulation and all nodes, and waits until they exit.
+func (s *Simulation) Close() {
+	s.ctx.Cancel(nil)
+	s.ctx.Wait()
+}
+
+// AddNode adds a new node and returns its ID.
+func (s *Simulation) AddNode(opts NodeOptions) (id int, err error) {
+	cfg := simulation.DefaultNodeConfig()
+	switch opts.Type {
+	case Router, "":
+	case FED:
+		cfg.IsRouter = false
+	case MED:
+		cfg.IsRouter, cfg.IsMtd = false, true
+	case SED:
+		cfg.IsRouter, cfg.IsMtd, cfg.RxOffWhenIdle = false, true, true
+	default:
+		return 0, errors.Errorf("invalid node type: %s", opts.Type)
+	}
+
+	if opts.Id > 0 {
+		cfg.ID = opts.Id
+	}
+	cfg.X, cfg.Y = opts.X, opts.Y
+	cfg.ExecutablePath = opts.Executable
+
+	err = s.do(func(sim *simulation.Simulation) error {
+		cfg.RadioRange = sim.DefaultRadioRange()
+		if opts.RadioRange > 0 {
+			cfg.RadioRange = opts.RadioRange
+		}
+
+		node, err := sim.AddNode(cfg)
+		if err == nil {
+			id = node.Id
+		}
+		return err
+	})
+	return
+}
+
+// DeleteNode deletes a node.
+func (s *Simulation) DeleteNode(id int) error {
+	return s.do(func(sim *simulation.Simulation) error {
+		return sim.DeleteNode(id)
+	})
+}
+
+// SetNodeFailed fails or recovers the radio of a node.
+func (s *Simulation) SetNodeFailed(id int, failed bool) error {
+	return s.do(func(sim *simulation.Simulation) error {
+		if sim.Nodes()[id] == nil {
+			return errors.Errorf("node %d not found", id)
+		}
+		sim.SetNodeFailed(id, failed)
+		return nil
+	})
+}
+
+// MoveNode moves a node to the new position.
+func (s *Simulation) MoveNode(id int, x, y int) error {
+	return s.do(func(sim *simulation.Simulation) error {
+		if sim.Nodes()[id] == nil {
+			return errors.Errorf("node %d not found", id)
+		}
+		sim.MoveNodeTo(id, x, y)
+		return nil
+	})
+}
+
+// Go runs the simulation for the duration of virtual time, and blocks until it is done.
+func (s *Simulation) Go(duration time.Duration) error {
+	var done <-chan struct{}
+	if err := s.do(func(sim *simulation.Simulation) error {
+		done = sim.Go(duration)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-s.ctx.Done():
+		return errors.Errorf("simulation stopped")
+	}
+}
+
+// Now returns the current virtual time of the simulation.
+func (s *Simulation) Now() (now time.Duration) {
+	_ = s.do(func(sim *simulation.Simulation) error {
+		now = time.Duration(sim.Dispatcher().CurTime) * time.Microsecond
+		return nil
+	})
+	return
+}
+
+// Nodes returns the states of all nodes ordered by node ID.
+func (s *Simulation) Nodes() (nodes []NodeInfo) {
+	_ = s.do(func(sim *simulation.Simulation) error {
+		for id := range sim.Nodes() {
+			nodes = append(nodes, nodeInfo(sim, id))
+		}
+		return nil
+	})
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Id < nodes[j].Id
+	})
+	return
+}
+
+// Node returns the state of a node.
+func (s *Simulation) Node(id int) (info NodeInfo, err error) {
+	err = s.do(func(sim *simulation.Simulation) error {
+		if sim.Nodes()[id] == nil {
+			return errors.Errorf("node %d not found", id)
+		}
+		info = nodeInfo(sim, id)
+		return nil
+	})
+	return
+}
+
+// Command runs an OT CLI command on a node and returns the output lines without the final "Done".
+func (s *Simulation) Command(id int, cmd string) (output []string, err error) {
+	err = s.do(func(sim *simulation.Simulation) error {
+		node := sim.Nodes()[id]
+		if node == nil {
+			return errors.Errorf("node %d not found", id)
+		}
+		if sim.Dispatcher().GetNode(id).IsPaused() {
+			return errors.Errorf("node %d is paused", id)
+		}
+
+		output = node.Command(cmd, simulation.DefaultCommandTimeout)
+		return nil
+	})
+	return
+}
+
+// Subscribe registers a callback for simulation events, and returns a function to unsubscribe.
+// The callback is called in the simulation goroutine: it must return quickly and must not call methods of Simulation.
+func (s *Simulation) Subscribe(cb func(Event)) (unsubscribe func()) {
+	return s.events.subscribe(cb)
+}
+
+// do runs f in the simulation goroutine and waits for it, converting panics to errors.
+func (s *Simulation) do(f func(sim *simulation.Simulation) error) error {
+	errc := make(chan error, 1)
+	s.sim.PostAsync(false, func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+			errc <- err
+		}()
+
+		err = f(s.sim)
+	})
+
+	select {
+	case err := <-errc:
+		return err
+	case <-s.ctx.Done():
+		return errors.Errorf("simulation stopped")
+	}
+}
+
+func nodeInfo(sim *simulation.Simulation, id NodeId) NodeInfo {
+	dnode := sim.Dispatcher().GetNode(id)
+	return NodeInfo{
+		Id:          id,
+		X:           dnode.X,
+		Y:           dnode.Y,
+		Role:        Role(dnode.Role.String()),
+		Rloc16:      dnode.Rloc16,
+		ExtAddr:     dnode.ExtAddr,
+		PartitionId: dnode.PartitionId,
+		Failed:      dnode.IsFailed(),
+	}
+}
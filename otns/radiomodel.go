@@ -0,0 +1,86 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package otns
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/simulation"
+)
+
+// LinkEnd is a node at one end of a radio link.
+type LinkEnd struct {
+	Id   int
+	X, Y int
+}
+
+// PathLossModel computes the path loss of a custom radio model.
+//
+// A frame is received if TxPowerDbm plus antenna gains minus the path loss is at least MinSnrDb above the noise floor
+// of the channel, using the radio parameters of the simulation (see the `radioparam` command).
+type PathLossModel interface {
+	// PathLossDb returns the path loss (in dB) of the link from src to dst on the channel, where meters is the
+	// distance of the nodes scaled by MeterPerUnit. It is called in the simulation goroutine.
+	PathLossDb(src, dst LinkEnd, meters float64, channel int) float64
+}
+
+// RegisterRadioModel registers a custom radio model by name, so that it can be selected with SetRadioModel or the
+// `radiomodel <name>` command. The factory is called each time the model is selected. RegisterRadioModel is usually
+// called from an init function, and fails if the name is not an identifier or is already used.
+func RegisterRadioModel(name string, factory func() PathLossModel) error {
+	if factory == nil {
+		return errors.Errorf("radio model %s: factory is nil", name)
+	}
+	return dispatcher.RegisterRadioModel(name, func() dispatcher.PathLossModel {
+		return pathLossAdapter{factory()}
+	})
+}
+
+// SetRadioModel selects a built-in ("disc", "logdistance" or "friis") or registered radio model.
+func (s *Simulation) SetRadioModel(name string) error {
+	model, err := dispatcher.ParseRadioModel(name)
+	if err != nil {
+		return err
+	}
+
+	return s.do(func(sim *simulation.Simulation) error {
+		params := sim.Dispatcher().GetRadioModelParams()
+		params.Model = model
+		sim.Dispatcher().SetRadioModelParams(params)
+		return nil
+	})
+}
+
+type pathLossAdapter struct {
+	model PathLossModel
+}
+
+func (a pathLossAdapter) PathLossDb(src, dst dispatcher.LinkEnd, meters float64, channel uint8) float64 {
+	return a.model.PathLossDb(LinkEnd{int(src.Id), src.X, src.Y}, LinkEnd{int(dst.Id), dst.X, dst.Y}, meters,
+		int(channel))
+}
@@ -29,9 +29,12 @@ package otns_main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -42,8 +45,11 @@ import (
 	"github.com/openthread/ot-ns/threadconst"
 
 	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/geo"
+	"github.com/openthread/ot-ns/mobility"
 
 	webSite "github.com/openthread/ot-ns/web/site"
+	webTelemetry "github.com/openthread/ot-ns/web/telemetry"
 
 	"github.com/openthread/ot-ns/web"
 
@@ -55,10 +61,13 @@ import (
 	visualizeGrpc "github.com/openthread/ot-ns/visualize/grpc"
 
 	visualizeMulti "github.com/openthread/ot-ns/visualize/multi"
+	visualizeStatslog "github.com/openthread/ot-ns/visualize/statslog"
+	visualizeTui "github.com/openthread/ot-ns/visualize/tui"
 
 	"github.com/openthread/ot-ns/cli"
 
 	"github.com/openthread/ot-ns/simulation"
+	"github.com/openthread/ot-ns/types"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
@@ -76,36 +85,102 @@ type MainArgs struct {
 	DispatcherPort int
 	DumpPackets    bool
 	NoPcap         bool
+	PcapNg         bool
+	LogCorrelation bool
 	NoReplay       bool
+	StatsWindow    time.Duration
+	StatsRetention int
+	StatsLog       string
+	StatsLogFormat string
+	Telemetry      bool
+	TelemetryRate  time.Duration
+	ControlToken   string
+	SingleCtrl     bool
+	Seed           int64
+	GeoOrigin      string
+	Mobility       bool
+	Transcript     bool
+	InitScript     string
+	NodeTemplates  string
+	StallTimeout   time.Duration
+	StallForceFail bool
+	LogRateLimit   int
+	Summary        bool
+	SummaryFile    string
+	JsonOutput     bool
+	RemoteCli      string
+	ResourceRate   time.Duration
+	TraceFile      string
+	SharedMemory   bool
+	TcpAddr        string
+	ContainerImage string
+	ContainerCli   string
+	CoalesceAlarms time.Duration
+	OutputDir      string
+	CoverageDir    string
+	Visualizer     string
 }
 
-var (
-	args MainArgs
-)
+func parseArgs(argv []string) *MainArgs {
+	args := &MainArgs{}
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
-func parseArgs() {
 	defaultOtCli := os.Getenv("OTNS_OT_CLI")
 	if defaultOtCli == "" {
 		defaultOtCli = "./ot-cli-ftd"
 	}
 
-	flag.StringVar(&args.Speed, "speed", "1", "set simulating speed")
-	flag.StringVar(&args.OtCliPath, "ot-cli", defaultOtCli, "specify the OT CLI executable")
-	flag.BoolVar(&args.AutoGo, "autogo", true, "auto go")
-	flag.BoolVar(&args.ReadOnly, "readonly", false, "readonly simulation can not be manipulated")
-	flag.StringVar(&args.LogLevel, "log", "warn", "set logging level")
-	flag.BoolVar(&args.OpenWeb, "web", true, "open web")
-	flag.BoolVar(&args.RawMode, "raw", false, "use raw mode")
-	flag.BoolVar(&args.Real, "real", false, "use real mode (for real devices)")
-	flag.StringVar(&args.ListenAddr, "listen", fmt.Sprintf("localhost:%d", threadconst.InitialDispatcherPort), "specify listen address")
-	flag.BoolVar(&args.DumpPackets, "dump-packets", false, "dump packets")
-	flag.BoolVar(&args.NoPcap, "no-pcap", false, "do not generate Pcap")
-	flag.BoolVar(&args.NoReplay, "no-replay", false, "do not generate Replay")
-
-	flag.Parse()
+	fs.StringVar(&args.Speed, "speed", "1", "set simulating speed")
+	fs.StringVar(&args.OtCliPath, "ot-cli", defaultOtCli, "specify the OT CLI executable")
+	fs.BoolVar(&args.AutoGo, "autogo", true, "auto go")
+	fs.BoolVar(&args.ReadOnly, "readonly", false, "readonly simulation can not be manipulated")
+	fs.StringVar(&args.LogLevel, "log", "warn", "set logging level")
+	fs.BoolVar(&args.OpenWeb, "web", true, "open web")
+	fs.StringVar(&args.Visualizer, "vis", "web", "set the visualizer: web, or tui to draw the simulation in the terminal")
+	fs.BoolVar(&args.RawMode, "raw", false, "use raw mode")
+	fs.BoolVar(&args.Real, "real", false, "use real mode (for real devices)")
+	fs.StringVar(&args.ListenAddr, "listen", fmt.Sprintf("localhost:%d", threadconst.InitialDispatcherPort), "specify listen address")
+	fs.BoolVar(&args.DumpPackets, "dump-packets", false, "dump packets")
+	fs.BoolVar(&args.NoPcap, "no-pcap", false, "do not generate Pcap")
+	fs.BoolVar(&args.PcapNg, "pcapng", false, "generate current.pcapng with per-node interfaces and frame metadata instead of current.pcap")
+	fs.BoolVar(&args.SharedMemory, "shm", false, "offer the shared memory transport to nodes")
+	fs.StringVar(&args.ContainerImage, "container-image", "", "run nodes inside containers of the image, or of the images by node type, e.g. ot:latest,sed=ot:legacy")
+	fs.StringVar(&args.ContainerCli, "container-runtime", simulation.DefaultContainerRuntime, "container runtime of the nodes: docker or podman")
+	fs.StringVar(&args.TcpAddr, "tcp", "", "let nodes connect to the dispatcher over TCP on the listen address, e.g. localhost:9000")
+	fs.StringVar(&args.TraceFile, "trace", "", "write a Chrome trace of the dispatcher activity to the file")
+	fs.BoolVar(&args.LogCorrelation, "log-correlation", false, "number node logs and tag captured frames with the log sequence numbers of the sending nodes")
+	fs.StringVar(&args.InitScript, "init-script", "", "run the init script file on each new node before it starts")
+	fs.StringVar(&args.NodeTemplates, "templates", "", "load the node templates from the YAML file, to add nodes by template name")
+	fs.BoolVar(&args.Transcript, "transcript", false, "record the CLI transcript of each node into tmp/<port offset>_<node ID>.transcript")
+	fs.BoolVar(&args.NoReplay, "no-replay", false, "do not generate Replay")
+	fs.DurationVar(&args.StatsWindow, "stats-window", time.Duration(dispatcher.DefaultStatsWindow)*time.Microsecond, "set the length of statistics time windows")
+	fs.IntVar(&args.StatsRetention, "stats-retention", dispatcher.DefaultStatsRetention, "set the number of statistics time windows to keep")
+	fs.StringVar(&args.StatsLog, "statslog", "", "write the node stats timeline to the file, or the InfluxDB write URL")
+	fs.StringVar(&args.StatsLogFormat, "statslog-format", visualizeStatslog.FormatCsv, "format of the node stats timeline: csv, sqlite or influx")
+	fs.DurationVar(&args.StallTimeout, "stall-timeout", 0, "report a stall when virtual time makes no progress for the duration (e.g. 30s) while waiting for nodes, or 0 to disable")
+	fs.BoolVar(&args.StallForceFail, "stall-force-fail", false, "fail the nodes which do not respond when a stall is detected")
+	fs.DurationVar(&args.CoalesceAlarms, "coalesce-alarms", 0, "fire alarms within the duration (e.g. 100us) together, or 0 to disable")
+	fs.IntVar(&args.LogRateLimit, "log-limit", dispatcher.DefaultLogRateLimit, "set the maximum number of log events per second accepted from each node, or 0 for no limit")
+	fs.DurationVar(&args.ResourceRate, "resource-interval", simulation.DefaultResourceSampleInterval, "set the interval of sampling the memory and CPU usage of node processes, or 0 to disable")
+	fs.BoolVar(&args.Summary, "summary", false, "print the summary of the run on exit")
+	fs.StringVar(&args.SummaryFile, "summary-file", "", "write the summary of the run on exit to the file in JSON format")
+	fs.BoolVar(&args.JsonOutput, "json", false, "output the results of CLI commands in JSON format")
+	fs.Int64Var(&args.Seed, "seed", 0, "set the seed of the PRNG, or 0 for a random seed")
+	fs.BoolVar(&args.Mobility, "mobility", false, "receive live node position updates from an external mobility simulator over UDP")
+	fs.StringVar(&args.GeoOrigin, "geo-origin", "", "enable the geographic mode with the origin `<lat>,<lon>[,<alt>[,<meters-per-unit>]]`")
+	fs.StringVar(&args.RemoteCli, "remote-cli", "", "serve the CLI to remote clients on the TCP `address`, protected by the control token (required for non-loopback addresses)")
+	fs.StringVar(&args.ControlToken, "control-token", os.Getenv("OTNS_CONTROL_TOKEN"), "require the token for controlling the simulation through gRPC, other clients are read-only")
+	fs.BoolVar(&args.SingleCtrl, "single-controller", false, "only allow the earliest attached web client to control the simulation through gRPC, other clients are read-only")
+	fs.BoolVar(&args.Telemetry, "telemetry", false, "serve the WebSocket telemetry of the simulation")
+	fs.DurationVar(&args.TelemetryRate, "telemetry-interval", time.Second, "set the default interval of WebSocket telemetry messages")
+	fs.StringVar(&args.CoverageDir, "coverage", "", "write the coverage data of instrumented nodes into the directory")
+	fs.StringVar(&args.OutputDir, "output-dir", "", "write the output files (pcap, replay, node directories, ...) to the directory instead of the working directory")
+
+	_ = fs.Parse(argv)
+	return args
 }
 
-func parseListenAddr() {
+func parseListenAddr(args *MainArgs) {
 	var err error
 
 	notifyInvalidListenAddr := func() {
@@ -126,50 +201,76 @@ func parseListenAddr() {
 		notifyInvalidListenAddr()
 	}
 
-	portOffset := (args.DispatcherPort - threadconst.InitialDispatcherPort) / threadconst.WellKnownNodeId
-	simplelogger.Infof("Using env PORT_OFFSET=%d", portOffset)
-	if err = os.Setenv("PORT_OFFSET", strconv.Itoa(portOffset)); err != nil {
-		simplelogger.Panic(err)
-	}
+	simplelogger.Infof("Using PORT_OFFSET=%d", listenPortOffset(args))
+}
+
+func listenPortOffset(args *MainArgs) int {
+	return (args.DispatcherPort - threadconst.InitialDispatcherPort) / threadconst.WellKnownNodeId
 }
 
 func Main(ctx *progctx.ProgCtx, visualizerCreator func(ctx *progctx.ProgCtx, args *MainArgs) visualize.Visualizer, cliOptions *runcli.CliOptions) {
-	parseArgs()
+	MainWithArgs(ctx, os.Args[1:], visualizerCreator, cliOptions)
+}
+
+// MainWithArgs runs OTNS with the command line arguments (without the program name). The state of each run is kept
+// apart, so that multiple instances with different listen addresses and output directories can run in one process.
+func MainWithArgs(ctx *progctx.ProgCtx, argv []string, visualizerCreator func(ctx *progctx.ProgCtx, args *MainArgs) visualize.Visualizer, cliOptions *runcli.CliOptions) {
+	args := parseArgs(argv)
 
 	simplelogger.SetLevel(simplelogger.ParseLevel(args.LogLevel))
 
-	parseListenAddr()
+	parseListenAddr(args)
 
-	rand.Seed(time.Now().UnixNano())
+	if args.Seed == 0 {
+		args.Seed = time.Now().UnixNano()
+	}
+	simplelogger.Infof("Using PRNG seed %d", args.Seed)
+	rand.Seed(args.Seed)
 	// run console in the main goroutine
-	ctx.Defer(func() {
-		_ = os.Stdin.Close()
-	})
+	if cliOptions == nil || cliOptions.Stdin == nil {
+		ctx.Defer(func() {
+			_ = os.Stdin.Close()
+		})
+	}
 
 	handleSignals(ctx)
 
 	var vis visualize.Visualizer
 	if visualizerCreator != nil {
-		vis = visualizerCreator(ctx, &args)
+		vis = visualizerCreator(ctx, args)
+	} else if args.Visualizer == "tui" {
+		vis = visualizeTui.NewTuiVisualizer(os.Stdout)
+		args.OpenWeb = false
+	} else if args.Visualizer != "web" {
+		simplelogger.Fatalf("unknown visualizer: %s", args.Visualizer)
 	}
 
 	visGrpcServerAddr := fmt.Sprintf("%s:%d", args.DispatcherHost, args.DispatcherPort-1)
 
+	if args.OutputDir != "" {
+		simplelogger.FatalIfError(os.MkdirAll(args.OutputDir, 0755))
+	}
+
 	replayFn := ""
 	if !args.NoReplay {
-		replayFn = fmt.Sprintf("otns_%s.replay", os.Getenv("PORT_OFFSET"))
+		replayFn = filepath.Join(args.OutputDir, fmt.Sprintf("otns_%d.replay", listenPortOffset(args)))
 	}
 	if vis != nil {
 		vis = visualizeMulti.NewMultiVisualizer(
 			vis,
-			visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn),
+			visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn, args.ControlToken, args.SingleCtrl),
 		)
 	} else {
-		vis = visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn)
+		vis = visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn, args.ControlToken, args.SingleCtrl)
 	}
 
-	sim := createSimulation(ctx)
+	sim, err := createSimulation(ctx, args, args.DispatcherPort, args.OutputDir)
+	simplelogger.FatalIfError(err)
 	rt := cli.NewCmdRunner(ctx, sim)
+	rt.SetJsonOutput(args.JsonOutput)
+	cli.NewSessionManager(rt, func(ctx *progctx.ProgCtx, id int) (*simulation.Simulation, error) {
+		return createSession(ctx, args, id)
+	})
 	sim.SetVisualizer(vis)
 	go sim.Run()
 	go func() {
@@ -185,11 +286,22 @@ func Main(ctx *progctx.ProgCtx, visualizerCreator func(ctx *progctx.ProgCtx, arg
 		}
 	}()
 
+	if args.Telemetry {
+		go serveTelemetry(ctx, args, sim, args.DispatcherPort)
+	}
+	if args.RemoteCli != "" {
+		go serveRemoteCli(ctx, args, rt)
+	}
+	if args.Mobility {
+		go serveMobility(ctx, args, sim, args.DispatcherPort)
+	}
+
 	if args.AutoGo {
 		go autoGo(ctx, sim)
 	}
 
-	web.ConfigWeb(args.DispatcherHost, args.DispatcherPort-2, args.DispatcherPort-1, args.DispatcherPort-3)
+	web.ConfigWeb(args.DispatcherHost, args.DispatcherPort-2, args.DispatcherPort-1, args.DispatcherPort-3,
+		args.ControlToken)
 
 	simplelogger.Debugf("open web: %v", args.OpenWeb)
 	if args.OpenWeb {
@@ -230,7 +342,154 @@ func autoGo(prog *progctx.ProgCtx, sim *simulation.Simulation) {
 	}
 }
 
-func createSimulation(ctx *progctx.ProgCtx) *simulation.Simulation {
+// createSession creates and starts the simulation of a new session. The session writes its output files to its own
+// directory, and serves gRPC and telemetry at ports derived from its dispatcher port like the main simulation.
+func createSession(ctx *progctx.ProgCtx, args *MainArgs, id int) (*simulation.Simulation, error) {
+	port := threadconst.InitialDispatcherPort + id*threadconst.WellKnownNodeId
+	outputDir := filepath.Join(args.OutputDir, fmt.Sprintf("otns_session_%d", id))
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	sim, err := createSimulation(ctx, args, port, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	replayFn := ""
+	if !args.NoReplay {
+		replayFn = filepath.Join(outputDir, "otns.replay")
+	}
+	vis := visualizeGrpc.NewGrpcVisualizer(fmt.Sprintf("%s:%d", args.DispatcherHost, port-1), replayFn, args.ControlToken,
+		args.SingleCtrl)
+	sim.SetVisualizer(vis)
+
+	go vis.Run()
+	go sim.Run()
+	if args.Telemetry {
+		go serveTelemetry(ctx, args, sim, port)
+	}
+	if args.Mobility {
+		go serveMobility(ctx, args, sim, port)
+	}
+	simplelogger.Infof("session %d created, output directory: %s", id, outputDir)
+	return sim, nil
+}
+
+func serveTelemetry(ctx *progctx.ProgCtx, args *MainArgs, sim *simulation.Simulation, dispatcherPort int) {
+	telemetryAddr := fmt.Sprintf("%s:%d", args.DispatcherHost, dispatcherPort-4)
+	err := webTelemetry.Serve(ctx, telemetryAddr, args.TelemetryRate, func() *webTelemetry.Snapshot {
+		return collectTelemetry(ctx, sim)
+	})
+	if err != nil {
+		simplelogger.Errorf("telemetry quited: %+v, WebSocket telemetry won't be available!", err)
+	}
+}
+
+func serveRemoteCli(ctx *progctx.ProgCtx, args *MainArgs, rt *cli.CmdRunner) {
+	err := cli.ServeRemote(ctx, args.RemoteCli, rt, args.ControlToken)
+	if err != nil {
+		simplelogger.Errorf("remote CLI quited: %+v, remote CLI won't be available!", err)
+	}
+}
+
+func serveMobility(ctx *progctx.ProgCtx, args *MainArgs, sim *simulation.Simulation, dispatcherPort int) {
+	if args.ReadOnly {
+		simplelogger.Warnf("mobility input is not available in a readonly simulation")
+		return
+	}
+
+	mobilityAddr := fmt.Sprintf("%s:%d", args.DispatcherHost, dispatcherPort-5)
+	err := mobility.Serve(ctx, mobilityAddr, func(updates []mobility.Update) {
+		applyMobilityUpdates(sim, updates)
+	})
+	if err != nil {
+		simplelogger.Errorf("mobility input quited: %+v, live position updates won't be available!", err)
+	}
+}
+
+// applyMobilityUpdates moves the nodes at the virtual times of the position updates.
+func applyMobilityUpdates(sim *simulation.Simulation, updates []mobility.Update) {
+	sim.PostAsync(false, func() {
+		d := sim.Dispatcher()
+		for _, update := range updates {
+			var x, y int
+			if update.IsGeo() {
+				origin := sim.GeoOrigin()
+				if origin == nil {
+					simplelogger.Warnf("mobility: geographic mode is disabled, dropped update of node %d", update.Node)
+					continue
+				}
+				x, y = origin.FromGeo(*update.Lat, *update.Lon)
+			} else {
+				x, y = int(math.Round(*update.X)), int(math.Round(*update.Y))
+			}
+
+			nodeid := update.Node
+			if update.Time == nil || *update.Time <= d.CurTime {
+				sim.MoveNodeTo(nodeid, x, y)
+			} else {
+				d.ScheduleAt(*update.Time, func() {
+					sim.MoveNodeTo(nodeid, x, y)
+				})
+			}
+		}
+	})
+}
+
+func collectTelemetry(ctx *progctx.ProgCtx, sim *simulation.Simulation) *webTelemetry.Snapshot {
+	done := make(chan *webTelemetry.Snapshot, 1)
+	sim.PostAsync(false, func() {
+		d := sim.Dispatcher()
+		snapshot := &webTelemetry.Snapshot{
+			Time:     d.CurTime,
+			Speed:    d.GetSpeed(),
+			Nodes:    sim.NodeStats(),
+			Counters: d.GetCounters(),
+		}
+
+		if sim.GeoOrigin() != nil {
+			snapshot.Positions = map[types.NodeId]geo.Position{}
+			for nodeid := range d.Nodes() {
+				snapshot.Positions[nodeid], _ = sim.NodeGeoPosition(nodeid)
+			}
+		}
+
+		partitions := map[uint32]int{}
+		for _, node := range d.Nodes() {
+			if node.IsFailed() || node.PartitionId == 0 {
+				continue
+			}
+
+			idx, ok := partitions[node.PartitionId]
+			if !ok {
+				idx = len(snapshot.Partitions)
+				partitions[node.PartitionId] = idx
+				snapshot.Partitions = append(snapshot.Partitions, webTelemetry.Partition{Id: node.PartitionId})
+			}
+			snapshot.Partitions[idx].Nodes = append(snapshot.Partitions[idx].Nodes, node.Id)
+		}
+
+		sort.Slice(snapshot.Partitions, func(i, j int) bool {
+			return snapshot.Partitions[i].Id < snapshot.Partitions[j].Id
+		})
+		for _, par := range snapshot.Partitions {
+			sort.Ints(par.Nodes)
+		}
+		done <- snapshot
+	})
+
+	select {
+	case snapshot := <-done:
+		return snapshot
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// createSimulation creates the simulation at the dispatcher port. If outputDir is not empty, output files are written
+// to the directory instead of the working directory.
+func createSimulation(ctx *progctx.ProgCtx, args *MainArgs, dispatcherPort int, outputDir string) (*simulation.Simulation, error) {
 	var speed float64
 	var err error
 
@@ -249,13 +508,81 @@ func createSimulation(ctx *progctx.ProgCtx) *simulation.Simulation {
 	simcfg.RawMode = args.RawMode
 	simcfg.Real = args.Real
 	simcfg.DispatcherHost = args.DispatcherHost
-	simcfg.DispatcherPort = args.DispatcherPort
+	simcfg.DispatcherPort = dispatcherPort
 	simcfg.DumpPackets = args.DumpPackets
+	simcfg.StatsLogFile = args.StatsLog
+	simcfg.StatsLogFormat = args.StatsLogFormat
+	simcfg.Seed = args.Seed
+	simcfg.LogCorrelation = args.LogCorrelation
+	simcfg.SharedMemory = args.SharedMemory
+	simcfg.TcpAddr = args.TcpAddr
+	simcfg.Transcript = args.Transcript
+	simcfg.InitScript = args.InitScript
+	if args.NodeTemplates != "" {
+		if simcfg.NodeTemplates, err = simulation.ReadNodeTemplates(args.NodeTemplates); err != nil {
+			return nil, err
+		}
+	}
+	simcfg.Summary = args.Summary
+	simcfg.ResourceSampleInterval = args.ResourceRate
+	simcfg.SummaryFile = args.SummaryFile
+	simcfg.CoverageDir = args.CoverageDir
+	if args.GeoOrigin != "" {
+		if simcfg.GeoOrigin, err = geo.ParseOrigin(args.GeoOrigin); err != nil {
+			return nil, err
+		}
+	}
+	if args.ContainerImage != "" {
+		simcfg.Container = &simulation.ContainerConfig{Runtime: args.ContainerCli}
+		if simcfg.Container.Images, err = simulation.ParseContainerImages(args.ContainerImage); err != nil {
+			return nil, err
+		}
+	}
+	if outputDir != "" {
+		simcfg.NodeDir = outputDir
+	}
+	if outputDir != "" && args.StatsLog != "" && args.StatsLogFormat != visualizeStatslog.FormatInflux {
+		simcfg.StatsLogFile = filepath.Join(outputDir, filepath.Base(args.StatsLog))
+	}
+	if outputDir != "" && args.SummaryFile != "" {
+		simcfg.SummaryFile = filepath.Join(outputDir, filepath.Base(args.SummaryFile))
+	}
+	if outputDir != "" && args.CoverageDir != "" && !filepath.IsAbs(args.CoverageDir) {
+		simcfg.CoverageDir = filepath.Join(outputDir, args.CoverageDir)
+	}
 
 	dispatcherCfg := dispatcher.DefaultConfig()
 	dispatcherCfg.NoPcap = args.NoPcap
+	if args.PcapNg {
+		dispatcherCfg.PcapNg = true
+		dispatcherCfg.PcapFile = "current.pcapng"
+	}
+	dispatcherCfg.TraceFile = args.TraceFile
+	if outputDir != "" {
+		dispatcherCfg.PcapFile = filepath.Join(outputDir, dispatcherCfg.PcapFile)
+		dispatcherCfg.Stall.DumpFile = filepath.Join(outputDir, dispatcherCfg.Stall.DumpFile)
+		if args.TraceFile != "" {
+			dispatcherCfg.TraceFile = filepath.Join(outputDir, filepath.Base(args.TraceFile))
+		}
+	}
+	if args.StatsWindow < time.Microsecond || args.StatsRetention <= 0 {
+		simplelogger.Fatalf("invalid statistics time window: %v x %d", args.StatsWindow, args.StatsRetention)
+	}
+	dispatcherCfg.StatsWindow.Interval = uint64(args.StatsWindow / time.Microsecond)
+	dispatcherCfg.StatsWindow.Retention = args.StatsRetention
+	if args.StallTimeout < 0 {
+		simplelogger.Fatalf("invalid stall timeout: %v", args.StallTimeout)
+	}
+	dispatcherCfg.Stall.Timeout = args.StallTimeout
+	dispatcherCfg.Stall.ForceFail = args.StallForceFail
+	if args.LogRateLimit < 0 {
+		simplelogger.Fatalf("invalid log rate limit: %d", args.LogRateLimit)
+	}
+	dispatcherCfg.LogRateLimit = args.LogRateLimit
+	if args.CoalesceAlarms < 0 || args.CoalesceAlarms > dispatcher.MaxAlarmCoalescing*time.Microsecond {
+		simplelogger.Fatalf("invalid alarm coalescing window: %v", args.CoalesceAlarms)
+	}
+	dispatcherCfg.AlarmCoalescing = uint64(args.CoalesceAlarms / time.Microsecond)
 
-	sim, err := simulation.NewSimulation(ctx, simcfg, dispatcherCfg)
-	simplelogger.FatalIfError(err)
-	return sim
+	return simulation.NewSimulation(ctx, simcfg, dispatcherCfg)
 }
@@ -30,9 +30,12 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
@@ -49,19 +52,40 @@ import (
 	"github.com/openthread/ot-ns/cli/runcli"
 	"github.com/openthread/ot-ns/otns_main"
 	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/threadconst"
 	. "github.com/openthread/ot-ns/types"
 	"github.com/openthread/ot-ns/visualize"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
-var (
+const (
 	stdinPipeFile  = "stdin.namedpipe"
 	stdoutPipeFile = "stdout.namedpipe"
+
+	// maxPortOffset is the largest port offset with all ports of the instance below 65536.
+	maxPortOffset = (65535 - threadconst.InitialDispatcherPort) / threadconst.WellKnownNodeId
+	// instancePortRange is the number of ports below the dispatcher port used by the servers of an instance.
+	instancePortRange = 5
+
+	commandTimeout  = time.Minute * 5
+	shutdownTimeout = time.Second * 30
+)
+
+var (
+	usedPortOffsets      = map[int]struct{}{}
+	usedPortOffsetsMutex sync.Mutex
 )
 
+// OtnsTest runs an OTNS instance in the test process and drives it through its CLI.
+// Each instance listens on a free port offset and writes its output files to a temporary directory of the test, so
+// that instances of parallel tests do not interfere. The instance is shut down when the test finishes.
 type OtnsTest struct {
 	*testing.T
 
+	portOffset             int
+	outputDir              string
+	shutdownOnce           sync.Once
+	grpcConn               *grpc.ClientConn
 	stdin                  *os.File
 	stdout                 *os.File
 	otnsDone               chan struct{}
@@ -112,18 +136,22 @@ func (ot *OtnsTest) stdoutReadRoutine() {
 	}
 }
 
-func (ot *OtnsTest) shutdown() {
-
-}
-
 func (ot *OtnsTest) expectDone() {
 	ot.expectCommandResultLines()
 }
 
 func (ot *OtnsTest) expectCommandResultLines() (output []string) {
+	timeout := time.After(commandTimeout)
 loop:
 	for {
-		line := <-ot.pendingOutput
+		var line string
+		select {
+		case line = <-ot.pendingOutput:
+		case <-ot.otnsDone:
+			ot.Fatalf("OTNS exited while waiting for the command result")
+		case <-timeout:
+			ot.Fatalf("timeout waiting for the command result")
+		}
 
 		if line == "Done" {
 			break loop
@@ -148,9 +176,39 @@ func (ot *OtnsTest) expectCommandResultInt() int {
 	return v
 }
 
+// Shutdown stops the OTNS instance and waits for it to exit. It is called when the test finishes, and may be called
+// earlier and more than once.
 func (ot *OtnsTest) Shutdown() {
-	ot.ctx.Cancel(nil)
-	ot.Join()
+	ot.shutdownOnce.Do(func() {
+		ot.ctx.Cancel(nil)
+
+		select {
+		case <-ot.otnsDone:
+		case <-time.After(shutdownTimeout):
+			ot.Errorf("OTNS did not exit within %v", shutdownTimeout)
+		}
+
+		if ot.grpcConn != nil {
+			_ = ot.grpcConn.Close()
+		}
+		_ = ot.stdin.Close()
+		_ = ot.stdout.Close()
+		releasePortOffset(ot.portOffset)
+	})
+}
+
+// ListenAddr returns the listen address of the OTNS instance.
+func (ot *OtnsTest) ListenAddr() string {
+	return fmt.Sprintf("localhost:%d", ot.dispatcherPort())
+}
+
+// OutputDir returns the directory of the output files of the OTNS instance.
+func (ot *OtnsTest) OutputDir() string {
+	return ot.outputDir
+}
+
+func (ot *OtnsTest) dispatcherPort() int {
+	return threadconst.InitialDispatcherPort + ot.portOffset*threadconst.WellKnownNodeId
 }
 
 func (ot *OtnsTest) SetSpeed(speed int) {
@@ -326,42 +384,52 @@ func (ot *OtnsTest) ExpectVisualizeAddNode(nodeid NodeId, x int, y int, radioRan
 	})
 }
 
-func NewOtnsTest(t *testing.T) *OtnsTest {
+// NewOtnsTest starts an OTNS instance for the test with the extra command line arguments.
+func NewOtnsTest(t *testing.T, extraArgs ...string) *OtnsTest {
+	portOffset, err := allocPortOffset()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
 	ot := &OtnsTest{
 		T:                      t,
+		portOffset:             portOffset,
+		outputDir:              t.TempDir(),
 		otnsDone:               make(chan struct{}),
 		pendingOutput:          make(chan string, 1000),
 		pendingVisualizeEvents: make(chan *visualize_grpc_pb.VisualizeEvent, 1000),
 	}
 
-	os.Args = append(os.Args, "-log", "debug", "-web=false", "-autogo=false")
+	argv := []string{"-log", "debug", "-web=false", "-autogo=false", "-listen", ot.ListenAddr(), "-output-dir",
+		ot.outputDir}
+	argv = append(argv, extraArgs...)
 
-	_ = os.Remove(stdinPipeFile)
-	_ = os.Remove(stdoutPipeFile)
+	stdinPipe := filepath.Join(ot.outputDir, stdinPipeFile)
+	stdoutPipe := filepath.Join(ot.outputDir, stdoutPipeFile)
 
-	err := syscall.Mkfifo(stdinPipeFile, 0644)
+	err = syscall.Mkfifo(stdinPipe, 0644)
 	simplelogger.PanicIfError(err)
 
-	ot.stdin, err = os.OpenFile(stdinPipeFile, os.O_RDWR, os.ModeNamedPipe)
+	ot.stdin, err = os.OpenFile(stdinPipe, os.O_RDWR, os.ModeNamedPipe)
 	simplelogger.PanicIfError(err)
 	ot.stdinCloser = readline.NewCancelableStdin(ot.stdin)
 
-	err = syscall.Mkfifo(stdoutPipeFile, 0644)
+	err = syscall.Mkfifo(stdoutPipe, 0644)
 	simplelogger.PanicIfError(err)
 
-	ot.stdout, err = os.OpenFile(stdoutPipeFile, os.O_RDWR, os.ModeNamedPipe)
+	ot.stdout, err = os.OpenFile(stdoutPipe, os.O_RDWR, os.ModeNamedPipe)
 	simplelogger.PanicIfError(err)
 
 	ot.ctx = progctx.New(context.Background())
+	t.Cleanup(ot.Shutdown)
 
 	go func() {
 		defer func() {
 			simplelogger.Infof("OTNS exited.")
-			ot.shutdown()
 			close(ot.otnsDone)
 		}()
 
-		otns_main.Main(ot.ctx, func(ctx *progctx.ProgCtx, args *otns_main.MainArgs) visualize.Visualizer {
+		otns_main.MainWithArgs(ot.ctx, argv, func(ctx *progctx.ProgCtx, args *otns_main.MainArgs) visualize.Visualizer {
 			return nil
 		}, &runcli.CliOptions{
 			EchoInput: false,
@@ -370,10 +438,11 @@ func NewOtnsTest(t *testing.T) *OtnsTest {
 		})
 	}()
 
-	grpcConn, err := grpc.Dial("localhost:8999", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	ot.grpcConn, err = grpc.Dial(fmt.Sprintf("localhost:%d", ot.dispatcherPort()-1),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
 	ot.ExpectNoError(err)
 
-	grpcClient := visualize_grpc_pb.NewVisualizeGrpcServiceClient(grpcConn)
+	grpcClient := visualize_grpc_pb.NewVisualizeGrpcServiceClient(ot.grpcConn)
 	ot.grpcClient = grpcClient
 
 	deadline := time.Now().Add(time.Second * 10)
@@ -391,3 +460,49 @@ func NewOtnsTest(t *testing.T) *OtnsTest {
 	go ot.visualizeStreamReadRoutine()
 	return ot
 }
+
+// allocPortOffset returns a port offset which is not used by other instances in this process, and whose ports are
+// free on this host.
+func allocPortOffset() (int, error) {
+	usedPortOffsetsMutex.Lock()
+	defer usedPortOffsetsMutex.Unlock()
+
+	// port offset 0 is left to OTNS instances run by the user
+	for portOffset := 1; portOffset <= maxPortOffset; portOffset++ {
+		if _, ok := usedPortOffsets[portOffset]; ok {
+			continue
+		}
+
+		if portsFree(threadconst.InitialDispatcherPort + portOffset*threadconst.WellKnownNodeId) {
+			usedPortOffsets[portOffset] = struct{}{}
+			return portOffset, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free port offset for OTNS")
+}
+
+func releasePortOffset(portOffset int) {
+	usedPortOffsetsMutex.Lock()
+	defer usedPortOffsetsMutex.Unlock()
+
+	delete(usedPortOffsets, portOffset)
+}
+
+// portsFree returns if the UDP dispatcher port and the TCP server ports below it are free.
+func portsFree(dispatcherPort int) bool {
+	udpConn, err := net.ListenPacket("udp", fmt.Sprintf("localhost:%d", dispatcherPort))
+	if err != nil {
+		return false
+	}
+	defer udpConn.Close()
+
+	for port := dispatcherPort - instancePortRange; port < dispatcherPort; port++ {
+		ln, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+		if err != nil {
+			return false
+		}
+		_ = ln.Close()
+	}
+	return true
+}
@@ -27,6 +27,7 @@
 package main
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 )
 
 func TestAdd(t *testing.T) {
+	t.Parallel()
 	ot := otnstester.NewOtnsTest(t)
 
 	defer ot.Shutdown()
@@ -41,6 +43,42 @@ func TestAdd(t *testing.T) {
 	testAddNode(ot)
 }
 
+func TestParallelInstances(t *testing.T) {
+	t.Parallel()
+	ot1 := otnstester.NewOtnsTest(t)
+	ot2 := otnstester.NewOtnsTest(t)
+	ot1.ExpectTrue(ot1.ListenAddr() != ot2.ListenAddr())
+	ot1.ExpectTrue(ot1.OutputDir() != ot2.OutputDir())
+
+	for _, ot := range []*otnstester.OtnsTest{ot1, ot2} {
+		ot.Reset()
+		nodeid := ot.AddNode("router")
+		ot.ExpectTrue(nodeid == 1)
+	}
+
+	for _, ot := range []*otnstester.OtnsTest{ot1, ot2} {
+		ot.Go(time.Second * 3)
+		ot.ExpectTrue(ot.GetNodeState(1) == RoleLeader)
+	}
+}
+
+func TestRfSimParams(t *testing.T) {
+	t.Parallel()
+	ot := otnstester.NewOtnsTest(t)
+	defer ot.Shutdown()
+
+	ot.Reset()
+	router := ot.AddNode("router")
+	fed := ot.AddNode("fed")
+	ot.Go(time.Second * 3)
+
+	ot.Command("rfsim routers ParamRxSensitivity -90")
+	output := ot.Command("rfsim all ParamRxSensitivity")
+	ot.ExpectTrue(len(output) == 2, output)
+	ot.ExpectTrue(output[0] == fmt.Sprintf("node=%-4d ParamRxSensitivity=-90", router), output)
+	ot.ExpectTrue(output[1] == fmt.Sprintf("node=%-4d ParamRxSensitivity=-Inf", fed), output)
+}
+
 func testAddNode(test *otnstester.OtnsTest) {
 	test.Reset()
 
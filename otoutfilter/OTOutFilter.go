@@ -27,9 +27,11 @@
 package otoutfilter
 
 import (
+	"fmt"
 	"io"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/simonlingoogle/go-simplelogger"
 )
@@ -42,6 +44,7 @@ type otOutFilter struct {
 	linebuf        string
 	subr           io.Reader
 	logPrintPrefix string
+	logSeq         *uint64
 }
 
 func (cc *otOutFilter) Read(p []byte) (int, error) {
@@ -110,25 +113,36 @@ func (cc *otOutFilter) readFirstLine(p []byte) int {
 }
 
 func (cc *otOutFilter) printLog(logStr string) {
+	printPrefix := cc.logPrintPrefix
+	if cc.logSeq != nil {
+		printPrefix = fmt.Sprintf("%s #%d", printPrefix, atomic.AddUint64(cc.logSeq, 1))
+	}
+
 	logPrefix := logStr[:6]
 	switch logPrefix {
 	case "[NONE]":
-		simplelogger.Errorf("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Errorf("%s - %s", printPrefix, logStr)
 	case "[CRIT]":
-		simplelogger.Errorf("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Errorf("%s - %s", printPrefix, logStr)
 	case "[WARN]":
-		simplelogger.Warnf("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Warnf("%s - %s", printPrefix, logStr)
 	case "[NOTE]":
-		simplelogger.Infof("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Infof("%s - %s", printPrefix, logStr)
 	case "[INFO]":
-		simplelogger.Infof("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Infof("%s - %s", printPrefix, logStr)
 	case "[DEBG]":
-		simplelogger.Debugf("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Debugf("%s - %s", printPrefix, logStr)
 	default:
-		simplelogger.Errorf("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Errorf("%s - %s", printPrefix, logStr)
 	}
 }
 
 func NewOTOutFilter(reader io.Reader, logPrintPrefix string) io.Reader {
 	return &otOutFilter{subr: reader, logPrintPrefix: logPrintPrefix}
 }
+
+// NewOTOutFilterWithLogSeq creates a filter which also numbers the logs: the counter at logSeq is incremented
+// atomically for each log, and the log is printed with its sequence number, e.g. `Node<1> #42 - [INFO]...`.
+func NewOTOutFilterWithLogSeq(reader io.Reader, logPrintPrefix string, logSeq *uint64) io.Reader {
+	return &otOutFilter{subr: reader, logPrintPrefix: logPrintPrefix, logSeq: logSeq}
+}
@@ -68,3 +68,26 @@ func TestOTOutFilter(t *testing.T) {
 		t.Fatalf("output %#v, expect: %#v", string(output), expectOutput)
 	}
 }
+
+func TestOTOutFilterWithLogSeq(t *testing.T) {
+	input := "> cmd1\n" +
+		"[INFO]log1\n" +
+		"A[DEBG]log2\n" +
+		"Done\n" +
+		"[WARN]log3\n" +
+		""
+
+	var logSeq uint64
+	r := NewOTOutFilterWithLogSeq(strings.NewReader(input), "Node<1>", &logSeq)
+	output, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(output) != "cmd1\nADone\n" {
+		t.Fatalf("unexpected output %#v", string(output))
+	}
+	if logSeq != 3 {
+		t.Fatalf("log seq %d, expect 3", logSeq)
+	}
+}
@@ -28,6 +28,7 @@ package pcap
 
 import (
 	"encoding/binary"
+	"io"
 	"os"
 )
 
@@ -63,6 +64,11 @@ func NewFile(filename string) (*File, error) {
 	return pf, nil
 }
 
+// WriteFrame writes the frame. The classic pcap format keeps only the capture time of the metadata.
+func (pf *File) WriteFrame(info *FrameInfo, frame []byte) error {
+	return pf.AppendFrame(info.Ustime, frame)
+}
+
 func (pf *File) AppendFrame(ustime uint64, frame []byte) error {
 	var header [pcapFrameHeaderSize]byte
 	sec := uint32(ustime / 1000000)
@@ -83,6 +89,17 @@ func (pf *File) AppendFrame(ustime uint64, frame []byte) error {
 	return err
 }
 
+// Reset discards all frames written to the file.
+func (pf *File) Reset() error {
+	if err := pf.fd.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := pf.fd.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return pf.writeHeader()
+}
+
 func (pf *File) Sync() error {
 	return pf.fd.Sync()
 }
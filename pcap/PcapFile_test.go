@@ -62,6 +62,22 @@ func TestPcapFile(t *testing.T) {
 		}
 		assert.True(t, pcapFileHeaderSize+(pcapFrameHeaderSize+1)*(i+1) == getFileSize(t, "test.pcap"))
 	}
+
+	err = pcap.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, pcapFileHeaderSize == getFileSize(t, "test.pcap"))
+
+	err = pcap.AppendFrame(0, []byte{0x0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pcap.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, pcapFileHeaderSize+pcapFrameHeaderSize+1 == getFileSize(t, "test.pcap"))
 }
 
 func getFileSize(t *testing.T, fp string) int {
@@ -0,0 +1,237 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	dltIeee802154Tap = 283
+
+	pcapngBlockTypeSHB = 0x0A0D0D0A
+	pcapngBlockTypeIDB = 0x00000001
+	pcapngBlockTypeEPB = 0x00000006
+	pcapngByteOrder    = 0x1A2B3C4D
+
+	pcapngOptEndOfOpt    = 0
+	pcapngOptComment     = 1
+	pcapngOptIfName      = 2
+	pcapngOptIfDesc      = 3
+	pcapngOptShbUserAppl = 4
+
+	tapTlvFcsType = 0
+	tapTlvRss     = 1
+	tapTlvChannel = 3
+	tapTlvLqi     = 10
+
+	tapFcsType16Bit = 1
+)
+
+// FrameInfo is the metadata of a captured frame. Metadata not supported by the file format is ignored.
+type FrameInfo struct {
+	Ustime  uint64 // capture time in simulation time (us)
+	NodeId  int    // the originating node
+	ExtAddr uint64 // extended address of the originating node
+	Channel uint8
+	HasRssi bool // whether Rssi and Lqi are valid
+	Rssi    float32
+	Lqi     uint8
+	Comment string
+}
+
+// Writer writes captured frames to a file.
+type Writer interface {
+	// WriteFrame writes a frame (including FCS) with its metadata.
+	WriteFrame(info *FrameInfo, frame []byte) error
+	// Reset discards all frames written to the file.
+	Reset() error
+	Sync() error
+	Close() error
+}
+
+// NgFile writes frames in the pcapng format. Each node has its own interface named `node <id>`, and frames carry the
+// channel, RSSI and LQI in IEEE 802.15.4 TAP headers, and an optional comment.
+type NgFile struct {
+	fd         *os.File
+	interfaces map[int]uint32
+}
+
+// NewNgFile creates a pcapng file.
+func NewNgFile(filename string) (*NgFile, error) {
+	fd, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	pf := &NgFile{
+		fd:         fd,
+		interfaces: map[int]uint32{},
+	}
+
+	if err = pf.writeSectionHeader(); err != nil {
+		_ = pf.Close()
+		return nil, err
+	}
+
+	return pf, nil
+}
+
+func (pf *NgFile) WriteFrame(info *FrameInfo, frame []byte) error {
+	ifIdx, ok := pf.interfaces[info.NodeId]
+	if !ok {
+		var err error
+		if ifIdx, err = pf.writeInterface(info); err != nil {
+			return err
+		}
+	}
+
+	tap := tapHeader(info)
+	captured := len(tap) + len(frame)
+
+	body := make([]byte, 20, 20+pad4(captured))
+	binary.LittleEndian.PutUint32(body[0:4], ifIdx)
+	binary.LittleEndian.PutUint32(body[4:8], uint32(info.Ustime>>32))
+	binary.LittleEndian.PutUint32(body[8:12], uint32(info.Ustime))
+	binary.LittleEndian.PutUint32(body[12:16], uint32(captured))
+	binary.LittleEndian.PutUint32(body[16:20], uint32(captured))
+	body = append(body, tap...)
+	body = append(body, frame...)
+	body = append(body, make([]byte, pad4(captured)-captured)...)
+
+	var opts []byte
+	if info.Comment != "" {
+		opts = appendOption(opts, pcapngOptComment, []byte(info.Comment))
+	}
+	return pf.writeBlock(pcapngBlockTypeEPB, body, opts)
+}
+
+func (pf *NgFile) Reset() error {
+	if err := pf.fd.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := pf.fd.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	pf.interfaces = map[int]uint32{}
+	return pf.writeSectionHeader()
+}
+
+func (pf *NgFile) Sync() error {
+	return pf.fd.Sync()
+}
+
+func (pf *NgFile) Close() error {
+	return pf.fd.Close()
+}
+
+func (pf *NgFile) writeSectionHeader() error {
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint32(body[0:4], pcapngByteOrder)
+	binary.LittleEndian.PutUint16(body[4:6], 1)
+	binary.LittleEndian.PutUint16(body[6:8], 0)
+	binary.LittleEndian.PutUint64(body[8:16], math.MaxUint64) // section length is not specified
+
+	opts := appendOption(nil, pcapngOptShbUserAppl, []byte("OTNS"))
+	if err := pf.writeBlock(pcapngBlockTypeSHB, body, opts); err != nil {
+		return err
+	}
+	return pf.fd.Sync()
+}
+
+func (pf *NgFile) writeInterface(info *FrameInfo) (uint32, error) {
+	body := make([]byte, 8)
+	binary.LittleEndian.PutUint16(body[0:2], dltIeee802154Tap)
+	binary.LittleEndian.PutUint32(body[4:8], 0) // no snap length limit
+
+	opts := appendOption(nil, pcapngOptIfName, []byte(fmt.Sprintf("node %d", info.NodeId)))
+	opts = appendOption(opts, pcapngOptIfDesc, []byte(fmt.Sprintf("extaddr %016x", info.ExtAddr)))
+	if err := pf.writeBlock(pcapngBlockTypeIDB, body, opts); err != nil {
+		return 0, err
+	}
+
+	ifIdx := uint32(len(pf.interfaces))
+	pf.interfaces[info.NodeId] = ifIdx
+	return ifIdx, nil
+}
+
+func (pf *NgFile) writeBlock(blockType uint32, body []byte, opts []byte) error {
+	if opts != nil {
+		opts = appendOption(opts, pcapngOptEndOfOpt, nil)
+	}
+
+	totalLen := uint32(12 + len(body) + len(opts))
+	block := make([]byte, 8, totalLen)
+	binary.LittleEndian.PutUint32(block[0:4], blockType)
+	binary.LittleEndian.PutUint32(block[4:8], totalLen)
+	block = append(block, body...)
+	block = append(block, opts...)
+	block = append(block, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(block[totalLen-4:], totalLen)
+
+	_, err := pf.fd.Write(block)
+	return err
+}
+
+// tapHeader returns the IEEE 802.15.4 TAP header carrying the metadata of the frame.
+func tapHeader(info *FrameInfo) []byte {
+	tlvs := appendTlv(nil, tapTlvFcsType, []byte{tapFcsType16Bit})
+	tlvs = appendTlv(tlvs, tapTlvChannel, []byte{info.Channel, 0, 0}) // channel (uint16) and page 0
+	if info.HasRssi {
+		var rss [4]byte
+		binary.LittleEndian.PutUint32(rss[:], math.Float32bits(info.Rssi))
+		tlvs = appendTlv(tlvs, tapTlvRss, rss[:])
+		tlvs = appendTlv(tlvs, tapTlvLqi, []byte{info.Lqi})
+	}
+
+	header := make([]byte, 4, 4+len(tlvs))
+	binary.LittleEndian.PutUint16(header[2:4], uint16(4+len(tlvs)))
+	return append(header, tlvs...)
+}
+
+// appendTlv appends a TAP TLV, which uses the same layout as a pcapng option.
+func appendTlv(b []byte, typ uint16, value []byte) []byte {
+	return appendOption(b, typ, value)
+}
+
+func appendOption(b []byte, code uint16, value []byte) []byte {
+	var header [4]byte
+	binary.LittleEndian.PutUint16(header[0:2], code)
+	binary.LittleEndian.PutUint16(header[2:4], uint16(len(value)))
+	b = append(b, header[:]...)
+	b = append(b, value...)
+	return append(b, make([]byte, pad4(len(value))-len(value))...)
+}
+
+func pad4(n int) int {
+	return (n + 3) &^ 3
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBlock struct {
+	Type uint32
+	Body []byte
+}
+
+func readBlocks(t *testing.T, fp string) []testBlock {
+	data, err := ioutil.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var blocks []testBlock
+	for len(data) > 0 {
+		blockType := binary.LittleEndian.Uint32(data[0:4])
+		totalLen := binary.LittleEndian.Uint32(data[4:8])
+		assert.True(t, totalLen%4 == 0)
+		assert.Equal(t, totalLen, binary.LittleEndian.Uint32(data[totalLen-4:totalLen]))
+		blocks = append(blocks, testBlock{Type: blockType, Body: data[8 : totalLen-4]})
+		data = data[totalLen:]
+	}
+	return blocks
+}
+
+func TestNgFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.pcapng")
+	pcap, err := NewNgFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = pcap.Close()
+	}()
+
+	frame := []byte{0x41, 0xd8, 0x01, 0xce, 0xfa, 0xff, 0xff, 0x00, 0x00}
+	assert.Nil(t, pcap.WriteFrame(&FrameInfo{Ustime: 1000001, NodeId: 1, ExtAddr: 0x1122, Channel: 11}, frame))
+	assert.Nil(t, pcap.WriteFrame(&FrameInfo{Ustime: 1000002, NodeId: 2, Channel: 15, HasRssi: true, Rssi: -70.5,
+		Lqi: 200, Comment: "node 2"}, frame))
+	assert.Nil(t, pcap.WriteFrame(&FrameInfo{Ustime: 1000003, NodeId: 1, Channel: 11}, frame))
+	assert.Nil(t, pcap.Sync())
+
+	blocks := readBlocks(t, filename)
+	assert.Equal(t, []uint32{pcapngBlockTypeSHB, pcapngBlockTypeIDB, pcapngBlockTypeEPB, pcapngBlockTypeIDB,
+		pcapngBlockTypeEPB, pcapngBlockTypeEPB}, []uint32{blocks[0].Type, blocks[1].Type, blocks[2].Type,
+		blocks[3].Type, blocks[4].Type, blocks[5].Type})
+
+	assert.Equal(t, uint32(pcapngByteOrder), binary.LittleEndian.Uint32(blocks[0].Body[0:4]))
+	assert.Equal(t, uint16(dltIeee802154Tap), binary.LittleEndian.Uint16(blocks[1].Body[0:2]))
+	assert.Contains(t, string(blocks[1].Body), "node 1")
+	assert.Contains(t, string(blocks[1].Body), "extaddr 0000000000001122")
+	assert.Contains(t, string(blocks[3].Body), "node 2")
+
+	// interface IDs follow the order of the interface blocks
+	assert.Equal(t, uint32(0), binary.LittleEndian.Uint32(blocks[2].Body[0:4]))
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(blocks[4].Body[0:4]))
+	assert.Equal(t, uint32(0), binary.LittleEndian.Uint32(blocks[5].Body[0:4]))
+
+	epb := blocks[4].Body
+	assert.Equal(t, uint64(1000002), uint64(binary.LittleEndian.Uint32(epb[4:8]))<<32|
+		uint64(binary.LittleEndian.Uint32(epb[8:12])))
+	captured := binary.LittleEndian.Uint32(epb[12:16])
+	packet := epb[20 : 20+captured]
+	tapLen := binary.LittleEndian.Uint16(packet[2:4])
+	assert.Equal(t, frame, packet[tapLen:])
+
+	tlvs := map[uint16][]byte{}
+	for tlv := packet[4:tapLen]; len(tlv) > 0; {
+		typ, length := binary.LittleEndian.Uint16(tlv[0:2]), binary.LittleEndian.Uint16(tlv[2:4])
+		tlvs[typ] = tlv[4 : 4+length]
+		tlv = tlv[4+pad4(int(length)):]
+	}
+	assert.Equal(t, []byte{tapFcsType16Bit}, tlvs[tapTlvFcsType])
+	assert.Equal(t, []byte{15, 0, 0}, tlvs[tapTlvChannel])
+	assert.Equal(t, float32(-70.5), math.Float32frombits(binary.LittleEndian.Uint32(tlvs[tapTlvRss])))
+	assert.Equal(t, []byte{200}, tlvs[tapTlvLqi])
+	assert.Contains(t, string(epb[20+pad4(int(captured)):]), "node 2")
+
+	assert.Nil(t, pcap.Reset())
+	assert.Nil(t, pcap.WriteFrame(&FrameInfo{Ustime: 1, NodeId: 3, Channel: 11}, frame))
+	blocks = readBlocks(t, filename)
+	assert.Equal(t, 3, len(blocks))
+	assert.Contains(t, string(blocks[1].Body), "node 3")
+	assert.Equal(t, uint32(0), binary.LittleEndian.Uint32(blocks[2].Body[0:4]))
+}
@@ -26,8 +26,10 @@
 # POSSIBILITY OF SUCH DAMAGE.
 
 import ipaddress
+import json
 import logging
 import os
+import re
 import shutil
 import signal
 import subprocess
@@ -44,6 +46,8 @@ class OTNS(object):
     """
 
     MAX_SIMULATE_SPEED = 1000000  # Max simulating speed
+    _JOB_FINISHED_PATTERN = re.compile(
+        r'^job (\d+) (done|canceled): (\d+) -> (\S+), sent=(\d+)/(\d+) received=(\d+)/(\d+)$')
     PAUSE_SIMULATE_SPEED = 0
 
     def __init__(self, otns_path: Optional[str] = None, otns_args: Optional[List[str]] = None):
@@ -96,300 +100,1890 @@ class OTNS(object):
 
         self._do_command(cmd)
 
+    def wait_node_state(self, nodeid: int, state: str, timeout: Optional[float] = None) -> float:
+        """
+        Run the simulation until the node has the state (role).
+
+        :param nodeid: the node ID
+        :param state: the role: `disabled`, `detached`, `child`, `router` or `leader`
+        :param timeout: the maximum simulation time to run in seconds, or None for the default timeout
+
+        :return: the simulation time waited in seconds
+        :raises OTNSCliError: if the node does not have the state within the timeout
+        """
+        cmd = f'wait node {nodeid} state {state}'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        return self._parse_waited(self._do_command(cmd))
+
+    def wait_partitions(self, count: int, timeout: Optional[float] = None) -> float:
+        """
+        Run the simulation until all nodes are attached and form the number of partitions.
+
+        :param count: the number of partitions
+        :param timeout: the maximum simulation time to run in seconds, or None for the default timeout
+
+        :return: the simulation time waited in seconds
+        :raises OTNSCliError: if the nodes do not form the partitions within the timeout
+        """
+        cmd = f'wait partitions {count}'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        return self._parse_waited(self._do_command(cmd))
+
+    @staticmethod
+    def _parse_waited(output: List[str]) -> float:
+        assert len(output) == 1 and output[0].startswith('waited='), output
+        return float(output[0][len('waited='):-1])
+
     @property
     def speed(self) -> float:
         """
-        :return: simulating speed
+        :return: simulating speed
+        """
+        speed = self._expect_float(self._do_command(f'speed'))
+        if speed >= OTNS.MAX_SIMULATE_SPEED:
+            return OTNS.MAX_SIMULATE_SPEED  # max speed
+        elif speed <= OTNS.PAUSE_SIMULATE_SPEED:
+            return OTNS.PAUSE_SIMULATE_SPEED  # paused
+        else:
+            return speed
+
+    @speed.setter
+    def speed(self, speed: float) -> None:
+        """
+        Set simulating speed.
+
+        :param speed: new simulating speed
+        """
+        if speed >= OTNS.MAX_SIMULATE_SPEED:
+            speed = OTNS.MAX_SIMULATE_SPEED
+        elif speed <= 0:
+            speed = OTNS.PAUSE_SIMULATE_SPEED
+
+        self._do_command(f'speed {speed}')
+
+    def set_auto_speed(self, target: Optional[float]) -> None:
+        """
+        Adjust the simulating speed automatically to keep the host CPU usage near the target.
+
+        :param target: the target host CPU usage in percent, or None to disable the auto speed mode
+        """
+        self._do_command(f'speed auto {f"{target}%" if target is not None else "off"}')
+
+    def get_auto_speed(self) -> Optional[float]:
+        """
+        :return: the target host CPU usage of the auto speed mode in percent, or None if it is disabled
+        """
+        target = self._expect_str(self._do_command('speed auto'))
+        return None if target == 'off' else float(target.rstrip('%'))
+
+    def set_poll_period(self, nodeid: int, period: float) -> None:
+        ms = int(period * 1000)
+        self.node_cmd(nodeid, f'pollperiod {ms}')
+
+    def get_poll_period(self, nodeid: int) -> float:
+        ms = self._expect_int(self.node_cmd(nodeid, 'pollperiod'))
+        return ms / 1000.0
+
+    @staticmethod
+    def _detect_otns_path() -> str:
+        env_otns_path = os.getenv('OTNS')
+        if env_otns_path:
+            return env_otns_path
+
+        which_otns = shutil.which('otns')
+        if not which_otns:
+            raise RuntimeError("otns not found in current directory and PATH")
+
+        return which_otns
+
+    def _do_command(self, cmd: str) -> List[str]:
+        logging.info("OTNS <<< %s", cmd)
+        try:
+            self._otns.stdin.write(cmd.encode('ascii') + b'\n')
+            self._otns.stdin.flush()
+        except BrokenPipeError:
+            self._on_otns_eof()
+
+        output = []
+        while True:
+            line = self._otns.stdout.readline()
+            if line == b'':
+                self._on_otns_eof()
+
+            line = line.rstrip(b'\r\n').decode('utf-8')
+            logging.info(f"OTNS >>> {line}")
+            job = self._JOB_FINISHED_PATTERN.match(line)
+            if job:
+                # finished jobs are reported asynchronously, before the output of the next command
+                self.on_job_finished({
+                    'id': int(job.group(1)),
+                    'state': job.group(2),
+                    'src': int(job.group(3)),
+                    'dst': job.group(4),
+                    'sent': int(job.group(5)),
+                    'count': int(job.group(6)),
+                    'received': int(job.group(7)),
+                    'expected': int(job.group(8)),
+                })
+                continue
+
+            if line == 'Done':
+                return output
+            elif line.startswith('Error: '):
+                raise OTNSCliError(line[7:])
+
+            output.append(line)
+
+    def add(self, type: str, x: float = None, y: float = None, id=None, radio_range=None, executable=None,
+            restore=False, at: float = None, geo: Tuple[float, float] = None, script: str = None,
+            vars: Dict[str, Any] = None, poll_period: float = None, boot_delay: float = None,
+            boot_crash: float = None, cpu_factor: float = None, like: int = None) -> int:
+        """
+        Add a new node to the simulation.
+
+        :param type: node type, or the name of a node template
+        :param x: node position X
+        :param y: node position Y
+        :param id: node ID, or None to use next available node ID
+        :param radio_range: node radio range or None for default
+        :param executable: specify the executable for the new node, or use default executable if None
+        :param restore: whether the node restores network configuration from persistent storage
+        :param at: simulation time (in seconds) to add the node at, or None to add the node now
+        :param geo: geographic position (latitude, longitude) of the node in geographic mode, instead of x and y
+        :param script: init script file of the node, or None to use the default init script
+        :param vars: variables of the init script for this node only
+        :param poll_period: data poll period (in seconds) of a SED, or None to let OpenThread choose it
+        :param boot_delay: delay (in seconds) before the node starts responding, or None to boot immediately
+        :param boot_crash: probability that the node crashes at boot, or None
+        :param cpu_factor: CPU speed factor stretching the alarm delays of the node (e.g. 2.0 for a 2x slower MCU),
+                           or None
+        :param like: ID of an existing node whose active dataset and radio parameters the new node gets, or None
+
+        :return: added node ID
+        """
+        cmd = f'add {type}'
+        if x is not None:
+            cmd = cmd + f' x {x}'
+        if y is not None:
+            cmd = cmd + f' y {y}'
+
+        if id is not None:
+            cmd += f' id {id}'
+
+        if radio_range is not None:
+            cmd += f' rr {radio_range}'
+
+        if executable:
+            cmd += f' exe "{executable}"'
+
+        if restore:
+            cmd += f' restore'
+
+        if at is not None:
+            cmd += f' at {at}'
+
+        if geo is not None:
+            cmd += f' geo {geo[0]} {geo[1]}'
+
+        if poll_period is not None:
+            cmd += f' poll {poll_period}'
+
+        if script is not None:
+            cmd += f' script "{script}"'
+
+        if boot_delay is not None:
+            cmd += f' bootdelay {boot_delay}'
+
+        if boot_crash is not None:
+            cmd += f' bootcrash {boot_crash}'
+
+        if cpu_factor is not None:
+            cmd += f' cpufactor {cpu_factor}'
+
+        if like is not None:
+            cmd += f' like {like}'
+
+        for name, value in (vars or {}).items():
+            cmd += f' var {name} "{value}"'
+
+        return self._expect_int(self._do_command(cmd))
+
+    def delete(self, *nodeids: int) -> None:
+        """
+        Delete nodes from simulation by IDs.
+
+        :param nodeids: node IDs
+        """
+        cmd = f'del {" ".join(map(str, nodeids))}'
+        self._do_command(cmd)
+
+    def save(self, file: str) -> None:
+        """
+        Save the topology of the simulation to a YAML file.
+
+        :param file: the topology file
+        """
+        self._do_command(f'save "{file}"')
+
+    def load(self, file: str, add: bool = False, offset: Tuple[int, int] = None, scale: float = None,
+             rotate: float = None, ids: str = None, pan: str = None) -> Dict[int, int]:
+        """
+        Load a topology saved by `save`.
+
+        :param file: the topology file
+        :param add: add the nodes to the simulation, instead of replacing all nodes
+        :param offset: offset (x, y) of the node positions
+        :param scale: scale of the node positions
+        :param rotate: rotation of the node positions in degrees clockwise
+        :param ids: strategy of node ID conflicts: keep, shift or renumber
+        :param pan: strategy of network parameter conflicts: sim, file or strict
+
+        :return: dict of the added node IDs by the node IDs in the file
+        """
+        cmd = f'load "{file}"'
+        if add:
+            cmd += ' add'
+        if offset is not None:
+            cmd += f' offset {offset[0]} {offset[1]}'
+        if scale is not None:
+            cmd += f' scale {scale}'
+        if rotate is not None:
+            cmd += f' rotate {rotate}'
+        if ids is not None:
+            cmd += f' ids {ids}'
+        if pan is not None:
+            cmd += f' pan {pan}'
+
+        added = {}
+        for line in self._do_command(cmd):
+            fields = dict(field.split('=') for field in line.split())
+            added[int(fields['file_id'])] = int(fields['id'])
+        return added
+
+    def move(self, nodeid: int, x: int, y: int) -> None:
+        """
+        Move node to the target position.
+
+        :param nodeid: target node ID
+        :param x: target position X
+        :param y: target position Y
+        """
+        cmd = f'move {nodeid} {x} {y}'
+        self._do_command(cmd)
+
+    def move_by(self, nodeid: int, dx: int = 0, dy: int = 0) -> None:
+        """
+        Move node relative to its current position.
+
+        :param nodeid: target node ID
+        :param dx: offset of position X
+        :param dy: offset of position Y
+        """
+        self._do_command(f'move {nodeid} dx {dx:+d} dy {dy:+d}')
+
+    def align(self, nodeids: List[int], horizontal: bool = True, spacing: Optional[int] = None) -> None:
+        """
+        Align nodes on the horizontal or vertical line through the first node.
+
+        :param nodeids: node IDs
+        :param horizontal: whether to align the nodes horizontally, or vertically
+        :param spacing: the spacing between the nodes in the listed order, or None to keep their other coordinate
+        """
+        cmd = f'align {" ".join(map(str, nodeids))} {"horizontal" if horizontal else "vertical"}'
+        if spacing is not None:
+            cmd += f' spacing {spacing}'
+        self._do_command(cmd)
+
+    def grid_snap(self, size: Optional[int]) -> None:
+        """
+        Make node positions snap to a grid.
+
+        :param size: grid size, or None to disable snapping
+        """
+        self._do_command(f'grid snap {size if size else "off"}')
+
+    def move_geo(self, nodeid: int, lat: float, lon: float) -> None:
+        """
+        Move node to the target geographic position in geographic mode.
+
+        :param nodeid: target node ID
+        :param lat: target latitude in degrees
+        :param lon: target longitude in degrees
+        """
+        self._do_command(f'move {nodeid} geo {lat} {lon}')
+
+    def radiorange(self) -> int:
+        """
+        Get the default radio range of new nodes.
+
+        :return: the default radio range
+        """
+        return self._expect_int(self._do_command('radiorange'))
+
+    def radiorange_calibrate(self, edge_rssi: float, channel: int = None) -> Tuple[int, float, float]:
+        """
+        Calibrate the default radio range of new nodes from the target RSSI at the edge of the range.
+
+        :param edge_rssi: the target RSSI (in dBm) at the edge of the radio range
+        :param channel: the channel to compute the path loss on, or None for the simulation channel
+
+        :return: the radio range, and the effective maximum distance in distance units and in meters
+        """
+        cmd = f'radiorange edge {edge_rssi}'
+        if channel is not None:
+            cmd += f' ch{channel}'
+        output = self._expect_str(self._do_command(cmd))
+        fields = dict(kv.split('=') for kv in output.split())
+        return int(fields['rr']), float(fields['maxdist']), float(fields['maxdist_m'])
+
+    def radios(self, nodeid: int) -> Dict[int, Dict[str, Any]]:
+        """
+        Get the radios of a node.
+
+        :param nodeid: the node ID
+        :return: dict of radio index to the radio `channels` (a (min, max) tuple, or None for the primary radio, which
+                 covers all other channels) and `range`
+        """
+        radios = {}
+        for line in self._do_command(f'radios {nodeid}'):
+            kv = dict(kv.split('=', 1) for kv in line.split())
+            channels = None
+            if kv['channels'] != '*':
+                channels = tuple(int(ch) for ch in kv['channels'].split('-'))
+            radios[int(kv['radio'])] = {'channels': channels, 'range': int(kv['range'])}
+        return radios
+
+    def radios_add(self, nodeid: int, min_channel: int, max_channel: int = None, radio_range: int = None) -> int:
+        """
+        Add an additional radio to a node.
+
+        :param nodeid: the node ID
+        :param min_channel: the lowest channel of the radio
+        :param max_channel: the highest channel of the radio, or the lowest channel if not specified
+        :param radio_range: the radio range of the radio, or the radio range of the node if not specified
+
+        :return: the radio index
+        """
+        cmd = f'radios {nodeid} add channel {min_channel}'
+        if max_channel is not None:
+            cmd += f' {max_channel}'
+        if radio_range is not None:
+            cmd += f' range {radio_range}'
+        return self._expect_int(self._do_command(cmd))
+
+    def radios_del(self, nodeid: int, index: int) -> None:
+        """
+        Delete an additional radio of a node.
+
+        :param nodeid: the node ID
+        :param index: the radio index
+        """
+        self._do_command(f'radios {nodeid} del {index}')
+
+    def range(self, src: int, dst: int, count: int = 1) -> List[Tuple[float, float, float]]:
+        """
+        Simulate time-of-flight ranging from a node to another node.
+
+        :param src: the node ID of the initiator
+        :param dst: the node ID of the responder
+        :param count: the number of measurements
+
+        :return: list of measurements, each of format (true distance, measured distance, time of flight in ns)
+        """
+        measurements = []
+        for line in self._do_command(f'range {src} {dst} count {count}'):
+            fields = dict(kv.split('=') for kv in line.split())
+            if 'dist' not in fields:
+                continue
+            measurements.append((float(fields['dist']), float(fields['measured']), float(fields['tof'][:-2])))
+        return measurements
+
+    def range_model(self, bias: float = None, stddev: float = None) -> Tuple[float, float]:
+        """
+        Get or set the error model of ranging measurements.
+
+        :param bias: the constant error in distance units, or None to keep the current value
+        :param stddev: the standard deviation of the Gaussian error in distance units, or None to keep the current value
+
+        :return: the bias and the standard deviation of the error model
+        """
+        cmd = 'range model'
+        if bias is not None:
+            cmd += f' bias {bias}'
+        if stddev is not None:
+            cmd += f' stddev {stddev}'
+        if bias is not None or stddev is not None:
+            self._do_command(cmd)
+
+        output = self._expect_str(self._do_command('range model'))
+        fields = dict(kv.split('=') for kv in output.split())
+        return float(fields['bias']), float(fields['stddev'])
+
+    def range_events(self, *nodeids: int, enable: bool = True) -> None:
+        """
+        Enable or disable sending ranging results to nodes as events. Only nodes supporting ranging events should be
+        enabled.
+
+        :param nodeids: operating node IDs
+        :param enable: whether to send ranging events to the nodes
+        """
+        self._do_command(f'range events {" ".join(map(str, nodeids))} {"on" if enable else "off"}')
+
+    def geo_origin(self, lat: float, lon: float, alt: float = 0, scale: float = 1) -> None:
+        """
+        Enable the geographic mode, mapping node positions to geographic coordinates.
+
+        :param lat: latitude of the simulation origin in degrees
+        :param lon: longitude of the simulation origin in degrees
+        :param alt: altitude of the simulation origin in meters
+        :param scale: length in meters of one simulation distance unit
+        """
+        self._do_command(f'geo origin {lat} {lon} alt {alt} scale {scale}')
+
+    def geo_off(self) -> None:
+        """
+        Disable the geographic mode.
+        """
+        self._do_command('geo off')
+
+    def geo(self) -> Optional[Dict[str, float]]:
+        """
+        Get the geographic mode configuration.
+
+        :return: dict of `lat`, `lon`, `alt` and `scale` (in meters per unit), or None if the geographic mode is disabled
+        """
+        output = self._do_command('geo')
+        if output == ['off']:
+            return None
+
+        conf = {}
+        for kv in output[0].split():
+            k, v = kv.split('=')
+            conf[k] = float(v.rstrip('m'))
+        return conf
+
+    def ping(self, srcid: int, dst: Union[int, str, ipaddress.IPv6Address], addrtype: str = 'any', datasize: int = 0,
+             count: int = 1,
+             interval: float = 1) -> int:
+        """
+        Ping from source node to destination node in the background.
+
+        :param srcid: source node ID
+        :param dst: destination node ID or address
+        :param addrtype: address type for the destination node (only useful for destination node ID)
+        :param datasize: ping data size
+        :param count: ping count
+        :param interval: ping interval (in seconds)
+
+        :return: the job ID of the pings
+
+        Use pings() to get ping results.
+        """
+        if isinstance(dst, (str, ipaddress.IPv6Address)):
+            addrtype = ''  # addrtype only appliable for dst ID
+
+            if isinstance(dst, ipaddress.IPv6Address):
+                dst = dst.compressed
+
+        cmd = f'ping {srcid} {dst!r} {addrtype} datasize {datasize} count {count} interval {interval}'
+        return self._expect_int(self._do_command(cmd))
+
+    @property
+    def packet_loss_ratio(self) -> float:
+        """
+        Get the message drop rate of 128 byte packet.
+        Smaller packet has lower drop rate.
+
+        :return: message drop rate (0 ~ 1.0)
+        """
+        return self.packet_loss_ratios()['data']
+
+    @packet_loss_ratio.setter
+    def packet_loss_ratio(self, value: float) -> None:
+        """
+        Set the message drop rate of 128 byte packet.
+        Smaller packet has lower drop rate.
+
+        :param value: message drop ratio (0 ~ 1.0)
+        """
+        self._do_command(f'plr {value}')
+
+    def set_packet_loss_ratios(self, data: Optional[float] = None, ack: Optional[float] = None) -> None:
+        """
+        Set the packet loss ratios of data and ACK frames separately.
+
+        :param data: packet loss ratio of 128 byte data frames (0 ~ 1.0), or None to keep the current value
+        :param ack: packet loss ratio of ACK frames (0 ~ 1.0), or None to keep the current value
+        """
+        cmd = 'plr'
+        if data is not None:
+            cmd += f' data {data}'
+        if ack is not None:
+            cmd += f' ack {ack}'
+        self._do_command(cmd)
+
+    def packet_loss_ratios(self) -> Dict[str, Optional[float]]:
+        """
+        :return: the packet loss ratios of data and ACK frames, with keys 'data' and 'ack'; the ratio of ACK frames is
+                 None if it is not set separately
+        """
+        line = self._expect_str(self._do_command('plr'))
+        if '=' not in line:
+            return {'data': float(line), 'ack': None}
+        return {k: float(v) for k, v in (kv.split('=') for kv in line.split())}
+
+    def set_frame_dup(self, ratio: float, delay: Optional[float] = None, src: Optional[int] = None,
+                      dst: Optional[int] = None) -> None:
+        """
+        Set the frame duplication, which delivers a ratio of the frames a second time after a delay.
+
+        :param ratio: the ratio of duplicated frames in [0, 1]
+        :param delay: the delay of duplicates in milliseconds, or None to keep the current value
+        :param src: the source node of the link, or None for the global duplication
+        :param dst: the destination node of the link
+        """
+        cmd = 'dup'
+        if src is not None:
+            cmd += f' link {src} {dst}'
+        cmd += f' {ratio * 100}%'
+        if delay is not None:
+            cmd += f' delay {delay}'
+        self._do_command(cmd)
+
+    def clear_frame_dup(self, src: Optional[int] = None, dst: Optional[int] = None) -> None:
+        """
+        Stop the global frame duplication, or remove the frame duplication of a link.
+
+        :param src: the source node of the link, or None for the global duplication
+        :param dst: the destination node of the link
+        """
+        self._do_command('dup off' if src is None else f'dup link {src} {dst} off')
+
+    def set_frame_reorder(self, ratio: float, hold: Optional[float] = None) -> None:
+        """
+        Set the frame reordering, which holds back a ratio of the frames of each link until the next frame of the link.
+
+        :param ratio: the ratio of reordered frames in [0, 1], or 0 to stop the reordering
+        :param hold: the maximum time a frame is held back in milliseconds, or None to keep the current value
+        """
+        cmd = f'reorder {ratio * 100}%'
+        if hold is not None:
+            cmd += f' hold {hold}'
+        self._do_command(cmd)
+
+    def set_antenna(self, nodeid: int, azimuth: float, beamwidth: float, gain: float = None,
+                    back: float = None) -> None:
+        """
+        Set a sector antenna pattern on a node.
+
+        :param nodeid: node ID
+        :param azimuth: direction of the main lobe in degrees
+        :param beamwidth: width of the main lobe in degrees
+        :param gain: gain within the main lobe in dBi, or None for default
+        :param back: gain outside the main lobe in dBi, or None for default
+        """
+        cmd = f'antenna {nodeid} sector {azimuth} {beamwidth}'
+        if gain is not None:
+            cmd += f' gain {gain}'
+        if back is not None:
+            cmd += f' back {back}'
+        self._do_command(cmd)
+
+    def clear_antenna(self, nodeid: int) -> None:
+        """
+        Restore the isotropic antenna of a node.
+
+        :param nodeid: node ID
+        """
+        self._do_command(f'antenna {nodeid} off')
+
+    @property
+    def radio_model(self) -> str:
+        """
+        Get the radio model.
+
+        :return: radio model name
+        """
+        return self._expect_str(self._do_command('radiomodel'))
+
+    @radio_model.setter
+    def radio_model(self, model: str) -> None:
+        """
+        Set the radio model.
+
+        :param model: radio model name: disc, logdistance or friis
+        """
+        self._do_command(f'radiomodel {model}')
+
+    def compare(self, golden_file: str, time: float = None, count: int = None) -> None:
+        """
+        Compare the node stats timeline of the current run against a golden file.
+        Raise OTNSCliError if there are mismatches.
+
+        :param golden_file: golden file written with the -statslog flag
+        :param time: time tolerance in seconds, or None for no tolerance
+        :param count: count tolerance, or None for no tolerance
+        """
+        cmd = f'compare "{golden_file}"'
+        if time is not None:
+            cmd += f' time {time}'
+        if count is not None:
+            cmd += f' count {count}'
+        self._do_command(cmd)
+
+    def session_new(self, session_id: int = None) -> int:
+        """
+        Create a new session, which is an independent simulation in the same OTNS process.
+
+        :param session_id: session ID, or None for the lowest free ID
+        :return: ID of the new session
+        """
+        cmd = 'session new'
+        if session_id is not None:
+            cmd += f' {session_id}'
+        return self._expect_int(self._do_command(cmd))
+
+    def session_switch(self, session_id: int) -> None:
+        """
+        Switch to the session. Subsequent commands run in the session.
+
+        :param session_id: session ID
+        """
+        self._do_command(f'session {session_id}')
+
+    def session_delete(self, session_id: int) -> None:
+        """
+        Stop the simulation of the session and delete the session.
+
+        :param session_id: session ID
+        """
+        self._do_command(f'session del {session_id}')
+
+    def sessions(self) -> List[int]:
+        """
+        List all sessions.
+
+        :return: session IDs
+        """
+        return [int(line.split()[1]) for line in self._do_command('session')]
+
+    def get_radioparam(self, name: str, channel: int = None) -> float:
+        """
+        Get a radio model parameter.
+
+        :param name: parameter name, e.g. NoiseFloorDbm
+        :param channel: channel of a per-channel parameter, or None for the global value
+
+        :return: parameter value
+        """
+        cmd = f'radioparam {name}'
+        if channel is not None:
+            cmd += f' ch{channel}'
+        return self._expect_float(self._do_command(cmd))
+
+    def set_radioparam(self, name: str, value: float, channel: int = None) -> None:
+        """
+        Set a radio model parameter.
+
+        :param name: parameter name, e.g. NoiseFloorDbm
+        :param value: parameter value
+        :param channel: channel of a per-channel parameter, or None for the global value
+        """
+        cmd = f'radioparam {name}'
+        if channel is not None:
+            cmd += f' ch{channel}'
+        self._do_command(f'{cmd} {value}')
+
+    def nodes(self, **filters: Any) -> Dict[int, Dict[str, Any]]:
+        """
+        Get all nodes in simulation
+
+        :param filters: only get the nodes whose fields equal the values, e.g. state='router'
+
+        :return: dict with node IDs as keys and node information as values
+        """
+        filters = {k: str(v).lower() if isinstance(v, bool) else v for k, v in filters.items()}
+        cmd = ' '.join(['nodes'] + [f'{k}="{v}"' for k, v in filters.items()])
+        output = self._do_command(cmd)
+        nodes = {}
+        for line in output:
+            nodeinfo = {}
+            for kv in line.split():
+                k, v = kv.split('=')
+                if k in ('id', 'x', 'y'):
+                    v = int(v)
+                elif k in ('extaddr', 'rloc16'):
+                    v = int(v, 16)
+                elif k in ('failed', 'paused'):
+                    v = v == 'true'
+                elif k in ('ct_interval', 'ct_delay', 'lat', 'lon', 'alt'):
+                    v = float(v)
+                else:
+                    pass
+
+                nodeinfo[k] = v
+
+            nodes[nodeinfo['id']] = nodeinfo
+
+        return nodes
+
+    def nodes_exec(self, cmd: str, *nodeids: int) -> Tuple[Dict[int, List[str]], Dict[int, str]]:
+        """
+        Run an OT CLI command on nodes in parallel.
+
+        :param cmd: the OT CLI command
+        :param nodeids: the node IDs, or all nodes if not specified
+
+        :return: dict of node ID to the output lines of the nodes which completed the command, and dict of node ID to
+                 the error of the other nodes
+        """
+        output = self._do_command(' '.join(['nodes exec'] + [str(nodeid) for nodeid in nodeids] + [f'"{cmd}"']))
+        outputs, errors = {}, {}
+        for line in output:
+            nodeid, _, line = line.partition('|')
+            nodeid, line = int(nodeid), line[1:]
+            if line.startswith('ERROR: '):
+                errors[nodeid] = line[len('ERROR: '):]
+                continue
+
+            lines = outputs.setdefault(nodeid, [])
+            if line:
+                lines.append(line)
+        return outputs, errors
+
+    def partitions(self) -> Dict[int, Collection[int]]:
+        """
+        Get partitions.
+
+        :return: dict with partition IDs as keys and node list as values
+        """
+        output = self._do_command('partitions')
+        partitions = {}
+        for line in output:
+            line = line.split()
+            assert line[0].startswith('partition=') and line[1].startswith('nodes='), line
+            parid = int(line[0].split('=')[1], 16)
+            nodeids = list(map(int, line[1].split('=')[1].split(',')))
+            partitions[parid] = nodeids
+
+        return partitions
+
+    def radio_on(self, *nodeids: int) -> None:
+        """
+        Turn on node radio.
+
+        :param nodeids: operating node IDs
+        """
+        self._do_command(f'radio {" ".join(map(str, nodeids))} on')
+
+    def radio_off(self, *nodeids: int) -> None:
+        """
+        Turn off node radio.
+
+        :param nodeids: operating node IDs
+        """
+        self._do_command(f'radio {" ".join(map(str, nodeids))} off')
+
+    def pause(self, *nodeids: int, sigstop: bool = False) -> None:
+        """
+        Pause nodes so that they miss virtual time until resumed, without failing their radios.
+
+        :param nodeids: operating node IDs
+        :param sigstop: whether to also suspend the node processes using SIGSTOP
+        """
+        cmd = f'pause {" ".join(map(str, nodeids))}'
+        if sigstop:
+            cmd += ' sigstop'
+        self._do_command(cmd)
+
+    def rfsim(self, targets: List[Union[int, str]], param: Optional[str] = None,
+              value: Optional[float] = None) -> Optional[Dict[int, Dict[str, float]]]:
+        """
+        Get or set a per-node radio parameter on a group of nodes.
+
+        :param targets: node IDs, node ID ranges like '3-20' or node groups ('all', 'routers', 'feds', 'meds', 'seds')
+        :param param: the parameter name, e.g. 'ParamClockDrift', or None for all parameters
+        :param value: the value to set, or None to get the parameter values
+
+        :return: the parameter values by node ID if value is None
+        """
+        cmd = f'rfsim {" ".join(map(str, targets))}'
+        if param is not None:
+            cmd += f' {param}'
+        if value is not None:
+            self._do_command(f'{cmd} {value}')
+            return None
+
+        params = {}
+        for line in self._do_command(cmd):
+            fields = line.split()
+            nodeid = int(fields[0].split('=')[1])
+            params[nodeid] = {k: float(v) for k, v in (f.split('=') for f in fields[1:])}
+        return params
+
+    def rfsim_raw(self, nodeid: int, data: bytes) -> List[bytes]:
+        """
+        Send a raw OT-RFSIM platform event to a node.
+
+        :param nodeid: the node ID
+        :param data: the event data
+
+        :return: the raw events sent back by the node
+        """
+        return [bytes.fromhex(line) for line in self._do_command(f'rfsim raw {nodeid} "{data.hex()}"')]
+
+    def attachlog(self, nodeid: int) -> List[Dict[str, Any]]:
+        """
+        Get the recent attach attempts of a node.
+
+        :param nodeid: the node ID
+
+        :return: the attempts, with `start_us`, `end_us`, the selected `parent` and the `candidates` (`extaddr`, `node`,
+                 `rloc16`, `rssi`, `link_quality`, `priority`, `lq3`, `selected` and the rejection `reason`)
+        """
+        return json.loads('\n'.join(self._do_command(f'attachlog {nodeid} json')))
+
+    def provision_dataset(self, nodeid: int, filename: str) -> None:
+        """
+        Provision the active dataset of a node from a file of dataset TLVs in hex, and verify it.
+
+        :param nodeid: the node ID
+        :param filename: the dataset file
+        """
+        self._do_command(f'provision {nodeid} dataset "{filename}"')
+
+    def pcap_nodes(self, *nodeids: int) -> None:
+        """
+        Restrict the packet capture to the frames transmitted by or destined to the nodes.
+
+        :param nodeids: node IDs, or none to capture all frames
+        """
+        if nodeids:
+            self._do_command(f'pcap nodes {" ".join(map(str, nodeids))}')
+        else:
+            self._do_command('pcap nodes all')
+
+    def resume(self, *nodeids: int) -> None:
+        """
+        Resume paused nodes.
+
+        :param nodeids: operating node IDs
+        """
+        self._do_command(f'resume {" ".join(map(str, nodeids))}')
+
+    def set_init_script(self, filename: Optional[str]) -> None:
+        """
+        Set the default init script of new nodes.
+
+        :param filename: the init script file, or None to run no init script
+        """
+        if filename is None:
+            self._do_command('script off')
+        else:
+            self._do_command(f'script "{filename}"')
+
+    def set_script_var(self, name: str, value: Any) -> None:
+        """
+        Define a custom variable of init scripts.
+
+        :param name: the variable name
+        :param value: the variable value
+        """
+        self._do_command(f'script var {name} "{value}"')
+
+    def watch(self, *nodeids: int, radio: Optional[str] = None) -> None:
+        """
+        Watch the events of nodes, or their radio traces if `radio` is specified.
+
+        :param nodeids: operating node IDs
+        :param radio: the radio watch level: 'off', 'info' or 'trace'
+        """
+        cmd = f'watch {" ".join(map(str, nodeids))}'
+        if radio is not None:
+            cmd += f' radio {radio}'
+        self._do_command(cmd)
+
+    def unwatch(self, *nodeids: int) -> None:
+        """
+        Stop watching the events and radio traces of nodes.
+
+        :param nodeids: operating node IDs
+        """
+        self._do_command(f'unwatch {" ".join(map(str, nodeids))}')
+
+    def reset(self) -> None:
+        """
+        Reset the simulation: delete all nodes, clear the simulation state and restart file outputs.
+        """
+        self._do_command('reset all')
+
+    def drift(self, ppm: float, *nodeids: int) -> None:
+        """
+        Set the clock drift of nodes.
+
+        :param ppm: clock drift in parts per million
+        :param nodeids: node IDs, or all nodes if not specified
+        """
+        cmd = f'drift {ppm}'
+        if nodeids:
+            cmd += f' nodes {" ".join(map(str, nodeids))}'
+        self._do_command(cmd)
+
+    def drift_analysis(self, accuracy: Optional[float] = None) -> Dict[int, Dict[str, Any]]:
+        """
+        Analyze the side effects of the clock drift of nodes.
+
+        :param accuracy: assumed clock accuracy of each node in ppm, or None for default
+
+        :return: dict of node ID to the drift analysis of the node: drift and rel in ppm, parent (None if the node is
+                 not a child), detach, reparent and csl ('ok' or 'risk', only for children)
+        """
+        cmd = 'drift analysis'
+        if accuracy is not None:
+            cmd += f' accuracy {accuracy}'
+
+        analyses = {}
+        for line in self._do_command(cmd):
+            fields = dict(kv.split('=', 1) for kv in line.split())
+            analysis = {
+                'drift': float(fields['drift'][:-3]),
+                'parent': None if fields['parent'] == '-' else int(fields['parent']),
+                'detach': int(fields['detach']),
+                'reparent': int(fields['reparent']),
+            }
+            if 'rel' in fields:
+                analysis['rel'] = float(fields['rel'][:-3])
+                analysis['csl'] = fields['csl']
+            analyses[int(fields['node'])] = analysis
+
+        return analyses
+
+    def dutycycle_set(self, percent: Optional[float], *nodeids: int, window: Optional[float] = None) -> None:
+        """
+        Set the duty-cycle limit of nodes.
+
+        :param percent: the max percentage of the window the nodes may transmit, or None to remove the limit
+        :param nodeids: node IDs, or all nodes if not specified
+        :param window: the sliding window in seconds, or None for the default window of one hour
+        """
+        cmd = 'dutycycle off' if percent is None else f'dutycycle {percent} %'
+        if percent is not None and window is not None:
+            cmd += f' window {window}'
+        if nodeids:
+            cmd += f' nodes {" ".join(map(str, nodeids))}'
+        self._do_command(cmd)
+
+    def dutycycle(self) -> List[Dict[str, Any]]:
+        """
+        Get the duty-cycle usage of the nodes with a duty-cycle limit.
+
+        :return: the usage of each node, with `node`, `limit`, `window_us`, `airtime_us`, `util`, `frames` and `blocked`
+        """
+        return json.loads('\n'.join(self._do_command('dutycycle json')))
+
+    def power_set(self, source: str, *nodeids: int) -> None:
+        """
+        Set the power source of nodes.
+
+        :param source: power source, e.g. 'mains', 'battery 2400' or 'harvest 0.5 1.2 period 86400 duty 50 %'
+        :param nodeids: node IDs, or all nodes if not specified
+        """
+        cmd = f'power {source}'
+        if nodeids:
+            cmd += f' nodes {" ".join(map(str, nodeids))}'
+        self._do_command(cmd)
+
+    def energy(self) -> Dict[str, Any]:
+        """
+        Get the estimated radio energy consumption of the nodes.
+
+        :return: the energy report, with the energy `model`, the stats of the `nodes` and the stats per power source
+                 in `classes`
+        """
+        return json.loads('\n'.join(self._do_command('energy json')))
+
+    def energy_reset(self) -> None:
+        """
+        Reset the energy consumption of all nodes, and recharge their batteries.
+        """
+        self._do_command('energy reset')
+
+    def radio_set_fail_time(self, *nodeids: int, fail_time: Optional[Tuple[int, int]]) -> None:
+        """
+        Set node radio fail time parameters.
+
+        :param nodeids: node IDs
+        :param fail_time: fail time (fail_duration, fail_interval) or None for always on.
+        """
+        fail_duration, period_time = fail_time
+        cmd = f'radio {" ".join(map(str, nodeids))} ft {fail_duration} {period_time}'
+        self._do_command(cmd)
+
+    def pings(self) -> List[Tuple[int, str, int, float]]:
+        """
+        Get ping results.
+
+        :return: list of ping results, each of format (node ID, destination address, data size, delay)
+        """
+        output = self._do_command('pings')
+        pings = []
+        for line in output:
+            line = line.split()
+            pings.append((
+                int(line[0].split('=')[1]),
+                line[1].split('=')[1],
+                int(line[2].split('=')[1]),
+                float(line[3].split('=')[1][:-2]),
+            ))
+
+        return pings
+
+    def pingall(self, *nodeids: int, datasize: int = None, count: int = None, interval: int = None) -> List[str]:
+        """
+        Ping between all pairs of nodes and get the reachability matrix.
+
+        :param nodeids: node IDs, or all nodes if not specified
+        :param datasize: ping data size, or None for default
+        :param count: ping count for each pair, or None for default
+        :param interval: ping interval in seconds, or None for default
+
+        :return: output lines of the reachability matrix
+        """
+        cmd = 'pingall'
+        if nodeids:
+            cmd += ' ' + ' '.join(map(str, nodeids))
+        if datasize is not None:
+            cmd += f' datasize {datasize}'
+        if count is not None:
+            cmd += f' count {count}'
+        if interval is not None:
+            cmd += f' interval {interval}'
+
+        return self._do_command(cmd)
+
+    def joins(self) -> List[Tuple[int, float, float]]:
+        """
+        Get join results.
+
+        :return: list of join results, each of format (node ID, join time, session time)
+        """
+        output = self._do_command('joins')
+        joins = []
+        for line in output:
+            line = line.split()
+            joins.append((
+                int(line[0].split('=')[1]),
+                float(line[1].split('=')[1][:-1]),
+                float(line[2].split('=')[1][:-1]),
+            ))
+
+        return joins
+
+    def joins_stats(self) -> Dict[str, Dict[str, float]]:
+        """
+        Get the latency breakdown of joiner sessions.
+
+        :return: dict of phase name to dict of `count`, `p50`, `p90`, `p99` and `max` (in seconds), and the
+                 `sessions` and `joined` counts
+        """
+        output = self._do_command('joins stats')
+        stats = {}
+        for line in output:
+            fields = line.split()
+            if fields[0].startswith('sessions='):
+                for field in fields:
+                    name, val = field.split('=')
+                    stats[name] = int(val)
+                continue
+
+            phase = {}
+            for field in fields[1:]:
+                name, val = field.split('=')
+                phase[name] = int(val) if name == 'count' else float(val[:-1])
+            stats[fields[0]] = phase
+
+        return stats
+
+    def joins_stats_reset(self) -> None:
+        """
+        Discard the joiner sessions summarized by `joins_stats`.
+        """
+        self._do_command('joins stats reset')
+
+    def upgrade(self, nodeid: int, executable: str) -> None:
+        """
+        Upgrade node firmware by restarting the node with another executable, keeping its flash.
+
+        :param nodeid: the node ID
+        :param executable: the executable of the new firmware
+        """
+        self._do_command(f'upgrade {nodeid} "{executable}"')
+
+    def upgrades(self) -> List[Tuple[int, float, Optional[float]]]:
+        """
+        Get upgrade results.
+
+        :return: list of upgrade results, each of format (node ID, upgrade time, rejoin time or None if pending)
+        """
+        output = self._do_command('upgrades')
+        upgrades = []
+        for line in output:
+            line = line.split()
+            rejoin = line[2].split('=')[1]
+            upgrades.append((
+                int(line[0].split('=')[1]),
+                float(line[1].split('=')[1][:-1]),
+                float(rejoin[:-1]) if rejoin != 'pending' else None,
+            ))
+
+        return upgrades
+
+    def srp_stats(self) -> Dict[str, int]:
+        """
+        Get SRP registration statistics aggregated over all nodes.
+
+        :return: dict of SRP statistics
+        """
+        output = self._do_command('srp stats')
+        stats = {}
+        for line in output:
+            for kv in line.split():
+                k, v = kv.split('=')
+                stats[k] = int(v)
+
+        return stats
+
+    def send(self, src: int, group: str, datasize: int = None, count: int = 1, interval: int = 1) -> int:
+        """
+        Send UDP multicast messages in the background and track the delivery to each receiver.
+
+        :param src: source node ID
+        :param group: 'link', 'realm', or a multicast address
+        :param datasize: payload size of each message, or None for default
+        :param count: number of messages to send
+        :param interval: interval between messages in seconds
+
+        :return: the job ID of the messages
+        """
+        if group in ('link', 'realm'):
+            cmd = f'send {src} {group}'
+        else:
+            cmd = f'send {src} "{group}"'
+
+        if datasize is not None:
+            cmd += f' datasize {datasize}'
+
+        cmd += f' count {count} interval {interval}'
+        return self._expect_int(self._do_command(cmd))
+
+    def jobs(self) -> List[Dict[str, Any]]:
+        """
+        :return: the running and the latest finished background jobs, each a dict with id, kind, src, dst, count, sent,
+                 received, expected, start_us, end_us and state
+        """
+        return json.loads('\n'.join(self._do_command('jobs json'))) or []
+
+    def on_job_finished(self, job: Dict[str, Any]) -> None:
+        """
+        Called when a background job finishes or is canceled. Override to handle the job reports, which are received
+        while running the next command.
+
+        :param job: dict with id, state, src, dst, sent, count, received and expected
+        """
+        pass
+
+    def cancel_job(self, job: int) -> None:
+        """
+        Cancel a running background job.
+
+        :param job: the job ID
+        """
+        self._do_command(f'cancel {job}')
+
+    def send_report(self) -> List[Tuple[str, int, int, int, int]]:
+        """
+        Get multicast delivery results.
+
+        :return: list of delivery results, each of format (group, source node ID, receiver node ID, sent, received)
+        """
+        output = self._do_command('send report')
+        report = []
+        for line in output:
+            line = line.split()
+            report.append((
+                line[0].split('=')[1],
+                int(line[1].split('=')[1]),
+                int(line[2].split('=')[1]),
+                int(line[3].split('=')[1]),
+                int(line[4].split('=')[1]),
+            ))
+
+        return report
+
+    def roles(self) -> List[Tuple[float, int, str, str]]:
+        """
+        Get the role changes of all nodes in time order.
+
+        :return: list of role changes, each of format (time in seconds, node ID, old role, new role)
+        """
+        changes = []
+        for line in self._do_command('roles'):
+            change = dict(kv.split('=') for kv in line.split())
+            changes.append((float(change['time'][:-1]), int(change['node']), change['old'], change['new']))
+        return changes
+
+    def roles_reset(self) -> None:
+        """
+        Discard the role changes returned by `roles`.
+        """
+        self._do_command('roles reset')
+
+    def kpi_start(self) -> None:
+        """
+        Start collecting KPI, discarding the previous KPI.
+        """
+        self._do_command('kpi start')
+
+    def kpi_stop(self) -> None:
+        """
+        Stop collecting KPI.
+        """
+        self._do_command('kpi stop')
+
+    def kpi(self) -> Dict[str, Any]:
+        """
+        Get the KPI collected so far.
+
+        :return: the KPI, as described by the `kpi` CLI command
+        """
+        return json.loads('\n'.join(self._do_command('kpi')))
+
+    def timeline(self) -> List[Dict[str, Any]]:
+        """
+        Get the timeline of topology-affecting events of all nodes in time order.
+
+        :return: list of events, each a dict of `time_us`, `type`, `node` and optionally `old` and `new`
+        """
+        return [json.loads(line) for line in self._do_command('timeline')]
+
+    def timeline_save(self, filename: str) -> None:
+        """
+        Save the timeline of topology-affecting events to a file in JSONL format.
+
+        :param filename: the file path
+        """
+        self._do_command(f'timeline save "{filename}"')
+
+    def timeline_reset(self) -> None:
+        """
+        Discard the events of the timeline.
+        """
+        self._do_command('timeline reset')
+
+    def election(self, count: int = 1, timeout: float = None, settle: float = None) -> List[Dict[str, Any]]:
+        """
+        Run leader election experiments by failing the leader repeatedly.
+
+        :param count: the number of runs
+        :param timeout: the maximum time (in seconds) to wait for a new leader, or None for the default
+        :param settle: the time (in seconds) to settle after each election and each recovery, or None for the default
+
+        :return: list of runs, each a dict of `run`, `leader`, `new_leader` (None if not elected), `downtime` (in seconds,
+                 None if not elected) and `partitions`
+        """
+        cmd = f'election count {count}'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        if settle is not None:
+            cmd += f' settle {settle}'
+
+        runs = []
+        for line in self._do_command(cmd):
+            if not line.startswith('run='):
+                continue
+
+            run = dict(kv.split('=') for kv in line.split())
+            elected = run['new_leader'] != 'none'
+            runs.append({
+                'run': int(run['run']),
+                'leader': int(run['leader']),
+                'new_leader': int(run['new_leader']) if elected else None,
+                'downtime': float(run['downtime'][:-1]) if elected else None,
+                'partitions': int(run['partitions']),
+            })
+        return runs
+
+    def netdata(self, nodeid: int = None) -> List[Dict[str, Any]]:
+        """
+        Collect the Network Data of routers and compare the view of each router with the view of its partition leader.
+
+        :param nodeid: the router ID, or None for all routers
+
+        :return: list of router views, each with the normalized `prefixes`, `routes` and `services`, and the `missing`
+                 and `stale` entries compared with the leader view
+        """
+        cmd = 'netdata'
+        if nodeid is not None:
+            cmd += f' {nodeid}'
+        return json.loads('\n'.join(self._do_command(cmd + ' json')))
+
+    def link_metrics_start(self, srcid: int, dstid: int, interval: Optional[float] = None) -> None:
+        """
+        Start probing the Link Metrics of the link from the source node to the neighbor.
+
+        :param srcid: the source node
+        :param dstid: the neighbor node
+        :param interval: the probe interval in seconds, or None for the default interval
+        """
+        cmd = f'linkmetrics start {srcid} {dstid}'
+        if interval is not None:
+            cmd += f' interval {interval}'
+        self._do_command(cmd)
+
+    def link_metrics_stop(self, srcid: Optional[int] = None, dstid: Optional[int] = None) -> None:
+        """
+        Stop probing the Link Metrics of a link, or of all links.
+
+        :param srcid: the source node, or None to stop all probes
+        :param dstid: the neighbor node
+        """
+        self._do_command('linkmetrics stop' if srcid is None else f'linkmetrics stop {srcid} {dstid}')
+
+    def link_metrics(self) -> List[Dict[str, Any]]:
+        """
+        :return: the aggregates of the Link Metrics reports of each link, each a dict with src, dst, samples, and the
+                 min, max, avg and last values of lqi, margin and rssi
+        """
+        return json.loads('\n'.join(self._do_command('linkmetrics json'))) or []
+
+    def link_metrics_series(self, srcid: int, dstid: int) -> List[Dict[str, Any]]:
+        """
+        :return: the latest Link Metrics reports of the link, each a dict with time_us, pdus, lqi, margin and rssi
+        """
+        return json.loads('\n'.join(self._do_command(f'linkmetrics {srcid} {dstid} json'))) or []
+
+    def link_metrics_reset(self) -> None:
+        """
+        Discard all Link Metrics reports.
+        """
+        self._do_command('linkmetrics reset')
+
+    def macstats(self) -> Dict[int, Dict[str, Any]]:
+        """
+        :return: the MAC retransmission distribution and backoff statistics of each node, each a dict with frames,
+                 retries (the number of frames by number of retransmissions) and backoff
+        """
+        stats = json.loads('\n'.join(self._do_command('macstats json'))) or {}
+        return {int(nodeid): s for nodeid, s in stats.items()}
+
+    def macstats_reset(self) -> None:
+        """
+        Discard the MAC retransmission statistics of all nodes.
+        """
+        self._do_command('macstats reset')
+
+    def load_templates(self, file: str) -> None:
+        """
+        Load the node templates from a YAML file, replacing the current ones.
+
+        :param file: the YAML file of the node templates
+        """
+        self._do_command(f'templates load "{file}"')
+
+    def templates(self) -> Dict[str, Dict[str, str]]:
+        """
+        :return: the node templates, each a dict of the parameters set by the template (type, exe, rr, ...)
+        """
+        templates = {}
+        for line in self._do_command('templates'):
+            fields = line.split()
+            templates[fields[0]] = dict(field.split('=', 1) for field in fields[1:])
+        return templates
+
+    def linkstats(self, srcid: int, dstid: int) -> Dict[str, Any]:
+        """
+        Get the MAC frame statistics and the latency histogram of the unicast frames from a node to another.
+
+        :param srcid: the source node
+        :param dstid: the destination node
+
+        :return: dict of the `frames`, `delivered`, `lost`, `retried` and `retries` counts, the `latency` as dict of
+                 `count`, `p50`, `p90`, `p99` and `max` (in ms), and the `histogram` as list of (bucket, count)
+        """
+        output = self._do_command(f'linkstats {srcid} {dstid}')
+        stats = {'histogram': []}
+        for line in output:
+            fields = line.split()
+            if fields[0].startswith('src='):
+                for field in fields[2:]:
+                    name, val = field.split('=')
+                    stats[name] = int(val)
+            elif fields[0] == 'latency':
+                latency = {}
+                for field in fields[1:]:
+                    name, val = field.split('=')
+                    latency[name] = int(val) if name == 'count' else float(val[:-2])
+                stats['latency'] = latency
+            else:
+                stats['histogram'].append((fields[0], int(fields[1])))
+
+        return stats
+
+    def linkstats_reset(self) -> None:
+        """
+        Discard the link statistics of all links.
+        """
+        self._do_command('linkstats reset')
+
+    def frag_send(self, srcid: int, dstid: int, datasize: Optional[int] = None, count: int = 1,
+                  interval: int = 1) -> int:
+        """
+        Send UDP messages larger than a frame from a node to another node in the background, so that they are
+        fragmented.
+
+        :param srcid: the source node
+        :param dstid: the destination node
+        :param datasize: the UDP payload size, or None for the default size
+        :param count: the number of messages
+        :param interval: the interval between messages in seconds
+
+        :return: the job ID of the messages
+        """
+        cmd = f'frag send {srcid} {dstid} count {count} interval {interval}'
+        if datasize is not None:
+            cmd += f' datasize {datasize}'
+        return self._expect_int(self._do_command(cmd))
+
+    def frag_stats(self) -> Dict[str, Any]:
+        """
+        Get the 6LoWPAN fragmentation and reassembly statistics.
+
+        :return: dict of the `datagrams`, `fragments`, `receptions`, `reassembled`, `pending` and `undecrypted`
+                 counts, the reassembly `ratio` (in %), the `latency` as dict of `count`, `p50`, `p90`, `p99` and
+                 `max` (in seconds), and `nodes` as dict of transmitting node ID to dict of its counts
+        """
+        output = self._do_command('frag stats')
+        stats = {'nodes': {}}
+        for line in output:
+            fields = line.split()
+            if fields[0].startswith('datagrams='):
+                for field in fields:
+                    name, val = field.split('=')
+                    stats[name] = float(val[:-1]) if name == 'ratio' else int(val)
+            elif fields[0] == 'latency':
+                latency = {}
+                for field in fields[1:]:
+                    name, val = field.split('=')
+                    latency[name] = int(val) if name == 'count' else float(val[:-1])
+                stats['latency'] = latency
+            else:
+                node = dict((name, int(val)) for name, val in (field.split('=') for field in fields))
+                stats['nodes'][node.pop('node')] = node
+
+        return stats
+
+    def frag_stats_reset(self) -> None:
+        """
+        Discard the fragmentation statistics.
+        """
+        self._do_command('frag stats reset')
+
+    def netdiag_sweep(self, nodeid: int, tlvs: Optional[List[int]] = None,
+                      timeout: Optional[float] = None) -> Dict[str, Any]:
+        """
+        Collect an inventory of the network with network diagnostic queries from a node to all other nodes.
+        The simulation runs for the timeout while the replies arrive.
+
+        :param nodeid: the node sending the queries
+        :param tlvs: the network diagnostic TLV types, or None for the default types
+        :param timeout: the simulation time in seconds to wait for the replies, or None for the default timeout
+
+        :return: the inventory, with the `replies` of nodes (`node`, `address` and parsed `tlvs`) and the `missing` nodes
+        """
+        cmd = f'netdiag sweep {nodeid}'
+        if tlvs:
+            cmd += ' tlv ' + ' '.join(str(tlv) for tlv in tlvs)
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        return json.loads('\n'.join(self._do_command(cmd + ' json')))
+
+    def energyscan_all(self, duration: Optional[int] = None, timeout: Optional[float] = None) -> Dict[str, Any]:
         """
-        speed = self._expect_float(self._do_command(f'speed'))
-        if speed >= OTNS.MAX_SIMULATE_SPEED:
-            return OTNS.MAX_SIMULATE_SPEED  # max speed
-        elif speed <= OTNS.PAUSE_SIMULATE_SPEED:
-            return OTNS.PAUSE_SIMULATE_SPEED  # paused
-        else:
-            return speed
+        Run energy scans on all routers over all channels, and aggregate the results into a channel quality matrix.
+        The simulation runs until all scans finish.
 
-    @speed.setter
-    def speed(self, speed: float) -> None:
+        :param duration: the scan duration per channel in ms, or None for the default duration
+        :param timeout: the simulation time in seconds to wait for the scans, or None for the default timeout
+
+        :return: the matrix, with the `nodes` (`node` and `rssi` of each channel), the `channels` (`channel`, `nodes`,
+                 `avg_rssi` and `max_rssi`), the `recommended` channel and the `missing` nodes
         """
-        Set simulating speed.
+        cmd = 'energyscan all'
+        if duration is not None:
+            cmd += f' duration {duration}'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        return json.loads('\n'.join(self._do_command(cmd + ' json')))
 
-        :param speed: new simulating speed
+    def alert_add(self, condition: str, threshold: Optional[float] = None, for_secs: Optional[float] = None,
+                  webhook: Optional[str] = None) -> int:
         """
-        if speed >= OTNS.MAX_SIMULATE_SPEED:
-            speed = OTNS.MAX_SIMULATE_SPEED
-        elif speed <= 0:
-            speed = OTNS.PAUSE_SIMULATE_SPEED
+        Add an alert rule.
 
-        self._do_command(f'speed {speed}')
+        :param condition: detached, partitions or pingloss
+        :param threshold: the partition count or ping loss percentage, None for detached
+        :param for_secs: the duration the condition must hold, or the ping loss window, in seconds
+        :param webhook: the URL to post the alert events to, or None
 
-    def set_poll_period(self, nodeid: int, period: float) -> None:
-        ms = int(period * 1000)
-        self.node_cmd(nodeid, f'pollperiod {ms}')
+        :return: the rule ID
+        """
+        cmd = f'alert add {condition}'
+        if threshold is not None:
+            cmd += f' {threshold}'
+        if for_secs is not None:
+            cmd += f' for {for_secs}'
+        if webhook is not None:
+            cmd += f' webhook "{webhook}"'
+        return self._expect_int(self._do_command(cmd))
 
-    def get_poll_period(self, nodeid: int) -> float:
-        ms = self._expect_int(self.node_cmd(nodeid, 'pollperiod'))
-        return ms / 1000.0
+    def alert_del(self, ruleid: int) -> None:
+        """
+        Delete an alert rule.
 
-    @staticmethod
-    def _detect_otns_path() -> str:
-        env_otns_path = os.getenv('OTNS')
-        if env_otns_path:
-            return env_otns_path
+        :param ruleid: the rule ID
+        """
+        self._do_command(f'alert del {ruleid}')
 
-        which_otns = shutil.which('otns')
-        if not which_otns:
-            raise RuntimeError("otns not found in current directory and PATH")
+    def alerts(self) -> List[Dict[str, Any]]:
+        """
+        Get the alert rules.
 
-        return which_otns
+        :return: the rules, with `id`, `condition`, `threshold`, `for_us` and `webhook`
+        """
+        return json.loads('\n'.join(self._do_command('alert json')))
 
-    def _do_command(self, cmd: str) -> List[str]:
-        logging.info("OTNS <<< %s", cmd)
-        try:
-            self._otns.stdin.write(cmd.encode('ascii') + b'\n')
-            self._otns.stdin.flush()
-        except BrokenPipeError:
-            self._on_otns_eof()
+    def alert_events(self) -> List[Dict[str, Any]]:
+        """
+        Get the alert events, in order.
 
-        output = []
-        while True:
-            line = self._otns.stdout.readline()
-            if line == b'':
-                self._on_otns_eof()
+        :return: the events, with `time_us`, `rule`, `condition`, `node`, `value`, `resolved` and `message`
+        """
+        return json.loads('\n'.join(self._do_command('alert events json')))
 
-            line = line.rstrip(b'\r\n').decode('utf-8')
-            logging.info(f"OTNS >>> {line}")
-            if line == 'Done':
-                return output
-            elif line.startswith('Error: '):
-                raise OTNSCliError(line[7:])
+    def alert_reset(self) -> None:
+        """
+        Clear the alert events.
+        """
+        self._do_command('alert reset')
 
-            output.append(line)
+    def counters(self) -> Dict[str, int]:
+        """
+        Get counters.
 
-    def add(self, type: str, x: float = None, y: float = None, id=None, radio_range=None, executable=None,
-            restore=False) -> int:
+        :return: dict of all counters
         """
-        Add a new node to the simulation.
+        output = self._do_command('counters')
+        counters = {}
+        for line in output:
+            name, val = line.split()
+            val = int(val)
+            counters[name] = val
 
-        :param type: node type
-        :param x: node position X
-        :param y: node position Y
-        :param id: node ID, or None to use next available node ID
-        :param radio_range: node radio range or None for default
-        :param executable: specify the executable for the new node, or use default executable if None
-        :param restore: whether the node restores network configuration from persistent storage
+        return counters
 
-        :return: added node ID
+    def json_command(self, cmd: str) -> Any:
         """
-        cmd = f'add {type}'
-        if x is not None:
-            cmd = cmd + f' x {x}'
-        if y is not None:
-            cmd = cmd + f' y {y}'
+        Run an OTNS CLI command with the `json` option and parse its output, e.g. `json_command('nodes')`.
+        The output format of other commands is not changed.
 
-        if id is not None:
-            cmd += f' id {id}'
+        :param cmd: the OTNS CLI command, which accepts the `json` option as its last argument
 
-        if radio_range is not None:
-            cmd += f' rr {radio_range}'
+        :return: the parsed JSON output
+        """
+        return json.loads('\n'.join(self._do_command(cmd + ' json')))
 
-        if executable:
-            cmd += f' exe "{executable}"'
+    def summary(self) -> Dict[str, Any]:
+        """
+        Get the summary of the simulation run.
 
-        if restore:
-            cmd += f' restore'
+        :return: the summary, as described by the `summary` CLI command, e.g. `sim_time_us`, `speedup`, `nodes`,
+                 `tx_frames`, `mac`, `pings` and `joins`
+        """
+        return json.loads('\n'.join(self._do_command('summary json')))
 
-        return self._expect_int(self._do_command(cmd))
+    def top(self, *nodeids: int) -> List[Dict[str, Any]]:
+        """
+        Get the memory and CPU usage of node processes, busiest first.
 
-    def delete(self, *nodeids: int) -> None:
+        :param nodeids: node IDs, or all nodes if empty
+        :return: the usage of each node process, e.g. `node`, `pid`, `rss`, `peak_rss`, `rss_growth` (in bytes),
+                 `cpu_time` (in seconds) and `cpu` (in percent)
         """
-        Delete nodes from simulation by IDs.
+        cmd = ' '.join(['top'] + [str(nodeid) for nodeid in nodeids] + ['json'])
+        return json.loads('\n'.join(self._do_command(cmd)))
 
-        :param nodeids: node IDs
+    def top_total(self) -> Dict[str, int]:
         """
-        cmd = f'del {" ".join(map(str, nodeids))}'
-        self._do_command(cmd)
+        Get the total memory and CPU usage of the simulation.
 
-    def move(self, nodeid: int, x: int, y: int) -> None:
+        :return: the total usage, i.e. `nodes`, `rss_bytes`, `peak_rss_bytes`, `cpu_us`, `otns_rss_bytes` and
+                 `otns_cpu_us`
         """
-        Move node to the target position.
+        return json.loads('\n'.join(self._do_command('top total json')))
 
-        :param nodeid: target node ID
-        :param x: target position X
-        :param y: target position Y
+    def throttle(self, *nodeids: int, limit: Optional[int] = None) -> Dict[int, Dict[str, int]]:
         """
-        cmd = f'move {nodeid} {x} {y}'
-        self._do_command(cmd)
+        Set or get the rate limit of log events of nodes. UART writes are never throttled.
 
-    def ping(self, srcid: int, dst: Union[int, str, ipaddress.IPv6Address], addrtype: str = 'any', datasize: int = 0,
-             count: int = 1,
-             interval: float = 1) -> None:
+        :param nodeids: the node IDs, or all nodes (including nodes added later) if not specified
+        :param limit: the maximum number of events per second, or 0 for no limit. Get the limits if not specified.
+
+        :return: dict of node ID to the node `limit` and `throttled` events
         """
-        Ping from source node to destination node.
+        cmd = ' '.join(['throttle'] + [str(nodeid) for nodeid in nodeids])
+        if limit is not None:
+            self._do_command(f'{cmd} limit {limit}')
+            return {}
 
-        :param srcid: source node ID
-        :param dst: destination node ID or address
-        :param addrtype: address type for the destination node (only useful for destination node ID)
-        :param datasize: ping data size
-        :param count: ping count
-        :param interval: ping interval (in seconds)
+        nodes = {}
+        for line in self._do_command(cmd):
+            kv = dict(kv.split('=', 1) for kv in line.split())
+            if 'node' in kv:
+                nodes[int(kv['node'])] = {'limit': int(kv['limit']), 'throttled': int(kv['throttled'])}
+        return nodes
 
-        Use pings() to get ping results.
+    def coalesce(self, window: Optional[int] = None) -> Optional[int]:
         """
-        if isinstance(dst, (str, ipaddress.IPv6Address)):
-            addrtype = ''  # addrtype only appliable for dst ID
+        Set or get the alarm coalescing window.
 
-            if isinstance(dst, ipaddress.IPv6Address):
-                dst = dst.compressed
+        :param window: the window in microseconds within which alarms are fired together, 0 to disable alarm
+                       coalescing, or None to get the current window
 
-        cmd = f'ping {srcid} {dst!r} {addrtype} datasize {datasize} count {count} interval {interval}'
-        self._do_command(cmd)
+        :return: the current window in microseconds, or 0 if alarm coalescing is disabled
+        """
+        if window is not None:
+            self._do_command(f'coalesce {window}' if window > 0 else 'coalesce off')
+            return window
 
-    @property
-    def packet_loss_ratio(self) -> float:
+        line = self._do_command('coalesce')[0]
+        if line == 'off':
+            return 0
+        kv = dict(kv.split('=', 1) for kv in line.split())
+        return int(kv['window'].rstrip('us'))
+
+    def stall(self, timeout: Optional[float] = None, forcefail: Optional[bool] = None) -> List[Dict[str, str]]:
         """
-        Get the message drop rate of 128 byte packet.
-        Smaller packet has lower drop rate.
+        Configure the stall detector, and get the stalls detected so far.
 
-        :return: message drop rate (0 ~ 1.0)
+        :param timeout: the wall-clock duration (in seconds) without virtual time progress after which a stall is
+                        reported, 0 to disable, or None to keep the current value
+        :param forcefail: whether to fail the nodes which do not respond, or None to keep the current value
+
+        :return: list of stalls, each a dict with time, duration, alive and forcefailed
         """
-        return self._expect_float(self._do_command('plr'))
+        cmd = 'stall'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        if forcefail is not None:
+            cmd += f' forcefail {"on" if forcefail else "off"}'
+        if cmd != 'stall':
+            self._do_command(cmd)
 
-    @packet_loss_ratio.setter
-    def packet_loss_ratio(self, value: float) -> None:
+        stalls = []
+        for line in self._do_command('stall')[1:]:
+            stalls.append(dict(kv.split('=', 1) for kv in line.split()))
+        return stalls
+
+    def set_site(self, site: Optional[str], *nodeids: int) -> None:
         """
-        Set the message drop rate of 128 byte packet.
-        Smaller packet has lower drop rate.
+        Put the nodes on the infrastructure link of a site, or take them off the backbone.
 
-        :param value: message drop ratio (0 ~ 1.0)
+        :param site: the site name, or None to take the nodes off the backbone
+        :param nodeids: the node IDs
         """
-        self._do_command(f'plr {value}')
+        self._do_command(f'site {site if site is not None else "off"} {" ".join(map(str, nodeids))}')
 
-    def nodes(self) -> Dict[int, Dict[str, Any]]:
+    def sites(self) -> Dict[str, List[int]]:
         """
-        Get all nodes in simulation
+        :return: the node IDs of each site
+        """
+        sites = {}
+        for line in self._do_command('site'):
+            name, nodeids = line.split(': ', 1)
+            sites[name] = [int(nodeid) for nodeid in nodeids.split(',')]
+        return sites
 
-        :return: dict with node IDs as keys and node information as values
+    def set_backbone(self, latency: Optional[float] = None, jitter: Optional[float] = None,
+                     loss: Optional[float] = None) -> None:
         """
-        cmd = 'nodes'
-        output = self._do_command(cmd)
-        nodes = {}
-        for line in output:
-            nodeinfo = {}
-            for kv in line.split():
-                k, v = kv.split('=')
-                if k in ('id', 'x', 'y'):
-                    v = int(v)
-                elif k in ('extaddr', 'rloc16'):
-                    v = int(v, 16)
-                elif k in ('failed',):
-                    v = v == 'true'
-                elif k in ('ct_interval', 'ct_delay'):
-                    v = float(v)
-                else:
-                    pass
+        Set the backbone link between sites.
 
-                nodeinfo[k] = v
+        :param latency: the one-way latency in milliseconds, or None to keep the current value
+        :param jitter: the maximum random delay added to the latency in milliseconds, or None to keep the current value
+        :param loss: the ratio of lost packets in [0, 1], or None to keep the current value
+        """
+        cmd = 'site backbone'
+        if latency is not None:
+            cmd += f' latency {latency}'
+        if jitter is not None:
+            cmd += f' jitter {jitter}'
+        if loss is not None:
+            cmd += f' loss {loss * 100}%'
+        self._do_command(cmd)
 
-            nodes[nodeinfo['id']] = nodeinfo
+    def history(self, nodeid: int, start: Optional[float] = None, end: Optional[float] = None) -> List[Dict[str, Any]]:
+        """
+        Get the history of the state of a node.
 
-        return nodes
+        :param nodeid: the node ID
+        :param start: the simulation time in seconds, to get only the state at the time, or None for the whole history
+        :param end: the simulation time in seconds, to get the state at start followed by the transitions until end
 
-    def partitions(self) -> Dict[int, Collection[int]]:
+        :return: list of states, each a dict with time_us, role, rloc16, parent, partition_id and deleted
         """
-        Get partitions.
+        cmd = f'history {nodeid}'
+        if start is not None:
+            cmd += f' {start}'
+            if end is not None:
+                cmd += f' {end}'
+        return json.loads('\n'.join(self._do_command(cmd + ' json')))
 
-        :return: dict with partition IDs as keys and node list as values
+    def health(self) -> List[Dict[str, Any]]:
         """
-        output = self._do_command('partitions')
-        partitions = {}
-        for line in output:
-            line = line.split()
-            assert line[0].startswith('partition=') and line[1].startswith('nodes='), line
-            parid = int(line[0].split('=')[1], 16)
-            nodeids = list(map(int, line[1].split('=')[1].split(',')))
-            partitions[parid] = nodeids
+        Get the health report of node processes which died unexpectedly.
 
-        return partitions
+        :return: list of health events, each a dict with time_us, node, status, stderr, action and restarts
+        """
+        return json.loads('\n'.join(self._do_command('health json')))
 
-    def radio_on(self, *nodeids: int) -> None:
+    def health_policy(self, policy: Optional[str] = None, max_restarts: Optional[int] = None) -> Tuple[str, int]:
         """
-        Turn on node radio.
+        Get or set the action taken when a node process dies unexpectedly.
 
-        :param nodeids: operating node IDs
+        :param policy: 'restart' or 'delete', or None to keep the current policy
+        :param max_restarts: the number of times a node is restarted before being deleted, or None to keep the
+                             current value
+
+        :return: the policy and the maximum number of restarts
         """
-        self._do_command(f'radio {" ".join(map(str, nodeids))} on')
+        if policy is not None or max_restarts is not None:
+            if policy is None:
+                policy = self.health_policy()[0]
+            cmd = f'health policy {policy}'
+            if max_restarts is not None:
+                cmd += f' max {max_restarts}'
+            self._do_command(cmd)
 
-    def radio_off(self, *nodeids: int) -> None:
+        output = self._expect_str(self._do_command('health policy'))
+        fields = dict(kv.split('=') for kv in output.split())
+        return fields['policy'], int(fields['max'])
+
+    def zombies(self) -> List[Dict[str, int]]:
         """
-        Turn off node radio.
+        Get the zombie nodes, which stopped producing events while not asleep.
 
-        :param nodeids: operating node IDs
+        :return: the zombie nodes, with `node`, `last_event_us` and `detected_us`
         """
-        self._do_command(f'radio {" ".join(map(str, nodeids))} off')
+        return json.loads('\n'.join(self._do_command('zombies json')))
 
-    def radio_set_fail_time(self, *nodeids: int, fail_time: Optional[Tuple[int, int]]) -> None:
+    def zombies_policy(self, timeout: Optional[float] = None, restart: Optional[bool] = None) -> None:
         """
-        Set node radio fail time parameters.
+        Configure the detection of zombie nodes.
 
-        :param nodeids: node IDs
-        :param fail_time: fail time (fail_duration, fail_interval) or None for always on.
+        :param timeout: the virtual time (in seconds) without events after which a node is a zombie, or 0 to disable
+        :param restart: whether zombie nodes are restarted or deleted according to the health policy
         """
-        fail_duration, period_time = fail_time
-        cmd = f'radio {" ".join(map(str, nodeids))} ft {fail_duration} {period_time}'
+        cmd = 'zombies policy'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+        if restart is not None:
+            cmd += f' restart {"on" if restart else "off"}'
         self._do_command(cmd)
 
-    def pings(self) -> List[Tuple[int, str, int, float]]:
+    def cmd_budget(self, seconds: Optional[float] = None) -> None:
         """
-        Get ping results.
+        Set the virtual-time budget of node commands.
 
-        :return: list of ping results, each of format (node ID, destination address, data size, delay)
+        :param seconds: the virtual time a node command may consume, or None to run node commands at a fixed virtual
+                        time
         """
-        output = self._do_command('pings')
-        pings = []
-        for line in output:
-            line = line.split()
-            pings.append((
-                int(line[0].split('=')[1]),
-                line[1].split('=')[1],
-                int(line[2].split('=')[1]),
-                float(line[3].split('=')[1][:-2]),
-            ))
+        self._do_command(f'cmdbudget {seconds if seconds is not None else "off"}')
 
-        return pings
+    def cmd_costs(self, reset: bool = False) -> List[Dict[str, Any]]:
+        """
+        Get the virtual-time costs of the latest node commands run under the command budget.
 
-    def joins(self) -> List[Tuple[int, float, float]]:
+        :param reset: whether to discard the costs after reading them
+
+        :return: the costs, with the node, command, start time, cost and exceeded flag of each command
         """
-        Get join results.
+        costs = json.loads('\n'.join(self._do_command('cmdbudget costs json'))) or []
+        if reset:
+            self._do_command('cmdbudget reset')
+        return costs
 
-        :return: list of join results, each of format (node ID, join time, session time)
+    def coverage_merge(self, output: Optional[str] = None) -> Optional[str]:
         """
-        output = self._do_command('joins')
-        joins = []
-        for line in output:
-            line = line.split()
-            joins.append((
-                int(line[0].split('=')[1]),
-                float(line[1].split('=')[1][:-1]),
-                float(line[2].split('=')[1][:-1]),
-            ))
+        Merge the LLVM coverage profiles of the nodes which have exited. OTNS must be started with `-coverage <dir>`.
 
-        return joins
+        :param output: the merged profile file, relative to the coverage directory, or None for merged.profdata
 
-    def counters(self) -> Dict[str, int]:
+        :return: the path of the merged profile, or None if there were only gcov data
         """
-        Get counters.
+        cmd = 'coverage merge'
+        if output is not None:
+            cmd += f' "{output}"'
+        for line in self._do_command(cmd):
+            if line.startswith('profiles='):
+                return line.split('output=', 1)[1]
+        return None
 
-        :return: dict of all counters
+    def counters_reset(self) -> None:
         """
-        output = self._do_command('counters')
-        counters = {}
-        for line in output:
-            name, val = line.split()
-            val = int(val)
-            counters[name] = val
+        Clear counters.
+        """
+        self._do_command('counters reset')
 
-        return counters
+    def counters_snapshot(self, name: str) -> None:
+        """
+        Take a snapshot of counters, replacing the previous snapshot of the same name.
+
+        :param name: the snapshot name
+        """
+        self._do_command(f'counters snapshot "{name}"')
+
+    def counters_snapshots(self) -> Dict[str, float]:
+        """
+        Get counter snapshots.
+
+        :return: dict of the simulation time (in seconds) of each snapshot by name
+        """
+        snapshots = {}
+        for line in self._do_command('counters snapshot'):
+            name, time = line.rsplit(' time=', 1)
+            snapshots[name.strip()] = float(time[:-1])
+        return snapshots
+
+    def counters_diff(self, frm: str, to: Optional[str] = None) -> Dict[str, int]:
+        """
+        Get the increase of counters between snapshots.
+
+        :param frm: the name of the earlier snapshot
+        :param to: the name of the later snapshot, or None for the current counters
+
+        :return: dict of the increase of all counters
+        """
+        cmd = f'counters diff "{frm}"'
+        if to is not None:
+            cmd += f' "{to}"'
+        diff = {}
+        for line in self._do_command(cmd):
+            name, val = line.split()
+            if name != 'Duration':
+                diff[name] = int(val)
+        return diff
 
     def prefix_add(self, nodeid: int, prefix: str, preferred=True, slaac=True, dhcp=False, dhcp_other=False,
                    default_route=True, on_mesh=True, stable=True, prf='med') -> None:
@@ -624,8 +2218,8 @@ class OTNS(object):
         self.node_cmd(nodeid, f"commissioner joiner add {usr} {pwd}{timeout_s}")
 
     def config_visualization(self, broadcast_message: bool = None, unicast_message: bool = None,
-                             ack_message: bool = None, router_table: bool = None, child_table: bool = None) \
-            -> Dict[str, bool]:
+                             ack_message: bool = None, router_table: bool = None, child_table: bool = None,
+                             nodes: Optional[Collection[Union[int, str]]] = None) -> Dict[str, Any]:
         """
         Configure the visualization options.
 
@@ -634,8 +2228,10 @@ class OTNS(object):
         :param ack_message: whether or not to visualize ACK messages
         :param router_table: whether or not to visualize router tables
         :param child_table: whether or not to visualize child tables
+        :param nodes: the node IDs or ranges like '3-5' which messages are visualized, or an empty collection for all
+                      nodes
 
-        :return: the active visualization options
+        :return: the active visualization options, with the node IDs (or None for all nodes) in `nodes`
         """
         cmd = "cv"
         if broadcast_message is not None:
@@ -653,10 +2249,17 @@ class OTNS(object):
         if child_table is not None:
             cmd += " ctb " + ("on" if child_table else "off")
 
+        if nodes is not None:
+            cmd += f' nodes {" ".join(map(str, nodes)) if nodes else "all"}'
+
         output = self._do_command(cmd)
         vopts = {}
         for line in output:
             line = line.split('=')
+            if line[0] == 'nodes':
+                vopts['nodes'] = None if line[1] == 'all' else [int(id) for id in line[1].split(',')]
+                continue
+
             assert len(line) == 2 and line[1] in ('on', 'off'), line
             vopts[line[0]] = (line[1] == "on")
 
@@ -0,0 +1,227 @@
+#!/usr/bin/env python3
+# Copyright (c) 2020, The OTNS Authors.
+# All rights reserved.
+#
+# Redistribution and use in source and binary forms, with or without
+# modification, are permitted provided that the following conditions are met:
+# 1. Redistributions of source code must retain the above copyright
+#    notice, this list of conditions and the following disclaimer.
+# 2. Redistributions in binary form must reproduce the above copyright
+#    notice, this list of conditions and the following disclaimer in the
+#    documentation and/or other materials provided with the distribution.
+# 3. Neither the name of the copyright holder nor the
+#    names of its contributors may be used to endorse or promote products
+#    derived from this software without specific prior written permission.
+#
+# THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+# AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+# IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+# ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+# LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+# CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+# SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+# INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+# CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+# ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+# POSSIBILITY OF SUCH DAMAGE.
+
+import json
+import logging
+from typing import Any, Callable, Dict, Generator, List, Optional
+
+from .OTNS import OTNS
+
+# A scenario runs on each simulation of a comparison. It issues commands to the simulation and yields the simulation
+# time (in seconds) to advance before its next step.
+Scenario = Callable[[OTNS], Generator[float, None, None]]
+
+
+class OTNSComparison(object):
+    """
+    OTNSComparison runs the same scenario on several OTNS simulations (variants) in lock-step virtual time, e.g. with
+    different radio models or OpenThread versions, and compares their KPIs at the end.
+
+    Each variant runs in its own OTNS process with its own listen port. All variants use the same PRNG seed and start
+    collecting KPIs at the same simulation time, so that comparisons are reproducible.
+    """
+
+    def __init__(self,
+                 variants: Dict[str, List[str]],
+                 otns_path: Optional[str] = None,
+                 seed: int = 1,
+                 step: float = 1.0,
+                 first_port: int = 10000):
+        """
+        Launch the simulations of the variants.
+
+        :param variants: dict of variant name to additional OTNS arguments. The first variant is the baseline of the
+                         comparison.
+        :param otns_path: the OTNS executable, or detect it if not specified
+        :param seed: the PRNG seed of all variants
+        :param step: the simulation time (in seconds) all variants advance in turn when running the scenario
+        :param first_port: the listen port of the first variant, the next variants use the following multiples of 1000
+        """
+        assert len(variants) >= 2, 'at least 2 variants are required for a comparison'
+        assert step > 0, step
+
+        self._step = step
+        self._time = 0.0
+        self._sims: Dict[str, OTNS] = {}
+        try:
+            for i, (name, args) in enumerate(variants.items()):
+                args = ['-listen', f'localhost:{first_port + i * 1000}', '-seed', str(seed)] + list(args)
+                logging.info("launching variant %s: %s", name, ' '.join(args))
+                self._sims[name] = OTNS(otns_path=otns_path, otns_args=args)
+        except Exception:
+            self.close()
+            raise
+
+    @property
+    def simulations(self) -> Dict[str, OTNS]:
+        """
+        :return: dict of variant name to its simulation
+        """
+        return dict(self._sims)
+
+    @property
+    def time(self) -> float:
+        """
+        :return: the simulation time (in seconds) of all variants since the scenario started
+        """
+        return self._time
+
+    def close(self) -> None:
+        """
+        Close the simulations of all variants.
+        """
+        for sim in self._sims.values():
+            sim.close()
+
+    def run(self, scenario: Scenario) -> Dict[str, Any]:
+        """
+        Run the scenario on all variants in lock-step virtual time and compare their KPIs.
+
+        The scenario is started on each variant in turn. Each time it yields a duration, all variants advance by
+        the duration in turn, `step` seconds at a time, before the scenario continues. The scenario must yield the
+        same durations on all variants.
+
+        :param scenario: the scenario
+        :return: the comparison report, see `compare`
+        """
+        for sim in self._sims.values():
+            sim.speed = OTNS.MAX_SIMULATE_SPEED
+            sim.kpi_start()
+
+        steps = {name: scenario(sim) for name, sim in self._sims.items()}
+        while steps:
+            durations = {}
+            for name in list(steps):
+                try:
+                    durations[name] = float(next(steps[name]))
+                except StopIteration:
+                    del steps[name]
+
+            if not durations:
+                break
+            if len(durations) != len(self._sims) or len(set(durations.values())) != 1:
+                raise ValueError(f'scenario is not in lock-step at {self._time}s: {durations}')
+
+            self._advance(next(iter(durations.values())))
+
+        for sim in self._sims.values():
+            sim.kpi_stop()
+
+        return self.compare()
+
+    def _advance(self, duration: float) -> None:
+        end = self._time + duration
+        while self._time < end:
+            step = min(self._step, end - self._time)
+            for sim in self._sims.values():
+                sim.go(step)
+            self._time += step
+
+    def compare(self) -> Dict[str, Any]:
+        """
+        Compare the KPIs of the variants.
+
+        :return: the comparison report, with `baseline` (the name of the first variant), `time` (the scenario duration
+                 in seconds), and `metrics`, a dict of metric name to a dict of variant name to the metric value and
+                 its difference to the baseline. Metrics are the KPI counters, the airtime and the numeric values of
+                 the simulation summary, e.g. `counters.RadioEvents`, `airtime.util` and `pings.timeouts`.
+        """
+        values = {name: self._collect_metrics(sim) for name, sim in self._sims.items()}
+        baseline = next(iter(self._sims))
+
+        metrics = {}
+        for metric in sorted(set().union(*values.values())):
+            base = values[baseline].get(metric)
+            metrics[metric] = {}
+            for name in self._sims:
+                val = values[name].get(metric)
+                diff = None if val is None or base is None else val - base
+                metrics[metric][name] = {'value': val, 'diff': diff}
+
+        return {'baseline': baseline, 'time': self._time, 'metrics': metrics}
+
+    @staticmethod
+    def save_report(report: Dict[str, Any], filename: str) -> None:
+        """
+        Save the comparison report to a file in JSON format.
+
+        :param report: the comparison report
+        :param filename: the file name
+        """
+        with open(filename, 'w') as f:
+            json.dump(report, f, indent=2)
+
+    @staticmethod
+    def format_report(report: Dict[str, Any]) -> str:
+        """
+        Format the comparison report as a text table, with one row per metric and the differences to the baseline.
+
+        :param report: the comparison report
+        :return: the text table
+        """
+        names = list(next(iter(report['metrics'].values()), {}).keys())
+        width = max([len('metric')] + [len(metric) for metric in report['metrics']])
+        lines = [f'{"metric":<{width}}' + ''.join(f' {name:>20}' for name in names)]
+        for metric, variants in report['metrics'].items():
+            cols = []
+            for name in names:
+                val, diff = variants[name]['value'], variants[name]['diff']
+                col = '-' if val is None else f'{val:g}'
+                if name != report['baseline'] and diff:
+                    col += f' ({diff:+g})'
+                cols.append(f' {col:>20}')
+            lines.append(f'{metric:<{width}}' + ''.join(cols))
+        return '\n'.join(lines)
+
+    @staticmethod
+    def _collect_metrics(sim: OTNS) -> Dict[str, float]:
+        metrics = {}
+        kpi = sim.kpi()
+        for name, val in kpi['counters'].items():
+            metrics[f'counters.{name}'] = val
+        for name in ('airtime_us', 'util', 'fairness'):
+            metrics[f'airtime.{name}'] = kpi['airtime'][name]
+
+        def flatten(prefix: str, obj: Any):
+            if isinstance(obj, dict):
+                for key, val in obj.items():
+                    flatten(f'{prefix}.{key}' if prefix else key, val)
+            elif isinstance(obj, (int, float)) and not isinstance(obj, bool):
+                metrics[prefix] = obj
+
+        summary = sim.summary()
+        # the wall-clock times depend on the host, and the node counts are given by the scenario
+        for key in ('wall_time_us', 'run_time_us', 'speedup', 'nodes'):
+            summary.pop(key, None)
+        flatten('', summary)
+        return metrics
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, exc_type, exc_val, exc_tb):
+        self.close()
@@ -0,0 +1,113 @@
+#!/usr/bin/env python3
+# Copyright (c) 2020, The OTNS Authors.
+# All rights reserved.
+#
+# Redistribution and use in source and binary forms, with or without
+# modification, are permitted provided that the following conditions are met:
+# 1. Redistributions of source code must retain the above copyright
+#    notice, this list of conditions and the following disclaimer.
+# 2. Redistributions in binary form must reproduce the above copyright
+#    notice, this list of conditions and the following disclaimer in the
+#    documentation and/or other materials provided with the distribution.
+# 3. Neither the name of the copyright holder nor the
+#    names of its contributors may be used to endorse or promote products
+#    derived from this software without specific prior written permission.
+#
+# THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+# AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+# IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+# ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+# LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+# CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+# SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+# INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+# CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+# ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+# POSSIBILITY OF SUCH DAMAGE.
+
+from typing import Collection, Dict, Optional
+
+import grpc
+
+from otns.proto import visualize_grpc_pb2 as pb
+from otns.proto.visualize_grpc_pb2_grpc import SimulationServiceStub
+
+
+class OTNSGrpc(object):
+    """
+    OTNSGrpc controls an OTNS simulation through the gRPC SimulationService, which returns structured data instead of
+    CLI output text.
+    """
+
+    def __init__(self, address: str = 'localhost:8999', token: Optional[str] = None):
+        """
+        :param address: gRPC address of OTNS, which is at the dispatcher port - 1
+        :param token: control token of OTNS started with `-control-token`, without which the client is read-only
+        """
+        self._channel = grpc.insecure_channel(address)
+        self._stub = SimulationServiceStub(self._channel)
+        self._metadata = (('otns-token', token),) if token else None
+
+    def close(self) -> None:
+        self._channel.close()
+
+    def counters(self) -> Dict[str, int]:
+        """
+        Get the dispatcher counters.
+
+        :return: counter values by name
+        """
+        return dict(self._stub.GetCounters(pb.Empty(), metadata=self._metadata).counters)
+
+    def get_radio_params(self) -> pb.RadioParams:
+        """
+        Get the radio model parameters.
+
+        :return: parameters, with channel_noise_floor_dbm mapping channels to noise floors, and models listing the
+                 radio models which can be selected
+        """
+        return self._stub.GetRadioParams(pb.Empty(), metadata=self._metadata)
+
+    def set_radio_params(self, **params) -> None:
+        """
+        Update radio model parameters, e.g. set_radio_params(model='friis', channel_noise_floor_dbm={15: -85}).
+        Channels in reset_channel_noise_floor restore the default noise floor of the channels.
+        """
+        self._stub.SetRadioParams(pb.RadioParams(**params), metadata=self._metadata)
+
+    def kpi_start(self) -> None:
+        """
+        Start collecting KPI, discarding the previous KPI.
+        """
+        self._stub.StartKpi(pb.Empty(), metadata=self._metadata)
+
+    def kpi_stop(self) -> None:
+        """
+        Stop collecting KPI.
+        """
+        self._stub.StopKpi(pb.Empty(), metadata=self._metadata)
+
+    def kpi(self) -> pb.Kpi:
+        """
+        Get the KPI collected so far.
+
+        :return: KPI with the fields of `kpi save`
+        """
+        return self._stub.GetKpi(pb.Empty(), metadata=self._metadata)
+
+    def kpi_save(self, filename: str) -> None:
+        """
+        Save the KPI to a JSON file.
+
+        :param filename: file path on the OTNS host
+        """
+        self._stub.SaveKpi(pb.SaveKpiRequest(filename=filename), metadata=self._metadata)
+
+    def watch(self, nodeids: Collection[int], watch: bool = True) -> None:
+        """
+        Watch or unwatch nodes to log their events.
+
+        :param nodeids: node IDs
+        :param watch: whether to watch or unwatch the nodes
+        """
+        self._stub.Watch(pb.WatchRequest(node_ids=list(nodeids), unwatch=not watch), metadata=self._metadata)
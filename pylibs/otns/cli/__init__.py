@@ -26,5 +26,6 @@
 # POSSIBILITY OF SUCH DAMAGE.
 
 from .OTNS import OTNS
+from .OTNSComparison import OTNSComparison
 
-__all__ = ['OTNS']
+__all__ = ['OTNS', 'OTNSComparison']
@@ -15,7 +15,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x14visualize_grpc.proto\x12\x11visualize_grpc_pb\"\x12\n\x10VisualizeRequest\"\xb7\x0b\n\x0eVisualizeEvent\x12\x33\n\x08\x61\x64\x64_node\x18\x01 \x01(\x0b\x32\x1f.visualize_grpc_pb.AddNodeEventH\x00\x12\x39\n\x0b\x64\x65lete_node\x18\x02 \x01(\x0b\x32\".visualize_grpc_pb.DeleteNodeEventH\x00\x12@\n\x0fset_node_rloc16\x18\x03 \x01(\x0b\x32%.visualize_grpc_pb.SetNodeRloc16EventH\x00\x12<\n\rset_node_role\x18\x04 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeRoleEventH\x00\x12:\n\x0cset_node_pos\x18\x05 \x01(\x0b\x32\".visualize_grpc_pb.SetNodePosEventH\x00\x12K\n\x15set_node_partition_id\x18\x06 \x01(\x0b\x32*.visualize_grpc_pb.SetNodePartitionIdEventH\x00\x12:\n\x0con_node_fail\x18\x07 \x01(\x0b\x32\".visualize_grpc_pb.OnNodeFailEventH\x00\x12@\n\x0fon_node_recover\x18\x08 \x01(\x0b\x32%.visualize_grpc_pb.OnNodeRecoverEventH\x00\x12\x37\n\nset_parent\x18\t \x01(\x0b\x32!.visualize_grpc_pb.SetParentEventH\x00\x12\x37\n\ncount_down\x18\n \x01(\x0b\x32!.visualize_grpc_pb.CountDownEventH\x00\x12\x42\n\x10show_demo_legend\x18\x0b \x01(\x0b\x32&.visualize_grpc_pb.ShowDemoLegendEventH\x00\x12;\n\x0c\x61\x64vance_time\x18\x0c \x01(\x0b\x32#.visualize_grpc_pb.AdvanceTimeEventH\x00\x12\x42\n\x10\x61\x64\x64_router_table\x18\r \x01(\x0b\x32&.visualize_grpc_pb.AddRouterTableEventH\x00\x12H\n\x13remove_router_table\x18\x0e \x01(\x0b\x32).visualize_grpc_pb.RemoveRouterTableEventH\x00\x12@\n\x0f\x61\x64\x64_child_table\x18\x0f \x01(\x0b\x32%.visualize_grpc_pb.AddChildTableEventH\x00\x12\x46\n\x12remove_child_table\x18\x10 \x01(\x0b\x32(.visualize_grpc_pb.RemoveChildTableEventH\x00\x12,\n\x04send\x18\x11 \x01(\x0b\x32\x1c.visualize_grpc_pb.SendEventH\x00\x12\x35\n\tset_speed\x18\x12 \x01(\x0b\x32 .visualize_grpc_pb.SetSpeedEventH\x00\x12\x36\n\theartbeat\x18\x13 \x01(\x0b\x32!.visualize_grpc_pb.HeartbeatEventH\x00\x12\x45\n\x12on_ext_addr_change\x18\x14 \x01(\x0b\x32\'.visualize_grpc_pb.OnExtAddrChangeEventH\x00\x12\x35\n\tset_title\x18\x15 \x01(\x0b\x32 .visualize_grpc_pb.SetTitleEventH\x00\x12<\n\rset_node_mode\x18\x16 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeModeEventH\x00\x12\x42\n\x10set_network_info\x18\x17 \x01(\x0b\x32&.visualize_grpc_pb.SetNetworkInfoEventH\x00\x42\x06\n\x04type\"a\n\tSendEvent\x12\x0e\n\x06src_id\x18\x01 \x01(\x05\x12\x0e\n\x06\x64st_id\x18\x02 \x01(\x05\x12\x34\n\x07mv_info\x18\x03 \x01(\x0b\x32#.visualize_grpc_pb.MsgVisualizeInfo\"z\n\x10MsgVisualizeInfo\x12\x0f\n\x07\x63hannel\x18\x01 \x01(\r\x12\x15\n\rframe_control\x18\x02 \x01(\r\x12\x0b\n\x03seq\x18\x03 \x01(\r\x12\x16\n\x0e\x64st_addr_short\x18\x04 \x01(\r\x12\x19\n\x11\x64st_addr_extended\x18\x05 \x01(\x04\"8\n\x13\x41\x64\x64RouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\";\n\x16RemoveRouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"7\n\x12\x41\x64\x64\x43hildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\":\n\x15RemoveChildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"\x1e\n\rSetSpeedEvent\x12\r\n\x05speed\x18\x01 \x01(\x01\"\x10\n\x0eHeartbeatEvent\"-\n\x10\x41\x64vanceTimeEvent\x12\n\n\x02ts\x18\x01 \x01(\x04\x12\r\n\x05speed\x18\x02 \x01(\x01\"3\n\x0eSetParentEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"3\n\x0e\x43ountDownEvent\x12\x13\n\x0b\x64uration_ms\x18\x01 \x01(\x03\x12\x0c\n\x04text\x18\x02 \x01(\t\":\n\x13ShowDemoLegendEvent\x12\t\n\x01x\x18\x01 \x01(\x05\x12\t\n\x01y\x18\x02 \x01(\x05\x12\r\n\x05title\x18\x03 \x01(\t\"8\n\x0fSetNodePosEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\"R\n\x10SetNodeRoleEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12-\n\x04role\x18\x02 \x01(\x0e\x32\x1f.visualize_grpc_pb.OtDeviceRole\"@\n\x17SetNodePartitionIdEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x14\n\x0cpartition_id\x18\x02 \x01(\r\"\"\n\x0fOnNodeFailEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"%\n\x12OnNodeRecoverEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"\"\n\x0f\x44\x65leteNodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"J\n\x0c\x41\x64\x64NodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x13\n\x0bradio_range\x18\x04 \x01(\x05\"x\n\x08NodeMode\x12\x17\n\x0frx_on_when_idle\x18\x01 \x01(\x08\x12\x1c\n\x14secure_data_requests\x18\x02 \x01(\x08\x12\x1a\n\x12\x66ull_thread_device\x18\x03 \x01(\x08\x12\x19\n\x11\x66ull_network_data\x18\x04 \x01(\x08\"5\n\x12SetNodeRloc16Event\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x0e\n\x06rloc16\x18\x02 \x01(\r\"9\n\x14OnExtAddrChangeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"G\n\rSetTitleEvent\x12\r\n\x05title\x18\x01 \x01(\t\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x11\n\tfont_size\x18\x04 \x01(\x05\"S\n\x10SetNodeModeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12.\n\tnode_mode\x18\x02 \x01(\x0b\x32\x1b.visualize_grpc_pb.NodeMode\"D\n\x13SetNetworkInfoEvent\x12\x0c\n\x04real\x18\x01 \x01(\x08\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x03 \x01(\t\"!\n\x0e\x43ommandRequest\x12\x0f\n\x07\x63ommand\x18\x01 \x01(\t\"!\n\x0f\x43ommandResponse\x12\x0e\n\x06output\x18\x01 \x03(\t\"R\n\x0bReplayEntry\x12\x11\n\ttimestamp\x18\x01 \x01(\x04\x12\x30\n\x05\x65vent\x18\x02 \x01(\x0b\x32!.visualize_grpc_pb.VisualizeEvent\"\x07\n\x05\x45mpty*\x98\x01\n\x0cOtDeviceRole\x12\x1b\n\x17OT_DEVICE_ROLE_DISABLED\x10\x00\x12\x1b\n\x17OT_DEVICE_ROLE_DETACHED\x10\x01\x12\x18\n\x14OT_DEVICE_ROLE_CHILD\x10\x02\x12\x19\n\x15OT_DEVICE_ROLE_ROUTER\x10\x03\x12\x19\n\x15OT_DEVICE_ROLE_LEADER\x10\x04\x32\xbf\x01\n\x14VisualizeGrpcService\x12U\n\tVisualize\x12#.visualize_grpc_pb.VisualizeRequest\x1a!.visualize_grpc_pb.VisualizeEvent0\x01\x12P\n\x07\x43ommand\x12!.visualize_grpc_pb.CommandRequest\x1a\".visualize_grpc_pb.CommandResponseB/Z-github.com/openthread/ot-ns/visualize/grpc/pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x14visualize_grpc.proto\x12\x11visualize_grpc_pb\"\x12\n\x10VisualizeRequest\"\xb7\x0b\n\x0eVisualizeEvent\x12\x33\n\x08\x61\x64\x64_node\x18\x01 \x01(\x0b\x32\x1f.visualize_grpc_pb.AddNodeEventH\x00\x12\x39\n\x0b\x64\x65lete_node\x18\x02 \x01(\x0b\x32\".visualize_grpc_pb.DeleteNodeEventH\x00\x12@\n\x0fset_node_rloc16\x18\x03 \x01(\x0b\x32%.visualize_grpc_pb.SetNodeRloc16EventH\x00\x12<\n\rset_node_role\x18\x04 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeRoleEventH\x00\x12:\n\x0cset_node_pos\x18\x05 \x01(\x0b\x32\".visualize_grpc_pb.SetNodePosEventH\x00\x12K\n\x15set_node_partition_id\x18\x06 \x01(\x0b\x32*.visualize_grpc_pb.SetNodePartitionIdEventH\x00\x12:\n\x0con_node_fail\x18\x07 \x01(\x0b\x32\".visualize_grpc_pb.OnNodeFailEventH\x00\x12@\n\x0fon_node_recover\x18\x08 \x01(\x0b\x32%.visualize_grpc_pb.OnNodeRecoverEventH\x00\x12\x37\n\nset_parent\x18\t \x01(\x0b\x32!.visualize_grpc_pb.SetParentEventH\x00\x12\x37\n\ncount_down\x18\n \x01(\x0b\x32!.visualize_grpc_pb.CountDownEventH\x00\x12\x42\n\x10show_demo_legend\x18\x0b \x01(\x0b\x32&.visualize_grpc_pb.ShowDemoLegendEventH\x00\x12;\n\x0c\x61\x64vance_time\x18\x0c \x01(\x0b\x32#.visualize_grpc_pb.AdvanceTimeEventH\x00\x12\x42\n\x10\x61\x64\x64_router_table\x18\r \x01(\x0b\x32&.visualize_grpc_pb.AddRouterTableEventH\x00\x12H\n\x13remove_router_table\x18\x0e \x01(\x0b\x32).visualize_grpc_pb.RemoveRouterTableEventH\x00\x12@\n\x0f\x61\x64\x64_child_table\x18\x0f \x01(\x0b\x32%.visualize_grpc_pb.AddChildTableEventH\x00\x12\x46\n\x12remove_child_table\x18\x10 \x01(\x0b\x32(.visualize_grpc_pb.RemoveChildTableEventH\x00\x12,\n\x04send\x18\x11 \x01(\x0b\x32\x1c.visualize_grpc_pb.SendEventH\x00\x12\x35\n\tset_speed\x18\x12 \x01(\x0b\x32 .visualize_grpc_pb.SetSpeedEventH\x00\x12\x36\n\theartbeat\x18\x13 \x01(\x0b\x32!.visualize_grpc_pb.HeartbeatEventH\x00\x12\x45\n\x12on_ext_addr_change\x18\x14 \x01(\x0b\x32\'.visualize_grpc_pb.OnExtAddrChangeEventH\x00\x12\x35\n\tset_title\x18\x15 \x01(\x0b\x32 .visualize_grpc_pb.SetTitleEventH\x00\x12<\n\rset_node_mode\x18\x16 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeModeEventH\x00\x12\x42\n\x10set_network_info\x18\x17 \x01(\x0b\x32&.visualize_grpc_pb.SetNetworkInfoEventH\x00\x42\x06\n\x04type\"a\n\tSendEvent\x12\x0e\n\x06src_id\x18\x01 \x01(\x05\x12\x0e\n\x06\x64st_id\x18\x02 \x01(\x05\x12\x34\n\x07mv_info\x18\x03 \x01(\x0b\x32#.visualize_grpc_pb.MsgVisualizeInfo\"z\n\x10MsgVisualizeInfo\x12\x0f\n\x07\x63hannel\x18\x01 \x01(\r\x12\x15\n\rframe_control\x18\x02 \x01(\r\x12\x0b\n\x03seq\x18\x03 \x01(\r\x12\x16\n\x0e\x64st_addr_short\x18\x04 \x01(\r\x12\x19\n\x11\x64st_addr_extended\x18\x05 \x01(\x04\"8\n\x13\x41\x64\x64RouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\";\n\x16RemoveRouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"7\n\x12\x41\x64\x64\x43hildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\":\n\x15RemoveChildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"\x1e\n\rSetSpeedEvent\x12\r\n\x05speed\x18\x01 \x01(\x01\"\x10\n\x0eHeartbeatEvent\"-\n\x10\x41\x64vanceTimeEvent\x12\n\n\x02ts\x18\x01 \x01(\x04\x12\r\n\x05speed\x18\x02 \x01(\x01\"3\n\x0eSetParentEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"3\n\x0e\x43ountDownEvent\x12\x13\n\x0b\x64uration_ms\x18\x01 \x01(\x03\x12\x0c\n\x04text\x18\x02 \x01(\t\":\n\x13ShowDemoLegendEvent\x12\t\n\x01x\x18\x01 \x01(\x05\x12\t\n\x01y\x18\x02 \x01(\x05\x12\r\n\x05title\x18\x03 \x01(\t\"8\n\x0fSetNodePosEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\"R\n\x10SetNodeRoleEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12-\n\x04role\x18\x02 \x01(\x0e\x32\x1f.visualize_grpc_pb.OtDeviceRole\"@\n\x17SetNodePartitionIdEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x14\n\x0cpartition_id\x18\x02 \x01(\r\"\"\n\x0fOnNodeFailEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"%\n\x12OnNodeRecoverEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"\"\n\x0f\x44\x65leteNodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"J\n\x0c\x41\x64\x64NodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x13\n\x0bradio_range\x18\x04 \x01(\x05\"x\n\x08NodeMode\x12\x17\n\x0frx_on_when_idle\x18\x01 \x01(\x08\x12\x1c\n\x14secure_data_requests\x18\x02 \x01(\x08\x12\x1a\n\x12\x66ull_thread_device\x18\x03 \x01(\x08\x12\x19\n\x11\x66ull_network_data\x18\x04 \x01(\x08\"5\n\x12SetNodeRloc16Event\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x0e\n\x06rloc16\x18\x02 \x01(\r\"9\n\x14OnExtAddrChangeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"G\n\rSetTitleEvent\x12\r\n\x05title\x18\x01 \x01(\t\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x11\n\tfont_size\x18\x04 \x01(\x05\"S\n\x10SetNodeModeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12.\n\tnode_mode\x18\x02 \x01(\x0b\x32\x1b.visualize_grpc_pb.NodeMode\"D\n\x13SetNetworkInfoEvent\x12\x0c\n\x04real\x18\x01 \x01(\x08\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x03 \x01(\t\"!\n\x0e\x43ommandRequest\x12\x0f\n\x07\x63ommand\x18\x01 \x01(\t\"!\n\x0f\x43ommandResponse\x12\x0e\n\x06output\x18\x01 \x03(\t\"R\n\x0bReplayEntry\x12\x11\n\ttimestamp\x18\x01 \x01(\x04\x12\x30\n\x05\x65vent\x18\x02 \x01(\x0b\x32!.visualize_grpc_pb.VisualizeEvent\"\x07\n\x05\x45mpty\"x\n\x08\x43ounters\x12;\n\x08\x63ounters\x18\x01 \x03(\x0b\x32).visualize_grpc_pb.Counters.CountersEntry\x1a/\n\rCountersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x04:\x02\x38\x01\"\xe4\x03\n\x0bRadioParams\x12\x12\n\x05model\x18\x01 \x01(\tH\x00\x88\x01\x01\x12\x1c\n\x0fnoise_floor_dbm\x18\x02 \x01(\x01H\x01\x88\x01\x01\x12Y\n\x17\x63hannel_noise_floor_dbm\x18\x03 \x03(\x0b\x32\x38.visualize_grpc_pb.RadioParams.ChannelNoiseFloorDbmEntry\x12\x1f\n\x12path_loss_exponent\x18\x04 \x01(\x01H\x02\x88\x01\x01\x12\x19\n\x0ctx_power_dbm\x18\x05 \x01(\x01H\x03\x88\x01\x01\x12\x17\n\nmin_snr_db\x18\x06 \x01(\x01H\x04\x88\x01\x01\x12\x1b\n\x0emeter_per_unit\x18\x07 \x01(\x01H\x05\x88\x01\x01\x12!\n\x19reset_channel_noise_floor\x18\x08 \x03(\r\x12\x0e\n\x06models\x18\t \x03(\t\x1a;\n\x19\x43hannelNoiseFloorDbmEntry\x12\x0b\n\x03key\x18\x01 \x01(\r\x12\r\n\x05value\x18\x02 \x01(\x01:\x02\x38\x01\x42\x08\n\x06_modelB\x12\n\x10_noise_floor_dbmB\x15\n\x13_path_loss_exponentB\x0f\n\r_tx_power_dbmB\r\n\x0b_min_snr_dbB\x11\n\x0f_meter_per_unit\"\x95\x05\n\x03Kpi\x12\x10\n\x08start_us\x18\x01 \x01(\x04\x12\x0f\n\x07stop_us\x18\x02 \x01(\x04\x12\x0f\n\x07running\x18\x03 \x01(\x08\x12\x36\n\x08\x63ounters\x18\x04 \x03(\x0b\x32$.visualize_grpc_pb.Kpi.CountersEntry\x12\x31\n\x07\x61irtime\x18\x05 \x01(\x0b\x32 .visualize_grpc_pb.AirtimeReport\x12,\n\x03mac\x18\x06 \x03(\x0b\x32\x1f.visualize_grpc_pb.Kpi.MacEntry\x12\x38\n\tresources\x18\x07 \x03(\x0b\x32%.visualize_grpc_pb.Kpi.ResourcesEntry\x12\x39\n\x0clink_metrics\x18\x08 \x03(\x0b\x32#.visualize_grpc_pb.LinkMetricsStats\x12\x31\n\x06mac_tx\x18\t \x03(\x0b\x32!.visualize_grpc_pb.Kpi.MacTxEntry\x1a/\n\rCountersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x04:\x02\x38\x01\x1aG\n\x08MacEntry\x12\x0b\n\x03key\x18\x01 \x01(\x05\x12*\n\x05value\x18\x02 \x01(\x0b\x32\x1b.visualize_grpc_pb.MacStats:\x02\x38\x01\x1aR\n\x0eResourcesEntry\x12\x0b\n\x03key\x18\x01 \x01(\x05\x12/\n\x05value\x18\x02 \x01(\x0b\x32 .visualize_grpc_pb.ResourceUsage:\x02\x38\x01\x1aK\n\nMacTxEntry\x12\x0b\n\x03key\x18\x01 \x01(\x05\x12,\n\x05value\x18\x02 \x01(\x0b\x32\x1d.visualize_grpc_pb.MacTxStats:\x02\x38\x01\"\xa2\x01\n\rAirtimeReport\x12\x17\n\x0fwindow_start_us\x18\x01 \x01(\x04\x12\x15\n\rwindow_end_us\x18\x02 \x01(\x04\x12\x12\n\nairtime_us\x18\x03 \x01(\x04\x12\x0c\n\x04util\x18\x04 \x01(\x01\x12\x10\n\x08\x66\x61irness\x18\x05 \x01(\x01\x12-\n\x05nodes\x18\x06 \x03(\x0b\x32\x1e.visualize_grpc_pb.AirtimeStat\"n\n\x0b\x41irtimeStat\x12\x0c\n\x04node\x18\x01 \x01(\x05\x12\x0e\n\x06\x66rames\x18\x02 \x01(\x04\x12\x12\n\nairtime_us\x18\x03 \x01(\x04\x12\x0c\n\x04util\x18\x04 \x01(\x01\x12\r\n\x05share\x18\x05 \x01(\x01\x12\x10\n\x08\x64ominant\x18\x06 \x01(\x08\"\x8d\x01\n\x08MacStats\x12\x0f\n\x07retries\x18\x01 \x01(\x04\x12\x0b\n\x03\x63\x63\x61\x18\x02 \x01(\x04\x12\x35\n\x05\x64rops\x18\x03 \x03(\x0b\x32&.visualize_grpc_pb.MacStats.DropsEntry\x1a,\n\nDropsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x04:\x02\x38\x01\"J\n\rResourceUsage\x12\x11\n\trss_bytes\x18\x01 \x01(\x04\x12\x16\n\x0epeak_rss_bytes\x18\x02 \x01(\x04\x12\x0e\n\x06\x63pu_us\x18\x03 \x01(\x04\"\xc8\x01\n\x10LinkMetricsStats\x12\x0b\n\x03src\x18\x01 \x01(\x05\x12\x0b\n\x03\x64st\x18\x02 \x01(\x05\x12\x0f\n\x07samples\x18\x03 \x01(\x05\x12+\n\x03lqi\x18\x04 \x01(\x0b\x32\x1e.visualize_grpc_pb.MetricStats\x12.\n\x06margin\x18\x05 \x01(\x0b\x32\x1e.visualize_grpc_pb.MetricStats\x12,\n\x04rssi\x18\x06 \x01(\x0b\x32\x1e.visualize_grpc_pb.MetricStats\"B\n\x0bMetricStats\x12\x0b\n\x03min\x18\x01 \x01(\x05\x12\x0b\n\x03max\x18\x02 \x01(\x05\x12\x0b\n\x03\x61vg\x18\x03 \x01(\x01\x12\x0c\n\x04last\x18\x04 \x01(\x05\"`\n\nMacTxStats\x12\x0e\n\x06\x66rames\x18\x01 \x01(\x04\x12\x0f\n\x07retries\x18\x02 \x03(\x04\x12\x31\n\x07\x62\x61\x63koff\x18\x03 \x01(\x0b\x32 .visualize_grpc_pb.DurationStats\"R\n\rDurationStats\x12\r\n\x05\x63ount\x18\x01 \x01(\x05\x12\x0b\n\x03p50\x18\x02 \x01(\x04\x12\x0b\n\x03p90\x18\x03 \x01(\x04\x12\x0b\n\x03p99\x18\x04 \x01(\x04\x12\x0b\n\x03max\x18\x05 \x01(\x04\"\"\n\x0eSaveKpiRequest\x12\x10\n\x08\x66ilename\x18\x01 \x01(\t\"1\n\x0cWatchRequest\x12\x10\n\x08node_ids\x18\x01 \x03(\x05\x12\x0f\n\x07unwatch\x18\x02 \x01(\x08\"W\n\x12NodeHistoryRequest\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08start_us\x18\x02 \x01(\x04\x12\x13\n\x06\x65nd_us\x18\x03 \x01(\x04H\x00\x88\x01\x01\x42\t\n\x07_end_us\";\n\x0bNodeHistory\x12,\n\x06states\x18\x01 \x03(\x0b\x32\x1c.visualize_grpc_pb.NodeState\"\x92\x01\n\tNodeState\x12\x0f\n\x07time_us\x18\x01 \x01(\x04\x12-\n\x04role\x18\x02 \x01(\x0e\x32\x1f.visualize_grpc_pb.OtDeviceRole\x12\x0e\n\x06rloc16\x18\x03 \x01(\r\x12\x0e\n\x06parent\x18\x04 \x01(\x04\x12\x14\n\x0cpartition_id\x18\x05 \x01(\r\x12\x0f\n\x07\x64\x65leted\x18\x06 \x01(\x08\"!\n\x0eNodeGeoRequest\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"E\n\x0bGeoPosition\x12\x0f\n\x07\x65nabled\x18\x01 \x01(\x08\x12\x0b\n\x03lat\x18\x02 \x01(\x01\x12\x0b\n\x03lon\x18\x03 \x01(\x01\x12\x0b\n\x03\x61lt\x18\x04 \x01(\x01*\x98\x01\n\x0cOtDeviceRole\x12\x1b\n\x17OT_DEVICE_ROLE_DISABLED\x10\x00\x12\x1b\n\x17OT_DEVICE_ROLE_DETACHED\x10\x01\x12\x18\n\x14OT_DEVICE_ROLE_CHILD\x10\x02\x12\x19\n\x15OT_DEVICE_ROLE_ROUTER\x10\x03\x12\x19\n\x15OT_DEVICE_ROLE_LEADER\x10\x04\x32\xbf\x01\n\x14VisualizeGrpcService\x12U\n\tVisualize\x12#.visualize_grpc_pb.VisualizeRequest\x1a!.visualize_grpc_pb.VisualizeEvent0\x01\x12P\n\x07\x43ommand\x12!.visualize_grpc_pb.CommandRequest\x1a\".visualize_grpc_pb.CommandResponse2\xe2\x05\n\x11SimulationService\x12\x44\n\x0bGetCounters\x12\x18.visualize_grpc_pb.Empty\x1a\x1b.visualize_grpc_pb.Counters\x12J\n\x0eGetRadioParams\x12\x18.visualize_grpc_pb.Empty\x1a\x1e.visualize_grpc_pb.RadioParams\x12J\n\x0eSetRadioParams\x12\x1e.visualize_grpc_pb.RadioParams\x1a\x18.visualize_grpc_pb.Empty\x12>\n\x08StartKpi\x12\x18.visualize_grpc_pb.Empty\x1a\x18.visualize_grpc_pb.Empty\x12=\n\x07StopKpi\x12\x18.visualize_grpc_pb.Empty\x1a\x18.visualize_grpc_pb.Empty\x12:\n\x06GetKpi\x12\x18.visualize_grpc_pb.Empty\x1a\x16.visualize_grpc_pb.Kpi\x12\x46\n\x07SaveKpi\x12!.visualize_grpc_pb.SaveKpiRequest\x1a\x18.visualize_grpc_pb.Empty\x12\x42\n\x05Watch\x12\x1f.visualize_grpc_pb.WatchRequest\x1a\x18.visualize_grpc_pb.Empty\x12W\n\x0eGetNodeHistory\x12%.visualize_grpc_pb.NodeHistoryRequest\x1a\x1e.visualize_grpc_pb.NodeHistory\x12O\n\nGetNodeGeo\x12!.visualize_grpc_pb.NodeGeoRequest\x1a\x1e.visualize_grpc_pb.GeoPositionB/Z-github.com/openthread/ot-ns/visualize/grpc/pbb\x06proto3')
 
 _OTDEVICEROLE = DESCRIPTOR.enum_types_by_name['OtDeviceRole']
 OtDeviceRole = enum_type_wrapper.EnumTypeWrapper(_OTDEVICEROLE)
@@ -57,6 +57,31 @@ _COMMANDREQUEST = DESCRIPTOR.message_types_by_name['CommandRequest']
 _COMMANDRESPONSE = DESCRIPTOR.message_types_by_name['CommandResponse']
 _REPLAYENTRY = DESCRIPTOR.message_types_by_name['ReplayEntry']
 _EMPTY = DESCRIPTOR.message_types_by_name['Empty']
+_COUNTERS = DESCRIPTOR.message_types_by_name['Counters']
+_COUNTERS_COUNTERSENTRY = _COUNTERS.nested_types_by_name['CountersEntry']
+_RADIOPARAMS = DESCRIPTOR.message_types_by_name['RadioParams']
+_RADIOPARAMS_CHANNELNOISEFLOORDBMENTRY = _RADIOPARAMS.nested_types_by_name['ChannelNoiseFloorDbmEntry']
+_KPI = DESCRIPTOR.message_types_by_name['Kpi']
+_KPI_COUNTERSENTRY = _KPI.nested_types_by_name['CountersEntry']
+_KPI_MACENTRY = _KPI.nested_types_by_name['MacEntry']
+_KPI_RESOURCESENTRY = _KPI.nested_types_by_name['ResourcesEntry']
+_KPI_MACTXENTRY = _KPI.nested_types_by_name['MacTxEntry']
+_AIRTIMEREPORT = DESCRIPTOR.message_types_by_name['AirtimeReport']
+_AIRTIMESTAT = DESCRIPTOR.message_types_by_name['AirtimeStat']
+_MACSTATS = DESCRIPTOR.message_types_by_name['MacStats']
+_MACSTATS_DROPSENTRY = _MACSTATS.nested_types_by_name['DropsEntry']
+_RESOURCEUSAGE = DESCRIPTOR.message_types_by_name['ResourceUsage']
+_LINKMETRICSSTATS = DESCRIPTOR.message_types_by_name['LinkMetricsStats']
+_METRICSTATS = DESCRIPTOR.message_types_by_name['MetricStats']
+_MACTXSTATS = DESCRIPTOR.message_types_by_name['MacTxStats']
+_DURATIONSTATS = DESCRIPTOR.message_types_by_name['DurationStats']
+_SAVEKPIREQUEST = DESCRIPTOR.message_types_by_name['SaveKpiRequest']
+_WATCHREQUEST = DESCRIPTOR.message_types_by_name['WatchRequest']
+_NODEHISTORYREQUEST = DESCRIPTOR.message_types_by_name['NodeHistoryRequest']
+_NODEHISTORY = DESCRIPTOR.message_types_by_name['NodeHistory']
+_NODESTATE = DESCRIPTOR.message_types_by_name['NodeState']
+_NODEGEOREQUEST = DESCRIPTOR.message_types_by_name['NodeGeoRequest']
+_GEOPOSITION = DESCRIPTOR.message_types_by_name['GeoPosition']
 VisualizeRequest = _reflection.GeneratedProtocolMessageType('VisualizeRequest', (_message.Message,), {
   'DESCRIPTOR' : _VISUALIZEREQUEST,
   '__module__' : 'visualize_grpc_pb2'
@@ -274,13 +299,210 @@ Empty = _reflection.GeneratedProtocolMessageType('Empty', (_message.Message,), {
   })
 _sym_db.RegisterMessage(Empty)
 
+Counters = _reflection.GeneratedProtocolMessageType('Counters', (_message.Message,), {
+
+  'CountersEntry' : _reflection.GeneratedProtocolMessageType('CountersEntry', (_message.Message,), {
+    'DESCRIPTOR' : _COUNTERS_COUNTERSENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Counters.CountersEntry)
+    })
+  ,
+  'DESCRIPTOR' : _COUNTERS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Counters)
+  })
+_sym_db.RegisterMessage(Counters)
+_sym_db.RegisterMessage(Counters.CountersEntry)
+
+RadioParams = _reflection.GeneratedProtocolMessageType('RadioParams', (_message.Message,), {
+
+  'ChannelNoiseFloorDbmEntry' : _reflection.GeneratedProtocolMessageType('ChannelNoiseFloorDbmEntry', (_message.Message,), {
+    'DESCRIPTOR' : _RADIOPARAMS_CHANNELNOISEFLOORDBMENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.RadioParams.ChannelNoiseFloorDbmEntry)
+    })
+  ,
+  'DESCRIPTOR' : _RADIOPARAMS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.RadioParams)
+  })
+_sym_db.RegisterMessage(RadioParams)
+_sym_db.RegisterMessage(RadioParams.ChannelNoiseFloorDbmEntry)
+
+Kpi = _reflection.GeneratedProtocolMessageType('Kpi', (_message.Message,), {
+
+  'CountersEntry' : _reflection.GeneratedProtocolMessageType('CountersEntry', (_message.Message,), {
+    'DESCRIPTOR' : _KPI_COUNTERSENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Kpi.CountersEntry)
+    })
+  ,
+
+  'MacEntry' : _reflection.GeneratedProtocolMessageType('MacEntry', (_message.Message,), {
+    'DESCRIPTOR' : _KPI_MACENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Kpi.MacEntry)
+    })
+  ,
+
+  'ResourcesEntry' : _reflection.GeneratedProtocolMessageType('ResourcesEntry', (_message.Message,), {
+    'DESCRIPTOR' : _KPI_RESOURCESENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Kpi.ResourcesEntry)
+    })
+  ,
+
+  'MacTxEntry' : _reflection.GeneratedProtocolMessageType('MacTxEntry', (_message.Message,), {
+    'DESCRIPTOR' : _KPI_MACTXENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Kpi.MacTxEntry)
+    })
+  ,
+  'DESCRIPTOR' : _KPI,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.Kpi)
+  })
+_sym_db.RegisterMessage(Kpi)
+_sym_db.RegisterMessage(Kpi.CountersEntry)
+_sym_db.RegisterMessage(Kpi.MacEntry)
+_sym_db.RegisterMessage(Kpi.ResourcesEntry)
+_sym_db.RegisterMessage(Kpi.MacTxEntry)
+
+AirtimeReport = _reflection.GeneratedProtocolMessageType('AirtimeReport', (_message.Message,), {
+  'DESCRIPTOR' : _AIRTIMEREPORT,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.AirtimeReport)
+  })
+_sym_db.RegisterMessage(AirtimeReport)
+
+AirtimeStat = _reflection.GeneratedProtocolMessageType('AirtimeStat', (_message.Message,), {
+  'DESCRIPTOR' : _AIRTIMESTAT,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.AirtimeStat)
+  })
+_sym_db.RegisterMessage(AirtimeStat)
+
+MacStats = _reflection.GeneratedProtocolMessageType('MacStats', (_message.Message,), {
+
+  'DropsEntry' : _reflection.GeneratedProtocolMessageType('DropsEntry', (_message.Message,), {
+    'DESCRIPTOR' : _MACSTATS_DROPSENTRY,
+    '__module__' : 'visualize_grpc_pb2'
+    # @@protoc_insertion_point(class_scope:visualize_grpc_pb.MacStats.DropsEntry)
+    })
+  ,
+  'DESCRIPTOR' : _MACSTATS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.MacStats)
+  })
+_sym_db.RegisterMessage(MacStats)
+_sym_db.RegisterMessage(MacStats.DropsEntry)
+
+ResourceUsage = _reflection.GeneratedProtocolMessageType('ResourceUsage', (_message.Message,), {
+  'DESCRIPTOR' : _RESOURCEUSAGE,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.ResourceUsage)
+  })
+_sym_db.RegisterMessage(ResourceUsage)
+
+LinkMetricsStats = _reflection.GeneratedProtocolMessageType('LinkMetricsStats', (_message.Message,), {
+  'DESCRIPTOR' : _LINKMETRICSSTATS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.LinkMetricsStats)
+  })
+_sym_db.RegisterMessage(LinkMetricsStats)
+
+MetricStats = _reflection.GeneratedProtocolMessageType('MetricStats', (_message.Message,), {
+  'DESCRIPTOR' : _METRICSTATS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.MetricStats)
+  })
+_sym_db.RegisterMessage(MetricStats)
+
+MacTxStats = _reflection.GeneratedProtocolMessageType('MacTxStats', (_message.Message,), {
+  'DESCRIPTOR' : _MACTXSTATS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.MacTxStats)
+  })
+_sym_db.RegisterMessage(MacTxStats)
+
+DurationStats = _reflection.GeneratedProtocolMessageType('DurationStats', (_message.Message,), {
+  'DESCRIPTOR' : _DURATIONSTATS,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.DurationStats)
+  })
+_sym_db.RegisterMessage(DurationStats)
+
+SaveKpiRequest = _reflection.GeneratedProtocolMessageType('SaveKpiRequest', (_message.Message,), {
+  'DESCRIPTOR' : _SAVEKPIREQUEST,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.SaveKpiRequest)
+  })
+_sym_db.RegisterMessage(SaveKpiRequest)
+
+WatchRequest = _reflection.GeneratedProtocolMessageType('WatchRequest', (_message.Message,), {
+  'DESCRIPTOR' : _WATCHREQUEST,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.WatchRequest)
+  })
+_sym_db.RegisterMessage(WatchRequest)
+
+NodeHistoryRequest = _reflection.GeneratedProtocolMessageType('NodeHistoryRequest', (_message.Message,), {
+  'DESCRIPTOR' : _NODEHISTORYREQUEST,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.NodeHistoryRequest)
+  })
+_sym_db.RegisterMessage(NodeHistoryRequest)
+
+NodeHistory = _reflection.GeneratedProtocolMessageType('NodeHistory', (_message.Message,), {
+  'DESCRIPTOR' : _NODEHISTORY,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.NodeHistory)
+  })
+_sym_db.RegisterMessage(NodeHistory)
+
+NodeState = _reflection.GeneratedProtocolMessageType('NodeState', (_message.Message,), {
+  'DESCRIPTOR' : _NODESTATE,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.NodeState)
+  })
+_sym_db.RegisterMessage(NodeState)
+
+NodeGeoRequest = _reflection.GeneratedProtocolMessageType('NodeGeoRequest', (_message.Message,), {
+  'DESCRIPTOR' : _NODEGEOREQUEST,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.NodeGeoRequest)
+  })
+_sym_db.RegisterMessage(NodeGeoRequest)
+
+GeoPosition = _reflection.GeneratedProtocolMessageType('GeoPosition', (_message.Message,), {
+  'DESCRIPTOR' : _GEOPOSITION,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.GeoPosition)
+  })
+_sym_db.RegisterMessage(GeoPosition)
+
 _VISUALIZEGRPCSERVICE = DESCRIPTOR.services_by_name['VisualizeGrpcService']
+_SIMULATIONSERVICE = DESCRIPTOR.services_by_name['SimulationService']
 if _descriptor._USE_C_DESCRIPTORS == False:
 
   DESCRIPTOR._options = None
   DESCRIPTOR._serialized_options = b'Z-github.com/openthread/ot-ns/visualize/grpc/pb'
-  _OTDEVICEROLE._serialized_start=3274
-  _OTDEVICEROLE._serialized_end=3426
+  _COUNTERS_COUNTERSENTRY._options = None
+  _COUNTERS_COUNTERSENTRY._serialized_options = b'8\001'
+  _RADIOPARAMS_CHANNELNOISEFLOORDBMENTRY._options = None
+  _RADIOPARAMS_CHANNELNOISEFLOORDBMENTRY._serialized_options = b'8\001'
+  _KPI_COUNTERSENTRY._options = None
+  _KPI_COUNTERSENTRY._serialized_options = b'8\001'
+  _KPI_MACENTRY._options = None
+  _KPI_MACENTRY._serialized_options = b'8\001'
+  _KPI_RESOURCESENTRY._options = None
+  _KPI_RESOURCESENTRY._serialized_options = b'8\001'
+  _KPI_MACTXENTRY._options = None
+  _KPI_MACTXENTRY._serialized_options = b'8\001'
+  _MACSTATS_DROPSENTRY._options = None
+  _MACSTATS_DROPSENTRY._serialized_options = b'8\001'
+  _OTDEVICEROLE._serialized_start=5989
+  _OTDEVICEROLE._serialized_end=6141
   _VISUALIZEREQUEST._serialized_start=43
   _VISUALIZEREQUEST._serialized_end=61
   _VISUALIZEEVENT._serialized_start=64
@@ -343,6 +565,58 @@ if _descriptor._USE_C_DESCRIPTORS == False:
   _REPLAYENTRY._serialized_end=3262
   _EMPTY._serialized_start=3264
   _EMPTY._serialized_end=3271
-  _VISUALIZEGRPCSERVICE._serialized_start=3429
-  _VISUALIZEGRPCSERVICE._serialized_end=3620
+  _COUNTERS._serialized_start=3273
+  _COUNTERS._serialized_end=3393
+  _COUNTERS_COUNTERSENTRY._serialized_start=3346
+  _COUNTERS_COUNTERSENTRY._serialized_end=3393
+  _RADIOPARAMS._serialized_start=3396
+  _RADIOPARAMS._serialized_end=3880
+  _RADIOPARAMS_CHANNELNOISEFLOORDBMENTRY._serialized_start=3717
+  _RADIOPARAMS_CHANNELNOISEFLOORDBMENTRY._serialized_end=3776
+  _KPI._serialized_start=3883
+  _KPI._serialized_end=4544
+  _KPI_COUNTERSENTRY._serialized_start=4263
+  _KPI_COUNTERSENTRY._serialized_end=4310
+  _KPI_MACENTRY._serialized_start=4312
+  _KPI_MACENTRY._serialized_end=4383
+  _KPI_RESOURCESENTRY._serialized_start=4385
+  _KPI_RESOURCESENTRY._serialized_end=4467
+  _KPI_MACTXENTRY._serialized_start=4469
+  _KPI_MACTXENTRY._serialized_end=4544
+  _AIRTIMEREPORT._serialized_start=4547
+  _AIRTIMEREPORT._serialized_end=4709
+  _AIRTIMESTAT._serialized_start=4711
+  _AIRTIMESTAT._serialized_end=4821
+  _MACSTATS._serialized_start=4824
+  _MACSTATS._serialized_end=4965
+  _MACSTATS_DROPSENTRY._serialized_start=4921
+  _MACSTATS_DROPSENTRY._serialized_end=4965
+  _RESOURCEUSAGE._serialized_start=4967
+  _RESOURCEUSAGE._serialized_end=5041
+  _LINKMETRICSSTATS._serialized_start=5044
+  _LINKMETRICSSTATS._serialized_end=5244
+  _METRICSTATS._serialized_start=5246
+  _METRICSTATS._serialized_end=5312
+  _MACTXSTATS._serialized_start=5314
+  _MACTXSTATS._serialized_end=5410
+  _DURATIONSTATS._serialized_start=5412
+  _DURATIONSTATS._serialized_end=5494
+  _SAVEKPIREQUEST._serialized_start=5496
+  _SAVEKPIREQUEST._serialized_end=5530
+  _WATCHREQUEST._serialized_start=5532
+  _WATCHREQUEST._serialized_end=5581
+  _NODEHISTORYREQUEST._serialized_start=5583
+  _NODEHISTORYREQUEST._serialized_end=5670
+  _NODEHISTORY._serialized_start=5672
+  _NODEHISTORY._serialized_end=5731
+  _NODESTATE._serialized_start=5734
+  _NODESTATE._serialized_end=5880
+  _NODEGEOREQUEST._serialized_start=5882
+  _NODEGEOREQUEST._serialized_end=5915
+  _GEOPOSITION._serialized_start=5917
+  _GEOPOSITION._serialized_end=5986
+  _VISUALIZEGRPCSERVICE._serialized_start=6144
+  _VISUALIZEGRPCSERVICE._serialized_end=6335
+  _SIMULATIONSERVICE._serialized_start=6338
+  _SIMULATIONSERVICE._serialized_end=7076
 # @@protoc_insertion_point(module_scope)
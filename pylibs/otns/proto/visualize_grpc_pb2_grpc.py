@@ -98,3 +98,369 @@ class VisualizeGrpcService(object):
             visualize__grpc__pb2.CommandResponse.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+
+class SimulationServiceStub(object):
+    """SimulationService controls the simulation with structured data.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.GetCounters = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/GetCounters',
+                request_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Counters.FromString,
+                )
+        self.GetRadioParams = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/GetRadioParams',
+                request_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.RadioParams.FromString,
+                )
+        self.SetRadioParams = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/SetRadioParams',
+                request_serializer=visualize__grpc__pb2.RadioParams.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Empty.FromString,
+                )
+        self.StartKpi = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/StartKpi',
+                request_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Empty.FromString,
+                )
+        self.StopKpi = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/StopKpi',
+                request_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Empty.FromString,
+                )
+        self.GetKpi = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/GetKpi',
+                request_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Kpi.FromString,
+                )
+        self.SaveKpi = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/SaveKpi',
+                request_serializer=visualize__grpc__pb2.SaveKpiRequest.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Empty.FromString,
+                )
+        self.Watch = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/Watch',
+                request_serializer=visualize__grpc__pb2.WatchRequest.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.Empty.FromString,
+                )
+        self.GetNodeHistory = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/GetNodeHistory',
+                request_serializer=visualize__grpc__pb2.NodeHistoryRequest.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.NodeHistory.FromString,
+                )
+        self.GetNodeGeo = channel.unary_unary(
+                '/visualize_grpc_pb.SimulationService/GetNodeGeo',
+                request_serializer=visualize__grpc__pb2.NodeGeoRequest.SerializeToString,
+                response_deserializer=visualize__grpc__pb2.GeoPosition.FromString,
+                )
+
+
+class SimulationServiceServicer(object):
+    """SimulationService controls the simulation with structured data.
+    """
+
+    def GetCounters(self, request, context):
+        """GetCounters gets the dispatcher counters.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetRadioParams(self, request, context):
+        """GetRadioParams gets the radio model parameters and the radio models which can be selected.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def SetRadioParams(self, request, context):
+        """SetRadioParams updates the radio model parameters which are set.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def StartKpi(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def StopKpi(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetKpi(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def SaveKpi(self, request, context):
+        """SaveKpi saves the KPI to the file on the OTNS host.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Watch(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetNodeHistory(self, request, context):
+        """GetNodeHistory gets the state of the node at start_us followed by its state transitions until end_us.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetNodeGeo(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_SimulationServiceServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'GetCounters': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetCounters,
+                    request_deserializer=visualize__grpc__pb2.Empty.FromString,
+                    response_serializer=visualize__grpc__pb2.Counters.SerializeToString,
+            ),
+            'GetRadioParams': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetRadioParams,
+                    request_deserializer=visualize__grpc__pb2.Empty.FromString,
+                    response_serializer=visualize__grpc__pb2.RadioParams.SerializeToString,
+            ),
+            'SetRadioParams': grpc.unary_unary_rpc_method_handler(
+                    servicer.SetRadioParams,
+                    request_deserializer=visualize__grpc__pb2.RadioParams.FromString,
+                    response_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+            ),
+            'StartKpi': grpc.unary_unary_rpc_method_handler(
+                    servicer.StartKpi,
+                    request_deserializer=visualize__grpc__pb2.Empty.FromString,
+                    response_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+            ),
+            'StopKpi': grpc.unary_unary_rpc_method_handler(
+                    servicer.StopKpi,
+                    request_deserializer=visualize__grpc__pb2.Empty.FromString,
+                    response_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+            ),
+            'GetKpi': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetKpi,
+                    request_deserializer=visualize__grpc__pb2.Empty.FromString,
+                    response_serializer=visualize__grpc__pb2.Kpi.SerializeToString,
+            ),
+            'SaveKpi': grpc.unary_unary_rpc_method_handler(
+                    servicer.SaveKpi,
+                    request_deserializer=visualize__grpc__pb2.SaveKpiRequest.FromString,
+                    response_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+            ),
+            'Watch': grpc.unary_unary_rpc_method_handler(
+                    servicer.Watch,
+                    request_deserializer=visualize__grpc__pb2.WatchRequest.FromString,
+                    response_serializer=visualize__grpc__pb2.Empty.SerializeToString,
+            ),
+            'GetNodeHistory': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetNodeHistory,
+                    request_deserializer=visualize__grpc__pb2.NodeHistoryRequest.FromString,
+                    response_serializer=visualize__grpc__pb2.NodeHistory.SerializeToString,
+            ),
+            'GetNodeGeo': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetNodeGeo,
+                    request_deserializer=visualize__grpc__pb2.NodeGeoRequest.FromString,
+                    response_serializer=visualize__grpc__pb2.GeoPosition.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'visualize_grpc_pb.SimulationService', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+
+
+ # This class is part of an EXPERIMENTAL API.
+class SimulationService(object):
+    """SimulationService controls the simulation with structured data.
+    """
+
+    @staticmethod
+    def GetCounters(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/GetCounters',
+            visualize__grpc__pb2.Empty.SerializeToString,
+            visualize__grpc__pb2.Counters.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetRadioParams(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/GetRadioParams',
+            visualize__grpc__pb2.Empty.SerializeToString,
+            visualize__grpc__pb2.RadioParams.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def SetRadioParams(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/SetRadioParams',
+            visualize__grpc__pb2.RadioParams.SerializeToString,
+            visualize__grpc__pb2.Empty.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def StartKpi(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/StartKpi',
+            visualize__grpc__pb2.Empty.SerializeToString,
+            visualize__grpc__pb2.Empty.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def StopKpi(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/StopKpi',
+            visualize__grpc__pb2.Empty.SerializeToString,
+            visualize__grpc__pb2.Empty.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetKpi(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/GetKpi',
+            visualize__grpc__pb2.Empty.SerializeToString,
+            visualize__grpc__pb2.Kpi.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def SaveKpi(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/SaveKpi',
+            visualize__grpc__pb2.SaveKpiRequest.SerializeToString,
+            visualize__grpc__pb2.Empty.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Watch(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/Watch',
+            visualize__grpc__pb2.WatchRequest.SerializeToString,
+            visualize__grpc__pb2.Empty.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetNodeHistory(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/GetNodeHistory',
+            visualize__grpc__pb2.NodeHistoryRequest.SerializeToString,
+            visualize__grpc__pb2.NodeHistory.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetNodeGeo(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/visualize_grpc_pb.SimulationService/GetNodeGeo',
+            visualize__grpc__pb2.NodeGeoRequest.SerializeToString,
+            visualize__grpc__pb2.GeoPosition.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -41,4 +41,5 @@ setuptools.setup(
     ],
     python_requires='>=3.7',
     install_requires=['PyYAML'],
+    extras_require={'grpc': ['grpcio', 'protobuf']},
 )
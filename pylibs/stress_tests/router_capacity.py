@@ -0,0 +1,147 @@
+#!/usr/bin/env python3
+#
+# Copyright (c) 2022, The OTNS Authors.
+# All rights reserved.
+#
+# Redistribution and use in source and binary forms, with or without
+# modification, are permitted provided that the following conditions are met:
+# 1. Redistributions of source code must retain the above copyright
+#    notice, this list of conditions and the following disclaimer.
+# 2. Redistributions in binary form must reproduce the above copyright
+#    notice, this list of conditions and the following disclaimer in the
+#    documentation and/or other materials provided with the distribution.
+# 3. Neither the name of the copyright holder nor the
+#    names of its contributors may be used to endorse or promote products
+#    derived from this software without specific prior written permission.
+#
+# THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+# AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+# IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+# ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+# LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+# CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+# SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+# INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+# CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+# ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+# POSSIBILITY OF SUCH DAMAGE.
+#
+import logging
+import math
+import random
+from typing import Dict, List, Tuple
+
+from BaseStressTest import BaseStressTest
+
+CENTER_X = 500
+CENTER_Y = 500
+MAX_DISTANCE = 200
+RADIO_RANGE = int(MAX_DISTANCE * 2.5)
+
+MAX_ROUTERS = 32
+MAX_NODES = 48
+ADD_INTERVAL = 30
+STALL_ADDS = 6
+SETTLE_TIME = 300
+
+# the default upgrade threshold, and a threshold above the router limit to make the leader run out of router IDs
+UPGRADE_THRESHOLDS = [16, 63]
+
+
+class StressTest(BaseStressTest):
+    SUITE = 'network-limits'
+
+    def __init__(self):
+        super(StressTest, self).__init__("Router Capacity and REED Promotion", [
+            "Upgrade Threshold", "Nodes", "Max Routers", "Cap Reached At", "Promotions", "Demotions",
+            "Failed Promotions"
+        ])
+
+    def run(self):
+        for threshold in UPGRADE_THRESHOLDS:
+            self.test(threshold)
+
+    def test(self, threshold: int):
+        self.reset()
+        self.ns.roles_reset()
+
+        # router counts over time, each of format (simulation time, nodes, routers)
+        timeline: List[Tuple[float, int, int]] = []
+        now = 0
+        max_routers = 0
+        cap_time = None
+        stalled = 0
+
+        for i in range(MAX_NODES):
+            angle = random.uniform(0, math.pi * 2)
+            d = random.randint(0, MAX_DISTANCE * MAX_DISTANCE)**0.5
+            nid = self.ns.add("router", int(CENTER_X + d * math.cos(angle)), int(CENTER_Y + d * math.sin(angle)),
+                              radio_range=RADIO_RANGE)
+            self.ns.node_cmd(nid, f'routerupgradethreshold {threshold}')
+            self.ns.node_cmd(nid, 'routerselectionjitter 1')
+            self.ns.go(ADD_INTERVAL)
+            now += ADD_INTERVAL
+
+            routers = self._count_routers()
+            timeline.append((now, i + 1, routers))
+            logging.info("time %ds: %d nodes, %d routers", now, i + 1, routers)
+
+            if routers > max_routers:
+                max_routers, cap_time, stalled = routers, now, 0
+            else:
+                stalled += 1
+                if stalled >= STALL_ADDS:
+                    break
+
+        self.ns.go(SETTLE_TIME)
+        failed = self._failed_promotions(threshold)
+        promotions, demotions = self._count_promotions()
+
+        self.result.fail_if(max_routers > MAX_ROUTERS, f"{max_routers} routers exceed the limit of {MAX_ROUTERS}")
+        self.result.fail_if(max_routers < min(threshold, MAX_ROUTERS) - 1,
+                            f"only {max_routers} routers with upgrade threshold {threshold}")
+        self.result.append_row(threshold, len(timeline), max_routers, f'{cap_time}s', promotions, demotions,
+                               ', '.join(f'{n} ({reason})' for reason, n in failed.items()) or '-')
+
+    def _count_routers(self) -> int:
+        return sum(1 for info in self.ns.nodes().values() if info['state'] in ('router', 'leader'))
+
+    def _count_promotions(self) -> Tuple[int, int]:
+        routers = ('router', 'leader')
+        promotions, demotions = 0, 0
+        for _, _, old, new in self.ns.roles():
+            if old not in routers and new in routers:
+                promotions += 1
+            elif old in routers and new not in routers:
+                demotions += 1
+        return promotions, demotions
+
+    def _failed_promotions(self, threshold: int) -> Dict[str, int]:
+        """
+        Count the router eligible children which are not promoted, by reason.
+
+        REEDs only request a router ID if the partition has less routers than the upgrade threshold, and the leader
+        rejects the request if all router IDs that can be allocated are in use.
+        """
+        nodes = self.ns.nodes()
+        leader = next((nid for nid, info in nodes.items() if info['state'] == 'leader'), None)
+        if leader is None:
+            return {'no leader': sum(1 for info in nodes.values() if info['state'] == 'child')}
+
+        allocated = len(self.ns.node_cmd(leader, 'router list')[0].split())
+        if allocated >= threshold:
+            reason = f'upgrade threshold {threshold} reached'
+        elif allocated >= MAX_ROUTERS:
+            reason = f'router IDs exhausted with {allocated} routers'
+        else:
+            reason = f'not promoted with {allocated} routers'
+
+        failed = {}
+        for info in nodes.values():
+            if info['state'] == 'child':
+                failed[reason] = failed.get(reason, 0) + 1
+        return failed
+
+
+if __name__ == '__main__':
+    StressTest().run()
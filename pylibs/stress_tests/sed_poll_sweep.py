@@ -0,0 +1,140 @@
+#!/usr/bin/env python3
+#
+# Copyright (c) 2022, The OTNS Authors.
+# All rights reserved.
+#
+# Redistribution and use in source and binary forms, with or without
+# modification, are permitted provided that the following conditions are met:
+# 1. Redistributions of source code must retain the above copyright
+#    notice, this list of conditions and the following disclaimer.
+# 2. Redistributions in binary form must reproduce the above copyright
+#    notice, this list of conditions and the following disclaimer in the
+#    documentation and/or other materials provided with the distribution.
+# 3. Neither the name of the copyright holder nor the
+#    names of its contributors may be used to endorse or promote products
+#    derived from this software without specific prior written permission.
+#
+# THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+# AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+# IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+# ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+# LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+# CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+# SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+# INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+# CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+# ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+# POSSIBILITY OF SUCH DAMAGE.
+#
+#
+# SED Poll Period Sweep:
+#   Measure the trade-off between the downlink latency and the radio activity of SEDs for different poll periods
+# Topology:
+#   1 Router (leader)
+#   6 SEDs around the leader
+# Fault Injections:
+#   None
+# Pass Criteria:
+#   Max ping latency of each poll period < poll period + 1s
+#   SEDs transmit less frames with longer poll periods
+#
+import logging
+import math
+import random
+from typing import Dict, List
+
+from BaseStressTest import BaseStressTest
+
+CENTER_X = 500
+CENTER_Y = 500
+DISTANCE = 100
+SED_COUNT = 6
+
+# poll periods to sweep, in seconds
+POLL_PERIODS = [0.5, 1, 2, 5, 10]
+ATTACH_TIMEOUT = 60
+PING_ROUNDS = 10
+PING_DATASIZE = 32
+LATENCY_SLACK = 1000  # ms
+
+
+class StressTest(BaseStressTest):
+    SUITE = 'network-latency'
+
+    def __init__(self):
+        super(StressTest, self).__init__("SED Poll Period Sweep", [
+            "Poll Period", "Avg Ping Latency", "Max Ping Latency", "SED TX Frames/min", "SED TX Airtime/min"
+        ])
+        self._frames_per_min: List[float] = []
+
+    def run(self):
+        for period in POLL_PERIODS:
+            self.test(period)
+
+        # radio activity (a proxy of energy consumption) should drop with longer poll periods
+        for i in range(1, len(self._frames_per_min)):
+            self.result.fail_if(self._frames_per_min[i] >= self._frames_per_min[i - 1],
+                                f"SEDs do not transmit less with poll period {POLL_PERIODS[i]}s")
+
+    def test(self, period: float):
+        self.reset()
+        ns = self.ns
+
+        leader = ns.add("router", CENTER_X, CENTER_Y)
+        self.expect_node_state(leader, 'leader', 10)
+
+        seds = []
+        for i in range(SED_COUNT):
+            angle = math.pi * 2 * i / SED_COUNT
+            seds.append(
+                ns.add("sed",
+                       int(CENTER_X + DISTANCE * math.cos(angle)),
+                       int(CENTER_Y + DISTANCE * math.sin(angle)),
+                       poll_period=period))
+        for sed in seds:
+            self.expect_node_state(sed, 'child', ATTACH_TIMEOUT)
+
+        ns.pings()  # throw away previous ping results
+        ns.kpi_start()
+        duration = 0
+        for _ in range(PING_ROUNDS):
+            for sed in seds:
+                ns.ping(leader, sed, addrtype='rloc', datasize=PING_DATASIZE)
+            # ping at a random phase of the poll period
+            wait = period + LATENCY_SLACK / 1000 + random.uniform(0, period)
+            ns.go(wait)
+            duration += wait
+        ns.kpi_stop()
+
+        latencies = [delay for srcid, _, _, delay in ns.pings() if srcid == leader]
+        self.result.fail_if(len(latencies) < PING_ROUNDS * SED_COUNT / 2,
+                            f"only {len(latencies)} pings replied with poll period {period}s")
+        avg_latency = self.avg(latencies) if latencies else 0
+        max_latency = max(latencies) if latencies else 0
+        self.result.fail_if(max_latency > period * 1000 + LATENCY_SLACK,
+                            f"max ping latency {max_latency}ms exceeds poll period {period}s")
+
+        frames, airtime = self._sed_tx(ns.kpi(), seds)
+        minutes = duration / 60
+        self._frames_per_min.append(frames / minutes)
+        logging.info("poll period %gs: latency avg %dms max %dms, SED TX frames %d airtime %dus", period, avg_latency,
+                     max_latency, frames, airtime)
+
+        self.result.append_row(f'{period}s', f'{avg_latency:.0f}ms', f'{max_latency:.0f}ms',
+                               f'{frames / minutes / SED_COUNT:.1f}', f'{airtime / minutes / SED_COUNT / 1000:.2f}ms')
+
+    @staticmethod
+    def _sed_tx(kpi: Dict, seds: List[int]):
+        """
+        Sum the frames and airtime (in us) transmitted by the SEDs while KPI was collected.
+        """
+        frames, airtime = 0, 0
+        for stat in kpi['airtime']['nodes']:
+            if stat['node'] in seds:
+                frames += stat['frames']
+                airtime += stat['airtime_us']
+        return frames, airtime
+
+
+if __name__ == '__main__':
+    StressTest().run()
@@ -249,6 +249,10 @@ class BasicTests(OTNSTestCase):
         for opt in ('broadcast_message', 'unicast_message', 'ack_message', 'router_table', 'child_table'):
             self.assertFalse(vopts[opt])
 
+        self.assertIsNone(vopts['nodes'])
+        self.assertEqual([1, 3, 4, 5], ns.config_visualization(nodes=[1, '3-5'])['nodes'])
+        self.assertIsNone(ns.config_visualization(nodes=[])['nodes'])
+
     def testWithOTNS(self):
         """
         make sure OTNS works in with-statement
@@ -0,0 +1,136 @@
+#!/usr/bin/env python3
+#
+# Copyright (c) 2020, The OTNS Authors.
+# All rights reserved.
+#
+# Redistribution and use in source and binary forms, with or without
+# modification, are permitted provided that the following conditions are met:
+# 1. Redistributions of source code must retain the above copyright
+#    notice, this list of conditions and the following disclaimer.
+# 2. Redistributions in binary form must reproduce the above copyright
+#    notice, this list of conditions and the following disclaimer in the
+#    documentation and/or other materials provided with the distribution.
+# 3. Neither the name of the copyright holder nor the
+#    names of its contributors may be used to endorse or promote products
+#    derived from this software without specific prior written permission.
+#
+# THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+# AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+# IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+# ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+# LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+# CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+# SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+# INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+# CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+# ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+# POSSIBILITY OF SUCH DAMAGE.
+#
+import sys
+import unittest
+from typing import Any, Dict, List, Optional
+from unittest import mock
+
+from otns.cli import OTNSComparison
+
+# the class OTNSComparison shadows its module in the package
+comparison_module = sys.modules['otns.cli.OTNSComparison']
+
+
+class FakeOTNS(object):
+    """
+    FakeOTNS records the commands of the comparison instead of running a simulation. The KPIs of a variant differ by
+    the number of its listen port.
+    """
+    MAX_SIMULATE_SPEED = 1000000
+
+    log: List[tuple] = []
+
+    def __init__(self, otns_path: Optional[str] = None, otns_args: Optional[List[str]] = None):
+        self.args = otns_args
+        self.port = int(otns_args[otns_args.index('-listen') + 1].split(':')[1])
+        self.speed = 1
+
+    def kpi_start(self) -> None:
+        FakeOTNS.log.append((self.port, 'kpi_start'))
+
+    def kpi_stop(self) -> None:
+        FakeOTNS.log.append((self.port, 'kpi_stop'))
+
+    def go(self, duration: float) -> None:
+        FakeOTNS.log.append((self.port, duration))
+
+    def kpi(self) -> Dict[str, Any]:
+        return {
+            'counters': {'RadioEvents': self.port // 100},
+            'airtime': {'airtime_us': 1000, 'util': 0.5, 'fairness': 1.0},
+        }
+
+    def summary(self) -> Dict[str, Any]:
+        return {'wall_time_us': self.port, 'nodes': 3, 'pings': {'timeouts': self.port % 10000 // 1000}}
+
+    def close(self) -> None:
+        pass
+
+
+class ComparisonTests(unittest.TestCase):
+
+    def setUp(self) -> None:
+        FakeOTNS.log = []
+        patcher = mock.patch.object(comparison_module, 'OTNS', FakeOTNS)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def testLockStep(self):
+        def scenario(ns):
+            yield 2.5
+            yield 1
+
+        with OTNSComparison({'a': [], 'b': ['-seed', '2']}, seed=7, step=1, first_port=20000) as comparison:
+            sims = comparison.simulations
+            self.assertEqual(['-listen', 'localhost:20000', '-seed', '7'], sims['a'].args)
+            self.assertEqual(['-listen', 'localhost:21000', '-seed', '7', '-seed', '2'], sims['b'].args)
+
+            comparison.run(scenario)
+            self.assertEqual(3.5, comparison.time)
+
+        # the variants advance in turn, one step at a time
+        self.assertEqual([
+            (20000, 'kpi_start'), (21000, 'kpi_start'),
+            (20000, 1), (21000, 1), (20000, 1), (21000, 1), (20000, 0.5), (21000, 0.5),
+            (20000, 1), (21000, 1),
+            (20000, 'kpi_stop'), (21000, 'kpi_stop'),
+        ], FakeOTNS.log)
+
+    def testNotInLockStep(self):
+        def scenario(ns):
+            yield 1
+            if ns.port == 20000:
+                yield 1
+
+        with OTNSComparison({'a': [], 'b': []}, first_port=20000) as comparison:
+            self.assertRaises(ValueError, comparison.run, scenario)
+
+    def testCompare(self):
+        with OTNSComparison({'a': [], 'b': []}, first_port=20000) as comparison:
+            report = comparison.compare()
+
+        self.assertEqual('a', report['baseline'])
+        metrics = report['metrics']
+        self.assertEqual({'a': {'value': 200, 'diff': 0}, 'b': {'value': 210, 'diff': 10}},
+                         metrics['counters.RadioEvents'])
+        self.assertEqual({'a': {'value': 0, 'diff': 0}, 'b': {'value': 1, 'diff': 1}}, metrics['pings.timeouts'])
+        # the wall-clock times and the node counts are not compared
+        self.assertNotIn('wall_time_us', metrics)
+        self.assertNotIn('nodes', metrics)
+
+        table = OTNSComparison.format_report(report).splitlines()
+        self.assertEqual(len(metrics) + 1, len(table))
+        self.assertIn('210 (+10)', [line for line in table if line.startswith('counters.RadioEvents')][0])
+
+    def testTooFewVariants(self):
+        self.assertRaises(AssertionError, OTNSComparison, {'a': []})
+
+
+if __name__ == '__main__':
+    unittest.main()
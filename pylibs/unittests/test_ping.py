@@ -53,6 +53,31 @@ class BasicTests(OTNSTestCase):
 
         self.assertFalse(ns.pings())
 
+    def testPingAll(self):
+        ns = self.ns
+        for _ in range(3):
+            ns.add("router")
+        ns.go(20)
+
+        ns.ping(1, 2, datasize=10)
+        ns.go(1)
+
+        matrix = ns.pingall(count=2)
+        self.assertEqual(4, len(matrix), matrix)
+        for src, row in enumerate(matrix[1:], start=1):
+            cells = row.split()
+            self.assertEqual(str(src), cells[0])
+            for dst, cell in enumerate(cells[1:], start=1):
+                if dst == src:
+                    self.assertEqual('-', cell)
+                else:
+                    self.assertTrue(cell.startswith('0%/'), matrix)
+
+        # the ping results of the sweep are kept separate from the ping results of the user
+        pings = ns.pings()
+        self.assertEqual(1, len(pings), pings)
+        self.assertEqual(10, pings[0][2])
+
 
 if __name__ == '__main__':
     unittest.main()
@@ -0,0 +1,182 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/dispatcher"
+)
+
+const (
+	// AutoSpeedInterval is the wall-clock interval of adjusting the speed in the auto speed mode.
+	AutoSpeedInterval = time.Second
+	// MinAutoSpeed is the lowest speed set in the auto speed mode, so that the simulation never stops.
+	MinAutoSpeed = 0.01
+
+	maxAutoSpeedStep = 2 // maximum factor by which the speed changes at each adjustment
+)
+
+// autoSpeedTuner adjusts the speed of the simulation to keep the host CPU usage near the target.
+type autoSpeedTuner struct {
+	target   float64 // target host CPU usage in percent
+	stop     chan struct{}
+	lastBusy uint64
+	lastAll  uint64
+}
+
+// readHostCpuTimes reads the busy and total CPU times of the host from /proc/stat, which is only available on Linux.
+func readHostCpuTimes() (busy uint64, total uint64, err error) {
+	stat, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return
+	}
+
+	// the first line is the sum of all CPUs: cpu user nice system idle iowait irq softirq steal ...
+	line := strings.SplitN(string(stat), "\n", 2)[0]
+	fields := strings.Fields(line)
+	if len(fields) < 9 || fields[0] != "cpu" {
+		return 0, 0, errors.Errorf("invalid /proc/stat")
+	}
+
+	var idle uint64
+	for i, field := range fields[1:9] {
+		val, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "invalid /proc/stat")
+		}
+		total += val
+		if i == 3 || i == 4 { // idle and iowait
+			idle += val
+		}
+	}
+	return total - idle, total, nil
+}
+
+// tuneSpeed returns the speed which is expected to bring the host CPU usage from cpuPercent to the target, changing
+// the speed by at most maxAutoSpeedStep times.
+func tuneSpeed(speed float64, cpuPercent float64, target float64) float64 {
+	if speed < MinAutoSpeed {
+		speed = MinAutoSpeed
+	}
+
+	factor := float64(maxAutoSpeedStep)
+	if cpuPercent*maxAutoSpeedStep > target {
+		factor = target / cpuPercent
+	}
+	if factor < 1.0/maxAutoSpeedStep {
+		factor = 1.0 / maxAutoSpeedStep
+	}
+
+	speed *= factor
+	if speed < MinAutoSpeed {
+		speed = MinAutoSpeed
+	} else if speed > dispatcher.MaxSimulateSpeed {
+		speed = dispatcher.MaxSimulateSpeed
+	}
+	return speed
+}
+
+// SetAutoSpeed enables the auto speed mode, which adjusts the speed continuously to keep the host CPU usage near the
+// target percentage, or disables it if the target is 0.
+func (s *Simulation) SetAutoSpeed(target float64) error {
+	if target < 0 || target > 100 {
+		return errors.Errorf("invalid target CPU usage: %v%%", target)
+	}
+
+	if s.autoSpeed != nil {
+		close(s.autoSpeed.stop)
+		s.autoSpeed = nil
+	}
+	if target == 0 {
+		return nil
+	}
+
+	busy, total, err := readHostCpuTimes()
+	if err != nil {
+		return errors.Wrapf(err, "host CPU usage not available")
+	}
+
+	tuner := &autoSpeedTuner{target: target, stop: make(chan struct{}), lastBusy: busy, lastAll: total}
+	s.autoSpeed = tuner
+	go s.tuneSpeedPeriodically(tuner)
+	return nil
+}
+
+// AutoSpeed returns the target host CPU usage of the auto speed mode, or 0 if it is disabled.
+func (s *Simulation) AutoSpeed() float64 {
+	if s.autoSpeed == nil {
+		return 0
+	}
+	return s.autoSpeed.target
+}
+
+// tuneSpeedPeriodically adjusts the speed at AutoSpeedInterval until the tuner is stopped.
+func (s *Simulation) tuneSpeedPeriodically(tuner *autoSpeedTuner) {
+	ticker := time.NewTicker(AutoSpeedInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-tuner.stop:
+			return
+		case <-ticker.C:
+			s.PostAsync(true, func() {
+				if s.autoSpeed == tuner {
+					s.tuneSpeed(tuner)
+				}
+			})
+		}
+	}
+}
+
+func (s *Simulation) tuneSpeed(tuner *autoSpeedTuner) {
+	busy, total, err := readHostCpuTimes()
+	if err != nil {
+		simplelogger.Warnf("read host CPU usage failed: %v", err)
+		return
+	}
+	if total <= tuner.lastAll || busy < tuner.lastBusy {
+		return
+	}
+
+	cpuPercent := float64(busy-tuner.lastBusy) * 100 / float64(total-tuner.lastAll)
+	tuner.lastBusy, tuner.lastAll = busy, total
+
+	speed := tuneSpeed(s.GetSpeed(), cpuPercent, tuner.target)
+	simplelogger.Debugf("auto speed: host CPU %.1f%% (target %.0f%%), speed %v -> %v", cpuPercent, tuner.target,
+		s.GetSpeed(), speed)
+	s.d.SetSpeed(speed)
+}
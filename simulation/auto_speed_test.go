@@ -0,0 +1,58 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+)
+
+func TestTuneSpeed(t *testing.T) {
+	for _, tc := range []struct {
+		speed, cpuPercent, target float64
+		expected                  float64
+	}{
+		// no CPU usage, or far below the target: at most doubled
+		{speed: 10, cpuPercent: 0, target: 80, expected: 20},
+		{speed: 10, cpuPercent: 10, target: 80, expected: 20},
+		// near the target: proportional
+		{speed: 10, cpuPercent: 50, target: 80, expected: 16},
+		{speed: 10, cpuPercent: 80, target: 80, expected: 10},
+		{speed: 10, cpuPercent: 100, target: 80, expected: 8},
+		// far above the target: at most halved
+		{speed: 10, cpuPercent: 100, target: 10, expected: 5},
+		// clamped to the speed limits
+		{speed: 0, cpuPercent: 100, target: 80, expected: MinAutoSpeed},
+		{speed: MinAutoSpeed, cpuPercent: 100, target: 10, expected: MinAutoSpeed},
+		{speed: dispatcher.MaxSimulateSpeed, cpuPercent: 0, target: 80, expected: dispatcher.MaxSimulateSpeed},
+	} {
+		assert.InDelta(t, tc.expected, tuneSpeed(tc.speed, tc.cpuPercent, tc.target), 1e-9, "%+v", tc)
+	}
+}
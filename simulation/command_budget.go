@@ -0,0 +1,164 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CommandBudgetStep is the virtual time advanced at once while a node command waits for its completion.
+	CommandBudgetStep = time.Millisecond
+	maxCommandCosts   = 1000
+)
+
+// CommandCost is the virtual time consumed by a node command run under the command budget.
+type CommandCost struct {
+	Node     NodeId `json:"node"`
+	Command  string `json:"command"`
+	Start    uint64 `json:"start_us"`
+	Cost     uint64 `json:"cost_us"`
+	Exceeded bool   `json:"exceeded"` // the command did not complete within the budget
+}
+
+// BudgetedCommand is a node command which may consume virtual time, up to the command budget, before it completes.
+// The simulation is advanced by the caller in steps of CommandBudgetStep between the polls of the command.
+type BudgetedCommand struct {
+	s        *Simulation
+	cmd      string
+	es       *execState
+	deadline time.Time // wall-clock deadline of the command echo
+	cost     *CommandCost
+}
+
+// CommandBudget returns the virtual-time budget of node commands in us, or 0 if node commands run at a fixed
+// virtual time.
+func (s *Simulation) CommandBudget() uint64 {
+	return s.cmdBudget
+}
+
+// SetCommandBudget sets the virtual-time budget of node commands in us, or 0 to run them at a fixed virtual time.
+func (s *Simulation) SetCommandBudget(budget uint64) {
+	s.cmdBudget = budget
+}
+
+// CommandCosts returns the costs of the latest node commands run under the command budget.
+func (s *Simulation) CommandCosts() []*CommandCost {
+	return s.cmdCosts
+}
+
+// ResetCommandCosts discards the costs of node commands.
+func (s *Simulation) ResetCommandCosts() {
+	s.cmdCosts = nil
+}
+
+// StartCommand inputs the CLI command to the node, to run it under the command budget.
+func (s *Simulation) StartCommand(id NodeId, cmd string) (*BudgetedCommand, error) {
+	node := s.nodes[id]
+	if node == nil {
+		return nil, errors.Errorf("node %d not found", id)
+	}
+	if dnode := s.d.GetNode(id); dnode == nil || dnode.IsPaused() {
+		return nil, errors.Errorf("node %d is paused", id)
+	}
+
+	node.inputCommand(cmd)
+	return &BudgetedCommand{
+		s:        s,
+		cmd:      cmd,
+		es:       &execState{node: node, result: &ExecResult{Node: id}},
+		deadline: time.Now().Add(DefaultCommandTimeout),
+		cost:     &CommandCost{Node: id, Command: cmd, Start: s.d.CurTime},
+	}, nil
+}
+
+// Poll reads the output of the command until the node waits for virtual time, and returns if the command completed
+// or exhausted the command budget. The cost of the command is recorded when it returns true.
+func (bc *BudgetedCommand) Poll() bool {
+	s := bc.s
+	for !bc.es.done {
+		if bc.es.readLines(bc.cmd) {
+			continue
+		}
+		if bc.es.echoed {
+			// the node processed the command, and only completes it if the simulation advances
+			s.d.RecvEvents()
+			if !bc.es.readLines(bc.cmd) {
+				break
+			}
+		} else if time.Now().After(bc.deadline) {
+			bc.es.result.Error = "timeout"
+			bc.es.done = true
+		} else {
+			s.d.RecvEvents()
+		}
+	}
+
+	bc.cost.Cost = s.d.CurTime - bc.cost.Start
+	if !bc.es.done && bc.cost.Cost < s.cmdBudget {
+		return false
+	}
+
+	bc.cost.Exceeded = !bc.es.done
+	if bc.cost.Exceeded {
+		// the node completes the command later: its remaining output and Done must not be taken as the output of
+		// the next command
+		bc.es.node.lateCommands++
+	}
+	s.cmdCosts = append(s.cmdCosts, bc.cost)
+	if len(s.cmdCosts) > maxCommandCosts {
+		s.cmdCosts = s.cmdCosts[len(s.cmdCosts)-maxCommandCosts:]
+	}
+	return true
+}
+
+// discardLateOutput returns if the output line of the node belongs to a command which exceeded the command budget,
+// and must be discarded. The output of such a command ends with its Done or error line. Lines matching the expected
+// line, e.g. the echo of the next command, are kept unless they are Done or an error.
+func (node *Node) discardLateOutput(line string, expected interface{}) bool {
+	if node.lateCommands == 0 {
+		return false
+	}
+	if DoneOrErrorRegexp.MatchString(line) {
+		node.lateCommands--
+		return true
+	}
+	return !node.isLineMatch(line, expected)
+}
+
+// Result returns the result of the command. The output is incomplete if the command exceeded the budget.
+func (bc *BudgetedCommand) Result() *ExecResult {
+	return bc.es.result
+}
+
+// Cost returns the cost of the command.
+func (bc *BudgetedCommand) Cost() *CommandCost {
+	return bc.cost
+}
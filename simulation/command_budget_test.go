@@ -0,0 +1,99 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+)
+
+func newBudgetTestCommand(node *Node, cmd string) *BudgetedCommand {
+	return &BudgetedCommand{
+		s:        node.S,
+		cmd:      cmd,
+		es:       &execState{node: node, result: &ExecResult{Node: node.Id}},
+		deadline: time.Now().Add(DefaultCommandTimeout),
+		cost:     &CommandCost{Node: node.Id, Command: cmd, Start: node.S.d.CurTime},
+	}
+}
+
+func TestBudgetedCommandPoll(t *testing.T) {
+	s := &Simulation{d: &dispatcher.Dispatcher{}, cmdBudget: 2000}
+	node := &Node{S: s, Id: 1, pendingLines: make(chan string, 100)}
+
+	// the command completes within the budget
+	bc := newBudgetTestCommand(node, "ping ff03::1")
+	node.pendingLines <- "ping ff03::1"
+	assert.False(t, bc.Poll())
+	s.d.CurTime = 1000
+	node.pendingLines <- "16 bytes from fdde:ad00:beef:0:0:ff:fe00:400: icmp_seq=1 hlim=64 time=3ms"
+	node.pendingLines <- "Done"
+	assert.True(t, bc.Poll())
+	assert.Equal(t, []string{"16 bytes from fdde:ad00:beef:0:0:ff:fe00:400: icmp_seq=1 hlim=64 time=3ms"},
+		bc.Result().Output)
+	assert.Equal(t, "", bc.Result().Error)
+	assert.Equal(t, CommandCost{Node: 1, Command: "ping ff03::1", Cost: 1000}, *bc.Cost())
+
+	// the command exceeds the budget
+	bc = newBudgetTestCommand(node, "ping ff03::2")
+	node.pendingLines <- "ping ff03::2"
+	node.pendingLines <- "1 packets transmitted"
+	assert.False(t, bc.Poll())
+	s.d.CurTime = 3000
+	assert.True(t, bc.Poll())
+	assert.True(t, bc.Cost().Exceeded)
+	assert.Equal(t, []string{"1 packets transmitted"}, bc.Result().Output)
+	assert.Equal(t, 2, len(s.CommandCosts()))
+
+	// the late output of the command is discarded, and does not complete the next command
+	node.pendingLines <- "16 bytes from fdde:ad00:beef:0:0:ff:fe00:800: icmp_seq=2 hlim=64 time=5ms"
+	node.pendingLines <- "Done"
+	bc = newBudgetTestCommand(node, "state")
+	node.pendingLines <- "state"
+	assert.False(t, bc.Poll())
+	node.pendingLines <- "leader"
+	node.pendingLines <- "Done"
+	assert.True(t, bc.Poll())
+	assert.False(t, bc.Cost().Exceeded)
+	assert.Equal(t, []string{"leader"}, bc.Result().Output)
+	assert.Equal(t, 0, node.lateCommands)
+
+	// the late output may arrive after the echo of the next command
+	node.lateCommands = 1
+	result := &ExecResult{Node: 1}
+	es := &execState{node: node, result: result}
+	for _, line := range []string{"rloc16", "Error 13: InvalidState", "0400", "Done"} {
+		node.pendingLines <- line
+	}
+	assert.True(t, es.readLines("rloc16"))
+	assert.True(t, es.done)
+	assert.Equal(t, &ExecResult{Node: 1, Output: []string{"0400"}}, result)
+}
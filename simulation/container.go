@@ -0,0 +1,145 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultContainerRuntime = "docker"
+)
+
+// ContainerConfig configures the container backend, which runs each node inside a Docker or Podman container, so
+// that OT binaries built for different libc or OS versions can coexist in one simulation, isolated from the host.
+// Containers use the network of the host, so that nodes still reach the dispatcher through the event socket.
+type ContainerConfig struct {
+	Runtime string            // container runtime executable, e.g. docker or podman
+	Images  map[string]string // image by node type (router, fed, med, sed), with the default image under ""
+}
+
+// ParseContainerImages parses the images of the container backend: an image for all node types, or comma separated
+// <type>=<image> pairs with an optional default image, e.g. "ot-nodes:latest,sed=ot-nodes:legacy".
+func ParseContainerImages(s string) (map[string]string, error) {
+	images := map[string]string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		typ, image := "", item
+		if sep := strings.IndexByte(item, '='); sep >= 0 {
+			typ, image = item[:sep], item[sep+1:]
+			if err := DefaultNodeConfig().SetNodeType(typ); err != nil {
+				return nil, err
+			}
+		}
+		if image == "" {
+			return nil, errors.Errorf("empty container image: %s", item)
+		}
+		images[typ] = image
+	}
+
+	if len(images) == 0 {
+		return nil, errors.Errorf("no container image")
+	}
+	return images, nil
+}
+
+// Image returns the container image of the node type, or "" if there is none.
+func (cc *ContainerConfig) Image(nodeType string) string {
+	if image, ok := cc.Images[nodeType]; ok {
+		return image
+	}
+	return cc.Images[""]
+}
+
+func (cc *ContainerConfig) runtime() string {
+	if cc.Runtime == "" {
+		return DefaultContainerRuntime
+	}
+	return cc.Runtime
+}
+
+// containerRuntime returns the container runtime running the nodes, or "" if nodes run on the host.
+func (s *Simulation) containerRuntime() string {
+	if s.cfg.Container == nil {
+		return ""
+	}
+	return s.cfg.Container.runtime()
+}
+
+// containerName returns the name of the container of the node, which is unique across the simulations on the host.
+func (s *Simulation) containerName(id NodeId) string {
+	return fmt.Sprintf("otns-%d-%d", s.PortOffset(), id)
+}
+
+// containerCommand returns the command running the executable as the node inside a container. The working directory
+// of the node and the other host paths in mounts are mounted at the same paths, and env is passed to the node.
+func (s *Simulation) containerCommand(id NodeId, cfg *NodeConfig, executable string, dir string, env []string,
+	mounts []string) (*exec.Cmd, error) {
+	cc := s.cfg.Container
+	image := cc.Image(cfg.NodeType())
+	if image == "" {
+		return nil, errors.Errorf("no container image for node type %s", cfg.NodeType())
+	}
+
+	workdir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"run", "--rm", "-i", "--network", "host", "--name", s.containerName(id), "-w", workdir}
+	for _, path := range append([]string{workdir}, mounts...) {
+		args = append(args, "-v", fmt.Sprintf("%s:%s", path, path))
+	}
+	for _, kv := range env {
+		args = append(args, "-e", kv)
+	}
+	args = append(args, image, executable, strconv.Itoa(id))
+
+	cmd := exec.CommandContext(context.Background(), cc.runtime(), args...)
+	cmd.Dir = dir
+	return cmd, nil
+}
+
+// containerCtl runs a control command of the container runtime on the container of the node.
+func (node *Node) containerCtl(command string) error {
+	out, err := exec.Command(node.S.cfg.Container.runtime(), command, node.container).CombinedOutput()
+	if err != nil {
+		return errors.Errorf("%s %s failed: %v: %s", command, node.container, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
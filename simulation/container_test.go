@@ -0,0 +1,111 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/threadconst"
+)
+
+func TestParseContainerImages(t *testing.T) {
+	for _, tc := range []struct {
+		s      string
+		images map[string]string
+		err    string
+	}{
+		{s: "ot-nodes:latest", images: map[string]string{"": "ot-nodes:latest"}},
+		{s: "ot-nodes:latest, sed=ot-nodes:legacy",
+			images: map[string]string{"": "ot-nodes:latest", "sed": "ot-nodes:legacy"}},
+		{s: "router=r:1,fed=f:1,med=m:1,sed=s:1,",
+			images: map[string]string{"router": "r:1", "fed": "f:1", "med": "m:1", "sed": "s:1"}},
+		{s: "registry.example.com:5000/ot:1.3", images: map[string]string{"": "registry.example.com:5000/ot:1.3"}},
+		// the last image of a type wins
+		{s: "a:1,b:2", images: map[string]string{"": "b:2"}},
+		{s: "", err: "no container image"},
+		{s: " , ", err: "no container image"},
+		{s: "sed=", err: "empty container image: sed="},
+		{s: "leader=ot:1", err: "invalid node type: leader"},
+		{s: "=ot:1", err: "invalid node type: "},
+	} {
+		images, err := ParseContainerImages(tc.s)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "%#v", tc.s)
+			continue
+		}
+		assert.Nil(t, err, "%#v", tc.s)
+		assert.Equal(t, tc.images, images, "%#v", tc.s)
+	}
+
+	cc := &ContainerConfig{Images: map[string]string{"": "ot:latest", "sed": "ot:legacy"}}
+	assert.Equal(t, "ot:legacy", cc.Image("sed"))
+	assert.Equal(t, "ot:latest", cc.Image("router"))
+	assert.Equal(t, "", (&ContainerConfig{Images: map[string]string{"sed": "ot:legacy"}}).Image("med"))
+}
+
+func TestContainerCommand(t *testing.T) {
+	s := &Simulation{cfg: &Config{
+		DispatcherPort: threadconst.InitialDispatcherPort + 2*threadconst.WellKnownNodeId,
+		Container:      &ContainerConfig{Runtime: "podman", Images: map[string]string{"sed": "ot:legacy"}},
+	}}
+	cfg := DefaultNodeConfig()
+	assert.Nil(t, cfg.SetNodeType("sed"))
+
+	dir := t.TempDir()
+	cmd, err := s.containerCommand(5, cfg, "/opt/ot/ot-cli-mtd", dir, []string{"PORT_OFFSET=2", "OTNS_SHM=/dev/shm/x"},
+		[]string{"/dev/shm/x", "/tmp/coverage"})
+	assert.Nil(t, err)
+	assert.Equal(t, "podman", filepath.Base(cmd.Path))
+	assert.Equal(t, dir, cmd.Dir)
+	assert.Equal(t, []string{
+		"podman", "run", "--rm", "-i", "--network", "host", "--name", "otns-2-5", "-w", dir,
+		"-v", dir + ":" + dir, "-v", "/dev/shm/x:/dev/shm/x", "-v", "/tmp/coverage:/tmp/coverage",
+		"-e", "PORT_OFFSET=2", "-e", "OTNS_SHM=/dev/shm/x",
+		"ot:legacy", "/opt/ot/ot-cli-mtd", "5",
+	}, cmd.Args)
+	// the environment of the host is not passed to the runtime client
+	assert.Nil(t, cmd.Env)
+	assert.Equal(t, "podman", s.containerRuntime())
+
+	// the working directory is mounted by its absolute path
+	cmd, err = s.containerCommand(5, cfg, "ot-cli-mtd", "tmp/2_5", nil, nil)
+	assert.Nil(t, err)
+	workdir, _ := filepath.Abs("tmp/2_5")
+	assert.Equal(t, []string{"-w", workdir, "-v", workdir + ":" + workdir, "ot:legacy"}, cmd.Args[8:13])
+
+	assert.Nil(t, cfg.SetNodeType("router"))
+	_, err = s.containerCommand(5, cfg, "ot-cli-ftd", dir, nil, nil)
+	assert.EqualError(t, err, "no container image for node type router")
+
+	s.cfg.Container.Runtime = ""
+	assert.Equal(t, "docker", s.containerRuntime())
+	s.cfg.Container = nil
+	assert.Equal(t, "", s.containerRuntime())
+}
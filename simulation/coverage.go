@@ -0,0 +1,152 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	coverageFlushTimeout = time.Second * 3 // time for a node to exit by itself, writing its coverage data
+	llvmProfdataPath     = "llvm-profdata"
+	DefaultCoverageMerge = "merged.profdata"
+)
+
+// CoverageResult is the result of merging the coverage profiles of nodes.
+type CoverageResult struct {
+	Output   string   // merged LLVM profile, or "" if there were no LLVM profiles
+	Profiles int      // merged LLVM profiles
+	GcovDirs []string // directories of the gcov data of nodes, which are not merged
+}
+
+// coverageEnv returns the environment variables which make an instrumented node write its coverage data into the
+// coverage directory: LLVM profiles into <dir>/node-<id>-<pid>.profraw, and gcov data under <dir>/node-<id>.
+func (s *Simulation) coverageEnv(id NodeId) []string {
+	dir := s.cfg.CoverageDir
+	return []string{
+		fmt.Sprintf("LLVM_PROFILE_FILE=%s", filepath.Join(dir, fmt.Sprintf("node-%d-%%p.profraw", id))),
+		fmt.Sprintf("GCOV_PREFIX=%s", filepath.Join(dir, fmt.Sprintf("node-%d", id))),
+	}
+}
+
+// prepareCoverageDir creates the coverage directory and makes its path absolute, since nodes run in their own
+// working directories.
+func (s *Simulation) prepareCoverageDir() error {
+	if s.cfg.CoverageDir == "" {
+		return nil
+	}
+
+	dir, err := filepath.Abs(s.cfg.CoverageDir)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "create coverage directory failed")
+	}
+
+	s.cfg.CoverageDir = dir
+	return nil
+}
+
+// CoverageDir returns the directory of the coverage data of nodes, or "" if coverage is disabled.
+func (s *Simulation) CoverageDir() string {
+	return s.cfg.CoverageDir
+}
+
+// MergeCoverage merges the LLVM profiles written by the nodes which have exited into the output file, relative to the
+// coverage directory if not absolute, using llvm-profdata. The gcov data of nodes is listed but not merged, since
+// gcov based tools (e.g. lcov, gcovr) accept several directories.
+func (s *Simulation) MergeCoverage(output string) (*CoverageResult, error) {
+	dir := s.cfg.CoverageDir
+	if dir == "" {
+		return nil, errors.Errorf("coverage is disabled")
+	}
+
+	if output == "" {
+		output = DefaultCoverageMerge
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+
+	profiles, err := filepath.Glob(filepath.Join(dir, "node-*.profraw"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(profiles)
+
+	result := &CoverageResult{Profiles: len(profiles)}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "node-") {
+			result.GcovDirs = append(result.GcovDirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	if len(profiles) == 0 {
+		if len(result.GcovDirs) == 0 {
+			return nil, errors.Errorf("no coverage data in %s", dir)
+		}
+		return result, nil
+	}
+
+	args := append([]string{"merge", "-sparse", "-o", output}, profiles...)
+	if out, err := exec.Command(llvmProfdataPath, args...).CombinedOutput(); err != nil {
+		return nil, errors.Errorf("%s failed: %v: %s", llvmProfdataPath, err, strings.TrimSpace(string(out)))
+	}
+
+	simplelogger.Infof("merged %d coverage profiles into %s", len(profiles), output)
+	result.Output = output
+	return result, nil
+}
+
+// waitCoverageFlush waits for the node to exit by itself after the exit command, so that it writes its coverage data
+// before it is terminated.
+func (node *Node) waitCoverageFlush() {
+	if node.S.cfg.CoverageDir == "" {
+		return
+	}
+
+	select {
+	case <-node.exited:
+	case <-time.After(coverageFlushTimeout):
+		simplelogger.Warnf("%v - did not exit in %v, coverage data may be lost", node, coverageFlushTimeout)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultEnergyScanDuration is the scan duration per channel of energy scans, in milliseconds.
+	DefaultEnergyScanDuration = 100
+
+	energyScanMinChannel = 11
+	energyScanMaxChannel = 26
+)
+
+var (
+	// e.x. | 11 |  -59 |
+	energyScanRowRegexp = regexp.MustCompile(`^\|\s*(\d+)\s*\|\s*(-?\d+)\s*\|$`)
+	// the header and separator of the result table, i.e. | Ch | RSSI | and +----+------+
+	energyScanHeaderRegexp = regexp.MustCompile(`^(\|\s*Ch\s*\|\s*RSSI\s*\||\+-+\+-+\+)$`)
+)
+
+// EnergyScanNode is the result of the energy scan of a node: the maximum RSSI measured on each channel.
+type EnergyScanNode struct {
+	Node  NodeId      `json:"node"`
+	Rssi  map[int]int `json:"rssi"`
+	Error string      `json:"error,omitempty"`
+}
+
+// EnergyScanChannel is the network-wide quality of a channel, aggregated over the routers which scanned it.
+type EnergyScanChannel struct {
+	Channel int     `json:"channel"`
+	Nodes   int     `json:"nodes"`
+	AvgRssi float64 `json:"avg_rssi"`
+	MaxRssi int     `json:"max_rssi"`
+}
+
+// EnergyScanMatrix is the channel quality matrix of an energy scan of all routers. The recommended channel is the
+// least interfered one: the channel with the lowest maximum RSSI, then the lowest average RSSI.
+type EnergyScanMatrix struct {
+	Duration    int                  `json:"duration"` // scan duration per channel in ms
+	Nodes       []*EnergyScanNode    `json:"nodes"`
+	Channels    []*EnergyScanChannel `json:"channels"`
+	Recommended int                  `json:"recommended"`
+	Missing     []NodeId             `json:"missing"` // nodes which did not finish the scan
+}
+
+// energyScanCollector collects the energy scan results printed by the nodes. Results are reported from the node
+// output routines, so the collector is protected by a lock.
+type energyScanCollector struct {
+	sync.Mutex
+	duration int
+	scans    map[NodeId]*energyScanState
+}
+
+type energyScanState struct {
+	result EnergyScanNode
+	table  bool // the result table started
+	done   bool
+}
+
+func newEnergyScanCollector() *energyScanCollector {
+	return &energyScanCollector{scans: map[NodeId]*energyScanState{}}
+}
+
+// onNodeOutput collects the output line of the node, and returns if the line is part of the energy scan result. The
+// result lines are not passed to the CLI commands, which would print the table rows. The `Done` of the scan ends the
+// result table, and is ignored before the table started, as it belongs to another command.
+func (ec *energyScanCollector) onNodeOutput(id NodeId, line string) bool {
+	ec.Lock()
+	defer ec.Unlock()
+
+	state := ec.scans[id]
+	if state == nil || state.done {
+		return false
+	}
+
+	line = strings.TrimSpace(line)
+	if m := energyScanRowRegexp.FindStringSubmatch(line); m != nil {
+		channel, _ := strconv.Atoi(m[1])
+		rssi, _ := strconv.Atoi(m[2])
+		state.result.Rssi[channel] = rssi
+		state.table = true
+	} else if energyScanHeaderRegexp.MatchString(line) {
+		state.table = true
+	} else if line == "Done" && state.table {
+		state.done = true
+	} else if strings.HasPrefix(line, "Error ") {
+		state.result.Error = line
+		state.done = true
+	} else {
+		return false
+	}
+	return true
+}
+
+func (ec *energyScanCollector) reset() {
+	ec.Lock()
+	defer ec.Unlock()
+
+	ec.scans = map[NodeId]*energyScanState{}
+}
+
+// allDone returns if all nodes finished scanning.
+func (ec *energyScanCollector) allDone() bool {
+	ec.Lock()
+	defer ec.Unlock()
+
+	for _, state := range ec.scans {
+		if !state.done {
+			return false
+		}
+	}
+	return true
+}
+
+// StartEnergyScan starts energy scans of the duration (in ms) per channel on all channels on all routers, and returns
+// the simulation time the scans take. The simulation must run for the scans to finish, until FinishEnergyScan.
+// Failed and paused nodes are skipped.
+func (s *Simulation) StartEnergyScan(duration int) (scanTime time.Duration, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = errors.Errorf("%v", e)
+			s.energyScan.reset()
+		}
+	}()
+
+	if duration <= 0 {
+		duration = DefaultEnergyScanDuration
+	}
+
+	var routers []*Node
+	s.VisitNodesInOrder(func(node *Node) {
+		dnode := s.d.GetNode(node.Id)
+		if dnode == nil || dnode.IsPaused() || dnode.IsFailed() ||
+			(dnode.Role != OtDeviceRoleRouter && dnode.Role != OtDeviceRoleLeader) {
+			return
+		}
+		routers = append(routers, node)
+	})
+	if len(routers) == 0 {
+		return 0, errors.Errorf("no routers to scan")
+	}
+
+	s.energyScan.Lock()
+	s.energyScan.duration = duration
+	s.energyScan.scans = map[NodeId]*energyScanState{}
+	for _, node := range routers {
+		s.energyScan.scans[node.Id] = &energyScanState{
+			result: EnergyScanNode{Node: node.Id, Rssi: map[int]int{}},
+		}
+	}
+	s.energyScan.Unlock()
+
+	// the results are printed when the scan finishes, and collected from the node output
+	for _, node := range routers {
+		node.CommandExpectNone(fmt.Sprintf("scan energy %d", duration), DefaultCommandTimeout)
+	}
+
+	channels := energyScanMaxChannel - energyScanMinChannel + 1
+	return time.Duration(duration*channels) * time.Millisecond, nil
+}
+
+// EnergyScanDone returns if all routers finished the energy scan.
+func (s *Simulation) EnergyScanDone() bool {
+	return s.energyScan.allDone()
+}
+
+// FinishEnergyScan stops collecting the energy scan results, and returns the channel quality matrix.
+func (s *Simulation) FinishEnergyScan() (*EnergyScanMatrix, error) {
+	s.energyScan.Lock()
+	scans, duration := s.energyScan.scans, s.energyScan.duration
+	s.energyScan.scans = map[NodeId]*energyScanState{}
+	s.energyScan.Unlock()
+
+	if len(scans) == 0 {
+		return nil, errors.Errorf("no energy scan")
+	}
+
+	matrix := &EnergyScanMatrix{Duration: duration, Nodes: []*EnergyScanNode{}, Missing: []NodeId{}}
+	for _, state := range scans {
+		if !state.done {
+			matrix.Missing = append(matrix.Missing, state.result.Node)
+			continue
+		}
+		result := state.result
+		matrix.Nodes = append(matrix.Nodes, &result)
+	}
+	sort.Slice(matrix.Nodes, func(i, j int) bool {
+		return matrix.Nodes[i].Node < matrix.Nodes[j].Node
+	})
+	sort.Ints(matrix.Missing)
+
+	matrix.Channels, matrix.Recommended = aggregateEnergyScan(matrix.Nodes)
+	return matrix, nil
+}
+
+// aggregateEnergyScan aggregates the RSSI of each channel over the nodes, and returns the channel qualities and the
+// least interfered channel, or 0 if no channel was scanned.
+func aggregateEnergyScan(nodes []*EnergyScanNode) ([]*EnergyScanChannel, int) {
+	channels := []*EnergyScanChannel{}
+	var best *EnergyScanChannel
+	for ch := energyScanMinChannel; ch <= energyScanMaxChannel; ch++ {
+		quality := &EnergyScanChannel{Channel: ch}
+		sum := 0
+		for _, node := range nodes {
+			rssi, ok := node.Rssi[ch]
+			if !ok {
+				continue
+			}
+			if quality.Nodes == 0 || rssi > quality.MaxRssi {
+				quality.MaxRssi = rssi
+			}
+			quality.Nodes++
+			sum += rssi
+		}
+		if quality.Nodes == 0 {
+			continue
+		}
+		quality.AvgRssi = float64(sum) / float64(quality.Nodes)
+		channels = append(channels, quality)
+
+		if best == nil || quality.MaxRssi < best.MaxRssi ||
+			(quality.MaxRssi == best.MaxRssi && quality.AvgRssi < best.AvgRssi) {
+			best = quality
+		}
+	}
+
+	if best == nil {
+		return channels, 0
+	}
+	return channels, best.Channel
+}
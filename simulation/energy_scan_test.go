@@ -0,0 +1,121 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnergyScanCollector(t *testing.T) {
+	ec := newEnergyScanCollector()
+	ec.scans[1] = &energyScanState{result: EnergyScanNode{Node: 1, Rssi: map[int]int{}}}
+	ec.scans[2] = &energyScanState{result: EnergyScanNode{Node: 2, Rssi: map[int]int{}}}
+
+	// output of `scan energy 100` on the OT CLI, after the `Done` of another command
+	for _, tc := range []struct {
+		line   string
+		result bool
+	}{
+		{"Done", false},
+		{"scan energy 100", false},
+		{"| Ch | RSSI |", true},
+		{"+----+------+", true},
+		{"| 11 |  -59 |", true},
+		{"| 12 |  -90 |", true},
+		{"| 26 | -101 |\r", true},
+		{"Done", true},
+		// the scan is done
+		{"| 13 |  -20 |", false},
+		{"Done", false},
+	} {
+		assert.Equal(t, tc.result, ec.onNodeOutput(1, tc.line), "%#v", tc.line)
+	}
+	assert.True(t, ec.scans[1].done)
+	assert.Equal(t, map[int]int{11: -59, 12: -90, 26: -101}, ec.scans[1].result.Rssi)
+
+	// other nodes are not affected
+	assert.False(t, ec.onNodeOutput(3, "| 11 |  -59 |"))
+	assert.False(t, ec.allDone())
+	assert.True(t, ec.onNodeOutput(2, "Error 13: InvalidState"))
+	assert.Equal(t, "Error 13: InvalidState", ec.scans[2].result.Error)
+	assert.True(t, ec.allDone())
+
+	ec.reset()
+	assert.False(t, ec.onNodeOutput(1, "| 11 |  -59 |"))
+}
+
+func TestAggregateEnergyScan(t *testing.T) {
+	for _, tc := range []struct {
+		nodes       []*EnergyScanNode
+		channels    []*EnergyScanChannel
+		recommended int
+	}{
+		{nodes: nil, channels: []*EnergyScanChannel{}},
+		{nodes: []*EnergyScanNode{{Node: 1, Rssi: map[int]int{}}}, channels: []*EnergyScanChannel{}},
+		{
+			nodes: []*EnergyScanNode{{Node: 1, Rssi: map[int]int{11: -60, 15: -80, 26: -70}}},
+			channels: []*EnergyScanChannel{
+				{Channel: 11, Nodes: 1, AvgRssi: -60, MaxRssi: -60},
+				{Channel: 15, Nodes: 1, AvgRssi: -80, MaxRssi: -80},
+				{Channel: 26, Nodes: 1, AvgRssi: -70, MaxRssi: -70},
+			},
+			recommended: 15,
+		},
+		{
+			// channels out of range are ignored, and nodes which did not scan a channel are not counted
+			nodes: []*EnergyScanNode{
+				{Node: 1, Rssi: map[int]int{10: -100, 11: -90, 12: -60}},
+				{Node: 2, Rssi: map[int]int{11: -70, 12: -100, 27: -100}},
+				{Node: 3, Rssi: map[int]int{12: -95}},
+			},
+			channels: []*EnergyScanChannel{
+				{Channel: 11, Nodes: 2, AvgRssi: -80, MaxRssi: -70},
+				{Channel: 12, Nodes: 3, AvgRssi: -85, MaxRssi: -60},
+			},
+			recommended: 11,
+		},
+		{
+			// the lowest average RSSI breaks ties of the maximum RSSI, then the lowest channel
+			nodes: []*EnergyScanNode{
+				{Node: 1, Rssi: map[int]int{20: -70, 21: -70, 22: -70}},
+				{Node: 2, Rssi: map[int]int{20: -80, 21: -90, 22: -90}},
+			},
+			channels: []*EnergyScanChannel{
+				{Channel: 20, Nodes: 2, AvgRssi: -75, MaxRssi: -70},
+				{Channel: 21, Nodes: 2, AvgRssi: -80, MaxRssi: -70},
+				{Channel: 22, Nodes: 2, AvgRssi: -80, MaxRssi: -70},
+			},
+			recommended: 21,
+		},
+	} {
+		channels, recommended := aggregateEnergyScan(tc.nodes)
+		assert.Equal(t, tc.channels, channels)
+		assert.Equal(t, tc.recommended, recommended)
+	}
+}
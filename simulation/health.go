@@ -0,0 +1,265 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"bufio"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultMaxNodeRestarts is the default number of times a node is restarted after its process died.
+	DefaultMaxNodeRestarts = 3
+
+	stderrTailLines   = 10
+	stderrDrainPeriod = time.Second
+)
+
+// HealthPolicy is the action taken when a node process dies unexpectedly.
+type HealthPolicy string
+
+const (
+	// HealthPolicyRestart restarts the node with its flash preserved, until it has been restarted MaxRestarts times.
+	HealthPolicyRestart HealthPolicy = "restart"
+	// HealthPolicyDelete deletes the node.
+	HealthPolicyDelete HealthPolicy = "delete"
+)
+
+func ParseHealthPolicy(s string) (HealthPolicy, error) {
+	switch policy := HealthPolicy(s); policy {
+	case HealthPolicyRestart, HealthPolicyDelete:
+		return policy, nil
+	default:
+		return "", errors.Errorf("unknown health policy: %s", s)
+	}
+}
+
+// HealthConfig configures the supervision of node processes.
+type HealthConfig struct {
+	Policy      HealthPolicy
+	MaxRestarts int
+}
+
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Policy:      HealthPolicyRestart,
+		MaxRestarts: DefaultMaxNodeRestarts,
+	}
+}
+
+// HealthEvent reports a node process which died unexpectedly, and the action taken by the supervisor.
+type HealthEvent struct {
+	Time     uint64       `json:"time_us"`
+	Node     NodeId       `json:"node"`
+	Status   string       `json:"status"`
+	Stderr   []string     `json:"stderr"` // last lines written by the node to stderr
+	Action   HealthPolicy `json:"action"`
+	Restarts int          `json:"restarts"` // restarts of the node so far, including this one
+	Error    string       `json:"error,omitempty"`
+}
+
+// stderrTail keeps the last lines written by a node process to stderr.
+type stderrTail struct {
+	sync.Mutex
+	lines []string
+	done  chan struct{}
+}
+
+func (st *stderrTail) add(line string) {
+	st.Lock()
+	defer st.Unlock()
+
+	if len(st.lines) == stderrTailLines {
+		st.lines = st.lines[1:]
+	}
+	st.lines = append(st.lines, line)
+}
+
+func (st *stderrTail) get() []string {
+	st.Lock()
+	defer st.Unlock()
+
+	return append([]string(nil), st.lines...)
+}
+
+// stderrReader reads the stderr of the node process until it exits.
+func (node *Node) stderrReader() {
+	defer close(node.stderr.done)
+
+	scanner := bufio.NewScanner(node.pipeErr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		simplelogger.Debugf("%v - stderr: %s", node, line)
+		node.stderr.add(line)
+	}
+}
+
+// onProcessExit is called by the line reader routine when the output of the node process is closed.
+func (node *Node) onProcessExit() {
+	if atomic.LoadInt32(&node.exiting) != 0 {
+		return
+	}
+
+	// wait for the last lines of stderr, which usually explain the crash, without blocking the simulation
+	select {
+	case <-node.stderr.done:
+	case <-time.After(stderrDrainPeriod):
+	}
+
+	node.S.PostAsync(false, func() {
+		node.S.onNodeProcessDied(node)
+	})
+}
+
+// GetHealthConfig returns the configuration of the node process supervision.
+func (s *Simulation) GetHealthConfig() HealthConfig {
+	return s.healthCfg
+}
+
+// SetHealthConfig sets the configuration of the node process supervision.
+func (s *Simulation) SetHealthConfig(cfg HealthConfig) {
+	simplelogger.AssertTrue(cfg.MaxRestarts >= 0)
+	s.healthCfg = cfg
+}
+
+// HealthEvents returns the node processes which died unexpectedly, in order.
+func (s *Simulation) HealthEvents() []*HealthEvent {
+	return s.healthEvents
+}
+
+// onNodeProcessDied handles a node process which exited without being asked to, by restarting or deleting the node
+// according to the health policy.
+func (s *Simulation) onNodeProcessDied(node *Node) {
+	if s.IsStopped() || s.nodes[node.Id] != node {
+		// the node was deleted or replaced in the meantime
+		return
+	}
+
+	evt := s.newHealthEvent(node)
+	cfg := nodeRestartConfig(node)
+	_ = s.DeleteNode(node.Id)
+	evt.Stderr = node.stderr.get()
+	evt.Status = "exit status 0"
+	if node.exitErr != nil {
+		evt.Status = node.exitErr.Error()
+	}
+
+	s.applyHealthEvent(evt, cfg)
+}
+
+// ZombieRestart returns if zombie nodes are handled by the health policy.
+func (s *Simulation) ZombieRestart() bool {
+	return s.zombieRestart
+}
+
+// SetZombieRestart sets if zombie nodes are handled by the health policy like nodes which died, i.e. restarted or
+// deleted.
+func (s *Simulation) SetZombieRestart(restart bool) {
+	s.zombieRestart = restart
+}
+
+// onNodeZombie is called by the dispatcher when it detects a zombie node.
+func (s *Simulation) onNodeZombie(z *dispatcher.Zombie) {
+	if !s.zombieRestart {
+		return
+	}
+
+	// the dispatcher goroutine, which runs the tasks, must not block on posting a task
+	go s.PostAsync(false, func() {
+		node := s.nodes[z.Node]
+		if s.IsStopped() || node == nil {
+			return
+		}
+
+		evt := s.newHealthEvent(node)
+		evt.Status = fmt.Sprintf("zombie: no events since %.6fs", float64(z.LastEvent)/1000000)
+		cfg := nodeRestartConfig(node)
+		_ = s.DeleteNode(node.Id)
+		evt.Stderr = node.stderr.get()
+		s.applyHealthEvent(evt, cfg)
+	})
+}
+
+// newHealthEvent returns the health event of the node with the action of the health policy.
+func (s *Simulation) newHealthEvent(node *Node) *HealthEvent {
+	evt := &HealthEvent{
+		Time:     s.d.CurTime,
+		Node:     node.Id,
+		Action:   s.healthCfg.Policy,
+		Restarts: node.restarts,
+	}
+	if evt.Action == HealthPolicyRestart && node.restarts >= s.healthCfg.MaxRestarts {
+		evt.Action = HealthPolicyDelete
+	}
+	return evt
+}
+
+// nodeRestartConfig returns the configuration which restarts the node at its position with its flash preserved.
+func nodeRestartConfig(node *Node) NodeConfig {
+	dnode := node.S.d.GetNode(node.Id)
+	cfg := *node.cfg
+	cfg.ID = node.Id
+	cfg.X, cfg.Y = dnode.X, dnode.Y
+	cfg.Restore = true
+	return cfg
+}
+
+// applyHealthEvent restarts the deleted node of the health event if required by the action, and records the event.
+func (s *Simulation) applyHealthEvent(evt *HealthEvent, cfg NodeConfig) {
+	if evt.Action == HealthPolicyRestart {
+		evt.Restarts++
+		if newNode, err := s.AddNode(&cfg); err != nil {
+			evt.Error = err.Error()
+		} else {
+			newNode.restarts = evt.Restarts
+		}
+	}
+
+	s.healthEvents = append(s.healthEvents, evt)
+	s.logHealthEvent(evt)
+}
+
+func (s *Simulation) logHealthEvent(evt *HealthEvent) {
+	msg := fmt.Sprintf("node %d process died (%s), action=%s restarts=%d", evt.Node, evt.Status, evt.Action,
+		evt.Restarts)
+	if evt.Error != "" {
+		msg += ": " + evt.Error
+	}
+	simplelogger.Errorf("%s", msg)
+	for _, line := range evt.Stderr {
+		simplelogger.Errorf("node %d stderr: %s", evt.Node, line)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/threadconst"
+)
+
+// newHealthTestSimulation creates and runs a simulation without nodes, at a port not used by other tests.
+func newHealthTestSimulation(t *testing.T) *Simulation {
+	ctx := progctx.New(nil)
+	cfg := DefaultConfig()
+	cfg.DispatcherPort = threadconst.InitialDispatcherPort + 46*threadconst.WellKnownNodeId
+	cfg.NodeDir = t.TempDir()
+	dispatcherCfg := dispatcher.DefaultConfig()
+	dispatcherCfg.NoPcap = true
+	s, err := NewSimulation(ctx, cfg, dispatcherCfg)
+	assert.Nil(t, err)
+	go s.Run()
+	t.Cleanup(func() {
+		ctx.Cancel("test done")
+		ctx.Wait()
+	})
+	return s
+}
+
+// postWait runs f in the simulation goroutine and returns how long it took until f ran.
+func postWait(s *Simulation, f func()) time.Duration {
+	start := time.Now()
+	done := make(chan struct{})
+	s.PostAsync(false, func() {
+		f()
+		close(done)
+	})
+	<-done
+	return time.Since(start)
+}
+
+func TestOnProcessExitDoesNotBlockSimulation(t *testing.T) {
+	s := newHealthTestSimulation(t)
+	node := &Node{S: s, Id: 1, stderr: stderrTail{done: make(chan struct{})}}
+	postWait(s, func() {
+		s.nodes[node.Id] = node
+	})
+
+	// stderr of the node is not closed yet, and is drained outside the simulation goroutine
+	exited := make(chan struct{})
+	go func() {
+		node.onProcessExit()
+		close(exited)
+	}()
+	time.Sleep(stderrDrainPeriod / 10)
+	assert.Less(t, int64(postWait(s, func() {})), int64(stderrDrainPeriod/2))
+
+	// the node is deleted in the meantime, so the exit is ignored
+	postWait(s, func() {
+		delete(s.nodes, node.Id)
+	})
+	close(node.stderr.done)
+	<-exited
+	postWait(s, func() {})
+	assert.Empty(t, s.HealthEvents())
+}
+
+func TestOnProcessExitOfExitingNode(t *testing.T) {
+	node := &Node{Id: 1, exiting: 1, stderr: stderrTail{done: make(chan struct{})}}
+
+	// the node was asked to exit: neither waits for stderr nor posts to the simulation
+	start := time.Now()
+	node.onProcessExit()
+	assert.Less(t, int64(time.Since(start)), int64(stderrDrainPeriod/2))
+}
+
+func TestStderrTail(t *testing.T) {
+	var st stderrTail
+	for i := 0; i < stderrTailLines+2; i++ {
+		st.add(fmt.Sprintf("line %d", i))
+	}
+	lines := st.get()
+	assert.Equal(t, stderrTailLines, len(lines))
+	assert.Equal(t, "line 2", lines[0])
+	assert.Equal(t, fmt.Sprintf("line %d", stderrTailLines+1), lines[len(lines)-1])
+}
+
+func TestNewHealthEvent(t *testing.T) {
+	s := &Simulation{d: &dispatcher.Dispatcher{CurTime: 5000000}, healthCfg: DefaultHealthConfig()}
+	node := &Node{S: s, Id: 3}
+
+	evt := s.newHealthEvent(node)
+	assert.Equal(t, HealthEvent{Time: 5000000, Node: 3, Action: HealthPolicyRestart}, *evt)
+
+	// the node is deleted after restarting it MaxRestarts times
+	node.restarts = DefaultMaxNodeRestarts
+	assert.Equal(t, HealthPolicyDelete, s.newHealthEvent(node).Action)
+
+	s.healthCfg.Policy = HealthPolicyDelete
+	node.restarts = 0
+	assert.Equal(t, HealthPolicyDelete, s.newHealthEvent(node).Action)
+}
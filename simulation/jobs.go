@@ -0,0 +1,268 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	JobPing = "ping"
+	JobSend = "send"
+
+	JobRunning  = "running"
+	JobDone     = "done"
+	JobCanceled = "canceled"
+
+	// JobSendWaitUs is the time a send job waits after the last message for the messages to be delivered.
+	JobSendWaitUs = 1000000
+
+	maxFinishedJobs = 100
+)
+
+// Job is a long-running ping or send started in the background by a CLI command, which can be listed and canceled
+// until it completes.
+type Job struct {
+	Id       int    `json:"id"`
+	Kind     string `json:"kind"` // JobPing or JobSend
+	Src      NodeId `json:"src"`
+	Dst      string `json:"dst"`      // destination address, multicast group, or node ID of unicast sends
+	Count    int    `json:"count"`    // number of pings or messages to send
+	Sent     int    `json:"sent"`     // number of pings or messages sent so far
+	Received int    `json:"received"` // number of ping replies, or deliveries of the sent messages
+	Expected int    `json:"expected"` // number of expected ping replies or deliveries of the sent messages
+	Start    uint64 `json:"start_us"`
+	End      uint64 `json:"end_us"` // time when the job completes, or completed if it is not running
+	State    string `json:"state"`  // JobRunning, JobDone or JobCanceled
+
+	interval uint64 // interval of pings or messages in us
+	sessions []*sendSession
+}
+
+func (job *Job) String() string {
+	return fmt.Sprintf("job %d %s: %d -> %s, sent=%d/%d received=%d/%d", job.Id, job.State, job.Src, job.Dst, job.Sent,
+		job.Count, job.Received, job.Expected)
+}
+
+// jobManager keeps the running jobs and the latest finished jobs.
+type jobManager struct {
+	nextId   int
+	jobs     []*Job
+	handlers []func(*Job)
+}
+
+// startJob registers a new job started at the current time, which completes after the duration (in us) unless it is
+// canceled or finished earlier.
+func (s *Simulation) startJob(kind string, src NodeId, dst string, count int, interval uint64, duration uint64) *Job {
+	jm := &s.jobs
+	if jm.nextId == 0 {
+		jm.nextId = 1
+		s.d.SubscribePingResults(s.onPingResult)
+	}
+
+	job := &Job{
+		Id:       jm.nextId,
+		Kind:     kind,
+		Src:      src,
+		Dst:      dst,
+		Count:    count,
+		Start:    s.d.CurTime,
+		End:      s.d.CurTime + duration,
+		State:    JobRunning,
+		interval: interval,
+	}
+	jm.nextId++
+	jm.jobs = append(jm.jobs, job)
+
+	s.d.ScheduleAt(job.End, func() {
+		s.finishJob(job, JobDone)
+	})
+	return job
+}
+
+// finishJob updates the results of the running job, marks it as finished, and reports it to the subscribers.
+func (s *Simulation) finishJob(job *Job, state string) {
+	if job.State != JobRunning {
+		return
+	}
+
+	s.updateJob(job)
+	if job.Kind == JobPing && state == JobDone {
+		job.Sent = job.Count
+		job.Expected = job.Count
+	}
+	job.State = state
+	job.End = s.d.CurTime
+
+	simplelogger.Infof("%v", job)
+	for _, cb := range s.jobs.handlers {
+		cb(job)
+	}
+
+	s.trimJobs()
+}
+
+// trimJobs discards the oldest finished jobs beyond maxFinishedJobs.
+func (s *Simulation) trimJobs() {
+	jm := &s.jobs
+	finished := 0
+	for _, job := range jm.jobs {
+		if job.State != JobRunning {
+			finished++
+		}
+	}
+
+	jobs := jm.jobs[:0]
+	for _, job := range jm.jobs {
+		if job.State != JobRunning && finished > maxFinishedJobs {
+			finished--
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	jm.jobs = jobs
+}
+
+// updateJob updates the results of the running job as of the current time.
+func (s *Simulation) updateJob(job *Job) {
+	if job.State != JobRunning {
+		return
+	}
+
+	switch job.Kind {
+	case JobPing:
+		// pings are sent by the node at the interval
+		sent := job.Count
+		if job.interval > 0 {
+			sent = int((s.d.CurTime-job.Start)/job.interval) + 1
+		}
+		if sent > job.Count {
+			sent = job.Count
+		}
+		job.Sent = sent
+		job.Expected = sent
+	case JobSend:
+		st := s.sendTracker
+		st.Lock()
+		job.Received, job.Expected = 0, 0
+		for _, ss := range job.sessions {
+			job.Received += len(ss.Received)
+			job.Expected += len(ss.Expected)
+		}
+		st.Unlock()
+	}
+}
+
+func (s *Simulation) onPingResult(src NodeId, result *dispatcher.PingResult) {
+	if result.Delay >= dispatcher.MaxPingDelayUs {
+		return
+	}
+
+	dst := net.ParseIP(result.Dst)
+	for _, job := range s.jobs.jobs {
+		if job.Kind != JobPing || job.State != JobRunning || job.Src != src || !dst.Equal(net.ParseIP(job.Dst)) {
+			continue
+		}
+
+		job.Received++
+		if job.Received >= job.Count {
+			s.finishJob(job, JobDone)
+		}
+		return
+	}
+}
+
+// StartPing starts pinging the destination address from the source node as a background job. The interval is in
+// seconds like the OT CLI ping command.
+func (s *Simulation) StartPing(src NodeId, dst string, datasize int, count int, interval int, hopLimit int) (*Job, error) {
+	node := s.nodes[src]
+	if node == nil {
+		return nil, errors.Errorf("node %d not found", src)
+	}
+	if count <= 0 {
+		return nil, errors.Errorf("invalid count: %d", count)
+	}
+
+	node.Ping(dst, datasize, count, interval, hopLimit)
+	intervalUs := uint64(interval) * 1000000
+	return s.startJob(JobPing, src, dst, count, intervalUs, uint64(count-1)*intervalUs+dispatcher.MaxPingDelayUs), nil
+}
+
+// Jobs returns the running jobs and the latest finished jobs, with their results as of the current time.
+func (s *Simulation) Jobs() []*Job {
+	for _, job := range s.jobs.jobs {
+		s.updateJob(job)
+	}
+	return s.jobs.jobs
+}
+
+// CancelJob stops the running job.
+func (s *Simulation) CancelJob(id int) error {
+	var job *Job
+	for _, j := range s.jobs.jobs {
+		if j.Id == id {
+			job = j
+		}
+	}
+	if job == nil {
+		return errors.Errorf("job %d not found", id)
+	}
+	if job.State != JobRunning {
+		return errors.Errorf("job %d is %s", id, job.State)
+	}
+
+	if s.nodes[job.Src] != nil && job.Kind == JobPing {
+		// the node may have finished pinging already, which ping stop ignores
+		result := s.ExecCommand([]NodeId{job.Src}, "ping stop", DefaultCommandTimeout)[0]
+		if result.Error != "" {
+			return errors.Errorf("job %d: ping stop failed on node %d: %s", id, job.Src, result.Error)
+		}
+	}
+	s.finishJob(job, JobCanceled)
+	return nil
+}
+
+// SubscribeJobs registers a callback for jobs which complete or are canceled.
+// The callback is called in the dispatcher goroutine and must return quickly.
+func (s *Simulation) SubscribeJobs(cb func(*Job)) {
+	s.jobs.handlers = append(s.jobs.handlers, cb)
+}
+
+// resetJobs discards all jobs, e.g. when the simulation is reset. The subscribers are kept.
+func (s *Simulation) resetJobs() {
+	for _, job := range s.jobs.jobs {
+		job.State = JobCanceled
+	}
+	s.jobs.jobs = nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// GridSnap returns the grid size which node positions snap to, or 0 if snapping is disabled.
+func (s *Simulation) GridSnap() int {
+	return s.gridSnap
+}
+
+// SetGridSnap makes node positions snap to a grid of the specified size, or disables snapping if size is 0. All nodes
+// are moved to the nearest grid point.
+func (s *Simulation) SetGridSnap(size int) {
+	if size < 0 {
+		size = 0
+	}
+	s.gridSnap = size
+	if size == 0 {
+		return
+	}
+
+	s.VisitNodesInOrder(func(node *Node) {
+		dn := s.d.GetNode(node.Id)
+		x, y := s.SnapToGrid(dn.X, dn.Y)
+		if x != dn.X || y != dn.Y {
+			s.d.SetNodePos(node.Id, x, y)
+		}
+	})
+}
+
+// SnapToGrid returns the grid point nearest to the position, or the position if snapping is disabled.
+func (s *Simulation) SnapToGrid(x, y int) (int, int) {
+	if s.gridSnap <= 0 {
+		return x, y
+	}
+
+	g := float64(s.gridSnap)
+	return int(math.Round(float64(x)/g) * g), int(math.Round(float64(y)/g) * g)
+}
+
+// MoveNodeBy moves the node relative to its current position, snapped to the grid.
+func (s *Simulation) MoveNodeBy(nodeid NodeId, dx, dy int) error {
+	dn := s.d.GetNode(nodeid)
+	if dn == nil {
+		return errors.Errorf("node %d not found", nodeid)
+	}
+
+	x, y := s.SnapToGrid(dn.X+dx, dn.Y+dy)
+	s.d.SetNodePos(nodeid, x, y)
+	return nil
+}
+
+// AlignNodes aligns the nodes on the horizontal or vertical line through the first node, snapped to the grid. If
+// spacing is positive, the nodes are also placed in the specified order with the spacing between each other, starting
+// at the first node; otherwise they keep their other coordinate.
+func (s *Simulation) AlignNodes(nodeids []NodeId, horizontal bool, spacing int) error {
+	if len(nodeids) < 2 {
+		return errors.Errorf("at least 2 nodes are required")
+	}
+
+	for _, id := range nodeids {
+		if s.d.GetNode(id) == nil {
+			return errors.Errorf("node %d not found", id)
+		}
+	}
+
+	first := s.d.GetNode(nodeids[0])
+	x0, y0 := s.SnapToGrid(first.X, first.Y)
+	for i, id := range nodeids {
+		dn := s.d.GetNode(id)
+		x, y := dn.X, dn.Y
+		if horizontal {
+			y = y0
+			if spacing > 0 {
+				x = x0 + i*spacing
+			}
+		} else {
+			x = x0
+			if spacing > 0 {
+				y = y0 + i*spacing
+			}
+		}
+
+		x, y = s.SnapToGrid(x, y)
+		s.d.SetNodePos(id, x, y)
+	}
+	return nil
+}
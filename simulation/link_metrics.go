@@ -0,0 +1,260 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultLinkMetricsInterval is the default interval of Link Metrics probes in us.
+	DefaultLinkMetricsInterval = 1000000
+)
+
+var (
+	// e.x. Received Link Metrics Report from: fe80:0:0:0:3092:f334:1455:1ad2
+	linkMetricsReportRegexp = regexp.MustCompile(`^Received Link Metrics Report from: (\S+)$`)
+	// e.x. - RSSI: -18 (dBm) (Exponential Moving Average)
+	linkMetricsValueRegexp = regexp.MustCompile(`^- (PDU Counter|LQI|Margin|RSSI): (-?\d+)`)
+)
+
+// LinkMetricsProbe queries the Link Metrics of the link from the source node to the neighbor periodically, with
+// single probes of the Thread 1.2 Link Metrics protocol (`linkmetrics query <addr> single pqmr`).
+type LinkMetricsProbe struct {
+	Src      NodeId `json:"src"`
+	Dst      NodeId `json:"dst"`
+	Interval uint64 `json:"interval_us"`
+	Addr     string `json:"addr"` // link-local address of the neighbor
+}
+
+type linkMetricsPair struct {
+	Src, Dst NodeId
+}
+
+type linkMetricsReport struct {
+	addr   string
+	sample dispatcher.LinkMetricsSample
+}
+
+// linkMetricsCollector collects the Link Metrics reports printed by the nodes. Reports are parsed from the node output
+// routines, so the collector is protected by a lock.
+type linkMetricsCollector struct {
+	sync.Mutex
+	current map[NodeId]*linkMetricsReport   // report being printed by each node
+	reports map[NodeId][]*linkMetricsReport // complete reports of each node
+}
+
+func newLinkMetricsCollector() *linkMetricsCollector {
+	return &linkMetricsCollector{
+		current: map[NodeId]*linkMetricsReport{},
+		reports: map[NodeId][]*linkMetricsReport{},
+	}
+}
+
+func (lc *linkMetricsCollector) onNodeOutput(id NodeId, timestamp uint64, line string) {
+	line = strings.TrimSpace(line)
+	if m := linkMetricsReportRegexp.FindStringSubmatch(line); m != nil {
+		lc.Lock()
+		defer lc.Unlock()
+
+		lc.complete(id)
+		lc.current[id] = &linkMetricsReport{addr: m[1], sample: dispatcher.LinkMetricsSample{Time: timestamp}}
+		return
+	}
+
+	m := linkMetricsValueRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	report := lc.current[id]
+	if report == nil {
+		return
+	}
+
+	val, _ := strconv.Atoi(m[2])
+	switch m[1] {
+	case "PDU Counter":
+		report.sample.Pdus = uint32(val)
+	case "LQI":
+		report.sample.Lqi = val
+	case "Margin":
+		report.sample.Margin = val
+	case "RSSI":
+		// RSSI is the last metric of reports
+		report.sample.Rssi = val
+		lc.complete(id)
+	}
+}
+
+// complete moves the report being printed by the node to the complete reports.
+func (lc *linkMetricsCollector) complete(id NodeId) {
+	if report := lc.current[id]; report != nil {
+		lc.reports[id] = append(lc.reports[id], report)
+		delete(lc.current, id)
+	}
+}
+
+// take returns the complete reports and discards them from the collector.
+func (lc *linkMetricsCollector) take() map[NodeId][]*linkMetricsReport {
+	lc.Lock()
+	defer lc.Unlock()
+
+	reports := lc.reports
+	lc.reports = map[NodeId][]*linkMetricsReport{}
+	return reports
+}
+
+func (lc *linkMetricsCollector) reset() {
+	lc.Lock()
+	defer lc.Unlock()
+
+	lc.current = map[NodeId]*linkMetricsReport{}
+	lc.reports = map[NodeId][]*linkMetricsReport{}
+}
+
+// StartLinkMetrics starts probing the Link Metrics of the link from the source node to the neighbor at the interval
+// (in us). The reports are recorded in the dispatcher as the Link Metrics series of the node pair.
+func (s *Simulation) StartLinkMetrics(src NodeId, dst NodeId, interval uint64) error {
+	if s.nodes[src] == nil {
+		return errors.Errorf("node %d not found", src)
+	}
+	dstnode := s.nodes[dst]
+	if dstnode == nil || dst == src {
+		return errors.Errorf("invalid neighbor node: %d", dst)
+	}
+	if interval == 0 {
+		return errors.Errorf("invalid interval: %d", interval)
+	}
+
+	result := s.ExecCommand([]NodeId{dst}, "ipaddr linklocal", DefaultCommandTimeout)[0]
+	if result.Error != "" {
+		return errors.Errorf("node %d: ipaddr linklocal failed: %s", dst, result.Error)
+	}
+	addrs := result.Output
+	if len(addrs) == 0 {
+		return errors.Errorf("node %d has no link-local address", dst)
+	}
+
+	if s.linkProbes == nil {
+		s.linkProbes = map[linkMetricsPair]*LinkMetricsProbe{}
+	}
+	probe := &LinkMetricsProbe{Src: src, Dst: dst, Interval: interval, Addr: strings.TrimSpace(addrs[0])}
+	s.linkProbes[linkMetricsPair{src, dst}] = probe
+	s.probeLinkMetrics(probe)
+	return nil
+}
+
+// StopLinkMetrics stops probing the Link Metrics of the link from the source node to the neighbor.
+func (s *Simulation) StopLinkMetrics(src NodeId, dst NodeId) error {
+	pair := linkMetricsPair{src, dst}
+	if s.linkProbes[pair] == nil {
+		return errors.Errorf("link %d->%d not probed", src, dst)
+	}
+
+	s.CollectLinkMetrics()
+	delete(s.linkProbes, pair)
+	return nil
+}
+
+// StopAllLinkMetrics stops all Link Metrics probes.
+func (s *Simulation) StopAllLinkMetrics() {
+	s.CollectLinkMetrics()
+	s.linkProbes = nil
+}
+
+// LinkMetricsProbes returns the running Link Metrics probes ordered by node pair.
+func (s *Simulation) LinkMetricsProbes() []*LinkMetricsProbe {
+	probes := make([]*LinkMetricsProbe, 0, len(s.linkProbes))
+	for _, probe := range s.linkProbes {
+		probes = append(probes, probe)
+	}
+	sort.Slice(probes, func(i, j int) bool {
+		if probes[i].Src != probes[j].Src {
+			return probes[i].Src < probes[j].Src
+		}
+		return probes[i].Dst < probes[j].Dst
+	})
+	return probes
+}
+
+// CollectLinkMetrics records the Link Metrics reports received by the probing nodes so far in the dispatcher. Reports
+// of links which are not probed, e.g. of queries by user commands, are discarded.
+func (s *Simulation) CollectLinkMetrics() {
+	for src, reports := range s.linkMetrics.take() {
+		for _, report := range reports {
+			addr := net.ParseIP(report.addr)
+			for _, probe := range s.linkProbes {
+				if probe.Src == src && addr.Equal(net.ParseIP(probe.Addr)) {
+					s.d.AddLinkMetrics(src, probe.Dst, report.sample)
+					break
+				}
+			}
+		}
+	}
+}
+
+// probeLinkMetrics collects the reports so far, sends a single probe query and schedules the next one, until the
+// probe is stopped.
+func (s *Simulation) probeLinkMetrics(probe *LinkMetricsProbe) {
+	if s.linkProbes[linkMetricsPair{probe.Src, probe.Dst}] != probe {
+		return
+	}
+
+	s.CollectLinkMetrics()
+	if s.nodes[probe.Src] == nil || s.nodes[probe.Dst] == nil {
+		delete(s.linkProbes, linkMetricsPair{probe.Src, probe.Dst})
+		return
+	}
+
+	if dnode := s.d.GetNode(probe.Src); !dnode.IsPaused() && !dnode.IsFailed() {
+		// the node may not support Link Metrics, or the neighbor may be unreachable
+		cmd := fmt.Sprintf("linkmetrics query %s single pqmr", probe.Addr)
+		if result := s.ExecCommand([]NodeId{probe.Src}, cmd, DefaultCommandTimeout)[0]; result.Error != "" {
+			simplelogger.Debugf("node %d: link metrics query failed: %s", probe.Src, result.Error)
+		}
+	}
+
+	s.d.ScheduleAt(s.d.CurTime+probe.Interval, func() {
+		s.probeLinkMetrics(probe)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const linkMetricsTestAddr = "fe80:0:0:0:3092:f334:1455:1ad2"
+
+// output of `linkmetrics query <addr> single pqmr` on the OT CLI
+var linkMetricsTestReport = []string{
+	"Received Link Metrics Report from: " + linkMetricsTestAddr,
+	"",
+	" - PDU Counter: 2 (Count/Summation)",
+	" - LQI: 76 (Exponential Moving Average)",
+	" - Margin: 82 (dB) (Exponential Moving Average)",
+	" - RSSI: -18 (dBm) (Exponential Moving Average)",
+}
+
+func TestLinkMetricsCollector(t *testing.T) {
+	lc := newLinkMetricsCollector()
+	for _, line := range linkMetricsTestReport {
+		lc.onNodeOutput(1, 1000, line+"\n")
+	}
+
+	reports := lc.take()
+	assert.Equal(t, 1, len(reports[1]))
+	assert.Equal(t, linkMetricsTestAddr, reports[1][0].addr)
+	assert.Equal(t, dispatcher.LinkMetricsSample{Time: 1000, Pdus: 2, Lqi: 76, Margin: 82, Rssi: -18},
+		reports[1][0].sample)
+	assert.Empty(t, lc.take())
+}
+
+func TestLinkMetricsCollectorPartialReports(t *testing.T) {
+	lc := newLinkMetricsCollector()
+
+	// values without a report header are ignored
+	lc.onNodeOutput(1, 500, " - RSSI: -40 (dBm) (Exponential Moving Average)")
+	assert.Empty(t, lc.take())
+
+	// a report without RSSI is completed by the next report
+	lc.onNodeOutput(1, 1000, linkMetricsTestReport[0])
+	lc.onNodeOutput(1, 1000, " - LQI: 10 (Exponential Moving Average)")
+	assert.Empty(t, lc.take())
+	for _, line := range linkMetricsTestReport {
+		lc.onNodeOutput(1, 2000, line)
+	}
+
+	reports := lc.take()
+	assert.Equal(t, 2, len(reports[1]))
+	assert.Equal(t, dispatcher.LinkMetricsSample{Time: 1000, Lqi: 10}, reports[1][0].sample)
+	assert.Equal(t, uint64(2000), reports[1][1].sample.Time)
+	assert.Equal(t, -18, reports[1][1].sample.Rssi)
+}
+
+func TestLinkMetricsCollectorNodes(t *testing.T) {
+	lc := newLinkMetricsCollector()
+
+	// reports printed by several nodes at once are collected per node
+	for i, line := range linkMetricsTestReport {
+		lc.onNodeOutput(1, 1000, line)
+		if i < 3 {
+			lc.onNodeOutput(2, 1000, line)
+		}
+	}
+
+	reports := lc.take()
+	assert.Equal(t, 1, len(reports[1]))
+	assert.Nil(t, reports[NodeId(2)])
+
+	lc.reset()
+	lc.onNodeOutput(2, 2000, linkMetricsTestReport[5])
+	assert.Empty(t, lc.take())
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// parseMacCounters parses the MAC retry and CCA failure counters from the output of `counters mac`.
+func parseMacCounters(output []string) (counters dispatcher.MacCounters) {
+	for _, line := range output {
+		name, val, ok := parseCounterLine(line)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "TxRetry":
+			counters.TxRetry = val
+		case "TxErrCca":
+			counters.TxErrCca = val
+		}
+	}
+	return
+}
+
+// parseCounterLine parses a counter line such as `    TxRetry: 3`.
+func parseCounterLine(line string) (name string, val uint64, ok bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return
+	}
+
+	val, err := strconv.ParseUint(strings.TrimSpace(line[idx+1:]), 10, 64)
+	if err != nil {
+		return
+	}
+	return strings.TrimSpace(line[:idx]), val, true
+}
+
+// CollectMacCounters samples the MAC counters of all running nodes and records them in the dispatcher, which
+// attributes the increments since the previous sample to the current time window and the KPI.
+func (s *Simulation) CollectMacCounters() {
+	var ids []NodeId
+	s.VisitNodesInOrder(func(node *Node) {
+		if dnode := s.d.GetNode(node.Id); dnode != nil && !dnode.IsPaused() && !dnode.IsFailed() {
+			ids = append(ids, node.Id)
+		}
+	})
+
+	for _, result := range s.ExecCommand(ids, "counters mac", DefaultCommandTimeout) {
+		if result.Error != "" {
+			simplelogger.Warnf("node %d: collect MAC counters failed: %s", result.Node, result.Error)
+			continue
+		}
+		s.d.SetMacCounters(result.Node, parseMacCounters(result.Output))
+	}
+}
+
+// SetWindowStatsConfig reconfigures the time window statistics, discarding all collected windows.
+func (s *Simulation) SetWindowStatsConfig(cfg dispatcher.WindowStatsConfig) {
+	s.d.SetWindowStatsConfig(cfg)
+	s.startMacCounterSampling()
+}
+
+// startMacCounterSampling samples the MAC counters of the nodes just before each time window closes, so that the
+// increments are attributed to the window in which they happened, and not to the window in which the counters are
+// shown. Sampling runs while the retries or cca metric is enabled, and restarts when the windows are reconfigured.
+func (s *Simulation) startMacCounterSampling() {
+	s.macSampling++
+	if !s.d.GetWindowStatsConfig().Metrics.Has(dispatcher.WindowMetricRetries | dispatcher.WindowMetricCca) {
+		return
+	}
+	s.sampleMacCountersAt(s.macSampling, s.d.GetCurrentWindowEnd())
+}
+
+func (s *Simulation) sampleMacCountersAt(generation int, windowEnd uint64) {
+	s.d.ScheduleAt(windowEnd-1, func() {
+		if generation != s.macSampling {
+			return
+		}
+
+		s.CollectMacCounters()
+		s.sampleMacCountersAt(generation, windowEnd+s.d.GetWindowStatsConfig().Interval)
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// NetworkData is the normalized Thread Network Data of a node: the entries of each section are sorted.
+type NetworkData struct {
+	Prefixes []string `json:"prefixes"`
+	Routes   []string `json:"routes"`
+	Services []string `json:"services"`
+}
+
+// entries returns all entries, each prefixed by its type.
+func (nd *NetworkData) entries() []string {
+	var entries []string
+	for _, prefix := range nd.Prefixes {
+		entries = append(entries, "prefix "+prefix)
+	}
+	for _, route := range nd.Routes {
+		entries = append(entries, "route "+route)
+	}
+	for _, service := range nd.Services {
+		entries = append(entries, "service "+service)
+	}
+	return entries
+}
+
+// NetworkDataView is the Network Data and leader state of a router, compared with the view of the leader of its
+// partition.
+type NetworkDataView struct {
+	Node              NodeId `json:"node"`
+	Role              string `json:"role"`
+	PartitionId       uint32 `json:"partition_id"`
+	DataVersion       int    `json:"data_version"`
+	StableDataVersion int    `json:"stable_data_version"`
+	LeaderRouterId    int    `json:"leader_router_id"`
+	NetworkData
+	// Missing lists the entries of the leader view missing in this view.
+	Missing []string `json:"missing,omitempty"`
+	// Stale lists the entries of this view not in the leader view.
+	Stale []string `json:"stale,omitempty"`
+}
+
+// IsConsistent returns if the view has the same entries as the leader view.
+func (v *NetworkDataView) IsConsistent() bool {
+	return len(v.Missing) == 0 && len(v.Stale) == 0
+}
+
+func (node *Node) GetNetworkData() NetworkData {
+	return parseNetworkData(node.Command("netdata show", DefaultCommandTimeout))
+}
+
+// parseNetworkData parses the output of `netdata show`, which lists the entries under section lines such as
+// `Prefixes:`. Sections other than prefixes, routes and services are ignored.
+func parseNetworkData(output []string) NetworkData {
+	var nd NetworkData
+	var section *[]string
+
+	for _, line := range output {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasSuffix(line, ":") && !strings.Contains(line, " ") {
+			switch line {
+			case "Prefixes:":
+				section = &nd.Prefixes
+			case "Routes:":
+				section = &nd.Routes
+			case "Services:":
+				section = &nd.Services
+			default:
+				section = nil
+			}
+			continue
+		}
+
+		if section != nil {
+			*section = append(*section, strings.Join(strings.Fields(line), " "))
+		}
+	}
+
+	sort.Strings(nd.Prefixes)
+	sort.Strings(nd.Routes)
+	sort.Strings(nd.Services)
+	return nd
+}
+
+// diffEntries returns the entries of a missing in b, and the entries of b missing in a.
+func diffEntries(a, b []string) (onlyA, onlyB []string) {
+	inA := map[string]struct{}{}
+	for _, entry := range a {
+		inA[entry] = struct{}{}
+	}
+	inB := map[string]struct{}{}
+	for _, entry := range b {
+		inB[entry] = struct{}{}
+		if _, ok := inA[entry]; !ok {
+			onlyB = append(onlyB, entry)
+		}
+	}
+	for _, entry := range a {
+		if _, ok := inB[entry]; !ok {
+			onlyA = append(onlyA, entry)
+		}
+	}
+	return
+}
+
+// CollectNetworkData queries the Network Data and leader data of all routers and leaders, and compares the view of each
+// router with the view of the leader of its partition. Paused nodes are skipped.
+func (s *Simulation) CollectNetworkData() []*NetworkDataView {
+	var views []*NetworkDataView
+	leaders := map[uint32]*NetworkDataView{}
+
+	s.VisitNodesInOrder(func(node *Node) {
+		dnode := s.d.GetNode(node.Id)
+		if dnode == nil || dnode.IsPaused() || dnode.IsFailed() ||
+			(dnode.Role != OtDeviceRoleRouter && dnode.Role != OtDeviceRoleLeader) {
+			return
+		}
+
+		defer func() {
+			if err := recover(); err != nil {
+				simplelogger.Warnf("%v - collect network data failed: %v", node, err)
+			}
+		}()
+
+		leaderData := node.GetLeaderData()
+		view := &NetworkDataView{
+			Node:              node.Id,
+			Role:              dnode.Role.String(),
+			PartitionId:       dnode.PartitionId,
+			DataVersion:       leaderData.DataVersion,
+			StableDataVersion: leaderData.StableDataVersion,
+			LeaderRouterId:    leaderData.LeaderRouterID,
+			NetworkData:       node.GetNetworkData(),
+		}
+		views = append(views, view)
+		if dnode.Role == OtDeviceRoleLeader {
+			leaders[view.PartitionId] = view
+		}
+	})
+
+	for _, view := range views {
+		leader := leaders[view.PartitionId]
+		if leader == nil || leader == view {
+			continue
+		}
+
+		view.Missing, view.Stale = diffEntries(leader.entries(), view.entries())
+	}
+
+	return views
+}
@@ -0,0 +1,328 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/openthread/ot-ns/threadconst"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// Network diagnostic TLV types queried by default: extended address, RLOC16, MAC counters, battery level, supply
+// voltage and child table.
+var DefaultNetDiagTlvs = []int{0, 1, 9, 14, 15, 16}
+
+var (
+	// e.x. DIAG_GET.rsp/ans from fdde:ad00:beef:0:0:ff:fe00:5400: 00086e2e5a2a0a8fd0a5
+	netDiagReplyRegexp = regexp.MustCompile(`^DIAG_GET\.rsp/ans(?: from (\S+))?: ?[0-9a-fA-F]*$`)
+)
+
+// NetDiagReply is the network diagnostic reply of a node. Tlvs contains the TLVs as parsed by the OT CLI: values are
+// numbers or strings, sections are objects, and tables are lists.
+type NetDiagReply struct {
+	Node    NodeId                 `json:"node"`
+	Address string                 `json:"address"`
+	Tlvs    map[string]interface{} `json:"tlvs"`
+	Lines   []string               `json:"-"` // output lines of the TLVs
+}
+
+// NetDiagSweep is the inventory collected by a network diagnostic sweep from a source node to all other nodes.
+type NetDiagSweep struct {
+	Source   NodeId          `json:"source"`
+	TlvTypes []int           `json:"tlv_types"`
+	Replies  []*NetDiagReply `json:"replies"`
+	Missing  []NodeId        `json:"missing"` // nodes which did not reply
+}
+
+// netDiagCollector collects the network diagnostic replies received by source nodes of sweeps. Replies are reported
+// from the node output routines, so the collector is protected by a lock.
+type netDiagCollector struct {
+	sync.Mutex
+	sweeps map[NodeId]*netDiagSweepState
+}
+
+type netDiagSweepState struct {
+	sweep   NetDiagSweep
+	targets map[string]NodeId // RLOC address of each queried node
+	current *NetDiagReply
+}
+
+func newNetDiagCollector() *netDiagCollector {
+	return &netDiagCollector{sweeps: map[NodeId]*netDiagSweepState{}}
+}
+
+func (nc *netDiagCollector) onNodeOutput(id NodeId, line string) {
+	nc.Lock()
+	defer nc.Unlock()
+
+	state := nc.sweeps[id]
+	if state == nil {
+		return
+	}
+
+	if m := netDiagReplyRegexp.FindStringSubmatch(line); m != nil {
+		state.current = &NetDiagReply{Node: InvalidNodeId, Address: normalizeIp6(m[1])}
+		state.sweep.Replies = append(state.sweep.Replies, state.current)
+		return
+	}
+
+	if state.current == nil {
+		return
+	}
+	if text := strings.TrimSpace(line); text == "" || (netDiagIndent(line) == 0 && !strings.Contains(text, ":")) {
+		// the reply ends at the next output which is not a TLV, e.g. `Done`
+		state.current = nil
+		return
+	}
+	state.current.Lines = append(state.current.Lines, line)
+}
+
+func trimmedLine(line string) string {
+	return strings.TrimRight(line, " \r")
+}
+
+func normalizeIp6(addr string) string {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String()
+	}
+	return addr
+}
+
+// StartNetDiagSweep sends network diagnostic GET queries of the TLV types from the source node to the RLOC addresses
+// of all other nodes, and starts collecting the replies. The simulation must run for the replies to arrive, until
+// FinishNetDiagSweep. Failed and paused nodes are skipped.
+func (s *Simulation) StartNetDiagSweep(src NodeId, tlvTypes []int) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = errors.Errorf("%v", e)
+			s.netDiag.stop(src)
+		}
+	}()
+
+	srcnode := s.nodes[src]
+	if srcnode == nil {
+		return errors.Errorf("node %d not found", src)
+	}
+	if len(tlvTypes) == 0 {
+		tlvTypes = DefaultNetDiagTlvs
+	}
+
+	rlocs := srcnode.GetIpAddrRloc()
+	if len(rlocs) == 0 {
+		return errors.Errorf("node %d has no RLOC address", src)
+	}
+	prefix := net.ParseIP(strings.TrimSpace(rlocs[0]))
+	if prefix == nil {
+		return errors.Errorf("invalid RLOC address: %s", rlocs[0])
+	}
+
+	state := &netDiagSweepState{
+		sweep:   NetDiagSweep{Source: src, TlvTypes: tlvTypes, Replies: []*NetDiagReply{}},
+		targets: map[string]NodeId{},
+	}
+	s.VisitNodesInOrder(func(node *Node) {
+		dnode := s.d.GetNode(node.Id)
+		if node.Id == src || dnode == nil || dnode.IsPaused() || dnode.IsFailed() ||
+			dnode.Rloc16 == threadconst.InvalidRloc16 {
+			return
+		}
+		addr := make(net.IP, net.IPv6len)
+		copy(addr, prefix)
+		addr[14], addr[15] = byte(dnode.Rloc16>>8), byte(dnode.Rloc16)
+		state.targets[addr.String()] = node.Id
+	})
+	if len(state.targets) == 0 {
+		return errors.Errorf("no nodes to query")
+	}
+
+	s.netDiag.Lock()
+	s.netDiag.sweeps[src] = state
+	s.netDiag.Unlock()
+
+	tlvs := make([]string, len(tlvTypes))
+	for i, typ := range tlvTypes {
+		tlvs[i] = strconv.Itoa(typ)
+	}
+	var addrs []string
+	for addr := range state.targets {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		srcnode.Command(fmt.Sprintf("networkdiagnostic get %s %s", addr, strings.Join(tlvs, " ")),
+			DefaultCommandTimeout)
+	}
+	return nil
+}
+
+// FinishNetDiagSweep stops collecting the replies of the sweep of the source node, and returns the inventory.
+func (s *Simulation) FinishNetDiagSweep(src NodeId) (*NetDiagSweep, error) {
+	state := s.netDiag.stop(src)
+	if state == nil {
+		return nil, errors.Errorf("no sweep from node %d", src)
+	}
+
+	sweep := &state.sweep
+	replied := map[NodeId]struct{}{}
+	for _, reply := range sweep.Replies {
+		reply.Tlvs = parseNetDiag(reply.Lines)
+		if id, ok := state.targets[reply.Address]; ok {
+			reply.Node = id
+		} else if rloc16, ok := reply.Tlvs["Rloc16"].(string); ok {
+			reply.Node = s.nodeByRloc16(rloc16)
+		}
+		replied[reply.Node] = struct{}{}
+	}
+	sort.SliceStable(sweep.Replies, func(i, j int) bool {
+		return sweep.Replies[i].Node < sweep.Replies[j].Node
+	})
+
+	sweep.Missing = []NodeId{}
+	for _, id := range state.targets {
+		if _, ok := replied[id]; !ok {
+			sweep.Missing = append(sweep.Missing, id)
+		}
+	}
+	sort.Ints(sweep.Missing)
+	return sweep, nil
+}
+
+func (nc *netDiagCollector) reset() {
+	nc.Lock()
+	defer nc.Unlock()
+
+	nc.sweeps = map[NodeId]*netDiagSweepState{}
+}
+
+func (nc *netDiagCollector) stop(src NodeId) *netDiagSweepState {
+	nc.Lock()
+	defer nc.Unlock()
+
+	state := nc.sweeps[src]
+	delete(nc.sweeps, src)
+	return state
+}
+
+func (s *Simulation) nodeByRloc16(rloc16 string) NodeId {
+	val, err := strconv.ParseUint(strings.TrimPrefix(rloc16, "0x"), 16, 16)
+	if err != nil {
+		return InvalidNodeId
+	}
+	for id := range s.nodes {
+		if dnode := s.d.GetNode(id); dnode != nil && dnode.Rloc16 == uint16(val) {
+			return id
+		}
+	}
+	return InvalidNodeId
+}
+
+// parseNetDiag parses the TLVs of a network diagnostic reply as printed by the OT CLI: `Key: value` lines, sections
+// of indented lines under `Key:` lines, and tables of `- ` items.
+func parseNetDiag(lines []string) map[string]interface{} {
+	lines = append([]string{}, lines...)
+	i := 0
+	return parseNetDiagBlock(lines, &i, 0)
+}
+
+func netDiagIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func parseNetDiagBlock(lines []string, i *int, indent int) map[string]interface{} {
+	block := map[string]interface{}{}
+	for *i < len(lines) {
+		line := trimmedLine(lines[*i])
+		text := strings.TrimSpace(line)
+		if netDiagIndent(line) < indent || strings.HasPrefix(text, "- ") {
+			break
+		}
+		*i++
+
+		key, val := text, ""
+		if idx := strings.Index(text, ": "); idx >= 0 {
+			key, val = text[:idx], strings.TrimSpace(text[idx+2:])
+		} else {
+			key = strings.TrimSuffix(text, ":")
+		}
+		if val != "" {
+			block[key] = parseNetDiagValue(val)
+			continue
+		}
+
+		// a section or a table of the following indented lines
+		if *i < len(lines) && netDiagIndent(lines[*i]) > netDiagIndent(line) {
+			next := lines[*i]
+			if strings.HasPrefix(strings.TrimSpace(next), "- ") {
+				block[key] = parseNetDiagTable(lines, i, netDiagIndent(next))
+			} else {
+				block[key] = parseNetDiagBlock(lines, i, netDiagIndent(next))
+			}
+		} else {
+			block[key] = map[string]interface{}{}
+		}
+	}
+	return block
+}
+
+func parseNetDiagTable(lines []string, i *int, indent int) []interface{} {
+	table := []interface{}{}
+	for *i < len(lines) && netDiagIndent(lines[*i]) == indent {
+		text := strings.TrimSpace(lines[*i])
+		if !strings.HasPrefix(text, "- ") {
+			break
+		}
+
+		item := strings.TrimSpace(text[2:])
+		if !strings.Contains(item, ": ") && !strings.HasSuffix(item, ":") {
+			// e.g. an IPv6 address
+			table = append(table, parseNetDiagValue(item))
+			*i++
+			continue
+		}
+		// the item continues with the lines indented like its first field
+		lines[*i] = strings.Repeat(" ", indent+2) + item
+		table = append(table, parseNetDiagBlock(lines, i, indent+2))
+	}
+	return table
+}
+
+func parseNetDiagValue(val string) interface{} {
+	val = strings.Trim(val, "'\"")
+	if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return n
+	}
+	return val
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// output of `networkdiagnostic get fdde:ad00:beef:0:0:ff:fe00:400 0 1 5 6 9 14 15 16 8` on the OT CLI
+var netDiagOutput = []string{
+	"Ext Address: 3e7d9a2bd44e5c21",
+	"Rloc16: 0x0400",
+	"Mode:",
+	"    RxOnWhenIdle: 1",
+	"    DeviceType: 1",
+	"    NetworkData: 1",
+	"Leader Data:",
+	"    PartitionId: 0x5a2b1c3d",
+	"    Weighting: 64",
+	"    DataVersion: 138",
+	"    StableDataVersion: 201",
+	"    LeaderRouterId: 1",
+	"Network Data: 08040b02174703140040fd00",
+	"MAC Counters:",
+	"    IfInUnknownProtos: 0",
+	"    IfInErrors: 0",
+	"    IfOutErrors: 2",
+	"    IfInUcastPkts: 31",
+	"Battery Level: 100%",
+	"Supply Voltage: 3300mV",
+	"Child Table:",
+	"    - ChildId: 1",
+	"      Timeout: 240",
+	"      Link Quality: 3",
+	"      Mode:",
+	"        RxOnWhenIdle: 0",
+	"        DeviceType: 0",
+	"        NetworkData: 0",
+	"    - ChildId: 2",
+	"      Timeout: 240",
+	"      Link Quality: 2",
+	"IP6 Address List:",
+	"    - fdde:ad00:beef:0:0:ff:fe00:400",
+	"    - fe80:0:0:0:3c7d:9a2b:d44e:5c21",
+}
+
+func TestParseNetDiag(t *testing.T) {
+	tlvs := parseNetDiag(netDiagOutput)
+	assert.Equal(t, map[string]interface{}{
+		"Ext Address": "3e7d9a2bd44e5c21",
+		"Rloc16":      "0x0400",
+		"Mode":        map[string]interface{}{"RxOnWhenIdle": int64(1), "DeviceType": int64(1), "NetworkData": int64(1)},
+		"Leader Data": map[string]interface{}{
+			"PartitionId":       "0x5a2b1c3d",
+			"Weighting":         int64(64),
+			"DataVersion":       int64(138),
+			"StableDataVersion": int64(201),
+			"LeaderRouterId":    int64(1),
+		},
+		"Network Data": "08040b02174703140040fd00",
+		"MAC Counters": map[string]interface{}{
+			"IfInUnknownProtos": int64(0),
+			"IfInErrors":        int64(0),
+			"IfOutErrors":       int64(2),
+			"IfInUcastPkts":     int64(31),
+		},
+		"Battery Level":  "100%",
+		"Supply Voltage": "3300mV",
+		"Child Table": []interface{}{
+			map[string]interface{}{
+				"ChildId":      int64(1),
+				"Timeout":      int64(240),
+				"Link Quality": int64(3),
+				"Mode": map[string]interface{}{
+					"RxOnWhenIdle": int64(0), "DeviceType": int64(0), "NetworkData": int64(0),
+				},
+			},
+			map[string]interface{}{"ChildId": int64(2), "Timeout": int64(240), "Link Quality": int64(2)},
+		},
+		"IP6 Address List": []interface{}{"fdde:ad00:beef:0:0:ff:fe00:400", "fe80:0:0:0:3c7d:9a2b:d44e:5c21"},
+	}, tlvs)
+
+	// the lines are not modified by parsing the tables
+	assert.Equal(t, "    - ChildId: 1", netDiagOutput[21])
+	assert.Equal(t, map[string]interface{}{}, parseNetDiag(nil))
+}
+
+func TestParseNetDiagTruncated(t *testing.T) {
+	for _, tc := range []struct {
+		lines []string
+		tlvs  map[string]interface{}
+	}{
+		// a section or table without lines is empty
+		{lines: []string{"Rloc16: 0x0400", "Child Table:"},
+			tlvs: map[string]interface{}{"Rloc16": "0x0400", "Child Table": map[string]interface{}{}}},
+		{lines: []string{"Mode:", "Rloc16: 0x0400"},
+			tlvs: map[string]interface{}{"Mode": map[string]interface{}{}, "Rloc16": "0x0400"}},
+		// a table item cut after its first field
+		{lines: []string{"Child Table:", "    - ChildId: 1"},
+			tlvs: map[string]interface{}{"Child Table": []interface{}{map[string]interface{}{"ChildId": int64(1)}}}},
+		// a nested section cut after its header
+		{lines: []string{"Child Table:", "    - ChildId: 1", "      Mode:"},
+			tlvs: map[string]interface{}{"Child Table": []interface{}{
+				map[string]interface{}{"ChildId": int64(1), "Mode": map[string]interface{}{}}}}},
+		// a line cut before its value, and a line without a separator
+		{lines: []string{"Battery Level:", "Supply Voltage"},
+			tlvs: map[string]interface{}{"Battery Level": map[string]interface{}{},
+				"Supply Voltage": map[string]interface{}{}}},
+		// an indented line at the start belongs to the top level
+		{lines: []string{"    DeviceType: 1", "Rloc16: 0x0400"},
+			tlvs: map[string]interface{}{"DeviceType": int64(1), "Rloc16": "0x0400"}},
+	} {
+		assert.Equal(t, tc.tlvs, parseNetDiag(tc.lines), "%v", tc.lines)
+	}
+}
+
+func TestParseNetDiagTable(t *testing.T) {
+	for _, tc := range []struct {
+		lines []string
+		table []interface{}
+		next  int // index of the first line after the table
+	}{
+		{lines: []string{"  - 1", "  - '2'", "  - x"}, table: []interface{}{int64(1), int64(2), "x"}, next: 3},
+		{lines: []string{"  - a: 1", "    b: 2", "  - a: 3", "Done"},
+			table: []interface{}{map[string]interface{}{"a": int64(1), "b": int64(2)}, map[string]interface{}{"a": int64(3)}},
+			next:  3},
+		// the table ends at a line with another indentation or which is not an item
+		{lines: []string{"  - 1", "    - 2"}, table: []interface{}{int64(1)}, next: 1},
+		{lines: []string{"  - 1", "  x: 2"}, table: []interface{}{int64(1)}, next: 1},
+		{lines: []string{"  - a:", "      b: 1"},
+			table: []interface{}{map[string]interface{}{"a": map[string]interface{}{"b": int64(1)}}}, next: 2},
+		{lines: nil, table: []interface{}{}, next: 0},
+	} {
+		i := 0
+		assert.Equal(t, tc.table, parseNetDiagTable(tc.lines, &i, 2), "%v", tc.lines)
+		assert.Equal(t, tc.next, i, "%v", tc.lines)
+	}
+}
+
+func TestParseNetDiagBlock(t *testing.T) {
+	lines := []string{"  a: 1", "  b:", "    c: 2", "d: 3"}
+	i := 0
+	assert.Equal(t, map[string]interface{}{"a": int64(1), "b": map[string]interface{}{"c": int64(2)}},
+		parseNetDiagBlock(lines, &i, 2))
+	assert.Equal(t, 3, i)
+	assert.Equal(t, map[string]interface{}{"d": int64(3)}, parseNetDiagBlock(lines, &i, 0))
+	assert.Equal(t, 4, i)
+}
+
+func TestParseNetDiagValue(t *testing.T) {
+	for _, tc := range []struct {
+		val    string
+		parsed interface{}
+	}{
+		{"0", int64(0)},
+		{"-12", int64(-12)},
+		{"'42'", int64(42)},
+		{"\"abc\"", "abc"},
+		{"0x0400", "0x0400"},
+		{"3300mV", "3300mV"},
+		{"9223372036854775808", "9223372036854775808"},
+		{"", ""},
+	} {
+		assert.Equal(t, tc.parsed, parseNetDiagValue(tc.val), "%#v", tc.val)
+	}
+}
@@ -34,16 +34,18 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/openthread/ot-ns/threadconst"
-
+	"github.com/openthread/ot-ns/dispatcher"
 	"github.com/openthread/ot-ns/otoutfilter"
 	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
@@ -64,30 +66,66 @@ const (
 )
 
 func newNode(s *Simulation, id NodeId, cfg *NodeConfig) (*Node, error) {
-	var err error
-
-	if !cfg.Restore {
-		portOffset := (s.cfg.DispatcherPort - threadconst.InitialDispatcherPort) / threadconst.WellKnownNodeId
-		flashFile := fmt.Sprintf("tmp/%d_%d.flash", portOffset, id)
-		if err := os.RemoveAll(flashFile); err != nil {
-			simplelogger.Errorf("Remove flash file %s failed: %+v", flashFile, err)
-		}
+	// each node runs in its own directory, so that the files of nodes never clobber each other
+	dir, err := s.prepareNodeDir(id, cfg.Restore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "prepare directory of node %d failed", id)
 	}
 
 	otCliPath := s.cfg.OtCliPath
 	if cfg.ExecutablePath != "" {
 		otCliPath = cfg.ExecutablePath
 	}
-	simplelogger.Debugf("node exe path: %s", otCliPath)
-	cmd := exec.CommandContext(context.Background(), otCliPath, strconv.Itoa(id))
+	if strings.ContainsRune(otCliPath, filepath.Separator) && s.cfg.Container == nil {
+		// relative paths would be resolved in the node directory
+		if otCliPath, err = filepath.Abs(otCliPath); err != nil {
+			return nil, err
+		}
+	}
+	simplelogger.Debugf("node exe path: %s, dir: %s", otCliPath, dir)
+	// nodes find the dispatcher by the port offset, which differs between simulations in the same process
+	env := []string{fmt.Sprintf("PORT_OFFSET=%d", s.PortOffset())}
+	var mounts []string
+	if s.cfg.SharedMemory {
+		shmPath, err := s.d.PrepareSharedMemory(id)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, fmt.Sprintf("%s=%s", dispatcher.ShmEnvVar, shmPath))
+		mounts = append(mounts, shmPath)
+	}
+	if tcpAddr := s.d.TcpAddr(); tcpAddr != "" {
+		env = append(env, fmt.Sprintf("%s=%s", dispatcher.TcpEnvVar, tcpAddr))
+	}
+	if s.cfg.CoverageDir != "" {
+		env = append(env, s.coverageEnv(id)...)
+		mounts = append(mounts, s.cfg.CoverageDir)
+	}
+
+	var cmd *exec.Cmd
+	if s.cfg.Container != nil {
+		if cmd, err = s.containerCommand(id, cfg, otCliPath, dir, env, mounts); err != nil {
+			return nil, err
+		}
+	} else {
+		cmd = exec.CommandContext(context.Background(), otCliPath, strconv.Itoa(id))
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	node := &Node{
 		S:            s,
 		Id:           id,
 		cfg:          cfg,
 		cmd:          cmd,
+		executable:   otCliPath,
 		pendingLines: make(chan string, 100),
 		uartType:     NodeUartTypeUndefined,
+		stderr:       stderrTail{done: make(chan struct{})},
+		exited:       make(chan struct{}),
+	}
+	if s.cfg.Container != nil {
+		node.container = s.containerName(id)
 	}
 
 	node.virtualUartReader, node.virtualUartPipe = io.Pipe()
@@ -107,11 +145,20 @@ func newNode(s *Simulation, id NodeId, cfg *NodeConfig) (*Node, error) {
 
 	err = cmd.Start()
 	if err != nil {
-		return nil, err
+		return nil, diagnoseStartError(id, otCliPath, s.containerRuntime(), err)
+	}
+
+	if s.cfg.Transcript {
+		transcriptFile := filepath.Join(s.NodeDir(id), transcriptFileName)
+		// a restored node continues the transcript of the node it replaces
+		if node.transcript, err = newTranscript(transcriptFile, cfg.Restore); err != nil {
+			simplelogger.Errorf("%v - create transcript %s failed: %v", node, transcriptFile, err)
+		}
 	}
 
 	go node.lineReader(node.pipeOut, NodeUartTypeRealTime)
 	go node.lineReader(node.virtualUartReader, NodeUartTypeVirtualTime)
+	go node.stderrReader()
 	return node, nil
 }
 
@@ -130,14 +177,43 @@ type Node struct {
 	virtualUartReader *io.PipeReader
 	virtualUartPipe   *io.PipeWriter
 	uartType          NodeUartType
+	stopped           bool
+	udpPort           int
+	logSeq            uint64
+	transcript        *transcript
+	uartTime          uint64 // virtual time of the latest UART input or output, accessed atomically
+	exiting           int32  // set when the node is asked to exit, accessed atomically
+	exitErr           error
+	exited            chan struct{} // closed when the output of the node process is closed
+	stderr            stderrTail
+	restarts          int
+	resources         resourceSampler
+	container         string // name of the container running the node, or "" for a host process
+	executable        string // executable of the node, in the container if any
+	lateCommands      int    // commands which exceeded the command budget, whose remaining output is discarded
 }
 
 func (node *Node) String() string {
 	return fmt.Sprintf("Node<%d>", node.Id)
 }
 
+// LogSeq returns the sequence number of the last log of the node, if log correlation is enabled.
+func (node *Node) LogSeq() uint64 {
+	return atomic.LoadUint64(&node.logSeq)
+}
+
 func (node *Node) SetupNetworkParameters(sim *Simulation) {
-	node.ConfigActiveDataset(node.S.Channel(), node.S.NetworkKey(), node.S.Panid())
+	channel, networkkey, panid := node.S.Channel(), node.S.NetworkKey(), node.S.Panid()
+	if node.cfg.Channel != 0 {
+		channel = node.cfg.Channel
+	}
+	if node.cfg.NetworkKey != "" {
+		networkkey = node.cfg.NetworkKey
+	}
+	if node.cfg.Panid != 0 {
+		panid = node.cfg.Panid
+	}
+	node.ConfigActiveDataset(channel, networkkey, panid)
 }
 
 func (node *Node) Start() {
@@ -153,6 +229,11 @@ func (node *Node) IsFED() bool {
 	return !node.cfg.IsMtd
 }
 
+// NodeType returns the type of the node: router, fed, med or sed.
+func (node *Node) NodeType() string {
+	return node.cfg.NodeType()
+}
+
 func (node *Node) Stop() {
 	node.ThreadStop()
 	node.IfconfigDown()
@@ -160,16 +241,89 @@ func (node *Node) Stop() {
 }
 
 func (node *Node) Exit() error {
+	atomic.StoreInt32(&node.exiting, 1)
+	node.ContinueProcess()
 	node.inputCommand("exit")
+	node.waitCoverageFlush()
 	_ = node.cmd.Process.Signal(syscall.SIGTERM)
 	_ = node.virtualUartReader.Close()
 
 	err := node.cmd.Wait()
+	node.exitErr = err
 	node.S.Dispatcher().NotifyExit(node.Id)
 
+	if node.transcript != nil {
+		if closeErr := node.transcript.Close(); closeErr != nil {
+			simplelogger.Errorf("%v - close transcript failed: %v", node, closeErr)
+		}
+	}
+
 	return err
 }
 
+// hasExited returns if the node process has exited.
+func (node *Node) hasExited() bool {
+	select {
+	case <-node.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// kill kills the node process which failed to start.
+func (node *Node) kill() {
+	atomic.StoreInt32(&node.exiting, 1)
+	node.ContinueProcess()
+	_ = node.cmd.Process.Kill()
+	if node.container != "" {
+		// killing the runtime client leaves the container running
+		_ = node.containerCtl("kill")
+	}
+	_ = node.virtualUartReader.Close()
+	node.exitErr = node.cmd.Wait()
+
+	if node.transcript != nil {
+		_ = node.transcript.Close()
+	}
+}
+
+// StopProcess suspends the node process with SIGSTOP.
+func (node *Node) StopProcess() error {
+	if node.stopped {
+		return nil
+	}
+
+	if node.container != "" {
+		if err := node.containerCtl("pause"); err != nil {
+			return err
+		}
+	} else if err := node.cmd.Process.Signal(syscall.SIGSTOP); err != nil {
+		return err
+	}
+
+	node.stopped = true
+	return nil
+}
+
+// ContinueProcess continues the node process if it was suspended by StopProcess.
+func (node *Node) ContinueProcess() {
+	if !node.stopped {
+		return
+	}
+
+	var err error
+	if node.container != "" {
+		err = node.containerCtl("unpause")
+	} else {
+		err = node.cmd.Process.Signal(syscall.SIGCONT)
+	}
+	if err != nil {
+		simplelogger.Errorf("%v - continue process failed: %v", node, err)
+	}
+	node.stopped = false
+}
+
 func (node *Node) AssurePrompt() {
 	node.inputCommand("")
 	if found, _ := node.TryExpectLine("", time.Second); found {
@@ -188,6 +342,12 @@ func (node *Node) AssurePrompt() {
 func (node *Node) inputCommand(cmd string) {
 	simplelogger.AssertTrue(node.uartType != NodeUartTypeUndefined)
 
+	now := node.S.Dispatcher().CurTime
+	atomic.StoreUint64(&node.uartTime, now)
+	if node.transcript != nil {
+		node.transcript.record(now, transcriptInput, cmd)
+	}
+
 	if node.uartType == NodeUartTypeRealTime {
 		_, _ = node.pipeIn.Write([]byte(cmd + "\n"))
 		node.S.Dispatcher().NotifyCommand(node.Id)
@@ -519,6 +679,16 @@ func (node *Node) SetMode(mode string) {
 	node.Command(fmt.Sprintf("mode %s", mode), DefaultCommandTimeout)
 }
 
+// GetPollPeriod returns the data poll period of the node in milliseconds, or 0 if it is chosen by OpenThread.
+func (node *Node) GetPollPeriod() int {
+	return node.CommandExpectInt("pollperiod", DefaultCommandTimeout)
+}
+
+// SetPollPeriod sets the data poll period of the node in milliseconds, or 0 to let OpenThread choose it.
+func (node *Node) SetPollPeriod(ms int) {
+	node.Command(fmt.Sprintf("pollperiod %d", ms), DefaultCommandTimeout)
+}
+
 func (node *Node) GetPanid() uint16 {
 	// todo: return Mode type rather than just string
 	return uint16(node.CommandExpectInt("panid", DefaultCommandTimeout))
@@ -571,6 +741,10 @@ func (node *Node) GetVersion() string {
 	return node.CommandExpectString("version", DefaultCommandTimeout)
 }
 
+func (node *Node) GetThreadVersion() int {
+	return node.CommandExpectInt("thread version", DefaultCommandTimeout)
+}
+
 func (node *Node) GetSingleton() bool {
 	s := node.CommandExpectString("singleton", DefaultCommandTimeout)
 	if s == "true" {
@@ -597,16 +771,35 @@ func (node *Node) ConfigActiveDataset(channel int, networkkey string, panid uint
 
 func (node *Node) lineReader(reader io.Reader, uartType NodeUartType) {
 	// close the line channel after line reader routine exit
-	scanner := bufio.NewScanner(otoutfilter.NewOTOutFilter(bufio.NewReader(reader), node.String()))
+	var filter io.Reader
+	if node.S.cfg.LogCorrelation {
+		filter = otoutfilter.NewOTOutFilterWithLogSeq(bufio.NewReader(reader), node.String(), &node.logSeq)
+	} else {
+		filter = otoutfilter.NewOTOutFilter(bufio.NewReader(reader), node.String())
+	}
+	scanner := bufio.NewScanner(filter)
 	scanner.Split(bufio.ScanLines)
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		node.S.sendTracker.onNodeOutput(node.Id, line)
+		node.S.netDiag.onNodeOutput(node.Id, line)
+		energyScanResult := node.S.energyScan.onNodeOutput(node.Id, line)
+		node.S.linkMetrics.onNodeOutput(node.Id, atomic.LoadUint64(&node.uartTime), line)
+		if node.transcript != nil {
+			// output lines are stamped with the time of the latest UART activity, because the dispatcher time can
+			// not be read from this routine
+			node.transcript.record(atomic.LoadUint64(&node.uartTime), transcriptOutput, line)
+		}
 
 		if node.uartType == NodeUartTypeUndefined {
 			simplelogger.Debugf("%v's UART type is %v", node, uartType)
 			node.uartType = uartType
 		}
+		if energyScanResult {
+			// the result is collected, and would be printed as a scan result by TryExpectLine
+			continue
+		}
 
 		select {
 		case node.pendingLines <- line:
@@ -622,6 +815,12 @@ func (node *Node) lineReader(reader io.Reader, uartType NodeUartType) {
 			break
 		}
 	}
+
+	if uartType == NodeUartTypeRealTime {
+		// the process output is closed when the process exits
+		close(node.exited)
+		node.onProcessExit()
+	}
 }
 
 func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool, []string) {
@@ -641,6 +840,9 @@ func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool,
 			}
 
 			simplelogger.Debugf("%v - %s", node, readLine)
+			if node.discardLateOutput(readLine, line) {
+				continue
+			}
 
 			outputLines = append(outputLines, readLine)
 			if node.isLineMatch(readLine, line) {
@@ -730,6 +932,11 @@ func (node *Node) setupMode() {
 
 	node.SetMode(mode)
 
+	if node.cfg.PollPeriod > 0 {
+		simplelogger.AssertTrue(node.cfg.RxOffWhenIdle)
+		node.SetPollPeriod(node.cfg.PollPeriod)
+	}
+
 	if !node.cfg.IsRouter {
 		node.RouterEligibleDisable()
 	} else {
@@ -738,15 +945,19 @@ func (node *Node) setupMode() {
 }
 
 func (node *Node) onUartWrite(data []byte) {
+	atomic.StoreUint64(&node.uartTime, node.S.Dispatcher().CurTime)
 	_, _ = node.virtualUartPipe.Write(data)
 }
 
-func (node *Node) detectVirtualTimeUART() {
+func (node *Node) detectVirtualTimeUART() error {
 	// Input newline to both Virtual Time UART and stdin and check where node outputs newline
 	node.S.Dispatcher().SendToUART(node.Id, []byte("\n"))
 	_, _ = node.pipeIn.Write([]byte("\n"))
 
-	node.expectLine("", DefaultCommandTimeout)
+	if found, _ := node.TryExpectLine("", DefaultCommandTimeout); !found {
+		return fmt.Errorf("node %d did not respond on its UART", node.Id)
+	}
 	// UART type should have been correctly set when the new line is received from node
 	simplelogger.AssertTrue(node.uartType != NodeUartTypeUndefined)
+	return nil
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// CloneNodeConfig configures a new node like the existing node: the new node gets the active dataset of the node
+// instead of the network parameters of the simulation, and the radio range, clock drift and CPU factor of the node. It
+// also gets the executable of the node if both are FTDs or MTDs, and its poll period if both are SEDs. Only fields
+// which are still unset in the config are filled. The type, position and ID of the new node are kept, and it gets
+// fresh addresses.
+func (s *Simulation) CloneNodeConfig(cfg *NodeConfig, id NodeId) error {
+	node := s.nodes[id]
+	if node == nil {
+		return errors.Errorf("node %d not found", id)
+	}
+
+	res := s.ExecCommand([]NodeId{id}, "dataset active -x", DefaultCommandTimeout)[0]
+	if res.Error != "" {
+		return errors.Errorf("read dataset of node %d failed: %s", id, res.Error)
+	}
+	if len(res.Output) != 1 {
+		return errors.Errorf("read dataset of node %d failed: unexpected output %q", id, res.Output)
+	}
+	dataset, err := hex.DecodeString(res.Output[0])
+	if err == nil {
+		_, err = parseDatasetTlvs(dataset)
+	}
+	if err != nil || len(dataset) == 0 {
+		return errors.Errorf("node %d has no valid active dataset: %s", id, res.Output[0])
+	}
+	cfg.Dataset = dataset
+
+	dnode := s.d.GetNode(id)
+	if cfg.RadioRange == 0 {
+		cfg.RadioRange = dnode.RadioRange()
+	}
+	if cfg.ClockDrift == 0 {
+		cfg.ClockDrift = dnode.ClockDrift()
+	}
+	if cfg.CpuFactor == 0 {
+		cfg.CpuFactor = dnode.CpuFactor()
+	}
+	if cfg.ExecutablePath == "" && cfg.IsMtd == node.cfg.IsMtd {
+		cfg.ExecutablePath = node.cfg.ExecutablePath
+	}
+	if cfg.PollPeriod == 0 && cfg.RxOffWhenIdle && node.cfg.RxOffWhenIdle {
+		cfg.PollPeriod = node.cfg.PollPeriod
+	}
+	return nil
+}
+
+// setActiveDataset sets the active dataset of the node from the dataset TLVs.
+func (node *Node) setActiveDataset(dataset []byte) error {
+	tlvs, err := parseDatasetTlvs(dataset)
+	if err != nil {
+		return err
+	}
+	cmds, err := datasetCommands(tlvs)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range cmds {
+		if err = node.runScriptCommand(cmd); err != nil {
+			return errors.Wrapf(err, "set active dataset: %s", cmd)
+		}
+	}
+	return nil
+}
@@ -26,6 +26,8 @@
 
 package simulation
 
+import "github.com/pkg/errors"
+
 type NodeConfig struct {
 	ID             int
 	X, Y           int
@@ -35,6 +37,18 @@ type NodeConfig struct {
 	RadioRange     int
 	ExecutablePath string
 	Restore        bool
+	PollPeriod     int               // data poll period of SEDs in milliseconds, or 0 to let OpenThread choose it
+	InitScript     string            // init script file, or "" for the default init script of the simulation
+	ScriptVars     map[string]string // variables of the init script specific to the node
+	BootDelay      int               // delay in milliseconds before the node starts responding, or 0
+	BootCrashProb  float64           // probability that the node crashes at boot, and never responds
+	CpuFactor      float64           // CPU speed factor scaling the alarm delays of the node, or 0 for 1
+	Channel        int               // channel of the network, or 0 for the channel of the simulation
+	Panid          uint16            // PAN ID of the network, or 0 for the PAN ID of the simulation
+	NetworkKey     string            // network key, or "" for the network key of the simulation
+	Dataset        []byte            // active dataset TLVs set instead of the network parameters, or nil
+	ClockDrift     float64           // clock drift of the node in ppm, or 0
+	ThreadVersion  int               // Thread version the node must run as major*10+minor, or 0 for any
 }
 
 func DefaultNodeConfig() *NodeConfig {
@@ -50,3 +64,33 @@ func DefaultNodeConfig() *NodeConfig {
 		Restore:        false,
 	}
 }
+
+// SetNodeType sets the type of the node: router, fed, med or sed.
+func (cfg *NodeConfig) SetNodeType(typ string) error {
+	switch typ {
+	case "router":
+		cfg.IsRouter, cfg.IsMtd, cfg.RxOffWhenIdle = true, false, false
+	case "fed":
+		cfg.IsRouter, cfg.IsMtd, cfg.RxOffWhenIdle = false, false, false
+	case "med":
+		cfg.IsRouter, cfg.IsMtd, cfg.RxOffWhenIdle = false, true, false
+	case "sed":
+		cfg.IsRouter, cfg.IsMtd, cfg.RxOffWhenIdle = false, true, true
+	default:
+		return errors.Errorf("invalid node type: %s", typ)
+	}
+	return nil
+}
+
+// NodeType returns the type of the node: router, fed, med or sed.
+func (cfg *NodeConfig) NodeType() string {
+	if cfg.IsRouter {
+		return "router"
+	} else if !cfg.IsMtd {
+		return "fed"
+	} else if cfg.RxOffWhenIdle {
+		return "sed"
+	} else {
+		return "med"
+	}
+}
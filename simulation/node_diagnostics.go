@@ -0,0 +1,239 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// NodeStartFailure is the class of the failure of a node to start.
+type NodeStartFailure string
+
+const (
+	NodeStartNotFound       NodeStartFailure = "not-found"
+	NodeStartNotExecutable  NodeStartFailure = "not-executable"
+	NodeStartWrongPlatform  NodeStartFailure = "wrong-platform"
+	NodeStartMissingLibrary NodeStartFailure = "missing-library"
+	NodeStartNotSimulation  NodeStartFailure = "not-simulation-platform"
+	NodeStartNoOtns         NodeStartFailure = "no-otns-support"
+	NodeStartCrashed        NodeStartFailure = "crashed"
+	NodeStartNoResponse     NodeStartFailure = "no-response"
+)
+
+const buildHint = "build OpenThread for OTNS with `make -f examples/Makefile-simulation OTNS=1` (see GUIDE.md), " +
+	"or select another executable with -ot-cli or OTNS_OT_CLI"
+
+// NodeStartError is a classified failure of a node to start, with a hint on how to fix it.
+type NodeStartError struct {
+	Node       NodeId
+	Failure    NodeStartFailure
+	Reason     string // what went wrong, e.g. "executable not built for this platform"
+	Hint       string // how to fix it
+	Executable string
+	Runtime    string   // container runtime running the executable, or "" if it runs on the host
+	Status     string   // exit status of the node process, or "" if it did not exit by itself
+	Stderr     []string // last lines written by the node to stderr
+	Err        error    // the error which revealed the failure
+}
+
+func (e *NodeStartError) Error() string {
+	msg := fmt.Sprintf("node %d failed to start: %s", e.Node, e.Reason)
+	if e.Status != "" {
+		msg += fmt.Sprintf(" (%s)", e.Status)
+	}
+	if e.Hint != "" {
+		msg += ": " + e.Hint
+	}
+	return msg
+}
+
+func (e *NodeStartError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	missingLibraryPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`error while loading shared libraries: ([^:]+)`),
+		regexp.MustCompile(`Library not loaded: (\S+)`),
+	}
+	wrongPlatformPattern = regexp.MustCompile(`(?i)cannot execute binary file|exec format error|bad CPU type`)
+)
+
+// diagnoseStartError classifies an error of starting the process of a node. With the container backend, runtime is
+// the container runtime, which is the process started on the host, and executable is the executable in the container.
+func diagnoseStartError(id NodeId, executable string, runtime string, err error) *NodeStartError {
+	e := &NodeStartError{Node: id, Executable: executable, Runtime: runtime, Err: err}
+	if runtime != "" {
+		e.Failure = NodeStartCrashed
+		e.Reason = err.Error()
+		if errors.Is(err, exec.ErrNotFound) || os.IsNotExist(err) {
+			e.Failure = NodeStartNotFound
+			e.Reason = fmt.Sprintf("container runtime %s not found", runtime)
+			e.Hint = "install Docker or Podman, or select the runtime with -container-runtime"
+		}
+		return e
+	}
+
+	switch {
+	case errors.Is(err, exec.ErrNotFound) || os.IsNotExist(err):
+		e.Failure = NodeStartNotFound
+		e.Reason = fmt.Sprintf("executable %s not found", executable)
+		e.Hint = buildHint
+	case errors.Is(err, syscall.ENOEXEC):
+		e.Failure = NodeStartWrongPlatform
+		e.Reason = "executable not built for this platform" + describeExecutable(executable)
+		e.Hint = "rebuild OpenThread on this host, " + buildHint
+	case os.IsPermission(err):
+		e.Failure = NodeStartNotExecutable
+		e.Reason = fmt.Sprintf("executable %s is not executable", executable)
+		e.Hint = fmt.Sprintf("make it executable with `chmod +x %s`", executable)
+	default:
+		e.Failure = NodeStartCrashed
+		e.Reason = err.Error()
+	}
+	return e
+}
+
+// diagnoseNoResponse classifies a node process which started but did not respond, from its exit status, its stderr
+// and its executable. The executable is only inspected if it runs on the host, i.e. runtime is "".
+func diagnoseNoResponse(id NodeId, executable string, runtime string, err error, exitErr error, exited bool,
+	stderr []string, dispatcherPort int) *NodeStartError {
+	e := &NodeStartError{Node: id, Executable: executable, Runtime: runtime, Stderr: stderr, Err: err}
+	if exited {
+		e.Status = "exit status 0"
+		if exitErr != nil {
+			e.Status = exitErr.Error()
+		}
+	}
+
+	output := strings.Join(stderr, "\n")
+	for _, pat := range missingLibraryPatterns {
+		if m := pat.FindStringSubmatch(output); m != nil {
+			e.Failure = NodeStartMissingLibrary
+			e.Reason = fmt.Sprintf("shared library %s not found", strings.TrimSpace(m[1]))
+			e.Hint = "install the library, or rebuild OpenThread on this host"
+			if runtime != "" {
+				e.Hint = "install the library in the container image, or rebuild OpenThread for the image"
+			}
+			return e
+		}
+	}
+	if wrongPlatformPattern.MatchString(output) {
+		e.Failure = NodeStartWrongPlatform
+		e.Reason = "executable not built for this platform"
+		e.Hint = "rebuild OpenThread for the container image, " + buildHint
+		if runtime == "" {
+			e.Reason += describeExecutable(executable)
+			e.Hint = "rebuild OpenThread on this host, " + buildHint
+		}
+		return e
+	}
+
+	var data []byte
+	if runtime == "" {
+		// an executable in a container can not be inspected from the host
+		data, _ = ioutil.ReadFile(executable)
+	}
+	if len(data) > 0 {
+		// the simulation platform finds the dispatcher with PORT_OFFSET, and OTNS support pushes the extended address
+		if !bytes.Contains(data, []byte("PORT_OFFSET")) {
+			e.Failure = NodeStartNotSimulation
+			e.Reason = "executable not built for the OpenThread simulation platform (RFSIM platform not compiled in)"
+			e.Hint = buildHint
+			return e
+		}
+		if !bytes.Contains(data, []byte("extaddr=")) {
+			e.Failure = NodeStartNoOtns
+			e.Reason = "OTNS support not compiled in"
+			e.Hint = "rebuild with OTNS=1, " + buildHint
+			return e
+		}
+	}
+
+	if exited {
+		e.Failure = NodeStartCrashed
+		e.Reason = "node process exited during startup"
+		e.Hint = "see the stderr of the node"
+	} else {
+		e.Failure = NodeStartNoResponse
+		e.Reason = err.Error()
+		e.Hint = fmt.Sprintf("check that the node can reach the dispatcher on UDP port %d, and that the host is not "+
+			"overloaded", dispatcherPort)
+	}
+	return e
+}
+
+// describeExecutable returns the platform the executable is built for, if it can be read.
+func describeExecutable(executable string) string {
+	host := runtime.GOOS + "/" + runtime.GOARCH
+	if f, err := elf.Open(executable); err == nil {
+		defer f.Close()
+		return fmt.Sprintf(" (ELF %s, host is %s)", strings.TrimPrefix(f.Machine.String(), "EM_"), host)
+	}
+	if f, err := macho.Open(executable); err == nil {
+		defer f.Close()
+		return fmt.Sprintf(" (Mach-O %s, host is %s)", strings.TrimPrefix(f.Cpu.String(), "Cpu"), host)
+	}
+	return ""
+}
+
+// failNodeStart deletes a node which did not respond after its process started, and returns the classified error.
+func (s *Simulation) failNodeStart(node *Node, err error) *NodeStartError {
+	exited := node.hasExited()
+	node.kill()
+	delete(s.nodes, node.Id)
+	s.d.DeleteNode(node.Id)
+
+	// wait for the last lines of stderr, which usually explain the failure
+	select {
+	case <-node.stderr.done:
+	case <-time.After(stderrDrainPeriod):
+	}
+
+	e := diagnoseNoResponse(node.Id, node.executable, s.containerRuntime(), err, node.exitErr, exited,
+		node.stderr.get(), s.cfg.DispatcherPort)
+	for _, line := range e.Stderr {
+		simplelogger.Errorf("node %d stderr: %s", node.Id, line)
+	}
+	return e
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnoseStartError(t *testing.T) {
+	const executable = "/tmp/ot-cli-ftd"
+	for _, tc := range []struct {
+		err     error
+		failure NodeStartFailure
+		reason  string
+	}{
+		{err: &exec.Error{Name: executable, Err: exec.ErrNotFound}, failure: NodeStartNotFound,
+			reason: "executable /tmp/ot-cli-ftd not found"},
+		{err: &os.PathError{Op: "fork/exec", Path: executable, Err: syscall.ENOENT}, failure: NodeStartNotFound,
+			reason: "executable /tmp/ot-cli-ftd not found"},
+		{err: &os.PathError{Op: "fork/exec", Path: executable, Err: syscall.ENOEXEC}, failure: NodeStartWrongPlatform,
+			reason: "executable not built for this platform"},
+		{err: &os.PathError{Op: "fork/exec", Path: executable, Err: syscall.EACCES}, failure: NodeStartNotExecutable,
+			reason: "executable /tmp/ot-cli-ftd is not executable"},
+		{err: errors.Errorf("too many open files"), failure: NodeStartCrashed, reason: "too many open files"},
+	} {
+		e := diagnoseStartError(3, executable, "", tc.err)
+		assert.Equal(t, tc.failure, e.Failure, "%v", tc.err)
+		assert.Equal(t, tc.reason, e.Reason, "%v", tc.err)
+		assert.Equal(t, 3, e.Node)
+		assert.Equal(t, executable, e.Executable)
+		assert.True(t, errors.Is(e, tc.err))
+		assert.Contains(t, e.Error(), "node 3 failed to start: "+tc.reason)
+	}
+
+	// with the container backend, the process started on the host is the container runtime
+	e := diagnoseStartError(3, executable, "podman", &exec.Error{Name: "podman", Err: exec.ErrNotFound})
+	assert.Equal(t, NodeStartNotFound, e.Failure)
+	assert.Equal(t, "container runtime podman not found", e.Reason)
+	assert.Equal(t, executable, e.Executable)
+	assert.Equal(t, "podman", e.Runtime)
+	e = diagnoseStartError(3, executable, "docker", &os.PathError{Op: "fork/exec", Path: "docker", Err: syscall.EACCES})
+	assert.Equal(t, NodeStartCrashed, e.Failure)
+	assert.Equal(t, "fork/exec docker: permission denied", e.Reason)
+}
+
+func TestDiagnoseNoResponse(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutable := func(name string, content string) string {
+		filename := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(filename, []byte(content), 0755))
+		return filename
+	}
+	otns := writeExecutable("otns", "\x00PORT_OFFSET\x00extaddr=%s\x00")
+	noOtns := writeExecutable("no-otns", "\x00PORT_OFFSET\x00")
+	posix := writeExecutable("posix", "\x00ot-cli\x00")
+	missing := filepath.Join(dir, "missing")
+	noResponse := errors.Errorf("no response from node 2")
+	exitErr := errors.Errorf("exit status 127")
+
+	for _, tc := range []struct {
+		executable string
+		exitErr    error
+		exited     bool
+		stderr     []string
+		failure    NodeStartFailure
+		reason     string
+		status     string
+	}{
+		{executable: otns, exitErr: exitErr, exited: true,
+			stderr: []string{
+				"ot-cli-ftd: error while loading shared libraries: libmbedtls.so.14: cannot open shared object file",
+			},
+			failure: NodeStartMissingLibrary, reason: "shared library libmbedtls.so.14 not found", status: "exit status 127"},
+		{executable: otns, exited: true,
+			stderr:  []string{"dyld: Library not loaded: /usr/local/lib/libssl.1.1.dylib", "  Referenced from: ot-cli-ftd"},
+			failure: NodeStartMissingLibrary, reason: "shared library /usr/local/lib/libssl.1.1.dylib not found",
+			status: "exit status 0"},
+		{executable: posix, exitErr: exitErr, exited: true, stderr: []string{"ot-cli-ftd: cannot execute binary file"},
+			failure: NodeStartWrongPlatform, reason: "executable not built for this platform", status: "exit status 127"},
+		{executable: posix, failure: NodeStartNotSimulation,
+			reason: "executable not built for the OpenThread simulation platform (RFSIM platform not compiled in)"},
+		{executable: noOtns, failure: NodeStartNoOtns, reason: "OTNS support not compiled in"},
+		{executable: otns, exitErr: errors.Errorf("signal: segmentation fault"), exited: true,
+			failure: NodeStartCrashed, reason: "node process exited during startup", status: "signal: segmentation fault"},
+		{executable: otns, failure: NodeStartNoResponse, reason: "no response from node 2"},
+		// the executable is not checked if it can not be read
+		{executable: missing, failure: NodeStartNoResponse, reason: "no response from node 2"},
+	} {
+		e := diagnoseNoResponse(2, tc.executable, "", noResponse, tc.exitErr, tc.exited, tc.stderr, 9000)
+		assert.Equal(t, tc.failure, e.Failure, "%v", tc)
+		assert.Equal(t, tc.reason, e.Reason, "%v", tc)
+		assert.Equal(t, tc.status, e.Status, "%v", tc)
+		assert.Equal(t, tc.stderr, e.Stderr, "%v", tc)
+		assert.True(t, errors.Is(e, noResponse))
+	}
+
+	e := diagnoseNoResponse(2, otns, "", noResponse, nil, false, nil, 9000)
+	assert.Contains(t, e.Hint, "UDP port 9000")
+	assert.Equal(t, "node 2 failed to start: no response from node 2: "+e.Hint, e.Error())
+
+	// the executable of a container node is a path in the container, even if it exists on the host
+	e = diagnoseNoResponse(2, posix, "docker", noResponse, nil, false, nil, 9000)
+	assert.Equal(t, NodeStartNoResponse, e.Failure)
+	e = diagnoseNoResponse(2, posix, "docker", noResponse, exitErr, true, []string{"exec format error"}, 9000)
+	assert.Equal(t, NodeStartWrongPlatform, e.Failure)
+	assert.Equal(t, "executable not built for this platform", e.Reason)
+	assert.Contains(t, e.Hint, "container image")
+}
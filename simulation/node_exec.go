@@ -0,0 +1,134 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// ExecResult is the result of a CLI command executed on a node by ExecCommand.
+type ExecResult struct {
+	Node   NodeId
+	Output []string // output lines, excluding the command echo and the Done line
+	Error  string   // error of the command, or "" if it succeeded
+}
+
+// execState tracks the output of a command executed on a node.
+type execState struct {
+	node   *Node
+	echoed bool // the command echo was received
+	done   bool
+	result *ExecResult
+}
+
+// readLines reads the pending output lines of the node without blocking, and returns if any line was read.
+func (es *execState) readLines(cmd string) bool {
+	read := false
+	for !es.done {
+		select {
+		case line, ok := <-es.node.pendingLines:
+			if !ok {
+				es.result.Error = "node exited"
+				es.done = true
+				return read
+			}
+
+			read = true
+			if es.node.discardLateOutput(line, cmd) {
+				continue
+			}
+			if !es.echoed {
+				es.echoed = es.node.isLineMatch(line, cmd)
+			} else if DoneOrErrorRegexp.MatchString(line) {
+				if line != "Done" {
+					es.result.Error = line
+				}
+				es.done = true
+			} else {
+				es.result.Output = append(es.result.Output, line)
+			}
+		default:
+			return read
+		}
+	}
+	return read
+}
+
+// ExecCommand executes the CLI command on the nodes in parallel: the command is input to all nodes at once, and
+// the dispatcher runs all nodes until each node completes the command or the timeout expires. The results are
+// returned in the order of ids. Paused nodes and nodes not found are reported as errors.
+func (s *Simulation) ExecCommand(ids []NodeId, cmd string, timeout time.Duration) []*ExecResult {
+	results := make([]*ExecResult, len(ids))
+	var running []*execState
+	for i, id := range ids {
+		results[i] = &ExecResult{Node: id}
+		node := s.nodes[id]
+		if node == nil {
+			results[i].Error = "node not found"
+			continue
+		}
+		if dnode := s.d.GetNode(id); dnode == nil || dnode.IsPaused() {
+			results[i].Error = "node is paused"
+			continue
+		}
+
+		node.inputCommand(cmd)
+		running = append(running, &execState{node: node, result: results[i]})
+	}
+
+	deadline := time.Now().Add(timeout)
+	for len(running) > 0 {
+		read := false
+		pending := running[:0]
+		for _, es := range running {
+			if es.readLines(cmd) {
+				read = true
+			}
+			if !es.done {
+				pending = append(pending, es)
+			}
+		}
+		running = pending
+
+		if len(running) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			for _, es := range running {
+				es.result.Error = "timeout"
+			}
+			break
+		}
+		if !read {
+			s.d.RecvEvents()
+		}
+	}
+
+	return results
+}
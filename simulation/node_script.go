@@ -0,0 +1,361 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// Directives of init scripts for conditional sections.
+const (
+	scriptDirectiveIf    = "@if"
+	scriptDirectiveElse  = "@else"
+	scriptDirectiveEndif = "@endif"
+)
+
+var (
+	scriptVarRegexp         = regexp.MustCompile(`{{\s*([^{}\s]*)\s*}}`)
+	scriptVarNameRegexp     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	scriptVersionCondRegexp = regexp.MustCompile(`^version\s*(==|!=|>=|<=|>|<)\s*(\d+)$`)
+	scriptNodeTypes         = map[string]struct{}{"router": {}, "fed": {}, "med": {}, "sed": {}, "ftd": {}, "mtd": {}}
+)
+
+// scriptTarget describes the node which an init script runs on, for evaluating the conditions of the script.
+type scriptTarget struct {
+	types         map[string]bool     // node types of the node, e.g. router and ftd
+	threadVersion func() (int, error) // Thread version of the node as major*10+minor, e.g. 13 for Thread 1.3
+}
+
+// scriptCondition is a parsed condition of an `@if` directive.
+type scriptCondition struct {
+	negate  bool
+	typ     string // node type, or "" for a version condition
+	op      string
+	version int
+}
+
+func parseScriptCondition(s string) (*scriptCondition, error) {
+	cond := &scriptCondition{}
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "!") {
+		cond.negate = true
+		s = strings.TrimSpace(s[1:])
+	}
+
+	if m := scriptVersionCondRegexp.FindStringSubmatch(s); m != nil {
+		cond.op = m[1]
+		cond.version, _ = strconv.Atoi(m[2])
+		return cond, nil
+	}
+
+	if _, ok := scriptNodeTypes[s]; ok {
+		cond.typ = s
+		return cond, nil
+	}
+
+	return nil, errors.Errorf("invalid condition: %#v", s)
+}
+
+func (cond *scriptCondition) eval(target *scriptTarget) (bool, error) {
+	var res bool
+	if cond.typ != "" {
+		res = target.types[cond.typ]
+	} else {
+		version, err := target.threadVersion()
+		if err != nil {
+			return false, err
+		}
+
+		switch cond.op {
+		case "==":
+			res = version == cond.version
+		case "!=":
+			res = version != cond.version
+		case ">=":
+			res = version >= cond.version
+		case "<=":
+			res = version <= cond.version
+		case ">":
+			res = version > cond.version
+		case "<":
+			res = version < cond.version
+		}
+	}
+	return res != cond.negate, nil
+}
+
+// selectNodeScript returns the commands of the script which apply to the target node, evaluating the `@if`,
+// `@else` and `@endif` directives. Sections can be nested. Conditions of skipped sections are only checked for syntax.
+// If the target is nil, no condition is evaluated, which checks the syntax of all directives.
+func selectNodeScript(script []string, target *scriptTarget) ([]string, error) {
+	type section struct {
+		active    bool // commands of the section run
+		condition bool // result of the condition of the section
+		inElse    bool
+	}
+
+	var selected []string
+	var sections []section
+	active := func() bool {
+		return len(sections) == 0 || sections[len(sections)-1].active
+	}
+
+	for _, line := range script {
+		directive := strings.Fields(line)[0]
+		switch directive {
+		case scriptDirectiveIf:
+			cond, err := parseScriptCondition(strings.TrimPrefix(line, scriptDirectiveIf))
+			if err != nil {
+				return nil, err
+			}
+
+			sec := section{}
+			if active() && target != nil {
+				if sec.condition, err = cond.eval(target); err != nil {
+					return nil, err
+				}
+				sec.active = sec.condition
+			}
+			sections = append(sections, sec)
+		case scriptDirectiveElse, scriptDirectiveEndif:
+			if line != directive {
+				return nil, errors.Errorf("unexpected arguments: %#v", line)
+			}
+			if len(sections) == 0 {
+				return nil, errors.Errorf("%s without %s", directive, scriptDirectiveIf)
+			}
+
+			sec := &sections[len(sections)-1]
+			if directive == scriptDirectiveEndif {
+				sections = sections[:len(sections)-1]
+			} else if sec.inElse {
+				return nil, errors.Errorf("duplicate %s", scriptDirectiveElse)
+			} else {
+				sec.inElse = true
+				parentActive := len(sections) == 1 || sections[len(sections)-2].active
+				sec.active = parentActive && !sec.condition
+			}
+		default:
+			if strings.HasPrefix(directive, "@") {
+				return nil, errors.Errorf("unknown directive: %s", directive)
+			}
+			if active() {
+				selected = append(selected, line)
+			}
+		}
+	}
+
+	if len(sections) > 0 {
+		return nil, errors.Errorf("%s without %s", scriptDirectiveIf, scriptDirectiveEndif)
+	}
+	return selected, nil
+}
+
+// ReadNodeScript reads a node init script: one CLI command or directive per line. Blank lines and lines starting with
+// `#` are skipped. The structure of the directives is validated.
+func ReadNodeScript(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var script []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		script = append(script, line)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// check the directives without evaluating any condition
+	if _, err = selectNodeScript(script, nil); err != nil {
+		return nil, errors.Wrap(err, filename)
+	}
+	return script, nil
+}
+
+// ExpandNodeScript substitutes the variables of the form `{{name}}` in the commands of a script.
+// It fails if a command references an undefined variable.
+func ExpandNodeScript(script []string, vars map[string]string) ([]string, error) {
+	expanded := make([]string, len(script))
+	for i, cmd := range script {
+		var err error
+		expanded[i] = scriptVarRegexp.ReplaceAllStringFunc(cmd, func(ref string) string {
+			name := scriptVarRegexp.FindStringSubmatch(ref)[1]
+			val, ok := vars[name]
+			if !ok && err == nil {
+				err = errors.Errorf("undefined variable %#v in command %#v", name, cmd)
+			}
+			return val
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// ValidateScriptVarName returns an error if the name can not be used as a script variable.
+func ValidateScriptVarName(name string) error {
+	if !scriptVarNameRegexp.MatchString(name) {
+		return errors.Errorf("invalid variable name: %#v", name)
+	}
+	return nil
+}
+
+// InitScript returns the default init script file of new nodes, or "" if there is none.
+func (s *Simulation) InitScript() string {
+	return s.initScript
+}
+
+// SetInitScript sets the default init script file of new nodes, or "" for none.
+func (s *Simulation) SetInitScript(filename string) {
+	s.initScript = filename
+}
+
+// ScriptVars returns the custom variables of init scripts.
+func (s *Simulation) ScriptVars() map[string]string {
+	vars := make(map[string]string, len(s.scriptVars))
+	for name, val := range s.scriptVars {
+		vars[name] = val
+	}
+	return vars
+}
+
+// SetScriptVar sets a custom variable of init scripts.
+func (s *Simulation) SetScriptVar(name string, val string) error {
+	if err := ValidateScriptVarName(name); err != nil {
+		return err
+	}
+	s.scriptVars[name] = val
+	return nil
+}
+
+// nodeScriptVars returns the variables of the init script of a node: the built-in variables, overridden by the custom
+// variables of the simulation, overridden by the variables of the node.
+func (s *Simulation) nodeScriptVars(nodeid NodeId, cfg *NodeConfig) map[string]string {
+	vars := map[string]string{
+		"nodeid":     strconv.Itoa(nodeid),
+		"panid":      fmt.Sprintf("0x%04x", s.Panid()),
+		"channel":    strconv.Itoa(s.Channel()),
+		"networkkey": s.NetworkKey(),
+		"x":          strconv.Itoa(cfg.X),
+		"y":          strconv.Itoa(cfg.Y),
+	}
+	for name, val := range s.scriptVars {
+		vars[name] = val
+	}
+	for name, val := range cfg.ScriptVars {
+		vars[name] = val
+	}
+	return vars
+}
+
+// runInitScript runs the init script of a new node, if any.
+func (s *Simulation) runInitScript(node *Node) (err error) {
+	filename := node.cfg.InitScript
+	if filename == "" {
+		filename = s.initScript
+	}
+	if filename == "" {
+		return nil
+	}
+
+	script, err := ReadNodeScript(filename)
+	if err != nil {
+		return errors.Wrapf(err, "read init script %s", filename)
+	}
+
+	script, err = selectNodeScript(script, node.scriptTarget())
+	if err != nil {
+		return errors.Wrapf(err, "init script %s", filename)
+	}
+
+	script, err = ExpandNodeScript(script, s.nodeScriptVars(node.Id, node.cfg))
+	if err != nil {
+		return errors.Wrapf(err, "init script %s", filename)
+	}
+
+	for _, cmd := range script {
+		if err = node.runScriptCommand(cmd); err != nil {
+			return errors.Wrapf(err, "init script %s: %s", filename, cmd)
+		}
+	}
+	return nil
+}
+
+func (node *Node) scriptTarget() *scriptTarget {
+	cfg := node.cfg
+	types := map[string]bool{
+		"ftd":          !cfg.IsMtd,
+		"mtd":          cfg.IsMtd,
+		cfg.NodeType(): true,
+	}
+
+	version := -1
+	return &scriptTarget{
+		types: types,
+		threadVersion: func() (v int, err error) {
+			if version < 0 {
+				defer func() {
+					if r := recover(); r != nil {
+						err = errors.Errorf("get Thread version: %v", r)
+					}
+				}()
+				// `thread version` is 2 for Thread 1.1, 3 for 1.2, 4 for 1.3 and so on
+				version = 9 + node.GetThreadVersion()
+			}
+			return version, nil
+		},
+	}
+}
+
+func (node *Node) runScriptCommand(cmd string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+	}()
+
+	node.Command(cmd, DefaultCommandTimeout)
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandNodeScript(t *testing.T) {
+	vars := map[string]string{"nodeid": "3", "channel": "15", "empty": ""}
+	for _, tc := range []struct {
+		script   []string
+		expanded []string
+		err      string
+	}{
+		{script: nil, expanded: []string{}},
+		{script: []string{"ifconfig up"}, expanded: []string{"ifconfig up"}},
+		{script: []string{"channel {{channel}}", "extaddr 00000000000000{{ nodeid }}"},
+			expanded: []string{"channel 15", "extaddr 000000000000003"}},
+		{script: []string{"{{nodeid}}{{nodeid}} {{empty}}x"}, expanded: []string{"33 x"}},
+		{script: []string{"txpower {x}", "txpower {{ }}"}, err: `undefined variable "" in command "txpower {{ }}"`},
+		{script: []string{"ifconfig up", "panid {{panid}}"}, err: `undefined variable "panid" in command "panid {{panid}}"`},
+	} {
+		expanded, err := ExpandNodeScript(tc.script, vars)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "%v", tc.script)
+			continue
+		}
+		assert.Nil(t, err, "%v", tc.script)
+		assert.Equal(t, tc.expanded, expanded, "%v", tc.script)
+	}
+}
+
+func TestParseScriptCondition(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		cond scriptCondition
+		err  bool
+	}{
+		{s: "router", cond: scriptCondition{typ: "router"}},
+		{s: " ! sed ", cond: scriptCondition{negate: true, typ: "sed"}},
+		{s: "version >= 13", cond: scriptCondition{op: ">=", version: 13}},
+		{s: "!version==12", cond: scriptCondition{negate: true, op: "==", version: 12}},
+		{s: "version < 14", cond: scriptCondition{op: "<", version: 14}},
+		{s: "", err: true},
+		{s: "leader", err: true},
+		{s: "version", err: true},
+		{s: "version => 13", err: true},
+		{s: "version >= 1.3", err: true},
+		{s: "!!router", err: true},
+	} {
+		cond, err := parseScriptCondition(tc.s)
+		if tc.err {
+			assert.NotNil(t, err, "%#v", tc.s)
+			continue
+		}
+		assert.Nil(t, err, "%#v", tc.s)
+		assert.Equal(t, tc.cond, *cond, "%#v", tc.s)
+	}
+}
+
+func TestSelectNodeScript(t *testing.T) {
+	versionQueried := false
+	router13 := &scriptTarget{
+		types: map[string]bool{"router": true, "ftd": true},
+		threadVersion: func() (int, error) {
+			versionQueried = true
+			return 13, nil
+		},
+	}
+	sed := &scriptTarget{
+		types: map[string]bool{"sed": true, "mtd": true},
+		threadVersion: func() (int, error) {
+			return 0, errors.Errorf("node not responding")
+		},
+	}
+
+	for _, tc := range []struct {
+		script         []string
+		target         *scriptTarget
+		selected       []string
+		err            string
+		versionQueried bool
+	}{
+		{script: []string{"a", "b"}, target: router13, selected: []string{"a", "b"}},
+		{script: []string{"a", "@if router", "b", "@else", "c", "@endif", "d"}, target: router13,
+			selected: []string{"a", "b", "d"}},
+		{script: []string{"a", "@if router", "b", "@else", "c", "@endif", "d"}, target: sed,
+			selected: []string{"a", "c", "d"}},
+		{script: []string{"@if !mtd", "a", "@endif"}, target: sed},
+		{script: []string{"@if version >= 13", "a", "@else", "b", "@endif"}, target: router13,
+			selected: []string{"a"}, versionQueried: true},
+		{script: []string{"@if version != 13", "a", "@endif"}, target: router13, versionQueried: true},
+		// nested sections
+		{script: []string{"@if ftd", "a", "@if version > 12", "b", "@else", "c", "@endif", "@endif"},
+			target: router13, selected: []string{"a", "b"}, versionQueried: true},
+		{script: []string{"@if router", "a", "@else", "@if !ftd", "b", "@endif", "c", "@endif"}, target: router13,
+			selected: []string{"a"}},
+		{script: []string{"@if router", "a", "@else", "@if !ftd", "b", "@endif", "c", "@endif"}, target: sed,
+			selected: []string{"b", "c"}},
+		// conditions of skipped sections are not evaluated
+		{script: []string{"@if router", "@if version < 13", "a", "@endif", "@endif"}, target: sed},
+		{script: []string{"@if version < 13", "a", "@endif"}, target: sed, err: "node not responding"},
+		// errors
+		{script: []string{"@if router", "a"}, target: router13, err: "@if without @endif"},
+		{script: []string{"a", "@endif"}, target: nil, err: "@endif without @if"},
+		{script: []string{"@else"}, target: router13, err: "@else without @if"},
+		{script: []string{"@if sed", "@else", "@else", "@endif"}, target: router13, err: "duplicate @else"},
+		{script: []string{"@if sed", "@endif router"}, target: router13, err: `unexpected arguments: "@endif router"`},
+		{script: []string{"@if leader", "@endif"}, target: nil, err: `invalid condition: "leader"`},
+		{script: []string{"@elif router"}, target: router13, err: "unknown directive: @elif"},
+	} {
+		versionQueried = false
+		selected, err := selectNodeScript(tc.script, tc.target)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "%v", tc.script)
+			continue
+		}
+		assert.Nil(t, err, "%v", tc.script)
+		assert.Equal(t, tc.selected, selected, "%v", tc.script)
+		assert.Equal(t, tc.versionQueried, versionQueried, "%v", tc.script)
+	}
+}
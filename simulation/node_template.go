@@ -0,0 +1,260 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openthread/ot-ns/dispatcher"
+)
+
+var nodeTemplateNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NodeTemplate is a named node configuration, which `add <template-name>` instantiates. A template can extend another
+// template: the fields it sets override the fields of the parent template, and its script variables are added to the
+// variables of the parent template. Zero values are inherited.
+type NodeTemplate struct {
+	Extends    string            `yaml:"extends,omitempty"`   // name of the parent template
+	Type       string            `yaml:"type,omitempty"`      // router, fed, med or sed
+	Executable string            `yaml:"exe,omitempty"`       // executable, or "" for the default executable
+	Version    int               `yaml:"version,omitempty"`   // required Thread version, e.g. 13 for Thread 1.3
+	RadioRange int               `yaml:"rr,omitempty"`        // radio range, or 0 for the default radio range
+	ClockDrift float64           `yaml:"drift,omitempty"`     // clock drift in ppm
+	CpuFactor  float64           `yaml:"cpufactor,omitempty"` // CPU speed factor
+	PollPeriod int               `yaml:"poll,omitempty"`      // data poll period of SEDs in milliseconds
+	InitScript string            `yaml:"script,omitempty"`    // init script file, or "" for the default init script
+	Vars       map[string]string `yaml:"vars,omitempty"`      // variables of the init script
+}
+
+// NodeTemplatesFile is the YAML file of node templates.
+type NodeTemplatesFile struct {
+	Templates map[string]*NodeTemplate `yaml:"templates"`
+}
+
+// ReadNodeTemplates reads the node templates of the YAML file, with the inheritance resolved.
+func ReadNodeTemplates(filename string) (map[string]*NodeTemplate, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file NodeTemplatesFile
+	if err = yaml.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrapf(err, "read node templates %s", filename)
+	}
+	templates, err := ResolveNodeTemplates(file.Templates)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read node templates %s", filename)
+	}
+	return templates, nil
+}
+
+// ResolveNodeTemplates resolves the inheritance of the node templates, and validates the resolved templates. A nil
+// template, e.g. an empty YAML entry, is an empty template. The templates are not modified.
+func ResolveNodeTemplates(templates map[string]*NodeTemplate) (map[string]*NodeTemplate, error) {
+	resolved := map[string]*NodeTemplate{}
+	var resolve func(name string, extending []string) (*NodeTemplate, error)
+	resolve = func(name string, extending []string) (*NodeTemplate, error) {
+		if t := resolved[name]; t != nil {
+			return t, nil
+		}
+		for _, child := range extending {
+			if child == name {
+				return nil, errors.Errorf("template %s extends itself", name)
+			}
+		}
+
+		t, ok := templates[name]
+		if !ok {
+			return nil, errors.Errorf("template %s extends unknown template %s", extending[len(extending)-1], name)
+		}
+		if t == nil {
+			t = &NodeTemplate{}
+		}
+		// the resolved template does not share the variables of the template
+		merged := (&NodeTemplate{}).extend(t)
+		if t.Extends != "" {
+			parent, err := resolve(t.Extends, append(extending, name))
+			if err != nil {
+				return nil, err
+			}
+			merged = parent.extend(t)
+		}
+		resolved[name] = &merged
+		return &merged, nil
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t, err := resolve(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err = t.validate(name); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// extend returns the template extended by the child template.
+func (t *NodeTemplate) extend(child *NodeTemplate) NodeTemplate {
+	merged := *t
+	merged.Extends = child.Extends
+	if child.Type != "" {
+		merged.Type = child.Type
+	}
+	if child.Executable != "" {
+		merged.Executable = child.Executable
+	}
+	if child.Version != 0 {
+		merged.Version = child.Version
+	}
+	if child.RadioRange != 0 {
+		merged.RadioRange = child.RadioRange
+	}
+	if child.ClockDrift != 0 {
+		merged.ClockDrift = child.ClockDrift
+	}
+	if child.CpuFactor != 0 {
+		merged.CpuFactor = child.CpuFactor
+	}
+	if child.PollPeriod != 0 {
+		merged.PollPeriod = child.PollPeriod
+	}
+	if child.InitScript != "" {
+		merged.InitScript = child.InitScript
+	}
+	if len(t.Vars)+len(child.Vars) > 0 {
+		merged.Vars = map[string]string{}
+		for name, val := range t.Vars {
+			merged.Vars[name] = val
+		}
+		for name, val := range child.Vars {
+			merged.Vars[name] = val
+		}
+	}
+	return merged
+}
+
+func (t *NodeTemplate) validate(name string) error {
+	if !nodeTemplateNameRegexp.MatchString(name) {
+		return errors.Errorf("invalid template name: %#v", name)
+	}
+	cfg := DefaultNodeConfig()
+	if cfg.SetNodeType(name) == nil {
+		return errors.Errorf("template %s: name is a node type", name)
+	}
+	if t.Type == "" {
+		return errors.Errorf("template %s: no node type", name)
+	}
+	if err := t.Apply(cfg); err != nil {
+		return errors.Wrapf(err, "template %s", name)
+	}
+	return nil
+}
+
+// Apply configures the node like the template.
+func (t *NodeTemplate) Apply(cfg *NodeConfig) error {
+	if err := cfg.SetNodeType(t.Type); err != nil {
+		return err
+	}
+	if t.Version < 0 {
+		return errors.Errorf("invalid Thread version: %d", t.Version)
+	}
+	if t.RadioRange < 0 {
+		return errors.Errorf("invalid radio range: %d", t.RadioRange)
+	}
+	if t.ClockDrift < -dispatcher.MaxClockDriftPpm || t.ClockDrift > dispatcher.MaxClockDriftPpm {
+		return errors.Errorf("invalid clock drift: %gppm", t.ClockDrift)
+	}
+	if t.CpuFactor < 0 || t.CpuFactor > dispatcher.MaxCpuFactor {
+		return errors.Errorf("invalid cpu factor: %v", t.CpuFactor)
+	}
+	if t.PollPeriod < 0 || t.PollPeriod > 0 && !cfg.RxOffWhenIdle {
+		return errors.Errorf("poll period only applies to sed")
+	}
+	for name := range t.Vars {
+		if err := ValidateScriptVarName(name); err != nil {
+			return err
+		}
+	}
+
+	cfg.ExecutablePath = t.Executable
+	cfg.ThreadVersion = t.Version
+	if t.RadioRange > 0 {
+		cfg.RadioRange = t.RadioRange
+	}
+	cfg.ClockDrift = t.ClockDrift
+	cfg.CpuFactor = t.CpuFactor
+	cfg.PollPeriod = t.PollPeriod
+	cfg.InitScript = t.InitScript
+	if len(t.Vars) > 0 {
+		cfg.ScriptVars = map[string]string{}
+		for name, val := range t.Vars {
+			cfg.ScriptVars[name] = val
+		}
+	}
+	return nil
+}
+
+// NodeTemplates returns the node templates by name.
+func (s *Simulation) NodeTemplates() map[string]*NodeTemplate {
+	return s.nodeTemplates
+}
+
+// NodeTemplate returns the node template, or nil if there is none with the name.
+func (s *Simulation) NodeTemplate(name string) *NodeTemplate {
+	return s.nodeTemplates[name]
+}
+
+// SetNodeTemplates replaces the node templates with the resolved templates.
+func (s *Simulation) SetNodeTemplates(templates map[string]*NodeTemplate) {
+	s.nodeTemplates = templates
+}
+
+// checkThreadVersion checks that the node runs the Thread version, as major*10+minor.
+func (node *Node) checkThreadVersion(version int) error {
+	actual, err := node.scriptTarget().threadVersion()
+	if err != nil {
+		return err
+	}
+	if actual != version {
+		return errors.Errorf("node %d runs Thread version %d, expected %d", node.Id, actual, version)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveNodeTemplates(t *testing.T) {
+	base := &NodeTemplate{Type: "router", RadioRange: 200, ClockDrift: 10, Vars: map[string]string{"a": "1", "b": "1"}}
+	sleepy := &NodeTemplate{Extends: "base", Type: "sed", PollPeriod: 500, Vars: map[string]string{"b": "2"}}
+	templates := map[string]*NodeTemplate{
+		"base":   base,
+		"sleepy": sleepy,
+		"slow":   {Extends: "sleepy", ClockDrift: -20, CpuFactor: 0.5, Vars: map[string]string{"c": "3"}},
+	}
+	resolved, err := ResolveNodeTemplates(templates)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(resolved))
+	assert.Equal(t, NodeTemplate{Type: "router", RadioRange: 200, ClockDrift: 10,
+		Vars: map[string]string{"a": "1", "b": "1"}}, *resolved["base"])
+	assert.Equal(t, NodeTemplate{Extends: "base", Type: "sed", RadioRange: 200, ClockDrift: 10, PollPeriod: 500,
+		Vars: map[string]string{"a": "1", "b": "2"}}, *resolved["sleepy"])
+	assert.Equal(t, NodeTemplate{Extends: "sleepy", Type: "sed", RadioRange: 200, ClockDrift: -20, CpuFactor: 0.5,
+		PollPeriod: 500, Vars: map[string]string{"a": "1", "b": "2", "c": "3"}}, *resolved["slow"])
+
+	// the templates are not modified, and the resolved templates do not share their variables
+	assert.Equal(t, map[string]string{"b": "2"}, sleepy.Vars)
+	resolved["base"].Vars["a"] = "x"
+	assert.Equal(t, "1", base.Vars["a"])
+
+	// a nil template is empty
+	templates = map[string]*NodeTemplate{"empty": nil}
+	_, err = ResolveNodeTemplates(templates)
+	assert.EqualError(t, err, "template empty: no node type")
+	assert.Nil(t, templates["empty"])
+
+	for _, tc := range []struct {
+		templates map[string]*NodeTemplate
+		err       string
+	}{
+		{templates: map[string]*NodeTemplate{"a": {Extends: "a", Type: "router"}}, err: "template a extends itself"},
+		{templates: map[string]*NodeTemplate{"a": {Extends: "b", Type: "router"}, "b": {Extends: "c"},
+			"c": {Extends: "a"}}, err: "template a extends itself"},
+		{templates: map[string]*NodeTemplate{"a": {Extends: "b", Type: "router"}},
+			err: "template a extends unknown template b"},
+		// the type is inherited
+		{templates: map[string]*NodeTemplate{"a": {Type: "med"}, "b": {Extends: "a"}}},
+		{templates: map[string]*NodeTemplate{"a": {Type: "leader"}}, err: "template a: invalid node type: leader"},
+		{templates: map[string]*NodeTemplate{"my-node": {Type: "router"}}, err: `invalid template name: "my-node"`},
+		{templates: map[string]*NodeTemplate{"sed": {Type: "sed"}}, err: "template sed: name is a node type"},
+		{templates: map[string]*NodeTemplate{"a": {Type: "router", PollPeriod: 100}},
+			err: "template a: poll period only applies to sed"},
+		{templates: map[string]*NodeTemplate{"a": {Type: "sed"}, "b": {Extends: "a", Type: "med", PollPeriod: 100}},
+			err: "template b: poll period only applies to sed"},
+		{templates: map[string]*NodeTemplate{"a": {Type: "router", ClockDrift: 200000}},
+			err: "template a: invalid clock drift: 200000ppm"},
+		{templates: map[string]*NodeTemplate{"a": {Type: "router", Vars: map[string]string{"1x": ""}}},
+			err: `template a: invalid variable name: "1x"`},
+	} {
+		_, err := ResolveNodeTemplates(tc.templates)
+		if tc.err == "" {
+			assert.Nil(t, err, "%v", tc.templates)
+			continue
+		}
+		assert.EqualError(t, err, tc.err, "%v", tc.templates)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// maxCliLineLength is the maximum length of a command line of the OT CLI (OPENTHREAD_CONFIG_CLI_MAX_LINE_LENGTH).
+const maxCliLineLength = 384
+
+// ProvisionKind is the kind of blob provisioned to a node.
+type ProvisionKind string
+
+const (
+	// ProvisionDataset provisions an active operational dataset in MeshCoP TLV format.
+	ProvisionDataset ProvisionKind = "dataset"
+)
+
+// MeshCoP TLV types of the operational dataset which can be set by dataset CLI commands.
+const (
+	datasetTlvChannel          = 0
+	datasetTlvPanId            = 1
+	datasetTlvExtPanId         = 2
+	datasetTlvNetworkName      = 3
+	datasetTlvPskc             = 4
+	datasetTlvNetworkKey       = 5
+	datasetTlvMeshLocalPrefix  = 7
+	datasetTlvActiveTimestamp  = 14
+	datasetTlvPendingTimestamp = 51
+	datasetTlvDelayTimer       = 52
+)
+
+// ProvisionResult is the result of provisioning a blob to a node.
+type ProvisionResult struct {
+	Node     NodeId        `json:"node"`
+	Kind     ProvisionKind `json:"kind"`
+	Bytes    int           `json:"bytes"`
+	Commands []string      `json:"commands"` // CLI commands sent to the node, in order
+}
+
+type datasetTlv struct {
+	Type  byte
+	Value []byte
+}
+
+// ParseProvisionBlob parses a blob in hex format. Whitespace, including line breaks, is ignored.
+func ParseProvisionBlob(data []byte) ([]byte, error) {
+	text := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(data))
+
+	blob, err := hex.DecodeString(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hex blob")
+	}
+	if len(blob) == 0 {
+		return nil, errors.Errorf("empty blob")
+	}
+	return blob, nil
+}
+
+func parseDatasetTlvs(data []byte) ([]datasetTlv, error) {
+	var tlvs []datasetTlv
+	for len(data) > 0 {
+		if len(data) < 2 || len(data) < 2+int(data[1]) {
+			return nil, errors.Errorf("truncated dataset TLV")
+		}
+		end := 2 + int(data[1])
+		tlvs = append(tlvs, datasetTlv{Type: data[0], Value: data[2:end]})
+		data = data[end:]
+	}
+	return tlvs, nil
+}
+
+func encodeDatasetTlvs(tlvs []datasetTlv) string {
+	var buf bytes.Buffer
+	for _, tlv := range tlvs {
+		buf.WriteByte(tlv.Type)
+		buf.WriteByte(byte(len(tlv.Value)))
+		buf.Write(tlv.Value)
+	}
+	return hex.EncodeToString(buf.Bytes())
+}
+
+// command returns the dataset CLI command setting the TLV, or false if there is none.
+func (tlv datasetTlv) command() (string, bool) {
+	v := tlv.Value
+	switch {
+	case tlv.Type == datasetTlvChannel && len(v) == 3 && v[0] == 0:
+		return fmt.Sprintf("dataset channel %d", binary.BigEndian.Uint16(v[1:])), true
+	case tlv.Type == datasetTlvPanId && len(v) == 2:
+		return fmt.Sprintf("dataset panid 0x%04x", binary.BigEndian.Uint16(v)), true
+	case tlv.Type == datasetTlvExtPanId && len(v) == 8:
+		return fmt.Sprintf("dataset extpanid %x", v), true
+	case tlv.Type == datasetTlvNetworkName && len(v) > 0 && isCliWord(string(v)):
+		return fmt.Sprintf("dataset networkname %s", v), true
+	case tlv.Type == datasetTlvPskc && len(v) == 16:
+		return fmt.Sprintf("dataset pskc %x", v), true
+	case tlv.Type == datasetTlvNetworkKey && len(v) == 16:
+		return fmt.Sprintf("dataset networkkey %x", v), true
+	case tlv.Type == datasetTlvMeshLocalPrefix && len(v) == 8:
+		return fmt.Sprintf("dataset meshlocalprefix %x:%x:%x:%x::", v[0:2], v[2:4], v[4:6], v[6:8]), true
+	case tlv.Type == datasetTlvActiveTimestamp && len(v) == 8 && binary.BigEndian.Uint16(v[6:]) == 0:
+		// only timestamps without ticks and authoritative bit can be set by the CLI
+		return fmt.Sprintf("dataset activetimestamp %d", binary.BigEndian.Uint64(v)>>16), true
+	case tlv.Type == datasetTlvPendingTimestamp && len(v) == 8 && binary.BigEndian.Uint16(v[6:]) == 0:
+		return fmt.Sprintf("dataset pendingtimestamp %d", binary.BigEndian.Uint64(v)>>16), true
+	case tlv.Type == datasetTlvDelayTimer && len(v) == 4:
+		return fmt.Sprintf("dataset delay %d", binary.BigEndian.Uint32(v)), true
+	default:
+		return "", false
+	}
+}
+
+func isCliWord(s string) bool {
+	for _, r := range s {
+		if r <= ' ' || r > '~' || r == '"' || r == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// datasetCommands returns the CLI commands setting the active dataset of the TLVs. The dataset is set by a single
+// command if it fits in a CLI line. Otherwise, the TLVs without dataset commands and as many others as fit are set
+// first, and the remaining TLVs are added by their dataset commands.
+func datasetCommands(tlvs []datasetTlv) ([]string, error) {
+	const setCmd = "dataset set active "
+	if len(setCmd)+len(encodeDatasetTlvs(tlvs)) <= maxCliLineLength {
+		return []string{setCmd + encodeDatasetTlvs(tlvs)}, nil
+	}
+
+	var first, rest []datasetTlv
+	for _, tlv := range tlvs {
+		if _, ok := tlv.command(); ok {
+			rest = append(rest, tlv)
+		} else {
+			first = append(first, tlv)
+		}
+	}
+	if len(setCmd)+len(encodeDatasetTlvs(first)) > maxCliLineLength {
+		return nil, errors.Errorf("dataset TLVs without dataset commands exceed the CLI line length")
+	}
+
+	var fieldCmds []string
+	for _, tlv := range rest {
+		if len(setCmd)+len(encodeDatasetTlvs(append(first, tlv))) <= maxCliLineLength {
+			first = append(first, tlv)
+		} else {
+			cmd, _ := tlv.command()
+			fieldCmds = append(fieldCmds, cmd)
+		}
+	}
+
+	var cmds []string
+	if len(first) > 0 {
+		cmds = append(cmds, setCmd+encodeDatasetTlvs(first), "dataset init active")
+	} else {
+		cmds = append(cmds, "dataset clear")
+	}
+	cmds = append(cmds, fieldCmds...)
+	return append(cmds, "dataset commit active"), nil
+}
+
+// verifyDataset checks that the dataset read from the node contains all TLVs with the same values.
+func verifyDataset(expected []datasetTlv, actualHex string) error {
+	data, err := hex.DecodeString(actualHex)
+	if err != nil {
+		return errors.Errorf("invalid active dataset: %s", actualHex)
+	}
+	actual, err := parseDatasetTlvs(data)
+	if err != nil {
+		return err
+	}
+
+	values := map[byte][]byte{}
+	for _, tlv := range actual {
+		values[tlv.Type] = tlv.Value
+	}
+	for _, tlv := range expected {
+		if v, ok := values[tlv.Type]; !ok || !bytes.Equal(v, tlv.Value) {
+			return errors.Errorf("TLV %d is %x, expected %x", tlv.Type, v, tlv.Value)
+		}
+	}
+	return nil
+}
+
+// Provision pushes the blob to the node by CLI commands which fit in the CLI line length, and verifies it by reading
+// it back.
+func (s *Simulation) Provision(id NodeId, kind ProvisionKind, blob []byte) (*ProvisionResult, error) {
+	if kind != ProvisionDataset {
+		return nil, errors.Errorf("unknown provision kind: %s", kind)
+	}
+	if s.nodes[id] == nil {
+		return nil, errors.Errorf("node %d not found", id)
+	}
+
+	tlvs, err := parseDatasetTlvs(blob)
+	if err != nil {
+		return nil, err
+	}
+	cmds, err := datasetCommands(tlvs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProvisionResult{Node: id, Kind: kind, Bytes: len(blob)}
+	for _, cmd := range cmds {
+		result.Commands = append(result.Commands, cmd)
+		if res := s.ExecCommand([]NodeId{id}, cmd, DefaultCommandTimeout)[0]; res.Error != "" {
+			return result, errors.Errorf("%s: %s", cmd, res.Error)
+		}
+	}
+
+	res := s.ExecCommand([]NodeId{id}, "dataset active -x", DefaultCommandTimeout)[0]
+	if res.Error != "" {
+		return result, errors.Errorf("read back dataset failed: %s", res.Error)
+	}
+	if len(res.Output) != 1 {
+		return result, errors.Errorf("read back dataset failed: unexpected output %q", res.Output)
+	}
+	if err = verifyDataset(tlvs, res.Output[0]); err != nil {
+		return result, errors.Wrap(err, "verify failed")
+	}
+	return result, nil
+}
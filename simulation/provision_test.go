@@ -0,0 +1,157 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// output of `dataset active -x` on the OT CLI: active timestamp, channel, channel mask, extended PAN ID, mesh local
+// prefix, network key, network name, PAN ID, PSKc and security policy
+const provisionDatasetHex = "0e080000000000010000000300000f35060004001fffe00208dead00beef00cafe0708fddead00beef0000" +
+	"051000112233445566778899aabbccddeeff030a4f70656e5468726561640102face04103ca67c969efb0d0c74a4d8ee923b576c" +
+	"0c0402a0f7f8"
+
+func TestParseDatasetTlvs(t *testing.T) {
+	data, _ := hex.DecodeString(provisionDatasetHex)
+	tlvs, err := parseDatasetTlvs(data)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(tlvs))
+	assert.Equal(t, datasetTlv{Type: datasetTlvChannel, Value: []byte{0, 0, 15}}, tlvs[1])
+	assert.Equal(t, datasetTlv{Type: datasetTlvNetworkName, Value: []byte("OpenThread")}, tlvs[6])
+	assert.Equal(t, provisionDatasetHex, encodeDatasetTlvs(tlvs))
+
+	for _, tc := range []struct {
+		data string
+		err  bool
+	}{
+		{data: ""},
+		{data: "0300"},
+		{data: "00", err: true},
+		{data: "000300000f01", err: true},
+		{data: "0102fa", err: true},
+	} {
+		data, _ := hex.DecodeString(tc.data)
+		tlvs, err := parseDatasetTlvs(data)
+		if tc.err {
+			assert.EqualError(t, err, "truncated dataset TLV", "%s", tc.data)
+			continue
+		}
+		assert.Nil(t, err, "%s", tc.data)
+		assert.Equal(t, tc.data, encodeDatasetTlvs(tlvs), "%s", tc.data)
+	}
+}
+
+func TestDatasetTlvCommand(t *testing.T) {
+	for _, tc := range []struct {
+		tlv string
+		cmd string // "" if the TLV has no command
+	}{
+		{tlv: "000300000f", cmd: "dataset channel 15"},
+		{tlv: "000301000f"},
+		{tlv: "0102face", cmd: "dataset panid 0xface"},
+		{tlv: "0208dead00beef00cafe", cmd: "dataset extpanid dead00beef00cafe"},
+		{tlv: "030a4f70656e546872656164", cmd: "dataset networkname OpenThread"},
+		// network names which are not a single CLI word
+		{tlv: "0303412042"},
+		{tlv: "0303412242"},
+		{tlv: "0300"},
+		{tlv: "04103ca67c969efb0d0c74a4d8ee923b576c", cmd: "dataset pskc 3ca67c969efb0d0c74a4d8ee923b576c"},
+		{tlv: "051000112233445566778899aabbccddeeff", cmd: "dataset networkkey 00112233445566778899aabbccddeeff"},
+		{tlv: "0708fddead00beef0000", cmd: "dataset meshlocalprefix fdde:ad00:beef:0000::"},
+		{tlv: "0e080000000000010000", cmd: "dataset activetimestamp 1"},
+		// timestamps with ticks or the authoritative bit
+		{tlv: "0e080000000000010001"},
+		{tlv: "330800000000002a0000", cmd: "dataset pendingtimestamp 42"},
+		{tlv: "340400007530", cmd: "dataset delay 30000"},
+		{tlv: "35060004001fffe0"},
+		{tlv: "0c0402a0f7f8"},
+	} {
+		data, _ := hex.DecodeString(tc.tlv)
+		tlvs, err := parseDatasetTlvs(data)
+		assert.Nil(t, err, "%s", tc.tlv)
+		cmd, ok := tlvs[0].command()
+		assert.Equal(t, tc.cmd != "", ok, "%s", tc.tlv)
+		assert.Equal(t, tc.cmd, cmd, "%s", tc.tlv)
+	}
+}
+
+func TestDatasetCommands(t *testing.T) {
+	data, _ := hex.DecodeString(provisionDatasetHex)
+	tlvs, _ := parseDatasetTlvs(data)
+	cmds, err := datasetCommands(tlvs)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dataset set active " + provisionDatasetHex}, cmds)
+
+	// a vendor TLV makes the dataset exceed the CLI line: the TLVs without commands and the first TLVs with commands
+	// are set first, and the others by their commands
+	vendorTlv := "8096" + strings.Repeat("ab", 150)
+	data, _ = hex.DecodeString(provisionDatasetHex + vendorTlv)
+	tlvs, _ = parseDatasetTlvs(data)
+	cmds, err = datasetCommands(tlvs)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"dataset set active 35060004001fffe00c0402a0f7f8" + vendorTlv + "0e080000000000010000000300000f",
+		"dataset init active",
+		"dataset extpanid dead00beef00cafe",
+		"dataset meshlocalprefix fdde:ad00:beef:0000::",
+		"dataset networkkey 00112233445566778899aabbccddeeff",
+		"dataset networkname OpenThread",
+		"dataset panid 0xface",
+		"dataset pskc 3ca67c969efb0d0c74a4d8ee923b576c",
+		"dataset commit active",
+	}, cmds)
+	for _, cmd := range cmds {
+		assert.LessOrEqual(t, len(cmd), maxCliLineLength)
+	}
+
+	// the TLVs set by the first command and by the dataset commands are all TLVs
+	first, _ := hex.DecodeString(strings.TrimPrefix(cmds[0], "dataset set active "))
+	firstTlvs, _ := parseDatasetTlvs(first)
+	assert.Equal(t, len(tlvs), len(firstTlvs)+len(cmds)-3)
+
+	// the TLVs without commands must fit in one line
+	data, _ = hex.DecodeString(vendorTlv + strings.Replace(vendorTlv, "80", "81", 1))
+	tlvs, _ = parseDatasetTlvs(data)
+	_, err = datasetCommands(tlvs)
+	assert.EqualError(t, err, "dataset TLVs without dataset commands exceed the CLI line length")
+}
+
+func TestVerifyDataset(t *testing.T) {
+	data, _ := hex.DecodeString("000300000f0102face")
+	expected, _ := parseDatasetTlvs(data)
+
+	assert.Nil(t, verifyDataset(expected, "0102face000300000f0c0402a0f7f8"))
+	assert.EqualError(t, verifyDataset(expected, "000300000f0102abcd"), "TLV 1 is abcd, expected face")
+	assert.EqualError(t, verifyDataset(expected, "000300000f"), "TLV 1 is , expected face")
+	assert.EqualError(t, verifyDataset(expected, "0003"), "truncated dataset TLV")
+	assert.EqualError(t, verifyDataset(expected, "Error 23: NotFound"), `invalid active dataset: Error 23: NotFound`)
+}
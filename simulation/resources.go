@@ -0,0 +1,250 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	// DefaultResourceSampleInterval is the default wall-clock interval of sampling the resource usage of node processes.
+	DefaultResourceSampleInterval = time.Second * 10
+	// SpinningCpuPercent is the CPU usage above which a node process is reported as spinning.
+	SpinningCpuPercent = 90
+
+	clockTicksPerSecond = 100 // USER_HZ, the unit of CPU times in /proc
+)
+
+// NodeResources is the resource usage of a node process as of the last sample.
+type NodeResources struct {
+	Node       NodeId  `json:"node"`
+	Pid        int     `json:"pid"`
+	RssGrowth  int64   `json:"rss_growth_bytes"` // RSS increase since the first sample of the process
+	CpuPercent float64 `json:"cpu_percent"`      // CPU usage between the last two samples
+	dispatcher.ResourceUsage
+}
+
+// ResourceFootprint is the total resource usage of the simulation.
+type ResourceFootprint struct {
+	Nodes        int    `json:"nodes"`          // number of sampled node processes
+	RssBytes     uint64 `json:"rss_bytes"`      // total RSS of node processes
+	PeakRssBytes uint64 `json:"peak_rss_bytes"` // total peak RSS of node processes
+	CpuTime      uint64 `json:"cpu_us"`         // total CPU time of node processes
+	OtnsRssBytes uint64 `json:"otns_rss_bytes"` // RSS of the OTNS process
+	OtnsCpuTime  uint64 `json:"otns_cpu_us"`    // CPU time of the OTNS process
+}
+
+// resourceSampler keeps the resource usage samples of a node process.
+type resourceSampler struct {
+	pid        int
+	firstRss   uint64
+	last       dispatcher.ResourceUsage
+	lastTime   time.Time
+	cpuPercent float64
+	spinning   bool
+}
+
+// FormatBytes formats the memory size in MB.
+func FormatBytes(n uint64) string {
+	return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+}
+
+// readProcessUsage reads the resource usage of the process from /proc, which is only available on Linux.
+func readProcessUsage(pid string) (usage dispatcher.ResourceUsage, err error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%s/stat", pid))
+	if err != nil {
+		return
+	}
+
+	// the command name in parentheses may contain spaces, so fields are counted from the closing parenthesis
+	idx := bytes.LastIndexByte(stat, ')')
+	if idx < 0 {
+		return usage, errors.Errorf("invalid /proc/%s/stat", pid)
+	}
+	fields := strings.Fields(string(stat[idx+1:]))
+	if len(fields) < 13 {
+		return usage, errors.Errorf("invalid /proc/%s/stat", pid)
+	}
+	var ticks uint64
+	for _, field := range fields[11:13] { // utime and stime
+		val, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return usage, errors.Wrapf(err, "invalid /proc/%s/stat", pid)
+		}
+		ticks += val
+	}
+	usage.CpuTime = ticks * 1000000 / clockTicksPerSecond
+
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%s/status", pid))
+	if err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		name, kb, ok := parseStatusLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "VmRSS":
+			usage.RssBytes = kb * 1024
+		case "VmHWM":
+			usage.PeakRssBytes = kb * 1024
+		}
+	}
+	return
+}
+
+// parseStatusLine parses a memory line of /proc/<pid>/status such as `VmRSS:	    1234 kB`.
+func parseStatusLine(line string) (name string, kb uint64, ok bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return
+	}
+
+	fields := strings.Fields(line[idx+1:])
+	if len(fields) != 2 || fields[1] != "kB" {
+		return
+	}
+
+	kb, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return
+	}
+	return line[:idx], kb, true
+}
+
+// sampleResources samples the resource usage of the node process.
+func (node *Node) sampleResources(now time.Time) (dispatcher.ResourceUsage, error) {
+	if node.cmd.Process == nil {
+		return dispatcher.ResourceUsage{}, errors.Errorf("process not started")
+	}
+
+	pid := node.cmd.Process.Pid
+	usage, err := readProcessUsage(strconv.Itoa(pid))
+	if err != nil {
+		return usage, err
+	}
+
+	rs := &node.resources
+	if rs.pid != pid {
+		// first sample of the process
+		*rs = resourceSampler{pid: pid, firstRss: usage.RssBytes}
+	} else if elapsed := now.Sub(rs.lastTime); elapsed >= time.Millisecond*100 && usage.CpuTime >= rs.last.CpuTime {
+		rs.cpuPercent = float64(usage.CpuTime-rs.last.CpuTime) * 100 / float64(elapsed/time.Microsecond)
+	}
+	rs.last = usage
+	rs.lastTime = now
+
+	if rs.cpuPercent >= SpinningCpuPercent && !rs.spinning {
+		simplelogger.Warnf("%v - process is spinning at %.0f%% CPU", node, rs.cpuPercent)
+	}
+	rs.spinning = rs.cpuPercent >= SpinningCpuPercent
+	return usage, nil
+}
+
+// Resources returns the resource usage of the node process as of the last sample, or false if not sampled.
+func (node *Node) Resources() (NodeResources, bool) {
+	rs := &node.resources
+	if rs.pid == 0 {
+		return NodeResources{}, false
+	}
+
+	return NodeResources{
+		Node:          node.Id,
+		Pid:           rs.pid,
+		RssGrowth:     int64(rs.last.RssBytes) - int64(rs.firstRss),
+		CpuPercent:    rs.cpuPercent,
+		ResourceUsage: rs.last,
+	}, true
+}
+
+// CollectResourceUsage samples the resource usage of all node processes and records them in the dispatcher for the
+// KPI.
+func (s *Simulation) CollectResourceUsage() {
+	now := time.Now()
+	s.VisitNodesInOrder(func(node *Node) {
+		usage, err := node.sampleResources(now)
+		if err != nil {
+			simplelogger.Debugf("%v - sample resource usage failed: %v", node, err)
+			return
+		}
+		s.d.SetResourceUsage(node.Id, usage)
+	})
+}
+
+// ResourceFootprint returns the total resource usage of the node processes as of the last samples, and the current
+// resource usage of the OTNS process.
+func (s *Simulation) ResourceFootprint() ResourceFootprint {
+	var fp ResourceFootprint
+	for _, node := range s.nodes {
+		r, ok := node.Resources()
+		if !ok {
+			continue
+		}
+
+		fp.Nodes++
+		fp.RssBytes += r.RssBytes
+		fp.PeakRssBytes += r.PeakRssBytes
+		fp.CpuTime += r.CpuTime
+	}
+
+	if usage, err := readProcessUsage("self"); err == nil {
+		fp.OtnsRssBytes = usage.RssBytes
+		fp.OtnsCpuTime = usage.CpuTime
+	}
+	return fp
+}
+
+// sampleResourcesPeriodically samples the resource usage of node processes at the interval until the simulation stops.
+func (s *Simulation) sampleResourcesPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.PostAsync(true, s.CollectResourceUsage)
+		}
+	}
+}
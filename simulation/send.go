@@ -0,0 +1,384 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	SendUdpPort         = 10000
+	FragDefaultDataSize = 256 // UDP payload size fragmented into 3 frames
+	sendPayloadPrefix   = "otns"
+	sendPayloadSeqLen   = 6
+	sendMinPayloadSize  = len(sendPayloadPrefix) + sendPayloadSeqLen
+	udpAlreadyOpenError = "Error 24: Already" // result of "udp open" if the socket is open
+)
+
+var (
+	// e.x. 10 bytes from fdde:ad00:beef:0:0:ff:fe00:fc00 10000 otns000001
+	sendRecvRegexp = regexp.MustCompile(`^\d+ bytes from \S+ \d+ ` + sendPayloadPrefix + `(\d{6})`)
+
+	// all-nodes multicast groups of each scope
+	SendScopeGroups = map[string]string{
+		"link":  "ff02::1",
+		"realm": "ff03::1",
+	}
+)
+
+type sendSession struct {
+	Seq       int
+	Src       NodeId
+	Group     string
+	Timestamp uint64
+	Expected  map[NodeId]struct{} // nodes subscribed to the group when sending
+	Received  map[NodeId]struct{}
+}
+
+// SendDelivery contains the delivery result of multicast messages from a source to a receiver for one group.
+type SendDelivery struct {
+	Group    string
+	Src      NodeId
+	Dst      NodeId
+	Sent     int
+	Received int
+}
+
+func (sd *SendDelivery) Ratio() float64 {
+	if sd.Sent == 0 {
+		return 0
+	}
+	return float64(sd.Received) / float64(sd.Sent)
+}
+
+// sendTracker tracks multicast messages sent by Simulation.SendMulticast and their reception by each node.
+// Receptions are reported from the node output routines, so the tracker is protected by a lock.
+type sendTracker struct {
+	sync.Mutex
+	nextSeq  int
+	sessions map[int]*sendSession
+}
+
+func newSendTracker() *sendTracker {
+	return &sendTracker{
+		nextSeq:  1,
+		sessions: map[int]*sendSession{},
+	}
+}
+
+func (st *sendTracker) newSession(src NodeId, group string, timestamp uint64, expected map[NodeId]struct{}) *sendSession {
+	st.Lock()
+	defer st.Unlock()
+
+	ss := &sendSession{
+		Seq:       st.nextSeq,
+		Src:       src,
+		Group:     group,
+		Timestamp: timestamp,
+		Expected:  expected,
+		Received:  map[NodeId]struct{}{},
+	}
+	st.sessions[ss.Seq] = ss
+	st.nextSeq += 1
+	return ss
+}
+
+func (st *sendTracker) onNodeOutput(id NodeId, line string) {
+	m := sendRecvRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+
+	seq, _ := strconv.Atoi(m[1])
+
+	st.Lock()
+	defer st.Unlock()
+
+	if ss := st.sessions[seq]; ss != nil && ss.Src != id {
+		ss.Received[id] = struct{}{}
+	}
+}
+
+func (st *sendTracker) report() []*SendDelivery {
+	st.Lock()
+	defer st.Unlock()
+
+	type deliveryKey struct {
+		Group    string
+		Src, Dst NodeId
+	}
+	deliveries := map[deliveryKey]*SendDelivery{}
+
+	count := func(ss *sendSession, dst NodeId, received bool) {
+		key := deliveryKey{ss.Group, ss.Src, dst}
+		sd := deliveries[key]
+		if sd == nil {
+			sd = &SendDelivery{Group: ss.Group, Src: ss.Src, Dst: dst}
+			deliveries[key] = sd
+		}
+		sd.Sent += 1
+		if received {
+			sd.Received += 1
+		}
+	}
+
+	for _, ss := range st.sessions {
+		for dst := range ss.Expected {
+			_, received := ss.Received[dst]
+			count(ss, dst, received)
+		}
+		for dst := range ss.Received {
+			if _, expected := ss.Expected[dst]; !expected {
+				count(ss, dst, true)
+			}
+		}
+	}
+
+	report := make([]*SendDelivery, 0, len(deliveries))
+	for _, sd := range deliveries {
+		report = append(report, sd)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Src != b.Src {
+			return a.Src < b.Src
+		}
+		return a.Dst < b.Dst
+	})
+	return report
+}
+
+func (st *sendTracker) reset() {
+	st.Lock()
+	defer st.Unlock()
+
+	st.sessions = map[int]*sendSession{}
+}
+
+// SendMulticast sends UDP messages from the source node to the multicast group, one every interval (in us).
+// Nodes subscribed to the group when the job starts are expected to receive each message, and the delivery to each of
+// them is tracked for SendReport. The messages are sent by a background job, which is returned.
+func (s *Simulation) SendMulticast(src NodeId, group string, count int, interval uint64, datasize int) (*Job, error) {
+	if s.nodes[src] == nil {
+		return nil, errors.Errorf("node %d not found", src)
+	}
+
+	if ip := net.ParseIP(group); ip == nil || !ip.IsMulticast() {
+		return nil, errors.Errorf("invalid multicast address: %s", group)
+	}
+
+	if count <= 0 {
+		return nil, errors.Errorf("invalid count: %d", count)
+	}
+
+	if datasize < sendMinPayloadSize {
+		datasize = sendMinPayloadSize
+	}
+
+	expected, err := s.multicastReceivers(src, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startSendJob(src, group, count, interval, func() (*sendSession, error) {
+		return s.sendMulticastOnce(src, group, expected, datasize)
+	}), nil
+}
+
+// startSendJob starts a background job which sends a message with send at once and then at the interval (in us).
+func (s *Simulation) startSendJob(src NodeId, dst string, count int, interval uint64,
+	send func() (*sendSession, error)) *Job {
+	job := s.startJob(JobSend, src, dst, count, interval, uint64(count-1)*interval+JobSendWaitUs)
+	for i := 0; i < count; i++ {
+		task := func() {
+			if job.State != JobRunning {
+				return
+			}
+
+			job.Sent++
+			ss, err := send()
+			if err != nil {
+				simplelogger.Errorf("node %d send to %s failed: %v", src, dst, err)
+				return
+			}
+			job.sessions = append(job.sessions, ss)
+		}
+
+		if i == 0 {
+			task()
+		} else {
+			s.d.ScheduleAt(s.d.CurTime+uint64(i)*interval, task)
+		}
+	}
+	return job
+}
+
+// multicastReceivers binds the UDP port of the nodes, and returns the nodes other than src which are subscribed to the
+// group.
+func (s *Simulation) multicastReceivers(src NodeId, group string) (receivers map[NodeId]struct{}, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			receivers, err = nil, errors.Errorf("%v", e)
+		}
+	}()
+
+	receivers = map[NodeId]struct{}{}
+	s.VisitNodesInOrder(func(node *Node) {
+		if dnode := s.d.GetNode(node.Id); dnode == nil || dnode.IsPaused() {
+			return
+		}
+
+		node.udpBind(SendUdpPort)
+		if node.Id != src && node.isSubscribed(group) {
+			receivers[node.Id] = struct{}{}
+		}
+	})
+	return
+}
+
+func (s *Simulation) sendMulticastOnce(src NodeId, group string, expected map[NodeId]struct{},
+	datasize int) (ss *sendSession, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = errors.Errorf("%v", e)
+		}
+	}()
+
+	srcnode := s.nodes[src]
+	if srcnode == nil {
+		return nil, errors.Errorf("node %d not found", src)
+	}
+
+	return s.sendUdp(srcnode, group, expected, datasize), nil
+}
+
+func (s *Simulation) sendUdp(srcnode *Node, dstAddr string, expected map[NodeId]struct{}, datasize int) *sendSession {
+	ss := s.sendTracker.newSession(srcnode.Id, dstAddr, s.d.CurTime, expected)
+	payload := fmt.Sprintf("%s%0*d", sendPayloadPrefix, sendPayloadSeqLen, ss.Seq)
+	payload += strings.Repeat("x", datasize-len(payload))
+	srcnode.Command(fmt.Sprintf("udp send %s %d %s", dstAddr, SendUdpPort, payload), DefaultCommandTimeout)
+	return ss
+}
+
+// SendUnicast sends UDP messages from the source node to the mesh-local EID of the destination node, one every
+// interval (in us). Messages larger than a frame are fragmented, which is summarized by the dispatcher frag stats.
+// The delivery is tracked for SendReport like multicast messages, with the destination address as group. The messages
+// are sent by a background job, which is returned.
+func (s *Simulation) SendUnicast(src NodeId, dst NodeId, count int, interval uint64, datasize int) (*Job, error) {
+	if s.nodes[src] == nil {
+		return nil, errors.Errorf("node %d not found", src)
+	}
+
+	if s.nodes[dst] == nil || dst == src {
+		return nil, errors.Errorf("invalid destination node: %d", dst)
+	}
+
+	if count <= 0 {
+		return nil, errors.Errorf("invalid count: %d", count)
+	}
+
+	if datasize < sendMinPayloadSize {
+		datasize = sendMinPayloadSize
+	}
+
+	return s.startSendJob(src, strconv.Itoa(dst), count, interval, func() (*sendSession, error) {
+		return s.sendUnicastOnce(src, dst, datasize)
+	}), nil
+}
+
+func (s *Simulation) sendUnicastOnce(src NodeId, dst NodeId, datasize int) (ss *sendSession, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = errors.Errorf("%v", e)
+		}
+	}()
+
+	srcnode, dstnode := s.nodes[src], s.nodes[dst]
+	if srcnode == nil || dstnode == nil {
+		return nil, errors.Errorf("node %d or %d not found", src, dst)
+	}
+
+	mleids := dstnode.GetIpAddrMleid()
+	if len(mleids) == 0 {
+		return nil, errors.Errorf("node %d has no mesh-local EID", dst)
+	}
+
+	dstnode.udpBind(SendUdpPort)
+	srcnode.udpBind(SendUdpPort)
+	return s.sendUdp(srcnode, strings.TrimSpace(mleids[0]), map[NodeId]struct{}{dst: {}}, datasize), nil
+}
+
+// SendReport returns the multicast delivery results of each group, source and receiver.
+func (s *Simulation) SendReport() []*SendDelivery {
+	return s.sendTracker.report()
+}
+
+// ResetSendReport discards all tracked multicast messages.
+func (s *Simulation) ResetSendReport() {
+	s.sendTracker.reset()
+}
+
+func (node *Node) udpBind(port int) {
+	if node.udpPort == port {
+		return
+	}
+
+	func() {
+		// the socket may have been opened already, e.g. by a user command
+		defer func() {
+			if e := recover(); e != nil && e != udpAlreadyOpenError {
+				panic(e)
+			}
+		}()
+		node.Command("udp open", DefaultCommandTimeout)
+	}()
+	node.Command(fmt.Sprintf("udp bind :: %d", port), DefaultCommandTimeout)
+	node.udpPort = port
+}
+
+func (node *Node) isSubscribed(group string) bool {
+	groupIp := net.ParseIP(group)
+	for _, addr := range node.GetIpMaddr() {
+		if ip := net.ParseIP(strings.TrimSpace(addr)); ip != nil && ip.Equal(groupIp) {
+			return true
+		}
+	}
+	return false
+}
@@ -27,28 +27,71 @@
 package simulation
 
 import (
+	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/openthread/ot-ns/progctx"
 
 	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/geo"
+	"github.com/openthread/ot-ns/threadconst"
 	. "github.com/openthread/ot-ns/types"
 	"github.com/openthread/ot-ns/visualize"
+	visualizeMulti "github.com/openthread/ot-ns/visualize/multi"
+	visualizeStatslog "github.com/openthread/ot-ns/visualize/statslog"
 	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
 type Simulation struct {
-	ctx         *progctx.ProgCtx
-	cfg         *Config
-	nodes       map[NodeId]*Node
-	d           *dispatcher.Dispatcher
-	vis         visualize.Visualizer
-	cmdRunner   CmdRunner
-	rawMode     bool
-	networkInfo visualize.NetworkInfo
+	ctx           *progctx.ProgCtx
+	cfg           *Config
+	nodes         map[NodeId]*Node
+	pendingIds    map[NodeId]struct{}
+	sendTracker   *sendTracker
+	netDiag       *netDiagCollector
+	energyScan    *energyScanCollector
+	d             *dispatcher.Dispatcher
+	vis           visualize.Visualizer
+	statsLog      *visualizeStatslog.StatslogVisualizer
+	cmdRunner     CmdRunner
+	rawMode       bool
+	networkInfo   visualize.NetworkInfo
+	geoOrigin     *geo.Origin
+	radioRange    int
+	gridSnap      int // grid size which node positions snap to, or 0
+	initScript    string
+	scriptVars    map[string]string
+	nodeTemplates map[string]*NodeTemplate
+	healthCfg     HealthConfig
+	healthEvents  []*HealthEvent
+	zombieRestart bool   // handle zombie nodes by the health policy
+	cmdBudget     uint64 // virtual-time budget of node commands in us, or 0 to run them at a fixed virtual time
+	cmdCosts      []*CommandCost
+	startTime     time.Time
+	autoSpeed     *autoSpeedTuner // adjusts the speed to the target host CPU usage, or nil
+	jobs          jobManager
+	linkMetrics   *linkMetricsCollector
+	linkProbes    map[linkMetricsPair]*LinkMetricsProbe // running Link Metrics probes by node pair
+	macSampling   int                                   // generation of the MAC counter sampling of statistics windows
+}
+
+// openStatsLogSink opens the sink of the node stats timeline, or returns nil if none is configured or it fails to open.
+func openStatsLogSink(cfg *Config) visualizeStatslog.Sink {
+	if cfg.StatsLogFile == "" {
+		return nil
+	}
+
+	sink, err := visualizeStatslog.OpenSink(cfg.StatsLogFormat, cfg.StatsLogFile)
+	if err != nil {
+		simplelogger.Errorf("open statslog %s failed: %+v", cfg.StatsLogFile, err)
+		return nil
+	}
+	return sink
 }
 
 func NewSimulation(ctx *progctx.ProgCtx, cfg *Config, dispatcherCfg *dispatcher.Config) (*Simulation, error) {
@@ -56,10 +99,26 @@ func NewSimulation(ctx *progctx.ProgCtx, cfg *Config, dispatcherCfg *dispatcher.
 		ctx:         ctx,
 		cfg:         cfg,
 		nodes:       map[NodeId]*Node{},
+		pendingIds:  map[NodeId]struct{}{},
+		sendTracker: newSendTracker(),
+		netDiag:     newNetDiagCollector(),
+		energyScan:  newEnergyScanCollector(),
+		linkMetrics: newLinkMetricsCollector(),
 		rawMode:     cfg.RawMode,
 		networkInfo: visualize.DefaultNetworkInfo(),
+		statsLog:    visualizeStatslog.NewStatslogVisualizer(openStatsLogSink(cfg)),
+		geoOrigin:   cfg.GeoOrigin,
+		radioRange:  DefaultNodeConfig().RadioRange,
+		initScript:  cfg.InitScript,
+		scriptVars:  map[string]string{},
+		healthCfg:   DefaultHealthConfig(),
+		startTime:   time.Now(),
 	}
 	s.networkInfo.Real = cfg.Real
+	s.nodeTemplates = cfg.NodeTemplates
+	if err := s.prepareCoverageDir(); err != nil {
+		return nil, err
+	}
 
 	// start the event_dispatcher for virtual time
 	if dispatcherCfg == nil {
@@ -71,11 +130,17 @@ func NewSimulation(ctx *progctx.ProgCtx, cfg *Config, dispatcherCfg *dispatcher.
 	dispatcherCfg.Host = cfg.DispatcherHost
 	dispatcherCfg.Port = cfg.DispatcherPort
 	dispatcherCfg.DumpPackets = cfg.DumpPackets
+	dispatcherCfg.LogCorrelation = cfg.LogCorrelation
+	dispatcherCfg.SharedMemory = cfg.SharedMemory
+	dispatcherCfg.TcpAddr = cfg.TcpAddr
+	dispatcherCfg.NetworkKey = cfg.NetworkKey
 
 	s.d = dispatcher.NewDispatcher(s.ctx, dispatcherCfg, s)
+	s.d.SubscribeZombies(s.onNodeZombie)
 	s.vis = s.d.GetVisualizer()
-	if err := s.removeTmpDir(); err != nil {
-		simplelogger.Panicf("remove tmp directory failed: %+v", err)
+	s.startMacCounterSampling()
+	if err := s.removeNodeDirs(); err != nil {
+		simplelogger.Panicf("remove node directories failed: %+v", err)
 	}
 
 	return s, nil
@@ -95,6 +160,10 @@ func (s *Simulation) AddNode(cfg *NodeConfig) (*Node, error) {
 		return nil, errors.Errorf("node %d already exists", nodeid)
 	}
 
+	if s.isPendingId(nodeid) {
+		return nil, errors.Errorf("node %d is scheduled to be added", nodeid)
+	}
+
 	node, err := newNode(s, nodeid, cfg)
 	if err != nil {
 		simplelogger.Errorf("simulation add node failed: %v", err)
@@ -104,28 +173,111 @@ func (s *Simulation) AddNode(cfg *NodeConfig) (*Node, error) {
 	s.nodes[nodeid] = node
 
 	simplelogger.Infof("simulation:CtrlAddNode: %+v, rawMode=%v", cfg, s.rawMode)
-	s.d.AddNode(nodeid, cfg.X, cfg.Y, cfg.RadioRange)
+	x, y := s.SnapToGrid(cfg.X, cfg.Y)
+	err = s.d.AddNodeChecked(nodeid, x, y, cfg.RadioRange, node.hasExited)
+	if err == nil {
+		err = node.detectVirtualTimeUART()
+	}
+	if err != nil {
+		err = s.failNodeStart(node, err)
+		simplelogger.Errorf("simulation add node failed: %v", err)
+		return nil, err
+	}
 
-	node.detectVirtualTimeUART()
+	if cfg.CpuFactor > 0 {
+		s.d.SetNodeCpuFactor(nodeid, cfg.CpuFactor)
+	}
+	if cfg.ClockDrift != 0 {
+		s.d.SetNodeClockDrift(nodeid, cfg.ClockDrift)
+	}
+	if cfg.ThreadVersion != 0 {
+		if err = node.checkThreadVersion(cfg.ThreadVersion); err != nil {
+			simplelogger.Errorf("simulation add node failed: %v", err)
+			_ = s.DeleteNode(nodeid)
+			return nil, err
+		}
+	}
 
 	node.setupMode()
 
 	if !s.rawMode {
-		node.SetupNetworkParameters(s)
+		if cfg.Dataset != nil {
+			if err = node.setActiveDataset(cfg.Dataset); err != nil {
+				simplelogger.Errorf("simulation add node failed: %v", err)
+				_ = s.DeleteNode(nodeid)
+				return nil, err
+			}
+		} else {
+			node.SetupNetworkParameters(s)
+		}
+	}
+
+	if err = s.runInitScript(node); err != nil {
+		simplelogger.Errorf("simulation add node failed: %v", err)
+		_ = s.DeleteNode(nodeid)
+		return nil, err
+	}
+
+	if !s.rawMode {
 		node.Start()
 	}
 
+	if cfg.BootDelay > 0 || cfg.BootCrashProb > 0 {
+		crash := rand.Float64() < cfg.BootCrashProb
+		if err = s.d.DelayNodeBoot(nodeid, uint64(cfg.BootDelay)*1000, crash); err != nil {
+			simplelogger.Errorf("simulation add node failed: %v", err)
+			_ = s.DeleteNode(nodeid)
+			return nil, err
+		}
+	}
+
 	return node, nil
 }
 
+// AddNodeAt schedules the node to be added when the simulation reaches the specified time (in us).
+// The node ID is reserved immediately so that it can be referenced before the node is added.
+func (s *Simulation) AddNodeAt(cfg *NodeConfig, timestamp uint64) (NodeId, error) {
+	if timestamp <= s.d.CurTime {
+		return InvalidNodeId, errors.Errorf("time %.3fs has already passed", float64(timestamp)/1000000)
+	}
+
+	nodecfg := *cfg
+	if nodecfg.ID <= 0 {
+		nodecfg.ID = s.genNodeId()
+	}
+
+	nodeid := nodecfg.ID
+	if s.nodes[nodeid] != nil {
+		return InvalidNodeId, errors.Errorf("node %d already exists", nodeid)
+	}
+
+	if s.isPendingId(nodeid) {
+		return InvalidNodeId, errors.Errorf("node %d is scheduled to be added", nodeid)
+	}
+
+	s.pendingIds[nodeid] = struct{}{}
+	s.d.ScheduleAt(timestamp, func() {
+		delete(s.pendingIds, nodeid)
+		if _, err := s.AddNode(&nodecfg); err != nil {
+			simplelogger.Errorf("simulation add node %d at %d failed: %v", nodeid, timestamp, err)
+		}
+	})
+	return nodeid, nil
+}
+
 func (s *Simulation) genNodeId() NodeId {
 	nodeid := 1
-	for s.nodes[nodeid] != nil {
+	for s.nodes[nodeid] != nil || s.isPendingId(nodeid) {
 		nodeid += 1
 	}
 	return nodeid
 }
 
+func (s *Simulation) isPendingId(nodeid NodeId) bool {
+	_, ok := s.pendingIds[nodeid]
+	return ok
+}
+
 func (s *Simulation) Run() {
 	s.ctx.WaitAdd("simulation", 1)
 	defer s.ctx.WaitDone("simulation")
@@ -133,6 +285,9 @@ func (s *Simulation) Run() {
 
 	defer s.Stop()
 
+	if s.cfg.ResourceSampleInterval > 0 {
+		go s.sampleResourcesPeriodically(s.cfg.ResourceSampleInterval)
+	}
 	s.d.Run()
 }
 
@@ -158,6 +313,7 @@ func (s *Simulation) Stop() {
 	}
 
 	simplelogger.Infof("stopping simulation ...")
+	s.reportSummary()
 	for _, node := range s.nodes {
 		_ = node.Exit()
 	}
@@ -169,6 +325,7 @@ func (s *Simulation) Stop() {
 
 func (s *Simulation) SetVisualizer(vis visualize.Visualizer) {
 	simplelogger.AssertNotNil(vis)
+	vis = visualizeMulti.NewMultiVisualizer(vis, s.statsLog)
 	s.vis = vis
 	s.d.SetVisualizer(vis)
 	vis.SetController(NewSimulationController(s))
@@ -176,6 +333,16 @@ func (s *Simulation) SetVisualizer(vis visualize.Visualizer) {
 	s.vis.SetNetworkInfo(s.GetNetworkInfo())
 }
 
+// StatsLog returns the timeline of node stats recorded so far.
+func (s *Simulation) StatsLog() visualizeStatslog.Timeline {
+	return s.statsLog.Timeline()
+}
+
+// NodeStats returns the current node stats.
+func (s *Simulation) NodeStats() visualizeStatslog.NodeStats {
+	return s.statsLog.Current()
+}
+
 func (s *Simulation) OnNodeFail(nodeid NodeId) {
 	node := s.nodes[nodeid]
 	simplelogger.AssertNotNil(node)
@@ -197,6 +364,17 @@ func (s *Simulation) OnUartWrite(nodeid NodeId, data []byte) {
 	node.onUartWrite(data)
 }
 
+// GetNodeLogSeq returns the sequence number of the last log of the node.
+// It is part of implementation of dispatcher.CallbackHandler.
+func (s *Simulation) GetNodeLogSeq(nodeid NodeId) uint64 {
+	node := s.nodes[nodeid]
+	if node == nil {
+		return 0
+	}
+
+	return node.LogSeq()
+}
+
 func (s *Simulation) PostAsync(trivial bool, f func()) {
 	s.d.PostAsync(trivial, f)
 }
@@ -225,6 +403,38 @@ func (s *Simulation) MoveNodeTo(nodeid NodeId, x, y int) {
 	s.d.SetNodePos(nodeid, x, y)
 }
 
+// DefaultRadioRange returns the radio range of new nodes without a specified radio range.
+func (s *Simulation) DefaultRadioRange() int {
+	return s.radioRange
+}
+
+// SetDefaultRadioRange sets the radio range of new nodes without a specified radio range.
+func (s *Simulation) SetDefaultRadioRange(radioRange int) {
+	simplelogger.AssertTrue(radioRange > 0)
+	s.radioRange = radioRange
+}
+
+// GeoOrigin returns the geographic mapping of node positions, or nil if the geographic mode is disabled.
+func (s *Simulation) GeoOrigin() *geo.Origin {
+	return s.geoOrigin
+}
+
+// SetGeoOrigin sets the geographic mapping of node positions, or disables the geographic mode if origin is nil.
+func (s *Simulation) SetGeoOrigin(origin *geo.Origin) {
+	s.geoOrigin = origin
+}
+
+// NodeGeoPosition returns the geographic position of the node. It returns false if the node is not found or the
+// geographic mode is disabled.
+func (s *Simulation) NodeGeoPosition(nodeid NodeId) (geo.Position, bool) {
+	dn := s.d.GetNode(nodeid)
+	if dn == nil || s.geoOrigin == nil {
+		return geo.Position{}, false
+	}
+
+	return s.geoOrigin.ToGeo(dn.X, dn.Y), true
+}
+
 func (s *Simulation) DeleteNode(nodeid NodeId) error {
 	node := s.nodes[nodeid]
 	if node == nil {
@@ -238,15 +448,103 @@ func (s *Simulation) DeleteNode(nodeid NodeId) error {
 	return nil
 }
 
+// Reset deletes all nodes and clears the simulation state, so that the next experiment starts with a clean slate.
+// The PRNG is reinitialized with the configured seed, and file outputs are restarted.
+func (s *Simulation) Reset() {
+	var nodeids []NodeId
+	for nodeid := range s.nodes {
+		nodeids = append(nodeids, nodeid)
+	}
+	sort.Ints(nodeids)
+
+	for _, nodeid := range nodeids {
+		_ = s.DeleteNode(nodeid)
+	}
+
+	s.pendingIds = map[NodeId]struct{}{}
+	s.healthEvents = nil
+	s.startTime = time.Now()
+	s.sendTracker.reset()
+	s.resetJobs()
+	s.linkProbes = nil
+	s.linkMetrics.reset()
+	s.netDiag.reset()
+	s.energyScan.reset()
+	s.d.Reset()
+	s.startMacCounterSampling()
+	s.statsLog.Reset()
+	rand.Seed(s.cfg.Seed)
+}
+
 func (s *Simulation) SetNodeFailed(id NodeId, failed bool) {
 	s.d.SetNodeFailed(id, failed)
 }
 
+// UpgradeNode gracefully stops the node and restarts it with the specified executable, keeping its node ID, position
+// and flash, so that it restores its network configuration like a device after an OTA upgrade.
+func (s *Simulation) UpgradeNode(id NodeId, executable string) (*Node, error) {
+	node := s.nodes[id]
+	if node == nil {
+		return nil, errors.Errorf("node %d not found", id)
+	}
+
+	if _, err := os.Stat(executable); err != nil {
+		return nil, errors.Wrapf(err, "executable %s not found", executable)
+	}
+
+	dnode := s.d.GetNode(id)
+	cfg := *node.cfg
+	cfg.ID = id
+	cfg.X, cfg.Y = dnode.X, dnode.Y
+	cfg.ExecutablePath = executable
+	cfg.Restore = true
+
+	if err := s.DeleteNode(id); err != nil {
+		return nil, err
+	}
+
+	s.d.NotifyUpgrade(id, executable)
+	return s.AddNode(&cfg)
+}
+
+// PauseNode freezes the node so that it misses virtual time until resumed, without failing its radio.
+// If stopProcess is true, the node process is also suspended using SIGSTOP.
+func (s *Simulation) PauseNode(id NodeId, stopProcess bool) error {
+	node := s.nodes[id]
+	if node == nil {
+		return errors.Errorf("node %d not found", id)
+	}
+
+	if s.cfg.Real {
+		return errors.Errorf("can not pause node in real mode")
+	}
+
+	s.d.PauseNode(id)
+	if stopProcess {
+		return node.StopProcess()
+	}
+	return nil
+}
+
+// ResumeNode resumes the paused node.
+func (s *Simulation) ResumeNode(id NodeId) error {
+	node := s.nodes[id]
+	if node == nil {
+		return errors.Errorf("node %d not found", id)
+	}
+
+	node.ContinueProcess()
+	s.d.ResumeNode(id)
+	return nil
+}
+
 func (s *Simulation) ShowDemoLegend(x int, y int, title string) {
 	s.vis.ShowDemoLegend(x, y, title)
 }
 
+// SetSpeed sets the speed of the simulation, and disables the auto speed mode.
 func (s *Simulation) SetSpeed(speed float64) {
+	_ = s.SetAutoSpeed(0)
 	s.d.SetSpeed(speed)
 }
 
@@ -262,9 +560,36 @@ func (s *Simulation) Go(duration time.Duration) <-chan struct{} {
 	return s.d.Go(duration)
 }
 
-func (s *Simulation) removeTmpDir() error {
-	// tmp directory is used by nodes for saving *.flash files. Need to be removed when simulation started
-	return os.RemoveAll("tmp")
+// PortOffset returns the port offset of the simulation, which identifies the simulation among simulations running
+// at the same time.
+func (s *Simulation) PortOffset() int {
+	return (s.cfg.DispatcherPort - threadconst.InitialDispatcherPort) / threadconst.WellKnownNodeId
+}
+
+// NodeDir returns the private working directory of the node, where it saves its flash and any other files.
+func (s *Simulation) NodeDir(id NodeId) string {
+	return filepath.Join(s.cfg.NodeDir, strconv.Itoa(s.PortOffset()), strconv.Itoa(id))
+}
+
+// prepareNodeDir creates the working directory of the node. Unless the node is restored, the files left by a previous
+// node with the same ID are removed, so that the node starts with an empty flash.
+func (s *Simulation) prepareNodeDir(id NodeId, restore bool) (string, error) {
+	dir := s.NodeDir(id)
+	if !restore {
+		if err := os.RemoveAll(dir); err != nil {
+			return "", err
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func (s *Simulation) removeNodeDirs() error {
+	// the node directories of this simulation need to be removed when simulation started, while other simulations
+	// may still be using theirs.
+	return os.RemoveAll(filepath.Join(s.cfg.NodeDir, strconv.Itoa(s.PortOffset())))
 }
 
 // IsStopped returns if the simulation is already stopped.
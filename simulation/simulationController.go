@@ -29,7 +29,10 @@ package simulation
 import (
 	"strings"
 
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
 	"github.com/openthread/ot-ns/visualize"
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
 	"github.com/pkg/errors"
 )
 
@@ -52,6 +55,287 @@ func (sc *simulationController) Command(cmd string) ([]string, error) {
 	return output, nil
 }
 
+var simulationExitedError = errors.Errorf("simulation exited")
+
+// do runs f in the simulation goroutine and waits for it, or fails if the simulation exits first.
+func (sc *simulationController) do(f func(sim *Simulation) error) error {
+	errc := make(chan error, 1)
+	sc.sim.PostAsync(false, func() {
+		errc <- f(sc.sim)
+	})
+
+	select {
+	case err := <-errc:
+		return err
+	case <-sc.sim.ctx.Done():
+		return simulationExitedError
+	}
+}
+
+func (sc *simulationController) GetCounters() (counters map[string]uint64, err error) {
+	err = sc.do(func(sim *Simulation) error {
+		counters = sim.d.GetCounters()
+		return nil
+	})
+	return
+}
+
+func (sc *simulationController) GetRadioParams() (params *pb.RadioParams, err error) {
+	err = sc.do(func(sim *Simulation) error {
+		p := sim.d.GetRadioModelParams()
+		model := string(p.Model)
+		params = &pb.RadioParams{
+			Model:                &model,
+			NoiseFloorDbm:        &p.NoiseFloorDbm,
+			ChannelNoiseFloorDbm: map[uint32]float64{},
+			PathLossExponent:     &p.PathLossExponent,
+			TxPowerDbm:           &p.TxPowerDbm,
+			MinSnrDb:             &p.MinSnrDb,
+			MeterPerUnit:         &p.MeterPerUnit,
+		}
+		for ch, nf := range p.ChannelNoiseFloorDbm {
+			params.ChannelNoiseFloorDbm[uint32(ch)] = nf
+		}
+		for _, model := range dispatcher.RadioModels() {
+			params.Models = append(params.Models, string(model))
+		}
+		return nil
+	})
+	return
+}
+
+func (sc *simulationController) SetRadioParams(params *pb.RadioParams) error {
+	return sc.do(func(sim *Simulation) error {
+		p := sim.d.GetRadioModelParams()
+		if params.Model != nil {
+			model, err := dispatcher.ParseRadioModel(*params.Model)
+			if err != nil {
+				return err
+			}
+			p.Model = model
+		}
+
+		if err := setChannelNoiseFloor(&p, params); err != nil {
+			return err
+		}
+		if err := setRadioParam(&p.NoiseFloorDbm, "noise_floor_dbm", params.NoiseFloorDbm, false); err != nil {
+			return err
+		}
+		if err := setRadioParam(&p.PathLossExponent, "path_loss_exponent", params.PathLossExponent, true); err != nil {
+			return err
+		}
+		if err := setRadioParam(&p.TxPowerDbm, "tx_power_dbm", params.TxPowerDbm, false); err != nil {
+			return err
+		}
+		if err := setRadioParam(&p.MinSnrDb, "min_snr_db", params.MinSnrDb, false); err != nil {
+			return err
+		}
+		if err := setRadioParam(&p.MeterPerUnit, "meter_per_unit", params.MeterPerUnit, true); err != nil {
+			return err
+		}
+
+		sim.d.SetRadioModelParams(p)
+		return nil
+	})
+}
+
+// setRadioParam sets the parameter to val, unless val is not set.
+func setRadioParam(param *float64, name string, val *float64, positive bool) error {
+	if val == nil {
+		return nil
+	}
+	if positive && *val <= 0 {
+		return errors.Errorf("invalid %s: %v", name, *val)
+	}
+	*param = *val
+	return nil
+}
+
+func setChannelNoiseFloor(p *dispatcher.RadioModelParams, params *pb.RadioParams) error {
+	for ch, nf := range params.ChannelNoiseFloorDbm {
+		if ch < dispatcher.MinChannel || ch > dispatcher.MaxChannel {
+			return errors.Errorf("invalid channel: %d", ch)
+		}
+		p.ChannelNoiseFloorDbm[uint8(ch)] = nf
+	}
+
+	for _, ch := range params.ResetChannelNoiseFloor {
+		if ch < dispatcher.MinChannel || ch > dispatcher.MaxChannel {
+			return errors.Errorf("invalid channel: %d", ch)
+		}
+		delete(p.ChannelNoiseFloorDbm, uint8(ch))
+	}
+	return nil
+}
+
+func (sc *simulationController) StartKpi() error {
+	return sc.do(func(sim *Simulation) error {
+		sim.CollectResourceUsage()
+		sim.d.StartKpi()
+		return nil
+	})
+}
+
+func (sc *simulationController) StopKpi() error {
+	return sc.do(func(sim *Simulation) error {
+		sim.CollectResourceUsage()
+		return sim.d.StopKpi()
+	})
+}
+
+func (sc *simulationController) getKpi() (kpi *dispatcher.Kpi, err error) {
+	err = sc.do(func(sim *Simulation) error {
+		kpi = sim.d.GetKpi()
+		if kpi == nil {
+			return errors.Errorf("KPI not started")
+		}
+		return nil
+	})
+	return
+}
+
+func (sc *simulationController) GetKpi() (*pb.Kpi, error) {
+	kpi, err := sc.getKpi()
+	if err != nil {
+		return nil, err
+	}
+	return kpiToPb(kpi), nil
+}
+
+func kpiToPb(kpi *dispatcher.Kpi) *pb.Kpi {
+	res := &pb.Kpi{
+		StartUs:   kpi.StartTime,
+		StopUs:    kpi.StopTime,
+		Running:   kpi.Running,
+		Counters:  kpi.Counters,
+		Mac:       map[int32]*pb.MacStats{},
+		Resources: map[int32]*pb.ResourceUsage{},
+		MacTx:     map[int32]*pb.MacTxStats{},
+	}
+
+	if a := kpi.Airtime; a != nil {
+		res.Airtime = &pb.AirtimeReport{
+			WindowStartUs: a.WindowStart,
+			WindowEndUs:   a.WindowEnd,
+			AirtimeUs:     a.TxAirtime,
+			Util:          a.Utilization,
+			Fairness:      a.Fairness,
+		}
+		for _, n := range a.Nodes {
+			res.Airtime.Nodes = append(res.Airtime.Nodes, &pb.AirtimeStat{
+				Node:      int32(n.NodeId),
+				Frames:    n.TxFrames,
+				AirtimeUs: n.TxAirtime,
+				Util:      n.Utilization,
+				Share:     n.Share,
+				Dominant:  n.Dominant,
+			})
+		}
+	}
+
+	for id, s := range kpi.Mac {
+		res.Mac[int32(id)] = &pb.MacStats{Retries: s.TxRetries, Cca: s.CcaFailures, Drops: s.Drops}
+	}
+	for id, u := range kpi.Resources {
+		res.Resources[int32(id)] = &pb.ResourceUsage{RssBytes: u.RssBytes, PeakRssBytes: u.PeakRssBytes, CpuUs: u.CpuTime}
+	}
+	for _, lm := range kpi.LinkMetrics {
+		res.LinkMetrics = append(res.LinkMetrics, &pb.LinkMetricsStats{
+			Src:     int32(lm.Src),
+			Dst:     int32(lm.Dst),
+			Samples: int32(lm.Samples),
+			Lqi:     metricStatsToPb(lm.Lqi),
+			Margin:  metricStatsToPb(lm.Margin),
+			Rssi:    metricStatsToPb(lm.Rssi),
+		})
+	}
+	for id, tx := range kpi.MacTx {
+		res.MacTx[int32(id)] = &pb.MacTxStats{
+			Frames:  tx.Frames,
+			Retries: tx.Retries,
+			Backoff: &pb.DurationStats{
+				Count: int32(tx.Backoff.Count),
+				P50:   tx.Backoff.P50,
+				P90:   tx.Backoff.P90,
+				P99:   tx.Backoff.P99,
+				Max:   tx.Backoff.Max,
+			},
+		}
+	}
+	return res
+}
+
+func metricStatsToPb(s dispatcher.MetricStats) *pb.MetricStats {
+	return &pb.MetricStats{Min: int32(s.Min), Max: int32(s.Max), Avg: s.Avg, Last: int32(s.Last)}
+}
+
+func (sc *simulationController) SaveKpi(filename string) error {
+	kpi, err := sc.getKpi()
+	if err != nil {
+		return err
+	}
+	return kpi.WriteFile(filename)
+}
+
+func (sc *simulationController) Watch(nodeids []NodeId, watch bool) error {
+	return sc.do(func(sim *Simulation) error {
+		for _, id := range nodeids {
+			if sim.d.GetNode(id) == nil {
+				return errors.Errorf("node %d not found", id)
+			}
+		}
+
+		for _, id := range nodeids {
+			if watch {
+				sim.d.WatchNode(id)
+			} else {
+				sim.d.UnwatchNode(id)
+			}
+		}
+		return nil
+	})
+}
+
+func (sc *simulationController) GetNodeHistory(nodeid NodeId, start, end uint64) ([]*pb.NodeState, error) {
+	var states []dispatcher.NodeState
+	_ = sc.do(func(sim *Simulation) error {
+		states = sim.d.NodeHistory(nodeid, start, end)
+		return nil
+	})
+
+	res := make([]*pb.NodeState, len(states))
+	for i, s := range states {
+		res[i] = &pb.NodeState{
+			TimeUs:      s.Time,
+			Role:        pb.OtDeviceRole(s.Role),
+			Rloc16:      uint32(s.Rloc16),
+			Parent:      s.Parent,
+			PartitionId: s.PartitionId,
+			Deleted:     s.Deleted,
+		}
+	}
+	return res, nil
+}
+
+// GetNodeGeo returns the geographic position of the node, which is not enabled if the geographic mode is disabled.
+func (sc *simulationController) GetNodeGeo(nodeid NodeId) (pos *pb.GeoPosition, err error) {
+	err = sc.do(func(sim *Simulation) error {
+		if sim.d.GetNode(nodeid) == nil {
+			return errors.Errorf("node %d not found", nodeid)
+		}
+
+		pos = &pb.GeoPosition{}
+		if p, ok := sim.NodeGeoPosition(nodeid); ok {
+			pos.Enabled = true
+			pos.Lat = p.Lat
+			pos.Lon = p.Lon
+			pos.Alt = p.Alt
+		}
+		return nil
+	})
+	return
+}
+
 type readonlySimulationController struct {
 }
 
@@ -61,6 +345,46 @@ func (r readonlySimulationController) Command(cmd string) (output []string, err
 	return nil, readonlySimulationError
 }
 
+func (r readonlySimulationController) GetCounters() (map[string]uint64, error) {
+	return nil, readonlySimulationError
+}
+
+func (r readonlySimulationController) GetRadioParams() (*pb.RadioParams, error) {
+	return nil, readonlySimulationError
+}
+
+func (r readonlySimulationController) SetRadioParams(params *pb.RadioParams) error {
+	return readonlySimulationError
+}
+
+func (r readonlySimulationController) StartKpi() error {
+	return readonlySimulationError
+}
+
+func (r readonlySimulationController) StopKpi() error {
+	return readonlySimulationError
+}
+
+func (r readonlySimulationController) GetKpi() (*pb.Kpi, error) {
+	return nil, readonlySimulationError
+}
+
+func (r readonlySimulationController) SaveKpi(filename string) error {
+	return readonlySimulationError
+}
+
+func (r readonlySimulationController) Watch(nodeids []NodeId, watch bool) error {
+	return readonlySimulationError
+}
+
+func (r readonlySimulationController) GetNodeHistory(nodeid NodeId, start, end uint64) ([]*pb.NodeState, error) {
+	return nil, readonlySimulationError
+}
+
+func (r readonlySimulationController) GetNodeGeo(nodeid NodeId) (*pb.GeoPosition, error) {
+	return nil, readonlySimulationError
+}
+
 func NewSimulationController(sim *Simulation) visualize.SimulationController {
 	if !sim.cfg.ReadOnly {
 		return &simulationController{sim}
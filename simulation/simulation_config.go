@@ -26,7 +26,12 @@
 
 package simulation
 
-import "github.com/openthread/ot-ns/threadconst"
+import (
+	"time"
+
+	"github.com/openthread/ot-ns/geo"
+	"github.com/openthread/ot-ns/threadconst"
+)
 
 const (
 	DefaultChannel         = 11
@@ -52,6 +57,25 @@ type Config struct {
 	DispatcherHost string
 	DispatcherPort int
 	DumpPackets    bool
+	StatsLogFile   string      // file or URL of the node stats timeline, or "" for none
+	StatsLogFormat string      // format of the node stats timeline: csv (default), sqlite or influx
+	Seed           int64       // seed of the PRNG, which is reinitialized with the seed on reset
+	LogCorrelation bool        // number node logs and tag captured frames with the log sequence numbers
+	GeoOrigin      *geo.Origin // geographic mapping of node positions, or nil if disabled
+	InitScript     string      // default init script file of nodes, or "" for none
+	Transcript     bool        // record the CLI transcript of each node into transcript.txt in its directory
+	Summary        bool        // print the summary of the run on exit
+	SummaryFile    string      // write the summary of the run on exit to the file in JSON format, or "" for none
+	SharedMemory   bool        // offer the shared memory transport to nodes
+	TcpAddr        string      // listen address of the TCP transport for nodes, or "" for UDP only
+	NodeDir        string      // base of the working directories of nodes, <NodeDir>/<port offset>/<node ID>
+	CoverageDir    string      // directory of the coverage data of instrumented nodes, or "" to disable
+
+	ResourceSampleInterval time.Duration // wall-clock interval of sampling the resource usage of nodes, or 0 to disable
+
+	Container *ContainerConfig // run nodes inside containers, or nil to run them as host processes
+
+	NodeTemplates map[string]*NodeTemplate // node templates by name, with the inheritance resolved
 }
 
 func DefaultConfig() *Config {
@@ -66,5 +90,8 @@ func DefaultConfig() *Config {
 		Real:           false,
 		DispatcherHost: "localhost",
 		DispatcherPort: threadconst.InitialDispatcherPort,
+		NodeDir:        "tmp",
+
+		ResourceSampleInterval: DefaultResourceSampleInterval,
 	}
 }
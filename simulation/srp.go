@@ -0,0 +1,276 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+var (
+	// instance:"ins1", name:"_test1._udp", state:Registered, port:777, priority:0, weight:0
+	srpClientServiceRegexp = regexp.MustCompile(`^instance:"(.*)", name:"(.*)", state:(\w+), port:(\d+)`)
+)
+
+const (
+	SrpStateToAdd      = "ToAdd"
+	SrpStateAdding     = "Adding"
+	SrpStateToRefresh  = "ToRefresh"
+	SrpStateRefreshing = "Refreshing"
+	SrpStateToRemove   = "ToRemove"
+	SrpStateRemoving   = "Removing"
+	SrpStateRegistered = "Registered"
+	SrpStateRemoved    = "Removed"
+)
+
+type SrpClientService struct {
+	Instance string
+	Name     string
+	State    string
+	Port     int
+}
+
+type SrpServerHost struct {
+	Name      string
+	Deleted   bool
+	Addresses []string
+}
+
+type SrpServerService struct {
+	Name    string
+	Deleted bool
+	Port    int
+	Host    string
+}
+
+func (node *Node) SrpClientSetHostName(name string) {
+	node.Command(fmt.Sprintf("srp client host name %s", name), DefaultCommandTimeout)
+}
+
+func (node *Node) SrpClientSetHostAddress(addrs ...string) {
+	node.Command(fmt.Sprintf("srp client host address %s", strings.Join(addrs, " ")), DefaultCommandTimeout)
+}
+
+func (node *Node) SrpClientGetHostState() string {
+	return node.CommandExpectString("srp client host state", DefaultCommandTimeout)
+}
+
+func (node *Node) SrpClientAddService(instance string, service string, port int) {
+	node.Command(fmt.Sprintf("srp client service add %s %s %d", instance, service, port), DefaultCommandTimeout)
+}
+
+func (node *Node) SrpClientRemoveService(instance string, service string) {
+	node.Command(fmt.Sprintf("srp client service remove %s %s", instance, service), DefaultCommandTimeout)
+}
+
+func (node *Node) SrpClientGetServices() []SrpClientService {
+	return parseSrpClientServices(node.Command("srp client service", DefaultCommandTimeout))
+}
+
+func (node *Node) SrpClientEnableAutoStart() {
+	node.Command("srp client autostart enable", DefaultCommandTimeout)
+}
+
+func (node *Node) SrpClientStop() {
+	node.Command("srp client stop", DefaultCommandTimeout)
+}
+
+func (node *Node) SrpServerEnable() {
+	node.Command("srp server enable", DefaultCommandTimeout)
+}
+
+func (node *Node) SrpServerDisable() {
+	node.Command("srp server disable", DefaultCommandTimeout)
+}
+
+func (node *Node) SrpServerGetState() string {
+	return node.CommandExpectString("srp server state", DefaultCommandTimeout)
+}
+
+func (node *Node) SrpServerGetHosts() []SrpServerHost {
+	return parseSrpServerHosts(node.Command("srp server host", DefaultCommandTimeout))
+}
+
+func (node *Node) SrpServerGetServices() []SrpServerService {
+	return parseSrpServerServices(node.Command("srp server service", DefaultCommandTimeout))
+}
+
+func parseSrpClientServices(output []string) (services []SrpClientService) {
+	for _, line := range output {
+		m := srpClientServiceRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			simplelogger.Warnf("unexpected srp client service: %#v", line)
+			continue
+		}
+
+		port, _ := strconv.Atoi(m[4])
+		services = append(services, SrpClientService{
+			Instance: m[1],
+			Name:     m[2],
+			State:    m[3],
+			Port:     port,
+		})
+	}
+	return
+}
+
+// parseSrpEntries splits the output of `srp server host` or `srp server service` into entries, each of which has a
+// name line followed by indented `key: value` lines.
+func parseSrpEntries(output []string, cb func(name string, attrs map[string]string)) {
+	var name string
+	var attrs map[string]string
+
+	flush := func() {
+		if name != "" {
+			cb(name, attrs)
+		}
+	}
+
+	for _, line := range output {
+		if !strings.HasPrefix(line, " ") {
+			flush()
+			name = strings.TrimSpace(line)
+			attrs = map[string]string{}
+			continue
+		}
+
+		kv := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(kv) == 2 && attrs != nil {
+			attrs[kv[0]] = strings.TrimSpace(kv[1])
+		}
+	}
+	flush()
+}
+
+func parseSrpServerHosts(output []string) (hosts []SrpServerHost) {
+	parseSrpEntries(output, func(name string, attrs map[string]string) {
+		host := SrpServerHost{
+			Name:    name,
+			Deleted: attrs["deleted"] == "true",
+		}
+		addrs := strings.Trim(attrs["addresses"], "[]")
+		if addrs != "" {
+			for _, addr := range strings.Split(addrs, ",") {
+				host.Addresses = append(host.Addresses, strings.TrimSpace(addr))
+			}
+		}
+		hosts = append(hosts, host)
+	})
+	return
+}
+
+func parseSrpServerServices(output []string) (services []SrpServerService) {
+	parseSrpEntries(output, func(name string, attrs map[string]string) {
+		port, _ := strconv.Atoi(attrs["port"])
+		services = append(services, SrpServerService{
+			Name:    name,
+			Deleted: attrs["deleted"] == "true",
+			Port:    port,
+			Host:    attrs["host"],
+		})
+	})
+	return
+}
+
+// SrpStats contains the SRP registration states aggregated over all nodes at the time of collection.
+type SrpStats struct {
+	Servers         int // nodes with a running SRP server
+	ServerHosts     int // hosts registered at servers
+	ServerServices  int // services registered at servers
+	DeletedServices int // services deleted at servers, but still retained for their key lease
+	Clients         int // nodes with SRP client services
+	Registered      int // client services successfully registered
+	Renewing        int // client services being renewed
+	Pending         int // client services waiting for or being registered
+	Conflicts       int // client services with an instance name also claimed by another client
+}
+
+// CollectSrpStats queries SRP server and client states of all nodes. Paused nodes and nodes without SRP support are
+// skipped.
+func (s *Simulation) CollectSrpStats() *SrpStats {
+	stats := &SrpStats{}
+	claims := map[string]int{}
+	var clientServices []SrpClientService
+
+	s.VisitNodesInOrder(func(node *Node) {
+		if dnode := s.d.GetNode(node.Id); dnode == nil || dnode.IsPaused() {
+			return
+		}
+
+		defer func() {
+			if err := recover(); err != nil {
+				simplelogger.Warnf("%v - collect srp stats failed: %v", node, err)
+			}
+		}()
+
+		if node.SrpServerGetState() == "running" {
+			stats.Servers += 1
+			for _, host := range node.SrpServerGetHosts() {
+				if !host.Deleted {
+					stats.ServerHosts += 1
+				}
+			}
+			for _, service := range node.SrpServerGetServices() {
+				if service.Deleted {
+					stats.DeletedServices += 1
+				} else {
+					stats.ServerServices += 1
+				}
+			}
+		}
+
+		services := node.SrpClientGetServices()
+		if len(services) > 0 {
+			stats.Clients += 1
+		}
+		for _, service := range services {
+			claims[service.Instance+"."+service.Name] += 1
+			clientServices = append(clientServices, service)
+		}
+	})
+
+	for _, service := range clientServices {
+		switch service.State {
+		case SrpStateRegistered:
+			stats.Registered += 1
+		case SrpStateToRefresh, SrpStateRefreshing:
+			stats.Renewing += 1
+		case SrpStateToAdd, SrpStateAdding:
+			stats.Pending += 1
+		}
+
+		if claims[service.Instance+"."+service.Name] > 1 {
+			stats.Conflicts += 1
+		}
+	}
+
+	return stats
+}
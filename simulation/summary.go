@@ -0,0 +1,143 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// Summary is the consolidated report of the simulation run since the simulation started or was reset.
+type Summary struct {
+	SimTime  uint64         `json:"sim_time_us"`  // simulated time
+	WallTime uint64         `json:"wall_time_us"` // wall-clock time since the simulation started
+	RunTime  uint64         `json:"run_time_us"`  // wall-clock time spent simulating
+	Speedup  float64        `json:"speedup"`      // simulated time per run time
+	Nodes    map[string]int `json:"nodes"`        // number of nodes of each type
+	dispatcher.RunStats
+	Resources *ResourceFootprint `json:"resources,omitempty"` // resource usage, if sampled
+}
+
+// Summary returns the summary of the simulation run. MAC retries and CCA failures are counted up to the last sample of
+// the MAC counters, see CollectMacCounters.
+func (s *Simulation) Summary() *Summary {
+	stats := s.d.GetRunStats()
+	sum := &Summary{
+		SimTime:  s.d.CurTime,
+		WallTime: uint64(time.Since(s.startTime) / time.Microsecond),
+		RunTime:  uint64(stats.RunTime / time.Microsecond),
+		Nodes:    map[string]int{},
+		RunStats: stats,
+	}
+	if sum.RunTime > 0 {
+		sum.Speedup = float64(sum.SimTime) / float64(sum.RunTime)
+	}
+	for _, node := range s.nodes {
+		sum.Nodes[node.cfg.NodeType()]++
+	}
+	if fp := s.ResourceFootprint(); fp.Nodes > 0 || fp.OtnsRssBytes > 0 {
+		sum.Resources = &fp
+	}
+	return sum
+}
+
+// WriteFile writes the summary to the file in JSON format.
+func (sum *Summary) WriteFile(filename string) error {
+	data, err := json.MarshalIndent(sum, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// WriteText writes the summary in human readable text.
+func (sum *Summary) WriteText(w io.Writer) {
+	var types []string
+	nodeCount := 0
+	for typ, n := range sum.Nodes {
+		types = append(types, fmt.Sprintf("%s=%d", typ, n))
+		nodeCount += n
+	}
+	sort.Strings(types)
+
+	fmt.Fprintf(w, "sim_time=%.3fs wall_time=%.3fs run_time=%.3fs speedup=%.2f\n", float64(sum.SimTime)/1000000,
+		float64(sum.WallTime)/1000000, float64(sum.RunTime)/1000000, sum.Speedup)
+	fmt.Fprintf(w, "nodes=%d %s\n", nodeCount, strings.Join(types, " "))
+	fmt.Fprintf(w, "frames=%d bytes=%d\n", sum.TxFrames, sum.TxBytes)
+	fmt.Fprintf(w, "collisions=%d overlapped=%d\n", sum.Collisions.LostDeliveries, sum.Collisions.OverlappedFrames)
+
+	mac := &sum.Mac
+	var drops []string
+	for _, reason := range mac.DropReasons() {
+		drops = append(drops, fmt.Sprintf("%s=%d", reason, mac.Drops[reason]))
+	}
+	fmt.Fprintf(w, "retries=%d cca=%d drops=%d %s\n", mac.TxRetries, mac.CcaFailures, mac.TotalDrops(), strings.Join(drops, " "))
+
+	pings := &sum.Pings
+	fmt.Fprintf(w, "pings=%d timeouts=%d p50=%.3fms p90=%.3fms p99=%.3fms max=%.3fms\n", pings.Count, pings.Timeouts,
+		float64(pings.Delay.P50)/1000, float64(pings.Delay.P90)/1000, float64(pings.Delay.P99)/1000, float64(pings.Delay.Max)/1000)
+
+	joins := &sum.Joins
+	fmt.Fprintf(w, "joins=%d joined=%d p50=%.3fs p90=%.3fs p99=%.3fs max=%.3fs\n", joins.Sessions, joins.Joined,
+		float64(joins.Join.P50)/1000000, float64(joins.Join.P90)/1000000, float64(joins.Join.P99)/1000000, float64(joins.Join.Max)/1000000)
+
+	if fp := sum.Resources; fp != nil {
+		fmt.Fprintf(w, "rss=%s peak_rss=%s cpu=%.3fs otns_rss=%s otns_cpu=%.3fs\n", FormatBytes(fp.RssBytes),
+			FormatBytes(fp.PeakRssBytes), float64(fp.CpuTime)/1000000, FormatBytes(fp.OtnsRssBytes), float64(fp.OtnsCpuTime)/1000000)
+	}
+}
+
+func (s *Simulation) reportSummary() {
+	if !s.cfg.Summary && s.cfg.SummaryFile == "" {
+		return
+	}
+
+	if s.ctx.Err() == nil {
+		// nodes can not respond to commands once the simulation is canceled
+		s.CollectMacCounters()
+	}
+	s.CollectResourceUsage()
+
+	sum := s.Summary()
+	if s.cfg.Summary {
+		fmt.Println("simulation summary:")
+		sum.WriteText(os.Stdout)
+	}
+	if s.cfg.SummaryFile != "" {
+		if err := sum.WriteFile(s.cfg.SummaryFile); err != nil {
+			simplelogger.Errorf("write summary file %s failed: %v", s.cfg.SummaryFile, err)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/geo"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// TopologyIdStrategy resolves the conflicts of node IDs when loading a topology into a simulation with nodes.
+type TopologyIdStrategy string
+
+const (
+	TopologyIdsKeep     TopologyIdStrategy = "keep"     // keep the node IDs, and fail if any of them is in use
+	TopologyIdsShift    TopologyIdStrategy = "shift"    // shift all node IDs above the largest node ID in use
+	TopologyIdsRenumber TopologyIdStrategy = "renumber" // assign the next available node ID to conflicting nodes
+)
+
+// TopologyPanStrategy resolves the conflicts of the PAN parameters of a topology and the simulation.
+type TopologyPanStrategy string
+
+const (
+	TopologyPanSim    TopologyPanStrategy = "sim"    // nodes join the network of the simulation
+	TopologyPanFile   TopologyPanStrategy = "file"   // nodes form the network of the topology file
+	TopologyPanStrict TopologyPanStrategy = "strict" // fail if the parameters differ from the simulation
+)
+
+// Topology is a saved topology: the network parameters, the geographic origin (in geographic mode) and the nodes.
+type Topology struct {
+	Network TopologyNetwork `yaml:"network"`
+	Geo     *TopologyGeo    `yaml:"geo,omitempty"`
+	Nodes   []TopologyNode  `yaml:"nodes"`
+}
+
+// TopologyGeo is the geographic origin of a topology. It is informative: loading a topology uses the node positions
+// in distance units, and does not change the geographic mode of the simulation.
+type TopologyGeo struct {
+	Lat   float64 `yaml:"lat"`
+	Lon   float64 `yaml:"lon"`
+	Alt   float64 `yaml:"alt"`
+	Scale float64 `yaml:"scale"` // meters per distance unit
+}
+
+// TopologyNetwork is the network parameters of a topology. Zero values are the parameters of the simulation.
+type TopologyNetwork struct {
+	Channel    int    `yaml:"channel,omitempty"`
+	Panid      uint16 `yaml:"panid,omitempty"`
+	NetworkKey string `yaml:"networkkey,omitempty"`
+}
+
+// TopologyNode is a node of a topology. A zero radio range is the default radio range of the simulation.
+type TopologyNode struct {
+	Id         NodeId        `yaml:"id"`
+	Type       string        `yaml:"type"`
+	X          int           `yaml:"x"`
+	Y          int           `yaml:"y"`
+	RadioRange int           `yaml:"rr,omitempty"`
+	ClockDrift float64       `yaml:"drift,omitempty"` // clock drift in ppm
+	Geo        *geo.Position `yaml:"geo,omitempty"`   // geographic position in geographic mode, informative
+}
+
+// TopologyLoadOptions are the options of loading a topology. Node positions are scaled, then rotated (in degrees,
+// clockwise as displayed) around the origin, and then offset.
+type TopologyLoadOptions struct {
+	Add              bool // add the nodes to the simulation, instead of replacing all nodes
+	OffsetX, OffsetY int
+	Scale            float64 // 0 for no scaling
+	Rotate           float64
+	Ids              TopologyIdStrategy
+	Pan              TopologyPanStrategy
+}
+
+// Topology returns the topology of the simulation.
+func (s *Simulation) Topology() *Topology {
+	topo := &Topology{
+		Network: TopologyNetwork{Channel: s.Channel(), Panid: s.Panid(), NetworkKey: s.NetworkKey()},
+		Nodes:   []TopologyNode{},
+	}
+	if o := s.geoOrigin; o != nil {
+		topo.Geo = &TopologyGeo{Lat: o.Lat, Lon: o.Lon, Alt: o.Alt, Scale: o.MetersPerUnit}
+	}
+	s.VisitNodesInOrder(func(node *Node) {
+		dnode := s.d.GetNode(node.Id)
+		tn := TopologyNode{
+			Id:         node.Id,
+			Type:       node.cfg.NodeType(),
+			X:          dnode.X,
+			Y:          dnode.Y,
+			RadioRange: dnode.RadioRange(),
+			ClockDrift: dnode.ClockDrift(),
+		}
+		if pos, ok := s.NodeGeoPosition(node.Id); ok {
+			tn.Geo = &pos
+		}
+		topo.Nodes = append(topo.Nodes, tn)
+	})
+	return topo
+}
+
+// LoadTopology adds the nodes of the topology with the options, and returns the IDs of the added nodes in the order of
+// the topology nodes. Unless opts.Add is set, all nodes of the simulation are deleted first.
+func (s *Simulation) LoadTopology(topo *Topology, opts TopologyLoadOptions) ([]NodeId, error) {
+	network := topo.Network
+	switch opts.Pan {
+	case TopologyPanSim, "":
+		network = TopologyNetwork{}
+	case TopologyPanStrict:
+		if (network.Channel != 0 && network.Channel != s.Channel()) || (network.Panid != 0 && network.Panid != s.Panid()) ||
+			(network.NetworkKey != "" && network.NetworkKey != s.NetworkKey()) {
+			return nil, errors.Errorf("network parameters differ from the simulation: %+v", network)
+		}
+		network = TopologyNetwork{}
+	case TopologyPanFile:
+	default:
+		return nil, errors.Errorf("invalid PAN strategy: %s", opts.Pan)
+	}
+
+	cfgs := make([]*NodeConfig, len(topo.Nodes))
+	for i, tn := range topo.Nodes {
+		cfg := DefaultNodeConfig()
+		if err := cfg.SetNodeType(tn.Type); err != nil {
+			return nil, err
+		}
+		if tn.Id <= 0 {
+			return nil, errors.Errorf("invalid node ID: %d", tn.Id)
+		}
+		cfg.ID = tn.Id
+		cfg.X, cfg.Y = opts.transform(tn.X, tn.Y)
+		cfg.RadioRange = s.radioRange
+		if tn.RadioRange > 0 {
+			cfg.RadioRange = tn.RadioRange
+		}
+		if tn.ClockDrift < -dispatcher.MaxClockDriftPpm || tn.ClockDrift > dispatcher.MaxClockDriftPpm {
+			return nil, errors.Errorf("invalid clock drift of node %d: %gppm", tn.Id, tn.ClockDrift)
+		}
+		cfg.ClockDrift = tn.ClockDrift
+		cfg.Channel, cfg.Panid, cfg.NetworkKey = network.Channel, network.Panid, network.NetworkKey
+		cfgs[i] = cfg
+	}
+
+	if err := s.assignTopologyIds(cfgs, opts.Ids, !opts.Add); err != nil {
+		return nil, err
+	}
+	if !opts.Add {
+		s.VisitNodesInOrder(func(node *Node) {
+			_ = s.DeleteNode(node.Id)
+		})
+	}
+
+	var added []NodeId
+	for _, cfg := range cfgs {
+		node, err := s.AddNode(cfg)
+		if err != nil {
+			return added, errors.Wrapf(err, "add node %d", cfg.ID)
+		}
+		added = append(added, node.Id)
+	}
+	return added, nil
+}
+
+func (opts *TopologyLoadOptions) transform(x, y int) (int, int) {
+	fx, fy := float64(x), float64(y)
+	if opts.Scale != 0 {
+		fx, fy = fx*opts.Scale, fy*opts.Scale
+	}
+	if opts.Rotate != 0 {
+		// the Y axis points down as displayed, so that the rotation is clockwise
+		sin, cos := math.Sincos(opts.Rotate * math.Pi / 180)
+		fx, fy = fx*cos-fy*sin, fx*sin+fy*cos
+	}
+	return int(math.Round(fx)) + opts.OffsetX, int(math.Round(fy)) + opts.OffsetY
+}
+
+// assignTopologyIds resolves the conflicts of the node IDs of the configs with the nodes of the simulation and with
+// each other. If replace is true, the nodes of the simulation are going to be deleted, and do not conflict.
+func (s *Simulation) assignTopologyIds(cfgs []*NodeConfig, strategy TopologyIdStrategy, replace bool) error {
+	inUse := func(id NodeId) bool {
+		return (!replace && s.nodes[id] != nil) || s.isPendingId(id)
+	}
+
+	switch strategy {
+	case TopologyIdsKeep, "":
+		seen := map[NodeId]struct{}{}
+		for _, cfg := range cfgs {
+			if _, dup := seen[cfg.ID]; dup || inUse(cfg.ID) {
+				return errors.Errorf("node %d already exists", cfg.ID)
+			}
+			seen[cfg.ID] = struct{}{}
+		}
+	case TopologyIdsShift:
+		maxId := 0
+		for id := range s.nodes {
+			if id > maxId && !replace {
+				maxId = id
+			}
+		}
+		for id := range s.pendingIds {
+			if id > maxId {
+				maxId = id
+			}
+		}
+		minId := math.MaxInt32
+		for _, cfg := range cfgs {
+			if cfg.ID < minId {
+				minId = cfg.ID
+			}
+		}
+		if shift := maxId + 1 - minId; shift > 0 {
+			for _, cfg := range cfgs {
+				cfg.ID += shift
+			}
+		}
+		seen := map[NodeId]struct{}{}
+		for _, cfg := range cfgs {
+			if _, dup := seen[cfg.ID]; dup {
+				return errors.Errorf("node %d is duplicated", cfg.ID)
+			}
+			seen[cfg.ID] = struct{}{}
+		}
+	case TopologyIdsRenumber:
+		// the nodes keep their IDs if possible, in the order of the IDs
+		order := make([]*NodeConfig, len(cfgs))
+		copy(order, cfgs)
+		sort.SliceStable(order, func(i, j int) bool {
+			return order[i].ID < order[j].ID
+		})
+		taken := map[NodeId]struct{}{}
+		var conflicts []*NodeConfig
+		for _, cfg := range order {
+			if _, dup := taken[cfg.ID]; dup || inUse(cfg.ID) {
+				conflicts = append(conflicts, cfg)
+				continue
+			}
+			taken[cfg.ID] = struct{}{}
+		}
+		free := func(id NodeId) bool {
+			_, dup := taken[id]
+			return !dup && !inUse(id)
+		}
+		next := 1
+		for _, cfg := range conflicts {
+			for !free(next) {
+				next++
+			}
+			cfg.ID = next
+			taken[next] = struct{}{}
+		}
+	default:
+		return errors.Errorf("invalid node ID strategy: %s", strategy)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openthread/ot-ns/geo"
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestAssignTopologyIds(t *testing.T) {
+	// nodes 1 and 3 exist, and node 5 is being added
+	s := &Simulation{
+		nodes:      map[NodeId]*Node{1: {Id: 1}, 3: {Id: 3}},
+		pendingIds: map[NodeId]struct{}{5: {}},
+	}
+
+	for _, tc := range []struct {
+		ids      []NodeId
+		strategy TopologyIdStrategy
+		replace  bool
+		assigned []NodeId
+		err      string
+	}{
+		{ids: []NodeId{2, 4}, strategy: TopologyIdsKeep, assigned: []NodeId{2, 4}},
+		{ids: []NodeId{2, 4}, strategy: "", assigned: []NodeId{2, 4}},
+		{ids: []NodeId{2, 3}, strategy: TopologyIdsKeep, err: "node 3 already exists"},
+		{ids: []NodeId{2, 2}, strategy: TopologyIdsKeep, err: "node 2 already exists"},
+		{ids: []NodeId{5}, strategy: TopologyIdsKeep, err: "node 5 already exists"},
+		// replaced nodes do not conflict, but pending nodes do
+		{ids: []NodeId{1, 3}, strategy: TopologyIdsKeep, replace: true, assigned: []NodeId{1, 3}},
+		{ids: []NodeId{5}, strategy: TopologyIdsKeep, replace: true, err: "node 5 already exists"},
+
+		// shifted above the largest node ID in use, keeping the gaps
+		{ids: []NodeId{1, 2, 4}, strategy: TopologyIdsShift, assigned: []NodeId{6, 7, 9}},
+		{ids: []NodeId{3, 1}, strategy: TopologyIdsShift, assigned: []NodeId{8, 6}},
+		{ids: []NodeId{10, 12}, strategy: TopologyIdsShift, assigned: []NodeId{10, 12}},
+		{ids: []NodeId{1, 2}, strategy: TopologyIdsShift, replace: true, assigned: []NodeId{6, 7}},
+		{ids: []NodeId{1, 1}, strategy: TopologyIdsShift, err: "node 6 is duplicated"},
+
+		// conflicting nodes get the lowest free IDs, in the order of their IDs
+		{ids: []NodeId{2, 4}, strategy: TopologyIdsRenumber, assigned: []NodeId{2, 4}},
+		{ids: []NodeId{3, 1, 2}, strategy: TopologyIdsRenumber, assigned: []NodeId{6, 4, 2}},
+		{ids: []NodeId{7, 5, 7}, strategy: TopologyIdsRenumber, assigned: []NodeId{7, 2, 4}},
+		{ids: []NodeId{3, 1, 2}, strategy: TopologyIdsRenumber, replace: true, assigned: []NodeId{3, 1, 2}},
+
+		{ids: []NodeId{1}, strategy: "random", err: "invalid node ID strategy: random"},
+	} {
+		cfgs := make([]*NodeConfig, len(tc.ids))
+		for i, id := range tc.ids {
+			cfgs[i] = &NodeConfig{ID: id}
+		}
+		err := s.assignTopologyIds(cfgs, tc.strategy, tc.replace)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "%v %s", tc.ids, tc.strategy)
+			continue
+		}
+		assert.Nil(t, err, "%v %s", tc.ids, tc.strategy)
+		assigned := make([]NodeId, len(cfgs))
+		for i, cfg := range cfgs {
+			assigned[i] = cfg.ID
+		}
+		assert.Equal(t, tc.assigned, assigned, "%v %s", tc.ids, tc.strategy)
+	}
+}
+
+func TestTopologyTransform(t *testing.T) {
+	for _, tc := range []struct {
+		opts TopologyLoadOptions
+		x, y int
+	}{
+		{opts: TopologyLoadOptions{}, x: 100, y: 50},
+		{opts: TopologyLoadOptions{OffsetX: 10, OffsetY: -20}, x: 110, y: 30},
+		{opts: TopologyLoadOptions{Scale: 1.5}, x: 150, y: 75},
+		{opts: TopologyLoadOptions{Scale: 0.333}, x: 33, y: 17},
+		// clockwise as displayed, with the Y axis pointing down
+		{opts: TopologyLoadOptions{Rotate: 90}, x: -50, y: 100},
+		{opts: TopologyLoadOptions{Rotate: 180}, x: -100, y: -50},
+		{opts: TopologyLoadOptions{Rotate: -90}, x: 50, y: -100},
+		{opts: TopologyLoadOptions{Rotate: 45}, x: 35, y: 106},
+		// scaled, then rotated, then offset
+		{opts: TopologyLoadOptions{Scale: 2, Rotate: 90, OffsetX: 500, OffsetY: 500}, x: 400, y: 700},
+	} {
+		x, y := tc.opts.transform(100, 50)
+		assert.Equal(t, []int{tc.x, tc.y}, []int{x, y}, "%+v", tc.opts)
+	}
+}
+
+func TestTopologyGeoYaml(t *testing.T) {
+	topo := &Topology{
+		Geo: &TopologyGeo{Lat: 37.422, Lon: -122.0841, Alt: 10, Scale: 0.5},
+		Nodes: []TopologyNode{
+			{Id: 1, Type: "router", X: 100, Y: 100, Geo: &geo.Position{Lat: 37.4215, Lon: -122.0835, Alt: 10}},
+		},
+	}
+	data, err := yaml.Marshal(topo)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "geo:\n    lat: 37.422\n    lon: -122.0841\n    alt: 10\n    scale: 0.5\n")
+	assert.Contains(t, string(data), "      geo:\n        lat: 37.4215\n")
+
+	var loaded Topology
+	assert.Nil(t, yaml.Unmarshal(data, &loaded))
+	assert.Equal(t, topo, &loaded)
+
+	// the geographic fields are omitted without the geographic mode
+	data, err = yaml.Marshal(&Topology{Nodes: []TopologyNode{{Id: 1, Type: "router"}}})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "geo")
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	transcriptFileName = "transcript.txt" // in the working directory of the node
+	transcriptInput    = ">"              // commands sent to the node
+	transcriptOutput   = "<"              // output lines received from the node
+)
+
+// transcript records the CLI transcript of a node into a file: the commands sent to the node and the output lines
+// received from it, each prefixed by the virtual time. Logs of the node are not included.
+// Commands are recorded by the simulation routine and output by the line reader routine of the node.
+type transcript struct {
+	sync.Mutex
+	f *os.File
+}
+
+func newTranscript(filename string, appendFile bool) (*transcript, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendFile {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(filename, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &transcript{f: f}, nil
+}
+
+func (t *transcript) record(timestamp uint64, dir string, line string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.f == nil {
+		return
+	}
+
+	if _, err := fmt.Fprintf(t.f, "%d.%06d %s %s\n", timestamp/1000000, timestamp%1000000, dir, line); err != nil {
+		simplelogger.Errorf("write transcript %s failed: %v", t.f.Name(), err)
+	}
+}
+
+func (t *transcript) Close() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.f == nil {
+		return nil
+	}
+
+	err := t.f.Close()
+	t.f = nil
+	return err
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readTranscript(t *testing.T, filename string) string {
+	data, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+	return string(data)
+}
+
+func TestTranscript(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), transcriptFileName)
+
+	tr, err := newTranscript(filename, false)
+	assert.Nil(t, err)
+	tr.record(12000000, transcriptInput, "state")
+	tr.record(12000500, transcriptOutput, "leader")
+	assert.Nil(t, tr.Close())
+	assert.Equal(t, "12.000000 > state\n12.000500 < leader\n", readTranscript(t, filename))
+
+	// lines recorded after closing the transcript are ignored
+	tr.record(13000000, transcriptOutput, "Done")
+	assert.Nil(t, tr.Close())
+	assert.Equal(t, "12.000000 > state\n12.000500 < leader\n", readTranscript(t, filename))
+
+	// a restored node continues the transcript
+	tr, err = newTranscript(filename, true)
+	assert.Nil(t, err)
+	tr.record(20000000, transcriptInput, "ifconfig up")
+	assert.Nil(t, tr.Close())
+	assert.Equal(t, "12.000000 > state\n12.000500 < leader\n20.000000 > ifconfig up\n", readTranscript(t, filename))
+
+	// a new node overwrites the transcript
+	tr, err = newTranscript(filename, false)
+	assert.Nil(t, err)
+	tr.record(1000, transcriptInput, "state")
+	assert.Nil(t, tr.Close())
+	assert.Equal(t, "0.001000 > state\n", readTranscript(t, filename))
+
+	_, err = newTranscript(filepath.Join(t.TempDir(), "missing", transcriptFileName), false)
+	assert.NotNil(t, err)
+}
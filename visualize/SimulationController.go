@@ -26,6 +26,21 @@
 
 package visualize
 
+import (
+	. "github.com/openthread/ot-ns/types"
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+)
+
 type SimulationController interface {
 	Command(cmd string) ([]string, error)
+	GetCounters() (map[string]uint64, error)
+	GetRadioParams() (*pb.RadioParams, error)
+	SetRadioParams(params *pb.RadioParams) error
+	StartKpi() error
+	StopKpi() error
+	GetKpi() (*pb.Kpi, error)
+	SaveKpi(filename string) error
+	Watch(nodeids []NodeId, watch bool) error
+	GetNodeHistory(nodeid NodeId, start, end uint64) ([]*pb.NodeState, error)
+	GetNodeGeo(nodeid NodeId) (*pb.GeoPosition, error)
 }
@@ -0,0 +1,146 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ControlTokenMetadataKey is the gRPC metadata key carrying the control token of a client.
+	ControlTokenMetadataKey = "otns-token"
+	// ClientIdMetadataKey is the gRPC metadata key carrying the ID a client chooses for itself, e.g. OTNS-Web uses a
+	// random ID per page. In the single controller mode, it identifies the client which controls the simulation.
+	ClientIdMetadataKey = "otns-client"
+)
+
+// readOnlyMethods are the gRPC methods which do not change the simulation. They are allowed for all clients, while
+// other methods require the control token if it is configured.
+var readOnlyMethods = map[string]struct{}{
+	"/visualize_grpc_pb.VisualizeGrpcService/Visualize": {},
+	"/" + simulationServiceName + "/GetCounters":        {},
+	"/" + simulationServiceName + "/GetRadioParams":     {},
+	"/" + simulationServiceName + "/GetKpi":             {},
+	"/" + simulationServiceName + "/GetNodeHistory":     {},
+	"/" + simulationServiceName + "/GetNodeGeo":         {},
+}
+
+// controllers keeps the clients with a visualize stream in the order they attached. In the single controller mode,
+// the earliest of them controls the simulation, until all its streams are closed.
+type controllers struct {
+	sync.Mutex
+	clients []string
+}
+
+// attach adds a visualize stream of the client.
+func (c *controllers) attach(client string) {
+	c.Lock()
+	defer c.Unlock()
+	c.clients = append(c.clients, client)
+}
+
+// detach removes a visualize stream of the client. The latest stream is removed, so that a client keeps its position
+// while it has other streams.
+func (c *controllers) detach(client string) {
+	c.Lock()
+	defer c.Unlock()
+	for i := len(c.clients) - 1; i >= 0; i-- {
+		if c.clients[i] == client {
+			c.clients = append(c.clients[:i], c.clients[i+1:]...)
+			return
+		}
+	}
+}
+
+// controller returns the client controlling the simulation, or "" if there is no client.
+func (c *controllers) controller() string {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.clients) == 0 {
+		return ""
+	}
+	return c.clients[0]
+}
+
+// clientId returns the ID of the client in the gRPC metadata, or "" if not given.
+func clientId(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(ClientIdMetadataKey); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+func (gs *grpcServer) authorize(ctx context.Context, method string) error {
+	if gs.controlToken == "" && !gs.singleController {
+		return nil
+	}
+
+	if _, ok := readOnlyMethods[method]; ok {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if gs.controlToken != "" {
+		for _, token := range md.Get(ControlTokenMetadataKey) {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(gs.controlToken)) == 1 {
+				return nil
+			}
+		}
+	}
+
+	if gs.singleController {
+		if id := clientId(ctx); id != "" && id == gs.controllers.controller() {
+			return nil
+		}
+		return status.Errorf(codes.PermissionDenied, "%s requires controlling the simulation, this client is read-only",
+			method)
+	}
+
+	return status.Errorf(codes.PermissionDenied, "%s requires a valid control token, this client is read-only", method)
+}
+
+func (gs *grpcServer) authorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := gs.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (gs *grpcServer) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := gs.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
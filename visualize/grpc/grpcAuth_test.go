@@ -0,0 +1,97 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorize(t *testing.T) {
+	const command = "/visualize_grpc_pb.VisualizeGrpcService/Command"
+	const visualize = "/visualize_grpc_pb.VisualizeGrpcService/Visualize"
+
+	viewer := context.Background()
+	controller := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ControlTokenMetadataKey, "secret"))
+	intruder := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ControlTokenMetadataKey, "guess"))
+
+	open := &grpcServer{}
+	assert.Nil(t, open.authorize(viewer, command))
+	assert.Nil(t, open.authorize(viewer, visualize))
+
+	protected := &grpcServer{controlToken: "secret"}
+	assert.Nil(t, protected.authorize(viewer, visualize))
+	assert.Nil(t, protected.authorize(viewer, "/"+simulationServiceName+"/GetCounters"))
+	assert.Nil(t, protected.authorize(controller, command))
+	assert.Nil(t, protected.authorize(controller, "/"+simulationServiceName+"/SetRadioParams"))
+
+	err := protected.authorize(viewer, command)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	err = protected.authorize(intruder, "/"+simulationServiceName+"/Watch")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuthorizeSingleController(t *testing.T) {
+	const command = "/visualize_grpc_pb.VisualizeGrpcService/Command"
+
+	client := func(id string, token string) context.Context {
+		md := metadata.Pairs(ClientIdMetadataKey, id)
+		if token != "" {
+			md.Append(ControlTokenMetadataKey, token)
+		}
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+
+	gs := &grpcServer{singleController: true}
+	assert.Equal(t, codes.PermissionDenied, status.Code(gs.authorize(client("a", ""), command)))
+
+	gs.controllers.attach("a")
+	gs.controllers.attach("b")
+	gs.controllers.attach("a")
+	assert.Nil(t, gs.authorize(client("a", ""), command))
+	assert.Equal(t, codes.PermissionDenied, status.Code(gs.authorize(client("b", ""), command)))
+	assert.Equal(t, codes.PermissionDenied, status.Code(gs.authorize(context.Background(), command)))
+	assert.Nil(t, gs.authorize(client("b", ""), "/visualize_grpc_pb.VisualizeGrpcService/Visualize"))
+
+	// the earliest client keeps control until all its streams are closed
+	gs.controllers.detach("a")
+	assert.Nil(t, gs.authorize(client("a", ""), command))
+	gs.controllers.detach("a")
+	assert.Equal(t, codes.PermissionDenied, status.Code(gs.authorize(client("a", ""), command)))
+	assert.Nil(t, gs.authorize(client("b", ""), command))
+
+	// clients with the control token can always control the simulation
+	gs.controlToken = "secret"
+	assert.Nil(t, gs.authorize(client("c", "secret"), command))
+	assert.Equal(t, codes.PermissionDenied, status.Code(gs.authorize(client("c", "guess"), command)))
+	assert.Nil(t, gs.authorize(client("b", ""), command))
+}
@@ -42,6 +42,9 @@ type grpcServer struct {
 	vis                *grpcVisualizer
 	server             *grpc.Server
 	address            string
+	controlToken       string
+	singleController   bool
+	controllers        controllers
 	visualizingStreams map[*grpcStream]struct{}
 }
 
@@ -54,7 +57,8 @@ func (gs *grpcServer) Visualize(req *pb.VisualizeRequest, stream pb.VisualizeGrp
 	var heartbeatTicker *time.Ticker
 
 	gstream := newGrpcStream(stream)
-	simplelogger.Debugf("New visualize request got.")
+	client := clientId(stream.Context())
+	simplelogger.Debugf("New visualize request got from client %q.", client)
 
 	gs.vis.Lock()
 	err = gs.prepareStream(gstream)
@@ -64,20 +68,27 @@ func (gs *grpcServer) Visualize(req *pb.VisualizeRequest, stream pb.VisualizeGrp
 	}
 
 	gs.visualizingStreams[gstream] = struct{}{}
+	gstream.start()
+	simplelogger.Infof("Visualize stream attached, %d streams in total", len(gs.visualizingStreams))
 	gs.vis.Unlock()
 
 	defer gs.disposeStream(gstream)
 
+	if client != "" {
+		gs.controllers.attach(client)
+		defer gs.controllers.detach(client)
+	}
+
 	heartbeatTicker = time.NewTicker(time.Second)
 	defer heartbeatTicker.Stop()
 
 	for {
 		select {
 		case <-heartbeatTicker.C:
-			err = stream.Send(heartbeatEvent)
-			if err != nil {
-				goto exit
-			}
+			gstream.post(heartbeatEvent)
+		case <-gstream.failed:
+			err = gstream.Err()
+			goto exit
 		case <-contextDone:
 			err = stream.Context().Err()
 			goto exit
@@ -85,7 +96,7 @@ func (gs *grpcServer) Visualize(req *pb.VisualizeRequest, stream pb.VisualizeGrp
 	}
 
 exit:
-	simplelogger.Infof("Visualize stream exit: %v", err)
+	simplelogger.Infof("Visualize stream exit: %v, dropped %d events", err, gstream.Dropped())
 	return err
 }
 
@@ -105,7 +116,7 @@ func (gs *grpcServer) Run() error {
 
 func (gs *grpcServer) SendEvent(event *pb.VisualizeEvent, trivial bool) {
 	for stream := range gs.visualizingStreams {
-		_ = stream.Send(event)
+		stream.post(event)
 	}
 }
 
@@ -130,14 +141,18 @@ func (gs *grpcServer) prepareStream(stream *grpcStream) error {
 	return gs.vis.prepareStream(stream)
 }
 
-func newGrpcServer(vis *grpcVisualizer, address string) *grpcServer {
-	server := grpc.NewServer(grpc.ReadBufferSize(1024*8), grpc.WriteBufferSize(1024*1024*1))
+func newGrpcServer(vis *grpcVisualizer, address string, controlToken string, singleController bool) *grpcServer {
 	gs := &grpcServer{
 		vis:                vis,
-		server:             server,
 		address:            address,
+		controlToken:       controlToken,
+		singleController:   singleController,
 		visualizingStreams: map[*grpcStream]struct{}{},
 	}
+	server := grpc.NewServer(grpc.ReadBufferSize(1024*8), grpc.WriteBufferSize(1024*1024*1),
+		grpc.UnaryInterceptor(gs.authorizeUnary), grpc.StreamInterceptor(gs.authorizeStream))
+	gs.server = server
 	pb.RegisterVisualizeGrpcServiceServer(server, gs)
+	pb.RegisterSimulationServiceServer(server, &simulationService{gs: gs})
 	return gs
 }
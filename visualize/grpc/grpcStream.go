@@ -26,18 +26,157 @@
 
 package visualize_grpc
 
-import pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+import (
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+)
+
+const (
+	// streamDropThreshold is the number of queued events beyond which frame events are dropped.
+	streamDropThreshold = 1000
+	// streamMaxQueue is the number of queued events beyond which a stream is considered stalled and closed. The web UI
+	// reconnects and receives the current state.
+	streamMaxQueue = 100000
+	// streamDropReportInterval is the minimal interval between reports of dropped events.
+	streamDropReportInterval = time.Second * 10
+)
+
+// grpcStream sends events to a visualizing client from its own goroutine, so that a slow client never blocks the
+// dispatcher. Under load, events are prioritized: node state events are always sent in order, advance time events are
+// coalesced to the latest one, and frame events are dropped when the client falls behind.
 type grpcStream struct {
 	pb.VisualizeGrpcService_VisualizeServer
+
+	mutex      sync.Mutex
+	queue      []*pb.VisualizeEvent
+	advanceIdx int // index of the queued advance time event, or -1
+	dropped    uint64
+	err        error
+	wake       chan struct{}
+	done       chan struct{}
+	failed     chan struct{} // closed when sending failed, see err
+	closeOnce  sync.Once
+}
+
+// post queues the event to be sent to the client.
+func (gst *grpcStream) post(event *pb.VisualizeEvent) {
+	gst.mutex.Lock()
+	defer gst.mutex.Unlock()
+
+	if gst.err != nil {
+		return
+	}
+
+	switch event.Type.(type) {
+	case *pb.VisualizeEvent_AdvanceTime:
+		if gst.advanceIdx >= 0 {
+			// skip the queued advance time event, which is outdated
+			gst.queue[gst.advanceIdx] = nil
+		}
+		gst.advanceIdx = len(gst.queue)
+	case *pb.VisualizeEvent_Send:
+		if len(gst.queue) >= streamDropThreshold {
+			gst.dropped += 1
+			return
+		}
+	default:
+		if len(gst.queue) >= streamMaxQueue {
+			gst.fail(errors.Errorf("visualize stream stalled with %d queued events", len(gst.queue)))
+			return
+		}
+	}
+
+	gst.queue = append(gst.queue, event)
+	select {
+	case gst.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped returns the number of events dropped so far.
+func (gst *grpcStream) Dropped() uint64 {
+	gst.mutex.Lock()
+	defer gst.mutex.Unlock()
+	return gst.dropped
+}
+
+// Err returns the error which failed the stream, or nil.
+func (gst *grpcStream) Err() error {
+	gst.mutex.Lock()
+	defer gst.mutex.Unlock()
+	return gst.err
+}
+
+func (gst *grpcStream) fail(err error) {
+	if gst.err == nil {
+		gst.err = err
+		gst.queue = nil
+		close(gst.failed)
+	}
+}
+
+func (gst *grpcStream) run() {
+	var reportedDropped uint64
+	var reportTime time.Time
+
+	for {
+		select {
+		case <-gst.wake:
+		case <-gst.done:
+			return
+		}
+
+		gst.mutex.Lock()
+		queue := gst.queue
+		gst.queue = nil
+		gst.advanceIdx = -1
+		dropped := gst.dropped
+		gst.mutex.Unlock()
+
+		for _, event := range queue {
+			if event == nil {
+				continue
+			}
+			if err := gst.Send(event); err != nil {
+				gst.mutex.Lock()
+				gst.fail(err)
+				gst.mutex.Unlock()
+				return
+			}
+		}
+
+		if dropped > reportedDropped && time.Since(reportTime) >= streamDropReportInterval {
+			simplelogger.Warnf("visualize stream is slow, dropped %d frame events so far", dropped)
+			reportedDropped = dropped
+			reportTime = time.Now()
+		}
+	}
 }
 
 func (gst *grpcStream) close() {
+	gst.closeOnce.Do(func() {
+		close(gst.done)
+	})
 }
 
+// newGrpcStream creates a stream. Events can be sent directly with Send until start is called.
 func newGrpcStream(stream pb.VisualizeGrpcService_VisualizeServer) *grpcStream {
 	gst := &grpcStream{
 		VisualizeGrpcService_VisualizeServer: stream,
+		advanceIdx:                           -1,
+		wake:                                 make(chan struct{}, 1),
+		done:                                 make(chan struct{}),
+		failed:                               make(chan struct{}),
 	}
 	return gst
 }
+
+// start starts sending the posted events.
+func (gst *grpcStream) start() {
+	go gst.run()
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+)
+
+type blockingStream struct {
+	pb.VisualizeGrpcService_VisualizeServer
+	unblock chan struct{}
+	sent    chan *pb.VisualizeEvent
+}
+
+func (bs *blockingStream) Send(event *pb.VisualizeEvent) error {
+	<-bs.unblock
+	bs.sent <- event
+	return nil
+}
+
+func TestStreamPriority(t *testing.T) {
+	bs := &blockingStream{unblock: make(chan struct{}), sent: make(chan *pb.VisualizeEvent, streamMaxQueue)}
+	gst := newGrpcStream(bs)
+	defer gst.close()
+
+	advanceTime := func(ts uint64) *pb.VisualizeEvent {
+		return &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AdvanceTime{AdvanceTime: &pb.AdvanceTimeEvent{Ts: ts}}}
+	}
+	frame := &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 1}}}
+	addNode := &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AddNode{AddNode: &pb.AddNodeEvent{NodeId: 1}}}
+
+	// the client does not receive events yet
+	gst.post(addNode)
+	gst.post(advanceTime(1))
+	for i := 0; i < streamDropThreshold; i++ {
+		gst.post(frame)
+	}
+	gst.post(advanceTime(2))
+	gst.post(addNode)
+	assert.Equal(t, uint64(2), gst.Dropped())
+
+	gst.start()
+	close(bs.unblock)
+	var events []*pb.VisualizeEvent
+	for len(events) < 1+(streamDropThreshold-2)+1+1 {
+		select {
+		case event := <-bs.sent:
+			events = append(events, event)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("received %d events", len(events))
+		}
+	}
+	assert.Equal(t, addNode, events[0])
+	assert.Equal(t, frame, events[1])
+	assert.Equal(t, advanceTime(2), events[len(events)-2])
+	assert.Equal(t, addNode, events[len(events)-1])
+	assert.Nil(t, gst.Err())
+}
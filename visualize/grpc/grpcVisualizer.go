@@ -490,7 +490,12 @@ func (gv *grpcVisualizer) AddVisualizationEvent(event *pb.VisualizeEvent, trivia
 	gv.server.SendEvent(event, trivial)
 }
 
-func NewGrpcVisualizer(address string, replayFn string) visualize.Visualizer {
+// NewGrpcVisualizer creates a gRPC visualizer serving on address. If controlToken is not empty, only clients providing
+// the token in the `otns-token` metadata can control the simulation, and other clients are read-only. If
+// singleController is true, the earliest attached client (identified by the `otns-client` metadata) can control the
+// simulation as well, while it is attached.
+func NewGrpcVisualizer(address string, replayFn string, controlToken string,
+	singleController bool) visualize.Visualizer {
 	gsv := &grpcVisualizer{
 		simctrl: nil,
 		f:       newGrpcField(),
@@ -500,6 +505,6 @@ func NewGrpcVisualizer(address string, replayFn string) visualize.Visualizer {
 		gsv.replay = replay.NewReplay(replayFn)
 	}
 
-	gsv.server = newGrpcServer(gsv, address)
+	gsv.server = newGrpcServer(gsv, address, controlToken, singleController)
 	return gsv
 }
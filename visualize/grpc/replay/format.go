@@ -0,0 +1,164 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// A replay file is a text file with one ReplayEntry in the protobuf text format per line. Since format version 2, the
+// first line is the header "# otns-replay format <version>", which is a comment for the protobuf text format. Files
+// without the header have format version 1.
+//
+// When the visualization schema changes incompatibly, FormatVersion is increased, and an upgrade of the entries of
+// the previous version is added to upgrades, so that old replay files stay readable and can be converted.
+const (
+	FormatVersion = 2
+
+	headerPrefix = "# otns-replay format "
+	maxLineLen   = 16 * 1024 * 1024
+)
+
+// upgrades[v] upgrades an entry of format version v to version v+1.
+var upgrades = map[int]func(entry *visualize_grpc_pb.ReplayEntry){
+	// version 2 only adds the header
+	1: func(entry *visualize_grpc_pb.ReplayEntry) {},
+}
+
+func formatHeader(version int) string {
+	return fmt.Sprintf("%s%d\n", headerPrefix, version)
+}
+
+// Reader reads the entries of a replay file of any supported format version, upgraded to the current version.
+type Reader struct {
+	scanner *bufio.Scanner
+	version int
+	line    int
+	pending *string // first line of a file without header
+}
+
+// NewReader creates a reader of the replay, reading its header.
+func NewReader(r io.Reader) (*Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLen)
+	rr := &Reader{scanner: scanner, version: 1}
+
+	if !scanner.Scan() {
+		return rr, scanner.Err()
+	}
+	rr.line = 1
+	first := scanner.Text()
+	if !strings.HasPrefix(first, headerPrefix) {
+		rr.pending = &first
+		return rr, nil
+	}
+
+	version, err := strconv.Atoi(strings.TrimSpace(first[len(headerPrefix):]))
+	if err != nil || version < 1 {
+		return nil, errors.Errorf("invalid replay header: %s", first)
+	}
+	if version > FormatVersion {
+		return nil, errors.Errorf("replay format version %d is newer than the supported version %d", version,
+			FormatVersion)
+	}
+	rr.version = version
+	return rr, nil
+}
+
+// Version returns the format version of the replay.
+func (rr *Reader) Version() int {
+	return rr.version
+}
+
+// Next returns the next entry of the replay, or io.EOF at the end of the replay.
+func (rr *Reader) Next() (*visualize_grpc_pb.ReplayEntry, error) {
+	var line string
+	if rr.pending != nil {
+		line, rr.pending = *rr.pending, nil
+	}
+	for strings.TrimSpace(line) == "" {
+		if !rr.scanner.Scan() {
+			if err := rr.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		rr.line++
+		line = rr.scanner.Text()
+	}
+
+	// fields removed in later versions are ignored in old replays
+	unmarshal := prototext.UnmarshalOptions{DiscardUnknown: rr.version < FormatVersion}
+	entry := &visualize_grpc_pb.ReplayEntry{}
+	if err := unmarshal.Unmarshal([]byte(line), entry); err != nil {
+		return nil, errors.Wrapf(err, "line %d", rr.line)
+	}
+	for v := rr.version; v < FormatVersion; v++ {
+		upgrades[v](entry)
+	}
+	return entry, nil
+}
+
+// Convert converts the replay to the current format version, and returns the format version of the original replay
+// and the number of entries converted.
+func Convert(r io.Reader, w io.Writer) (version int, entries int, err error) {
+	rr, err := NewReader(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err = bw.WriteString(formatHeader(FormatVersion)); err != nil {
+		return rr.Version(), 0, err
+	}
+	for {
+		entry, err := rr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return rr.Version(), entries, err
+		}
+
+		data, err := marshalOptions.Marshal(entry)
+		if err != nil {
+			return rr.Version(), entries, err
+		}
+		if _, err = bw.Write(append(data, '\n')); err != nil {
+			return rr.Version(), entries, err
+		}
+		entries++
+	}
+	return rr.Version(), entries, bw.Flush()
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadVersion1(t *testing.T) {
+	// version 1 replays have no header, and fields unknown to the current schema are ignored
+	v1 := "timestamp:10 event:{advance_time:{ts:1000 speed:1}}\n\n" +
+		"timestamp:20 event:{set_title:{title:\"demo\" removed_field:3}}\n"
+	rr, err := NewReader(strings.NewReader(v1))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rr.Version())
+
+	entry, err := rr.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), entry.Timestamp)
+	assert.Equal(t, uint64(1000), entry.Event.GetAdvanceTime().Ts)
+	entry, err = rr.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", entry.Event.GetSetTitle().Title)
+	_, err = rr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestConvert(t *testing.T) {
+	v1 := "timestamp:10 event:{advance_time:{ts:1000}}\ntimestamp:20 event:{heartbeat:{}}\n"
+	var out bytes.Buffer
+	version, entries, err := Convert(strings.NewReader(v1), &out)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, 2, entries)
+	assert.True(t, strings.HasPrefix(out.String(), "# otns-replay format 2\n"))
+
+	rr, err := NewReader(&out)
+	assert.Nil(t, err)
+	assert.Equal(t, FormatVersion, rr.Version())
+	entry, err := rr.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1000), entry.Event.GetAdvanceTime().Ts)
+	entry, err = rr.Next()
+	assert.Nil(t, err)
+	assert.NotNil(t, entry.Event.GetHeartbeat())
+
+	// unknown fields are errors in replays of the current version
+	rr, err = NewReader(strings.NewReader("# otns-replay format 2\ntimestamp:1 removed_field:3\n"))
+	assert.Nil(t, err)
+	_, err = rr.Next()
+	assert.NotNil(t, err)
+
+	_, err = NewReader(strings.NewReader("# otns-replay format 3\n"))
+	assert.NotNil(t, err)
+	_, err = NewReader(strings.NewReader("# otns-replay format x\n"))
+	assert.NotNil(t, err)
+}
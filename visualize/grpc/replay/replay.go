@@ -93,6 +93,8 @@ func NewReplay(filename string) *Replay {
 		beginTime:      time.Now(),
 	}
 
+	_, err = rep.fileWriter.WriteString(formatHeader(FormatVersion))
+	simplelogger.PanicIfError(err)
 	go rep.fileWriterRoutine()
 
 	return rep
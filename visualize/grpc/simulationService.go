@@ -0,0 +1,105 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_grpc
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	. "github.com/openthread/ot-ns/types"
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+)
+
+// simulationService implements the gRPC SimulationService, which controls the simulation with structured data.
+type simulationService struct {
+	gs *grpcServer
+}
+
+const simulationServiceName = "visualize_grpc_pb.SimulationService"
+
+func (ss *simulationService) GetCounters(ctx context.Context, req *pb.Empty) (*pb.Counters, error) {
+	counters, err := ss.gs.vis.simctrl.GetCounters()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Counters{Counters: counters}, nil
+}
+
+func (ss *simulationService) GetRadioParams(ctx context.Context, req *pb.Empty) (*pb.RadioParams, error) {
+	return ss.gs.vis.simctrl.GetRadioParams()
+}
+
+func (ss *simulationService) SetRadioParams(ctx context.Context, req *pb.RadioParams) (*pb.Empty, error) {
+	return &pb.Empty{}, ss.gs.vis.simctrl.SetRadioParams(req)
+}
+
+func (ss *simulationService) StartKpi(ctx context.Context, req *pb.Empty) (*pb.Empty, error) {
+	return &pb.Empty{}, ss.gs.vis.simctrl.StartKpi()
+}
+
+func (ss *simulationService) StopKpi(ctx context.Context, req *pb.Empty) (*pb.Empty, error) {
+	return &pb.Empty{}, ss.gs.vis.simctrl.StopKpi()
+}
+
+func (ss *simulationService) GetKpi(ctx context.Context, req *pb.Empty) (*pb.Kpi, error) {
+	return ss.gs.vis.simctrl.GetKpi()
+}
+
+func (ss *simulationService) SaveKpi(ctx context.Context, req *pb.SaveKpiRequest) (*pb.Empty, error) {
+	return &pb.Empty{}, ss.gs.vis.simctrl.SaveKpi(req.Filename)
+}
+
+func (ss *simulationService) Watch(ctx context.Context, req *pb.WatchRequest) (*pb.Empty, error) {
+	if len(req.NodeIds) == 0 {
+		return nil, errors.Errorf("no nodes specified")
+	}
+
+	nodeids := make([]NodeId, len(req.NodeIds))
+	for i, id := range req.NodeIds {
+		nodeids[i] = NodeId(id)
+	}
+	return &pb.Empty{}, ss.gs.vis.simctrl.Watch(nodeids, !req.Unwatch)
+}
+
+func (ss *simulationService) GetNodeHistory(ctx context.Context, req *pb.NodeHistoryRequest) (*pb.NodeHistory, error) {
+	end := uint64(math.MaxUint64)
+	if req.EndUs != nil {
+		end = *req.EndUs
+	}
+
+	states, err := ss.gs.vis.simctrl.GetNodeHistory(NodeId(req.NodeId), req.StartUs, end)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.NodeHistory{States: states}, nil
+}
+
+func (ss *simulationService) GetNodeGeo(ctx context.Context, req *pb.NodeGeoRequest) (*pb.GeoPosition, error) {
+	return ss.gs.vis.simctrl.GetNodeGeo(NodeId(req.NodeId))
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_grpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	. "github.com/openthread/ot-ns/types"
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+)
+
+type fakeSimulationController struct {
+	radioParams *pb.RadioParams
+	kpiFile     string
+	watched     []NodeId
+	watch       bool
+	history     [3]uint64
+}
+
+func (f *fakeSimulationController) Command(cmd string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeSimulationController) GetCounters() (map[string]uint64, error) {
+	return map[string]uint64{"AlarmEvents": 3, "RadioEvents": 5}, nil
+}
+
+func (f *fakeSimulationController) GetRadioParams() (*pb.RadioParams, error) {
+	return &pb.RadioParams{Model: proto.String("ideal"), TxPowerDbm: proto.Float64(-10)}, nil
+}
+
+func (f *fakeSimulationController) SetRadioParams(params *pb.RadioParams) error {
+	if params.Model != nil {
+		return errors.Errorf("invalid model")
+	}
+	f.radioParams = params
+	return nil
+}
+
+func (f *fakeSimulationController) StartKpi() error {
+	return nil
+}
+
+func (f *fakeSimulationController) StopKpi() error {
+	return nil
+}
+
+func (f *fakeSimulationController) GetKpi() (*pb.Kpi, error) {
+	return &pb.Kpi{StopUs: 10000000, Mac: map[int32]*pb.MacStats{1: {Retries: 2}}}, nil
+}
+
+func (f *fakeSimulationController) SaveKpi(filename string) error {
+	f.kpiFile = filename
+	return nil
+}
+
+func (f *fakeSimulationController) Watch(nodeids []NodeId, watch bool) error {
+	f.watched = nodeids
+	f.watch = watch
+	return nil
+}
+
+func (f *fakeSimulationController) GetNodeHistory(nodeid NodeId, start, end uint64) ([]*pb.NodeState, error) {
+	f.history = [3]uint64{uint64(nodeid), start, end}
+	return []*pb.NodeState{{Role: pb.OtDeviceRole_OT_DEVICE_ROLE_LEADER}}, nil
+}
+
+func (f *fakeSimulationController) GetNodeGeo(nodeid NodeId) (*pb.GeoPosition, error) {
+	if nodeid != 1 {
+		return nil, errors.Errorf("node %d not found", nodeid)
+	}
+	return &pb.GeoPosition{Enabled: true, Lat: 52.5, Lon: 13.4, Alt: 30}, nil
+}
+
+func TestSimulationService(t *testing.T) {
+	ctrl := &fakeSimulationController{}
+	gs := newGrpcServer(&grpcVisualizer{simctrl: ctrl}, "", "secret", false)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = gs.server.Serve(ln)
+	}()
+	defer gs.server.Stop()
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewSimulationServiceClient(conn)
+
+	viewer := context.Background()
+	controller := metadata.AppendToOutgoingContext(context.Background(), ControlTokenMetadataKey, "secret")
+
+	counters, err := client.GetCounters(viewer, &pb.Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]uint64{"AlarmEvents": 3, "RadioEvents": 5}, counters.Counters)
+
+	params, err := client.GetRadioParams(viewer, &pb.Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, "ideal", params.GetModel())
+	assert.Nil(t, params.NoiseFloorDbm)
+
+	// control methods require the token
+	req := &pb.RadioParams{TxPowerDbm: proto.Float64(0), ResetChannelNoiseFloor: []uint32{15}}
+	_, err = client.SetRadioParams(viewer, req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Nil(t, ctrl.radioParams)
+	_, err = client.SetRadioParams(controller, req)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, ctrl.radioParams.GetTxPowerDbm())
+	assert.Nil(t, ctrl.radioParams.MinSnrDb)
+	assert.Equal(t, []uint32{15}, ctrl.radioParams.ResetChannelNoiseFloor)
+
+	// errors of the controller are returned to the client
+	_, err = client.SetRadioParams(controller, &pb.RadioParams{Model: proto.String("none")})
+	assert.NotNil(t, err)
+	assert.Contains(t, status.Convert(err).Message(), "invalid model")
+
+	kpi, err := client.GetKpi(viewer, &pb.Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), kpi.Mac[1].Retries)
+
+	_, err = client.SaveKpi(controller, &pb.SaveKpiRequest{Filename: "kpi.json"})
+	assert.Nil(t, err)
+	assert.Equal(t, "kpi.json", ctrl.kpiFile)
+
+	_, err = client.Watch(controller, &pb.WatchRequest{NodeIds: []int32{1, 3}})
+	assert.Nil(t, err)
+	assert.Equal(t, []NodeId{1, 3}, ctrl.watched)
+	assert.True(t, ctrl.watch)
+	_, err = client.Watch(controller, &pb.WatchRequest{NodeIds: []int32{1}, Unwatch: true})
+	assert.Nil(t, err)
+	assert.False(t, ctrl.watch)
+	_, err = client.Watch(controller, &pb.WatchRequest{})
+	assert.NotNil(t, err)
+
+	history, err := client.GetNodeHistory(viewer, &pb.NodeHistoryRequest{NodeId: 2, StartUs: 1000})
+	assert.Nil(t, err)
+	assert.Equal(t, [3]uint64{2, 1000, math.MaxUint64}, ctrl.history)
+	assert.Equal(t, 1, len(history.States))
+	assert.Equal(t, pb.OtDeviceRole_OT_DEVICE_ROLE_LEADER, history.States[0].Role)
+	_, err = client.GetNodeHistory(viewer, &pb.NodeHistoryRequest{NodeId: 2, EndUs: proto.Uint64(5000)})
+	assert.Nil(t, err)
+	assert.Equal(t, [3]uint64{2, 0, 5000}, ctrl.history)
+
+	pos, err := client.GetNodeGeo(viewer, &pb.NodeGeoRequest{NodeId: 1})
+	assert.Nil(t, err)
+	assert.True(t, pos.Enabled)
+	assert.Equal(t, 52.5, pos.Lat)
+	_, err = client.GetNodeGeo(viewer, &pb.NodeGeoRequest{NodeId: 2})
+	assert.NotNil(t, err)
+}
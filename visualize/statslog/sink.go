@@ -0,0 +1,101 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Formats of the statslog output sinks.
+const (
+	FormatCsv    = "csv"    // canonical CSV file, which can be compared against
+	FormatSqlite = "sqlite" // SQLite database file, written by the sqlite3 command
+	FormatInflux = "influx" // InfluxDB line protocol pushed to an HTTP write URL
+)
+
+// Sink stores the node stats timeline. Entries are written in timestamp order, and never change once written.
+type Sink interface {
+	// Write stores the entry.
+	Write(e *Entry) error
+	// Reset discards the entries written so far.
+	Reset() error
+	// Close flushes the entries and releases the sink.
+	Close() error
+}
+
+// OpenSink opens the sink of the format. The target is the file for the csv and sqlite formats, and the write URL of
+// the influx format, e.g. http://localhost:8086/write?db=otns.
+func OpenSink(format string, target string) (Sink, error) {
+	switch format {
+	case FormatCsv, "":
+		return newCsvSink(target)
+	case FormatSqlite:
+		return newSqliteSink(target)
+	case FormatInflux:
+		return newInfluxSink(target)
+	default:
+		return nil, errors.Errorf("unknown statslog format: %s", format)
+	}
+}
+
+// csvSink writes the timeline to a file in the canonical CSV format.
+type csvSink struct {
+	file *os.File
+}
+
+func newCsvSink(filename string) (*csvSink, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err = writeHeader(f); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &csvSink{file: f}, nil
+}
+
+func (cs *csvSink) Write(e *Entry) error {
+	return e.write(cs.file)
+}
+
+func (cs *csvSink) Reset() error {
+	if err := cs.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := cs.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return writeHeader(cs.file)
+}
+
+func (cs *csvSink) Close() error {
+	return cs.file.Close()
+}
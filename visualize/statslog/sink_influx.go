@@ -0,0 +1,163 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	influxMeasurement = "otns_node_stats"
+	// influxBatchLines is the maximum number of lines pushed in one request.
+	influxBatchLines = 1000
+	// influxPushInterval is the maximum wall-clock time lines are buffered before they are pushed.
+	influxPushInterval = time.Second
+	influxTimeout      = time.Second * 10
+)
+
+// influxSink pushes the timeline to InfluxDB in the line protocol. Points are timestamped with the simulation time,
+// and tagged with the run, which is incremented on Reset, so that the points of different runs do not overwrite each
+// other. Lines are pushed in batches by a background routine, so that the simulation is not slowed down by requests.
+// Points are dropped while the routine is too far behind.
+type influxSink struct {
+	url     string
+	client  *http.Client
+	run     int
+	lines   chan string
+	done    chan struct{}
+	dropped int // points dropped since the last warning
+}
+
+func newInfluxSink(url string) (*influxSink, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, errors.Errorf("invalid InfluxDB write URL: %s", url)
+	}
+
+	is := &influxSink{
+		url:    url,
+		client: &http.Client{Timeout: influxTimeout},
+		lines:  make(chan string, influxBatchLines*10),
+		done:   make(chan struct{}),
+	}
+	go is.pusher()
+	return is, nil
+}
+
+// influxLine returns the entry in the line protocol, with the timestamp in ns.
+func influxLine(e *Entry, run int) string {
+	fields := make([]string, 0, len(columns)-1)
+	for i, f := range e.fields() {
+		fields = append(fields, fmt.Sprintf("%s=%di", columns[i+1], *f))
+	}
+	return fmt.Sprintf("%s,run=%d %s %d", influxMeasurement, run, strings.Join(fields, ","), e.Timestamp*1000)
+}
+
+func (is *influxSink) Write(e *Entry) error {
+	select {
+	case is.lines <- influxLine(e, is.run):
+		is.warnDropped()
+	default:
+		is.dropped++
+	}
+	return nil
+}
+
+// warnDropped warns about the points dropped since the last warning.
+func (is *influxSink) warnDropped() {
+	if is.dropped > 0 {
+		simplelogger.Warnf("push statslog to InfluxDB is too slow, dropped %d points", is.dropped)
+		is.dropped = 0
+	}
+}
+
+func (is *influxSink) Reset() error {
+	is.run++
+	return nil
+}
+
+func (is *influxSink) Close() error {
+	is.warnDropped()
+	close(is.lines)
+	<-is.done
+	return nil
+}
+
+func (is *influxSink) pusher() {
+	defer close(is.done)
+
+	var batch []string
+	ticker := time.NewTicker(influxPushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line, ok := <-is.lines:
+			if !ok {
+				is.push(batch)
+				return
+			}
+			batch = append(batch, line)
+			if len(batch) < influxBatchLines {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		is.push(batch)
+		batch = batch[:0]
+	}
+}
+
+func (is *influxSink) push(batch []string) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body := strings.Join(batch, "\n") + "\n"
+	resp, err := is.client.Post(is.url, "text/plain; charset=utf-8", bytes.NewBufferString(body))
+	if err != nil {
+		simplelogger.Warnf("push statslog to InfluxDB failed: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 256))
+		simplelogger.Warnf("push statslog to InfluxDB failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+		return
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	sqliteTable = "node_stats"
+	// sqliteCommitEntries is the number of entries written per transaction.
+	sqliteCommitEntries = 100
+)
+
+// sqliteSink writes the timeline to a SQLite database. The statements are piped to the sqlite3 command, so that no
+// database driver is needed. Entries are committed in transactions of sqliteCommitEntries entries, and on Close.
+// If sqlite3 fails, the error output of sqlite3 is included in the returned error.
+type sqliteSink struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stderr  bytes.Buffer // only read after sqlite3 exits
+	w       *bufio.Writer
+	pending int
+	err     error // the error of sqlite3, after which nothing is written
+}
+
+func newSqliteSink(filename string) (*sqliteSink, error) {
+	path, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite statslog requires the sqlite3 command")
+	}
+
+	ss := &sqliteSink{cmd: exec.Command(path, "-batch", "-bail", filename)}
+	ss.cmd.Stderr = &ss.stderr
+	if ss.stdin, err = ss.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if err = ss.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	ss.w = bufio.NewWriter(ss.stdin)
+	colDefs := make([]string, len(columns))
+	for i, col := range columns {
+		colDefs[i] = col + " INTEGER NOT NULL"
+	}
+	colDefs[0] += " PRIMARY KEY"
+	ss.exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", sqliteTable, strings.Join(colDefs, ", ")))
+	ss.exec(fmt.Sprintf("DELETE FROM %s;", sqliteTable))
+	ss.exec("BEGIN;")
+	if err = ss.flush(); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (ss *sqliteSink) exec(stmt string) {
+	_, _ = ss.w.WriteString(stmt + "\n")
+}
+
+func (ss *sqliteSink) Write(e *Entry) error {
+	ss.exec(fmt.Sprintf("INSERT OR REPLACE INTO %s VALUES (%s);", sqliteTable, e.String()))
+	ss.pending++
+	if ss.pending < sqliteCommitEntries {
+		return nil
+	}
+	return ss.commit()
+}
+
+func (ss *sqliteSink) commit() error {
+	ss.pending = 0
+	ss.exec("COMMIT;")
+	ss.exec("BEGIN;")
+	return ss.flush()
+}
+
+// flush writes the buffered statements to sqlite3. If that fails, sqlite3 has exited, so flush waits for it.
+func (ss *sqliteSink) flush() error {
+	if ss.err != nil {
+		return ss.err
+	}
+	if err := ss.w.Flush(); err != nil {
+		ss.err = ss.wait(err)
+	}
+	return ss.err
+}
+
+// wait closes the input of sqlite3 and waits for it to exit. It returns err, or else the exit error, with the error
+// output of sqlite3.
+func (ss *sqliteSink) wait(err error) error {
+	if cerr := ss.stdin.Close(); err == nil {
+		err = cerr
+	}
+	if werr := ss.cmd.Wait(); err == nil {
+		err = werr
+	}
+	if msg := strings.TrimSpace(ss.stderr.String()); err != nil && msg != "" {
+		err = errors.Wrapf(err, "sqlite3: %s", msg)
+	}
+	return err
+}
+
+func (ss *sqliteSink) Reset() error {
+	ss.exec(fmt.Sprintf("DELETE FROM %s;", sqliteTable))
+	return ss.commit()
+}
+
+func (ss *sqliteSink) Close() error {
+	if ss.err != nil {
+		return ss.err
+	}
+	ss.exec("COMMIT;")
+	return ss.wait(ss.w.Flush())
+}
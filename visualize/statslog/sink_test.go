@@ -0,0 +1,153 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func recordTimeline(sv *StatslogVisualizer) {
+	sv.AddNode(1, 0, 0, 100)
+	sv.AdvanceTime(1000, 1)
+	sv.SetNodeRole(1, OtDeviceRoleLeader)
+	sv.SetNodePartitionId(1, 0x1234)
+	sv.AdvanceTime(2000, 1)
+}
+
+func TestCsvSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stats.csv")
+	sink, err := OpenSink(FormatCsv, filename)
+	assert.Nil(t, err)
+
+	sv := NewStatslogVisualizer(sink)
+	recordTimeline(sv)
+	sv.Reset()
+	recordTimeline(sv)
+	sv.Stop()
+
+	tl, err := ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, sv.Timeline(), tl)
+
+	_, err = OpenSink("xml", filename)
+	assert.NotNil(t, err)
+}
+
+func TestInfluxSink(t *testing.T) {
+	var lock sync.Mutex
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		lock.Lock()
+		lines = append(lines, strings.Split(strings.TrimSpace(string(body)), "\n")...)
+		lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := OpenSink(FormatInflux, server.URL+"/write?db=otns")
+	assert.Nil(t, err)
+
+	sv := NewStatslogVisualizer(sink)
+	recordTimeline(sv)
+	sv.Reset()
+	recordTimeline(sv)
+	sv.Stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{
+		"otns_node_stats,run=0 nodes=1i,partitions=0i,leaders=0i,routers=0i,children=0i,sleepy=0i,detached=0i,disabled=1i,failed=0i 0",
+		"otns_node_stats,run=0 nodes=1i,partitions=1i,leaders=1i,routers=0i,children=0i,sleepy=0i,detached=0i,disabled=0i,failed=0i 1000000",
+		"otns_node_stats,run=1 nodes=1i,partitions=0i,leaders=0i,routers=0i,children=0i,sleepy=0i,detached=0i,disabled=1i,failed=0i 0",
+		"otns_node_stats,run=1 nodes=1i,partitions=1i,leaders=1i,routers=0i,children=0i,sleepy=0i,detached=0i,disabled=0i,failed=0i 1000000",
+	}, lines)
+
+	_, err = OpenSink(FormatInflux, "localhost:8086")
+	assert.NotNil(t, err)
+}
+
+func TestInfluxSinkDrop(t *testing.T) {
+	// without a pusher routine, the lines are never consumed
+	is := &influxSink{lines: make(chan string, 1)}
+	e := &Entry{}
+	assert.Nil(t, is.Write(e))
+	assert.Nil(t, is.Write(e))
+	assert.Nil(t, is.Write(e))
+	assert.Equal(t, 1, len(is.lines))
+	assert.Equal(t, 2, is.dropped)
+
+	<-is.lines
+	assert.Nil(t, is.Write(e))
+	assert.Equal(t, 0, is.dropped)
+}
+
+func TestSqliteSink(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not found")
+	}
+
+	filename := filepath.Join(t.TempDir(), "stats.db")
+	sink, err := OpenSink(FormatSqlite, filename)
+	assert.Nil(t, err)
+
+	sv := NewStatslogVisualizer(sink)
+	recordTimeline(sv)
+	sv.Reset()
+	recordTimeline(sv)
+	sv.Stop()
+
+	out, err := exec.Command("sqlite3", filename, "SELECT time_us, leaders FROM node_stats ORDER BY time_us;").Output()
+	assert.Nil(t, err)
+	assert.Equal(t, "0|0\n1000|1\n", string(out))
+}
+
+func TestSqliteSinkError(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not found")
+	}
+
+	// sqlite3 fails to open the database, either before or after the sink is opened
+	sink, err := OpenSink(FormatSqlite, filepath.Join(t.TempDir(), "missing", "stats.db"))
+	if err == nil {
+		err = sink.Close()
+	}
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "sqlite3: ")
+	assert.Contains(t, err.Error(), "unable to open database")
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NodeStats is the summary of node states at a point of time.
+type NodeStats struct {
+	Nodes      int `json:"nodes"`
+	Partitions int `json:"partitions"`
+	Leaders    int `json:"leaders"`
+	Routers    int `json:"routers"`
+	Children   int `json:"children"`
+	Sleepy     int `json:"sleepy"`
+	Detached   int `json:"detached"`
+	Disabled   int `json:"disabled"`
+	Failed     int `json:"failed"`
+}
+
+var columns = []string{"time_us", "nodes", "partitions", "leaders", "routers", "children", "sleepy", "detached",
+	"disabled", "failed"}
+
+func (s *NodeStats) fields() []*int {
+	return []*int{&s.Nodes, &s.Partitions, &s.Leaders, &s.Routers, &s.Children, &s.Sleepy, &s.Detached, &s.Disabled,
+		&s.Failed}
+}
+
+// Entry is the node stats since Timestamp (in us) until the timestamp of the next entry.
+type Entry struct {
+	Timestamp uint64
+	NodeStats
+}
+
+func (e *Entry) String() string {
+	items := []string{strconv.FormatUint(e.Timestamp, 10)}
+	for _, f := range e.fields() {
+		items = append(items, strconv.Itoa(*f))
+	}
+	return strings.Join(items, ",")
+}
+
+func (e *Entry) write(w io.Writer) error {
+	_, err := fmt.Fprintln(w, e.String())
+	return err
+}
+
+// Timeline is the list of node stats entries ordered by timestamp.
+// The canonical format has one entry per timestamp and no consecutive entries with equal stats.
+type Timeline []Entry
+
+// At returns the node stats at the specified time.
+func (tl Timeline) At(ts uint64) NodeStats {
+	var stats NodeStats
+	for _, e := range tl {
+		if e.Timestamp > ts {
+			break
+		}
+		stats = e.NodeStats
+	}
+	return stats
+}
+
+// add adds a new entry to the timeline, keeping it canonical.
+func (tl Timeline) add(e Entry) Timeline {
+	if len(tl) > 0 && tl[len(tl)-1].Timestamp == e.Timestamp {
+		tl = tl[:len(tl)-1]
+	}
+	if len(tl) > 0 && tl[len(tl)-1].NodeStats == e.NodeStats {
+		return tl
+	}
+	return append(tl, e)
+}
+
+func writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintln(w, strings.Join(columns, ","))
+	return err
+}
+
+// Write writes the timeline in the canonical CSV format.
+func (tl Timeline) Write(w io.Writer) error {
+	if err := writeHeader(w); err != nil {
+		return err
+	}
+	for i := range tl {
+		if err := tl[i].write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse reads a timeline in CSV format and canonicalizes it.
+func Parse(r io.Reader) (Timeline, error) {
+	var tl Timeline
+	scanner := bufio.NewScanner(r)
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line == strings.Join(columns, ",") {
+			continue
+		}
+
+		items := strings.Split(line, ",")
+		if len(items) != len(columns) {
+			return nil, fmt.Errorf("line %d: expect %d columns, but got %d", lineno, len(columns), len(items))
+		}
+
+		var e Entry
+		var err error
+		if e.Timestamp, err = strconv.ParseUint(items[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineno, err)
+		}
+		for i, f := range e.fields() {
+			if *f, err = strconv.Atoi(items[i+1]); err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineno, err)
+			}
+		}
+		if len(tl) > 0 && e.Timestamp < tl[len(tl)-1].Timestamp {
+			return nil, fmt.Errorf("line %d: timestamp goes backwards", lineno)
+		}
+		tl = tl.add(e)
+	}
+	return tl, scanner.Err()
+}
+
+// ReadFile reads a timeline from a CSV file.
+func ReadFile(filename string) (Timeline, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Tolerance defines how much the compared timelines may differ.
+type Tolerance struct {
+	Time  uint64 // a value may be observed up to Time us earlier or later
+	Count int    // a value may differ by up to Count
+}
+
+// Mismatch is a difference between the golden and current timelines beyond the tolerance.
+type Mismatch struct {
+	Timestamp uint64
+	Field     string
+	Golden    int
+	Current   int
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("time=%.6fs field=%s golden=%d current=%d", float64(m.Timestamp)/1000000, m.Field,
+		m.Golden, m.Current)
+}
+
+// Compare compares the current timeline against the golden one. Each value of either timeline must be matched by a
+// value of the other timeline within the tolerance. Only the time range of the golden timeline is compared.
+func Compare(golden Timeline, current Timeline, tol Tolerance) []Mismatch {
+	if len(golden) == 0 {
+		return nil
+	}
+
+	end := golden[len(golden)-1].Timestamp
+	var timestamps []uint64
+	for _, tl := range []Timeline{golden, current} {
+		for _, e := range tl {
+			if e.Timestamp <= end {
+				timestamps = append(timestamps, e.Timestamp)
+			}
+		}
+	}
+
+	var mismatches []Mismatch
+	reported := map[string]uint64{}
+	for _, ts := range sortUnique(timestamps) {
+		g, c := golden.At(ts), current.At(ts)
+		gf, cf := g.fields(), c.fields()
+		for i := range gf {
+			field := columns[i+1]
+			if matchNear(current, ts, i, *gf[i], tol) && matchNear(golden, ts, i, *cf[i], tol) {
+				continue
+			}
+			if last, ok := reported[field]; ok && last+tol.Time >= ts {
+				continue
+			}
+			reported[field] = ts
+			mismatches = append(mismatches, Mismatch{Timestamp: ts, Field: field, Golden: *gf[i], Current: *cf[i]})
+		}
+	}
+	return mismatches
+}
+
+// matchNear checks if the field of the timeline takes a value within the tolerance around the specified time.
+func matchNear(tl Timeline, ts uint64, field int, val int, tol Tolerance) bool {
+	from := uint64(0)
+	if ts > tol.Time {
+		from = ts - tol.Time
+	}
+
+	stats := tl.At(from)
+	if abs(*stats.fields()[field]-val) <= tol.Count {
+		return true
+	}
+	for _, e := range tl {
+		if e.Timestamp > ts+tol.Time {
+			break
+		}
+		if e.Timestamp > from && abs(*e.fields()[field]-val) <= tol.Count {
+			return true
+		}
+	}
+	return false
+}
+
+func sortUnique(timestamps []uint64) []uint64 {
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	var res []uint64
+	for i, ts := range timestamps {
+		if i == 0 || ts != timestamps[i-1] {
+			res = append(res, ts)
+		}
+	}
+	return res
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
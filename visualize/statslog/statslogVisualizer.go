@@ -0,0 +1,241 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"sync"
+
+	"github.com/simonlingoogle/go-simplelogger"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+type nodeState struct {
+	role        OtDeviceRole
+	mode        NodeMode
+	partitionId uint32
+	failed      bool
+}
+
+// StatslogVisualizer records the timeline of node stats, and optionally writes it to a sink.
+type StatslogVisualizer struct {
+	visualize.Visualizer
+
+	lock     sync.Mutex
+	nodes    map[NodeId]*nodeState
+	curTime  uint64
+	timeline Timeline
+	sink     Sink
+	written  int
+}
+
+// NewStatslogVisualizer creates a new StatslogVisualizer. If sink is not nil, the timeline is written to the sink,
+// which is closed when the visualizer stops.
+func NewStatslogVisualizer(sink Sink) *StatslogVisualizer {
+	return &StatslogVisualizer{
+		Visualizer: visualize.NewNopVisualizer(),
+		nodes:      map[NodeId]*nodeState{},
+		sink:       sink,
+	}
+}
+
+// Timeline returns the node stats timeline recorded so far.
+func (sv *StatslogVisualizer) Timeline() Timeline {
+	sv.lock.Lock()
+	defer sv.lock.Unlock()
+
+	return append(Timeline{}, sv.timeline...)
+}
+
+// Current returns the current node stats.
+func (sv *StatslogVisualizer) Current() NodeStats {
+	sv.lock.Lock()
+	defer sv.lock.Unlock()
+
+	return sv.calcStats()
+}
+
+// Reset discards the recorded timeline and resets the sink.
+func (sv *StatslogVisualizer) Reset() {
+	sv.lock.Lock()
+	defer sv.lock.Unlock()
+
+	sv.nodes = map[NodeId]*nodeState{}
+	sv.curTime = 0
+	sv.timeline = nil
+	sv.written = 0
+
+	if sv.sink != nil {
+		if err := sv.sink.Reset(); err != nil {
+			simplelogger.Errorf("reset statslog failed: %+v", err)
+			sv.closeSink()
+		}
+	}
+}
+
+func (sv *StatslogVisualizer) closeSink() {
+	if err := sv.sink.Close(); err != nil {
+		simplelogger.Errorf("close statslog failed: %+v", err)
+	}
+	sv.sink = nil
+}
+
+func (sv *StatslogVisualizer) Stop() {
+	sv.lock.Lock()
+	defer sv.lock.Unlock()
+
+	if sv.sink != nil {
+		sv.flush(len(sv.timeline))
+		if sv.sink != nil {
+			sv.closeSink()
+		}
+	}
+}
+
+func (sv *StatslogVisualizer) AddNode(nodeid NodeId, x int, y int, radioRange int) {
+	sv.update(func() {
+		sv.nodes[nodeid] = &nodeState{mode: DefaultNodeMode()}
+	})
+}
+
+func (sv *StatslogVisualizer) DeleteNode(id NodeId) {
+	sv.update(func() {
+		delete(sv.nodes, id)
+	})
+}
+
+func (sv *StatslogVisualizer) SetNodeRole(nodeid NodeId, role OtDeviceRole) {
+	sv.updateNode(nodeid, func(node *nodeState) {
+		node.role = role
+	})
+}
+
+func (sv *StatslogVisualizer) SetNodeMode(nodeid NodeId, mode NodeMode) {
+	sv.updateNode(nodeid, func(node *nodeState) {
+		node.mode = mode
+	})
+}
+
+func (sv *StatslogVisualizer) SetNodePartitionId(nodeid NodeId, parid uint32) {
+	sv.updateNode(nodeid, func(node *nodeState) {
+		node.partitionId = parid
+	})
+}
+
+func (sv *StatslogVisualizer) OnNodeFail(nodeid NodeId) {
+	sv.updateNode(nodeid, func(node *nodeState) {
+		node.failed = true
+	})
+}
+
+func (sv *StatslogVisualizer) OnNodeRecover(nodeid NodeId) {
+	sv.updateNode(nodeid, func(node *nodeState) {
+		node.failed = false
+	})
+}
+
+func (sv *StatslogVisualizer) AdvanceTime(ts uint64, speed float64) {
+	sv.lock.Lock()
+	defer sv.lock.Unlock()
+
+	if ts <= sv.curTime {
+		return
+	}
+	sv.curTime = ts
+
+	// entries before the current time can not change anymore
+	sv.flush(len(sv.timeline))
+}
+
+func (sv *StatslogVisualizer) updateNode(nodeid NodeId, f func(node *nodeState)) {
+	sv.update(func() {
+		if node := sv.nodes[nodeid]; node != nil {
+			f(node)
+		}
+	})
+}
+
+func (sv *StatslogVisualizer) update(f func()) {
+	sv.lock.Lock()
+	defer sv.lock.Unlock()
+
+	f()
+	sv.timeline = sv.timeline.add(Entry{Timestamp: sv.curTime, NodeStats: sv.calcStats()})
+	// the last entry may still be replaced at the current time
+	sv.flush(len(sv.timeline) - 1)
+}
+
+func (sv *StatslogVisualizer) flush(n int) {
+	if sv.sink == nil {
+		return
+	}
+
+	for ; sv.written < n; sv.written++ {
+		if err := sv.sink.Write(&sv.timeline[sv.written]); err != nil {
+			simplelogger.Errorf("write statslog failed: %+v", err)
+			sv.closeSink()
+			return
+		}
+	}
+}
+
+func (sv *StatslogVisualizer) calcStats() NodeStats {
+	var stats NodeStats
+	partitions := map[uint32]struct{}{}
+
+	for _, node := range sv.nodes {
+		stats.Nodes++
+		if node.failed {
+			stats.Failed++
+			continue
+		}
+
+		switch node.role {
+		case OtDeviceRoleDisabled:
+			stats.Disabled++
+		case OtDeviceRoleDetached:
+			stats.Detached++
+		case OtDeviceRoleChild:
+			stats.Children++
+		case OtDeviceRoleRouter:
+			stats.Routers++
+		case OtDeviceRoleLeader:
+			stats.Leaders++
+		}
+
+		if node.role >= OtDeviceRoleChild {
+			partitions[node.partitionId] = struct{}{}
+			if !node.mode.RxOnWhenIdle {
+				stats.Sleepy++
+			}
+		}
+	}
+
+	stats.Partitions = len(partitions)
+	return stats
+}
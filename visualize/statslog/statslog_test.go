@@ -0,0 +1,102 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_statslog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+func TestTimeline(t *testing.T) {
+	var tl Timeline
+	tl = tl.add(Entry{Timestamp: 0, NodeStats: NodeStats{Nodes: 1, Disabled: 1}})
+	tl = tl.add(Entry{Timestamp: 0, NodeStats: NodeStats{Nodes: 1, Detached: 1}})
+	tl = tl.add(Entry{Timestamp: 100, NodeStats: NodeStats{Nodes: 1, Detached: 1}})
+	tl = tl.add(Entry{Timestamp: 200, NodeStats: NodeStats{Nodes: 1, Partitions: 1, Leaders: 1}})
+	assert.Equal(t, 2, len(tl))
+	assert.Equal(t, 1, tl.At(199).Detached)
+	assert.Equal(t, 1, tl.At(200).Leaders)
+
+	var buf bytes.Buffer
+	assert.Nil(t, tl.Write(&buf))
+	assert.Equal(t, "time_us,nodes,partitions,leaders,routers,children,sleepy,detached,disabled,failed\n"+
+		"0,1,0,0,0,0,0,1,0,0\n200,1,1,1,0,0,0,0,0,0\n", buf.String())
+
+	parsed, err := Parse(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, tl, parsed)
+
+	_, err = Parse(strings.NewReader("0,1,2\n"))
+	assert.NotNil(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	golden := Timeline{
+		{Timestamp: 0, NodeStats: NodeStats{Nodes: 3, Detached: 3}},
+		{Timestamp: 1000, NodeStats: NodeStats{Nodes: 3, Partitions: 1, Leaders: 1, Detached: 2}},
+		{Timestamp: 5000, NodeStats: NodeStats{Nodes: 3, Partitions: 1, Leaders: 1, Routers: 2}},
+	}
+	assert.Empty(t, Compare(golden, golden, Tolerance{}))
+
+	current := Timeline{
+		{Timestamp: 0, NodeStats: NodeStats{Nodes: 3, Detached: 3}},
+		{Timestamp: 1200, NodeStats: NodeStats{Nodes: 3, Partitions: 1, Leaders: 1, Detached: 2}},
+		{Timestamp: 5000, NodeStats: NodeStats{Nodes: 3, Partitions: 1, Leaders: 1, Routers: 1, Children: 1}},
+	}
+	mismatches := Compare(golden, current, Tolerance{Time: 500})
+	assert.Equal(t, 2, len(mismatches))
+	assert.Equal(t, Mismatch{Timestamp: 5000, Field: "routers", Golden: 2, Current: 1}, mismatches[0])
+	assert.Equal(t, "children", mismatches[1].Field)
+
+	assert.Empty(t, Compare(golden, current, Tolerance{Time: 500, Count: 1}))
+	assert.True(t, len(Compare(golden, current, Tolerance{Time: 100})) > 2)
+}
+
+func TestStatslogVisualizer(t *testing.T) {
+	sv := NewStatslogVisualizer(nil)
+	sv.AddNode(1, 0, 0, 100)
+	sv.AddNode(2, 0, 0, 100)
+	sv.AdvanceTime(1000, 1)
+	sv.SetNodeRole(1, OtDeviceRoleLeader)
+	sv.SetNodePartitionId(1, 0x1234)
+	sv.SetNodeMode(2, NodeMode{})
+	sv.SetNodeRole(2, OtDeviceRoleChild)
+	sv.SetNodePartitionId(2, 0x1234)
+	sv.AdvanceTime(2000, 1)
+	sv.OnNodeFail(2)
+
+	tl := sv.Timeline()
+	assert.Equal(t, 3, len(tl))
+	assert.Equal(t, NodeStats{Nodes: 2, Disabled: 2}, tl[0].NodeStats)
+	assert.Equal(t, Entry{Timestamp: 1000, NodeStats: NodeStats{Nodes: 2, Partitions: 1, Leaders: 1, Children: 1, Sleepy: 1}}, tl[1])
+	assert.Equal(t, Entry{Timestamp: 2000, NodeStats: NodeStats{Nodes: 2, Partitions: 1, Leaders: 1, Failed: 1}}, tl[2])
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_tui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+const (
+	refreshInterval = time.Millisecond * 250
+	gridRows        = 12
+	eventRows       = 5
+	minCliRows      = 5
+	defaultRows     = 24
+	defaultCols     = 80
+)
+
+// partitionColors are the ANSI foreground colors of partitions, in the order the partitions are seen.
+var partitionColors = []int{32, 33, 34, 35, 36, 31}
+
+type nodeState struct {
+	x, y      int
+	role      OtDeviceRole
+	mode      NodeMode
+	partition uint32
+	failed    bool
+}
+
+// TuiVisualizer draws a dashboard of the simulation in the top rows of the terminal: the node grid with the roles and
+// partitions of the nodes, live counters and the recent events. The rows below the dashboard scroll the CLI as usual,
+// so that the simulation can be watched where the web UI can not be used, e.g. over SSH.
+type TuiVisualizer struct {
+	visualize.Visualizer
+
+	lock       sync.Mutex
+	out        io.Writer
+	size       func() (rows int, cols int)
+	nodes      map[NodeId]*nodeState
+	partitions map[uint32]int // color index by partition ID
+	curTime    uint64
+	speed      float64
+	title      string
+	frames     uint64
+	events     []string
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewTuiVisualizer creates a terminal visualizer drawing to out, which is the terminal of the CLI.
+func NewTuiVisualizer(out io.Writer) *TuiVisualizer {
+	return &TuiVisualizer{
+		Visualizer: visualize.NewNopVisualizer(),
+		out:        out,
+		size:       terminalSize,
+		nodes:      map[NodeId]*nodeState{},
+		partitions: map[uint32]int{},
+		speed:      1,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Run draws the dashboard periodically until the visualizer is stopped.
+func (tv *TuiVisualizer) Run() {
+	rows, _ := tv.size()
+	height := tv.height(rows)
+	// keep the CLI in the scroll region below the dashboard
+	_, _ = fmt.Fprintf(tv.out, "\x1b[2J\x1b[%d;%dr\x1b[%d;1H", height+1, rows, rows)
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			tv.draw()
+		case <-tv.stop:
+			_, _ = fmt.Fprintf(tv.out, "\x1b[r\x1b[%d;1H", rows)
+			return
+		}
+	}
+}
+
+func (tv *TuiVisualizer) Stop() {
+	tv.stopOnce.Do(func() {
+		close(tv.stop)
+	})
+}
+
+// height returns the number of rows of the dashboard in a terminal of the rows.
+func (tv *TuiVisualizer) height(rows int) int {
+	height := 3 + gridRows + 1 + eventRows
+	if height > rows-minCliRows {
+		height = rows - minCliRows
+	}
+	if height < 0 {
+		height = 0
+	}
+	return height
+}
+
+func (tv *TuiVisualizer) draw() {
+	rows, cols := tv.size()
+	lines := tv.render(cols)
+	if height := tv.height(rows); len(lines) > height {
+		lines = lines[:height]
+	}
+
+	var sb strings.Builder
+	// save the cursor of the CLI, draw each line from the top and restore the cursor
+	sb.WriteString("\x1b7")
+	for i, line := range lines {
+		fmt.Fprintf(&sb, "\x1b[%d;1H\x1b[2K%s", i+1, line)
+	}
+	sb.WriteString("\x1b8")
+	_, _ = io.WriteString(tv.out, sb.String())
+}
+
+// render returns the lines of the dashboard for a terminal of the columns.
+func (tv *TuiVisualizer) render(cols int) []string {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	roles := map[OtDeviceRole]int{}
+	failed := 0
+	for _, node := range tv.nodes {
+		if node.failed {
+			failed++
+		} else {
+			roles[node.role]++
+		}
+	}
+
+	title := "OTNS"
+	if tv.title != "" {
+		title += " - " + tv.title
+	}
+	lines := []string{
+		clip(fmt.Sprintf("%s  time=%.3fs speed=%gx nodes=%d partitions=%d frames=%d", title,
+			float64(tv.curTime)/1000000, tv.speed, len(tv.nodes), tv.countPartitions(), tv.frames), cols),
+		clip(fmt.Sprintf("leader=%d router=%d child=%d detached=%d disabled=%d failed=%d",
+			roles[OtDeviceRoleLeader], roles[OtDeviceRoleRouter], roles[OtDeviceRoleChild],
+			roles[OtDeviceRoleDetached], roles[OtDeviceRoleDisabled], failed), cols),
+		strings.Repeat("-", cols),
+	}
+	lines = append(lines, tv.renderGrid(cols)...)
+	lines = append(lines, strings.Repeat("-", cols))
+	for i := 0; i < eventRows; i++ {
+		if i < len(tv.events) {
+			lines = append(lines, clip(tv.events[i], cols))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	return lines
+}
+
+// renderGrid returns the rows of the node grid, where each node is drawn as its role letter and ID in the color of
+// its partition, at its position scaled to the grid.
+func (tv *TuiVisualizer) renderGrid(cols int) []string {
+	type cell struct {
+		text  string
+		color int
+	}
+	grid := make([][]*cell, gridRows)
+	for i := range grid {
+		grid[i] = make([]*cell, cols)
+	}
+
+	maxX, maxY := 1, 1
+	for _, node := range tv.nodes {
+		if node.x > maxX {
+			maxX = node.x
+		}
+		if node.y > maxY {
+			maxY = node.y
+		}
+	}
+
+	ids := make([]NodeId, 0, len(tv.nodes))
+	for id := range tv.nodes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		node := tv.nodes[id]
+		text := fmt.Sprintf("%c%d", roleLetter(node), id)
+		row := node.y * (gridRows - 1) / maxY
+		col := node.x * (cols - len(text)) / maxX
+		if row < 0 || col < 0 || row >= gridRows || col+len(text) > cols {
+			continue
+		}
+		color := 37
+		if !node.failed && node.partition != 0 {
+			color = partitionColors[tv.partitions[node.partition]%len(partitionColors)]
+		}
+		grid[row][col] = &cell{text: text, color: color}
+	}
+
+	lines := make([]string, gridRows)
+	for i, row := range grid {
+		var sb strings.Builder
+		for col := 0; col < cols; col++ {
+			if c := row[col]; c != nil {
+				fmt.Fprintf(&sb, "\x1b[%dm%s\x1b[0m", c.color, c.text)
+				// skip the cells covered by the node
+				col += len(c.text) - 1
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		lines[i] = strings.TrimRight(sb.String(), " ")
+	}
+	return lines
+}
+
+func (tv *TuiVisualizer) countPartitions() int {
+	partitions := map[uint32]struct{}{}
+	for _, node := range tv.nodes {
+		if !node.failed && node.partition != 0 {
+			partitions[node.partition] = struct{}{}
+		}
+	}
+	return len(partitions)
+}
+
+func roleLetter(node *nodeState) byte {
+	if node.failed {
+		return 'X'
+	}
+	switch node.role {
+	case OtDeviceRoleLeader:
+		return 'L'
+	case OtDeviceRoleRouter:
+		return 'R'
+	case OtDeviceRoleChild:
+		if !node.mode.RxOnWhenIdle {
+			return 's'
+		}
+		return 'C'
+	case OtDeviceRoleDetached:
+		return 'D'
+	default:
+		return '-'
+	}
+}
+
+func clip(s string, cols int) string {
+	if len(s) > cols {
+		return s[:cols]
+	}
+	return s
+}
+
+// addEvent adds a recent event, keeping the latest events first.
+func (tv *TuiVisualizer) addEvent(format string, args ...interface{}) {
+	evt := fmt.Sprintf("%10.3fs ", float64(tv.curTime)/1000000) + fmt.Sprintf(format, args...)
+	tv.events = append([]string{evt}, tv.events...)
+	if len(tv.events) > eventRows {
+		tv.events = tv.events[:eventRows]
+	}
+}
+
+func (tv *TuiVisualizer) updateNode(nodeid NodeId, f func(node *nodeState)) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	if node := tv.nodes[nodeid]; node != nil {
+		f(node)
+	}
+}
+
+func (tv *TuiVisualizer) AddNode(nodeid NodeId, x int, y int, radioRange int) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	tv.nodes[nodeid] = &nodeState{x: x, y: y, mode: DefaultNodeMode()}
+	tv.addEvent("node %d added", nodeid)
+}
+
+func (tv *TuiVisualizer) DeleteNode(id NodeId) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	delete(tv.nodes, id)
+	tv.addEvent("node %d deleted", id)
+}
+
+func (tv *TuiVisualizer) SetNodePos(nodeid NodeId, x, y int) {
+	tv.updateNode(nodeid, func(node *nodeState) {
+		node.x, node.y = x, y
+	})
+}
+
+func (tv *TuiVisualizer) SetNodeRole(nodeid NodeId, role OtDeviceRole) {
+	tv.updateNode(nodeid, func(node *nodeState) {
+		if node.role != role {
+			tv.addEvent("node %d %s -> %s", nodeid, node.role, role)
+		}
+		node.role = role
+	})
+}
+
+func (tv *TuiVisualizer) SetNodeMode(nodeid NodeId, mode NodeMode) {
+	tv.updateNode(nodeid, func(node *nodeState) {
+		node.mode = mode
+	})
+}
+
+func (tv *TuiVisualizer) SetNodePartitionId(nodeid NodeId, parid uint32) {
+	tv.updateNode(nodeid, func(node *nodeState) {
+		if _, ok := tv.partitions[parid]; !ok && parid != 0 {
+			tv.partitions[parid] = len(tv.partitions)
+			tv.addEvent("node %d formed partition %08x", nodeid, parid)
+		}
+		node.partition = parid
+	})
+}
+
+func (tv *TuiVisualizer) OnNodeFail(nodeid NodeId) {
+	tv.updateNode(nodeid, func(node *nodeState) {
+		node.failed = true
+		tv.addEvent("node %d failed", nodeid)
+	})
+}
+
+func (tv *TuiVisualizer) OnNodeRecover(nodeid NodeId) {
+	tv.updateNode(nodeid, func(node *nodeState) {
+		node.failed = false
+		tv.addEvent("node %d recovered", nodeid)
+	})
+}
+
+func (tv *TuiVisualizer) Send(srcid NodeId, dstid NodeId, mvinfo *visualize.MsgVisualizeInfo) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	tv.frames++
+}
+
+func (tv *TuiVisualizer) SetSpeed(speed float64) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	tv.speed = speed
+}
+
+func (tv *TuiVisualizer) AdvanceTime(ts uint64, speed float64) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	tv.curTime = ts
+	tv.speed = speed
+}
+
+func (tv *TuiVisualizer) SetTitle(titleInfo visualize.TitleInfo) {
+	tv.lock.Lock()
+	defer tv.lock.Unlock()
+
+	tv.title = titleInfo.Title
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package visualize_tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+func TestRender(t *testing.T) {
+	tv := NewTuiVisualizer(&bytes.Buffer{})
+	tv.AddNode(1, 0, 0, 100)
+	tv.AddNode(2, 100, 100, 100)
+	tv.SetNodeRole(1, OtDeviceRoleLeader)
+	tv.SetNodePartitionId(1, 0x1234)
+	tv.SetNodeRole(2, OtDeviceRoleChild)
+	tv.SetNodeMode(2, NodeMode{})
+	tv.SetNodePartitionId(2, 0x1234)
+	tv.Send(1, 2, &visualize.MsgVisualizeInfo{})
+	tv.AdvanceTime(1500000, 2)
+
+	lines := tv.render(40)
+	assert.Equal(t, 3+gridRows+1+eventRows, len(lines))
+	assert.Equal(t, "OTNS  time=1.500s speed=2x nodes=2 parti", lines[0])
+	assert.Equal(t, "leader=1 router=0 child=1 detached=0 dis", lines[1])
+	assert.Equal(t, "\x1b[32mL1\x1b[0m", lines[3])
+	assert.Equal(t, strings.Repeat(" ", 38)+"\x1b[32ms2\x1b[0m", lines[3+gridRows-1])
+	assert.Contains(t, lines[3+gridRows+1], "node 2 disabled -> child")
+
+	tv.OnNodeFail(1)
+	lines = tv.render(40)
+	assert.Equal(t, "\x1b[37mX1\x1b[0m", lines[3])
+	assert.Contains(t, lines[3+gridRows+1], "node 1 failed")
+
+	tv.DeleteNode(2)
+	lines = tv.render(80)
+	assert.Contains(t, lines[0], "nodes=1 partitions=0 frames=1")
+}
+
+func TestDraw(t *testing.T) {
+	var buf bytes.Buffer
+	tv := NewTuiVisualizer(&buf)
+	tv.size = func() (int, int) {
+		return 10, 80
+	}
+	tv.draw()
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "\x1b7\x1b[1;1H\x1b[2KOTNS"))
+	assert.True(t, strings.HasSuffix(out, "\x1b8"))
+	// only the rows above the CLI are drawn
+	assert.Contains(t, out, "\x1b[5;1H")
+	assert.NotContains(t, out, "\x1b[6;1H")
+}
@@ -105,6 +105,9 @@ export default class ActionBar extends VObject {
         this._logOnOffButton = this.addButton("Show Log", "any", "", (e) => {
             this.actionToggleLogWindow()
         });
+        this._radioPanelButton = this.addButton("Radio Model", "any", "radio", (e) => {
+            this.actionToggleRadioPanel()
+        });
     }
 
     setAbilities(abilities) {
@@ -231,6 +234,12 @@ export default class ActionBar extends VObject {
         this.vis.actionBar.refresh()
     }
 
+    actionToggleRadioPanel() {
+        this.vis.radioPanel.toggle();
+        this._radioPanelButton.text = this.vis.radioPanel.isVisible() ? "Hide Radio" : "Radio Model";
+        this.vis.actionBar.refresh()
+    }
+
     addButton(label, context, requiredAbility, callback, onRefresh) {
         let btn = new Button(this, label, callback, onRefresh);
         this.addChild(btn);
@@ -32,6 +32,7 @@ import {FRAME_CONTROL_MASK_FRAME_TYPE, FRAME_TYPE_ACK, MAX_SPEED, PAUSE_SPEED} f
 import Node from "./Node"
 import {AckMessage, BroadcastMessage, UnicastMessage} from "./message";
 import LogWindow, {LOG_WINDOW_WIDTH} from "./LogWindow";
+import RadioPanel from "./RadioPanel";
 
 const {
     OtDeviceRole, CommandRequest
@@ -45,12 +46,18 @@ export function Visualizer() {
 }
 
 export default class PixiVisualizer extends VObject {
-    constructor(app, grpcServiceClient) {
+    constructor(app, grpcServiceClient, simServiceClient, metadata) {
         super();
         vis = this;
 
         this.app = app;
         this.grpcServiceClient = grpcServiceClient;
+        this.metadata = metadata || {};
+        this.simServiceClient = simServiceClient;
+        this._selectedNodeGeo = null;
+        this.radioPanel = new RadioPanel(simServiceClient, (text) => {
+            this.log(text)
+        });
         this.speed = 1;
         this.curTime = 0;
         this.curSpeed = 1;
@@ -234,7 +241,32 @@ export default class PixiVisualizer extends VObject {
             + this.getNodeCountByRole(OtDeviceRole.OT_DEVICE_ROLE_ROUTER) + " routers "
             + this.getNodeCountByRole(OtDeviceRole.OT_DEVICE_ROLE_CHILD) + " EDs "
             + this.getNodeCountByRole(OtDeviceRole.OT_DEVICE_ROLE_DETACHED) + " detached"
-            + " | SPEED=" + Math.round(this.curSpeed * 10) / 10 + " | TIME=" + this.formatTime();
+            + " | SPEED=" + Math.round(this.curSpeed * 10) / 10 + " | TIME=" + this.formatTime()
+            + this.formatSelectedNodeGeo();
+    }
+
+    formatSelectedNodeGeo() {
+        let pos = this._selectedNodeGeo;
+        if (pos === null || pos.lat === undefined) {
+            return ""
+        }
+        return ` | NODE ${this._selectedNodeId} @ ${pos.lat.toFixed(6)},${pos.lon.toFixed(6)} alt ${pos.alt}m`
+    }
+
+    // refreshSelectedNodeGeo queries the geographic position of the selected node, which is shown in the status
+    // message if the geographic mode is enabled.
+    refreshSelectedNodeGeo() {
+        let id = this._selectedNodeId;
+        this._selectedNodeGeo = null;
+        if (!id) {
+            return
+        }
+
+        this.simServiceClient.getNodeGeo(id, (err, pos) => {
+            if (err === null && id === this._selectedNodeId) {
+                this._selectedNodeGeo = pos;
+            }
+        })
     }
 
     getNodeCountByRole(role) {
@@ -385,7 +417,10 @@ export default class PixiVisualizer extends VObject {
 
     visSetNodePos(nodeId, x, y) {
         this.nodes[nodeId].setPosition(x, y);
-        this.logNode(nodeId, `Moved to (${x},${y})`)
+        this.logNode(nodeId, `Moved to (${x},${y})`);
+        if (nodeId === this._selectedNodeId) {
+            this.refreshSelectedNodeGeo()
+        }
     }
 
     visOnExtAddrChange(nodeId, extAddr) {
@@ -485,7 +520,7 @@ export default class PixiVisualizer extends VObject {
         this.log(`> ${cmd}`);
         console.log(`> ${cmd}`);
 
-        this.grpcServiceClient.command(req, {}, (err, resp) => {
+        this.grpcServiceClient.command(req, this.metadata, (err, resp) => {
                 if (err !== null) {
                     this.log("Error: " + err.toLocaleString());
                     console.error("Error: " + err.toLocaleString());
@@ -538,6 +573,7 @@ export default class PixiVisualizer extends VObject {
             new_sel.onSelected()
         }
 
+        this.refreshSelectedNodeGeo();
         this.actionBar.setContext(new_sel || "any")
     }
 
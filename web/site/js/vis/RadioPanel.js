@@ -0,0 +1,130 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+// RADIO_SLIDERS are the radio model parameters tuned with sliders, with the ranges of the sliders.
+const RADIO_SLIDERS = [
+    {name: "PathLossExponent", label: "Path loss exponent", min: 1, max: 6, step: 0.1},
+    {name: "NoiseFloorDbm", label: "Noise floor (dBm)", min: -120, max: -60, step: 1},
+    {name: "TxPowerDbm", label: "TX power (dBm)", min: -20, max: 20, step: 1},
+    {name: "MinSnrDb", label: "Min SNR (dB)", min: -10, max: 20, step: 0.5},
+    {name: "MeterPerUnit", label: "Meters per unit", min: 0.01, max: 1, step: 0.01},
+];
+
+// RadioPanel selects the radio model and tunes its parameters. Changes are applied to the simulation immediately.
+export default class RadioPanel {
+    constructor(simService, log) {
+        this.simService = simService;
+        this.log = log;
+        this._inputs = {};
+
+        this.root = document.createElement("div");
+        this.root.style.cssText = "position: absolute; top: 30px; right: 20px; padding: 8px; display: none; " +
+            "background: rgba(255, 255, 255, 0.9); border: 1px solid #4193F5; font: 12px Verdana;";
+
+        this._model = document.createElement("select");
+        this._model.addEventListener("change", () => {
+            this.apply({Model: this._model.value})
+        });
+        this._addRow("Radio model", this._model, null);
+
+        for (let slider of RADIO_SLIDERS) {
+            let input = document.createElement("input");
+            input.type = "range";
+            input.min = slider.min;
+            input.max = slider.max;
+            input.step = slider.step;
+            let value = document.createElement("span");
+            input.addEventListener("input", () => {
+                value.textContent = input.value
+            });
+            input.addEventListener("change", () => {
+                this.apply({[slider.name]: parseFloat(input.value)})
+            });
+            this._inputs[slider.name] = {input: input, value: value};
+            this._addRow(slider.label, input, value);
+        }
+
+        document.body.appendChild(this.root);
+    }
+
+    _addRow(label, input, value) {
+        let row = document.createElement("div");
+        let text = document.createElement("label");
+        text.textContent = label + " ";
+        row.appendChild(text);
+        row.appendChild(input);
+        if (value !== null) {
+            row.appendChild(value);
+        }
+        this.root.appendChild(row);
+    }
+
+    isVisible() {
+        return this.root.style.display !== "none";
+    }
+
+    toggle() {
+        if (this.isVisible()) {
+            this.root.style.display = "none";
+        } else {
+            this.root.style.display = "block";
+            this.refresh();
+        }
+    }
+
+    refresh() {
+        this.simService.getRadioParams((err, params) => {
+            if (err !== null) {
+                this.log("Error: get radio params: " + err.message);
+                return
+            }
+
+            this._model.innerHTML = "";
+            for (let model of params.Models) {
+                let option = document.createElement("option");
+                option.value = model;
+                option.textContent = model;
+                option.selected = model === params.Model;
+                this._model.appendChild(option);
+            }
+
+            for (let name in this._inputs) {
+                this._inputs[name].input.value = params[name];
+                this._inputs[name].value.textContent = params[name];
+            }
+        })
+    }
+
+    apply(params) {
+        this.log("Radio params: " + JSON.stringify(params));
+        this.simService.setRadioParams(params, (err) => {
+            if (err !== null) {
+                this.log("Error: set radio params: " + err.message);
+            }
+            this.refresh();
+        })
+    }
+}
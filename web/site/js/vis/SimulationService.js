@@ -0,0 +1,80 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+const {Empty, NodeGeoRequest, RadioParams} = require('../proto/visualize_grpc_pb.js');
+const {SimulationServiceClient: GrpcSimulationServiceClient} = require('../proto/visualize_grpc_grpc_web_pb.js');
+
+// RADIO_PARAMS are the radio model parameters of RadioParams, by the names of their getters and setters.
+const RADIO_PARAMS = ["Model", "NoiseFloorDbm", "PathLossExponent", "TxPowerDbm", "MinSnrDb", "MeterPerUnit"];
+
+// SimulationServiceClient calls the SimulationService of OTNS, with the radio model parameters as plain objects.
+export default class SimulationServiceClient {
+    constructor(server, metadata) {
+        this.metadata = metadata || {};
+        this.client = new GrpcSimulationServiceClient(server);
+    }
+
+    // getRadioParams gets the radio model parameters by name, with Models listing the radio models which can be
+    // selected.
+    getRadioParams(callback) {
+        this.client.getRadioParams(new Empty(), this.metadata, (err, resp) => {
+            if (err !== null) {
+                callback(err, null);
+                return
+            }
+
+            let params = {Models: resp.getModelsList()};
+            for (let name of RADIO_PARAMS) {
+                params[name] = resp["get" + name]();
+            }
+            callback(null, params)
+        });
+    }
+
+    // setRadioParams updates the given radio model parameters, e.g. {TxPowerDbm: 0}.
+    setRadioParams(params, callback) {
+        let req = new RadioParams();
+        for (let name in params) {
+            req["set" + name](params[name]);
+        }
+        this.client.setRadioParams(req, this.metadata, (err) => {
+            callback(err)
+        });
+    }
+
+    // getNodeGeo gets the geographic position {lat, lon, alt} of the node, or {} if the geographic mode is disabled.
+    getNodeGeo(nodeId, callback) {
+        let req = new NodeGeoRequest();
+        req.setNodeId(nodeId);
+        this.client.getNodeGeo(req, this.metadata, (err, resp) => {
+            if (err !== null) {
+                callback(err, null);
+                return
+            }
+            callback(null, resp.getEnabled() ? {lat: resp.getLat(), lon: resp.getLon(), alt: resp.getAlt()} : {})
+        });
+    }
+}
@@ -27,6 +27,7 @@
 import PixiVisualizer from "./vis/PixiVisualizer";
 import * as PIXI from 'pixi.js'
 import {SetResources} from "./vis/resources";
+import SimulationServiceClient from "./vis/SimulationService";
 
 const {
     VisualizeRequest, VisualizeEvent, OtDeviceRole, NodeMode,
@@ -73,11 +74,21 @@ window.addEventListener("resize", function () {
     }
 });
 
+// getClientMetadata returns the gRPC metadata identifying this page, with the control token if given in the URL.
+function getClientMetadata() {
+    let metadata = {'otns-client': Math.random().toString(36).substring(2) + Date.now().toString(36)};
+    if (controlToken) {
+        metadata['otns-token'] = controlToken;
+    }
+    return metadata
+}
+
 function loadOk() {
     console.log('connecting to server ' + server);
     grpcServiceClient = new VisualizeGrpcServiceClient(server);
 
-    vis = new PixiVisualizer(app, grpcServiceClient);
+    let metadata = getClientMetadata();
+    vis = new PixiVisualizer(app, grpcServiceClient, new SimulationServiceClient(server, metadata), metadata);
 
     let [w, h] = getDesiredFieldSize();
     vis.onResize(w, h);
@@ -87,7 +98,6 @@ function loadOk() {
 
 
     let visualizeRequest = new VisualizeRequest();
-    let metadata = {'custom-header-1': 'value1'};
     let stream = grpcServiceClient.visualize(visualizeRequest, metadata);
     stream.on('data', function (resp) {
         let e = null;
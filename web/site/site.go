@@ -76,7 +76,8 @@ func Serve(listenAddr string) error {
 		addr := request.URL.Query()["addr"][0]
 		simplelogger.Debugf("visualizing addr=%+v", addr)
 		err := templates.ExecuteTemplate(writer, "visualize.html", map[string]interface{}{
-			"addr": addr,
+			"addr":  addr,
+			"token": request.URL.Query().Get("token"),
 		})
 		if err != nil {
 			writer.WriteHeader(501)
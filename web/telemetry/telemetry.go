@@ -0,0 +1,123 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package web_telemetry
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/simonlingoogle/go-simplelogger"
+	"golang.org/x/net/websocket"
+
+	"github.com/openthread/ot-ns/geo"
+	. "github.com/openthread/ot-ns/types"
+	visualizeStatslog "github.com/openthread/ot-ns/visualize/statslog"
+)
+
+const (
+	// Path is the HTTP path of the telemetry WebSocket endpoint.
+	Path = "/telemetry"
+	// MinInterval is the minimum interval between two telemetry messages.
+	MinInterval = 10 * time.Millisecond
+)
+
+// Partition lists the alive nodes of a Thread partition.
+type Partition struct {
+	Id    uint32   `json:"id"`
+	Nodes []NodeId `json:"nodes"`
+}
+
+// Snapshot is the telemetry message sent to WebSocket clients as JSON.
+type Snapshot struct {
+	Time       uint64                      `json:"time_us"`
+	Speed      float64                     `json:"speed"`
+	Nodes      visualizeStatslog.NodeStats `json:"nodes"`
+	Counters   map[string]uint64           `json:"counters"`
+	Partitions []Partition                 `json:"partitions"`
+	Positions  map[NodeId]geo.Position     `json:"positions,omitempty"` // geographic node positions in geographic mode
+}
+
+// Source collects a telemetry snapshot. It returns nil if the simulation is no longer available.
+type Source func() *Snapshot
+
+// Serve serves the telemetry WebSocket endpoint on listenAddr until ctx is done. Each connected client receives a
+// snapshot every interval, which can be overridden per client using the `interval` query parameter
+// (e.g. `?interval=200ms`).
+func Serve(ctx context.Context, listenAddr string, interval time.Duration, source Source) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler(interval, source))
+	server := &http.Server{Addr: listenAddr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	simplelogger.Infof("OTNS telemetry serving on ws://%s%s ...", listenAddr, Path)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Handler returns the WebSocket handler emitting telemetry snapshots every interval.
+func Handler(interval time.Duration, source Source) http.Handler {
+	return websocket.Handler(func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		connInterval := interval
+		if s := conn.Request().URL.Query().Get("interval"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				simplelogger.Warnf("telemetry: invalid interval %#v: %v", s, err)
+				return
+			}
+			connInterval = d
+		}
+		if connInterval < MinInterval {
+			connInterval = MinInterval
+		}
+
+		ticker := time.NewTicker(connInterval)
+		defer ticker.Stop()
+
+		for {
+			snapshot := source()
+			if snapshot == nil {
+				return
+			}
+
+			if err := websocket.JSON.Send(conn, snapshot); err != nil {
+				simplelogger.Debugf("telemetry client %s closed: %v", conn.Request().RemoteAddr, err)
+				return
+			}
+
+			<-ticker.C
+		}
+	})
+}
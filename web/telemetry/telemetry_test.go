@@ -0,0 +1,72 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package web_telemetry
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestHandler(t *testing.T) {
+	var calls int
+	source := func() *Snapshot {
+		calls++
+		if calls > 3 {
+			return nil
+		}
+		return &Snapshot{
+			Time:       uint64(calls) * 1000,
+			Speed:      1,
+			Counters:   map[string]uint64{"DispatchByExtAddrSucc": uint64(calls)},
+			Partitions: []Partition{{Id: 0x1234, Nodes: []int{1, 2}}},
+		}
+	}
+
+	server := httptest.NewServer(Handler(time.Hour, source))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + Path + "?interval=10ms"
+	conn, err := websocket.Dial(url, "", server.URL)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	for i := 1; i <= 3; i++ {
+		var snapshot Snapshot
+		assert.Nil(t, websocket.JSON.Receive(conn, &snapshot))
+		assert.Equal(t, uint64(i)*1000, snapshot.Time)
+		assert.Equal(t, uint64(i), snapshot.Counters["DispatchByExtAddrSucc"])
+		assert.Equal(t, []Partition{{Id: 0x1234, Nodes: []int{1, 2}}}, snapshot.Partitions)
+	}
+
+	var snapshot Snapshot
+	assert.NotNil(t, websocket.JSON.Receive(conn, &snapshot))
+}
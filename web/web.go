@@ -28,6 +28,7 @@ package web
 
 import (
 	"fmt"
+	"net/url"
 	"os/exec"
 	"runtime"
 
@@ -44,15 +45,21 @@ var (
 		serverHttpDebugPort int
 		grpcServicePort     int
 		webSitePort         int
+		controlToken        string
 	}
 )
 
-func ConfigWeb(serverBindAddress string, serverHttpDebugPort int, grpcServicePort int, webSitePort int) {
+// ConfigWeb configures the web visualization. If controlToken is not empty, the web browser opened by OpenWeb passes
+// it to OTNS-Web, so that it can control the simulation.
+func ConfigWeb(serverBindAddress string, serverHttpDebugPort int, grpcServicePort int, webSitePort int,
+	controlToken string) {
 	grpcWebProxyParams.serverBindAddress = serverBindAddress
 	grpcWebProxyParams.serverHttpDebugPort = serverHttpDebugPort
 	grpcWebProxyParams.grpcServicePort = grpcServicePort
 	grpcWebProxyParams.webSitePort = webSitePort
-	simplelogger.Debugf("ConfigWeb: %+v", grpcWebProxyParams)
+	grpcWebProxyParams.controlToken = controlToken
+	simplelogger.Debugf("ConfigWeb: bind=%s debug=%d grpc=%d web=%d", serverBindAddress, serverHttpDebugPort,
+		grpcServicePort, webSitePort)
 }
 
 func OpenWeb(ctx *progctx.ProgCtx) error {
@@ -62,7 +69,12 @@ func OpenWeb(ctx *progctx.ProgCtx) error {
 		return err
 	}
 
-	return openWebBrowser(fmt.Sprintf("http://localhost:%d/visualize?addr=localhost:%d", grpcWebProxyParams.webSitePort, grpcWebProxyParams.serverHttpDebugPort))
+	webUrl := fmt.Sprintf("http://localhost:%d/visualize?addr=localhost:%d", grpcWebProxyParams.webSitePort,
+		grpcWebProxyParams.serverHttpDebugPort)
+	if grpcWebProxyParams.controlToken != "" {
+		webUrl += "&token=" + url.QueryEscape(grpcWebProxyParams.controlToken)
+	}
+	return openWebBrowser(webUrl)
 }
 
 // open opens the specified URL in the default browser of the user.